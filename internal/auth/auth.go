@@ -1,12 +1,14 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 	"taskflow/internal/common"
 	"taskflow/internal/repository/gorm/gorm_user"
 	"taskflow/pkg/jwt"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -23,10 +25,71 @@ func NewUserAuth(secret string, userRepo gorm_user.UserRepositoryInterface) *Use
 
 var _ UserAuthInterface = (*UserAuth)(nil)
 
+// Errors returned by Authenticate.
+var (
+	ErrInvalidToken  = errors.New("invalid or expired token")
+	ErrInvalidUserID = errors.New("invalid user ID in token")
+	ErrInvalidClaims = errors.New("invalid token claims")
+	ErrUserNotFound  = errors.New("user account not found")
+)
+
+// Authenticate validates a JWT and returns the ID of the user it was
+// issued to, checking that the account still exists.
+func (ua *UserAuth) Authenticate(tokenString string) (int, error) {
+	claims, err := jwt.ValidateToken(tokenString, ua.secretKey)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+
+	// Handle numeric or string user_id from claims
+	claimsMap := *claims
+	var userID int
+	switch v := claimsMap["user_id"].(type) {
+	case float64:
+		userID = int(v)
+	case int:
+		userID = v
+	case string:
+		var err error
+		userID, err = strconv.Atoi(v)
+		if err != nil {
+			return 0, ErrInvalidUserID
+		}
+	default:
+		return 0, ErrInvalidClaims
+	}
+
+	if ua.userRepo != nil {
+		_, err := ua.userRepo.GetByID(userID)
+		if err == gorm.ErrRecordNotFound {
+			return 0, ErrUserNotFound
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return userID, nil
+}
+
+// ExpiresAt returns the exp claim of a valid token, so long-lived
+// connections can end when the token does.
+func (ua *UserAuth) ExpiresAt(tokenString string) (time.Time, error) {
+	claims, err := jwt.ValidateToken(tokenString, ua.secretKey)
+	if err != nil {
+		return time.Time{}, ErrInvalidToken
+	}
+	exp, err := claims.GetExpirationTime()
+	if err != nil {
+		return time.Time{}, ErrInvalidClaims
+	}
+	if exp == nil {
+		return time.Time{}, nil
+	}
+	return exp.Time, nil
+}
+
 func (ua *UserAuth) AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		secretKey := ua.secretKey
-
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, common.ErrorResponse{
@@ -45,60 +108,23 @@ func (ua *UserAuth) AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-
-		claims, err := jwt.ValidateToken(tokenString, secretKey)
+		userID, err := ua.Authenticate(parts[1])
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, common.ErrorResponse{
-				Message: "invalid or expired token",
-			})
-			c.Abort()
-			return
-		}
-
-		// Handle numeric or string user_id from claims
-		claimsMap := *claims
-		var userID int
-		switch v := claimsMap["user_id"].(type) {
-		case float64:
-			userID = int(v)
-		case int:
-			userID = v
-		case string:
-			var err error
-			userID, err = strconv.Atoi(v)
-			if err != nil {
+			switch {
+			case errors.Is(err, ErrInvalidToken), errors.Is(err, ErrInvalidUserID),
+				errors.Is(err, ErrInvalidClaims), errors.Is(err, ErrUserNotFound):
 				c.JSON(http.StatusUnauthorized, common.ErrorResponse{
-					Message: "invalid user ID in token",
+					Message: err.Error(),
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, common.ErrorResponse{
+					Message: "authentication failed",
 				})
-				c.Abort()
-				return
 			}
-		default:
-			c.JSON(http.StatusUnauthorized, common.ErrorResponse{
-				Message: "invalid token claims",
-			})
 			c.Abort()
 			return
 		}
 
-		if ua.userRepo != nil {
-			_, err := ua.userRepo.GetByID(userID)
-			if err != nil {
-				if err == gorm.ErrRecordNotFound {
-					c.JSON(http.StatusUnauthorized, common.ErrorResponse{
-						Message: "user account not found",
-					})
-				} else {
-					c.JSON(http.StatusInternalServerError, common.ErrorResponse{
-						Message: "authentication failed",
-					})
-				}
-				c.Abort()
-				return
-			}
-		}
-
 		c.Set("userID", userID)
 		c.Next()
 	}
@@ -1,8 +1,16 @@
 package auth
 
-import "github.com/gin-gonic/gin"
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
 
 type UserAuthInterface interface {
+	Authenticate(tokenString string) (int, error)
+	// ExpiresAt returns when a token expires, or the zero time when it
+	// doesn't.
+	ExpiresAt(tokenString string) (time.Time, error)
 	AuthMiddleware() gin.HandlerFunc
 	OptionalAuthMiddleware() gin.HandlerFunc
 }
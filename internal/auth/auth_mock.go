@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 )
@@ -11,6 +13,16 @@ type MockUserAuth struct {
 
 var _ UserAuthInterface = (*MockUserAuth)(nil)
 
+func (m *MockUserAuth) Authenticate(tokenString string) (int, error) {
+	args := m.Called(tokenString)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserAuth) ExpiresAt(tokenString string) (time.Time, error) {
+	args := m.Called(tokenString)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
 func (m *MockUserAuth) AuthMiddleware() gin.HandlerFunc {
 	args := m.Called()
 	return args.Get(0).(gin.HandlerFunc)
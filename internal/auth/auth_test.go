@@ -10,6 +10,7 @@ import (
 	"taskflow/internal/repository/gorm/gorm_user"
 	"taskflow/pkg/jwt"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -286,3 +287,70 @@ func TestSecurityScenario_DeletedUserCannotAccess(t *testing.T) {
 
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserAuth_Authenticate(t *testing.T) {
+	secretKey := []byte("test-secret")
+	validToken, err := jwt.CreateToken(1, "test@example.com", secretKey)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		token     string
+		setupMock func(*gorm_user.MockUserRepository)
+		wantID    int
+		wantErr   error
+	}{
+		{
+			name:  "success",
+			token: validToken,
+			setupMock: func(mockRepo *gorm_user.MockUserRepository) {
+				mockRepo.On("GetByID", 1).Return(&user.User{ID: 1}, nil)
+			},
+			wantID: 1,
+		},
+		{
+			name:      "failure - invalid token",
+			token:     "invalid.token.here",
+			setupMock: func(mockRepo *gorm_user.MockUserRepository) {},
+			wantErr:   ErrInvalidToken,
+		},
+		{
+			name:  "failure - user deleted",
+			token: validToken,
+			setupMock: func(mockRepo *gorm_user.MockUserRepository) {
+				mockRepo.On("GetByID", 1).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(gorm_user.MockUserRepository)
+			tt.setupMock(mockRepo)
+
+			id, err := NewUserAuth("test-secret", mockRepo).Authenticate(tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantID, id)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestUserAuth_ExpiresAt(t *testing.T) {
+	secretKey := []byte("test-secret")
+	ua := NewUserAuth(string(secretKey), nil)
+
+	token, err := jwt.CreateToken(1, "test@example.com", secretKey)
+	assert.NoError(t, err)
+	exp, err := ua.ExpiresAt(token)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(jwt.TokenExpiration), exp, time.Minute)
+
+	_, err = ua.ExpiresAt("not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,422 @@
+package collab
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	project_service "taskflow/internal/service/project"
+	task_service "taskflow/internal/service/task"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// fakeConn is a Conn fed by the test. Receive returns errors sent on in
+// as they are.
+type fakeConn struct {
+	in     chan any
+	out    chan Outbound
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{in: make(chan any, 10), out: make(chan Outbound, 100), closed: make(chan struct{})}
+}
+
+func (c *fakeConn) Receive(v any) error {
+	select {
+	case m := <-c.in:
+		if err, ok := m.(error); ok {
+			return err
+		}
+		*v.(*Inbound) = m.(Inbound)
+		return nil
+	case <-c.closed:
+		return io.EOF
+	}
+}
+
+func (c *fakeConn) Send(v any) error {
+	select {
+	case c.out <- v.(Outbound):
+		return nil
+	case <-c.closed:
+		return io.EOF
+	}
+}
+
+func (c *fakeConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *fakeConn) SetWriteDeadline(t time.Time) error { return nil }
+
+func (c *fakeConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+// next returns the next message the server sent, skipping pings.
+func (c *fakeConn) next(t *testing.T) Outbound {
+	t.Helper()
+	for {
+		select {
+		case m := <-c.out:
+			if m.Type == TypePing {
+				continue
+			}
+			return m
+		case <-time.After(time.Second):
+			t.Fatal("no message from the server")
+			return Outbound{}
+		}
+	}
+}
+
+func testConfig() Config {
+	return Config{PingInterval: time.Hour, PongWait: time.Hour, RateLimit: 1000, RateBurst: 1000}
+}
+
+type fixture struct {
+	hub      *Hub
+	server   *Server
+	userAuth *auth.MockUserAuth
+	tasks    *task_service.TaskServiceMock
+	projects *project_service.ProjectServiceMock
+}
+
+func newFixture(cfg Config) *fixture {
+	f := &fixture{
+		hub:      NewHub(),
+		userAuth: new(auth.MockUserAuth),
+		tasks:    new(task_service.TaskServiceMock),
+		projects: new(project_service.ProjectServiceMock),
+	}
+	f.server = NewServer(f.hub, f.userAuth, f.tasks, f.projects, cfg)
+	return f
+}
+
+// connect starts a session for userID and waits until it is ready.
+func (f *fixture) connect(t *testing.T, userID int) *fakeConn {
+	t.Helper()
+	c := newFakeConn()
+	go f.server.Serve(c, userID, time.Time{})
+	require.Equal(t, TypeReady, c.next(t).Type)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestServer_AuthMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    Inbound
+		authErr  error
+		expected Outbound
+	}{
+		{
+			name:     "success",
+			first:    Inbound{Type: TypeAuth, Token: "tok"},
+			expected: Outbound{Type: TypeReady, Data: map[string]int{"user_id": 1}},
+		},
+		{
+			name:     "failure - invalid token",
+			first:    Inbound{Type: TypeAuth, Token: "tok"},
+			authErr:  auth.ErrInvalidToken,
+			expected: errorMessage("", CodeUnauthorized, "invalid or expired token"),
+		},
+		{
+			name:     "failure - lookup error",
+			first:    Inbound{Type: TypeAuth, Token: "tok"},
+			authErr:  errors.New("db down"),
+			expected: errorMessage("", CodeUnauthorized, "authentication failed"),
+		},
+		{
+			name:     "failure - not an auth message",
+			first:    Inbound{Type: TypePing},
+			expected: errorMessage("", CodeUnauthorized, "authenticate first"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(testConfig())
+			f.userAuth.On("Authenticate", "tok").Return(1, tt.authErr).Maybe()
+			f.userAuth.On("ExpiresAt", "tok").Return(time.Time{}, nil).Maybe()
+
+			c := newFakeConn()
+			done := make(chan struct{})
+			go func() {
+				f.server.Serve(c, 0, time.Time{})
+				close(done)
+			}()
+			c.in <- tt.first
+
+			assert.Equal(t, tt.expected, c.next(t))
+			if tt.expected.Type == TypeError {
+				<-done
+			}
+			c.Close()
+		})
+	}
+}
+
+func TestServer_SubscribePresenceAndEvents(t *testing.T) {
+	f := newFixture(testConfig())
+	project := 7
+	f.tasks.On("GetTask", 1, 4).Return(dto.GetTaskResponse{ID: 4}, nil)
+	f.projects.On("GetProject", 2, 7).Return(dto.ProjectResponse{ID: 7}, nil)
+
+	a := f.connect(t, 1)
+	a.in <- Inbound{Type: TypeSubscribe, Ref: "1", Topic: "task:4"}
+	assert.Equal(t, Outbound{Type: TypeAck, Ref: "1", Topic: "task:4"}, a.next(t))
+	assert.Equal(t, Outbound{Type: TypePresence, Topic: "task:4", Users: []Presence{{UserID: 1, Sessions: 1}}}, a.next(t))
+
+	b := f.connect(t, 1)
+	b.in <- Inbound{Type: TypeSubscribe, Ref: "1", Topic: "task:4"}
+	assert.Equal(t, TypeAck, b.next(t).Type)
+	both := Outbound{Type: TypePresence, Topic: "task:4", Users: []Presence{{UserID: 1, Sessions: 2}}}
+	assert.Equal(t, both, a.next(t))
+	assert.Equal(t, both, b.next(t))
+
+	// Someone who can see project 7, but not the task that moves into it.
+	other := f.connect(t, 2)
+	other.in <- Inbound{Type: TypeSubscribe, Ref: "1", Topic: "project:7"}
+	assert.Equal(t, TypeAck, other.next(t).Type)
+	assert.Equal(t, TypePresence, other.next(t).Type)
+
+	task := dto.GetTaskResponse{ID: 4, Status: "completed", ProjectID: &project}
+	r, err := events.NewRecord(events.StatusChanged{UserID: 1, Task: task, From: "pending"}, time.Now())
+	require.NoError(t, err)
+	e := r.Envelope()
+	f.hub.HandleEvent(&e)
+
+	want := Outbound{
+		Type:    TypeEvent,
+		Topic:   "task:4",
+		Event:   events.TypeStatusChanged,
+		EventID: e.ID,
+		Data:    dto.TaskEventData{Task: task, PreviousStatus: "pending"},
+	}
+	assert.Equal(t, want, a.next(t))
+	assert.Equal(t, want, b.next(t))
+	assert.Empty(t, other.out)
+
+	b.in <- Inbound{Type: TypeUnsubscribe, Ref: "2", Topic: "task:4"}
+	assert.Equal(t, Outbound{Type: TypeAck, Ref: "2", Topic: "task:4"}, b.next(t))
+	assert.Equal(t, Outbound{Type: TypePresence, Topic: "task:4", Users: []Presence{{UserID: 1, Sessions: 1}}}, a.next(t))
+}
+
+func TestServer_SubscribeErrors(t *testing.T) {
+	f := newFixture(testConfig())
+	f.tasks.On("GetTask", 1, 9).Return(dto.GetTaskResponse{}, gorm.ErrRecordNotFound)
+	c := f.connect(t, 1)
+
+	for topic, expected := range map[string]Outbound{
+		"task:9":    errorMessage("x", CodeNotFound, "not found"),
+		"task:nope": errorMessage("x", CodeBadRequest, errInvalidTopic.Error()),
+		"board:1":   errorMessage("x", CodeBadRequest, errInvalidTopic.Error()),
+	} {
+		c.in <- Inbound{Type: TypeSubscribe, Ref: "x", Topic: topic}
+		assert.Equal(t, expected, c.next(t), topic)
+	}
+}
+
+func TestServer_Commands(t *testing.T) {
+	task := dto.GetTaskResponse{ID: 4, Status: "completed"}
+
+	tests := []struct {
+		name      string
+		command   string
+		data      string
+		setupMock func(m *task_service.TaskServiceMock)
+		expected  Outbound
+	}{
+		{
+			name:    "update status",
+			command: CommandUpdateStatus,
+			data:    `{"id":4,"status":"completed"}`,
+			setupMock: func(m *task_service.TaskServiceMock) {
+				m.On("UpdateStatus", 1, 4, "completed").Return(task, nil)
+			},
+			expected: Outbound{Type: TypeAck, Ref: "r", Data: task},
+		},
+		{
+			name:    "move",
+			command: CommandMoveTask,
+			data:    `{"id":4,"after_id":3,"status":"completed"}`,
+			setupMock: func(m *task_service.TaskServiceMock) {
+				m.On("Move", 1, 4, &dto.MoveTaskRequest{AfterID: 3, Status: "completed"}).Return(task, nil)
+			},
+			expected: Outbound{Type: TypeAck, Ref: "r", Data: task},
+		},
+		{
+			name:    "create",
+			command: CommandCreateTask,
+			data:    `{"task":"Write report"}`,
+			setupMock: func(m *task_service.TaskServiceMock) {
+				m.On("CreateTask", 1, &dto.CreateTaskRequest{Task: "Write report"}).Return(task, nil)
+			},
+			expected: Outbound{Type: TypeAck, Ref: "r", Data: task},
+		},
+		{
+			name:    "delete",
+			command: CommandDeleteTask,
+			data:    `{"id":4}`,
+			setupMock: func(m *task_service.TaskServiceMock) {
+				m.On("Delete", 1, 4).Return(nil)
+			},
+			expected: Outbound{Type: TypeAck, Ref: "r"},
+		},
+		{
+			name:    "restore",
+			command: CommandRestoreTask,
+			data:    `{"id":4}`,
+			setupMock: func(m *task_service.TaskServiceMock) {
+				m.On("Restore", 1, 4).Return(task, nil)
+			},
+			expected: Outbound{Type: TypeAck, Ref: "r", Data: task},
+		},
+		{
+			name:    "failure - not found",
+			command: CommandDeleteTask,
+			data:    `{"id":4}`,
+			setupMock: func(m *task_service.TaskServiceMock) {
+				m.On("Delete", 1, 4).Return(gorm.ErrRecordNotFound)
+			},
+			expected: errorMessage("r", CodeNotFound, "not found"),
+		},
+		{
+			name:    "failure - WIP limit",
+			command: CommandUpdateStatus,
+			data:    `{"id":4,"status":"in-progress"}`,
+			setupMock: func(m *task_service.TaskServiceMock) {
+				m.On("UpdateStatus", 1, 4, "in-progress").Return(dto.GetTaskResponse{}, task_service.ErrWIPLimitReached)
+			},
+			expected: errorMessage("r", CodeConflict, task_service.ErrWIPLimitReached.Error()),
+		},
+		{
+			name:      "failure - invalid data",
+			command:   CommandUpdateStatus,
+			data:      `{"id":4,"status":"done"}`,
+			setupMock: func(m *task_service.TaskServiceMock) {},
+			expected:  Outbound{Type: TypeError, Ref: "r", Code: CodeBadRequest},
+		},
+		{
+			name:      "failure - unknown command",
+			command:   "task.explode",
+			setupMock: func(m *task_service.TaskServiceMock) {},
+			expected:  errorMessage("r", CodeBadRequest, errUnknownCommand.Error()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(testConfig())
+			tt.setupMock(f.tasks)
+			c := f.connect(t, 1)
+
+			c.in <- Inbound{Type: TypeCommand, Ref: "r", Command: tt.command, Data: json.RawMessage(tt.data)}
+			got := c.next(t)
+			if tt.expected.Code == CodeBadRequest && tt.expected.Error == "" {
+				assert.Contains(t, got.Error, errInvalidData.Error())
+				got.Error = ""
+			}
+			assert.Equal(t, tt.expected, got)
+			f.tasks.AssertExpectations(t)
+		})
+	}
+}
+
+func TestServer_PingPongAndMalformed(t *testing.T) {
+	cfg := testConfig()
+	cfg.PingInterval = 10 * time.Millisecond
+	f := newFixture(cfg)
+	c := newFakeConn()
+	go f.server.Serve(c, 1, time.Time{})
+	defer c.Close()
+
+	types := map[string]bool{}
+	for len(types) < 2 {
+		select {
+		case m := <-c.out:
+			types[m.Type] = true
+		case <-time.After(time.Second):
+			t.Fatal("no ping from the server")
+		}
+	}
+	assert.Equal(t, map[string]bool{TypeReady: true, TypePing: true}, types)
+
+	c.in <- Inbound{Type: TypePing, Ref: "p"}
+	assert.Equal(t, Outbound{Type: TypePong, Ref: "p"}, c.next(t))
+
+	c.in <- ErrMalformed
+	assert.Equal(t, errorMessage("", CodeBadRequest, ErrMalformed.Error()), c.next(t))
+}
+
+func TestServer_TokenExpiry(t *testing.T) {
+	f := newFixture(testConfig())
+	c := newFakeConn()
+	go f.server.Serve(c, 1, time.Now().Add(20*time.Millisecond))
+	defer c.Close()
+
+	require.Equal(t, TypeReady, c.next(t).Type)
+	assert.Equal(t, errorMessage("", CodeUnauthorized, "token expired"), c.next(t))
+	<-c.closed
+}
+
+func TestHub_UserDeletedDisconnects(t *testing.T) {
+	f := newFixture(testConfig())
+	deleted := f.connect(t, 1)
+	other := f.connect(t, 2)
+
+	r, err := events.NewRecord(events.UserDeleted{UserID: 1}, time.Now())
+	require.NoError(t, err)
+	e := r.Envelope()
+	f.hub.HandleEvent(&e)
+
+	assert.Equal(t, errorMessage("", CodeUnauthorized, "account deleted"), deleted.next(t))
+	<-deleted.closed
+
+	other.in <- Inbound{Type: TypePing, Ref: "1"}
+	assert.Equal(t, Outbound{Type: TypePong, Ref: "1"}, other.next(t))
+}
+
+func TestServer_RateLimit(t *testing.T) {
+	cfg := testConfig()
+	cfg.RateLimit = 0.001
+	cfg.RateBurst = 2
+	f := newFixture(cfg)
+	c := f.connect(t, 1)
+
+	for _, ref := range []string{"1", "2", "3"} {
+		c.in <- Inbound{Type: TypePing, Ref: ref}
+	}
+	assert.Equal(t, Outbound{Type: TypePong, Ref: "1"}, c.next(t))
+	assert.Equal(t, Outbound{Type: TypePong, Ref: "2"}, c.next(t))
+	assert.Equal(t, errorMessage("3", CodeRateLimited, "too many messages"), c.next(t))
+}
+
+func TestSession_SlowClientIsDisconnected(t *testing.T) {
+	c := newFakeConn()
+	s := &session{userID: 1, conn: c, out: make(chan Outbound, sendBuffer), done: make(chan struct{})}
+
+	for range sendBuffer {
+		s.send(Outbound{Type: TypePing})
+	}
+	select {
+	case <-s.done:
+		t.Fatal("closed before the buffer was full")
+	default:
+	}
+
+	s.send(Outbound{Type: TypePing})
+	<-s.done
+	<-c.closed
+	s.send(Outbound{Type: TypePing}) // does not block once closed
+}
@@ -0,0 +1,144 @@
+package collab
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+)
+
+// Hub tracks the open sessions and which of them are subscribed to which
+// topics. Topics are "task:<id>" and "project:<id>".
+type Hub struct {
+	mu       sync.Mutex
+	sessions map[*session]struct{}
+	topics   map[string]map[*session]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{sessions: map[*session]struct{}{}, topics: map[string]map[*session]struct{}{}}
+}
+
+// register adds s to the open sessions.
+func (h *Hub) register(s *session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.sessions[s] = struct{}{}
+}
+
+// unregister removes s from the open sessions and unsubscribes it from
+// every topic.
+func (h *Hub) unregister(s *session) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.sessions, s)
+	for topic := range h.topics {
+		h.remove(s, topic)
+	}
+}
+
+func taskTopic(id int) string    { return fmt.Sprintf("task:%d", id) }
+func projectTopic(id int) string { return fmt.Sprintf("project:%d", id) }
+
+// join subscribes s to topic and tells everyone on it who is there.
+func (h *Hub) join(s *session, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.topics[topic] == nil {
+		h.topics[topic] = map[*session]struct{}{}
+	}
+	if _, ok := h.topics[topic][s]; ok {
+		return
+	}
+	h.topics[topic][s] = struct{}{}
+	h.announce(topic)
+}
+
+// leave unsubscribes s from topic and tells the rest who is left.
+func (h *Hub) leave(s *session, topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.remove(s, topic)
+}
+
+// remove must be called with h.mu held.
+func (h *Hub) remove(s *session, topic string) {
+	if _, ok := h.topics[topic][s]; !ok {
+		return
+	}
+	delete(h.topics[topic], s)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+		return
+	}
+	h.announce(topic)
+}
+
+// announce sends topic's presence to its subscribers. h.mu must be held.
+func (h *Hub) announce(topic string) {
+	sessions := map[int]int{}
+	for s := range h.topics[topic] {
+		sessions[s.userID]++
+	}
+	users := make([]Presence, 0, len(sessions))
+	for userID, n := range sessions {
+		users = append(users, Presence{UserID: userID, Sessions: n})
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].UserID < users[j].UserID })
+
+	for s := range h.topics[topic] {
+		s.send(Outbound{Type: TypePresence, Topic: topic, Users: users})
+	}
+}
+
+// HandleEvent pushes task events to the sessions subscribed to the task or
+// to its project, and disconnects a deleted user. It is an events.Listener.
+func (h *Hub) HandleEvent(e *events.Envelope) {
+	if e.Type == events.TypeUserDeleted {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		for s := range h.sessions {
+			if s.userID == e.UserID {
+				s.end("account deleted")
+			}
+		}
+		return
+	}
+	if !strings.HasPrefix(e.Type, "task.") {
+		return
+	}
+	var ev struct {
+		Task dto.GetTaskResponse `json:"task"`
+		From string              `json:"from"`
+	}
+	if err := e.Decode(&ev); err != nil {
+		log.Printf("collab: decoding %s %s: %v", e.Type, e.ID, err)
+		return
+	}
+	topics := []string{taskTopic(ev.Task.ID)}
+	if ev.Task.ProjectID != nil {
+		topics = append(topics, projectTopic(*ev.Task.ProjectID))
+	}
+	data := dto.TaskEventData{Task: ev.Task, PreviousStatus: ev.From}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	sent := map[*session]bool{}
+	for _, topic := range topics {
+		for s := range h.topics[topic] {
+			// Subscriptions are checked when they are made, but a task
+			// can move to a project the subscriber can't see. Only the
+			// task's owner can see it.
+			if sent[s] || s.userID != e.UserID {
+				continue
+			}
+			sent[s] = true
+			s.send(Outbound{Type: TypeEvent, Topic: topic, Event: e.Type, EventID: e.ID, Data: data})
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Package collab runs the WebSocket collaboration channel: clients
+// subscribe to tasks and projects, see who else is looking at them, get
+// their task events pushed and send task commands.
+//
+// Every message is a JSON object with a type. Messages a client sends
+// may carry a ref, which the server echoes in the matching ack or error.
+package collab
+
+import "encoding/json"
+
+// Message types sent by clients.
+const (
+	// TypeAuth carries a JWT. It must be the first message unless the
+	// connection was opened with an Authorization header.
+	TypeAuth        = "auth"
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypeCommand     = "command"
+	// TypePing and TypePong are sent both ways. The server pings every
+	// PingInterval and closes connections that send nothing for
+	// PingInterval plus PongWait.
+	TypePing = "ping"
+	TypePong = "pong"
+)
+
+// Message types sent by the server.
+const (
+	TypeReady    = "ready"
+	TypeAck      = "ack"
+	TypeError    = "error"
+	TypeEvent    = "event"
+	TypePresence = "presence"
+)
+
+// Commands, with the data they take.
+const (
+	CommandCreateTask   = "task.create"        // dto.CreateTaskRequest
+	CommandUpdateStatus = "task.update_status" // UpdateStatusCommand
+	CommandMoveTask     = "task.move"          // MoveCommand
+	CommandDeleteTask   = "task.delete"        // TaskCommand
+	CommandRestoreTask  = "task.restore"       // TaskCommand
+)
+
+// Error codes.
+const (
+	CodeUnauthorized = "unauthorized"
+	CodeBadRequest   = "bad_request"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeRateLimited  = "rate_limited"
+)
+
+// Inbound is a message from a client.
+type Inbound struct {
+	Type    string          `json:"type"`
+	Ref     string          `json:"ref,omitempty"`
+	Token   string          `json:"token,omitempty"`
+	Topic   string          `json:"topic,omitempty"`
+	Command string          `json:"command,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Outbound is a message to a client.
+type Outbound struct {
+	Type    string     `json:"type"`
+	Ref     string     `json:"ref,omitempty"`
+	Topic   string     `json:"topic,omitempty"`
+	Event   string     `json:"event,omitempty"`
+	EventID string     `json:"event_id,omitempty"`
+	Users   []Presence `json:"users,omitempty"`
+	Data    any        `json:"data,omitempty"`
+	Code    string     `json:"code,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+// Presence is one user subscribed to a topic.
+type Presence struct {
+	UserID int `json:"user_id"`
+	// Sessions counts the user's connections subscribed to the topic.
+	Sessions int `json:"sessions"`
+}
+
+type TaskCommand struct {
+	ID int `json:"id" binding:"required,min=1"`
+}
+
+type UpdateStatusCommand struct {
+	ID     int    `json:"id" binding:"required,min=1"`
+	Status string `json:"status" binding:"required,oneof=pending in-progress completed"`
+}
+
+type MoveCommand struct {
+	ID       int    `json:"id" binding:"required,min=1"`
+	AfterID  int    `json:"after_id,omitempty" binding:"omitempty,min=1"`
+	BeforeID int    `json:"before_id,omitempty" binding:"omitempty,min=1"`
+	Status   string `json:"status,omitempty" binding:"omitempty,oneof=pending in-progress completed"`
+}
+
+func errorMessage(ref, code, message string) Outbound {
+	return Outbound{Type: TypeError, Ref: ref, Code: code, Error: message}
+}
@@ -0,0 +1,360 @@
+package collab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/dto"
+	project_service "taskflow/internal/service/project"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg"
+
+	"github.com/gin-gonic/gin/binding"
+	"golang.org/x/time/rate"
+	"gorm.io/gorm"
+)
+
+const (
+	// sendBuffer is how many messages may wait for a slow client before
+	// its connection is closed.
+	sendBuffer = 64
+	// maxTopics bounds the subscriptions of one connection.
+	maxTopics    = 100
+	writeTimeout = 10 * time.Second
+	authTimeout  = 10 * time.Second
+)
+
+// ErrMalformed is returned by Conn.Receive for a message that could not be
+// decoded. The connection stays usable.
+var ErrMalformed = errors.New("malformed message")
+
+var (
+	errInvalidTopic   = errors.New("topic must be task:<id> or project:<id>")
+	errUnknownCommand = errors.New("unknown command")
+	errInvalidData    = errors.New("invalid command data")
+)
+
+// Conn is a message-oriented client connection, such as a WebSocket.
+type Conn interface {
+	Receive(v any) error
+	Send(v any) error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	Close() error
+}
+
+type Config struct {
+	PingInterval time.Duration
+	// PongWait is how long after a ping a silent connection is closed.
+	PongWait time.Duration
+	// RateLimit is how many messages per second a connection may send,
+	// with bursts of up to RateBurst.
+	RateLimit float64
+	RateBurst int
+}
+
+func LoadConfigFromEnv() Config {
+	return Config{
+		PingInterval: pkg.DurationEnv("COLLAB_PING_INTERVAL", 30*time.Second),
+		PongWait:     pkg.DurationEnv("COLLAB_PONG_WAIT", 30*time.Second),
+		RateLimit:    pkg.FloatEnv("COLLAB_RATE_LIMIT", 20),
+		RateBurst:    pkg.IntEnv("COLLAB_RATE_BURST", 40),
+	}
+}
+
+// Server runs collaboration sessions.
+type Server struct {
+	hub      *Hub
+	userAuth auth.UserAuthInterface
+	tasks    task_service.TaskServiceInterface
+	projects project_service.ProjectServiceInterface
+	cfg      Config
+}
+
+func NewServer(
+	hub *Hub,
+	ua auth.UserAuthInterface,
+	tasks task_service.TaskServiceInterface,
+	projects project_service.ProjectServiceInterface,
+	cfg Config,
+) *Server {
+	return &Server{hub: hub, userAuth: ua, tasks: tasks, projects: projects, cfg: cfg}
+}
+
+// session is one client connection. Only its read loop touches topics;
+// everything else talks to the client through send.
+type session struct {
+	userID int
+	// expiresAt is when the session's token expires; zero when it doesn't.
+	expiresAt time.Time
+	conn      Conn
+	out       chan Outbound
+	// revoke ends the session with an unauthorized error carrying the
+	// reason.
+	revoke  chan string
+	done    chan struct{}
+	once    sync.Once
+	topics  map[string]bool
+	limiter *rate.Limiter
+}
+
+// send queues m for the client. A client that doesn't keep up is
+// disconnected rather than slowing down everyone who publishes to it.
+func (s *session) send(m Outbound) {
+	select {
+	case <-s.done:
+	case s.out <- m:
+	default:
+		log.Printf("collab: closing connection of user %d: client too slow", s.userID)
+		s.close()
+	}
+}
+
+// end asks the write loop to tell the client why and disconnect it.
+func (s *session) end(reason string) {
+	select {
+	case s.revoke <- reason:
+	default: // already ending
+	}
+}
+
+func (s *session) close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.conn.Close()
+	})
+}
+
+// Serve runs a session on conn until the client disconnects or its token
+// expires at expiresAt. userID is 0 when the connection is not
+// authenticated yet; the first message must then be an auth message.
+func (srv *Server) Serve(conn Conn, userID int, expiresAt time.Time) {
+	defer conn.Close()
+
+	if userID == 0 {
+		var err error
+		if userID, expiresAt, err = srv.authenticate(conn); err != nil {
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			conn.Send(errorMessage("", CodeUnauthorized, err.Error()))
+			return
+		}
+	}
+
+	s := &session{
+		userID:    userID,
+		expiresAt: expiresAt,
+		conn:      conn,
+		out:       make(chan Outbound, sendBuffer),
+		revoke:    make(chan string, 1),
+		done:      make(chan struct{}),
+		topics:    map[string]bool{},
+		limiter:   rate.NewLimiter(rate.Limit(srv.cfg.RateLimit), srv.cfg.RateBurst),
+	}
+	srv.hub.register(s)
+	defer srv.hub.unregister(s)
+	defer s.close()
+
+	go srv.writeLoop(s)
+	s.send(Outbound{Type: TypeReady, Data: map[string]int{"user_id": userID}})
+	srv.readLoop(s)
+}
+
+func (srv *Server) authenticate(conn Conn) (int, time.Time, error) {
+	conn.SetReadDeadline(time.Now().Add(authTimeout))
+	var m Inbound
+	if err := conn.Receive(&m); err != nil || m.Type != TypeAuth {
+		return 0, time.Time{}, errors.New("authenticate first")
+	}
+	userID, err := srv.userAuth.Authenticate(m.Token)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrInvalidUserID) ||
+			errors.Is(err, auth.ErrInvalidClaims) || errors.Is(err, auth.ErrUserNotFound) {
+			return 0, time.Time{}, err
+		}
+		return 0, time.Time{}, errors.New("authentication failed")
+	}
+	expiresAt, err := srv.userAuth.ExpiresAt(m.Token)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return userID, expiresAt, nil
+}
+
+func (srv *Server) writeLoop(s *session) {
+	ping := time.NewTicker(srv.cfg.PingInterval)
+	defer ping.Stop()
+	var expired <-chan time.Time
+	if !s.expiresAt.IsZero() {
+		timer := time.NewTimer(time.Until(s.expiresAt))
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for {
+		var m Outbound
+		select {
+		case <-s.done:
+			return
+		case m = <-s.out:
+		case <-ping.C:
+			m = Outbound{Type: TypePing}
+		case <-expired:
+			s.end("token expired")
+			continue
+		case reason := <-s.revoke:
+			s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			s.conn.Send(errorMessage("", CodeUnauthorized, reason))
+			s.close()
+			return
+		}
+		s.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := s.conn.Send(m); err != nil {
+			s.close()
+			return
+		}
+	}
+}
+
+func (srv *Server) readLoop(s *session) {
+	for {
+		// Any message, including a pong, shows the client is alive.
+		s.conn.SetReadDeadline(time.Now().Add(srv.cfg.PingInterval + srv.cfg.PongWait))
+		var m Inbound
+		err := s.conn.Receive(&m)
+		if err != nil && !errors.Is(err, ErrMalformed) {
+			return
+		}
+		if !s.limiter.Allow() {
+			s.send(errorMessage(m.Ref, CodeRateLimited, "too many messages"))
+			continue
+		}
+		if err != nil {
+			s.send(errorMessage("", CodeBadRequest, err.Error()))
+			continue
+		}
+		srv.handle(s, m)
+	}
+}
+
+func (srv *Server) handle(s *session, m Inbound) {
+	switch m.Type {
+	case TypePing:
+		s.send(Outbound{Type: TypePong, Ref: m.Ref})
+	case TypePong:
+	case TypeSubscribe:
+		if len(s.topics) >= maxTopics {
+			s.send(errorMessage(m.Ref, CodeBadRequest, fmt.Sprintf("at most %d subscriptions per connection", maxTopics)))
+			return
+		}
+		if err := srv.authorize(s.userID, m.Topic); err != nil {
+			s.send(toErrorMessage(m.Ref, err))
+			return
+		}
+		s.send(Outbound{Type: TypeAck, Ref: m.Ref, Topic: m.Topic})
+		s.topics[m.Topic] = true
+		srv.hub.join(s, m.Topic)
+	case TypeUnsubscribe:
+		delete(s.topics, m.Topic)
+		srv.hub.leave(s, m.Topic)
+		s.send(Outbound{Type: TypeAck, Ref: m.Ref, Topic: m.Topic})
+	case TypeCommand:
+		data, err := srv.runCommand(s.userID, m.Command, m.Data)
+		if err != nil {
+			s.send(toErrorMessage(m.Ref, err))
+			return
+		}
+		s.send(Outbound{Type: TypeAck, Ref: m.Ref, Data: data})
+	case TypeAuth:
+		s.send(errorMessage(m.Ref, CodeBadRequest, "already authenticated"))
+	default:
+		s.send(errorMessage(m.Ref, CodeBadRequest, fmt.Sprintf("unknown message type %q", m.Type)))
+	}
+}
+
+// authorize checks that userID can see topic.
+func (srv *Server) authorize(userID int, topic string) error {
+	kind, rawID, _ := strings.Cut(topic, ":")
+	id, err := strconv.Atoi(rawID)
+	if err != nil || id < 1 {
+		return errInvalidTopic
+	}
+	switch kind {
+	case "task":
+		_, err = srv.tasks.GetTask(userID, id)
+	case "project":
+		_, err = srv.projects.GetProject(userID, id)
+	default:
+		return errInvalidTopic
+	}
+	return err
+}
+
+// runCommand runs a task command through the task service and returns
+// what the matching REST endpoint would.
+func (srv *Server) runCommand(userID int, command string, data json.RawMessage) (any, error) {
+	switch command {
+	case CommandCreateTask:
+		var req dto.CreateTaskRequest
+		if err := decode(data, &req); err != nil {
+			return nil, err
+		}
+		return srv.tasks.CreateTask(userID, &req)
+	case CommandUpdateStatus:
+		var cmd UpdateStatusCommand
+		if err := decode(data, &cmd); err != nil {
+			return nil, err
+		}
+		return srv.tasks.UpdateStatus(userID, cmd.ID, cmd.Status)
+	case CommandMoveTask:
+		var cmd MoveCommand
+		if err := decode(data, &cmd); err != nil {
+			return nil, err
+		}
+		return srv.tasks.Move(userID, cmd.ID, &dto.MoveTaskRequest{AfterID: cmd.AfterID, BeforeID: cmd.BeforeID, Status: cmd.Status})
+	case CommandDeleteTask:
+		var cmd TaskCommand
+		if err := decode(data, &cmd); err != nil {
+			return nil, err
+		}
+		return nil, srv.tasks.Delete(userID, cmd.ID)
+	case CommandRestoreTask:
+		var cmd TaskCommand
+		if err := decode(data, &cmd); err != nil {
+			return nil, err
+		}
+		return srv.tasks.Restore(userID, cmd.ID)
+	default:
+		return nil, errUnknownCommand
+	}
+}
+
+// decode unmarshals command data and validates it like a request body.
+func decode(data json.RawMessage, v any) error {
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidData, err)
+	}
+	if err := binding.Validator.ValidateStruct(v); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidData, err)
+	}
+	return nil
+}
+
+func toErrorMessage(ref string, err error) Outbound {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errorMessage(ref, CodeNotFound, "not found")
+	case errors.Is(err, task_service.ErrWIPLimitReached):
+		return errorMessage(ref, CodeConflict, err.Error())
+	default:
+		return errorMessage(ref, CodeBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,27 @@
+package common
+
+import "strconv"
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ParsePagination converts raw page/limit query values into a 1-based page
+// and a bounded page size, falling back to defaults on missing or bad input.
+func ParsePagination(pageStr, limitStr string) (page int, limit int) {
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	limit, err = strconv.Atoi(limitStr)
+	if err != nil || limit < 1 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	return page, limit
+}
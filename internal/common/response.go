@@ -3,6 +3,8 @@ package common
 // ErrorResponse defines standard error format
 type ErrorResponse struct {
 	Message string `json:"error"`
+	// Position is the 1-based character offset of a query syntax error.
+	Position int `json:"position,omitempty"`
 }
 
 func (e ErrorResponse) Error() string {
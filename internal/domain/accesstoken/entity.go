@@ -0,0 +1,23 @@
+package accesstoken
+
+import "time"
+
+// Token is a personal access token, for clients that can't log in, such
+// as calendar apps. Only a hash of the secret is stored; the secret itself
+// is shown once, when the token is created.
+type Token struct {
+	ID     int    `json:"id" gorm:"primaryKey"`
+	UserID int    `json:"user_id" gorm:"not null;index"`
+	Name   string `json:"name" example:"iPhone Reminders" gorm:"size:100;not null"`
+	// Prefix is the start of the secret, to tell tokens apart.
+	Prefix     string     `json:"prefix" example:"tfp_3f9a1c" gorm:"size:16;not null"`
+	Hash       string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (Token) TableName() string {
+	return "access_tokens"
+}
@@ -0,0 +1,14 @@
+package attachment
+
+import "time"
+
+type Attachment struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	TaskID      int       `json:"task_id" gorm:"not null;index"`
+	UserID      int       `json:"user_id" gorm:"not null;index"`
+	FileName    string    `json:"file_name" example:"spec.pdf" gorm:"size:255;not null"`
+	ContentType string    `json:"content_type" example:"application/pdf" gorm:"size:127;not null"`
+	Size        int64     `json:"size" example:"52341" gorm:"not null"`
+	StorageKey  string    `json:"-" gorm:"size:255;not null;uniqueIndex"`
+	CreatedAt   time.Time `json:"created_at"`
+}
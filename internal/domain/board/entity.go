@@ -0,0 +1,20 @@
+package board
+
+import "time"
+
+// Column holds the settings of one status column on a board. A board is the
+// inbox (ProjectID nil) or a project, with one column per task status.
+// Columns without a row have no settings.
+type Column struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	UserID    int       `json:"user_id" gorm:"not null;index"`
+	ProjectID *int      `json:"project_id" gorm:"index"`
+	Status    string    `json:"status" example:"in-progress" gorm:"not null;size:20"`
+	WIPLimit  int       `json:"wip_limit" example:"3" gorm:"column:wip_limit;not null;default:0"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (Column) TableName() string {
+	return "board_columns"
+}
@@ -0,0 +1,35 @@
+package caldav
+
+import "time"
+
+// Object keeps the resource name and UID a CalDAV client gave a task it
+// created, since clients find their resources by them. Other tasks are
+// served as task-<id>.ics.
+type Object struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	UserID    int       `json:"user_id" gorm:"not null;uniqueIndex:idx_caldav_objects_user_name,priority:1"`
+	TaskID    int       `json:"task_id" gorm:"not null;uniqueIndex"`
+	Name      string    `json:"name" example:"5E2A1F0C-8C3B-4C1B-9A57-1D2E3F405162.ics" gorm:"size:255;not null;uniqueIndex:idx_caldav_objects_user_name,priority:2"`
+	UID       string    `json:"uid" example:"5E2A1F0C-8C3B-4C1B-9A57-1D2E3F405162" gorm:"size:255;not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Object) TableName() string {
+	return "caldav_objects"
+}
+
+// Tombstone records that a task was deleted for good, so sync reports can
+// tell clients to remove it. Tombstones are purged after a while; older
+// sync tokens then stop being accepted.
+type Tombstone struct {
+	ID        int64     `json:"id" gorm:"primaryKey"`
+	UserID    int       `json:"user_id" gorm:"not null;index"`
+	TaskID    int       `json:"task_id" gorm:"not null;uniqueIndex"`
+	ProjectID *int      `json:"project_id"`
+	Name      string    `json:"name" gorm:"size:255;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+func (Tombstone) TableName() string {
+	return "caldav_tombstones"
+}
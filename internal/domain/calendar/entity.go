@@ -0,0 +1,29 @@
+package calendar
+
+import "time"
+
+// Components a feed can render tasks as.
+const (
+	ComponentEvent = "VEVENT"
+	ComponentTodo  = "VTODO"
+)
+
+// Feed is a subscribable iCalendar feed of a user's tasks with due dates,
+// authorized by its secret token. Deleting the feed revokes the token.
+type Feed struct {
+	ID        int    `json:"id" gorm:"primaryKey"`
+	UserID    int    `json:"user_id" gorm:"not null;index"`
+	Name      string `json:"name" example:"Work" gorm:"size:100;not null"`
+	Token     string `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	ProjectID *int   `json:"project_id"`
+	// Component is ComponentEvent, which calendar apps show, or
+	// ComponentTodo, which task apps show.
+	Component        string    `json:"component" example:"VEVENT" gorm:"size:10;not null"`
+	IncludeCompleted bool      `json:"include_completed" gorm:"not null;default:false"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (Feed) TableName() string {
+	return "calendar_feeds"
+}
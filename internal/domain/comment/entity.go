@@ -0,0 +1,29 @@
+package comment
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Comment struct {
+	ID        int            `json:"id" gorm:"primaryKey"`
+	TaskID    int            `json:"task_id" gorm:"not null;index"`
+	UserID    int            `json:"user_id" gorm:"not null;index"`
+	Body      string         `json:"body" example:"Looks good, @jane@example.com can you review?" gorm:"type:text;not null"`
+	Mentions  []Mention      `json:"mentions" gorm:"foreignKey:CommentID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Mention links a comment to a user referenced with @email in its body.
+type Mention struct {
+	ID        int `json:"-" gorm:"primaryKey"`
+	CommentID int `json:"-" gorm:"not null;index"`
+	UserID    int `json:"user_id" gorm:"not null;index"`
+}
+
+func (Mention) TableName() string {
+	return "comment_mentions"
+}
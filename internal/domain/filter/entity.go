@@ -0,0 +1,68 @@
+package filter
+
+import "time"
+
+// Columns a saved filter may sort by.
+const (
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortDueAt     = "due_at"
+	SortPriority  = "priority"
+	SortTask      = "task"
+	SortPosition  = "position"
+)
+
+// Filter is a named, saved view over a user's tasks (a "smart list").
+type Filter struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	UserID    int       `json:"user_id" gorm:"not null;index"`
+	Name      string    `json:"name" example:"Work this week" gorm:"not null;size:100"`
+	Builtin   bool      `json:"builtin" gorm:"not null;default:false"`
+	Criteria  Criteria  `json:"criteria" gorm:"embedded"`
+	SortBy    string    `json:"sort_by" example:"created_at" gorm:"not null;size:20;default:created_at"`
+	SortDesc  bool      `json:"sort_desc" gorm:"not null;default:false"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Criteria narrows the tasks a filter matches. Zero values match everything.
+type Criteria struct {
+	Status      string     `json:"status,omitempty" example:"pending" gorm:"size:20"`
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
+	UpdatedFrom *time.Time `json:"updated_from,omitempty"`
+	UpdatedTo   *time.Time `json:"updated_to,omitempty"`
+	Text        string     `json:"text,omitempty" example:"report" gorm:"size:200"`
+	// Query is an optional task query language expression, e.g. "tag:work AND due<7d".
+	Query string `json:"query,omitempty" example:"tag:work" gorm:"size:500"`
+}
+
+// Defaults are the built-in smart lists every user starts with. Their
+// relative dates are expressed in the task query language so they stay
+// current without being rewritten.
+func Defaults(userID int) []Filter {
+	return []Filter{
+		{
+			UserID:   userID,
+			Name:     "Today",
+			Builtin:  true,
+			Criteria: Criteria{Query: "due:today AND status!=completed"},
+			SortBy:   SortDueAt,
+		},
+		{
+			UserID:   userID,
+			Name:     "Overdue",
+			Builtin:  true,
+			Criteria: Criteria{Query: "due<now AND status!=completed"},
+			SortBy:   SortDueAt,
+		},
+		{
+			UserID:   userID,
+			Name:     "Completed recently",
+			Builtin:  true,
+			Criteria: Criteria{Status: "completed", Query: "updated>-7d"},
+			SortBy:   SortUpdatedAt,
+			SortDesc: true,
+		},
+	}
+}
@@ -0,0 +1,57 @@
+package importjob
+
+import "time"
+
+// Job statuses.
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Job imports a file exported from another tool, such as a Todoist backup,
+// in the background. The file is kept so the job can be run again; tasks
+// that are already imported are skipped, so a run that stopped halfway
+// picks up where it left off.
+//
+// The counts are those of the latest attempt of the latest run.
+type Job struct {
+	ID         int    `json:"id" gorm:"primaryKey"`
+	UserID     int    `json:"user_id" gorm:"not null;index"`
+	Source     string `json:"source" example:"todoist" gorm:"size:20;not null"`
+	FileName   string `json:"file_name" example:"todoist-backup.json" gorm:"size:255;not null"`
+	StorageKey string `json:"-" gorm:"size:255;not null"`
+	Status     string `json:"status" example:"running" gorm:"size:20;not null"`
+	// Run counts the times the job was started.
+	Run       int `json:"run" gorm:"not null;default:1"`
+	Total     int `json:"total" gorm:"not null;default:0"`
+	Processed int `json:"processed" gorm:"not null;default:0"`
+	Imported  int `json:"imported" gorm:"not null;default:0"`
+	// Skipped counts tasks that were imported before.
+	Skipped int         `json:"skipped" gorm:"not null;default:0"`
+	Failed  int         `json:"failed" gorm:"not null;default:0"`
+	Errors  []ItemError `json:"errors" gorm:"serializer:json;type:text"`
+	// Error is why the job failed as a whole.
+	Error      string     `json:"error" gorm:"type:text"`
+	StartedAt  *time.Time `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (Job) TableName() string {
+	return "import_jobs"
+}
+
+// Finished reports whether the job is done running, either way.
+func (j Job) Finished() bool {
+	return j.Status == StatusSucceeded || j.Status == StatusFailed
+}
+
+// ItemError is a task of the file that could not be imported.
+type ItemError struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Message string `json:"message"`
+}
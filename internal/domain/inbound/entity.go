@@ -0,0 +1,19 @@
+package inbound
+
+import "time"
+
+// Address is a secret capture address. Anything posted to its URL, or
+// mailed to it when the SMTP listener runs, becomes a task of the user.
+type Address struct {
+	ID         int        `json:"id" gorm:"primaryKey"`
+	UserID     int        `json:"user_id" gorm:"not null;index"`
+	Name       string     `json:"name" example:"Zapier" gorm:"size:100;not null"`
+	Token      string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (Address) TableName() string {
+	return "inbound_addresses"
+}
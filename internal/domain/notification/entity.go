@@ -0,0 +1,70 @@
+package notification
+
+import (
+	"slices"
+	"time"
+)
+
+// Notification types.
+const (
+	TypeReminder     = "reminder"
+	TypeSubtasksDone = "subtasks_done"
+)
+
+// Types lists every notification type a user can set a preference for.
+var Types = []string{TypeReminder, TypeSubtasksDone}
+
+func ValidType(t string) bool {
+	return slices.Contains(Types, t)
+}
+
+// Deliveries a user can choose for a notification type.
+const (
+	DeliveryInApp = "in_app"
+	DeliveryEmail = "email"
+	DeliveryBoth  = "both"
+	DeliveryOff   = "off"
+)
+
+func ValidDelivery(d string) bool {
+	switch d {
+	case DeliveryInApp, DeliveryEmail, DeliveryBoth, DeliveryOff:
+		return true
+	}
+	return false
+}
+
+// DefaultDelivery is used for types the user has no preference for.
+// Reminders are worth an email; everything else stays in the app.
+func DefaultDelivery(t string) string {
+	if t == TypeReminder {
+		return DeliveryBoth
+	}
+	return DeliveryInApp
+}
+
+// Notification is a message in a user's in-app inbox.
+type Notification struct {
+	ID     int    `json:"id" gorm:"primaryKey"`
+	UserID int    `json:"user_id" gorm:"not null;index:idx_notifications_user_created,priority:1"`
+	Type   string `json:"type" example:"reminder" gorm:"size:50;not null"`
+	Title  string `json:"title" example:"Reminder: Pay rent" gorm:"size:255;not null"`
+	Body   string `json:"body" gorm:"type:text"`
+	TaskID *int   `json:"task_id" gorm:"index"`
+	// Key deduplicates retried deliveries of the same event; nil when the
+	// producer does not need it.
+	Key       *string    `json:"-" gorm:"size:191;uniqueIndex"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"index:idx_notifications_user_created,priority:2"`
+}
+
+// Preference is how a user wants to receive one type of notification.
+type Preference struct {
+	UserID   int    `json:"-" gorm:"primaryKey;autoIncrement:false"`
+	Type     string `json:"type" example:"subtasks_done" gorm:"primaryKey;size:50"`
+	Delivery string `json:"delivery" example:"both" gorm:"size:10;not null"`
+}
+
+func (Preference) TableName() string {
+	return "notification_preferences"
+}
@@ -0,0 +1,12 @@
+package project
+
+import "time"
+
+// Project groups a user's tasks. Tasks without a project are in the inbox.
+type Project struct {
+	ID        int       `json:"id" gorm:"primaryKey"`
+	UserID    int       `json:"user_id" gorm:"not null;index"`
+	Name      string    `json:"name" example:"Home renovation" gorm:"not null;size:100"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
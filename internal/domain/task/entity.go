@@ -1,11 +1,66 @@
 package task
 
-import "time"
+import (
+	"taskflow/internal/domain/attachment"
+	"taskflow/internal/domain/comment"
+	"taskflow/internal/domain/timeentry"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Task statuses, in board column order.
+const (
+	StatusPending    = "pending"
+	StatusInProgress = "in-progress"
+	StatusCompleted  = "completed"
+)
+
+// Statuses lists every task status in board column order.
+var Statuses = []string{StatusPending, StatusInProgress, StatusCompleted}
+
+// ValidStatus reports whether status is one of Statuses.
+func ValidStatus(status string) bool {
+	for _, s := range Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
 
 type Task struct {
-	ID        int       `json:"id" gorm:"primaryKey"`
-	Task      string    `json:"task" binding:"required" example:"Buy milk" gorm:"not null"`
-	Status    string    `json:"status" binding:"required" example:"pending" gorm:"not null"`
-	UserID    int       `json:"user_id" gorm:"not null;index"`
-	CreatedAt time.Time `json:"created_at" example:"2025-08-27 10:35:16.263"`
+	ID              int                     `json:"id" gorm:"primaryKey"`
+	Task            string                  `json:"task" binding:"required" example:"Buy milk" gorm:"not null"`
+	Description     string                  `json:"description" example:"2 litres, semi-skimmed" gorm:"type:text"`
+	Status          string                  `json:"status" binding:"required" example:"pending" gorm:"not null"`
+	Priority        Priority                `json:"priority" example:"high" gorm:"not null;default:0;index"`
+	DueAt           *time.Time              `json:"due_at" gorm:"index"`
+	CompletedAt     *time.Time              `json:"completed_at" gorm:"index"`
+	Recurrence      string                  `json:"recurrence" example:"FREQ=MONTHLY" gorm:"size:255;not null;default:''"`
+	EstimateMinutes *int                    `json:"estimate_minutes" example:"90"`
+	UserID          int                     `json:"user_id" gorm:"not null;index;uniqueIndex:idx_tasks_user_external,priority:1"`
+	ProjectID       *int                    `json:"project_id" gorm:"index"`
+	ParentID        *int                    `json:"parent_id" gorm:"index"`
+	Position        string                  `json:"position" example:"i" gorm:"size:64;not null;default:'';index"`
+	CreatedAt       time.Time               `json:"created_at" example:"2025-08-27 10:35:16.263"`
+	UpdatedAt       time.Time               `json:"updated_at" example:"2025-08-28 09:12:03.114"`
+	DeletedAt       gorm.DeletedAt          `json:"-" gorm:"index"`
+	Tags            []Tag                   `json:"tags" gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`
+	Comments        []comment.Comment       `json:"-" gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`
+	Attachments     []attachment.Attachment `json:"-" gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`
+	TimeEntries     []timeentry.Entry       `json:"-" gorm:"foreignKey:TaskID;constraint:OnDelete:CASCADE"`
+	// ExternalID identifies a task imported from another tool, as
+	// "<source>:<id>", so that importing it again can be skipped.
+	ExternalID *string `json:"-" gorm:"size:191;uniqueIndex:idx_tasks_user_external,priority:2"`
+}
+
+// Tag is a free-form, lower-case label on a task.
+type Tag struct {
+	TaskID int    `json:"-" gorm:"primaryKey"`
+	Name   string `json:"name" example:"work" gorm:"primaryKey;size:50;index"`
+}
+
+func (Tag) TableName() string {
+	return "task_tags"
 }
@@ -0,0 +1,33 @@
+package task
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePriority(t *testing.T) {
+	p, err := ParsePriority("high")
+	require.NoError(t, err)
+	assert.Equal(t, PriorityHigh, p)
+
+	p, err = ParsePriority("")
+	require.NoError(t, err)
+	assert.Equal(t, PriorityNone, p)
+
+	_, err = ParsePriority("critical")
+	assert.Error(t, err)
+}
+
+func TestPriority_JSON(t *testing.T) {
+	data, err := json.Marshal(PriorityUrgent)
+	require.NoError(t, err)
+	assert.Equal(t, `"urgent"`, string(data))
+
+	var p Priority
+	require.NoError(t, json.Unmarshal([]byte(`"low"`), &p))
+	assert.Equal(t, PriorityLow, p)
+	assert.Error(t, json.Unmarshal([]byte(`"nope"`), &p))
+}
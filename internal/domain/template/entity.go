@@ -0,0 +1,34 @@
+package template
+
+import "time"
+
+// Template is a reusable checklist: a task with subtasks that is copied into
+// real tasks when instantiated. Texts may contain {{name}} variables and due
+// dates are offsets from a base date; see ParseOffset and Expand.
+type Template struct {
+	ID          int       `json:"id" gorm:"primaryKey"`
+	UserID      int       `json:"user_id" gorm:"not null;index"`
+	Name        string    `json:"name" example:"Release prep" gorm:"not null;size:100"`
+	Title       string    `json:"title" example:"Release {{version}}" gorm:"not null;size:200"`
+	Description string    `json:"description" gorm:"type:text"`
+	Tags        []string  `json:"tags" gorm:"serializer:json;type:text"`
+	DueOffset   string    `json:"due_offset" example:"5d" gorm:"size:20;not null;default:''"`
+	Subtasks    []Subtask `json:"subtasks" gorm:"foreignKey:TemplateID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Subtask is one step of a template, kept in Position order.
+type Subtask struct {
+	ID          int      `json:"id" gorm:"primaryKey"`
+	TemplateID  int      `json:"-" gorm:"not null;index"`
+	Position    int      `json:"position" gorm:"not null;default:0"`
+	Title       string   `json:"title" example:"Tag {{version}}" gorm:"not null;size:200"`
+	Description string   `json:"description" gorm:"type:text"`
+	Tags        []string `json:"tags" gorm:"serializer:json;type:text"`
+	DueOffset   string   `json:"due_offset" example:"3d17h" gorm:"size:20;not null;default:''"`
+}
+
+func (Subtask) TableName() string {
+	return "template_subtasks"
+}
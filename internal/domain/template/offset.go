@@ -0,0 +1,86 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Offset is a due date relative to a base date, such as "3d", "-1w" or
+// "2d17h". Weeks and days count calendar days, so an offset keeps its wall
+// clock time across daylight saving changes.
+type Offset struct {
+	Days    int
+	Minutes int
+	// HasTime is set when the offset names hours or minutes. Offsets
+	// without one are due at the end of the day.
+	HasTime bool
+}
+
+var ErrInvalidOffset = errors.New("invalid due offset")
+
+// Offsets are limited to about ten years either way.
+const maxOffsetDays = 3660
+
+// ParseOffset reads an optional sign followed by one or more amounts with a
+// unit: w (weeks), d (days), h (hours) or m (minutes). The sign applies to
+// the whole offset.
+func ParseOffset(s string) (Offset, error) {
+	var o Offset
+	rest := strings.ToLower(strings.TrimSpace(s))
+	sign := 1
+	switch {
+	case strings.HasPrefix(rest, "-"):
+		sign, rest = -1, rest[1:]
+	case strings.HasPrefix(rest, "+"):
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return Offset{}, fmt.Errorf("%w %q", ErrInvalidOffset, s)
+	}
+
+	for rest != "" {
+		i := 0
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		if i == 0 || i == len(rest) || i > 6 {
+			return Offset{}, fmt.Errorf("%w %q", ErrInvalidOffset, s)
+		}
+		n, _ := strconv.Atoi(rest[:i])
+		switch rest[i] {
+		case 'w':
+			o.Days += 7 * n
+		case 'd':
+			o.Days += n
+		case 'h':
+			o.Minutes += 60 * n
+			o.HasTime = true
+		case 'm':
+			o.Minutes += n
+			o.HasTime = true
+		default:
+			return Offset{}, fmt.Errorf("%w %q", ErrInvalidOffset, s)
+		}
+		rest = rest[i+1:]
+	}
+
+	if o.Days > maxOffsetDays || o.Minutes > 24*60*maxOffsetDays {
+		return Offset{}, fmt.Errorf("%w %q: more than %d days away", ErrInvalidOffset, s, maxOffsetDays)
+	}
+	o.Days *= sign
+	o.Minutes *= sign
+	return o, nil
+}
+
+// Apply returns the due time for a base date, given as midnight in the
+// user's location. Offsets without a time are due at 23:59 on their day.
+func (o Offset) Apply(base time.Time) time.Time {
+	day := base.AddDate(0, 0, o.Days)
+	if !o.HasTime {
+		return time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 0, 0, day.Location())
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, o.Minutes, 0, 0, day.Location())
+}
@@ -0,0 +1,71 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Offset
+	}{
+		{"3d", Offset{Days: 3}},
+		{"+1w", Offset{Days: 7}},
+		{"-2d", Offset{Days: -2}},
+		{"2d17h", Offset{Days: 2, Minutes: 17 * 60, HasTime: true}},
+		{"-1w2d", Offset{Days: -9}},
+		{"9h30m", Offset{Minutes: 9*60 + 30, HasTime: true}},
+		{"0d", Offset{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseOffset(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, in := range []string{"", "-", "d", "3", "3x", "1.5d", "d3", "9999w"} {
+		_, err := ParseOffset(in)
+		assert.ErrorIs(t, err, ErrInvalidOffset, in)
+	}
+}
+
+func TestOffset_Apply(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	// Daylight saving ends on 2 November 2025 in New York.
+	base := time.Date(2025, 10, 31, 0, 0, 0, 0, ny)
+
+	o, _ := ParseOffset("3d")
+	assert.Equal(t, time.Date(2025, 11, 3, 23, 59, 0, 0, ny), o.Apply(base))
+
+	o, _ = ParseOffset("3d9h")
+	assert.Equal(t, time.Date(2025, 11, 3, 9, 0, 0, 0, ny), o.Apply(base))
+
+	o, _ = ParseOffset("-1d")
+	assert.Equal(t, time.Date(2025, 10, 30, 23, 59, 0, 0, ny), o.Apply(base))
+}
+
+func TestExpand(t *testing.T) {
+	vars := map[string]string{"version": "2.4", "owner": "Sam"}
+
+	got, err := Expand("Release {{version}} by {{ owner }}", vars)
+	require.NoError(t, err)
+	assert.Equal(t, "Release 2.4 by Sam", got)
+
+	got, err = Expand("No variables {here}", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "No variables {here}", got)
+
+	_, err = Expand("Release {{version}} on {{date}}", vars)
+	assert.ErrorIs(t, err, ErrMissingVariable)
+	assert.EqualError(t, err, `no value for variable "date"`)
+}
+
+func TestVariables(t *testing.T) {
+	assert.Equal(t, []string{"owner", "version"}, Variables("{{version}} {{owner}}", "{{ version }}"))
+	assert.Nil(t, Variables("plain"))
+}
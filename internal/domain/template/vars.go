@@ -0,0 +1,48 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+var ErrMissingVariable = errors.New("no value for variable")
+
+// variable matches {{name}} with optional spaces inside the braces.
+var variable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Variables returns the names of the variables used in texts, sorted and
+// without duplicates.
+func Variables(texts ...string) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, s := range texts {
+		for _, m := range variable.FindAllStringSubmatch(s, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				names = append(names, m[1])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Expand replaces every {{name}} in s with its value. A variable without a
+// value is an error, so no task is created with a placeholder left in it.
+func Expand(s string, vars map[string]string) (string, error) {
+	var missing string
+	out := variable.ReplaceAllStringFunc(s, func(m string) string {
+		name := variable.FindStringSubmatch(m)[1]
+		v, ok := vars[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return v
+	})
+	if missing != "" {
+		return "", fmt.Errorf("%w %q", ErrMissingVariable, missing)
+	}
+	return out, nil
+}
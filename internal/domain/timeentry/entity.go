@@ -0,0 +1,40 @@
+package timeentry
+
+import "time"
+
+// Entry is a span of time a user spent on a task, either tracked with a
+// timer or entered by hand. A running timer has no EndedAt.
+type Entry struct {
+	ID        int        `json:"id" gorm:"primaryKey"`
+	TaskID    int        `json:"task_id" gorm:"not null;index"`
+	UserID    int        `json:"user_id" gorm:"not null;index:idx_time_entries_user_started"`
+	StartedAt time.Time  `json:"started_at" gorm:"not null;index:idx_time_entries_user_started"`
+	EndedAt   *time.Time `json:"ended_at"`
+	Note      string     `json:"note" example:"Client call" gorm:"size:500"`
+	// RunningUserID is the owner's ID while the timer runs and NULL
+	// otherwise; its unique index allows one running timer per user.
+	RunningUserID *int      `json:"-" gorm:"uniqueIndex"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (Entry) TableName() string {
+	return "time_entries"
+}
+
+// Running reports whether the entry is a timer that has not been stopped.
+func (e Entry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// Duration returns the tracked time, counting a running timer up to now.
+func (e Entry) Duration(now time.Time) time.Duration {
+	end := now
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	if end.Before(e.StartedAt) {
+		return 0
+	}
+	return end.Sub(e.StartedAt)
+}
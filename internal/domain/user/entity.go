@@ -8,11 +8,25 @@ import (
 )
 
 type User struct {
-	ID        int            `gorm:"primaryKey" json:"id"`
-	Email     string         `gorm:"uniqueIndex;size:255;not null" json:"email"`
-	Password  string         `gorm:"size:255;not null" json:"-"`
+	ID       int    `gorm:"primaryKey" json:"id"`
+	Email    string `gorm:"uniqueIndex;size:255;not null" json:"email"`
+	Password string `gorm:"size:255;not null" json:"-"`
+	// TimeZone is an IANA zone name used for day boundaries in statistics.
+	TimeZone  string         `gorm:"size:64;not null;default:'UTC'" json:"time_zone"`
 	Tasks     []task.Task    `json:"tasks" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"`
 }
+
+// Location returns the user's time zone, or UTC when it is unset or unknown.
+func (u User) Location() *time.Location {
+	if u.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
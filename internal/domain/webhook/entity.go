@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"slices"
+	"time"
+)
+
+// Events a subscription can listen to.
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskStatusChanged = "task.status_changed"
+	EventTaskDeleted       = "task.deleted"
+	EventTaskRestored      = "task.restored"
+	EventTaskUpdated       = "task.updated"
+)
+
+// Events lists every event a subscription can listen to.
+var Events = []string{EventTaskCreated, EventTaskStatusChanged, EventTaskDeleted, EventTaskRestored, EventTaskUpdated}
+
+func ValidEvent(e string) bool {
+	return slices.Contains(Events, e)
+}
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Subscription is an endpoint a user registered to receive events.
+// Failures counts failed attempts since the last successful one; an
+// endpoint that keeps failing is disabled.
+type Subscription struct {
+	ID         int        `json:"id" gorm:"primaryKey"`
+	UserID     int        `json:"user_id" gorm:"not null;index"`
+	URL        string     `json:"url" example:"https://example.com/hooks/taskflow" gorm:"size:2048;not null"`
+	Secret     string     `json:"-" gorm:"size:100;not null"`
+	Events     []string   `json:"events" gorm:"serializer:json;type:text"`
+	Active     bool       `json:"active" gorm:"not null"`
+	Failures   int        `json:"failures" gorm:"not null;default:0"`
+	DisabledAt *time.Time `json:"disabled_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Subscribed reports whether the subscription listens to event.
+func (s Subscription) Subscribed(event string) bool {
+	return slices.Contains(s.Events, event)
+}
+
+// Delivery is one event sent, or to be sent, to one subscription. It is
+// the delivery log entry: the outcome of the latest attempt is kept.
+type Delivery struct {
+	ID             int    `json:"id" gorm:"primaryKey"`
+	SubscriptionID int    `json:"webhook_id" gorm:"not null;index:idx_webhook_deliveries_sub_created,priority:1"`
+	EventID        string `json:"event_id" gorm:"size:32;not null;index"`
+	Event          string `json:"event" example:"task.created" gorm:"size:50;not null"`
+	Payload        string `json:"-" gorm:"type:text;not null"`
+	Status         string `json:"status" example:"succeeded" gorm:"size:20;not null"`
+	Attempts       int    `json:"attempts" gorm:"not null;default:0"`
+	ResponseStatus int    `json:"response_status" example:"200"`
+	Error          string `json:"error" gorm:"type:text"`
+	DurationMS     int64  `json:"duration_ms" example:"84"`
+	// RedeliveryOf points at the delivery this one repeats.
+	RedeliveryOf  *int       `json:"redelivery_of"`
+	LastAttemptAt *time.Time `json:"last_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"index:idx_webhook_deliveries_sub_created,priority:2"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
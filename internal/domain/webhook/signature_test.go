@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	now := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	body := []byte(`{"event":"task.created"}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	sig := Sign("whsec_test", now.Unix(), body)
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, sig)
+	assert.NoError(t, Verify("whsec_test", ts, sig, body, now.Add(time.Minute), 5*time.Minute))
+
+	assert.ErrorIs(t, Verify("other", ts, sig, body, now, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", ts, sig, []byte(`{}`), now, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", "1757332801", sig, body, now, 5*time.Minute), ErrInvalidSignature, "the timestamp is signed")
+	assert.ErrorIs(t, Verify("whsec_test", "soon", sig, body, now, 5*time.Minute), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("whsec_test", ts, sig, body, now.Add(time.Hour), 5*time.Minute), ErrStaleTimestamp)
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type CreateAccessTokenRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"iPhone Reminders"`
+	// ExpiresAt is optional; tokens without it are valid until deleted.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-09-01T00:00:00Z"`
+}
+
+type AccessTokenResponse struct {
+	ID   int    `json:"id" example:"1"`
+	Name string `json:"name" example:"iPhone Reminders"`
+	// Prefix is the start of the token, to tell tokens apart.
+	Prefix     string     `json:"prefix" example:"tfp_3f9a1c"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateAccessTokenResponse carries the token itself, which is only shown
+// this once.
+type CreateAccessTokenResponse struct {
+	AccessTokenResponse
+	Token string `json:"token" example:"tfp_3f9a1c0b7e2d4a6f8c1e3b5d7f9a2c4e6b8d0f1a"`
+}
+
+type ListAccessTokensResponse struct {
+	Tokens []AccessTokenResponse `json:"tokens"`
+}
+
+type DeleteAccessTokenResponse struct {
+	Message string `json:"message" example:"Access token deleted successfully"`
+}
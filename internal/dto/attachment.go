@@ -0,0 +1,22 @@
+package dto
+
+import "time"
+
+type AttachmentResponse struct {
+	ID          int       `json:"id" example:"1"`
+	TaskID      int       `json:"task_id" example:"1"`
+	FileName    string    `json:"file_name" example:"spec.pdf"`
+	ContentType string    `json:"content_type" example:"application/pdf"`
+	Size        int64     `json:"size" example:"52341"`
+	DownloadURL string    `json:"download_url" example:"/api/attachments/1/download?expires=1735689600&signature=5d41402abc4b2a76b9719d911017c592"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ListAttachmentsResponse struct {
+	Attachments []AttachmentResponse `json:"attachments"`
+}
+
+type DeleteAttachmentResponse struct {
+	Message string `json:"message" example:"Attachment deleted successfully"`
+}
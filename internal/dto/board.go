@@ -0,0 +1,20 @@
+package dto
+
+type BoardColumnResponse struct {
+	Status   string            `json:"status" example:"in-progress"`
+	WIPLimit int               `json:"wip_limit,omitempty" example:"3"`
+	Count    int64             `json:"count" example:"2"`
+	Tasks    []GetTaskResponse `json:"tasks"`
+	Page     int               `json:"page" example:"1"`
+	Limit    int               `json:"limit" example:"20"`
+}
+
+type BoardResponse struct {
+	ProjectID *int                  `json:"project_id,omitempty" example:"2"`
+	Columns   []BoardColumnResponse `json:"columns"`
+}
+
+type SetWIPLimitRequest struct {
+	ProjectID *int `json:"project_id,omitempty" example:"2"`
+	WIPLimit  int  `json:"wip_limit" binding:"min=0,max=1000" example:"3"`
+}
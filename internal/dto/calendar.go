@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// CreateCalendarFeedRequest creates a feed. Component defaults to VEVENT.
+type CreateCalendarFeedRequest struct {
+	Name             string `json:"name" binding:"required,max=100" example:"Work"`
+	ProjectID        *int   `json:"project_id,omitempty" binding:"omitempty,min=1" example:"2"`
+	Component        string `json:"component,omitempty" binding:"omitempty,oneof=VEVENT VTODO" example:"VEVENT"`
+	IncludeCompleted bool   `json:"include_completed" example:"false"`
+}
+
+type CalendarFeedResponse struct {
+	ID               int    `json:"id" example:"1"`
+	Name             string `json:"name" example:"Work"`
+	ProjectID        *int   `json:"project_id,omitempty" example:"2"`
+	Component        string `json:"component" example:"VEVENT"`
+	IncludeCompleted bool   `json:"include_completed" example:"false"`
+	// URL serves the feed without authentication; keep it secret.
+	URL       string    `json:"url" example:"/calendar/9f86d081884c7d659a2feaa0c55ad015.ics"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ListCalendarFeedsResponse struct {
+	Feeds []CalendarFeedResponse `json:"feeds"`
+}
+
+type DeleteCalendarFeedResponse struct {
+	Message string `json:"message" example:"Calendar feed deleted successfully"`
+}
+
+// CalendarImportSkipped is a VTODO that was not imported.
+type CalendarImportSkipped struct {
+	UID     string `json:"uid,omitempty" example:"040000008200E00074C5B7101A82E008"`
+	Summary string `json:"summary" example:"Quarterly report"`
+	Error   string `json:"error" example:"task is cancelled"`
+}
+
+type CalendarImportResponse struct {
+	Imported int                     `json:"imported" example:"12"`
+	Tasks    []GetTaskResponse       `json:"tasks"`
+	Skipped  []CalendarImportSkipped `json:"skipped"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000" example:"Looks good, @jane@example.com can you review?"`
+}
+
+type UpdateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000" example:"Updated: ready for review"`
+}
+
+type CommentResponse struct {
+	ID        int       `json:"id" example:"1"`
+	TaskID    int       `json:"task_id" example:"1"`
+	UserID    int       `json:"user_id" example:"1"`
+	Body      string    `json:"body" example:"Looks good, @jane@example.com can you review?"`
+	BodyHTML  string    `json:"body_html" example:"<p>Looks good, <span class=\"mention\">@jane@example.com</span> can you review?</p>"`
+	Mentions  []int     `json:"mentions" example:"2"`
+	Edited    bool      `json:"edited" example:"false"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListCommentsResponse struct {
+	Comments []CommentResponse `json:"comments"`
+	Page     int               `json:"page" example:"1"`
+	Limit    int               `json:"limit" example:"20"`
+	Total    int64             `json:"total" example:"42"`
+}
+
+type DeleteCommentResponse struct {
+	Message string `json:"message" example:"Comment deleted successfully"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+type FilterCriteria struct {
+	Status      string     `json:"status,omitempty" binding:"omitempty,oneof=pending in-progress completed" example:"pending"`
+	CreatedFrom *time.Time `json:"created_from,omitempty" example:"2025-09-01T00:00:00Z"`
+	CreatedTo   *time.Time `json:"created_to,omitempty" example:"2025-10-01T00:00:00Z"`
+	UpdatedFrom *time.Time `json:"updated_from,omitempty"`
+	UpdatedTo   *time.Time `json:"updated_to,omitempty"`
+	Text        string     `json:"text,omitempty" binding:"max=200" example:"report"`
+	Query       string     `json:"query,omitempty" binding:"max=500" example:"tag:work AND priority>=high"`
+}
+
+type CreateFilterRequest struct {
+	Name     string         `json:"name" binding:"required,max=100" example:"Work this week"`
+	Criteria FilterCriteria `json:"criteria"`
+	SortBy   string         `json:"sort_by,omitempty" binding:"omitempty,oneof=created_at updated_at due_at priority task position" example:"due_at"`
+	SortDesc bool           `json:"sort_desc" example:"false"`
+}
+
+type UpdateFilterRequest struct {
+	Name     string         `json:"name" binding:"required,max=100" example:"Work this week"`
+	Criteria FilterCriteria `json:"criteria"`
+	SortBy   string         `json:"sort_by,omitempty" binding:"omitempty,oneof=created_at updated_at due_at priority task position" example:"due_at"`
+	SortDesc bool           `json:"sort_desc" example:"false"`
+}
+
+type FilterResponse struct {
+	ID        int            `json:"id" example:"1"`
+	Name      string         `json:"name" example:"Work this week"`
+	Builtin   bool           `json:"builtin" example:"false"`
+	Criteria  FilterCriteria `json:"criteria"`
+	SortBy    string         `json:"sort_by" example:"due_at"`
+	SortDesc  bool           `json:"sort_desc" example:"false"`
+	Count     int64          `json:"count" example:"4"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type ListFiltersResponse struct {
+	Filters []FilterResponse `json:"filters"`
+}
+
+type DeleteFilterResponse struct {
+	Message string `json:"message" example:"Filter deleted successfully"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+import "time"
+
+// ImportJobError is a task of an imported file that could not be
+// imported.
+type ImportJobError struct {
+	// ID is the task's ID in the file.
+	ID      string `json:"id" example:"2995104339"`
+	Name    string `json:"name" example:"Buy milk"`
+	Message string `json:"message" example:"task name cannot be empty"`
+}
+
+type ImportJobResponse struct {
+	ID       int    `json:"id" example:"1"`
+	Source   string `json:"source" example:"todoist"`
+	FileName string `json:"file_name" example:"todoist-backup.json"`
+	// Status is pending, running, succeeded or failed.
+	Status string `json:"status" example:"running"`
+	Run    int    `json:"run" example:"1"`
+	// Total is the number of tasks in the file.
+	Total     int `json:"total" example:"240"`
+	Processed int `json:"processed" example:"100"`
+	// Progress is the share of processed tasks, in percent.
+	Progress int `json:"progress" example:"41"`
+	Imported int `json:"imported" example:"96"`
+	// Skipped counts tasks that were imported before.
+	Skipped    int              `json:"skipped" example:"3"`
+	Failed     int              `json:"failed" example:"1"`
+	Errors     []ImportJobError `json:"errors"`
+	Error      string           `json:"error,omitempty"`
+	StartedAt  *time.Time       `json:"started_at,omitempty"`
+	FinishedAt *time.Time       `json:"finished_at,omitempty"`
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+type ListImportJobsResponse struct {
+	Jobs []ImportJobResponse `json:"jobs"`
+}
+
+type DeleteImportJobResponse struct {
+	Message string `json:"message" example:"Import deleted successfully"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+type CreateInboundAddressRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Zapier"`
+}
+
+type InboundAddressResponse struct {
+	ID   int    `json:"id" example:"1"`
+	Name string `json:"name" example:"Zapier"`
+	// URL accepts JSON or form posts without authentication; keep it secret.
+	URL string `json:"url" example:"/inbound/9f86d081884c7d659a2feaa0c55ad015"`
+	// Email is only set when the server receives mail.
+	Email      string     `json:"email,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015@in.example.com"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type ListInboundAddressesResponse struct {
+	Addresses []InboundAddressResponse `json:"addresses"`
+}
+
+type DeleteInboundAddressResponse struct {
+	Message string `json:"message" example:"Inbound address deleted successfully"`
+}
+
+// InboundCaptureRequest is what a capture URL accepts, as JSON or as a
+// form. Without a title, the first line of text is the title and the rest
+// the description. In forms, tags may also be given comma-separated.
+type InboundCaptureRequest struct {
+	Title       string     `json:"title" form:"title" binding:"max=1000" example:"Call the plumber about the leak"`
+	Text        string     `json:"text" form:"text" binding:"max=10000" example:"Call the plumber\nThe kitchen tap is leaking again"`
+	Description string     `json:"description" form:"description" binding:"max=10000" example:"The kitchen tap is leaking again"`
+	DueAt       *time.Time `json:"due_at,omitempty" form:"due_at" example:"2025-09-01T17:00:00Z"`
+	Priority    string     `json:"priority,omitempty" form:"priority" binding:"omitempty,oneof=none low medium high urgent" example:"high"`
+	Tags        []string   `json:"tags,omitempty" form:"tags" binding:"max=20" example:"home"`
+}
+
+type InboundCaptureResponse struct {
+	Task        GetTaskResponse      `json:"task"`
+	Attachments []AttachmentResponse `json:"attachments"`
+	// Skipped lists the files that could not be attached.
+	Skipped []string `json:"skipped,omitempty" example:"setup.exe"`
+}
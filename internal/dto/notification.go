@@ -0,0 +1,42 @@
+package dto
+
+import "time"
+
+type NotificationResponse struct {
+	ID        int        `json:"id" example:"1"`
+	Type      string     `json:"type" example:"subtasks_done"`
+	Title     string     `json:"title" example:"Subtasks done: Write report"`
+	Body      string     `json:"body" example:"On \"Write report\": can you review?"`
+	TaskID    *int       `json:"task_id" example:"4"`
+	Read      bool       `json:"read" example:"false"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Unread        int64                  `json:"unread" example:"3"`
+	Page          int                    `json:"page" example:"1"`
+	Limit         int                    `json:"limit" example:"20"`
+	Total         int64                  `json:"total" example:"42"`
+}
+
+type MarkReadResponse struct {
+	Marked int64 `json:"marked" example:"1"`
+	Unread int64 `json:"unread" example:"2"`
+}
+
+// NotificationPreference sets how one notification type is delivered:
+// in_app, email, both or off.
+type NotificationPreference struct {
+	Type     string `json:"type" binding:"required" example:"subtasks_done"`
+	Delivery string `json:"delivery" binding:"required" example:"both"`
+}
+
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreference `json:"preferences" binding:"required,min=1,dive"`
+}
+
+type NotificationPreferencesResponse struct {
+	Preferences []NotificationPreference `json:"preferences"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+type CreateProjectRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Home renovation"`
+}
+
+type UpdateProjectRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"Kitchen renovation"`
+}
+
+type ProjectResponse struct {
+	ID        int       `json:"id" example:"1"`
+	Name      string    `json:"name" example:"Home renovation"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListProjectsResponse struct {
+	Projects []ProjectResponse `json:"projects"`
+}
+
+type DeleteProjectResponse struct {
+	Message string `json:"message" example:"Project deleted successfully"`
+}
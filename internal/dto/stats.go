@@ -0,0 +1,34 @@
+package dto
+
+type StatsPeriod struct {
+	// Start is the period's first day; weeks start on Monday.
+	Start     string `json:"start" example:"2025-09-01"`
+	Created   int64  `json:"created" example:"4"`
+	Completed int64  `json:"completed" example:"3"`
+}
+
+type StatsStreaks struct {
+	// Current counts the days with a completion up to the last day of the
+	// range, or up to yesterday while today has none yet.
+	Current int `json:"current" example:"3"`
+	Longest int `json:"longest" example:"5"`
+}
+
+type StatsOverdue struct {
+	Open          int64 `json:"open" example:"2"`
+	CompletedLate int64 `json:"completed_late" example:"1"`
+}
+
+type StatsResponse struct {
+	From                 string           `json:"from" example:"2025-09-01"`
+	To                   string           `json:"to" example:"2025-09-30"`
+	TimeZone             string           `json:"time_zone" example:"Europe/Berlin"`
+	Interval             string           `json:"interval" example:"day"`
+	Periods              []StatsPeriod    `json:"periods"`
+	Created              int64            `json:"created" example:"42"`
+	Completed            int64            `json:"completed" example:"37"`
+	AvgCompletionSeconds *int64           `json:"avg_completion_seconds,omitempty" example:"86400"`
+	Streaks              StatsStreaks     `json:"streaks"`
+	Overdue              StatsOverdue     `json:"overdue"`
+	Statuses             map[string]int64 `json:"statuses"`
+}
@@ -1,13 +1,33 @@
 package dto
 
+import "time"
+
 type CreateTaskRequest struct {
-	Task string `json:"task" binding:"required,max=20" example:"Buy milk"`
+	Task        string     `json:"task" binding:"required,max=20" example:"Buy milk"`
+	Description string     `json:"description,omitempty" binding:"max=2000" example:"2 litres, semi-skimmed"`
+	Priority    string     `json:"priority,omitempty" binding:"omitempty,oneof=none low medium high urgent" example:"high"`
+	DueAt       *time.Time `json:"due_at,omitempty" example:"2025-09-01T17:00:00Z"`
+	Tags        []string   `json:"tags,omitempty" binding:"max=20,dive,max=50" example:"work,errands"`
+	// EstimateMinutes is the expected effort, compared with tracked time.
+	EstimateMinutes *int `json:"estimate_minutes,omitempty" binding:"omitempty,min=1,max=100000" example:"90"`
 }
 
 type GetTaskResponse struct {
-	ID     int    `json:"id" example:"1"`
-	Task   string `json:"task" example:"Buy milk"`
-	Status string `json:"status" example:"pending"`
+	ID              int        `json:"id" example:"1"`
+	Task            string     `json:"task" example:"Buy milk"`
+	Description     string     `json:"description,omitempty" example:"2 litres, semi-skimmed"`
+	Status          string     `json:"status" example:"pending"`
+	Priority        string     `json:"priority,omitempty" example:"high"`
+	DueAt           *time.Time `json:"due_at,omitempty" example:"2025-09-01T17:00:00Z"`
+	Tags            []string   `json:"tags,omitempty" example:"work,errands"`
+	ProjectID       *int       `json:"project_id,omitempty" example:"2"`
+	ParentID        *int       `json:"parent_id,omitempty" example:"4"`
+	Position        string     `json:"position,omitempty" example:"i"`
+	EstimateMinutes *int       `json:"estimate_minutes,omitempty" example:"90"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty" example:"2025-09-02T16:20:00Z"`
+	Recurrence      string     `json:"recurrence,omitempty" example:"FREQ=MONTHLY"`
+	CreatedAt       time.Time  `json:"created_at" example:"2025-08-30T09:15:00Z"`
+	UpdatedAt       time.Time  `json:"updated_at" example:"2025-09-02T16:20:00Z"`
 }
 
 type ListTasksResponse struct {
@@ -18,10 +38,105 @@ type UpdateStatusRequest struct {
 	Status string `json:"status" binding:"required,oneof=pending in-progress completed" example:"completed"`
 }
 
-type UpdateStatusResponse struct {
-	Message string `json:"message" example:"status updated"`
+// MoveTaskRequest places a task between two neighbours of its list. AfterID
+// is the task that should come right before it and BeforeID the one right
+// after; omit both to move the task to the end of its list. Status also
+// moves the task to another board column in the same call.
+type MoveTaskRequest struct {
+	AfterID  int    `json:"after_id,omitempty" binding:"omitempty,min=1" example:"3"`
+	BeforeID int    `json:"before_id,omitempty" binding:"omitempty,min=1" example:"8"`
+	Status   string `json:"status,omitempty" binding:"omitempty,oneof=pending in-progress completed" example:"in-progress"`
 }
 
 type DeleteTaskResponse struct {
 	Message string `json:"message" example:"Task deleted successfully"`
 }
+
+type EmptyTrashResponse struct {
+	// Deleted is the number of tasks deleted for good.
+	Deleted int `json:"deleted" example:"3"`
+}
+
+type SearchTaskResult struct {
+	Task      GetTaskResponse `json:"task"`
+	Score     float64         `json:"score" example:"3.42"`
+	Snippet   string          `json:"snippet" example:"Buy <mark>milk</mark>"`
+	MatchedIn []string        `json:"matched_in" example:"task,description"`
+}
+
+type SearchTasksResponse struct {
+	Query   string             `json:"query" example:"milk"`
+	Results []SearchTaskResult `json:"results"`
+	Page    int                `json:"page" example:"1"`
+	Limit   int                `json:"limit" example:"20"`
+	Total   int64              `json:"total" example:"3"`
+}
+
+type BulkTaskRequest struct {
+	Action    string   `json:"action" binding:"required,oneof=update_status delete tag move restore" example:"update_status"`
+	IDs       []int    `json:"ids,omitempty" binding:"max=500,dive,min=1" example:"1,2,3"`
+	FilterID  int      `json:"filter_id,omitempty" binding:"omitempty,min=1" example:"4"`
+	Query     string   `json:"query,omitempty" binding:"max=500" example:"status:completed AND updated<-30d"`
+	Status    string   `json:"status,omitempty" binding:"omitempty,oneof=pending in-progress completed" example:"completed"`
+	Tags      []string `json:"tags,omitempty" binding:"max=20,dive,max=50" example:"cleanup"`
+	ProjectID *int     `json:"project_id,omitempty" example:"2"`
+}
+
+type BulkItemResult struct {
+	ID     int    `json:"id" example:"1"`
+	Result string `json:"result" example:"success" enums:"success,not_found,forbidden"`
+}
+
+type BulkTaskResponse struct {
+	Action    string           `json:"action" example:"update_status"`
+	Results   []BulkItemResult `json:"results"`
+	Succeeded int              `json:"succeeded" example:"2"`
+	Failed    int              `json:"failed" example:"1"`
+}
+
+type QuickAddRequest struct {
+	Text string `json:"text" binding:"required,max=500" example:"Pay rent tomorrow 9am #finance !high every month"`
+	// DryRun only reports how the text would be read, without creating a task.
+	DryRun bool `json:"dry_run,omitempty" example:"false"`
+}
+
+// QuickAddToken is a phrase recognised in the text and what it was read as.
+type QuickAddToken struct {
+	Text  string `json:"text" example:"tomorrow"`
+	Kind  string `json:"kind" example:"date" enums:"tag,priority,date,time,recurrence"`
+	Value string `json:"value" example:"2025-09-04"`
+}
+
+type QuickAddParsed struct {
+	Task       string     `json:"task" example:"Pay rent"`
+	DueAt      *time.Time `json:"due_at,omitempty" example:"2025-09-04T09:00:00+02:00"`
+	Tags       []string   `json:"tags,omitempty" example:"finance"`
+	Priority   string     `json:"priority,omitempty" example:"high"`
+	Recurrence string     `json:"recurrence,omitempty" example:"FREQ=MONTHLY"`
+}
+
+type QuickAddResponse struct {
+	// Task is the created task; it is left out on a dry run.
+	Task     *GetTaskResponse `json:"task,omitempty"`
+	Parsed   QuickAddParsed   `json:"parsed"`
+	Tokens   []QuickAddToken  `json:"tokens"`
+	TimeZone string           `json:"time_zone" example:"Europe/Berlin"`
+}
+
+// ImportTaskRequest is a task brought in from a file or another tool. On
+// top of what CreateTaskRequest takes, it keeps the task's state there.
+type ImportTaskRequest struct {
+	CreateTaskRequest
+	Status      string     `json:"status,omitempty" binding:"omitempty,oneof=pending in-progress completed" example:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" example:"2025-09-02T16:20:00Z"`
+	Recurrence  string     `json:"recurrence,omitempty" binding:"max=255" example:"FREQ=MONTHLY"`
+	// ProjectID must be a project of the importing user; Import does not
+	// check it.
+	ProjectID *int `json:"project_id,omitempty" example:"2"`
+	// ParentID must be a task of the importing user; Import does not check
+	// it.
+	ParentID *int `json:"-"`
+	// ExternalID identifies the task in the tool it comes from. A user
+	// can have only one task with a given ExternalID.
+	ExternalID string `json:"-"`
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "time"
+
+// TemplateSubtask is one step of a template. Title, description and tags may
+// contain {{name}} variables; DueOffset is relative to the base date given
+// when the template is instantiated, e.g. "3d", "-1w" or "2d17h".
+type TemplateSubtask struct {
+	Title       string   `json:"title" binding:"required,max=200" example:"Tag {{version}}"`
+	Description string   `json:"description,omitempty" binding:"max=2000" example:"git tag v{{version}}"`
+	Tags        []string `json:"tags,omitempty" binding:"max=20,dive,max=50" example:"git"`
+	DueOffset   string   `json:"due_offset,omitempty" binding:"max=20" example:"3d17h"`
+}
+
+// TemplateRequest creates a template or replaces all of its fields and subtasks.
+type TemplateRequest struct {
+	Name        string            `json:"name" binding:"required,max=100" example:"Release prep"`
+	Title       string            `json:"title" binding:"required,max=200" example:"Release {{version}}"`
+	Description string            `json:"description,omitempty" binding:"max=2000" example:"Everything for shipping {{version}}"`
+	Tags        []string          `json:"tags,omitempty" binding:"max=20,dive,max=50" example:"release"`
+	DueOffset   string            `json:"due_offset,omitempty" binding:"max=20" example:"5d"`
+	Subtasks    []TemplateSubtask `json:"subtasks,omitempty" binding:"max=100,dive"`
+}
+
+type TemplateResponse struct {
+	ID          int               `json:"id" example:"1"`
+	Name        string            `json:"name" example:"Release prep"`
+	Title       string            `json:"title" example:"Release {{version}}"`
+	Description string            `json:"description,omitempty" example:"Everything for shipping {{version}}"`
+	Tags        []string          `json:"tags,omitempty" example:"release"`
+	DueOffset   string            `json:"due_offset,omitempty" example:"5d"`
+	Subtasks    []TemplateSubtask `json:"subtasks"`
+	// Variables lists the {{name}} variables used anywhere in the template.
+	Variables []string  `json:"variables" example:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListTemplatesResponse struct {
+	Templates []TemplateResponse `json:"templates"`
+}
+
+type DeleteTemplateResponse struct {
+	Message string `json:"message" example:"Template deleted successfully"`
+}
+
+// InstantiateTemplateRequest creates tasks from a template. BaseDate is a
+// YYYY-MM-DD day in the user's time zone and defaults to today.
+type InstantiateTemplateRequest struct {
+	BaseDate  string            `json:"base_date,omitempty" example:"2025-09-08"`
+	Variables map[string]string `json:"variables,omitempty" binding:"max=50,dive,keys,max=50,endkeys,max=200"`
+	ProjectID *int              `json:"project_id,omitempty" example:"2"`
+}
+
+type InstantiateTemplateResponse struct {
+	Task     GetTaskResponse   `json:"task"`
+	Subtasks []GetTaskResponse `json:"subtasks"`
+}
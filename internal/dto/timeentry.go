@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+type TimeEntryResponse struct {
+	ID              int        `json:"id" example:"7"`
+	TaskID          int        `json:"task_id" example:"1"`
+	StartedAt       time.Time  `json:"started_at" example:"2025-09-01T09:00:00Z"`
+	EndedAt         *time.Time `json:"ended_at,omitempty" example:"2025-09-01T10:30:00Z"`
+	DurationSeconds int64      `json:"duration_seconds" example:"5400"`
+	Running         bool       `json:"running" example:"false"`
+	Note            string     `json:"note,omitempty" example:"Client call"`
+}
+
+type StartTimerRequest struct {
+	Note string `json:"note,omitempty" binding:"max=500" example:"Client call"`
+}
+
+// CreateTimeEntryRequest records time by hand. Exactly one of EndedAt and
+// DurationMinutes must be set.
+type CreateTimeEntryRequest struct {
+	StartedAt       time.Time  `json:"started_at" binding:"required" example:"2025-09-01T09:00:00Z"`
+	EndedAt         *time.Time `json:"ended_at,omitempty" example:"2025-09-01T10:30:00Z"`
+	DurationMinutes int        `json:"duration_minutes,omitempty" binding:"omitempty,min=1,max=1440" example:"90"`
+	Note            string     `json:"note,omitempty" binding:"max=500" example:"Client call"`
+}
+
+type TaskTimeResponse struct {
+	TaskID          int   `json:"task_id" example:"1"`
+	EstimateMinutes *int  `json:"estimate_minutes,omitempty" example:"120"`
+	TotalSeconds    int64 `json:"total_seconds" example:"5400"`
+	// RemainingSeconds is negative once the estimate is exceeded.
+	RemainingSeconds *int64              `json:"remaining_seconds,omitempty" example:"1800"`
+	Entries          []TimeEntryResponse `json:"entries"`
+}
+
+type SetEstimateRequest struct {
+	// EstimateMinutes clears the estimate when null.
+	EstimateMinutes *int `json:"estimate_minutes" binding:"omitempty,min=1,max=100000" example:"120"`
+}
+
+type TimeReportRow struct {
+	Key     string  `json:"key" example:"2025-09-01"`
+	Label   string  `json:"label" example:"2025-09-01"`
+	Seconds int64   `json:"seconds" example:"5400"`
+	Hours   float64 `json:"hours" example:"1.5"`
+}
+
+type TimeReportResponse struct {
+	From         string          `json:"from" example:"2025-09-01"`
+	To           string          `json:"to" example:"2025-09-07"`
+	GroupBy      string          `json:"group_by" example:"day"`
+	Rows         []TimeReportRow `json:"rows"`
+	TotalSeconds int64           `json:"total_seconds" example:"5400"`
+	TotalHours   float64         `json:"total_hours" example:"1.5"`
+}
+
+type DeleteTimeEntryResponse struct {
+	Message string `json:"message" example:"Time entry deleted successfully"`
+}
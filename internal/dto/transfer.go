@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// ExportedTask is a task as written by an export. Project is the name of
+// its project, so the file reads without the project list.
+type ExportedTask struct {
+	GetTaskResponse
+	Project   string    `json:"project,omitempty" example:"Home renovation"`
+	CreatedAt time.Time `json:"created_at" example:"2025-08-27T10:35:16Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2025-08-28T09:12:03Z"`
+}
+
+// TaskImportOptions controls how POST /tasks/import reads a file.
+type TaskImportOptions struct {
+	// Format is csv, json or ndjson.
+	Format string
+	// Mapping maps columns of the file, or keys of JSON objects, to task
+	// fields. Columns it leaves out are matched by name.
+	Mapping map[string]string
+	// DryRun only checks the file and previews the tasks.
+	DryRun bool
+}
+
+// TaskImportRowError is a row of an import that doesn't make a valid task.
+type TaskImportRowError struct {
+	// Row is the line in a CSV file, counting the header, the line in an
+	// NDJSON file, or the position in a JSON array, from 1.
+	Row int `json:"row" example:"3"`
+	// Column is the column of the file, or key of the JSON object, the
+	// error is about.
+	Column  string `json:"column,omitempty" example:"Due"`
+	Message string `json:"message" example:"invalid date \"next week\""`
+}
+
+type TaskImportResponse struct {
+	DryRun bool `json:"dry_run" example:"false"`
+	// Mapping is the task field each column was read as; ignored columns
+	// are left out.
+	Mapping  map[string]string `json:"mapping"`
+	Rows     int               `json:"rows" example:"42"`
+	Imported int               `json:"imported" example:"42"`
+	// Preview lists the first tasks as they would be imported, on a dry
+	// run.
+	Preview []ImportTaskRequest  `json:"preview,omitempty"`
+	Tasks   []GetTaskResponse    `json:"tasks"`
+	Errors  []TaskImportRowError `json:"errors"`
+}
@@ -38,3 +38,12 @@ type UpdatePasswordRequest struct {
 type UpdatePasswordResponse struct {
 	Message string `json:"message" example:"Password updated successfully"`
 }
+
+type UpdateTimeZoneRequest struct {
+	ID       int    `json:"-"`
+	TimeZone string `json:"time_zone" binding:"required,max=64" example:"Europe/Berlin"`
+}
+
+type UpdateTimeZoneResponse struct {
+	TimeZone string `json:"time_zone" example:"Europe/Berlin"`
+}
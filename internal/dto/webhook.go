@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookRequest registers or changes an endpoint. Active is ignored on
+// create; set it to true to re-enable an endpoint that was disabled.
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url,max=2048" example:"https://example.com/hooks/taskflow"`
+	Events []string `json:"events" binding:"required,min=1,max=20" example:"task.created,task.status_changed"`
+	Active *bool    `json:"active,omitempty" example:"true"`
+}
+
+type WebhookResponse struct {
+	ID     int      `json:"id" example:"1"`
+	URL    string   `json:"url" example:"https://example.com/hooks/taskflow"`
+	Events []string `json:"events" example:"task.created,task.status_changed"`
+	Active bool     `json:"active" example:"true"`
+	// Failures counts failed attempts since the last successful one.
+	Failures   int        `json:"failures" example:"0"`
+	DisabledAt *time.Time `json:"disabled_at"`
+	// Secret is only returned when the webhook is created.
+	Secret    string    `json:"secret,omitempty" example:"whsec_4f0c9d2e8a7b6c5d4e3f2a1b0c9d8e7f"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+type DeleteWebhookResponse struct {
+	Message string `json:"message" example:"Webhook deleted successfully"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int             `json:"id" example:"7"`
+	WebhookID      int             `json:"webhook_id" example:"1"`
+	EventID        string          `json:"event_id" example:"9b2f0c4d1e8a7b6c5d4e3f2a1b0c9d8e"`
+	Event          string          `json:"event" example:"task.created"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status" example:"succeeded"`
+	Attempts       int             `json:"attempts" example:"1"`
+	ResponseStatus int             `json:"response_status" example:"200"`
+	Error          string          `json:"error" example:""`
+	DurationMS     int64           `json:"duration_ms" example:"84"`
+	RedeliveryOf   *int            `json:"redelivery_of"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+	Page       int                       `json:"page" example:"1"`
+	Limit      int                       `json:"limit" example:"20"`
+	Total      int64                     `json:"total" example:"42"`
+}
+
+// TaskEventData is the data of task.* webhook events. PreviousStatus is
+// set for task.status_changed.
+type TaskEventData struct {
+	Task           GetTaskResponse `json:"task"`
+	PreviousStatus string          `json:"previous_status,omitempty" example:"pending"`
+}
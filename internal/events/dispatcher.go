@@ -0,0 +1,221 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"taskflow/internal/scheduler"
+	"taskflow/pkg"
+)
+
+// Scheduler job kinds.
+const (
+	// KindDeliver hands one event to one consumer.
+	KindDeliver = "events.deliver"
+	// KindPurge deletes old dispatched events from the outbox.
+	KindPurge = "events.purge"
+)
+
+// MaxAttempts is how often a consumer is called with an event before the
+// delivery is given up, about three hours with the scheduler's backoff.
+const MaxAttempts = 10
+
+// Handler consumes one event. Returning an error retries the event for
+// this consumer only.
+type Handler func(ctx context.Context, e *Envelope) error
+
+// Listener sees events as this process relays them.
+type Listener func(e *Envelope)
+
+// Publisher forwards events to a system outside the process, such as a
+// message broker.
+type Publisher interface {
+	Publish(ctx context.Context, e *Envelope) error
+}
+
+type Config struct {
+	WorkerID     string
+	PollInterval time.Duration
+	// Lease is how long a replica holds the events it is relaying.
+	Lease     time.Duration
+	BatchSize int
+	// Retention is how long dispatched events stay in the outbox.
+	Retention time.Duration
+}
+
+func LoadConfigFromEnv() Config {
+	host, _ := os.Hostname()
+	return Config{
+		WorkerID:     pkg.GetEnv("EVENTS_WORKER_ID", fmt.Sprintf("%s-%d", host, os.Getpid())),
+		PollInterval: pkg.DurationEnv("EVENTS_POLL_INTERVAL", time.Second),
+		Lease:        pkg.DurationEnv("EVENTS_LEASE", 30*time.Second),
+		BatchSize:    pkg.IntEnv("EVENTS_BATCH_SIZE", 100),
+		Retention:    pkg.DurationEnv("EVENTS_RETENTION", 7*24*time.Hour),
+	}
+}
+
+type consumer struct {
+	name    string
+	types   map[string]bool // nil means every type
+	handler Handler
+}
+
+// delivery is the payload of a KindDeliver job.
+type delivery struct {
+	Consumer string   `json:"consumer"`
+	Event    Envelope `json:"event"`
+}
+
+// Dispatcher relays outbox events to consumers. Each consumer gets its own
+// scheduler job per event, keyed by event ID and consumer name, so a slow
+// or failing consumer is retried on its own and relaying the same event
+// twice, from a retry or from another replica, queues nothing new.
+//
+// Register consumers before calling Start.
+type Dispatcher struct {
+	outbox    *Outbox
+	queue     scheduler.Queue
+	cfg       Config
+	consumers map[string]consumer
+	listeners []Listener
+	now       func() time.Time
+}
+
+func NewDispatcher(outbox *Outbox, queue scheduler.Queue, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		outbox:    outbox,
+		queue:     queue,
+		cfg:       cfg,
+		consumers: map[string]consumer{},
+		now:       time.Now,
+	}
+}
+
+// Subscribe registers an in-process consumer of the given event types, or
+// of every type when none are given. name identifies the consumer in the
+// job queue and must not change between releases.
+func (d *Dispatcher) Subscribe(name string, h Handler, types ...string) {
+	c := consumer{name: name, handler: h}
+	if len(types) > 0 {
+		c.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			c.types[t] = true
+		}
+	}
+	d.consumers[name] = c
+}
+
+// AddPublisher registers an external publisher of every event.
+func (d *Dispatcher) AddPublisher(name string, p Publisher) {
+	d.Subscribe("publisher:"+name, p.Publish)
+}
+
+// Listen registers l for every event relayed by this process. Unlike
+// consumers, listeners are called in-process, at most once and without
+// retries, which suits live updates that are cheap to miss. With several
+// replicas each event reaches the listeners of the one that claimed it.
+func (d *Dispatcher) Listen(l Listener) {
+	d.listeners = append(d.listeners, l)
+}
+
+// Register installs the dispatcher's job handlers on s.
+func (d *Dispatcher) Register(s *scheduler.Scheduler) {
+	s.Handle(KindDeliver, d.Deliver)
+	s.Every(KindPurge, time.Hour, func(ctx context.Context, job *scheduler.Job) error {
+		n, err := d.outbox.Purge(d.now().Add(-d.cfg.Retention))
+		if n > 0 {
+			log.Printf("events: purged %d dispatched events", n)
+		}
+		return err
+	})
+}
+
+// Start relays events until ctx is cancelled.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(d.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			for {
+				n, err := d.RelayOnce()
+				if err != nil {
+					log.Printf("events: %v", err)
+				}
+				// A full batch means more events are probably waiting.
+				if err != nil || n < d.cfg.BatchSize || ctx.Err() != nil {
+					break
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RelayOnce claims one batch of pending events, queues them for their
+// consumers and marks them dispatched. It returns how many events were
+// relayed.
+func (d *Dispatcher) RelayOnce() (int, error) {
+	now := d.now()
+	records, err := d.outbox.Claim(d.cfg.WorkerID, now, d.cfg.Lease, d.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	ids := make([]int64, 0, len(records))
+	relayed := make([]Envelope, 0, len(records))
+	for i := range records {
+		e := records[i].Envelope()
+		for _, c := range d.consumers {
+			if c.types != nil && !c.types[e.Type] {
+				continue
+			}
+			job, err := scheduler.NewJob(KindDeliver, fmt.Sprintf("event:%s:%s", e.ID, c.name), now, delivery{
+				Consumer: c.name,
+				Event:    e,
+			})
+			if err != nil {
+				return 0, err
+			}
+			job.MaxAttempts = MaxAttempts
+			if _, err := d.queue.Enqueue(job); err != nil {
+				// The event stays pending and is relayed again once the
+				// claim runs out; consumers that were queued already are
+				// deduplicated.
+				return 0, err
+			}
+		}
+		ids = append(ids, records[i].ID)
+		relayed = append(relayed, e)
+	}
+	if err := d.outbox.MarkDispatched(ids, now); err != nil {
+		return 0, err
+	}
+	for i := range relayed {
+		for _, l := range d.listeners {
+			l(&relayed[i])
+		}
+	}
+	return len(ids), nil
+}
+
+// Deliver handles KindDeliver jobs. Events for consumers that no longer
+// exist are dropped.
+func (d *Dispatcher) Deliver(ctx context.Context, job *scheduler.Job) error {
+	var p delivery
+	if err := job.Decode(&p); err != nil {
+		return err
+	}
+	c, ok := d.consumers[p.Consumer]
+	if !ok {
+		log.Printf("events: dropping %s %s for unknown consumer %q", p.Event.Type, p.Event.ID, p.Consumer)
+		return nil
+	}
+	return c.handler(ctx, &p.Event)
+}
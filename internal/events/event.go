@@ -0,0 +1,167 @@
+// Package events is the domain event bus.
+//
+// Services record typed events in the outbox_events table inside the same
+// database transaction as the change they describe, so an event exists if
+// and only if the change was committed. A Dispatcher relays recorded events
+// to in-process subscribers and external publishers through the scheduler.
+// Delivery is at-least-once: consumers may see an event more than once and
+// must be idempotent, for example by keying their work on the event ID.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"taskflow/internal/dto"
+)
+
+// Event types.
+const (
+	TypeTaskCreated   = "task.created"
+	TypeStatusChanged = "task.status_changed"
+	TypeTaskDeleted   = "task.deleted"
+	TypeTaskRestored  = "task.restored"
+	TypeTaskMoved     = "task.moved"
+	TypeTaskUpdated   = "task.updated"
+	TypeUserDeleted   = "user.deleted"
+)
+
+// Event is a typed domain event.
+type Event interface {
+	EventType() string
+	// Owner is the user whose data changed.
+	Owner() int
+}
+
+// TaskCreated is recorded when a task is created.
+type TaskCreated struct {
+	UserID int                 `json:"user_id"`
+	Task   dto.GetTaskResponse `json:"task"`
+}
+
+func (TaskCreated) EventType() string { return TypeTaskCreated }
+func (e TaskCreated) Owner() int      { return e.UserID }
+
+// StatusChanged is recorded when a task moves to another status. Task is
+// the task after the change.
+type StatusChanged struct {
+	UserID int                 `json:"user_id"`
+	Task   dto.GetTaskResponse `json:"task"`
+	From   string              `json:"from"`
+}
+
+func (StatusChanged) EventType() string { return TypeStatusChanged }
+func (e StatusChanged) Owner() int      { return e.UserID }
+
+// TaskDeleted is recorded when a task is moved to the trash. Nothing is
+// recorded when the trash is emptied.
+type TaskDeleted struct {
+	UserID int                 `json:"user_id"`
+	Task   dto.GetTaskResponse `json:"task"`
+}
+
+func (TaskDeleted) EventType() string { return TypeTaskDeleted }
+func (e TaskDeleted) Owner() int      { return e.UserID }
+
+// TaskRestored is recorded when a task is restored from the trash.
+type TaskRestored struct {
+	UserID int                 `json:"user_id"`
+	Task   dto.GetTaskResponse `json:"task"`
+}
+
+func (TaskRestored) EventType() string { return TypeTaskRestored }
+func (e TaskRestored) Owner() int      { return e.UserID }
+
+// TaskMoved is recorded when a task is moved on the board or to another
+// list. Task is the task after the move.
+type TaskMoved struct {
+	UserID int                 `json:"user_id"`
+	Task   dto.GetTaskResponse `json:"task"`
+}
+
+func (TaskMoved) EventType() string { return TypeTaskMoved }
+func (e TaskMoved) Owner() int      { return e.UserID }
+
+// TaskUpdated is recorded when tags are added to a task in bulk. Task is
+// the task after the change.
+type TaskUpdated struct {
+	UserID int                 `json:"user_id"`
+	Task   dto.GetTaskResponse `json:"task"`
+}
+
+func (TaskUpdated) EventType() string { return TypeTaskUpdated }
+func (e TaskUpdated) Owner() int      { return e.UserID }
+
+// UserDeleted is recorded when a user account is deleted.
+type UserDeleted struct {
+	UserID int `json:"user_id"`
+}
+
+func (UserDeleted) EventType() string { return TypeUserDeleted }
+func (e UserDeleted) Owner() int      { return e.UserID }
+
+// Record is an event stored in the outbox. DispatchedAt is set once the
+// event has been handed to every consumer's queue.
+type Record struct {
+	ID           int64      `gorm:"primaryKey"`
+	EventID      string     `gorm:"size:32;not null;uniqueIndex"`
+	Type         string     `gorm:"size:50;not null"`
+	UserID       int        `gorm:"not null;index"`
+	Payload      string     `gorm:"type:text"` // JSON
+	OccurredAt   time.Time  `gorm:"not null"`
+	DispatchedAt *time.Time `gorm:"index"`
+	// ClaimedBy is the replica relaying the event until ClaimedUntil.
+	ClaimedBy    string `gorm:"size:100;not null;default:''"`
+	ClaimedUntil *time.Time
+}
+
+func (Record) TableName() string {
+	return "outbox_events"
+}
+
+// NewRecord encodes e for the outbox under a new random event ID.
+func NewRecord(e Event, at time.Time) (*Record, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return &Record{
+		EventID:    hex.EncodeToString(id),
+		Type:       e.EventType(),
+		UserID:     e.Owner(),
+		Payload:    string(data),
+		OccurredAt: at,
+	}, nil
+}
+
+// Envelope is an event as consumers and publishers receive it. ID is the
+// same on every delivery of the event.
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	UserID     int             `json:"user_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Envelope returns the record as delivered to consumers.
+func (r *Record) Envelope() Envelope {
+	return Envelope{
+		ID:         r.EventID,
+		Type:       r.Type,
+		UserID:     r.UserID,
+		OccurredAt: r.OccurredAt,
+		Data:       json.RawMessage(r.Payload),
+	}
+}
+
+// Decode unmarshals the event data into v, typically the typed event.
+func (e *Envelope) Decode(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
@@ -0,0 +1,257 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/webhook"
+	"taskflow/internal/dto"
+	"taskflow/internal/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	// Every connection to :memory: is a separate database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&Record{}, &scheduler.Job{}))
+	return db
+}
+
+var t0 = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+func testConfig() Config {
+	return Config{WorkerID: "a", PollInterval: time.Second, Lease: time.Minute, BatchSize: 10, Retention: 24 * time.Hour}
+}
+
+func created(id int) TaskCreated {
+	return TaskCreated{UserID: 1, Task: dto.GetTaskResponse{ID: id, Task: "Write report", Status: "pending"}}
+}
+
+func TestOutbox_AppendIsTransactional(t *testing.T) {
+	db := setupTestDB(t)
+
+	boom := errors.New("boom")
+	err := db.Transaction(func(tx *gorm.DB) error {
+		require.NoError(t, NewOutbox(tx).Append(created(1)))
+		return boom
+	})
+	require.ErrorIs(t, err, boom)
+
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return NewOutbox(tx).Append(created(2), UserDeleted{UserID: 3})
+	}))
+
+	pending, err := NewOutbox(db).Pending(10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2, "the rolled back event is gone")
+	assert.Equal(t, TypeTaskCreated, pending[0].Type)
+	assert.Equal(t, 1, pending[0].UserID)
+	assert.Len(t, pending[0].EventID, 32)
+	assert.Equal(t, TypeUserDeleted, pending[1].Type)
+	assert.Equal(t, 3, pending[1].UserID)
+
+	e := pending[0].Envelope()
+	var ev TaskCreated
+	require.NoError(t, e.Decode(&ev))
+	assert.Equal(t, created(2), ev)
+}
+
+func TestOutbox_MarkDispatchedAndPurge(t *testing.T) {
+	outbox := NewOutbox(setupTestDB(t))
+	require.NoError(t, outbox.Append(created(1), created(2)))
+
+	pending, err := outbox.Pending(10)
+	require.NoError(t, err)
+	require.NoError(t, outbox.MarkDispatched([]int64{pending[0].ID}, t0))
+
+	pending, err = outbox.Pending(10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+
+	n, err := outbox.Purge(t0.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n, "only dispatched events are purged")
+}
+
+func TestOutbox_Claim(t *testing.T) {
+	outbox := NewOutbox(setupTestDB(t))
+	require.NoError(t, outbox.Append(created(1), created(2)))
+
+	claimed, err := outbox.Claim("a", t0, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, "a", claimed[0].ClaimedBy)
+
+	// Held by a until its claim runs out.
+	claimed, err = outbox.Claim("b", t0.Add(30*time.Second), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	require.NoError(t, outbox.MarkDispatched([]int64{1}, t0))
+	claimed, err = outbox.Claim("b", t0.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "dispatched events are not claimed")
+	assert.Equal(t, int64(2), claimed[0].ID)
+}
+
+func TestDispatcher_RelayOnce(t *testing.T) {
+	db := setupTestDB(t)
+	outbox := NewOutbox(db)
+	store := scheduler.NewStore(db)
+	require.NoError(t, outbox.Append(created(1), UserDeleted{UserID: 1}))
+
+	d := NewDispatcher(outbox, store, testConfig())
+	d.now = func() time.Time { return t0 }
+	noop := func(ctx context.Context, e *Envelope) error { return nil }
+	d.Subscribe("tasks", noop, TypeTaskCreated, TypeStatusChanged)
+	d.Subscribe("cleanup", noop, TypeUserDeleted)
+	d.Subscribe("audit", noop)
+	var heard []string
+	d.Listen(func(e *Envelope) { heard = append(heard, e.Type) })
+
+	n, err := d.RelayOnce()
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{TypeTaskCreated, TypeUserDeleted}, heard)
+
+	var jobs []scheduler.Job
+	require.NoError(t, db.Order("id").Find(&jobs).Error)
+	var consumers []string
+	for _, j := range jobs {
+		var p delivery
+		require.NoError(t, j.Decode(&p))
+		assert.Equal(t, KindDeliver, j.Kind)
+		assert.Equal(t, MaxAttempts, j.MaxAttempts)
+		assert.Equal(t, "event:"+p.Event.ID+":"+p.Consumer, j.DedupKey)
+		consumers = append(consumers, p.Event.Type+" "+p.Consumer)
+	}
+	sort.Strings(consumers)
+	assert.Equal(t, []string{
+		"task.created audit",
+		"task.created tasks",
+		"user.deleted audit",
+		"user.deleted cleanup",
+	}, consumers)
+
+	n, err = d.RelayOnce()
+	require.NoError(t, err)
+	assert.Zero(t, n, "dispatched events are not relayed again")
+	assert.Len(t, heard, 2)
+}
+
+func TestDispatcher_RelayingTwiceQueuesOnce(t *testing.T) {
+	db := setupTestDB(t)
+	outbox := NewOutbox(db)
+	store := scheduler.NewStore(db)
+	require.NoError(t, outbox.Append(created(1)))
+
+	// A replica stalls past its claim, so the event is relayed again.
+	pending, err := outbox.Pending(10)
+	require.NoError(t, err)
+	for range 2 {
+		require.NoError(t, db.Model(&Record{}).Where("id = ?", pending[0].ID).
+			Updates(map[string]any{"dispatched_at": nil, "claimed_until": nil}).Error)
+		d := NewDispatcher(outbox, store, testConfig())
+		d.Subscribe("tasks", func(ctx context.Context, e *Envelope) error { return nil })
+		_, err := d.RelayOnce()
+		require.NoError(t, err)
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&scheduler.Job{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestDispatcher_DeliversThroughTheScheduler(t *testing.T) {
+	db := setupTestDB(t)
+	outbox := NewOutbox(db)
+	store := scheduler.NewStore(db)
+	require.NoError(t, outbox.Append(created(1)))
+
+	d := NewDispatcher(outbox, store, testConfig())
+	var got []int
+	calls := 0
+	d.Subscribe("flaky", func(ctx context.Context, e *Envelope) error {
+		calls++
+		if calls == 1 {
+			return errors.New("not yet")
+		}
+		var ev TaskCreated
+		require.NoError(t, e.Decode(&ev))
+		got = append(got, ev.Task.ID)
+		return nil
+	})
+	var others int
+	d.Subscribe("steady", func(ctx context.Context, e *Envelope) error {
+		others++
+		return nil
+	})
+
+	sched := scheduler.New(store, scheduler.Config{WorkerID: "w1", PollInterval: time.Second, Lease: time.Minute, BatchSize: 10, Retention: time.Hour})
+	d.Register(sched)
+
+	_, err := d.RelayOnce()
+	require.NoError(t, err)
+	_, err = sched.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, got)
+	assert.Equal(t, 1, others)
+
+	// The failed consumer is retried on its own after the backoff.
+	require.NoError(t, db.Model(&scheduler.Job{}).Where("status = ?", scheduler.StatusPending).Update("run_at", t0).Error)
+	_, err = sched.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, got)
+	assert.Equal(t, 1, others)
+}
+
+func TestDispatcher_DeliverDropsUnknownConsumers(t *testing.T) {
+	d := NewDispatcher(nil, nil, testConfig())
+	job, err := scheduler.NewJob(KindDeliver, "k", t0, delivery{Consumer: "gone", Event: Envelope{ID: "abc", Type: TypeTaskCreated}})
+	require.NoError(t, err)
+	assert.NoError(t, d.Deliver(context.Background(), job))
+}
+
+func TestHTTPPublisher_Publish(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		if r.Header.Get(webhook.DeliveryHeader) == "fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewHTTPPublisher(srv.URL, "s3cret")
+	p.now = func() time.Time { return t0 }
+
+	e := &Envelope{ID: "abc", Type: TypeTaskCreated, UserID: 1, OccurredAt: t0, Data: []byte(`{"user_id":1}`)}
+	require.NoError(t, p.Publish(context.Background(), e))
+	assert.Equal(t, TypeTaskCreated, received.Header.Get(webhook.EventHeader))
+	assert.NoError(t, webhook.Verify("s3cret", received.Header.Get(webhook.TimestampHeader), received.Header.Get(webhook.SignatureHeader), body, t0, time.Minute))
+	assert.JSONEq(t, `{"id":"abc","type":"task.created","user_id":1,"occurred_at":"2025-09-08T12:00:00Z","data":{"user_id":1}}`, string(body))
+
+	e.ID = "fail"
+	assert.EqualError(t, p.Publish(context.Background(), e), "publisher endpoint answered 502 Bad Gateway")
+}
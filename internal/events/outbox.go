@@ -0,0 +1,105 @@
+package events
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Outbox stores events. Repositories create one on their transaction so
+// events are committed or rolled back together with the change.
+type Outbox struct {
+	db *gorm.DB
+}
+
+func NewOutbox(db *gorm.DB) *Outbox {
+	return &Outbox{db: db}
+}
+
+// Append records evs.
+func (o *Outbox) Append(evs ...Event) error {
+	if len(evs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	records := make([]*Record, 0, len(evs))
+	for _, e := range evs {
+		r, err := NewRecord(e, now)
+		if err != nil {
+			return err
+		}
+		records = append(records, r)
+	}
+	return o.db.Create(records).Error
+}
+
+// Pending returns up to limit events that were not dispatched yet, oldest
+// first.
+func (o *Outbox) Pending(limit int) ([]Record, error) {
+	var records []Record
+	err := o.db.Where("dispatched_at IS NULL").
+		Order("id").
+		Limit(limit).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// Claim takes up to limit pending events for worker until now+lease and
+// returns them, oldest first. Claims are taken with a conditional UPDATE, as
+// scheduler leases are, so with several replicas polling the outbox each
+// event is relayed by one of them. An event whose claim runs out before it
+// is marked dispatched is claimed again.
+func (o *Outbox) Claim(worker string, now time.Time, lease time.Duration, limit int) ([]Record, error) {
+	var ids []int64
+	err := o.db.Model(&Record{}).
+		Where("dispatched_at IS NULL AND (claimed_until IS NULL OR claimed_until < ?)", now).
+		Order("id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	until := now.Add(lease)
+	claimed := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		// Another replica may have claimed the event since it was selected.
+		res := o.db.Model(&Record{}).
+			Where("id = ? AND dispatched_at IS NULL AND (claimed_until IS NULL OR claimed_until < ?)", id, now).
+			Updates(map[string]any{"claimed_by": worker, "claimed_until": until})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 1 {
+			claimed = append(claimed, id)
+		}
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	var records []Record
+	if err := o.db.Where("id IN ?", claimed).Order("id").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// MarkDispatched records that the events were handed to their consumers.
+func (o *Outbox) MarkDispatched(ids []int64, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return o.db.Model(&Record{}).
+		Where("id IN ? AND dispatched_at IS NULL", ids).
+		Update("dispatched_at", at).Error
+}
+
+// Purge deletes events dispatched before the given time.
+func (o *Outbox) Purge(before time.Time) (int64, error) {
+	res := o.db.Where("dispatched_at < ?", before).Delete(&Record{})
+	return res.RowsAffected, res.Error
+}
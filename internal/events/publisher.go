@@ -0,0 +1,61 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"taskflow/internal/domain/webhook"
+)
+
+// HTTPPublisher posts every event as JSON to a fixed URL, for example the
+// HTTP bridge of a message broker. Requests are signed like webhook
+// deliveries when a secret is set.
+type HTTPPublisher struct {
+	url    string
+	secret string
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewHTTPPublisher(url, secret string) *HTTPPublisher {
+	return &HTTPPublisher{url: url, secret: secret, client: &http.Client{Timeout: 10 * time.Second}, now: time.Now}
+}
+
+// Compile-time check
+var _ Publisher = (*HTTPPublisher)(nil)
+
+// Publish posts e and fails unless the receiver answers with a 2xx status.
+func (p *HTTPPublisher) Publish(ctx context.Context, e *Envelope) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.EventHeader, e.Type)
+	req.Header.Set(webhook.DeliveryHeader, e.ID)
+	if p.secret != "" {
+		ts := p.now().Unix()
+		req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(ts, 10))
+		req.Header.Set(webhook.SignatureHeader, webhook.Sign(p.secret, ts, body))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("publisher endpoint answered %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package accesstoken_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	accesstoken_service "taskflow/internal/service/accesstoken"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AccessTokenHandler struct {
+	service  accesstoken_service.AccessTokenServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewAccessTokenHandler(s accesstoken_service.AccessTokenServiceInterface, ua auth.UserAuthInterface) *AccessTokenHandler {
+	return &AccessTokenHandler{service: s, userAuth: ua}
+}
+
+var _ AccessTokenHandlerInterface = (*AccessTokenHandler)(nil)
+
+// CreateToken godoc
+// @Summary Create a personal access token
+// @Description Create a token for clients that can't log in, such as CalDAV apps. The token is only returned this once.
+// @Tags access-tokens
+// @Accept json
+// @Produce json
+// @Param token body dto.CreateAccessTokenRequest true "Token settings"
+// @Success 201 {object} dto.CreateAccessTokenResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 409 {object} common.ErrorResponse "Too many tokens"
+// @Router /access-tokens [post]
+func (h *AccessTokenHandler) CreateToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateToken(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListTokens godoc
+// @Summary List personal access tokens
+// @Description Returns the user's access tokens, without their secrets
+// @Tags access-tokens
+// @Produce json
+// @Success 200 {object} dto.ListAccessTokensResponse
+// @Router /access-tokens [get]
+func (h *AccessTokenHandler) ListTokens(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.ListTokens(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteToken godoc
+// @Summary Delete a personal access token
+// @Description Delete an access token. Clients using it can no longer sign in.
+// @Tags access-tokens
+// @Produce json
+// @Param id path int true "Token ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteAccessTokenResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Token not found"
+// @Router /access-tokens/{id} [delete]
+func (h *AccessTokenHandler) DeleteToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid token ID"})
+		return
+	}
+
+	if err := h.service.DeleteToken(userID.(int), id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteAccessTokenResponse{Message: "Access token deleted successfully"})
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, accesstoken_service.ErrTooManyTokens):
+		c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, accesstoken_service.ErrInvalidUser),
+		errors.Is(err, accesstoken_service.ErrExpiresInPast):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
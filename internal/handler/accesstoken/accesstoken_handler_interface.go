@@ -0,0 +1,14 @@
+package accesstoken_handler
+
+import "github.com/gin-gonic/gin"
+
+type AccessTokenHandlerInterface interface {
+	// CreateToken handles POST /api/access-tokens
+	CreateToken(c *gin.Context)
+
+	// ListTokens handles GET /api/access-tokens
+	ListTokens(c *gin.Context)
+
+	// DeleteToken handles DELETE /api/access-tokens/:id
+	DeleteToken(c *gin.Context)
+}
@@ -0,0 +1,168 @@
+package accesstoken_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	accesstoken_service "taskflow/internal/service/accesstoken"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestAccessTokenHandler_CreateToken(t *testing.T) {
+	created := dto.CreateAccessTokenResponse{
+		AccessTokenResponse: dto.AccessTokenResponse{ID: 1, Name: "Phone", Prefix: "tfp_0123ab"},
+		Token:               "tfp_0123ab",
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *accesstoken_service.AccessTokenServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"name":"Phone"}`,
+			setupMock: func() *accesstoken_service.AccessTokenServiceMock {
+				m := new(accesstoken_service.AccessTokenServiceMock)
+				m.On("CreateToken", 1, &dto.CreateAccessTokenRequest{Name: "Phone"}).Return(created, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   created,
+		},
+		{
+			name:        "failure - too many",
+			requestBody: `{"name":"Phone"}`,
+			setupMock: func() *accesstoken_service.AccessTokenServiceMock {
+				m := new(accesstoken_service.AccessTokenServiceMock)
+				m.On("CreateToken", 1, mock.Anything).Return(dto.CreateAccessTokenResponse{}, accesstoken_service.ErrTooManyTokens)
+				return m
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   common.ErrorResponse{Message: accesstoken_service.ErrTooManyTokens.Error()},
+		},
+		{
+			name:        "failure - expired",
+			requestBody: `{"name":"Phone","expires_at":"2020-01-01T00:00:00Z"}`,
+			setupMock: func() *accesstoken_service.AccessTokenServiceMock {
+				m := new(accesstoken_service.AccessTokenServiceMock)
+				m.On("CreateToken", 1, mock.Anything).Return(dto.CreateAccessTokenResponse{}, accesstoken_service.ErrExpiresInPast)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: accesstoken_service.ErrExpiresInPast.Error()},
+		},
+		{
+			name:        "failure - missing name",
+			requestBody: `{}`,
+			setupMock: func() *accesstoken_service.AccessTokenServiceMock {
+				return new(accesstoken_service.AccessTokenServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'CreateAccessTokenRequest.Name' Error:Field validation for 'Name' failed on the 'required' tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewAccessTokenHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/access-tokens", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.CreateToken(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/access-tokens", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAccessTokenHandler_DeleteToken(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		setupMock      func() *accesstoken_service.AccessTokenServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "success",
+			id:   "3",
+			setupMock: func() *accesstoken_service.AccessTokenServiceMock {
+				m := new(accesstoken_service.AccessTokenServiceMock)
+				m.On("DeleteToken", 1, 3).Return(nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.DeleteAccessTokenResponse{Message: "Access token deleted successfully"},
+		},
+		{
+			name: "failure - not found",
+			id:   "4",
+			setupMock: func() *accesstoken_service.AccessTokenServiceMock {
+				m := new(accesstoken_service.AccessTokenServiceMock)
+				m.On("DeleteToken", 1, 4).Return(gorm.ErrRecordNotFound)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+		{
+			name: "failure - invalid id",
+			id:   "abc",
+			setupMock: func() *accesstoken_service.AccessTokenServiceMock {
+				return new(accesstoken_service.AccessTokenServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid token ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewAccessTokenHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.DELETE("/access-tokens/:id", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.DeleteToken(c)
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/access-tokens/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package attachment_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	attachment_service "taskflow/internal/service/attachment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxUploadBody bounds the whole multipart request, leaving room for form overhead.
+const maxUploadBody = attachment_service.DefaultMaxSize + 1<<20
+
+type AttachmentHandler struct {
+	service  attachment_service.AttachmentServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewAttachmentHandler(s attachment_service.AttachmentServiceInterface, ua auth.UserAuthInterface) *AttachmentHandler {
+	return &AttachmentHandler{service: s, userAuth: ua}
+}
+
+var _ AttachmentHandlerInterface = (*AttachmentHandler)(nil)
+
+// Upload godoc
+// @Summary Attach a file to a task
+// @Description Upload a file as multipart/form-data. The type is detected from the file contents.
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param file formData file true "File to upload"
+// @Success 201 {object} dto.AttachmentResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Failure 413 {object} common.ErrorResponse "File too large"
+// @Failure 415 {object} common.ErrorResponse "File type not allowed"
+// @Router /tasks/{id}/attachments [post]
+func (h *AttachmentHandler) Upload(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || taskID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid task ID"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBody)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: attachment_service.ErrFileTooLarge.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "file is required"})
+		return
+	}
+
+	f, err := fh.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "could not read file"})
+		return
+	}
+	defer f.Close()
+
+	resp, err := h.service.Upload(userID.(int), taskID, fh.Filename, fh.Size, f)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	resp.DownloadURL = routePrefix(c) + resp.DownloadURL
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ReplayUpload gives an upload response replayed for a retried
+// Idempotency-Key a fresh download URL, as the stored one may have expired.
+func (h *AttachmentHandler) ReplayUpload(c *gin.Context, body []byte) ([]byte, error) {
+	var resp dto.AttachmentResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+	resp = h.service.Resign(resp)
+	resp.DownloadURL = routePrefix(c) + resp.DownloadURL
+	return json.Marshal(resp)
+}
+
+// List godoc
+// @Summary List task attachments
+// @Description Returns the attachments of a task with short-lived signed download URLs
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Success 200 {object} dto.ListAttachmentsResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Router /tasks/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || taskID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid task ID"})
+		return
+	}
+
+	resp, err := h.service.List(userID.(int), taskID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	prefix := routePrefix(c)
+	for i := range resp.Attachments {
+		resp.Attachments[i].DownloadURL = prefix + resp.Attachments[i].DownloadURL
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// Delete godoc
+// @Summary Delete an attachment
+// @Description Remove an attachment and its stored file
+// @Tags attachments
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param attachmentID path int true "Attachment ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteAttachmentResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Attachment not found"
+// @Router /tasks/{id}/attachments/{attachmentID} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || taskID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid task ID"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("attachmentID"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid attachment ID"})
+		return
+	}
+
+	if err := h.service.Delete(userID.(int), taskID, id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteAttachmentResponse{Message: "Attachment deleted successfully"})
+}
+
+// Download godoc
+// @Summary Download an attachment
+// @Description Streams attachment contents. Authorized by the signed, expiring URL returned when listing attachments.
+// @Tags attachments
+// @Produce octet-stream
+// @Param id path int true "Attachment ID" minimum(1) example(1)
+// @Param expires query int true "Unix expiry of the link"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} common.ErrorResponse "Invalid or expired link"
+// @Failure 404 {object} common.ErrorResponse "Attachment not found"
+// @Router /attachments/{id}/download [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid attachment ID"})
+		return
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, common.ErrorResponse{Message: attachment_service.ErrInvalidDownload.Error()})
+		return
+	}
+
+	meta, rc, err := h.service.Open(id, expires, c.Query("signature"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	defer rc.Close()
+
+	headers := map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": meta.FileName}),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, no-store",
+	}
+	c.DataFromReader(http.StatusOK, meta.Size, meta.ContentType, rc, headers)
+}
+
+// routePrefix returns the router group the request came through ("/api" or ""),
+// so download links point back at the same API surface.
+func routePrefix(c *gin.Context) string {
+	path := c.FullPath()
+	if i := strings.Index(path, "/tasks/"); i > 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, attachment_service.ErrAttachmentMissing):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, attachment_service.ErrInvalidDownload):
+		c.JSON(http.StatusForbidden, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, attachment_service.ErrFileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, attachment_service.ErrUnsupportedType):
+		c.JSON(http.StatusUnsupportedMediaType, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, attachment_service.ErrEmptyFile),
+		errors.Is(err, attachment_service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,20 @@
+package attachment_handler
+
+import "github.com/gin-gonic/gin"
+
+type AttachmentHandlerInterface interface {
+	// Upload handles POST /api/tasks/:id/attachments
+	Upload(c *gin.Context)
+
+	// List handles GET /api/tasks/:id/attachments
+	List(c *gin.Context)
+
+	// Delete handles DELETE /api/tasks/:id/attachments/:attachmentID
+	Delete(c *gin.Context)
+
+	// Download handles GET /api/attachments/:id/download
+	Download(c *gin.Context)
+
+	// ReplayUpload refreshes a replayed POST /api/tasks/:id/attachments response
+	ReplayUpload(c *gin.Context, body []byte) ([]byte, error)
+}
@@ -0,0 +1,206 @@
+package attachment_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/dto"
+	attachment_service "taskflow/internal/service/attachment"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func multipartBody(t *testing.T, field, name string, content []byte) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if field != "" {
+		part, err := w.CreateFormFile(field, name)
+		require.NoError(t, err)
+		part.Write(content)
+	}
+	require.NoError(t, w.Close())
+	return &buf, w.FormDataContentType()
+}
+
+func TestAttachmentHandler_Upload(t *testing.T) {
+	tests := []struct {
+		name           string
+		field          string
+		setupMock      func() *attachment_service.AttachmentServiceMock
+		expectedStatus int
+	}{
+		{
+			name:  "success",
+			field: "file",
+			setupMock: func() *attachment_service.AttachmentServiceMock {
+				m := new(attachment_service.AttachmentServiceMock)
+				m.On("Upload", 1, 10, "spec.pdf", int64(8), mock.Anything).
+					Return(dto.AttachmentResponse{ID: 3, DownloadURL: "/attachments/3/download?expires=1&signature=x"}, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "failure - missing file field",
+			field:          "",
+			setupMock:      func() *attachment_service.AttachmentServiceMock { return new(attachment_service.AttachmentServiceMock) },
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:  "failure - unsupported type",
+			field: "file",
+			setupMock: func() *attachment_service.AttachmentServiceMock {
+				m := new(attachment_service.AttachmentServiceMock)
+				m.On("Upload", 1, 10, "spec.pdf", int64(8), mock.Anything).
+					Return(dto.AttachmentResponse{}, attachment_service.ErrUnsupportedType)
+				return m
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewAttachmentHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			api := router.Group("/api")
+			api.POST("/tasks/:id/attachments", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.Upload(c)
+			})
+
+			body, contentType := multipartBody(t, tt.field, "spec.pdf", []byte("%PDF-1.4"))
+			req := httptest.NewRequest(http.MethodPost, "/api/tasks/10/attachments", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusCreated {
+				var resp dto.AttachmentResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, "/api/attachments/3/download?expires=1&signature=x", resp.DownloadURL)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttachmentHandler_ReplayUpload(t *testing.T) {
+	stale := dto.AttachmentResponse{ID: 3, DownloadURL: "/api/attachments/3/download?expires=1&signature=x"}
+	mockService := new(attachment_service.AttachmentServiceMock)
+	mockService.On("Resign", stale).Return(dto.AttachmentResponse{ID: 3, DownloadURL: "/attachments/3/download?expires=2&signature=y"})
+	handler := NewAttachmentHandler(mockService, new(auth.MockUserAuth))
+
+	var got []byte
+	router := setupGin()
+	router.POST("/api/tasks/:id/attachments", func(c *gin.Context) {
+		body, _ := json.Marshal(stale)
+		var err error
+		got, err = handler.ReplayUpload(c, body)
+		require.NoError(t, err)
+	})
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/tasks/10/attachments", nil))
+
+	var resp dto.AttachmentResponse
+	require.NoError(t, json.Unmarshal(got, &resp))
+	assert.Equal(t, "/api/attachments/3/download?expires=2&signature=y", resp.DownloadURL)
+	mockService.AssertExpectations(t)
+}
+
+func TestAttachmentHandler_Download(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func() *attachment_service.AttachmentServiceMock
+		expectedStatus int
+	}{
+		{
+			name:  "success",
+			query: "?expires=100&signature=abc",
+			setupMock: func() *attachment_service.AttachmentServiceMock {
+				m := new(attachment_service.AttachmentServiceMock)
+				m.On("Open", 3, int64(100), "abc").Return(
+					dto.AttachmentResponse{FileName: "spec.pdf", ContentType: "application/pdf", Size: 8},
+					io.NopCloser(bytes.NewReader([]byte("%PDF-1.4"))),
+					nil,
+				)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "failure - missing expiry",
+			query:          "?signature=abc",
+			setupMock:      func() *attachment_service.AttachmentServiceMock { return new(attachment_service.AttachmentServiceMock) },
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:  "failure - bad signature",
+			query: "?expires=100&signature=bad",
+			setupMock: func() *attachment_service.AttachmentServiceMock {
+				m := new(attachment_service.AttachmentServiceMock)
+				m.On("Open", 3, int64(100), "bad").Return(dto.AttachmentResponse{}, nil, attachment_service.ErrInvalidDownload)
+				return m
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewAttachmentHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/attachments/:id/download", handler.Download)
+
+			req := httptest.NewRequest(http.MethodGet, "/attachments/3/download"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				assert.Equal(t, "%PDF-1.4", w.Body.String())
+				assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename=spec.pdf`, w.Header().Get("Content-Disposition"))
+				assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttachmentHandler_Delete(t *testing.T) {
+	mockService := new(attachment_service.AttachmentServiceMock)
+	mockService.On("Delete", 1, 10, 3).Return(nil)
+	handler := NewAttachmentHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.DELETE("/tasks/:id/attachments/:attachmentID", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.Delete(c)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/tasks/10/attachments/3", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
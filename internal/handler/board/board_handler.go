@@ -0,0 +1,112 @@
+package board_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	board_service "taskflow/internal/service/board"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BoardHandler struct {
+	service  board_service.BoardServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewBoardHandler(s board_service.BoardServiceInterface, ua auth.UserAuthInterface) *BoardHandler {
+	return &BoardHandler{service: s, userAuth: ua}
+}
+
+var _ BoardHandlerInterface = (*BoardHandler)(nil)
+
+// GetBoard godoc
+// @Summary Get a kanban board
+// @Description Returns the inbox, or a project when project_id is set, as a board with one column per status. Each column has its WIP limit, its task count and one page of tasks in board order.
+// @Tags boards
+// @Produce json
+// @Param project_id query int false "Project ID; omit for the inbox"
+// @Param status query string false "Only return this column" Enums(pending, in-progress, completed)
+// @Param page query int false "Page of every column (default 1)"
+// @Param limit query int false "Tasks per column (default 20, max 100)"
+// @Success 200 {object} dto.BoardResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Project not found"
+// @Router /boards [get]
+func (h *BoardHandler) GetBoard(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var projectID *int
+	if raw := c.Query("project_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id < 1 {
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid project ID"})
+			return
+		}
+		projectID = &id
+	}
+	page, limit := common.ParsePagination(c.Query("page"), c.Query("limit"))
+
+	resp, err := h.service.GetBoard(userID.(int), projectID, c.Query("status"), page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetWIPLimit godoc
+// @Summary Set a column's WIP limit
+// @Description Limit how many tasks a board column may hold. Moving a task into a full column fails with 409. A limit of 0 removes it.
+// @Tags boards
+// @Accept json
+// @Produce json
+// @Param status path string true "Column" Enums(pending, in-progress, completed)
+// @Param request body dto.SetWIPLimitRequest true "Board and limit"
+// @Success 200 {object} dto.BoardColumnResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Project not found"
+// @Router /boards/columns/{status} [put]
+func (h *BoardHandler) SetWIPLimit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.SetWIPLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.SetWIPLimit(userID.(int), c.Param("status"), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, board_service.ErrUnknownColumn),
+		errors.Is(err, board_service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
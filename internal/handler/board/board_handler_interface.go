@@ -0,0 +1,11 @@
+package board_handler
+
+import "github.com/gin-gonic/gin"
+
+type BoardHandlerInterface interface {
+	// GetBoard handles GET /api/boards
+	GetBoard(c *gin.Context)
+
+	// SetWIPLimit handles PUT /api/boards/columns/:status
+	SetWIPLimit(c *gin.Context)
+}
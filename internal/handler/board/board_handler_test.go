@@ -0,0 +1,144 @@
+package board_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	board_service "taskflow/internal/service/board"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestBoardHandler_GetBoard(t *testing.T) {
+	projectID := 4
+	board := dto.BoardResponse{
+		ProjectID: &projectID,
+		Columns: []dto.BoardColumnResponse{
+			{Status: "in-progress", WIPLimit: 3, Count: 1, Tasks: []dto.GetTaskResponse{{ID: 7, Task: "Write report", Status: "in-progress"}}, Page: 1, Limit: 20},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func() *board_service.BoardServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:  "success",
+			query: "?project_id=4&status=in-progress",
+			setupMock: func() *board_service.BoardServiceMock {
+				m := new(board_service.BoardServiceMock)
+				m.On("GetBoard", 1, &projectID, "in-progress", 1, 20).Return(board, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   board,
+		},
+		{
+			name:           "failure - invalid project ID",
+			query:          "?project_id=abc",
+			setupMock:      func() *board_service.BoardServiceMock { return new(board_service.BoardServiceMock) },
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid project ID"},
+		},
+		{
+			name:  "failure - unknown column",
+			query: "?status=blocked&page=2&limit=5",
+			setupMock: func() *board_service.BoardServiceMock {
+				m := new(board_service.BoardServiceMock)
+				m.On("GetBoard", 1, (*int)(nil), "blocked", 2, 5).Return(dto.BoardResponse{}, board_service.ErrUnknownColumn)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: board_service.ErrUnknownColumn.Error()},
+		},
+		{
+			name:  "failure - project not found",
+			query: "?project_id=4",
+			setupMock: func() *board_service.BoardServiceMock {
+				m := new(board_service.BoardServiceMock)
+				m.On("GetBoard", 1, &projectID, "", 1, 20).Return(dto.BoardResponse{}, gorm.ErrRecordNotFound)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewBoardHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/boards", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.GetBoard(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/boards"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assertJSONEqual(t, tt.expectedBody, w.Body.Bytes())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBoardHandler_SetWIPLimit(t *testing.T) {
+	mockService := new(board_service.BoardServiceMock)
+	mockService.On("SetWIPLimit", 1, "in-progress", mock.MatchedBy(func(r *dto.SetWIPLimitRequest) bool {
+		return r.ProjectID == nil && r.WIPLimit == 3
+	})).Return(dto.BoardColumnResponse{Status: "in-progress", WIPLimit: 3, Tasks: []dto.GetTaskResponse{}, Page: 1, Limit: 20}, nil)
+	handler := NewBoardHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.PUT("/boards/columns/:status", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.SetWIPLimit(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/boards/columns/in-progress", bytes.NewBufferString(`{"wip_limit":3}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+
+	req = httptest.NewRequest(http.MethodPut, "/boards/columns/in-progress", bytes.NewBufferString(`{"wip_limit":-1}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func assertJSONEqual(t *testing.T, expected any, actual []byte) {
+	t.Helper()
+
+	expectedBytes, err := json.Marshal(expected)
+	assert.NoError(t, err)
+
+	var want, got any
+	assert.NoError(t, json.Unmarshal(expectedBytes, &want))
+	assert.NoError(t, json.Unmarshal(actual, &got))
+	assert.Equal(t, want, got)
+}
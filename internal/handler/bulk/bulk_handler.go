@@ -0,0 +1,80 @@
+package bulk_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	bulk_service "taskflow/internal/service/bulk"
+	"taskflow/internal/taskquery"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BulkHandler struct {
+	service  bulk_service.BulkServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewBulkHandler(s bulk_service.BulkServiceInterface, ua auth.UserAuthInterface) *BulkHandler {
+	return &BulkHandler{service: s, userAuth: ua}
+}
+
+var _ BulkHandlerInterface = (*BulkHandler)(nil)
+
+// BulkTasks godoc
+// @Summary Apply an action to many tasks
+// @Description Update status, delete (to trash), tag, move or restore up to 500 tasks selected by ids, a saved filter or a query, in one transaction. Each item is reported as success, not_found or forbidden.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body dto.BulkTaskRequest true "Action and selection"
+// @Success 200 {object} dto.BulkTaskResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Filter not found"
+// @Router /tasks/bulk [post]
+func (h *BulkHandler) BulkTasks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.BulkTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.Apply(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func writeError(c *gin.Context, err error) {
+	var qerr *taskquery.Error
+	switch {
+	case errors.As(err, &qerr):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: qerr.Message, Position: qerr.Pos})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, bulk_service.ErrSelection),
+		errors.Is(err, bulk_service.ErrTooManyTasks),
+		errors.Is(err, bulk_service.ErrMissingStatus),
+		errors.Is(err, bulk_service.ErrMissingTags),
+		errors.Is(err, bulk_service.ErrRestoreNeedsIDs),
+		errors.Is(err, bulk_service.ErrUnknownProject),
+		errors.Is(err, bulk_service.ErrUnknownAction),
+		errors.Is(err, bulk_service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,8 @@
+package bulk_handler
+
+import "github.com/gin-gonic/gin"
+
+type BulkHandlerInterface interface {
+	// BulkTasks handles POST /api/tasks/bulk
+	BulkTasks(c *gin.Context)
+}
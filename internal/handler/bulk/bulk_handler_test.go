@@ -0,0 +1,104 @@
+package bulk_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	bulk_service "taskflow/internal/service/bulk"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestBulkHandler_BulkTasks(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *bulk_service.BulkServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"action":"update_status","ids":[1,2],"status":"completed"}`,
+			setupMock: func() *bulk_service.BulkServiceMock {
+				m := new(bulk_service.BulkServiceMock)
+				m.On("Apply", 1, mock.MatchedBy(func(r *dto.BulkTaskRequest) bool {
+					return r.Action == "update_status" && len(r.IDs) == 2 && r.Status == "completed"
+				})).Return(dto.BulkTaskResponse{
+					Action: "update_status",
+					Results: []dto.BulkItemResult{
+						{ID: 1, Result: bulk_service.ResultSuccess},
+						{ID: 2, Result: bulk_service.ResultForbidden},
+					},
+					Succeeded: 1,
+					Failed:    1,
+				}, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.BulkTaskResponse{
+				Action: "update_status",
+				Results: []dto.BulkItemResult{
+					{ID: 1, Result: bulk_service.ResultSuccess},
+					{ID: 2, Result: bulk_service.ResultForbidden},
+				},
+				Succeeded: 1,
+				Failed:    1,
+			},
+		},
+		{
+			name:           "failure - unknown action",
+			requestBody:    `{"action":"archive","ids":[1]}`,
+			setupMock:      func() *bulk_service.BulkServiceMock { return new(bulk_service.BulkServiceMock) },
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "failure - no selection",
+			requestBody: `{"action":"delete"}`,
+			setupMock: func() *bulk_service.BulkServiceMock {
+				m := new(bulk_service.BulkServiceMock)
+				m.On("Apply", 1, mock.Anything).Return(dto.BulkTaskResponse{}, bulk_service.ErrSelection)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: bulk_service.ErrSelection.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewBulkHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/tasks/bulk", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.BulkTasks(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/tasks/bulk", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				expected, _ := json.Marshal(tt.expectedBody)
+				assert.JSONEq(t, string(expected), w.Body.String())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,459 @@
+package caldav_handler
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"taskflow/internal/common"
+	accesstoken_service "taskflow/internal/service/accesstoken"
+	caldav_service "taskflow/internal/service/caldav"
+	task_service "taskflow/internal/service/task"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Root is where the DAV tree is served.
+	Root = "/dav"
+	// maxObjectBody bounds the iCalendar object of a PUT.
+	maxObjectBody = 1 << 20
+
+	principalPath = Root + "/principal/"
+	homePath      = Root + "/calendars/"
+	contentType   = "text/calendar; charset=utf-8; component=vtodo"
+)
+
+// Methods are the request methods ServeDAV handles.
+var Methods = []string{"OPTIONS", "PROPFIND", "REPORT", http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete}
+
+type CalDAVHandler struct {
+	service caldav_service.CalDAVServiceInterface
+	tokens  accesstoken_service.AccessTokenServiceInterface
+}
+
+func NewCalDAVHandler(s caldav_service.CalDAVServiceInterface, tokens accesstoken_service.AccessTokenServiceInterface) *CalDAVHandler {
+	return &CalDAVHandler{service: s, tokens: tokens}
+}
+
+var _ CalDAVHandlerInterface = (*CalDAVHandler)(nil)
+
+// target is the resource a request path names.
+type target int
+
+const (
+	targetRoot target = iota
+	targetPrincipal
+	targetHome
+	targetCollection
+	targetObject
+)
+
+func (h *CalDAVHandler) BasicAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Clients probe for DAV support before they have credentials.
+		if c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		_, secret, ok := c.Request.BasicAuth()
+		if !ok {
+			challenge(c)
+			return
+		}
+		userID, err := h.tokens.Authenticate(secret)
+		if errors.Is(err, accesstoken_service.ErrInvalidToken) {
+			challenge(c)
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{Message: "authentication failed"})
+			return
+		}
+
+		c.Set("userID", userID)
+		c.Next()
+	}
+}
+
+func challenge(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Basic realm="TaskFlow", charset="UTF-8"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+}
+
+// WellKnown godoc
+// @Summary Discover the CalDAV server
+// @Description Redirects CalDAV clients to the DAV root, per RFC 6764.
+// @Tags caldav
+// @Success 301
+// @Router /.well-known/caldav [get]
+func (h *CalDAVHandler) WellKnown(c *gin.Context) {
+	c.Redirect(http.StatusMovedPermanently, Root+"/")
+}
+
+// ServeDAV godoc
+// @Summary CalDAV
+// @Description Serves your tasks as CalDAV task lists: one calendar for tasks without a project and one per project. Besides GET, PUT and DELETE of .ics resources it answers OPTIONS, PROPFIND and REPORT (calendar-query, calendar-multiget and sync-collection). Authenticate with HTTP Basic: any user name and a personal access token as the password.
+// @Tags caldav
+// @Accept text/calendar
+// @Produce text/calendar
+// @Param path path string true "Resource path, such as calendars/inbox/task-1.ics"
+// @Success 200 {string} string "iCalendar data"
+// @Success 201 "Created"
+// @Success 204 "Updated or deleted"
+// @Failure 401 {object} common.ErrorResponse "Missing or invalid access token"
+// @Failure 404 {object} common.ErrorResponse "Not found"
+// @Failure 412 {object} common.ErrorResponse "ETag mismatch"
+// @Router /dav/{path} [get]
+// @Router /dav/{path} [put]
+// @Router /dav/{path} [delete]
+func (h *CalDAVHandler) ServeDAV(c *gin.Context) {
+	if c.Request.Method == http.MethodOptions {
+		c.Header("DAV", "1, 3, calendar-access")
+		c.Header("Allow", strings.Join(Methods, ", "))
+		c.Status(http.StatusOK)
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	kind, collection, name, ok := parsePath(c.Param("path"))
+	if !ok {
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+		return
+	}
+
+	switch c.Request.Method {
+	case "PROPFIND":
+		h.propfind(c, userID.(int), kind, collection, name)
+	case "REPORT":
+		if kind != targetCollection {
+			methodNotAllowed(c)
+			return
+		}
+		h.report(c, userID.(int), collection)
+	case http.MethodGet, http.MethodHead:
+		if kind != targetObject {
+			methodNotAllowed(c)
+			return
+		}
+		h.get(c, userID.(int), collection, name)
+	case http.MethodPut:
+		if kind != targetObject {
+			methodNotAllowed(c)
+			return
+		}
+		h.put(c, userID.(int), collection, name)
+	case http.MethodDelete:
+		if kind != targetObject {
+			methodNotAllowed(c)
+			return
+		}
+		err := h.service.Delete(userID.(int), collection, name, conditions(c))
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	default:
+		methodNotAllowed(c)
+	}
+}
+
+func (h *CalDAVHandler) propfind(c *gin.Context, userID int, kind target, collection, name string) {
+	req, err := parseRequest(c.Request.Body)
+	if err != nil || req.XMLName != (xml.Name{Space: nsDAV, Local: "propfind"}) {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid PROPFIND body"})
+		return
+	}
+	wanted := req.names()
+	children := c.GetHeader("Depth") != "0"
+
+	ms := newMultistatus()
+	switch kind {
+	case targetRoot:
+		ms.response(Root+"/", rootProps(), wanted)
+	case targetPrincipal:
+		ms.response(principalPath, principalProps(), wanted)
+	case targetHome:
+		ms.response(homePath, homeProps(), wanted)
+		if children {
+			cols, err := h.service.Collections(userID)
+			if err != nil {
+				writeError(c, err)
+				return
+			}
+			for _, col := range cols {
+				ms.response(collectionHref(col.Name), collectionProps(col), wanted)
+			}
+		}
+	case targetCollection:
+		col, err := h.service.Collection(userID, collection)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		ms.response(collectionHref(col.Name), collectionProps(col), wanted)
+		if children {
+			objs, err := h.service.Objects(userID, collection)
+			if err != nil {
+				writeError(c, err)
+				return
+			}
+			for _, obj := range objs {
+				ms.response(objectHref(collection, obj.Name), objectProps(obj), wanted)
+			}
+		}
+	case targetObject:
+		obj, err := h.service.Object(userID, collection, name)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		ms.response(objectHref(collection, obj.Name), objectProps(obj), wanted)
+	}
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", ms.bytes())
+}
+
+func (h *CalDAVHandler) report(c *gin.Context, userID int, collection string) {
+	req, err := parseRequest(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid REPORT body"})
+		return
+	}
+	wanted := req.names()
+
+	ms := newMultistatus()
+	switch req.XMLName {
+	case xml.Name{Space: nsCalDAV, Local: "calendar-query"}:
+		if req.todosOnly() {
+			objs, err := h.service.Objects(userID, collection)
+			if err != nil {
+				writeError(c, err)
+				return
+			}
+			for _, obj := range objs {
+				ms.response(objectHref(collection, obj.Name), objectProps(obj), wanted)
+			}
+		} else if _, err := h.service.Collection(userID, collection); err != nil {
+			writeError(c, err)
+			return
+		}
+	case xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}:
+		if _, err := h.service.Collection(userID, collection); err != nil {
+			writeError(c, err)
+			return
+		}
+		for _, href := range req.Hrefs {
+			name, ok := objectName(href, collection)
+			if !ok {
+				ms.notFound(href)
+				continue
+			}
+			obj, err := h.service.Object(userID, collection, name)
+			if errors.Is(err, caldav_service.ErrNotFound) {
+				ms.notFound(href)
+				continue
+			}
+			if err != nil {
+				writeError(c, err)
+				return
+			}
+			ms.response(href, objectProps(obj), wanted)
+		}
+	case xml.Name{Space: nsDAV, Local: "sync-collection"}:
+		changes, err := h.service.Changes(userID, collection, req.SyncToken)
+		if err != nil {
+			writeError(c, err)
+			return
+		}
+		for _, obj := range changes.Updated {
+			ms.response(objectHref(collection, obj.Name), objectProps(obj), wanted)
+		}
+		for _, name := range changes.Removed {
+			ms.notFound(objectHref(collection, name))
+		}
+		ms.syncToken(changes.SyncToken)
+	default:
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", davError(xml.Name{Space: nsDAV, Local: "supported-report"}))
+		return
+	}
+	c.Data(http.StatusMultiStatus, "application/xml; charset=utf-8", ms.bytes())
+}
+
+func (h *CalDAVHandler) get(c *gin.Context, userID int, collection, name string) {
+	obj, err := h.service.Object(userID, collection, name)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("ETag", obj.ETag)
+	c.Header("Last-Modified", obj.Modified.UTC().Format(http.TimeFormat))
+	if match := c.GetHeader("If-None-Match"); match != "" && (match == "*" || strings.Contains(match, obj.ETag)) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, obj.Data)
+}
+
+func (h *CalDAVHandler) put(c *gin.Context, userID int, collection, name string) {
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxObjectBody))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "calendar object is too large"})
+		return
+	}
+
+	_, created, err := h.service.Put(userID, collection, name, data, conditions(c))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	// No ETag: the stored task doesn't keep every property of the
+	// object, so clients must fetch it again.
+	if created {
+		c.Header("Location", objectHref(collection, name))
+		c.Status(http.StatusCreated)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// parsePath splits a path under Root into the resource it names.
+func parsePath(p string) (kind target, collection, name string, ok bool) {
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "":
+		return targetRoot, "", "", true
+	case len(parts) == 1 && parts[0] == "principal":
+		return targetPrincipal, "", "", true
+	case parts[0] != "calendars":
+		return 0, "", "", false
+	case len(parts) == 1:
+		return targetHome, "", "", true
+	case len(parts) == 2:
+		return targetCollection, parts[1], "", true
+	case len(parts) == 3 && !strings.HasSuffix(p, "/"):
+		return targetObject, parts[1], parts[2], true
+	}
+	return 0, "", "", false
+}
+
+// objectName returns the name of the object in the collection a
+// multiget href points at. Hrefs may be absolute URLs.
+func objectName(href, collection string) (string, bool) {
+	u, err := url.Parse(href)
+	if err != nil {
+		return "", false
+	}
+	name, ok := strings.CutPrefix(u.Path, collectionHref(collection))
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", false
+	}
+	return name, true
+}
+
+func collectionHref(name string) string {
+	return homePath + url.PathEscape(name) + "/"
+}
+
+func objectHref(collection, name string) string {
+	return collectionHref(collection) + url.PathEscape(name)
+}
+
+func conditions(c *gin.Context) caldav_service.Conditions {
+	return caldav_service.Conditions{IfMatch: c.GetHeader("If-Match"), IfNoneMatch: c.GetHeader("If-None-Match")}
+}
+
+func rootProps() []property {
+	return []property{
+		prop(nsDAV, "resourcetype", "<d:collection/>"),
+		prop(nsDAV, "current-user-principal", href(principalPath)),
+	}
+}
+
+func principalProps() []property {
+	return []property{
+		prop(nsDAV, "resourcetype", "<d:collection/><d:principal/>"),
+		prop(nsDAV, "current-user-principal", href(principalPath)),
+		prop(nsDAV, "principal-URL", href(principalPath)),
+		prop(nsCalDAV, "calendar-home-set", href(homePath)),
+	}
+}
+
+func homeProps() []property {
+	return []property{
+		prop(nsDAV, "resourcetype", "<d:collection/>"),
+		prop(nsDAV, "current-user-principal", href(principalPath)),
+		prop(nsDAV, "owner", href(principalPath)),
+	}
+}
+
+func collectionProps(col caldav_service.Collection) []property {
+	return []property{
+		prop(nsDAV, "resourcetype", "<d:collection/><c:calendar/>"),
+		prop(nsDAV, "displayname", escape(col.DisplayName)),
+		prop(nsDAV, "current-user-principal", href(principalPath)),
+		prop(nsDAV, "owner", href(principalPath)),
+		prop(nsDAV, "current-user-privilege-set", "<d:privilege><d:read/></d:privilege><d:privilege><d:write/></d:privilege>"),
+		prop(nsDAV, "supported-report-set", supportedReports),
+		prop(nsDAV, "sync-token", escape(col.SyncToken)),
+		prop(nsCS, "getctag", escape(col.SyncToken)),
+		prop(nsCalDAV, "supported-calendar-component-set", `<c:comp name="VTODO"/>`),
+	}
+}
+
+func objectProps(obj caldav_service.Object) []property {
+	return []property{
+		prop(nsDAV, "resourcetype", ""),
+		prop(nsDAV, "getetag", escape(obj.ETag)),
+		prop(nsDAV, "getcontenttype", contentType),
+		prop(nsDAV, "getcontentlength", strconv.Itoa(len(obj.Data))),
+		prop(nsDAV, "getlastmodified", obj.Modified.UTC().Format(http.TimeFormat)),
+		{name: calendarData, value: escape(string(obj.Data)), hidden: true},
+	}
+}
+
+const supportedReports = "<d:supported-report><d:report><c:calendar-query/></d:report></d:supported-report>" +
+	"<d:supported-report><d:report><c:calendar-multiget/></d:report></d:supported-report>" +
+	"<d:supported-report><d:report><d:sync-collection/></d:report></d:supported-report>"
+
+func href(path string) string {
+	return "<d:href>" + escape(path) + "</d:href>"
+}
+
+func methodNotAllowed(c *gin.Context) {
+	c.Header("Allow", strings.Join(Methods, ", "))
+	c.JSON(http.StatusMethodNotAllowed, common.ErrorResponse{Message: "method not allowed"})
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, caldav_service.ErrNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, caldav_service.ErrPreconditionFailed):
+		c.JSON(http.StatusPreconditionFailed, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, caldav_service.ErrInvalidSyncToken):
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", davError(xml.Name{Space: nsDAV, Local: "valid-sync-token"}))
+	case errors.Is(err, caldav_service.ErrUnsupportedComponent):
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", davError(xml.Name{Space: nsCalDAV, Local: "supported-calendar-component"}))
+	case errors.Is(err, caldav_service.ErrUIDConflict):
+		c.Data(http.StatusForbidden, "application/xml; charset=utf-8", davError(xml.Name{Space: nsCalDAV, Local: "no-uid-conflict"}))
+	case errors.Is(err, caldav_service.ErrInvalidCalendarData):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, task_service.ErrWIPLimitReached):
+		c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,15 @@
+package caldav_handler
+
+import "github.com/gin-gonic/gin"
+
+type CalDAVHandlerInterface interface {
+	// BasicAuthMiddleware authenticates DAV requests by personal access
+	// token, sent as the password of HTTP Basic authentication.
+	BasicAuthMiddleware() gin.HandlerFunc
+
+	// WellKnown handles GET and PROPFIND /.well-known/caldav
+	WellKnown(c *gin.Context)
+
+	// ServeDAV handles OPTIONS, PROPFIND, REPORT, GET, HEAD, PUT and DELETE /dav/*path
+	ServeDAV(c *gin.Context)
+}
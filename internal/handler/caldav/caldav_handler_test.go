@@ -0,0 +1,440 @@
+package caldav_handler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	accesstoken_service "taskflow/internal/service/accesstoken"
+	caldav_service "taskflow/internal/service/caldav"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+const secret = "tfp_0123456789abcdef0123456789abcdef01234567"
+
+var (
+	modified = time.Date(2025, 9, 8, 11, 0, 0, 0, time.UTC)
+	inbox    = caldav_service.Collection{Name: "inbox", DisplayName: "Tasks", SyncToken: "http://taskflow/ns/sync/1-2"}
+	object   = caldav_service.Object{Name: "task-7.ics", ETag: `"abc"`, Modified: modified, Data: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")}
+)
+
+func setupRouter(svc *caldav_service.CalDAVServiceMock) *gin.Engine {
+	tokens := new(accesstoken_service.AccessTokenServiceMock)
+	tokens.On("Authenticate", secret).Return(1, nil)
+	tokens.On("Authenticate", mock.Anything).Return(0, accesstoken_service.ErrInvalidToken)
+	handler := NewCalDAVHandler(svc, tokens)
+
+	router := setupGin()
+	router.GET("/.well-known/caldav", handler.WellKnown)
+	dav := router.Group(Root, handler.BasicAuthMiddleware())
+	for _, m := range Methods {
+		dav.Handle(m, "/*path", handler.ServeDAV)
+	}
+	return router
+}
+
+func serve(router *gin.Engine, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.SetBasicAuth("me@example.com", secret)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCalDAVHandler_Auth(t *testing.T) {
+	router := setupRouter(new(caldav_service.CalDAVServiceMock))
+
+	req := httptest.NewRequest("PROPFIND", "/dav/", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), `Basic realm="TaskFlow"`)
+
+	req = httptest.NewRequest("PROPFIND", "/dav/", nil)
+	req.SetBasicAuth("me@example.com", "tfp_wrong")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	req = httptest.NewRequest(http.MethodOptions, "/dav/calendars/inbox/", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code, "OPTIONS needs no credentials")
+	assert.Equal(t, "1, 3, calendar-access", w.Header().Get("DAV"))
+
+	req = httptest.NewRequest(http.MethodGet, "/.well-known/caldav", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/dav/", w.Header().Get("Location"))
+}
+
+func TestCalDAVHandler_Propfind(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		depth          string
+		body           string
+		setupMock      func(m *caldav_service.CalDAVServiceMock)
+		expectedStatus int
+		contains       []string
+		excludes       []string
+	}{
+		{
+			name:           "root",
+			path:           "/dav/",
+			depth:          "0",
+			body:           `<?xml version="1.0"?><propfind xmlns="DAV:"><prop><current-user-principal/></prop></propfind>`,
+			setupMock:      func(m *caldav_service.CalDAVServiceMock) {},
+			expectedStatus: http.StatusMultiStatus,
+			contains:       []string{`<d:response><d:href>/dav/</d:href><d:propstat><d:prop><d:current-user-principal><d:href>/dav/principal/</d:href></d:current-user-principal></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`},
+		},
+		{
+			name:           "principal",
+			path:           "/dav/principal/",
+			depth:          "0",
+			body:           `<propfind xmlns="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:A="http://apple.com/ns/ical/"><prop><C:calendar-home-set/><A:calendar-color/></prop></propfind>`,
+			setupMock:      func(m *caldav_service.CalDAVServiceMock) {},
+			expectedStatus: http.StatusMultiStatus,
+			contains: []string{
+				`<c:calendar-home-set><d:href>/dav/calendars/</d:href></c:calendar-home-set>`,
+				`<d:propstat><d:prop><x:calendar-color xmlns:x="http://apple.com/ns/ical/"/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>`,
+			},
+		},
+		{
+			name:  "home lists calendars",
+			path:  "/dav/calendars/",
+			depth: "1",
+			body:  `<propfind xmlns="DAV:"><prop><resourcetype/><displayname/><sync-token/></prop></propfind>`,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Collections", 1).Return([]caldav_service.Collection{inbox, {Name: "project-2", DisplayName: "Work & play", SyncToken: inbox.SyncToken}}, nil)
+			},
+			expectedStatus: http.StatusMultiStatus,
+			contains: []string{
+				`<d:href>/dav/calendars/inbox/</d:href><d:propstat><d:prop><d:resourcetype><d:collection/><c:calendar/></d:resourcetype><d:displayname>Tasks</d:displayname><d:sync-token>http://taskflow/ns/sync/1-2</d:sync-token>`,
+				`<d:href>/dav/calendars/project-2/</d:href>`,
+				`<d:displayname>Work &amp; play</d:displayname>`,
+			},
+		},
+		{
+			name:  "calendar with objects, allprop",
+			path:  "/dav/calendars/inbox/",
+			depth: "1",
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Collection", 1, "inbox").Return(inbox, nil)
+				m.On("Objects", 1, "inbox").Return([]caldav_service.Object{object}, nil)
+			},
+			expectedStatus: http.StatusMultiStatus,
+			contains: []string{
+				`<c:supported-calendar-component-set><c:comp name="VTODO"/></c:supported-calendar-component-set>`,
+				`<d:href>/dav/calendars/inbox/task-7.ics</d:href>`,
+				`<d:getetag>&#34;abc&#34;</d:getetag>`,
+				`<d:getlastmodified>Mon, 08 Sep 2025 11:00:00 GMT</d:getlastmodified>`,
+			},
+			excludes: []string{"calendar-data"},
+		},
+		{
+			name:  "calendar without children",
+			path:  "/dav/calendars/inbox/",
+			depth: "0",
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Collection", 1, "inbox").Return(inbox, nil)
+			},
+			expectedStatus: http.StatusMultiStatus,
+			excludes:       []string{"task-7.ics"},
+		},
+		{
+			name:  "unknown calendar",
+			path:  "/dav/calendars/project-9/",
+			depth: "0",
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Collection", 1, "project-9").Return(caldav_service.Collection{}, caldav_service.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "invalid body",
+			path:           "/dav/",
+			body:           `<propfind`,
+			setupMock:      func(m *caldav_service.CalDAVServiceMock) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown path",
+			path:           "/dav/addressbooks/",
+			setupMock:      func(m *caldav_service.CalDAVServiceMock) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(caldav_service.CalDAVServiceMock)
+			tt.setupMock(m)
+			router := setupRouter(m)
+
+			w := serve(router, "PROPFIND", tt.path, tt.body, map[string]string{"Depth": tt.depth})
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			for _, s := range tt.contains {
+				assert.Contains(t, w.Body.String(), s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, w.Body.String(), s)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCalDAVHandler_Report(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(m *caldav_service.CalDAVServiceMock)
+		expectedStatus int
+		contains       []string
+		excludes       []string
+	}{
+		{
+			name: "calendar-query",
+			body: `<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"><D:prop><D:getetag/><C:calendar-data/></D:prop>` +
+				`<C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VTODO"/></C:comp-filter></C:filter></C:calendar-query>`,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Objects", 1, "inbox").Return([]caldav_service.Object{object}, nil)
+			},
+			expectedStatus: http.StatusMultiStatus,
+			contains:       []string{`<c:calendar-data>BEGIN:VCALENDAR&#xD;&#xA;END:VCALENDAR&#xD;&#xA;</c:calendar-data>`},
+		},
+		{
+			name: "calendar-query for events",
+			body: `<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"><D:prop><D:getetag/></D:prop>` +
+				`<C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VEVENT"/></C:comp-filter></C:filter></C:calendar-query>`,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Collection", 1, "inbox").Return(inbox, nil)
+			},
+			expectedStatus: http.StatusMultiStatus,
+			excludes:       []string{"<d:response>"},
+		},
+		{
+			name: "calendar-multiget",
+			body: `<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav"><D:prop><D:getetag/></D:prop>` +
+				`<D:href>/dav/calendars/inbox/task-7.ics</D:href><D:href>https://example.com/dav/calendars/inbox/gone.ics</D:href><D:href>/dav/calendars/project-2/x.ics</D:href></C:calendar-multiget>`,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Collection", 1, "inbox").Return(inbox, nil)
+				m.On("Object", 1, "inbox", "task-7.ics").Return(object, nil)
+				m.On("Object", 1, "inbox", "gone.ics").Return(caldav_service.Object{}, caldav_service.ErrNotFound)
+			},
+			expectedStatus: http.StatusMultiStatus,
+			contains: []string{
+				`<d:href>/dav/calendars/inbox/task-7.ics</d:href><d:propstat><d:prop><d:getetag>&#34;abc&#34;</d:getetag>`,
+				`<d:response><d:href>https://example.com/dav/calendars/inbox/gone.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>`,
+				`<d:response><d:href>/dav/calendars/project-2/x.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>`,
+			},
+		},
+		{
+			name: "sync-collection",
+			body: `<D:sync-collection xmlns:D="DAV:"><D:sync-token>http://taskflow/ns/sync/1-1</D:sync-token><D:sync-level>1</D:sync-level><D:prop><D:getetag/></D:prop></D:sync-collection>`,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Changes", 1, "inbox", "http://taskflow/ns/sync/1-1").Return(caldav_service.Changes{
+					Updated:   []caldav_service.Object{object},
+					Removed:   []string{"gone.ics"},
+					SyncToken: "http://taskflow/ns/sync/2-2",
+				}, nil)
+			},
+			expectedStatus: http.StatusMultiStatus,
+			contains: []string{
+				`<d:href>/dav/calendars/inbox/task-7.ics</d:href>`,
+				`<d:response><d:href>/dav/calendars/inbox/gone.ics</d:href><d:status>HTTP/1.1 404 Not Found</d:status></d:response>`,
+				`<d:sync-token>http://taskflow/ns/sync/2-2</d:sync-token></d:multistatus>`,
+			},
+		},
+		{
+			name: "sync-collection with an expired token",
+			body: `<D:sync-collection xmlns:D="DAV:"><D:sync-token>http://taskflow/ns/sync/1-1</D:sync-token><D:prop/></D:sync-collection>`,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Changes", 1, "inbox", mock.Anything).Return(caldav_service.Changes{}, caldav_service.ErrInvalidSyncToken)
+			},
+			expectedStatus: http.StatusForbidden,
+			contains:       []string{`<d:valid-sync-token/>`},
+		},
+		{
+			name:           "unsupported report",
+			body:           `<C:free-busy-query xmlns:C="urn:ietf:params:xml:ns:caldav"/>`,
+			setupMock:      func(m *caldav_service.CalDAVServiceMock) {},
+			expectedStatus: http.StatusForbidden,
+			contains:       []string{`<d:supported-report/>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(caldav_service.CalDAVServiceMock)
+			tt.setupMock(m)
+			router := setupRouter(m)
+
+			w := serve(router, "REPORT", "/dav/calendars/inbox/", tt.body, map[string]string{"Depth": "1"})
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			for _, s := range tt.contains {
+				assert.Contains(t, w.Body.String(), s)
+			}
+			for _, s := range tt.excludes {
+				assert.NotContains(t, w.Body.String(), s)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCalDAVHandler_Objects(t *testing.T) {
+	data := "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nUID:x\r\nSUMMARY:Milk\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		body            string
+		headers         map[string]string
+		setupMock       func(m *caldav_service.CalDAVServiceMock)
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			path:   "/dav/calendars/inbox/task-7.ics",
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Object", 1, "inbox", "task-7.ics").Return(object, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedHeaders: map[string]string{"ETag": `"abc"`, "Content-Type": "text/calendar; charset=utf-8; component=vtodo"},
+		},
+		{
+			name:    "get unchanged",
+			method:  http.MethodGet,
+			path:    "/dav/calendars/inbox/task-7.ics",
+			headers: map[string]string{"If-None-Match": `"abc"`},
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Object", 1, "inbox", "task-7.ics").Return(object, nil)
+			},
+			expectedStatus: http.StatusNotModified,
+		},
+		{
+			name:           "get a calendar",
+			method:         http.MethodGet,
+			path:           "/dav/calendars/inbox/",
+			setupMock:      func(m *caldav_service.CalDAVServiceMock) {},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:    "put new",
+			method:  http.MethodPut,
+			path:    "/dav/calendars/inbox/x.ics",
+			body:    data,
+			headers: map[string]string{"If-None-Match": "*"},
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Put", 1, "inbox", "x.ics", []byte(data), caldav_service.Conditions{IfNoneMatch: "*"}).Return(object, true, nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedHeaders: map[string]string{"ETag": "", "Location": "/dav/calendars/inbox/x.ics"},
+		},
+		{
+			name:    "put existing",
+			method:  http.MethodPut,
+			path:    "/dav/calendars/inbox/x.ics",
+			body:    data,
+			headers: map[string]string{"If-Match": `"abc"`},
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Put", 1, "inbox", "x.ics", []byte(data), caldav_service.Conditions{IfMatch: `"abc"`}).Return(object, false, nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "put stale",
+			method: http.MethodPut,
+			path:   "/dav/calendars/inbox/x.ics",
+			body:   data,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Put", 1, "inbox", "x.ics", mock.Anything, mock.Anything).Return(caldav_service.Object{}, false, caldav_service.ErrPreconditionFailed)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:   "put an event",
+			method: http.MethodPut,
+			path:   "/dav/calendars/inbox/x.ics",
+			body:   data,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Put", 1, "inbox", "x.ics", mock.Anything, mock.Anything).Return(caldav_service.Object{}, false, caldav_service.ErrUnsupportedComponent)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "put invalid",
+			method: http.MethodPut,
+			path:   "/dav/calendars/inbox/x.ics",
+			body:   data,
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Put", 1, "inbox", "x.ics", mock.Anything, mock.Anything).Return(caldav_service.Object{}, false, caldav_service.ErrInvalidCalendarData)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   "/dav/calendars/project-2/task-8.ics",
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Delete", 1, "project-2", "task-8.ics", caldav_service.Conditions{}).Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:   "delete missing",
+			method: http.MethodDelete,
+			path:   "/dav/calendars/inbox/gone.ics",
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Delete", 1, "inbox", "gone.ics", caldav_service.Conditions{}).Return(caldav_service.ErrNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "delete fails",
+			method: http.MethodDelete,
+			path:   "/dav/calendars/inbox/task-7.ics",
+			setupMock: func(m *caldav_service.CalDAVServiceMock) {
+				m.On("Delete", 1, "inbox", "task-7.ics", caldav_service.Conditions{}).Return(errors.New("db error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(caldav_service.CalDAVServiceMock)
+			tt.setupMock(m)
+			router := setupRouter(m)
+
+			w := serve(router, tt.method, tt.path, tt.body, tt.headers)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			for k, v := range tt.expectedHeaders {
+				assert.Equal(t, v, w.Header().Get(k), k)
+			}
+			m.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package caldav_handler
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+// prefixes are the namespace prefixes declared on every response.
+var prefixes = map[string]string{nsDAV: "d", nsCalDAV: "c", nsCS: "cs"}
+
+var calendarData = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+
+// property is a WebDAV property of a resource, with its value as XML.
+type property struct {
+	name  xml.Name
+	value string
+	// hidden properties are only returned when asked for by name.
+	hidden bool
+}
+
+func prop(space, local, value string) property {
+	return property{name: xml.Name{Space: space, Local: local}, value: value}
+}
+
+// davRequest is the body of a PROPFIND or REPORT request. The fields of
+// the reports this server supports are merged, and the root element tells
+// them apart.
+type davRequest struct {
+	XMLName   xml.Name
+	AllProp   *struct{}   `xml:"DAV: allprop"`
+	Prop      *propNames  `xml:"DAV: prop"`
+	Hrefs     []string    `xml:"DAV: href"`
+	SyncToken string      `xml:"DAV: sync-token"`
+	Filter    *compFilter `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+type propNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+type compFilter struct {
+	Name    string       `xml:"name,attr"`
+	Filters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// parseRequest decodes a request body. An empty body is an allprop
+// PROPFIND.
+func parseRequest(r io.Reader) (*davRequest, error) {
+	var req davRequest
+	if err := xml.NewDecoder(r).Decode(&req); err != nil {
+		if err == io.EOF {
+			return &davRequest{XMLName: xml.Name{Space: nsDAV, Local: "propfind"}}, nil
+		}
+		return nil, err
+	}
+	return &req, nil
+}
+
+// names returns the requested properties, or nil for all of them.
+func (r *davRequest) names() []xml.Name {
+	if r.Prop == nil {
+		return nil
+	}
+	names := make([]xml.Name, 0, len(r.Prop.Names))
+	for _, n := range r.Prop.Names {
+		names = append(names, n.XMLName)
+	}
+	return names
+}
+
+// todosOnly reports whether the filter of a calendar-query can match
+// to-dos. Filters on properties and time ranges aren't applied; clients
+// get a superset of what they asked for.
+func (r *davRequest) todosOnly() bool {
+	if r.Filter == nil {
+		return true
+	}
+	var match func(f compFilter, depth int) bool
+	match = func(f compFilter, depth int) bool {
+		switch depth {
+		case 0:
+			if f.Name != "VCALENDAR" {
+				return false
+			}
+		case 1:
+			if f.Name != "VTODO" {
+				return false
+			}
+		}
+		for _, sub := range f.Filters {
+			if !match(sub, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	for _, f := range r.Filter.Filters {
+		if !match(f, 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// multistatus builds a 207 Multi-Status response body.
+type multistatus struct {
+	buf bytes.Buffer
+}
+
+func newMultistatus() *multistatus {
+	m := &multistatus{}
+	m.buf.WriteString(xml.Header)
+	fmt.Fprintf(&m.buf, `<d:multistatus xmlns:d=%q xmlns:c=%q xmlns:cs=%q>`, nsDAV, nsCalDAV, nsCS)
+	return m
+}
+
+// response adds a resource with the wanted properties, or all of its
+// visible ones when wanted is nil. Properties it doesn't have are
+// reported as not found.
+func (m *multistatus) response(href string, props []property, wanted []xml.Name) {
+	var found []property
+	var missing []xml.Name
+	if wanted == nil {
+		for _, p := range props {
+			if !p.hidden {
+				found = append(found, p)
+			}
+		}
+	} else {
+		for _, name := range wanted {
+			if p, ok := lookup(props, name); ok {
+				found = append(found, p)
+			} else {
+				missing = append(missing, name)
+			}
+		}
+	}
+
+	m.buf.WriteString("<d:response><d:href>" + escape(href) + "</d:href>")
+	if len(found) > 0 {
+		m.buf.WriteString("<d:propstat><d:prop>")
+		for _, p := range found {
+			m.buf.WriteString(element(p.name, p.value))
+		}
+		m.buf.WriteString("</d:prop>" + status(http.StatusOK) + "</d:propstat>")
+	}
+	if len(missing) > 0 {
+		m.buf.WriteString("<d:propstat><d:prop>")
+		for _, name := range missing {
+			m.buf.WriteString(element(name, ""))
+		}
+		m.buf.WriteString("</d:prop>" + status(http.StatusNotFound) + "</d:propstat>")
+	}
+	m.buf.WriteString("</d:response>")
+}
+
+// notFound adds a resource that doesn't exist, or no longer does.
+func (m *multistatus) notFound(href string) {
+	m.buf.WriteString("<d:response><d:href>" + escape(href) + "</d:href>" + status(http.StatusNotFound) + "</d:response>")
+}
+
+func (m *multistatus) syncToken(token string) {
+	m.buf.WriteString("<d:sync-token>" + escape(token) + "</d:sync-token>")
+}
+
+func (m *multistatus) bytes() []byte {
+	m.buf.WriteString("</d:multistatus>")
+	return m.buf.Bytes()
+}
+
+// davError is the body of an error response naming the precondition that
+// failed, such as <d:valid-sync-token/>.
+func davError(name xml.Name) []byte {
+	return []byte(fmt.Sprintf(`%s<d:error xmlns:d=%q xmlns:c=%q>%s</d:error>`, xml.Header, nsDAV, nsCalDAV, element(name, "")))
+}
+
+func lookup(props []property, name xml.Name) (property, bool) {
+	for _, p := range props {
+		if p.name == name {
+			return p, true
+		}
+	}
+	return property{}, false
+}
+
+// element renders an element, declaring its namespace when it isn't one
+// of the prefixed ones.
+func element(name xml.Name, inner string) string {
+	tag, attr := name.Local, ""
+	if p, ok := prefixes[name.Space]; ok {
+		tag = p + ":" + name.Local
+	} else if name.Space != "" {
+		tag = "x:" + name.Local
+		attr = ` xmlns:x="` + escape(name.Space) + `"`
+	}
+	if inner == "" {
+		return "<" + tag + attr + "/>"
+	}
+	return "<" + tag + attr + ">" + inner + "</" + tag + ">"
+}
+
+func status(code int) string {
+	return fmt.Sprintf("<d:status>HTTP/1.1 %d %s</d:status>", code, http.StatusText(code))
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,226 @@
+package calendar_handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	calendar_service "taskflow/internal/service/calendar"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxImportBody bounds an uploaded .ics file, with room for form overhead.
+const maxImportBody = 5 << 20
+
+type CalendarHandler struct {
+	service  calendar_service.CalendarServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewCalendarHandler(s calendar_service.CalendarServiceInterface, ua auth.UserAuthInterface) *CalendarHandler {
+	return &CalendarHandler{service: s, userAuth: ua}
+}
+
+var _ CalendarHandlerInterface = (*CalendarHandler)(nil)
+
+// CreateFeed godoc
+// @Summary Create a calendar feed
+// @Description Create a secret iCalendar feed URL of your tasks with due dates, optionally of one project, for calendar apps to subscribe to.
+// @Tags calendar
+// @Accept json
+// @Produce json
+// @Param feed body dto.CreateCalendarFeedRequest true "Feed settings"
+// @Success 201 {object} dto.CalendarFeedResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Project not found"
+// @Failure 409 {object} common.ErrorResponse "Too many feeds"
+// @Router /calendar-feeds [post]
+func (h *CalendarHandler) CreateFeed(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.CreateCalendarFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateFeed(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListFeeds godoc
+// @Summary List calendar feeds
+// @Description Returns the user's calendar feeds
+// @Tags calendar
+// @Produce json
+// @Success 200 {object} dto.ListCalendarFeedsResponse
+// @Router /calendar-feeds [get]
+func (h *CalendarHandler) ListFeeds(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.ListFeeds(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteFeed godoc
+// @Summary Delete a calendar feed
+// @Description Delete a calendar feed. Its URL stops working.
+// @Tags calendar
+// @Produce json
+// @Param id path int true "Feed ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteCalendarFeedResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Feed not found"
+// @Router /calendar-feeds/{id} [delete]
+func (h *CalendarHandler) DeleteFeed(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid feed ID"})
+		return
+	}
+
+	if err := h.service.DeleteFeed(userID.(int), id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteCalendarFeedResponse{Message: "Calendar feed deleted successfully"})
+}
+
+// Feed godoc
+// @Summary Get a calendar feed
+// @Description Serves a feed as iCalendar. No authentication: the token in the URL is the secret.
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, followed by .ics"
+// @Success 200 {string} string "iCalendar data"
+// @Failure 404 {object} common.ErrorResponse "Unknown feed"
+// @Router /calendar/{token}.ics [get]
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("token"), ".ics")
+	if !ok {
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+		return
+	}
+
+	cal, err := h.service.Feed(token)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": "taskflow.ics"}))
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Status(http.StatusOK)
+	if err := ical.NewEncoder(c.Writer).Encode(cal); err != nil {
+		c.Error(err)
+	}
+}
+
+// Import godoc
+// @Summary Import tasks from iCalendar
+// @Description Create tasks from the VTODO entries of an .ics file, uploaded as multipart/form-data or sent as the text/calendar request body. Entries that don't make valid tasks are skipped and reported.
+// @Tags calendar
+// @Accept multipart/form-data,text/calendar
+// @Produce json
+// @Param file formData file false ".ics file"
+// @Success 201 {object} dto.CalendarImportResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid file"
+// @Failure 413 {object} common.ErrorResponse "File too large"
+// @Router /calendar/import [post]
+func (h *CalendarHandler) Import(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBody)
+	var body io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			if isTooLarge(err) {
+				c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "file is too large"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "file is required"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "could not read file"})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	resp, err := h.service.Import(userID.(int), body)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func isTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, calendar_service.ErrUnknownFeed):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, calendar_service.ErrTooManyFeeds),
+		errors.Is(err, task_service.ErrWIPLimitReached):
+		c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+	case isTooLarge(err):
+		c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "file is too large"})
+	case errors.Is(err, calendar_service.ErrInvalidUser),
+		errors.Is(err, calendar_service.ErrInvalidFile),
+		errors.Is(err, calendar_service.ErrNoTasks),
+		errors.Is(err, calendar_service.ErrTooManyTasks):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,20 @@
+package calendar_handler
+
+import "github.com/gin-gonic/gin"
+
+type CalendarHandlerInterface interface {
+	// CreateFeed handles POST /api/calendar-feeds
+	CreateFeed(c *gin.Context)
+
+	// ListFeeds handles GET /api/calendar-feeds
+	ListFeeds(c *gin.Context)
+
+	// DeleteFeed handles DELETE /api/calendar-feeds/:id
+	DeleteFeed(c *gin.Context)
+
+	// Feed handles GET /calendar/:token.ics
+	Feed(c *gin.Context)
+
+	// Import handles POST /api/calendar/import
+	Import(c *gin.Context)
+}
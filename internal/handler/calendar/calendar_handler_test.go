@@ -0,0 +1,246 @@
+package calendar_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	calendar_service "taskflow/internal/service/calendar"
+	"taskflow/pkg/ical"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestCalendarHandler_CreateFeed(t *testing.T) {
+	created := dto.CalendarFeedResponse{ID: 1, Name: "Work", Component: "VEVENT", URL: "/calendar/abc.ics"}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *calendar_service.CalendarServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"name":"Work"}`,
+			setupMock: func() *calendar_service.CalendarServiceMock {
+				m := new(calendar_service.CalendarServiceMock)
+				m.On("CreateFeed", 1, &dto.CreateCalendarFeedRequest{Name: "Work"}).Return(created, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   created,
+		},
+		{
+			name:        "failure - too many",
+			requestBody: `{"name":"Work"}`,
+			setupMock: func() *calendar_service.CalendarServiceMock {
+				m := new(calendar_service.CalendarServiceMock)
+				m.On("CreateFeed", 1, mock.Anything).Return(dto.CalendarFeedResponse{}, calendar_service.ErrTooManyFeeds)
+				return m
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   common.ErrorResponse{Message: calendar_service.ErrTooManyFeeds.Error()},
+		},
+		{
+			name:        "failure - invalid component",
+			requestBody: `{"name":"Work","component":"VJOURNAL"}`,
+			setupMock: func() *calendar_service.CalendarServiceMock {
+				return new(calendar_service.CalendarServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'CreateCalendarFeedRequest.Component' Error:Field validation for 'Component' failed on the 'oneof' tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewCalendarHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/calendar-feeds", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.CreateFeed(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/calendar-feeds", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCalendarHandler_Feed(t *testing.T) {
+	cal := ical.NewCalendar("-//Test//EN")
+	todo := &ical.Component{Name: "VTODO"}
+	todo.Add("UID", "task-1@taskflow")
+	cal.Components = append(cal.Components, todo)
+
+	t.Run("success", func(t *testing.T) {
+		m := new(calendar_service.CalendarServiceMock)
+		m.On("Feed", "abc").Return(cal, nil)
+		router := setupGin()
+		router.GET("/calendar/:token", NewCalendarHandler(m, new(auth.MockUserAuth)).Feed)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/abc.ics", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Test//EN\r\nCALSCALE:GREGORIAN\r\n"+
+			"BEGIN:VTODO\r\nUID:task-1@taskflow\r\nEND:VTODO\r\nEND:VCALENDAR\r\n", w.Body.String())
+		m.AssertExpectations(t)
+	})
+
+	t.Run("failure - unknown token", func(t *testing.T) {
+		m := new(calendar_service.CalendarServiceMock)
+		m.On("Feed", "nope").Return((*ical.Component)(nil), calendar_service.ErrUnknownFeed)
+		router := setupGin()
+		router.GET("/calendar/:token", NewCalendarHandler(m, new(auth.MockUserAuth)).Feed)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/nope.ics", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		m.AssertExpectations(t)
+	})
+
+	t.Run("failure - no extension", func(t *testing.T) {
+		m := new(calendar_service.CalendarServiceMock)
+		router := setupGin()
+		router.GET("/calendar/:token", NewCalendarHandler(m, new(auth.MockUserAuth)).Feed)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/calendar/abc", nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		m.AssertNotCalled(t, "Feed", mock.Anything)
+	})
+}
+
+const importData = "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nSUMMARY:Buy milk\r\nEND:VTODO\r\nEND:VCALENDAR\r\n"
+
+func TestCalendarHandler_Import(t *testing.T) {
+	imported := dto.CalendarImportResponse{Imported: 1, Tasks: []dto.GetTaskResponse{{ID: 3, Task: "Buy milk"}}, Skipped: []dto.CalendarImportSkipped{}}
+	readsData := mock.MatchedBy(func(r io.Reader) bool {
+		data, _ := io.ReadAll(r)
+		return string(data) == importData
+	})
+
+	tests := []struct {
+		name           string
+		body           func(t *testing.T) (string, io.Reader)
+		setupMock      func() *calendar_service.CalendarServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "raw body",
+			body: func(t *testing.T) (string, io.Reader) {
+				return "text/calendar", strings.NewReader(importData)
+			},
+			setupMock: func() *calendar_service.CalendarServiceMock {
+				m := new(calendar_service.CalendarServiceMock)
+				m.On("Import", 1, readsData).Return(imported, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   imported,
+		},
+		{
+			name: "multipart",
+			body: func(t *testing.T) (string, io.Reader) {
+				var buf bytes.Buffer
+				mw := multipart.NewWriter(&buf)
+				fw, err := mw.CreateFormFile("file", "tasks.ics")
+				require.NoError(t, err)
+				_, err = fw.Write([]byte(importData))
+				require.NoError(t, err)
+				require.NoError(t, mw.Close())
+				return mw.FormDataContentType(), &buf
+			},
+			setupMock: func() *calendar_service.CalendarServiceMock {
+				m := new(calendar_service.CalendarServiceMock)
+				m.On("Import", 1, readsData).Return(imported, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   imported,
+		},
+		{
+			name: "failure - missing file",
+			body: func(t *testing.T) (string, io.Reader) {
+				var buf bytes.Buffer
+				mw := multipart.NewWriter(&buf)
+				require.NoError(t, mw.WriteField("name", "tasks"))
+				require.NoError(t, mw.Close())
+				return mw.FormDataContentType(), &buf
+			},
+			setupMock: func() *calendar_service.CalendarServiceMock {
+				return new(calendar_service.CalendarServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "file is required"},
+		},
+		{
+			name: "failure - invalid file",
+			body: func(t *testing.T) (string, io.Reader) {
+				return "text/calendar", strings.NewReader("not a calendar")
+			},
+			setupMock: func() *calendar_service.CalendarServiceMock {
+				m := new(calendar_service.CalendarServiceMock)
+				m.On("Import", 1, mock.Anything).Return(dto.CalendarImportResponse{}, calendar_service.ErrInvalidFile)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: calendar_service.ErrInvalidFile.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewCalendarHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/calendar/import", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.Import(c)
+			})
+
+			contentType, body := tt.body(t)
+			req := httptest.NewRequest(http.MethodPost, "/calendar/import", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
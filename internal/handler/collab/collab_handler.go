@@ -0,0 +1,101 @@
+package collab_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/collab"
+	"taskflow/internal/common"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// maxMessageBytes bounds the size of one client message.
+const maxMessageBytes = 64 << 10
+
+type CollabHandler struct {
+	server   *collab.Server
+	userAuth auth.UserAuthInterface
+}
+
+func NewCollabHandler(server *collab.Server, ua auth.UserAuthInterface) *CollabHandler {
+	return &CollabHandler{server: server, userAuth: ua}
+}
+
+var _ CollabHandlerInterface = (*CollabHandler)(nil)
+
+// Connect godoc
+// @Summary Open the collaboration channel
+// @Description Upgrades to a WebSocket for task and project subscriptions, presence and task commands. Authenticate with an Authorization header, or with an auth message first if the client can't set headers. See the API guide for the message protocol.
+// @Tags collaboration
+// @Param Authorization header string false "Bearer token"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {string} string "Not a WebSocket handshake"
+// @Failure 401 {object} common.ErrorResponse "Invalid token"
+// @Router /collab [get]
+func (h *CollabHandler) Connect(c *gin.Context) {
+	userID := 0
+	var expiresAt time.Time
+	if header := c.GetHeader("Authorization"); header != "" {
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "invalid authorization header format"})
+			return
+		}
+		id, err := h.userAuth.Authenticate(token)
+		if err != nil {
+			writeAuthError(c, err)
+			return
+		}
+		if expiresAt, err = h.userAuth.ExpiresAt(token); err != nil {
+			writeAuthError(c, err)
+			return
+		}
+		userID = id
+	}
+
+	websocket.Server{
+		// Clients authenticate with a token rather than a cookie, so a
+		// page on another origin can't act for the user and the Origin
+		// header isn't checked. Non-browser clients don't send one.
+		Handler: func(ws *websocket.Conn) {
+			ws.MaxPayloadBytes = maxMessageBytes
+			h.server.Serve(conn{ws}, userID, expiresAt)
+		},
+	}.ServeHTTP(c.Writer, c.Request)
+}
+
+func writeAuthError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrInvalidUserID),
+		errors.Is(err, auth.ErrInvalidClaims), errors.Is(err, auth.ErrUserNotFound):
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: "authentication failed"})
+	}
+}
+
+// conn exchanges JSON messages over a WebSocket.
+type conn struct {
+	*websocket.Conn
+}
+
+func (c conn) Receive(v any) error {
+	err := websocket.JSON.Receive(c.Conn, v)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.Is(err, websocket.ErrFrameTooLarge) {
+		return fmt.Errorf("%w: %v", collab.ErrMalformed, err)
+	}
+	return err
+}
+
+func (c conn) Send(v any) error {
+	return websocket.JSON.Send(c.Conn, v)
+}
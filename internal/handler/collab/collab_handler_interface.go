@@ -0,0 +1,8 @@
+package collab_handler
+
+import "github.com/gin-gonic/gin"
+
+type CollabHandlerInterface interface {
+	// Connect handles GET /api/collab
+	Connect(c *gin.Context)
+}
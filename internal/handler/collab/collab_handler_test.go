@@ -0,0 +1,107 @@
+package collab_handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"taskflow/internal/auth"
+	"taskflow/internal/collab"
+	project_service "taskflow/internal/service/project"
+	task_service "taskflow/internal/service/task"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func setupServer(t *testing.T, ua *auth.MockUserAuth) string {
+	cfg := collab.Config{PingInterval: time.Hour, PongWait: time.Hour, RateLimit: 100, RateBurst: 100}
+	server := collab.NewServer(collab.NewHub(), ua, new(task_service.TaskServiceMock), new(project_service.ProjectServiceMock), cfg)
+	h := NewCollabHandler(server, ua)
+
+	r := setupGin()
+	r.GET("/collab", h.Connect)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + "/collab"
+}
+
+func dial(t *testing.T, url string, header http.Header) (*websocket.Conn, error) {
+	cfg, err := websocket.NewConfig(url, "http://localhost/")
+	require.NoError(t, err)
+	cfg.Header = header
+	return websocket.DialConfig(cfg)
+}
+
+func receive(t *testing.T, ws *websocket.Conn) map[string]any {
+	t.Helper()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(time.Second)))
+	var m map[string]any
+	require.NoError(t, websocket.JSON.Receive(ws, &m))
+	return m
+}
+
+func TestCollabHandler_Connect(t *testing.T) {
+	ua := new(auth.MockUserAuth)
+	ua.On("Authenticate", "good").Return(1, nil)
+	ua.On("ExpiresAt", "good").Return(time.Now().Add(time.Hour), nil)
+	url := setupServer(t, ua)
+
+	t.Run("authorization header", func(t *testing.T) {
+		ws, err := dial(t, url, http.Header{"Authorization": {"Bearer good"}})
+		require.NoError(t, err)
+		defer ws.Close()
+
+		assert.Equal(t, map[string]any{"type": "ready", "data": map[string]any{"user_id": float64(1)}}, receive(t, ws))
+
+		require.NoError(t, websocket.JSON.Send(ws, map[string]string{"type": "ping", "ref": "1"}))
+		assert.Equal(t, map[string]any{"type": "pong", "ref": "1"}, receive(t, ws))
+
+		_, err = ws.Write([]byte("{not json"))
+		require.NoError(t, err)
+		m := receive(t, ws)
+		assert.Equal(t, "bad_request", m["code"])
+
+		require.NoError(t, websocket.JSON.Send(ws, map[string]string{"type": "ping", "ref": "2"}))
+		assert.Equal(t, map[string]any{"type": "pong", "ref": "2"}, receive(t, ws), "the connection survives a malformed message")
+	})
+
+	t.Run("auth message", func(t *testing.T) {
+		ws, err := dial(t, url, nil)
+		require.NoError(t, err)
+		defer ws.Close()
+
+		require.NoError(t, websocket.JSON.Send(ws, map[string]string{"type": "auth", "token": "good"}))
+		assert.Equal(t, "ready", receive(t, ws)["type"])
+	})
+}
+
+func TestCollabHandler_Connect_Unauthorized(t *testing.T) {
+	ua := new(auth.MockUserAuth)
+	ua.On("Authenticate", "bad").Return(0, auth.ErrInvalidToken)
+	url := setupServer(t, ua)
+
+	for _, header := range []string{"Bearer bad", "Basic abc"} {
+		_, err := dial(t, url, http.Header{"Authorization": {header}})
+		assert.Error(t, err, header)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "/collab", nil)
+	req.Header.Set("Authorization", "Bearer bad")
+	w := httptest.NewRecorder()
+	h := NewCollabHandler(nil, ua)
+	r := setupGin()
+	r.GET("/collab", h.Connect)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"invalid or expired token"}`, w.Body.String())
+}
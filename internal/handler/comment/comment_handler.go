@@ -0,0 +1,206 @@
+package comment_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	comment_service "taskflow/internal/service/comment"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type CommentHandler struct {
+	service  comment_service.CommentServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewCommentHandler(s comment_service.CommentServiceInterface, ua auth.UserAuthInterface) *CommentHandler {
+	return &CommentHandler{service: s, userAuth: ua}
+}
+
+var _ CommentHandlerInterface = (*CommentHandler)(nil)
+
+// CreateComment godoc
+// @Summary Comment on a task
+// @Description Add a markdown comment to a task. @email mentions are resolved to users.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param comment body dto.CreateCommentRequest true "Comment to create"
+// @Success 201 {object} dto.CommentResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Router /tasks/{id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || taskID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid task ID"})
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateComment(userID.(int), taskID, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListComments godoc
+// @Summary List comments on a task
+// @Description Returns a page of comments on a task, oldest first
+// @Tags comments
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(20)
+// @Success 200 {object} dto.ListCommentsResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Router /tasks/{id}/comments [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || taskID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid task ID"})
+		return
+	}
+
+	page, limit := common.ParsePagination(c.Query("page"), c.Query("limit"))
+
+	resp, err := h.service.ListComments(userID.(int), taskID, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateComment godoc
+// @Summary Edit a comment
+// @Description Edit a comment body. Only the author may edit, and only shortly after posting.
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param commentID path int true "Comment ID" minimum(1) example(1)
+// @Param comment body dto.UpdateCommentRequest true "New comment body"
+// @Success 200 {object} dto.CommentResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 403 {object} common.ErrorResponse "Not allowed to edit"
+// @Failure 404 {object} common.ErrorResponse "Comment not found"
+// @Router /tasks/{id}/comments/{commentID} [patch]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	taskID, commentID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.UpdateComment(userID.(int), taskID, commentID, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment
+// @Description Soft-delete a comment. The author or the task owner may delete it.
+// @Tags comments
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param commentID path int true "Comment ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteCommentResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 403 {object} common.ErrorResponse "Not allowed to delete"
+// @Failure 404 {object} common.ErrorResponse "Comment not found"
+// @Router /tasks/{id}/comments/{commentID} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	taskID, commentID, ok := parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteComment(userID.(int), taskID, commentID); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteCommentResponse{Message: "Comment deleted successfully"})
+}
+
+func parseIDs(c *gin.Context) (taskID int, commentID int, ok bool) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || taskID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid task ID"})
+		return 0, 0, false
+	}
+
+	commentID, err = strconv.Atoi(c.Param("commentID"))
+	if err != nil || commentID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid comment ID"})
+		return 0, 0, false
+	}
+
+	return taskID, commentID, true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, comment_service.ErrNotCommentAuthor),
+		errors.Is(err, comment_service.ErrEditWindowExpired):
+		c.JSON(http.StatusForbidden, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, comment_service.ErrEmptyComment),
+		errors.Is(err, comment_service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
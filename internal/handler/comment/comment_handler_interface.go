@@ -0,0 +1,17 @@
+package comment_handler
+
+import "github.com/gin-gonic/gin"
+
+type CommentHandlerInterface interface {
+	// CreateComment handles POST /api/tasks/:id/comments
+	CreateComment(c *gin.Context)
+
+	// ListComments handles GET /api/tasks/:id/comments
+	ListComments(c *gin.Context)
+
+	// UpdateComment handles PATCH /api/tasks/:id/comments/:commentID
+	UpdateComment(c *gin.Context)
+
+	// DeleteComment handles DELETE /api/tasks/:id/comments/:commentID
+	DeleteComment(c *gin.Context)
+}
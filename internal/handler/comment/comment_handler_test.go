@@ -0,0 +1,239 @@
+package comment_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	comment_service "taskflow/internal/service/comment"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestCommentHandler_CreateComment(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         int
+		taskID         string
+		requestBody    string
+		setupMock      func() *comment_service.CommentServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			userID:      1,
+			taskID:      "10",
+			requestBody: `{"body":"hello"}`,
+			setupMock: func() *comment_service.CommentServiceMock {
+				m := new(comment_service.CommentServiceMock)
+				m.On("CreateComment", 1, 10, mock.MatchedBy(func(r *dto.CreateCommentRequest) bool {
+					return r.Body == "hello"
+				})).Return(dto.CommentResponse{ID: 1, TaskID: 10, UserID: 1, Body: "hello", BodyHTML: "<p>hello</p>", Mentions: []int{}}, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   dto.CommentResponse{ID: 1, TaskID: 10, UserID: 1, Body: "hello", BodyHTML: "<p>hello</p>", Mentions: []int{}},
+		},
+		{
+			name:           "failure - unauthorized",
+			taskID:         "10",
+			requestBody:    `{"body":"hello"}`,
+			setupMock:      func() *comment_service.CommentServiceMock { return new(comment_service.CommentServiceMock) },
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   common.ErrorResponse{Message: "unauthorized"},
+		},
+		{
+			name:           "failure - invalid task ID",
+			userID:         1,
+			taskID:         "abc",
+			requestBody:    `{"body":"hello"}`,
+			setupMock:      func() *comment_service.CommentServiceMock { return new(comment_service.CommentServiceMock) },
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid task ID"},
+		},
+		{
+			name:        "failure - task not found",
+			userID:      1,
+			taskID:      "10",
+			requestBody: `{"body":"hello"}`,
+			setupMock: func() *comment_service.CommentServiceMock {
+				m := new(comment_service.CommentServiceMock)
+				m.On("CreateComment", 1, 10, mock.Anything).Return(dto.CommentResponse{}, gorm.ErrRecordNotFound)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewCommentHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/tasks/:id/comments", func(c *gin.Context) {
+				if tt.userID != 0 {
+					c.Set("userID", tt.userID)
+				}
+				handler.CreateComment(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/tasks/"+tt.taskID+"/comments", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assertJSONEqual(t, tt.expectedBody, w.Body.Bytes())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentHandler_ListComments(t *testing.T) {
+	mockService := new(comment_service.CommentServiceMock)
+	mockService.On("ListComments", 1, 10, 2, 5).Return(dto.ListCommentsResponse{
+		Comments: []dto.CommentResponse{},
+		Page:     2,
+		Limit:    5,
+		Total:    6,
+	}, nil)
+	handler := NewCommentHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.GET("/tasks/:id/comments", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.ListComments(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tasks/10/comments?page=2&limit=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertJSONEqual(t, dto.ListCommentsResponse{Comments: []dto.CommentResponse{}, Page: 2, Limit: 5, Total: 6}, w.Body.Bytes())
+	mockService.AssertExpectations(t)
+}
+
+func TestCommentHandler_UpdateComment(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "edit window expired", serviceErr: comment_service.ErrEditWindowExpired, expectedStatus: http.StatusForbidden},
+		{name: "not the author", serviceErr: comment_service.ErrNotCommentAuthor, expectedStatus: http.StatusForbidden},
+		{name: "unexpected error", serviceErr: errors.New("db error"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(comment_service.CommentServiceMock)
+			mockService.On("UpdateComment", 1, 10, 5, mock.Anything).Return(dto.CommentResponse{ID: 5}, tt.serviceErr)
+			handler := NewCommentHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.PATCH("/tasks/:id/comments/:commentID", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.UpdateComment(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPatch, "/tasks/10/comments/5", bytes.NewBufferString(`{"body":"edit"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentHandler_DeleteComment(t *testing.T) {
+	tests := []struct {
+		name           string
+		commentID      string
+		setupMock      func() *comment_service.CommentServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:      "success",
+			commentID: "5",
+			setupMock: func() *comment_service.CommentServiceMock {
+				m := new(comment_service.CommentServiceMock)
+				m.On("DeleteComment", 1, 10, 5).Return(nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.DeleteCommentResponse{Message: "Comment deleted successfully"},
+		},
+		{
+			name:           "failure - invalid comment ID",
+			commentID:      "0",
+			setupMock:      func() *comment_service.CommentServiceMock { return new(comment_service.CommentServiceMock) },
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid comment ID"},
+		},
+		{
+			name:      "failure - forbidden",
+			commentID: "5",
+			setupMock: func() *comment_service.CommentServiceMock {
+				m := new(comment_service.CommentServiceMock)
+				m.On("DeleteComment", 1, 10, 5).Return(comment_service.ErrNotCommentAuthor)
+				return m
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   common.ErrorResponse{Message: comment_service.ErrNotCommentAuthor.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewCommentHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.DELETE("/tasks/:id/comments/:commentID", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.DeleteComment(c)
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/tasks/10/comments/"+tt.commentID, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assertJSONEqual(t, tt.expectedBody, w.Body.Bytes())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, expected any, actual []byte) {
+	t.Helper()
+
+	expectedBytes, err := json.Marshal(expected)
+	assert.NoError(t, err)
+
+	var want, got any
+	assert.NoError(t, json.Unmarshal(expectedBytes, &want))
+	assert.NoError(t, json.Unmarshal(actual, &got))
+	assert.Equal(t, want, got)
+}
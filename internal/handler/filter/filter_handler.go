@@ -0,0 +1,239 @@
+package filter_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	filter_service "taskflow/internal/service/filter"
+	"taskflow/internal/taskquery"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type FilterHandler struct {
+	service  filter_service.FilterServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewFilterHandler(s filter_service.FilterServiceInterface, ua auth.UserAuthInterface) *FilterHandler {
+	return &FilterHandler{service: s, userAuth: ua}
+}
+
+var _ FilterHandlerInterface = (*FilterHandler)(nil)
+
+// CreateFilter godoc
+// @Summary Save a filter
+// @Description Save a named filter (smart list) with criteria and sort order
+// @Tags filters
+// @Accept json
+// @Produce json
+// @Param filter body dto.CreateFilterRequest true "Filter to create"
+// @Success 201 {object} dto.FilterResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Router /filters [post]
+func (h *FilterHandler) CreateFilter(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.CreateFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateFilter(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListFilters godoc
+// @Summary List saved filters
+// @Description Returns the user's saved filters and built-in smart lists, each with a live count of matching tasks
+// @Tags filters
+// @Produce json
+// @Success 200 {object} dto.ListFiltersResponse
+// @Router /filters [get]
+func (h *FilterHandler) ListFilters(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.ListFilters(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetFilter godoc
+// @Summary Get a saved filter
+// @Description Returns a saved filter with a live count of matching tasks
+// @Tags filters
+// @Produce json
+// @Param id path int true "Filter ID" minimum(1) example(1)
+// @Success 200 {object} dto.FilterResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Filter not found"
+// @Router /filters/{id} [get]
+func (h *FilterHandler) GetFilter(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.GetFilter(userID.(int), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateFilter godoc
+// @Summary Replace a saved filter
+// @Description Replace a saved filter's name, criteria and sort order. Built-in smart lists cannot be changed.
+// @Tags filters
+// @Accept json
+// @Produce json
+// @Param id path int true "Filter ID" minimum(1) example(1)
+// @Param filter body dto.UpdateFilterRequest true "New filter definition"
+// @Success 200 {object} dto.FilterResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 403 {object} common.ErrorResponse "Built-in filter"
+// @Failure 404 {object} common.ErrorResponse "Filter not found"
+// @Router /filters/{id} [put]
+func (h *FilterHandler) UpdateFilter(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.UpdateFilter(userID.(int), id, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteFilter godoc
+// @Summary Delete a saved filter
+// @Description Delete a saved filter. Built-in smart lists cannot be deleted.
+// @Tags filters
+// @Produce json
+// @Param id path int true "Filter ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteFilterResponse
+// @Failure 403 {object} common.ErrorResponse "Built-in filter"
+// @Failure 404 {object} common.ErrorResponse "Filter not found"
+// @Router /filters/{id} [delete]
+func (h *FilterHandler) DeleteFilter(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteFilter(userID.(int), id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteFilterResponse{Message: "Filter deleted successfully"})
+}
+
+// RunFilter godoc
+// @Summary List tasks matching a saved filter
+// @Description Runs a saved filter and returns the matching tasks in the filter's sort order
+// @Tags filters
+// @Produce json
+// @Param id path int true "Filter ID" minimum(1) example(1)
+// @Success 200 {object} dto.ListTasksResponse
+// @Failure 404 {object} common.ErrorResponse "Filter not found"
+// @Router /filters/{id}/tasks [get]
+func (h *FilterHandler) RunFilter(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.RunFilter(userID.(int), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func parseID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid filter ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeError(c *gin.Context, err error) {
+	var qerr *taskquery.Error
+	switch {
+	case errors.As(err, &qerr):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: qerr.Message, Position: qerr.Pos})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, filter_service.ErrBuiltinFilter):
+		c.JSON(http.StatusForbidden, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, filter_service.ErrInvalidRange),
+		errors.Is(err, filter_service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
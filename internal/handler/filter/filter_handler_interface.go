@@ -0,0 +1,23 @@
+package filter_handler
+
+import "github.com/gin-gonic/gin"
+
+type FilterHandlerInterface interface {
+	// CreateFilter handles POST /api/filters
+	CreateFilter(c *gin.Context)
+
+	// ListFilters handles GET /api/filters
+	ListFilters(c *gin.Context)
+
+	// GetFilter handles GET /api/filters/:id
+	GetFilter(c *gin.Context)
+
+	// UpdateFilter handles PUT /api/filters/:id
+	UpdateFilter(c *gin.Context)
+
+	// DeleteFilter handles DELETE /api/filters/:id
+	DeleteFilter(c *gin.Context)
+
+	// RunFilter handles GET /api/filters/:id/tasks
+	RunFilter(c *gin.Context)
+}
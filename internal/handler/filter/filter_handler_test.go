@@ -0,0 +1,188 @@
+package filter_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	filter_service "taskflow/internal/service/filter"
+	"taskflow/internal/taskquery"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestFilterHandler_CreateFilter(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *filter_service.FilterServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"name":"Work","criteria":{"status":"pending","query":"tag:work"},"sort_by":"due_at"}`,
+			setupMock: func() *filter_service.FilterServiceMock {
+				m := new(filter_service.FilterServiceMock)
+				m.On("CreateFilter", 1, mock.MatchedBy(func(r *dto.CreateFilterRequest) bool {
+					return r.Name == "Work" && r.Criteria.Query == "tag:work" && r.SortBy == "due_at"
+				})).Return(dto.FilterResponse{ID: 5, Name: "Work", SortBy: "due_at", Count: 2}, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   dto.FilterResponse{ID: 5, Name: "Work", SortBy: "due_at", Count: 2},
+		},
+		{
+			name:           "failure - bad sort column",
+			requestBody:    `{"name":"Work","sort_by":"user_id"}`,
+			setupMock:      func() *filter_service.FilterServiceMock { return new(filter_service.FilterServiceMock) },
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "failure - query syntax error",
+			requestBody: `{"name":"Work","criteria":{"query":"tag:"}}`,
+			setupMock: func() *filter_service.FilterServiceMock {
+				m := new(filter_service.FilterServiceMock)
+				m.On("CreateFilter", 1, mock.Anything).Return(dto.FilterResponse{},
+					&taskquery.Error{Pos: 5, Message: "expected a value after ':' but found end of query"})
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "expected a value after ':' but found end of query", Position: 5},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewFilterHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/filters", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.CreateFilter(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/filters", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				assertJSONEqual(t, tt.expectedBody, w.Body.Bytes())
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilterHandler_ListFilters(t *testing.T) {
+	mockService := new(filter_service.FilterServiceMock)
+	mockService.On("ListFilters", 1).Return(dto.ListFiltersResponse{
+		Filters: []dto.FilterResponse{{ID: 1, Name: "Today", Builtin: true, SortBy: "due_at", Count: 3}},
+	}, nil)
+	handler := NewFilterHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.GET("/filters", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.ListFilters(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/filters", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertJSONEqual(t, dto.ListFiltersResponse{
+		Filters: []dto.FilterResponse{{ID: 1, Name: "Today", Builtin: true, SortBy: "due_at", Count: 3}},
+	}, w.Body.Bytes())
+	mockService.AssertExpectations(t)
+}
+
+func TestFilterHandler_DeleteFilter(t *testing.T) {
+	tests := []struct {
+		name           string
+		filterID       string
+		serviceErr     error
+		callsService   bool
+		expectedStatus int
+	}{
+		{name: "success", filterID: "5", callsService: true, expectedStatus: http.StatusOK},
+		{name: "built-in", filterID: "1", serviceErr: filter_service.ErrBuiltinFilter, callsService: true, expectedStatus: http.StatusForbidden},
+		{name: "not found", filterID: "9", serviceErr: gorm.ErrRecordNotFound, callsService: true, expectedStatus: http.StatusNotFound},
+		{name: "invalid ID", filterID: "abc", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(filter_service.FilterServiceMock)
+			if tt.callsService {
+				mockService.On("DeleteFilter", 1, mock.Anything).Return(tt.serviceErr)
+			}
+			handler := NewFilterHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.DELETE("/filters/:id", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.DeleteFilter(c)
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/filters/"+tt.filterID, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilterHandler_RunFilter(t *testing.T) {
+	mockService := new(filter_service.FilterServiceMock)
+	mockService.On("RunFilter", 1, 4).Return(dto.ListTasksResponse{
+		Tasks: []dto.GetTaskResponse{{ID: 2, Task: "Pay rent", Status: "pending"}},
+	}, nil)
+	handler := NewFilterHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.GET("/filters/:id/tasks", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.RunFilter(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/filters/4/tasks", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertJSONEqual(t, dto.ListTasksResponse{
+		Tasks: []dto.GetTaskResponse{{ID: 2, Task: "Pay rent", Status: "pending"}},
+	}, w.Body.Bytes())
+	mockService.AssertExpectations(t)
+}
+
+func assertJSONEqual(t *testing.T, expected any, actual []byte) {
+	t.Helper()
+
+	expectedBytes, err := json.Marshal(expected)
+	assert.NoError(t, err)
+
+	var want, got any
+	assert.NoError(t, json.Unmarshal(expectedBytes, &want))
+	assert.NoError(t, json.Unmarshal(actual, &got))
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,241 @@
+package importjob_handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	importjob_service "taskflow/internal/service/importjob"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxImportBody bounds an uploaded file, with room for form overhead.
+const maxImportBody = 10 << 20
+
+type ImportJobHandler struct {
+	service  importjob_service.ImportJobServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewImportJobHandler(s importjob_service.ImportJobServiceInterface, ua auth.UserAuthInterface) *ImportJobHandler {
+	return &ImportJobHandler{service: s, userAuth: ua}
+}
+
+var _ ImportJobHandlerInterface = (*ImportJobHandler)(nil)
+
+// CreateImport godoc
+// @Summary Import from another app
+// @Description Upload a Todoist JSON backup, a Trello board JSON export or a todo.txt file, as the "file" field of a multipart form or as the request body. The file is checked right away and imported in the background; poll the import for its progress. Tasks imported before, by any import, are skipped.
+// @Tags imports
+// @Accept multipart/form-data,json,text/plain
+// @Produce json
+// @Param file formData file false "File to import"
+// @Param source query string true "App the file comes from" Enums(todoist, trello, todotxt)
+// @Success 202 {object} dto.ImportJobResponse
+// @Failure 400 {object} common.ErrorResponse "Unknown source or invalid file"
+// @Failure 413 {object} common.ErrorResponse "File too large"
+// @Router /imports [post]
+func (h *ImportJobHandler) CreateImport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBody)
+	var body io.Reader = c.Request.Body
+	name := ""
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			if isTooLarge(err) {
+				c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "file is too large"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "file is required"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "could not read file"})
+			return
+		}
+		defer f.Close()
+		body, name = f, fh.Filename
+	}
+
+	source := c.Query("source")
+	if source == "" {
+		source = c.PostForm("source")
+	}
+	if source == "" {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "source is required; use " + strings.Join(h.service.Sources(), ", ")})
+		return
+	}
+
+	resp, err := h.service.Create(userID.(int), source, name, body)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// ListImports godoc
+// @Summary List imports
+// @Description Returns the user's imports, newest first
+// @Tags imports
+// @Produce json
+// @Success 200 {object} dto.ListImportJobsResponse
+// @Router /imports [get]
+func (h *ImportJobHandler) ListImports(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.List(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetImport godoc
+// @Summary Get an import
+// @Description Returns an import's status and progress, and the tasks that could not be imported
+// @Tags imports
+// @Produce json
+// @Param id path int true "Import ID" minimum(1) example(1)
+// @Success 200 {object} dto.ImportJobResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Import not found"
+// @Router /imports/{id} [get]
+func (h *ImportJobHandler) GetImport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Get(userID.(int), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// RerunImport godoc
+// @Summary Run an import again
+// @Description Import the file of an import again, in the background. Tasks imported before are skipped, so only the tasks that failed, or that an earlier run didn't get to, are added.
+// @Tags imports
+// @Produce json
+// @Param id path int true "Import ID" minimum(1) example(1)
+// @Success 202 {object} dto.ImportJobResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Import not found"
+// @Failure 409 {object} common.ErrorResponse "Import is running"
+// @Router /imports/{id}/rerun [post]
+func (h *ImportJobHandler) RerunImport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Rerun(userID.(int), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// DeleteImport godoc
+// @Summary Delete an import
+// @Description Delete an import and its file. The imported tasks are kept.
+// @Tags imports
+// @Produce json
+// @Param id path int true "Import ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteImportJobResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Import not found"
+// @Failure 409 {object} common.ErrorResponse "Import is running"
+// @Router /imports/{id} [delete]
+func (h *ImportJobHandler) DeleteImport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(userID.(int), id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteImportJobResponse{Message: "Import deleted successfully"})
+}
+
+func parseID(c *gin.Context) (int, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid import ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+func isTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case isTooLarge(err):
+		c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "file is too large"})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, importjob_service.ErrJobRunning):
+		c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, importjob_service.ErrInvalidUser),
+		errors.Is(err, importjob_service.ErrUnknownSource),
+		errors.Is(err, importjob_service.ErrEmptyFile),
+		errors.Is(err, importjob_service.ErrInvalidFile),
+		errors.Is(err, importjob_service.ErrNoTasks),
+		errors.Is(err, importjob_service.ErrTooManyTasks):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
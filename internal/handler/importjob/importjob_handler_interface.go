@@ -0,0 +1,20 @@
+package importjob_handler
+
+import "github.com/gin-gonic/gin"
+
+type ImportJobHandlerInterface interface {
+	// CreateImport handles POST /api/imports
+	CreateImport(c *gin.Context)
+
+	// ListImports handles GET /api/imports
+	ListImports(c *gin.Context)
+
+	// GetImport handles GET /api/imports/:id
+	GetImport(c *gin.Context)
+
+	// RerunImport handles POST /api/imports/:id/rerun
+	RerunImport(c *gin.Context)
+
+	// DeleteImport handles DELETE /api/imports/:id
+	DeleteImport(c *gin.Context)
+}
@@ -0,0 +1,320 @@
+package importjob_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	importjob_service "taskflow/internal/service/importjob"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+var pending = dto.ImportJobResponse{
+	ID:        5,
+	Source:    "todotxt",
+	FileName:  "todo.txt",
+	Status:    "pending",
+	Run:       1,
+	Total:     2,
+	Errors:    []dto.ImportJobError{},
+	CreatedAt: time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC),
+}
+
+func TestImportJobHandler_CreateImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		body           string
+		setupMock      func() *importjob_service.ImportJobServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:  "success - body",
+			query: "?source=todotxt",
+			body:  "Buy milk\nPay rent\n",
+			setupMock: func() *importjob_service.ImportJobServiceMock {
+				m := new(importjob_service.ImportJobServiceMock)
+				m.On("Create", 1, "todotxt", "", mock.Anything).Return(pending, nil)
+				return m
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   pending,
+		},
+		{
+			name: "failure - missing source",
+			body: "Buy milk\n",
+			setupMock: func() *importjob_service.ImportJobServiceMock {
+				m := new(importjob_service.ImportJobServiceMock)
+				m.On("Sources").Return([]string{"todoist", "todotxt", "trello"})
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "source is required; use todoist, todotxt, trello"},
+		},
+		{
+			name:  "failure - invalid file",
+			query: "?source=trello",
+			body:  "[",
+			setupMock: func() *importjob_service.ImportJobServiceMock {
+				m := new(importjob_service.ImportJobServiceMock)
+				m.On("Create", 1, "trello", "", mock.Anything).Return(dto.ImportJobResponse{}, importjob_service.ErrInvalidFile)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: importjob_service.ErrInvalidFile.Error()},
+		},
+		{
+			name:  "failure - file too large",
+			query: "?source=todotxt",
+			body:  "Buy milk\n",
+			setupMock: func() *importjob_service.ImportJobServiceMock {
+				m := new(importjob_service.ImportJobServiceMock)
+				m.On("Create", 1, "todotxt", "", mock.Anything).Return(dto.ImportJobResponse{}, &http.MaxBytesError{Limit: maxImportBody})
+				return m
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedBody:   common.ErrorResponse{Message: "file is too large"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewImportJobHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/imports", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.CreateImport(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/imports"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "text/plain")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportJobHandler_CreateImport_Multipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "todo.txt")
+	require.NoError(t, err)
+	fw.Write([]byte("Buy milk\n"))
+	require.NoError(t, mw.WriteField("source", "todotxt"))
+	require.NoError(t, mw.Close())
+
+	mockService := new(importjob_service.ImportJobServiceMock)
+	mockService.On("Create", 1, "todotxt", "todo.txt", mock.MatchedBy(func(r io.Reader) bool {
+		data, _ := io.ReadAll(r)
+		return string(data) == "Buy milk\n"
+	})).Return(pending, nil)
+	handler := NewImportJobHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.POST("/imports", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.CreateImport(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/imports", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestImportJobHandler_CreateImport_Unauthorized(t *testing.T) {
+	handler := NewImportJobHandler(new(importjob_service.ImportJobServiceMock), new(auth.MockUserAuth))
+	router := setupGin()
+	router.POST("/imports", handler.CreateImport)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/imports?source=todotxt", strings.NewReader("Buy milk")))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestImportJobHandler_ListImports(t *testing.T) {
+	mockService := new(importjob_service.ImportJobServiceMock)
+	mockService.On("List", 1).Return(dto.ListImportJobsResponse{Jobs: []dto.ImportJobResponse{pending}}, nil)
+	handler := NewImportJobHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.GET("/imports", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.ListImports(c)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/imports", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	expected, _ := json.Marshal(dto.ListImportJobsResponse{Jobs: []dto.ImportJobResponse{pending}})
+	assert.JSONEq(t, string(expected), w.Body.String())
+}
+
+func TestImportJobHandler_GetImport(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		setupMock      func() *importjob_service.ImportJobServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "success",
+			id:   "5",
+			setupMock: func() *importjob_service.ImportJobServiceMock {
+				m := new(importjob_service.ImportJobServiceMock)
+				m.On("Get", 1, 5).Return(pending, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   pending,
+		},
+		{
+			name: "failure - not found",
+			id:   "6",
+			setupMock: func() *importjob_service.ImportJobServiceMock {
+				m := new(importjob_service.ImportJobServiceMock)
+				m.On("Get", 1, 6).Return(dto.ImportJobResponse{}, gorm.ErrRecordNotFound)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+		{
+			name: "failure - invalid ID",
+			id:   "abc",
+			setupMock: func() *importjob_service.ImportJobServiceMock {
+				return new(importjob_service.ImportJobServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid import ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewImportJobHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/imports/:id", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.GetImport(c)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/imports/"+tt.id, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestImportJobHandler_RerunImport(t *testing.T) {
+	rerun := pending
+	rerun.Run = 2
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:           "success",
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   rerun,
+		},
+		{
+			name:           "failure - running",
+			err:            importjob_service.ErrJobRunning,
+			expectedStatus: http.StatusConflict,
+			expectedBody:   common.ErrorResponse{Message: importjob_service.ErrJobRunning.Error()},
+		},
+		{
+			name:           "failure - service error",
+			err:            errors.New("db error"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   common.ErrorResponse{Message: "db error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(importjob_service.ImportJobServiceMock)
+			if tt.err != nil {
+				mockService.On("Rerun", 1, 5).Return(dto.ImportJobResponse{}, tt.err)
+			} else {
+				mockService.On("Rerun", 1, 5).Return(rerun, nil)
+			}
+			handler := NewImportJobHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/imports/:id/rerun", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.RerunImport(c)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/imports/5/rerun", nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+		})
+	}
+}
+
+func TestImportJobHandler_DeleteImport(t *testing.T) {
+	mockService := new(importjob_service.ImportJobServiceMock)
+	mockService.On("Delete", 1, 5).Return(nil)
+	handler := NewImportJobHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.DELETE("/imports/:id", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.DeleteImport(c)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/imports/5", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"message":"Import deleted successfully"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
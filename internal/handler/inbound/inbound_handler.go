@@ -0,0 +1,188 @@
+package inbound_handler
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	attachment_service "taskflow/internal/service/attachment"
+	inbound_service "taskflow/internal/service/inbound"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxCaptureBody bounds a capture request, files included, leaving room
+// for form overhead.
+const maxCaptureBody = attachment_service.DefaultMaxSize + 1<<20
+
+type InboundHandler struct {
+	service  inbound_service.InboundServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewInboundHandler(s inbound_service.InboundServiceInterface, ua auth.UserAuthInterface) *InboundHandler {
+	return &InboundHandler{service: s, userAuth: ua}
+}
+
+var _ InboundHandlerInterface = (*InboundHandler)(nil)
+
+// CreateAddress godoc
+// @Summary Create an inbound address
+// @Description Create a secret capture address. Posting to its URL, or mailing it when the server receives mail, creates a task.
+// @Tags inbound
+// @Accept json
+// @Produce json
+// @Param address body dto.CreateInboundAddressRequest true "Address name"
+// @Success 201 {object} dto.InboundAddressResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 409 {object} common.ErrorResponse "Too many addresses"
+// @Router /inbound-addresses [post]
+func (h *InboundHandler) CreateAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.CreateInboundAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateAddress(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListAddresses godoc
+// @Summary List inbound addresses
+// @Description Returns the user's capture addresses
+// @Tags inbound
+// @Produce json
+// @Success 200 {object} dto.ListInboundAddressesResponse
+// @Router /inbound-addresses [get]
+func (h *InboundHandler) ListAddresses(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.ListAddresses(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAddress godoc
+// @Summary Delete an inbound address
+// @Description Delete a capture address. Its URL and email stop working.
+// @Tags inbound
+// @Produce json
+// @Param id path int true "Address ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteInboundAddressResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Address not found"
+// @Router /inbound-addresses/{id} [delete]
+func (h *InboundHandler) DeleteAddress(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid address ID"})
+		return
+	}
+
+	if err := h.service.DeleteAddress(userID.(int), id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteInboundAddressResponse{Message: "Inbound address deleted successfully"})
+}
+
+// Capture godoc
+// @Summary Capture a task
+// @Description Create a task for the owner of an inbound address. Takes JSON, a urlencoded form, or a multipart form with files under "attachments". No authentication: the token in the URL is the secret.
+// @Tags inbound
+// @Accept json,x-www-form-urlencoded,multipart/form-data
+// @Produce json
+// @Param token path string true "Address token"
+// @Param capture body dto.InboundCaptureRequest true "Task to create"
+// @Success 201 {object} dto.InboundCaptureResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Unknown address"
+// @Failure 413 {object} common.ErrorResponse "Request too large"
+// @Router /inbound/{token} [post]
+func (h *InboundHandler) Capture(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxCaptureBody)
+
+	var req dto.InboundCaptureRequest
+	if err := c.ShouldBind(&req); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "request is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	var files []inbound_service.File
+	if form := c.Request.MultipartForm; form != nil {
+		for _, fh := range form.File["attachments"] {
+			files = append(files, inbound_service.File{
+				Name: fh.Filename,
+				Size: fh.Size,
+				Open: openFile(fh),
+			})
+		}
+	}
+
+	resp, err := h.service.Capture(c.Param("token"), &req, files)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+func openFile(fh *multipart.FileHeader) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		return fh.Open()
+	}
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound),
+		errors.Is(err, inbound_service.ErrUnknownAddress):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, inbound_service.ErrTooManyAddresses):
+		c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, inbound_service.ErrInvalidUser),
+		errors.Is(err, inbound_service.ErrEmptyTitle):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,17 @@
+package inbound_handler
+
+import "github.com/gin-gonic/gin"
+
+type InboundHandlerInterface interface {
+	// CreateAddress handles POST /api/inbound-addresses
+	CreateAddress(c *gin.Context)
+
+	// ListAddresses handles GET /api/inbound-addresses
+	ListAddresses(c *gin.Context)
+
+	// DeleteAddress handles DELETE /api/inbound-addresses/:id
+	DeleteAddress(c *gin.Context)
+
+	// Capture handles POST /inbound/:token
+	Capture(c *gin.Context)
+}
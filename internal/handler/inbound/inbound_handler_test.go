@@ -0,0 +1,240 @@
+package inbound_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	inbound_service "taskflow/internal/service/inbound"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestInboundHandler_CreateAddress(t *testing.T) {
+	created := dto.InboundAddressResponse{ID: 1, Name: "Zapier", URL: "/inbound/abc"}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *inbound_service.InboundServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"name":"Zapier"}`,
+			setupMock: func() *inbound_service.InboundServiceMock {
+				m := new(inbound_service.InboundServiceMock)
+				m.On("CreateAddress", 1, &dto.CreateInboundAddressRequest{Name: "Zapier"}).Return(created, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   created,
+		},
+		{
+			name:        "failure - too many",
+			requestBody: `{"name":"Zapier"}`,
+			setupMock: func() *inbound_service.InboundServiceMock {
+				m := new(inbound_service.InboundServiceMock)
+				m.On("CreateAddress", 1, mock.Anything).Return(dto.InboundAddressResponse{}, inbound_service.ErrTooManyAddresses)
+				return m
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   common.ErrorResponse{Message: inbound_service.ErrTooManyAddresses.Error()},
+		},
+		{
+			name:        "failure - missing name",
+			requestBody: `{}`,
+			setupMock: func() *inbound_service.InboundServiceMock {
+				return new(inbound_service.InboundServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'CreateInboundAddressRequest.Name' Error:Field validation for 'Name' failed on the 'required' tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewInboundHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/inbound-addresses", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.CreateAddress(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/inbound-addresses", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func multipartBody(t *testing.T) (string, *bytes.Buffer) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	require.NoError(t, mw.WriteField("title", "Scan receipts"))
+	require.NoError(t, mw.WriteField("tags", "finance,home"))
+	fw, err := mw.CreateFormFile("attachments", "receipt.txt")
+	require.NoError(t, err)
+	_, err = fw.Write([]byte("total: 12.50"))
+	require.NoError(t, err)
+	require.NoError(t, mw.Close())
+	return mw.FormDataContentType(), &buf
+}
+
+func TestInboundHandler_Capture(t *testing.T) {
+	due := time.Date(2025, 9, 1, 17, 0, 0, 0, time.UTC)
+	captured := dto.InboundCaptureResponse{Task: dto.GetTaskResponse{ID: 9, Task: "Buy milk"}, Attachments: []dto.AttachmentResponse{}}
+
+	tests := []struct {
+		name           string
+		token          string
+		body           func(t *testing.T) (string, io.Reader)
+		setupMock      func() *inbound_service.InboundServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:  "json",
+			token: "abc",
+			body: func(t *testing.T) (string, io.Reader) {
+				return "application/json", strings.NewReader(`{"title":"Buy milk","due_at":"2025-09-01T17:00:00Z","priority":"high","tags":["home"]}`)
+			},
+			setupMock: func() *inbound_service.InboundServiceMock {
+				m := new(inbound_service.InboundServiceMock)
+				m.On("Capture", "abc", &dto.InboundCaptureRequest{Title: "Buy milk", DueAt: &due, Priority: "high", Tags: []string{"home"}}, []inbound_service.File(nil)).
+					Return(captured, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   captured,
+		},
+		{
+			name:  "form",
+			token: "abc",
+			body: func(t *testing.T) (string, io.Reader) {
+				form := url.Values{"text": {"Buy milk\nSemi-skimmed"}, "due_at": {"2025-09-01T17:00:00Z"}}
+				return "application/x-www-form-urlencoded", strings.NewReader(form.Encode())
+			},
+			setupMock: func() *inbound_service.InboundServiceMock {
+				m := new(inbound_service.InboundServiceMock)
+				m.On("Capture", "abc", &dto.InboundCaptureRequest{Text: "Buy milk\nSemi-skimmed", DueAt: &due}, []inbound_service.File(nil)).
+					Return(captured, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   captured,
+		},
+		{
+			name:  "multipart with files",
+			token: "abc",
+			body: func(t *testing.T) (string, io.Reader) {
+				return multipartBody(t)
+			},
+			setupMock: func() *inbound_service.InboundServiceMock {
+				m := new(inbound_service.InboundServiceMock)
+				m.On("Capture", "abc", &dto.InboundCaptureRequest{Title: "Scan receipts", Tags: []string{"finance,home"}}, mock.MatchedBy(func(files []inbound_service.File) bool {
+					if len(files) != 1 || files[0].Name != "receipt.txt" || files[0].Size != 12 {
+						return false
+					}
+					rc, err := files[0].Open()
+					if err != nil {
+						return false
+					}
+					defer rc.Close()
+					content, _ := io.ReadAll(rc)
+					return string(content) == "total: 12.50"
+				})).Return(captured, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   captured,
+		},
+		{
+			name:  "failure - unknown token",
+			token: "nope",
+			body: func(t *testing.T) (string, io.Reader) {
+				return "application/json", strings.NewReader(`{"title":"Buy milk"}`)
+			},
+			setupMock: func() *inbound_service.InboundServiceMock {
+				m := new(inbound_service.InboundServiceMock)
+				m.On("Capture", "nope", mock.Anything, mock.Anything).Return(dto.InboundCaptureResponse{}, inbound_service.ErrUnknownAddress)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+		{
+			name:  "failure - no title",
+			token: "abc",
+			body: func(t *testing.T) (string, io.Reader) {
+				return "application/json", strings.NewReader(`{"description":"Semi-skimmed"}`)
+			},
+			setupMock: func() *inbound_service.InboundServiceMock {
+				m := new(inbound_service.InboundServiceMock)
+				m.On("Capture", "abc", mock.Anything, mock.Anything).Return(dto.InboundCaptureResponse{}, inbound_service.ErrEmptyTitle)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: inbound_service.ErrEmptyTitle.Error()},
+		},
+		{
+			name:  "failure - invalid priority",
+			token: "abc",
+			body: func(t *testing.T) (string, io.Reader) {
+				return "application/json", strings.NewReader(`{"title":"Buy milk","priority":"asap"}`)
+			},
+			setupMock: func() *inbound_service.InboundServiceMock {
+				return new(inbound_service.InboundServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'InboundCaptureRequest.Priority' Error:Field validation for 'Priority' failed on the 'oneof' tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewInboundHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/inbound/:token", handler.Capture)
+
+			contentType, body := tt.body(t)
+			req := httptest.NewRequest(http.MethodPost, "/inbound/"+tt.token, body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package notification_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	notification_service "taskflow/internal/service/notification"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type NotificationHandler struct {
+	service  notification_service.NotificationServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewNotificationHandler(s notification_service.NotificationServiceInterface, ua auth.UserAuthInterface) *NotificationHandler {
+	return &NotificationHandler{service: s, userAuth: ua}
+}
+
+var _ NotificationHandlerInterface = (*NotificationHandler)(nil)
+
+// ListNotifications godoc
+// @Summary List notifications
+// @Description Returns a page of the user's notifications, newest first, with the number of unread ones
+// @Tags notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(20)
+// @Success 200 {object} dto.ListNotificationsResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Router /notifications [get]
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	unreadOnly := false
+	if raw := c.Query("unread"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "unread must be true or false"})
+			return
+		}
+		unreadOnly = v
+	}
+	page, limit := common.ParsePagination(c.Query("page"), c.Query("limit"))
+
+	resp, err := h.service.List(userID.(int), unreadOnly, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// MarkRead godoc
+// @Summary Mark a notification as read
+// @Description Marks one notification as read and returns the remaining unread count
+// @Tags notifications
+// @Produce json
+// @Param id path int true "Notification ID" minimum(1) example(1)
+// @Success 200 {object} dto.MarkReadResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Notification not found"
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid notification ID"})
+		return
+	}
+
+	resp, err := h.service.MarkRead(userID.(int), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Description Marks every unread notification as read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} dto.MarkReadResponse
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.MarkAllRead(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetPreferences godoc
+// @Summary Get notification preferences
+// @Description Returns how each notification type is delivered: in_app, email, both or off
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} dto.NotificationPreferencesResponse
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.GetPreferences(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdatePreferences godoc
+// @Summary Update notification preferences
+// @Description Sets the delivery of the listed notification types; other types are unchanged
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param preferences body dto.NotificationPreferencesRequest true "Preferences to change"
+// @Success 200 {object} dto.NotificationPreferencesResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.UpdatePreferences(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, notification_service.ErrInvalidUser),
+		errors.Is(err, notification_service.ErrInvalidType),
+		errors.Is(err, notification_service.ErrInvalidDelivery):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
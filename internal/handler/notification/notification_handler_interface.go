@@ -0,0 +1,20 @@
+package notification_handler
+
+import "github.com/gin-gonic/gin"
+
+type NotificationHandlerInterface interface {
+	// ListNotifications handles GET /api/notifications
+	ListNotifications(c *gin.Context)
+
+	// MarkRead handles POST /api/notifications/:id/read
+	MarkRead(c *gin.Context)
+
+	// MarkAllRead handles POST /api/notifications/read-all
+	MarkAllRead(c *gin.Context)
+
+	// GetPreferences handles GET /api/notifications/preferences
+	GetPreferences(c *gin.Context)
+
+	// UpdatePreferences handles PUT /api/notifications/preferences
+	UpdatePreferences(c *gin.Context)
+}
@@ -0,0 +1,253 @@
+package notification_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	notification_service "taskflow/internal/service/notification"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestNotificationHandler_ListNotifications(t *testing.T) {
+	list := dto.ListNotificationsResponse{
+		Notifications: []dto.NotificationResponse{{ID: 3, Type: "subtasks_done", Title: "Subtasks done: Write report"}},
+		Unread:        1,
+		Page:          2,
+		Limit:         10,
+		Total:         11,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func() *notification_service.NotificationServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:  "success",
+			query: "?page=2&limit=10",
+			setupMock: func() *notification_service.NotificationServiceMock {
+				m := new(notification_service.NotificationServiceMock)
+				m.On("List", 1, false, 2, 10).Return(list, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   list,
+		},
+		{
+			name:  "success - unread only",
+			query: "?unread=true",
+			setupMock: func() *notification_service.NotificationServiceMock {
+				m := new(notification_service.NotificationServiceMock)
+				m.On("List", 1, true, 1, 20).Return(list, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   list,
+		},
+		{
+			name:  "failure - invalid unread flag",
+			query: "?unread=maybe",
+			setupMock: func() *notification_service.NotificationServiceMock {
+				return new(notification_service.NotificationServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "unread must be true or false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewNotificationHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/notifications", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.ListNotifications(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/notifications"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationHandler_MarkRead(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		setupMock      func() *notification_service.NotificationServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "success",
+			id:   "3",
+			setupMock: func() *notification_service.NotificationServiceMock {
+				m := new(notification_service.NotificationServiceMock)
+				m.On("MarkRead", 1, 3).Return(dto.MarkReadResponse{Marked: 1, Unread: 4}, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.MarkReadResponse{Marked: 1, Unread: 4},
+		},
+		{
+			name: "failure - not found",
+			id:   "9",
+			setupMock: func() *notification_service.NotificationServiceMock {
+				m := new(notification_service.NotificationServiceMock)
+				m.On("MarkRead", 1, 9).Return(dto.MarkReadResponse{}, gorm.ErrRecordNotFound)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+		{
+			name: "failure - invalid ID",
+			id:   "abc",
+			setupMock: func() *notification_service.NotificationServiceMock {
+				return new(notification_service.NotificationServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid notification ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewNotificationHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/notifications/:id/read", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.MarkRead(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/notifications/"+tt.id+"/read", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNotificationHandler_MarkAllRead(t *testing.T) {
+	mockService := new(notification_service.NotificationServiceMock)
+	mockService.On("MarkAllRead", 1).Return(dto.MarkReadResponse{Marked: 4}, nil)
+	handler := NewNotificationHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.POST("/notifications/read-all", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.MarkAllRead(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/notifications/read-all", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"marked":4,"unread":0}`, w.Body.String())
+}
+
+func TestNotificationHandler_UpdatePreferences(t *testing.T) {
+	prefs := dto.NotificationPreferencesResponse{Preferences: []dto.NotificationPreference{
+		{Type: "reminder", Delivery: "both"},
+		{Type: "subtasks_done", Delivery: "off"},
+		{Type: "subtasks_done", Delivery: "in_app"},
+	}}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *notification_service.NotificationServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"preferences":[{"type":"subtasks_done","delivery":"off"}]}`,
+			setupMock: func() *notification_service.NotificationServiceMock {
+				m := new(notification_service.NotificationServiceMock)
+				m.On("UpdatePreferences", 1, &dto.NotificationPreferencesRequest{Preferences: []dto.NotificationPreference{
+					{Type: "subtasks_done", Delivery: "off"},
+				}}).Return(prefs, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   prefs,
+		},
+		{
+			name:        "failure - unknown type",
+			requestBody: `{"preferences":[{"type":"digest","delivery":"off"}]}`,
+			setupMock: func() *notification_service.NotificationServiceMock {
+				m := new(notification_service.NotificationServiceMock)
+				m.On("UpdatePreferences", 1, &dto.NotificationPreferencesRequest{Preferences: []dto.NotificationPreference{
+					{Type: "digest", Delivery: "off"},
+				}}).Return(dto.NotificationPreferencesResponse{}, fmt.Errorf("%w %q", notification_service.ErrInvalidType, "digest"))
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: `unknown notification type "digest"`},
+		},
+		{
+			name:        "failure - empty list",
+			requestBody: `{"preferences":[]}`,
+			setupMock: func() *notification_service.NotificationServiceMock {
+				return new(notification_service.NotificationServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'NotificationPreferencesRequest.Preferences' Error:Field validation for 'Preferences' failed on the 'min' tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewNotificationHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.PUT("/notifications/preferences", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.UpdatePreferences(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/notifications/preferences", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
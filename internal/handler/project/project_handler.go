@@ -0,0 +1,162 @@
+package project_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	project_service "taskflow/internal/service/project"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ProjectHandler struct {
+	service  project_service.ProjectServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewProjectHandler(s project_service.ProjectServiceInterface, ua auth.UserAuthInterface) *ProjectHandler {
+	return &ProjectHandler{service: s, userAuth: ua}
+}
+
+var _ ProjectHandlerInterface = (*ProjectHandler)(nil)
+
+// CreateProject godoc
+// @Summary Create a project
+// @Description Create a project to group tasks
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param project body dto.CreateProjectRequest true "Project to create"
+// @Success 201 {object} dto.ProjectResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Router /projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.CreateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateProject(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListProjects godoc
+// @Summary List projects
+// @Description Returns the user's projects sorted by name
+// @Tags projects
+// @Produce json
+// @Success 200 {object} dto.ListProjectsResponse
+// @Router /projects [get]
+func (h *ProjectHandler) ListProjects(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.ListProjects(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateProject godoc
+// @Summary Rename a project
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path int true "Project ID" minimum(1) example(1)
+// @Param project body dto.UpdateProjectRequest true "New name"
+// @Success 200 {object} dto.ProjectResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Project not found"
+// @Router /projects/{id} [patch]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid project ID"})
+		return
+	}
+
+	var req dto.UpdateProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.UpdateProject(userID.(int), id, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteProject godoc
+// @Summary Delete a project
+// @Description Delete a project. Its tasks are kept and move back to the inbox.
+// @Tags projects
+// @Produce json
+// @Param id path int true "Project ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteProjectResponse
+// @Failure 404 {object} common.ErrorResponse "Project not found"
+// @Router /projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid project ID"})
+		return
+	}
+
+	if err := h.service.DeleteProject(userID.(int), id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteProjectResponse{Message: "Project deleted successfully"})
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, project_service.ErrEmptyName),
+		errors.Is(err, project_service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
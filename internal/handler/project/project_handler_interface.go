@@ -0,0 +1,17 @@
+package project_handler
+
+import "github.com/gin-gonic/gin"
+
+type ProjectHandlerInterface interface {
+	// CreateProject handles POST /api/projects
+	CreateProject(c *gin.Context)
+
+	// ListProjects handles GET /api/projects
+	ListProjects(c *gin.Context)
+
+	// UpdateProject handles PATCH /api/projects/:id
+	UpdateProject(c *gin.Context)
+
+	// DeleteProject handles DELETE /api/projects/:id
+	DeleteProject(c *gin.Context)
+}
@@ -0,0 +1,113 @@
+package project_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	project_service "taskflow/internal/service/project"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestProjectHandler_CreateProject(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *project_service.ProjectServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"name":"Work"}`,
+			setupMock: func() *project_service.ProjectServiceMock {
+				m := new(project_service.ProjectServiceMock)
+				m.On("CreateProject", 1, mock.MatchedBy(func(r *dto.CreateProjectRequest) bool {
+					return r.Name == "Work"
+				})).Return(dto.ProjectResponse{ID: 3, Name: "Work"}, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   dto.ProjectResponse{ID: 3, Name: "Work"},
+		},
+		{
+			name:        "failure - blank name",
+			requestBody: `{"name":"  "}`,
+			setupMock: func() *project_service.ProjectServiceMock {
+				m := new(project_service.ProjectServiceMock)
+				m.On("CreateProject", 1, mock.Anything).Return(dto.ProjectResponse{}, project_service.ErrEmptyName)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: project_service.ErrEmptyName.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewProjectHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/projects", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.CreateProject(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/projects", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProjectHandler_DeleteProject(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "not found", serviceErr: gorm.ErrRecordNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(project_service.ProjectServiceMock)
+			mockService.On("DeleteProject", 1, 3).Return(tt.serviceErr)
+			handler := NewProjectHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.DELETE("/projects/:id", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.DeleteProject(c)
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/projects/3", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package search_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	search_service "taskflow/internal/service/search"
+	"taskflow/internal/taskquery"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	service  search_service.SearchServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewSearchHandler(s search_service.SearchServiceInterface, ua auth.UserAuthInterface) *SearchHandler {
+	return &SearchHandler{service: s, userAuth: ua}
+}
+
+var _ SearchHandlerInterface = (*SearchHandler)(nil)
+
+// SearchTasks godoc
+// @Summary Search tasks
+// @Description Full-text search over task titles, descriptions and comments, ranked by relevance. Fields such as `tag:work` or `due<7d` in the query filter the tasks searched, as they do for GET /tasks
+// @Tags tasks
+// @Produce json
+// @Param q query string true "Search text, with optional filter expressions" example(milk tag:home)
+// @Param status query string false "Filter by status" Enums(pending, in-progress, completed)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(20)
+// @Success 200 {object} dto.SearchTasksResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid query, with the error position"
+// @Failure 500 {object} common.ErrorResponse "Internal server error"
+// @Router /tasks/search [get]
+func (h *SearchHandler) SearchTasks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	page, limit := common.ParsePagination(c.Query("page"), c.Query("limit"))
+
+	resp, err := h.service.SearchTasks(userID.(int), c.Query("q"), c.Query("status"), page, limit)
+	var qerr *taskquery.Error
+	if errors.As(err, &qerr) {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: qerr.Message, Position: qerr.Pos})
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, search_service.ErrEmptyQuery),
+			errors.Is(err, search_service.ErrQueryTooLong),
+			errors.Is(err, search_service.ErrInvalidStatus),
+			errors.Is(err, search_service.ErrInvalidUser):
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
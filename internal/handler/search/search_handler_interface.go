@@ -0,0 +1,8 @@
+package search_handler
+
+import "github.com/gin-gonic/gin"
+
+type SearchHandlerInterface interface {
+	// SearchTasks handles GET /api/tasks/search
+	SearchTasks(c *gin.Context)
+}
@@ -0,0 +1,131 @@
+package search_handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	search_service "taskflow/internal/service/search"
+	"taskflow/internal/taskquery"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestSearchHandler_SearchTasks(t *testing.T) {
+	tests := []struct {
+		name           string
+		userID         int
+		url            string
+		setupMock      func() *search_service.SearchServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			url:    "/tasks/search?q=milk&status=pending&page=1&limit=5",
+			setupMock: func() *search_service.SearchServiceMock {
+				m := new(search_service.SearchServiceMock)
+				m.On("SearchTasks", 1, "milk", "pending", 1, 5).Return(dto.SearchTasksResponse{
+					Query:   "milk",
+					Results: []dto.SearchTaskResult{{Task: dto.GetTaskResponse{ID: 1, Task: "Buy milk", Status: "pending"}, Score: 1.5, Snippet: "Buy <mark>milk</mark>", MatchedIn: []string{"task"}}},
+					Page:    1,
+					Limit:   5,
+					Total:   1,
+				}, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.SearchTasksResponse{
+				Query:   "milk",
+				Results: []dto.SearchTaskResult{{Task: dto.GetTaskResponse{ID: 1, Task: "Buy milk", Status: "pending"}, Score: 1.5, Snippet: "Buy <mark>milk</mark>", MatchedIn: []string{"task"}}},
+				Page:    1,
+				Limit:   5,
+				Total:   1,
+			},
+		},
+		{
+			name:   "failure - empty query",
+			userID: 1,
+			url:    "/tasks/search",
+			setupMock: func() *search_service.SearchServiceMock {
+				m := new(search_service.SearchServiceMock)
+				m.On("SearchTasks", 1, "", "", 1, 20).Return(dto.SearchTasksResponse{}, search_service.ErrEmptyQuery)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: search_service.ErrEmptyQuery.Error()},
+		},
+		{
+			name:   "failure - invalid query",
+			userID: 1,
+			url:    "/tasks/search?q=milk+colour:red",
+			setupMock: func() *search_service.SearchServiceMock {
+				m := new(search_service.SearchServiceMock)
+				m.On("SearchTasks", 1, "milk colour:red", "", 1, 20).Return(dto.SearchTasksResponse{},
+					&taskquery.Error{Pos: 6, Message: "unknown field 'colour'"})
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "unknown field 'colour'", Position: 6},
+		},
+		{
+			name:   "failure - service error",
+			userID: 1,
+			url:    "/tasks/search?q=milk",
+			setupMock: func() *search_service.SearchServiceMock {
+				m := new(search_service.SearchServiceMock)
+				m.On("SearchTasks", 1, "milk", "", 1, 20).Return(dto.SearchTasksResponse{}, errors.New("db error"))
+				return m
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   common.ErrorResponse{Message: "db error"},
+		},
+		{
+			name:           "failure - unauthorized",
+			url:            "/tasks/search?q=milk",
+			setupMock:      func() *search_service.SearchServiceMock { return new(search_service.SearchServiceMock) },
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   common.ErrorResponse{Message: "unauthorized"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewSearchHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/tasks/search", func(c *gin.Context) {
+				if tt.userID != 0 {
+					c.Set("userID", tt.userID)
+				}
+				handler.SearchTasks(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			expectedBytes, _ := json.Marshal(tt.expectedBody)
+			var want, got any
+			assert.NoError(t, json.Unmarshal(expectedBytes, &want))
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, want, got)
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package stats_handler
+
+import (
+	"errors"
+	"net/http"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	stats_service "taskflow/internal/service/stats"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type StatsHandler struct {
+	service  stats_service.StatsServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewStatsHandler(s stats_service.StatsServiceInterface, ua auth.UserAuthInterface) *StatsHandler {
+	return &StatsHandler{service: s, userAuth: ua}
+}
+
+var _ StatsHandlerInterface = (*StatsHandler)(nil)
+
+// GetStats godoc
+// @Summary Productivity statistics
+// @Description Tasks created and completed per day or week, average time to completion, completion streaks, overdue counts and the status distribution. Days follow the user's time zone (see PATCH /users/timezone); the range defaults to the last 30 days.
+// @Tags stats
+// @Produce json
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param interval query string false "Period length (default day)" Enums(day, week)
+// @Success 200 {object} dto.StatsResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Router /stats [get]
+func (h *StatsHandler) GetStats(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.GetStats(userID.(int), c.Query("from"), c.Query("to"), c.Query("interval"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, stats_service.ErrInvalidRange),
+		errors.Is(err, stats_service.ErrInvalidInterval),
+		errors.Is(err, stats_service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,8 @@
+package stats_handler
+
+import "github.com/gin-gonic/gin"
+
+type StatsHandlerInterface interface {
+	// GetStats handles GET /api/stats
+	GetStats(c *gin.Context)
+}
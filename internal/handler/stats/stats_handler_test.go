@@ -0,0 +1,88 @@
+package stats_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	stats_service "taskflow/internal/service/stats"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestStatsHandler_GetStats(t *testing.T) {
+	stats := dto.StatsResponse{
+		From:      "2025-09-01",
+		To:        "2025-09-07",
+		TimeZone:  "Europe/Berlin",
+		Interval:  "week",
+		Periods:   []dto.StatsPeriod{{Start: "2025-09-01", Created: 4, Completed: 3}},
+		Created:   4,
+		Completed: 3,
+		Streaks:   dto.StatsStreaks{Current: 1, Longest: 3},
+		Statuses:  map[string]int64{"pending": 1, "in-progress": 0, "completed": 3},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func() *stats_service.StatsServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:  "success",
+			query: "?from=2025-09-01&to=2025-09-07&interval=week",
+			setupMock: func() *stats_service.StatsServiceMock {
+				m := new(stats_service.StatsServiceMock)
+				m.On("GetStats", 1, "2025-09-01", "2025-09-07", "week").Return(stats, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   stats,
+		},
+		{
+			name:  "failure - invalid range",
+			query: "?from=yesterday",
+			setupMock: func() *stats_service.StatsServiceMock {
+				m := new(stats_service.StatsServiceMock)
+				m.On("GetStats", 1, "yesterday", "", "").Return(dto.StatsResponse{}, stats_service.ErrInvalidRange)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: stats_service.ErrInvalidRange.Error()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewStatsHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/stats", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.GetStats(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/stats"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, err := json.Marshal(tt.expectedBody)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
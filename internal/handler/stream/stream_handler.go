@@ -0,0 +1,96 @@
+package stream_handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"taskflow/internal/common"
+	"taskflow/internal/stream"
+
+	"github.com/gin-gonic/gin"
+)
+
+// retryMS tells clients how long to wait before reconnecting.
+const retryMS = 3000
+
+type StreamHandler struct {
+	hub       stream.Hub
+	heartbeat time.Duration
+}
+
+func NewStreamHandler(hub stream.Hub, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{hub: hub, heartbeat: heartbeat}
+}
+
+var _ StreamHandlerInterface = (*StreamHandler)(nil)
+
+// Stream godoc
+// @Summary Stream task events
+// @Description Server-Sent Events stream of the user's task events. Each event's id is the domain event ID; reconnect with Last-Event-ID to replay missed events. A "reset" event means events were missed and the client should reload its tasks.
+// @Tags events
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "Resume after this event"
+// @Param last_event_id query string false "Resume after this event, for clients that can't set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 401 {object} common.ErrorResponse "Unauthorized"
+// @Router /events/stream [get]
+func (h *StreamHandler) Stream(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	sub, err := h.hub.Subscribe(userID.(int), lastEventID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: "internal server error"})
+		return
+	}
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Stop nginx from buffering the stream.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", retryMS)
+	if sub.Reset {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, m := range sub.Replay {
+		writeMessage(w, m)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case m, ok := <-sub.C:
+			if !ok {
+				// Dropped for falling behind; the client reconnects and
+				// replays what it missed.
+				return
+			}
+			writeMessage(w, m)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		w.Flush()
+	}
+}
+
+func writeMessage(w io.Writer, m stream.Message) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", m.ID, m.Event, m.Data)
+}
@@ -0,0 +1,8 @@
+package stream_handler
+
+import "github.com/gin-gonic/gin"
+
+type StreamHandlerInterface interface {
+	// Stream handles GET /api/events/stream
+	Stream(c *gin.Context)
+}
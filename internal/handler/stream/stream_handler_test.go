@@ -0,0 +1,147 @@
+package stream_handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/stream"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+// hubSpy hands the test each subscription once the handler holds it.
+type hubSpy struct {
+	stream.Hub
+	subs chan *stream.Subscription
+}
+
+func (h *hubSpy) Subscribe(userID int, lastEventID string) (*stream.Subscription, error) {
+	sub, err := h.Hub.Subscribe(userID, lastEventID)
+	if err == nil {
+		h.subs <- sub
+	}
+	return sub, err
+}
+
+func msg(id string, userID int) stream.Message {
+	return stream.Message{ID: id, Event: "task.created", UserID: userID, Data: json.RawMessage(`{"task":{"id":1}}`)}
+}
+
+func TestStreamHandler_Stream(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		query       string
+		live        bool
+		expectedOut string
+	}{
+		{
+			name:        "live events",
+			live:        true,
+			expectedOut: "retry: 3000\n\nid: c\nevent: task.created\ndata: {\"task\":{\"id\":1}}\n\n",
+		},
+		{
+			name:        "resume from header",
+			header:      "a",
+			expectedOut: "retry: 3000\n\nid: b\nevent: task.created\ndata: {\"task\":{\"id\":1}}\n\n",
+		},
+		{
+			name:        "resume from query",
+			query:       "?last_event_id=a",
+			expectedOut: "retry: 3000\n\nid: b\nevent: task.created\ndata: {\"task\":{\"id\":1}}\n\n",
+		},
+		{
+			name:        "reset",
+			header:      "gone",
+			expectedOut: "retry: 3000\n\nevent: reset\ndata: {}\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := &hubSpy{Hub: stream.NewMemoryHub(10), subs: make(chan *stream.Subscription, 1)}
+			hub.Publish(msg("a", 1))
+			hub.Publish(msg("b", 1))
+			hub.Publish(msg("x", 2))
+
+			h := NewStreamHandler(hub, time.Hour)
+			r := setupGin()
+			r.GET("/events/stream", func(c *gin.Context) {
+				c.Set("userID", 1)
+				h.Stream(c)
+			})
+
+			req, _ := http.NewRequest(http.MethodGet, "/events/stream"+tt.query, nil)
+			if tt.header != "" {
+				req.Header.Set("Last-Event-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			done := make(chan struct{})
+			go func() {
+				r.ServeHTTP(w, req)
+				close(done)
+			}()
+
+			sub := <-hub.subs
+			if tt.live {
+				hub.Publish(msg("y", 2))
+				hub.Publish(msg("c", 1))
+			}
+			// Buffered messages are still written before the stream ends.
+			sub.Close()
+			<-done
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedOut, w.Body.String())
+		})
+	}
+}
+
+func TestStreamHandler_Heartbeat(t *testing.T) {
+	hub := &hubSpy{Hub: stream.NewMemoryHub(10), subs: make(chan *stream.Subscription, 1)}
+	h := NewStreamHandler(hub, time.Millisecond)
+	r := setupGin()
+	r.GET("/events/stream", func(c *gin.Context) {
+		c.Set("userID", 1)
+		h.Stream(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/events/stream", nil)
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		r.ServeHTTP(w, req)
+		close(done)
+	}()
+
+	sub := <-hub.subs
+	time.Sleep(20 * time.Millisecond)
+	sub.Close()
+	<-done
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), ": ping\n\n")
+}
+
+func TestStreamHandler_Unauthorized(t *testing.T) {
+	h := NewStreamHandler(stream.NewMemoryHub(10), time.Hour)
+	r := setupGin()
+	r.GET("/events/stream", h.Stream)
+
+	req, _ := http.NewRequest(http.MethodGet, "/events/stream", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"unauthorized"}`, w.Body.String())
+}
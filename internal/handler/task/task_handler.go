@@ -2,13 +2,17 @@ package task_handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"taskflow/internal/auth"
 	"taskflow/internal/common"
 	"taskflow/internal/dto"
 	task_service "taskflow/internal/service/task"
+	"taskflow/internal/taskquery"
+	"taskflow/pkg/quickadd"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -27,12 +31,13 @@ var _ TaskHandlerInterface = (*TaskHandler)(nil)
 
 // CreateTask godoc
 // @Summary Create a new task
-// @Description Create a new task with title and description
+// @Description Create a new task and return it, with a Location header pointing at the new task
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param task body dto.CreateTaskRequest true "Task to create"
-// @Success 201 {object} task.Task
+// @Success 201 {object} dto.GetTaskResponse
+// @Header 201 {string} Location "URL of the created task"
 // @Failure 400 {object} common.ErrorResponse
 // @Router /tasks [post]
 func (h *TaskHandler) CreateTask(c *gin.Context) {
@@ -48,11 +53,61 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateTask(userID.(int), &req); err != nil {
+	resp, err := h.service.CreateTask(userID.(int), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+	c.Header("Location", fmt.Sprintf("%s/%d", c.FullPath(), resp.ID))
+	c.JSON(http.StatusCreated, resp)
+}
+
+// QuickAdd godoc
+// @Summary Quick-add a task from one line of text
+// @Description Parse natural language such as "Pay rent tomorrow 9am #finance !high every month" into a task.
+// @Description Dates are resolved in the user's time zone. With dry_run the parsed result is returned without creating anything.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body dto.QuickAddRequest true "Text to parse"
+// @Success 200 {object} dto.QuickAddResponse "Parsed result (dry run)"
+// @Success 201 {object} dto.QuickAddResponse "Task created"
+// @Header 201 {string} Location "URL of the created task"
+// @Failure 400 {object} common.ErrorResponse "Invalid input or nothing left for the title"
+// @Failure 500 {object} common.ErrorResponse "Internal server error"
+// @Router /tasks/quick [post]
+func (h *TaskHandler) QuickAdd(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.QuickAddRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
 		return
 	}
-	c.JSON(http.StatusCreated, req)
+
+	resp, err := h.service.QuickAdd(userID.(int), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, quickadd.ErrEmptyTitle), errors.Is(err, task_service.ErrTaskTooLong):
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "User not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: "Couldn't create task"})
+		}
+		return
+	}
+
+	if resp.Task == nil {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	c.Header("Location", fmt.Sprintf("%s/%d", strings.TrimSuffix(c.FullPath(), "/quick"), resp.Task.ID))
+	c.JSON(http.StatusCreated, resp)
 }
 
 // GetTask godoc
@@ -90,11 +145,13 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 // Handler Layer
 // ListTasks godoc
 // @Summary List all tasks
-// @Description Get a list of all tasks
+// @Description Get a list of all tasks, optionally filtered by a query such as `status:pending AND (tag:work OR priority>=high) AND due<7d`
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Param q query string false "Filter expression"
 // @Success 200 {object} dto.ListTasksResponse "List of tasks retrieved successfully"
+// @Failure 400 {object} common.ErrorResponse "Invalid query, with the error position"
 // @Failure 500 {object} common.ErrorResponse "Internal server error"
 // @Router /tasks [get]
 func (h *TaskHandler) ListTasks(c *gin.Context) {
@@ -104,7 +161,21 @@ func (h *TaskHandler) ListTasks(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.ListTasks(userID.(int))
+	var (
+		res dto.ListTasksResponse
+		err error
+	)
+	if q := c.Query("q"); q != "" {
+		res, err = h.service.FilterTasks(userID.(int), q)
+	} else {
+		res, err = h.service.ListTasks(userID.(int))
+	}
+
+	var qerr *taskquery.Error
+	if errors.As(err, &qerr) {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: qerr.Message, Position: qerr.Pos})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
 		return
@@ -114,15 +185,16 @@ func (h *TaskHandler) ListTasks(c *gin.Context) {
 
 // UpdateStatus godoc
 // @Summary Update task status
-// @Description Update the status field of a task by its ID
+// @Description Update the status field of a task by its ID and return the updated task
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path int true "Task ID" minimum(1) example(1)
 // @Param request body dto.UpdateStatusRequest true "Status payload"
-// @Success 200 {object} dto.UpdateStatusResponse "Status updated successfully"
+// @Success 200 {object} dto.GetTaskResponse "Status updated successfully"
 // @Failure 400 {object} common.ErrorResponse "Invalid input"
 // @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Failure 409 {object} common.ErrorResponse "Target column is at its WIP limit"
 // @Router /tasks/{id}/status [patch]
 func (h *TaskHandler) UpdateStatus(c *gin.Context) {
 	userID, exists := c.Get("userID")
@@ -143,22 +215,114 @@ func (h *TaskHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.UpdateStatus(userID.(int), id, req.Status); err != nil {
-		if err == gorm.ErrRecordNotFound {
+	resp, err := h.service.UpdateStatus(userID.(int), id, req.Status)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "Task not found"})
 			return
 		}
+		if errors.Is(err, task_service.ErrWIPLimitReached) {
+			c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Restore godoc
+// @Summary Restore a task from the trash
+// @Description Bring back a task moved to the trash and return it
+// @Tags tasks
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Success 200 {object} dto.GetTaskResponse "Task restored successfully"
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Failure 500 {object} common.ErrorResponse "Internal server error"
+// @Router /tasks/{id}/restore [post]
+func (h *TaskHandler) Restore(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "Invalid ID"})
+		return
+	}
+
+	resp, err := h.service.Restore(userID.(int), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "Task not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: "Couldn't restore task"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// MoveTask godoc
+// @Summary Reorder a task
+// @Description Place a task between two neighbours of a list (the inbox or a project). after_id is the task that should come right before it and before_id the one right after; anchors in another list move the task into that list. With no anchors the task moves to the end of its list. Setting status also moves the card to that board column in the same transaction.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param request body dto.MoveTaskRequest true "Anchors"
+// @Success 200 {object} dto.GetTaskResponse "Task moved successfully"
+// @Failure 400 {object} common.ErrorResponse "Invalid input or anchors"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Failure 409 {object} common.ErrorResponse "Target column is at its WIP limit"
+// @Failure 500 {object} common.ErrorResponse "Internal server error"
+// @Router /tasks/{id}/move [post]
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid task ID"})
+		return
+	}
+
+	var req dto.MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	resp := dto.UpdateStatusResponse{Message: "status updated"}
+	resp, err := h.service.Move(userID.(int), id, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "Task not found"})
+		case errors.Is(err, task_service.ErrInvalidAnchor):
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		case errors.Is(err, task_service.ErrWIPLimitReached):
+			c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: "Couldn't move task"})
+		}
+		return
+	}
+
 	c.JSON(http.StatusOK, resp)
 }
 
 // Delete godoc
 // @Summary Delete a task
-// @Description Delete a task by its ID
+// @Description Move a task to the trash. It can be restored until the trash is emptied.
 // @Tags tasks
 // @Produce json
 // @Param id path int true "Task ID" minimum(1) example(1)
@@ -193,3 +357,27 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 	resp := dto.DeleteTaskResponse{Message: "Task deleted successfully"}
 	c.JSON(http.StatusOK, resp)
 }
+
+// EmptyTrash godoc
+// @Summary Empty the trash
+// @Description Permanently delete every task in the trash, along with its comments and attachments
+// @Tags tasks
+// @Produce json
+// @Success 200 {object} dto.EmptyTrashResponse "Trash emptied"
+// @Failure 500 {object} common.ErrorResponse "Internal server error"
+// @Router /tasks/trash [delete]
+func (h *TaskHandler) EmptyTrash(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.EmptyTrash(userID.(int))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: "Couldn't empty trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
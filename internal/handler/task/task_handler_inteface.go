@@ -6,6 +6,9 @@ type TaskHandlerInterface interface {
 	// CreateTask handles POST /api/tasks
 	CreateTask(c *gin.Context)
 
+	// QuickAdd handles POST /api/tasks/quick
+	QuickAdd(c *gin.Context)
+
 	// GetTask handles GET /api/tasks/:id
 	GetTask(c *gin.Context)
 
@@ -15,6 +18,15 @@ type TaskHandlerInterface interface {
 	// UpdateStatus handles PATCH /api/tasks/:id/status
 	UpdateStatus(c *gin.Context)
 
+	// Restore handles POST /api/tasks/:id/restore
+	Restore(c *gin.Context)
+
+	// MoveTask handles POST /api/tasks/:id/move
+	MoveTask(c *gin.Context)
+
 	// Delete handles DELETE /api/tasks/:id
 	Delete(c *gin.Context)
+
+	// EmptyTrash handles DELETE /api/tasks/trash
+	EmptyTrash(c *gin.Context)
 }
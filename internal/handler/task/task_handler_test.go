@@ -6,11 +6,15 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"taskflow/internal/auth"
 	"taskflow/internal/common"
 	"taskflow/internal/dto"
 	task_service "taskflow/internal/service/task"
+	"taskflow/internal/taskquery"
+	"taskflow/pkg/quickadd"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -24,13 +28,15 @@ func setupGin() *gin.Engine {
 }
 
 func TestTaskHandler_CreateTask(t *testing.T) {
+	created := time.Date(2025, 8, 30, 9, 15, 0, 0, time.UTC)
 	tests := []struct {
-		name           string
-		userID         int
-		requestBody    any
-		setupMock      func() *task_service.TaskServiceMock
-		expectedStatus int
-		expectedBody   any
+		name             string
+		userID           int
+		requestBody      any
+		setupMock        func() *task_service.TaskServiceMock
+		expectedStatus   int
+		expectedBody     any
+		expectedLocation string
 	}{
 		{
 			name:   "success case",
@@ -42,13 +48,19 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 				mockService := new(task_service.TaskServiceMock)
 				mockService.On("CreateTask", 1, mock.MatchedBy(func(req *dto.CreateTaskRequest) bool {
 					return req.Task == "Buy Milk"
-				})).Return(nil)
+				})).Return(dto.GetTaskResponse{ID: 7, Task: "Buy Milk", Status: "pending", CreatedAt: created, UpdatedAt: created}, nil)
 				return mockService
 			},
 			expectedStatus: http.StatusCreated,
-			expectedBody: dto.CreateTaskRequest{
-				Task: "Buy Milk",
+			// Spelled out so the timestamps' JSON names are checked too.
+			expectedBody: map[string]any{
+				"id":         7,
+				"task":       "Buy Milk",
+				"status":     "pending",
+				"created_at": "2025-08-30T09:15:00Z",
+				"updated_at": "2025-08-30T09:15:00Z",
 			},
+			expectedLocation: "/tasks/7",
 		},
 		{
 			name:   "failure case - no userID",
@@ -84,7 +96,7 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 			},
 			setupMock: func() *task_service.TaskServiceMock {
 				mockService := new(task_service.TaskServiceMock)
-				mockService.On("CreateTask", 1, mock.Anything).Return(errors.New("service error"))
+				mockService.On("CreateTask", 1, mock.Anything).Return(dto.GetTaskResponse{}, errors.New("service error"))
 				return mockService
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -125,6 +137,7 @@ func TestTaskHandler_CreateTask(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
 
 			var responseBody any
 			err = json.Unmarshal(w.Body.Bytes(), &responseBody)
@@ -361,6 +374,76 @@ func TestTaskHandler_ListTasks(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_ListTasks_Query(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		setupMock      func() *task_service.TaskServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:  "success case - filtered",
+			query: "tag:work AND priority>=high",
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("FilterTasks", 1, "tag:work AND priority>=high").Return(dto.ListTasksResponse{
+					Tasks: []dto.GetTaskResponse{
+						{ID: 1, Task: "Write report", Status: "pending", Priority: "high", Tags: []string{"work"}},
+					},
+				}, nil)
+				return mockService
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: dto.ListTasksResponse{
+				Tasks: []dto.GetTaskResponse{
+					{ID: 1, Task: "Write report", Status: "pending", Priority: "high", Tags: []string{"work"}},
+				},
+			},
+		},
+		{
+			name:  "failure case - syntax error",
+			query: "status:pending AND",
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("FilterTasks", 1, "status:pending AND").Return(dto.ListTasksResponse{},
+					&taskquery.Error{Pos: 19, Message: "unexpected end of query"})
+				return mockService
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: common.ErrorResponse{
+				Message:  "unexpected end of query",
+				Position: 19,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTaskHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/tasks", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.ListTasks(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks?q="+url.QueryEscape(tt.query), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			expectedBodyBytes, err := json.Marshal(tt.expectedBody)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expectedBodyBytes), w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func TestTaskHandler_UpdateStatus(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -380,12 +463,15 @@ func TestTaskHandler_UpdateStatus(t *testing.T) {
 			},
 			setupMock: func() *task_service.TaskServiceMock {
 				mockService := new(task_service.TaskServiceMock)
-				mockService.On("UpdateStatus", 123, 1, "completed").Return(nil)
+				mockService.On("UpdateStatus", 123, 1, "completed").
+					Return(dto.GetTaskResponse{ID: 1, Task: "Buy Milk", Status: "completed"}, nil)
 				return mockService
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody: dto.UpdateStatusResponse{
-				Message: "status updated",
+			expectedBody: dto.GetTaskResponse{
+				ID:     1,
+				Task:   "Buy Milk",
+				Status: "completed",
 			},
 		},
 		{
@@ -467,7 +553,7 @@ func TestTaskHandler_UpdateStatus(t *testing.T) {
 			},
 			setupMock: func() *task_service.TaskServiceMock {
 				mockService := new(task_service.TaskServiceMock)
-				mockService.On("UpdateStatus", 123, 999, "completed").Return(gorm.ErrRecordNotFound)
+				mockService.On("UpdateStatus", 123, 999, "completed").Return(dto.GetTaskResponse{}, gorm.ErrRecordNotFound)
 				return mockService
 			},
 			expectedStatus: http.StatusNotFound,
@@ -484,7 +570,7 @@ func TestTaskHandler_UpdateStatus(t *testing.T) {
 			},
 			setupMock: func() *task_service.TaskServiceMock {
 				mockService := new(task_service.TaskServiceMock)
-				mockService.On("UpdateStatus", 123, 1, "completed").Return(errors.New("service error"))
+				mockService.On("UpdateStatus", 123, 1, "completed").Return(dto.GetTaskResponse{}, errors.New("service error"))
 				return mockService
 			},
 			expectedStatus: http.StatusBadRequest,
@@ -556,6 +642,73 @@ func TestTaskHandler_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestTaskHandler_Restore(t *testing.T) {
+	tests := []struct {
+		name           string
+		taskID         string
+		setupMock      func() *task_service.TaskServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:   "success case",
+			taskID: "5",
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("Restore", 1, 5).Return(dto.GetTaskResponse{ID: 5, Task: "Buy Milk", Status: "pending"}, nil)
+				return mockService
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.GetTaskResponse{ID: 5, Task: "Buy Milk", Status: "pending"},
+		},
+		{
+			name:   "failure case - not found",
+			taskID: "5",
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("Restore", 1, 5).Return(dto.GetTaskResponse{}, gorm.ErrRecordNotFound)
+				return mockService
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "Task not found"},
+		},
+		{
+			name:   "failure case - invalid ID",
+			taskID: "abc",
+			setupMock: func() *task_service.TaskServiceMock {
+				return new(task_service.TaskServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Invalid ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTaskHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/tasks/:id/restore", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.Restore(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/tasks/"+tt.taskID+"/restore", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			expectedBodyBytes, err := json.Marshal(tt.expectedBody)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expectedBodyBytes), w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
 func intPtr(i int) *int {
 	return &i
 }
@@ -671,3 +824,246 @@ func TestTaskHandler_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskHandler_MoveTask(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *task_service.TaskServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success case",
+			requestBody: `{"after_id":3,"before_id":8}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("Move", 1, 5, &dto.MoveTaskRequest{AfterID: 3, BeforeID: 8}).
+					Return(dto.GetTaskResponse{ID: 5, Task: "Buy Milk", Status: "pending", Position: "ai"}, nil)
+				return mockService
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.GetTaskResponse{ID: 5, Task: "Buy Milk", Status: "pending", Position: "ai"},
+		},
+		{
+			name:        "failure case - invalid anchors",
+			requestBody: `{"after_id":8,"before_id":3}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("Move", 1, 5, mock.Anything).Return(dto.GetTaskResponse{}, task_service.ErrInvalidAnchor)
+				return mockService
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: task_service.ErrInvalidAnchor.Error()},
+		},
+		{
+			name:        "failure case - column is full",
+			requestBody: `{"after_id":3,"status":"in-progress"}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("Move", 1, 5, &dto.MoveTaskRequest{AfterID: 3, Status: "in-progress"}).
+					Return(dto.GetTaskResponse{}, task_service.ErrWIPLimitReached)
+				return mockService
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   common.ErrorResponse{Message: task_service.ErrWIPLimitReached.Error()},
+		},
+		{
+			name:        "failure case - unknown column",
+			requestBody: `{"status":"blocked"}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				return new(task_service.TaskServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'MoveTaskRequest.Status' Error:Field validation for 'Status' failed on the 'oneof' tag"},
+		},
+		{
+			name:        "failure case - anchor not found",
+			requestBody: `{"after_id":99}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("Move", 1, 5, mock.Anything).Return(dto.GetTaskResponse{}, gorm.ErrRecordNotFound)
+				return mockService
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "Task not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTaskHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/tasks/:id/move", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.MoveTask(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/tasks/5/move", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			expectedBodyBytes, err := json.Marshal(tt.expectedBody)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expectedBodyBytes), w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTaskHandler_QuickAdd(t *testing.T) {
+	parsed := dto.QuickAddParsed{Task: "Pay rent", Tags: []string{"finance"}, Priority: "high", Recurrence: "FREQ=MONTHLY"}
+
+	tests := []struct {
+		name             string
+		requestBody      string
+		setupMock        func() *task_service.TaskServiceMock
+		expectedStatus   int
+		expectedBody     any
+		expectedLocation string
+	}{
+		{
+			name:        "success case",
+			requestBody: `{"text":"Pay rent #finance !high every month"}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("QuickAdd", 1, &dto.QuickAddRequest{Text: "Pay rent #finance !high every month"}).
+					Return(dto.QuickAddResponse{Task: &dto.GetTaskResponse{ID: 9, Task: "Pay rent", Status: "pending"}, Parsed: parsed, TimeZone: "UTC"}, nil)
+				return mockService
+			},
+			expectedStatus:   http.StatusCreated,
+			expectedBody:     dto.QuickAddResponse{Task: &dto.GetTaskResponse{ID: 9, Task: "Pay rent", Status: "pending"}, Parsed: parsed, TimeZone: "UTC"},
+			expectedLocation: "/tasks/9",
+		},
+		{
+			name:        "dry run",
+			requestBody: `{"text":"Pay rent #finance !high every month","dry_run":true}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("QuickAdd", 1, mock.Anything).Return(dto.QuickAddResponse{Parsed: parsed, TimeZone: "UTC"}, nil)
+				return mockService
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.QuickAddResponse{Parsed: parsed, TimeZone: "UTC"},
+		},
+		{
+			name:        "failure case - missing text",
+			requestBody: `{}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				return new(task_service.TaskServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'QuickAddRequest.Text' Error:Field validation for 'Text' failed on the 'required' tag"},
+		},
+		{
+			name:        "failure case - nothing left for the title",
+			requestBody: `{"text":"tomorrow #work"}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("QuickAdd", 1, mock.Anything).Return(dto.QuickAddResponse{}, quickadd.ErrEmptyTitle)
+				return mockService
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: quickadd.ErrEmptyTitle.Error()},
+		},
+		{
+			name:        "failure case - service error",
+			requestBody: `{"text":"Pay rent"}`,
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("QuickAdd", 1, mock.Anything).Return(dto.QuickAddResponse{}, errors.New("db down"))
+				return mockService
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   common.ErrorResponse{Message: "Couldn't create task"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTaskHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/tasks/quick", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.QuickAdd(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/tasks/quick", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+
+			expectedBodyBytes, err := json.Marshal(tt.expectedBody)
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(expectedBodyBytes), w.Body.String())
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTaskHandler_EmptyTrash(t *testing.T) {
+	tests := []struct {
+		name           string
+		setupMock      func() *task_service.TaskServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "success case",
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("EmptyTrash", 1).Return(dto.EmptyTrashResponse{Deleted: 3}, nil)
+				return mockService
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.EmptyTrashResponse{Deleted: 3},
+		},
+		{
+			name: "failure case - service error",
+			setupMock: func() *task_service.TaskServiceMock {
+				mockService := new(task_service.TaskServiceMock)
+				mockService.On("EmptyTrash", 1).Return(dto.EmptyTrashResponse{}, errors.New("database error"))
+				return mockService
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody:   common.ErrorResponse{Message: "Couldn't empty trash"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTaskHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.Use(func(c *gin.Context) {
+				c.Set("userID", 1)
+				c.Next()
+			})
+			// Registered next to /tasks/:id, as in main.go.
+			router.DELETE("/tasks/trash", handler.EmptyTrash)
+			router.DELETE("/tasks/:id", handler.Delete)
+
+			req := httptest.NewRequest(http.MethodDelete, "/tasks/trash", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package template_handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/domain/template"
+	"taskflow/internal/dto"
+	task_service "taskflow/internal/service/task"
+	template_service "taskflow/internal/service/template"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TemplateHandler struct {
+	service  template_service.TemplateServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewTemplateHandler(s template_service.TemplateServiceInterface, ua auth.UserAuthInterface) *TemplateHandler {
+	return &TemplateHandler{service: s, userAuth: ua}
+}
+
+var _ TemplateHandlerInterface = (*TemplateHandler)(nil)
+
+// CreateTemplate godoc
+// @Summary Create a task template
+// @Description Create a reusable checklist with subtasks, tags and due offsets. Texts may contain {{name}} variables.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param template body dto.TemplateRequest true "Template to create"
+// @Success 201 {object} dto.TemplateResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Router /templates [post]
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.CreateTemplate(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListTemplates godoc
+// @Summary List task templates
+// @Description Returns the user's templates sorted by name
+// @Tags templates
+// @Produce json
+// @Success 200 {object} dto.ListTemplatesResponse
+// @Router /templates [get]
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.ListTemplates(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetTemplate godoc
+// @Summary Get a task template
+// @Tags templates
+// @Produce json
+// @Param id path int true "Template ID" minimum(1) example(1)
+// @Success 200 {object} dto.TemplateResponse
+// @Failure 404 {object} common.ErrorResponse "Template not found"
+// @Router /templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid template ID"})
+		return
+	}
+
+	resp, err := h.service.GetTemplate(userID.(int), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateTemplate godoc
+// @Summary Replace a task template
+// @Description Replace all fields and subtasks of a template. Tasks created from it earlier are not changed.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID" minimum(1) example(1)
+// @Param template body dto.TemplateRequest true "New template"
+// @Success 200 {object} dto.TemplateResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Template not found"
+// @Router /templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid template ID"})
+		return
+	}
+
+	var req dto.TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.UpdateTemplate(userID.(int), id, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete a task template
+// @Description Delete a template. Tasks created from it are kept.
+// @Tags templates
+// @Produce json
+// @Param id path int true "Template ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteTemplateResponse
+// @Failure 404 {object} common.ErrorResponse "Template not found"
+// @Router /templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid template ID"})
+		return
+	}
+
+	if err := h.service.DeleteTemplate(userID.(int), id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteTemplateResponse{Message: "Template deleted successfully"})
+}
+
+// Instantiate godoc
+// @Summary Create tasks from a template
+// @Description Create the template's task and its subtasks in one transaction. Variables are substituted and due offsets are applied to the base date in the user's time zone.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path int true "Template ID" minimum(1) example(1)
+// @Param request body dto.InstantiateTemplateRequest false "Base date, variables and project"
+// @Success 201 {object} dto.InstantiateTemplateResponse
+// @Header 201 {string} Location "URL of the created parent task"
+// @Failure 400 {object} common.ErrorResponse "Invalid input, missing variable or unknown project"
+// @Failure 404 {object} common.ErrorResponse "Template not found"
+// @Router /templates/{id}/instantiate [post]
+func (h *TemplateHandler) Instantiate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid template ID"})
+		return
+	}
+
+	// The body is optional: without one the template is used as it is, from today.
+	var req dto.InstantiateTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+			return
+		}
+	}
+
+	resp, err := h.service.Instantiate(userID.(int), id, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	base := strings.TrimSuffix(c.FullPath(), "/templates/:id/instantiate")
+	c.Header("Location", fmt.Sprintf("%s/tasks/%d", base, resp.Task.ID))
+	c.JSON(http.StatusCreated, resp)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, template_service.ErrEmptyName),
+		errors.Is(err, template_service.ErrEmptyTitle),
+		errors.Is(err, template_service.ErrInvalidUser),
+		errors.Is(err, template_service.ErrInvalidBaseDate),
+		errors.Is(err, template_service.ErrUnknownProject),
+		errors.Is(err, template.ErrInvalidOffset),
+		errors.Is(err, template.ErrMissingVariable),
+		errors.Is(err, task_service.ErrTaskTooLong):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,23 @@
+package template_handler
+
+import "github.com/gin-gonic/gin"
+
+type TemplateHandlerInterface interface {
+	// CreateTemplate handles POST /api/templates
+	CreateTemplate(c *gin.Context)
+
+	// ListTemplates handles GET /api/templates
+	ListTemplates(c *gin.Context)
+
+	// GetTemplate handles GET /api/templates/:id
+	GetTemplate(c *gin.Context)
+
+	// UpdateTemplate handles PUT /api/templates/:id
+	UpdateTemplate(c *gin.Context)
+
+	// DeleteTemplate handles DELETE /api/templates/:id
+	DeleteTemplate(c *gin.Context)
+
+	// Instantiate handles POST /api/templates/:id/instantiate
+	Instantiate(c *gin.Context)
+}
@@ -0,0 +1,217 @@
+package template_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/domain/template"
+	"taskflow/internal/dto"
+	template_service "taskflow/internal/service/template"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *template_service.TemplateServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"name":"Release prep","title":"Release {{version}}","subtasks":[{"title":"Freeze","due_offset":"1d"}]}`,
+			setupMock: func() *template_service.TemplateServiceMock {
+				m := new(template_service.TemplateServiceMock)
+				m.On("CreateTemplate", 1, mock.MatchedBy(func(r *dto.TemplateRequest) bool {
+					return r.Name == "Release prep" && len(r.Subtasks) == 1 && r.Subtasks[0].DueOffset == "1d"
+				})).Return(dto.TemplateResponse{ID: 3, Name: "Release prep", Variables: []string{"version"}}, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   dto.TemplateResponse{ID: 3, Name: "Release prep", Variables: []string{"version"}},
+		},
+		{
+			name:        "failure - subtask without title",
+			requestBody: `{"name":"Release prep","title":"Release","subtasks":[{"due_offset":"1d"}]}`,
+			setupMock: func() *template_service.TemplateServiceMock {
+				return new(template_service.TemplateServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'TemplateRequest.Subtasks[0].Title' Error:Field validation for 'Title' failed on the 'required' tag"},
+		},
+		{
+			name:        "failure - invalid offset",
+			requestBody: `{"name":"Release prep","title":"Release","due_offset":"soon"}`,
+			setupMock: func() *template_service.TemplateServiceMock {
+				m := new(template_service.TemplateServiceMock)
+				m.On("CreateTemplate", 1, mock.Anything).Return(dto.TemplateResponse{}, fmt.Errorf("%w %q", template.ErrInvalidOffset, "soon"))
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: `invalid due offset "soon"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTemplateHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/templates", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.CreateTemplate(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/templates", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTemplateHandler_Instantiate(t *testing.T) {
+	created := dto.InstantiateTemplateResponse{
+		Task:     dto.GetTaskResponse{ID: 10, Task: "Release 2.4", Status: "pending"},
+		Subtasks: []dto.GetTaskResponse{{ID: 11, Task: "Freeze", Status: "pending", ParentID: intPtr(10)}},
+	}
+
+	tests := []struct {
+		name             string
+		requestBody      string
+		setupMock        func() *template_service.TemplateServiceMock
+		expectedStatus   int
+		expectedBody     any
+		expectedLocation string
+	}{
+		{
+			name:        "success",
+			requestBody: `{"base_date":"2025-09-08","variables":{"version":"2.4"}}`,
+			setupMock: func() *template_service.TemplateServiceMock {
+				m := new(template_service.TemplateServiceMock)
+				m.On("Instantiate", 1, 3, &dto.InstantiateTemplateRequest{
+					BaseDate:  "2025-09-08",
+					Variables: map[string]string{"version": "2.4"},
+				}).Return(created, nil)
+				return m
+			},
+			expectedStatus:   http.StatusCreated,
+			expectedBody:     created,
+			expectedLocation: "/api/tasks/10",
+		},
+		{
+			name: "success - without a body",
+			setupMock: func() *template_service.TemplateServiceMock {
+				m := new(template_service.TemplateServiceMock)
+				m.On("Instantiate", 1, 3, &dto.InstantiateTemplateRequest{}).Return(created, nil)
+				return m
+			},
+			expectedStatus:   http.StatusCreated,
+			expectedBody:     created,
+			expectedLocation: "/api/tasks/10",
+		},
+		{
+			name:        "failure - missing variable",
+			requestBody: `{}`,
+			setupMock: func() *template_service.TemplateServiceMock {
+				m := new(template_service.TemplateServiceMock)
+				m.On("Instantiate", 1, 3, mock.Anything).Return(dto.InstantiateTemplateResponse{}, fmt.Errorf("%w %q", template.ErrMissingVariable, "version"))
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: `no value for variable "version"`},
+		},
+		{
+			name:        "failure - template not found",
+			requestBody: `{}`,
+			setupMock: func() *template_service.TemplateServiceMock {
+				m := new(template_service.TemplateServiceMock)
+				m.On("Instantiate", 1, 3, mock.Anything).Return(dto.InstantiateTemplateResponse{}, gorm.ErrRecordNotFound)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTemplateHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/api/templates/:id/instantiate", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.Instantiate(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/templates/3/instantiate", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedLocation, w.Header().Get("Location"))
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTemplateHandler_DeleteTemplate(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "success", expectedStatus: http.StatusOK},
+		{name: "not found", serviceErr: gorm.ErrRecordNotFound, expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(template_service.TemplateServiceMock)
+			mockService.On("DeleteTemplate", 1, 3).Return(tt.serviceErr)
+			handler := NewTemplateHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.DELETE("/templates/:id", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.DeleteTemplate(c)
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/templates/3", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,294 @@
+package timeentry_handler
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	timeentry_service "taskflow/internal/service/timeentry"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TimeEntryHandler struct {
+	service  timeentry_service.TimeEntryServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewTimeEntryHandler(s timeentry_service.TimeEntryServiceInterface, ua auth.UserAuthInterface) *TimeEntryHandler {
+	return &TimeEntryHandler{service: s, userAuth: ua}
+}
+
+var _ TimeEntryHandlerInterface = (*TimeEntryHandler)(nil)
+
+// StartTimer godoc
+// @Summary Start a timer on a task
+// @Description Start tracking time on a task. Only one timer may run per user; starting another fails with 409 until the running one is stopped.
+// @Tags time
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param request body dto.StartTimerRequest false "Optional note"
+// @Success 201 {object} dto.TimeEntryResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Failure 409 {object} common.ErrorResponse "A timer is already running"
+// @Router /tasks/{id}/timer/start [post]
+func (h *TimeEntryHandler) StartTimer(c *gin.Context) {
+	userID, taskID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.StartTimerRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+			return
+		}
+	}
+
+	resp, err := h.service.StartTimer(userID, taskID, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// StopTimer godoc
+// @Summary Stop a task's timer
+// @Description Stop the running timer on a task and return the finished entry.
+// @Tags time
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Success 200 {object} dto.TimeEntryResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 409 {object} common.ErrorResponse "No timer is running on the task"
+// @Router /tasks/{id}/timer/stop [post]
+func (h *TimeEntryHandler) StopTimer(c *gin.Context) {
+	userID, taskID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.StopTimer(userID, taskID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListEntries godoc
+// @Summary List a task's time entries
+// @Description Returns a task's time entries, most recent first, with the total tracked time and what is left of the estimate. A running timer counts up to now.
+// @Tags time
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Success 200 {object} dto.TaskTimeResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Router /tasks/{id}/time-entries [get]
+func (h *TimeEntryHandler) ListEntries(c *gin.Context) {
+	userID, taskID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.ListEntries(userID, taskID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateEntry godoc
+// @Summary Add a time entry
+// @Description Record time spent on a task by hand, with either an end time or a duration. Entries may last at most 24 hours.
+// @Tags time
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param request body dto.CreateTimeEntryRequest true "Time entry"
+// @Success 201 {object} dto.TimeEntryResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Router /tasks/{id}/time-entries [post]
+func (h *TimeEntryHandler) CreateEntry(c *gin.Context) {
+	userID, taskID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateTimeEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.AddEntry(userID, taskID, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// DeleteEntry godoc
+// @Summary Delete a time entry
+// @Description Delete a time entry. Deleting a running timer discards it.
+// @Tags time
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param entryID path int true "Time entry ID" minimum(1) example(7)
+// @Success 200 {object} dto.DeleteTimeEntryResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Task or entry not found"
+// @Router /tasks/{id}/time-entries/{entryID} [delete]
+func (h *TimeEntryHandler) DeleteEntry(c *gin.Context) {
+	userID, taskID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	entryID, err := strconv.Atoi(c.Param("entryID"))
+	if err != nil || entryID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid time entry ID"})
+		return
+	}
+
+	if err := h.service.DeleteEntry(userID, taskID, entryID); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteTimeEntryResponse{Message: "Time entry deleted successfully"})
+}
+
+// SetEstimate godoc
+// @Summary Set a task's estimate
+// @Description Set the expected effort of a task in minutes, or clear it with null. Returns the task's time summary.
+// @Tags time
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID" minimum(1) example(1)
+// @Param request body dto.SetEstimateRequest true "Estimate"
+// @Success 200 {object} dto.TaskTimeResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Task not found"
+// @Router /tasks/{id}/estimate [put]
+func (h *TimeEntryHandler) SetEstimate(c *gin.Context) {
+	userID, taskID, ok := taskParams(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SetEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.SetEstimate(userID, taskID, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// TimeReport godoc
+// @Summary Time report
+// @Description Sums tracked time over a date range, grouped by day, project or tag. Dates are inclusive UTC days and default to the last seven days. An entry counts towards each of its tags. Send format=csv or Accept: text/csv for a CSV download.
+// @Tags time
+// @Produce json
+// @Produce text/csv
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param group_by query string false "Grouping (default day)" Enums(day, project, tag)
+// @Param format query string false "Response format" Enums(json, csv)
+// @Success 200 {object} dto.TimeReportResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Router /reports/time [get]
+func (h *TimeEntryHandler) TimeReport(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.Report(userID.(int), c.Query("from"), c.Query("to"), c.Query("group_by"))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if c.Query("format") == "csv" || c.Query("format") == "" && strings.Contains(c.GetHeader("Accept"), "text/csv") {
+		writeCSV(c, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func writeCSV(c *gin.Context, resp dto.TimeReportResponse) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{resp.GroupBy, "seconds", "hours"})
+	for _, row := range resp.Rows {
+		_ = w.Write([]string{row.Label, strconv.FormatInt(row.Seconds, 10), strconv.FormatFloat(row.Hours, 'f', 2, 64)})
+	}
+	_ = w.Write([]string{"total", strconv.FormatInt(resp.TotalSeconds, 10), strconv.FormatFloat(resp.TotalHours, 'f', 2, 64)})
+	w.Flush()
+
+	filename := "time-" + resp.From + "-" + resp.To + ".csv"
+	c.Header("Content-Disposition", `attachment; filename=`+filename)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// taskParams reads the user and the task ID, writing the error response
+// when either is missing or invalid.
+func taskParams(c *gin.Context) (int, int, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return 0, 0, false
+	}
+
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || taskID < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "invalid task ID"})
+		return 0, 0, false
+	}
+	return userID.(int), taskID, true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, timeentry_service.ErrTimerRunning),
+		errors.Is(err, timeentry_service.ErrNoRunningTimer):
+		c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, timeentry_service.ErrInvalidEntry),
+		errors.Is(err, timeentry_service.ErrInvalidRange),
+		errors.Is(err, timeentry_service.ErrInvalidGroup),
+		errors.Is(err, timeentry_service.ErrInvalidUser):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,26 @@
+package timeentry_handler
+
+import "github.com/gin-gonic/gin"
+
+type TimeEntryHandlerInterface interface {
+	// StartTimer handles POST /api/tasks/:id/timer/start
+	StartTimer(c *gin.Context)
+
+	// StopTimer handles POST /api/tasks/:id/timer/stop
+	StopTimer(c *gin.Context)
+
+	// ListEntries handles GET /api/tasks/:id/time-entries
+	ListEntries(c *gin.Context)
+
+	// CreateEntry handles POST /api/tasks/:id/time-entries
+	CreateEntry(c *gin.Context)
+
+	// DeleteEntry handles DELETE /api/tasks/:id/time-entries/:entryID
+	DeleteEntry(c *gin.Context)
+
+	// SetEstimate handles PUT /api/tasks/:id/estimate
+	SetEstimate(c *gin.Context)
+
+	// TimeReport handles GET /api/reports/time
+	TimeReport(c *gin.Context)
+}
@@ -0,0 +1,293 @@
+package timeentry_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	timeentry_service "taskflow/internal/service/timeentry"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestTimeEntryHandler_StartTimer(t *testing.T) {
+	started := time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+	entry := dto.TimeEntryResponse{ID: 7, TaskID: 5, StartedAt: started, Running: true}
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		setupMock      func() *timeentry_service.TimeEntryServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "success without body",
+			path: "/tasks/5/timer/start",
+			setupMock: func() *timeentry_service.TimeEntryServiceMock {
+				m := new(timeentry_service.TimeEntryServiceMock)
+				m.On("StartTimer", 1, 5, &dto.StartTimerRequest{}).Return(entry, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   entry,
+		},
+		{
+			name: "success with note",
+			path: "/tasks/5/timer/start",
+			body: `{"note":"Client call"}`,
+			setupMock: func() *timeentry_service.TimeEntryServiceMock {
+				m := new(timeentry_service.TimeEntryServiceMock)
+				m.On("StartTimer", 1, 5, &dto.StartTimerRequest{Note: "Client call"}).Return(entry, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   entry,
+		},
+		{
+			name:           "failure - invalid task ID",
+			path:           "/tasks/abc/timer/start",
+			setupMock:      func() *timeentry_service.TimeEntryServiceMock { return new(timeentry_service.TimeEntryServiceMock) },
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid task ID"},
+		},
+		{
+			name: "failure - timer already running",
+			path: "/tasks/5/timer/start",
+			setupMock: func() *timeentry_service.TimeEntryServiceMock {
+				m := new(timeentry_service.TimeEntryServiceMock)
+				m.On("StartTimer", 1, 5, mock.Anything).Return(dto.TimeEntryResponse{}, timeentry_service.ErrTimerRunning)
+				return m
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   common.ErrorResponse{Message: timeentry_service.ErrTimerRunning.Error()},
+		},
+		{
+			name: "failure - task not found",
+			path: "/tasks/5/timer/start",
+			setupMock: func() *timeentry_service.TimeEntryServiceMock {
+				m := new(timeentry_service.TimeEntryServiceMock)
+				m.On("StartTimer", 1, 5, mock.Anything).Return(dto.TimeEntryResponse{}, gorm.ErrRecordNotFound)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTimeEntryHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/tasks/:id/timer/start", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.StartTimer(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assertJSONEqual(t, tt.expectedBody, w.Body.Bytes())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTimeEntryHandler_StopTimer(t *testing.T) {
+	mockService := new(timeentry_service.TimeEntryServiceMock)
+	mockService.On("StopTimer", 1, 5).Return(dto.TimeEntryResponse{}, timeentry_service.ErrNoRunningTimer)
+	handler := NewTimeEntryHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.POST("/tasks/:id/timer/stop", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.StopTimer(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/5/timer/stop", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestTimeEntryHandler_CreateEntry(t *testing.T) {
+	mockService := new(timeentry_service.TimeEntryServiceMock)
+	mockService.On("AddEntry", 1, 5, mock.MatchedBy(func(r *dto.CreateTimeEntryRequest) bool {
+		return r.DurationMinutes == 45 && r.EndedAt == nil
+	})).Return(dto.TimeEntryResponse{ID: 8, TaskID: 5, DurationSeconds: 2700}, nil)
+	handler := NewTimeEntryHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.POST("/tasks/:id/time-entries", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.CreateEntry(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/5/time-entries",
+		bytes.NewBufferString(`{"started_at":"2025-09-01T09:00:00Z","duration_minutes":45}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+
+	req = httptest.NewRequest(http.MethodPost, "/tasks/5/time-entries", bytes.NewBufferString(`{"duration_minutes":45}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTimeEntryHandler_DeleteEntry(t *testing.T) {
+	mockService := new(timeentry_service.TimeEntryServiceMock)
+	mockService.On("DeleteEntry", 1, 5, 7).Return(nil)
+	handler := NewTimeEntryHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.DELETE("/tasks/:id/time-entries/:entryID", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.DeleteEntry(c)
+	})
+
+	req := httptest.NewRequest(http.MethodDelete, "/tasks/5/time-entries/7", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assertJSONEqual(t, dto.DeleteTimeEntryResponse{Message: "Time entry deleted successfully"}, w.Body.Bytes())
+	mockService.AssertExpectations(t)
+}
+
+func TestTimeEntryHandler_TimeReport(t *testing.T) {
+	report := dto.TimeReportResponse{
+		From:    "2025-09-01",
+		To:      "2025-09-07",
+		GroupBy: "project",
+		Rows: []dto.TimeReportRow{
+			{Key: "2", Label: "Website, v2", Seconds: 5400, Hours: 1.5},
+			{Key: "", Label: "Inbox", Seconds: 900, Hours: 0.25},
+		},
+		TotalSeconds: 6300,
+		TotalHours:   1.75,
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		accept         string
+		setupMock      func() *timeentry_service.TimeEntryServiceMock
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{
+			name:  "json",
+			query: "?from=2025-09-01&to=2025-09-07&group_by=project",
+			setupMock: func() *timeentry_service.TimeEntryServiceMock {
+				m := new(timeentry_service.TimeEntryServiceMock)
+				m.On("Report", 1, "2025-09-01", "2025-09-07", "project").Return(report, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/json; charset=utf-8",
+		},
+		{
+			name:  "csv by query",
+			query: "?from=2025-09-01&to=2025-09-07&group_by=project&format=csv",
+			setupMock: func() *timeentry_service.TimeEntryServiceMock {
+				m := new(timeentry_service.TimeEntryServiceMock)
+				m.On("Report", 1, "2025-09-01", "2025-09-07", "project").Return(report, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody:   "project,seconds,hours\n\"Website, v2\",5400,1.50\nInbox,900,0.25\ntotal,6300,1.75\n",
+		},
+		{
+			name:   "csv by accept header",
+			accept: "text/csv",
+			setupMock: func() *timeentry_service.TimeEntryServiceMock {
+				m := new(timeentry_service.TimeEntryServiceMock)
+				m.On("Report", 1, "", "", "").Return(report, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedType:   "text/csv; charset=utf-8",
+			expectedBody:   "project,seconds,hours\n\"Website, v2\",5400,1.50\nInbox,900,0.25\ntotal,6300,1.75\n",
+		},
+		{
+			name:  "failure - invalid group",
+			query: "?group_by=week",
+			setupMock: func() *timeentry_service.TimeEntryServiceMock {
+				m := new(timeentry_service.TimeEntryServiceMock)
+				m.On("Report", 1, "", "", "week").Return(dto.TimeReportResponse{}, timeentry_service.ErrInvalidGroup)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedType:   "application/json; charset=utf-8",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTimeEntryHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/reports/time", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.TimeReport(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/reports/time"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedType, w.Header().Get("Content-Type"))
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+				assert.Equal(t, "attachment; filename=time-2025-09-01-2025-09-07.csv", w.Header().Get("Content-Disposition"))
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, expected any, actual []byte) {
+	t.Helper()
+
+	expectedBytes, err := json.Marshal(expected)
+	assert.NoError(t, err)
+
+	var want, got any
+	assert.NoError(t, json.Unmarshal(expectedBytes, &want))
+	assert.NoError(t, json.Unmarshal(actual, &got))
+	assert.Equal(t, want, got)
+}
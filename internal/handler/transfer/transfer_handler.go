@@ -0,0 +1,222 @@
+package transfer_handler
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	task_service "taskflow/internal/service/task"
+	transfer_service "taskflow/internal/service/transfer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportBody bounds an uploaded file, with room for form overhead.
+const maxImportBody = 10 << 20
+
+// exportTypes are the content types of the export formats.
+var exportTypes = map[string]string{
+	transfer_service.FormatCSV:      "text/csv; charset=utf-8",
+	transfer_service.FormatJSON:     "application/json; charset=utf-8",
+	transfer_service.FormatNDJSON:   "application/x-ndjson",
+	transfer_service.FormatMarkdown: "text/markdown; charset=utf-8",
+}
+
+// exportExtensions are the file name extensions of the export formats.
+var exportExtensions = map[string]string{
+	transfer_service.FormatCSV:      ".csv",
+	transfer_service.FormatJSON:     ".json",
+	transfer_service.FormatNDJSON:   ".ndjson",
+	transfer_service.FormatMarkdown: ".md",
+}
+
+type TransferHandler struct {
+	service  transfer_service.TransferServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewTransferHandler(s transfer_service.TransferServiceInterface, ua auth.UserAuthInterface) *TransferHandler {
+	return &TransferHandler{service: s, userAuth: ua}
+}
+
+var _ TransferHandlerInterface = (*TransferHandler)(nil)
+
+// Export godoc
+// @Summary Export tasks
+// @Description Download all of your tasks, except those in the trash, as CSV, JSON, NDJSON (one task per line) or a Markdown checklist. The file is streamed as it is read.
+// @Tags tasks
+// @Produce text/csv,json,application/x-ndjson,text/markdown
+// @Param format query string false "File format" Enums(csv, json, ndjson, markdown) default(json)
+// @Success 200 {string} string "The tasks"
+// @Failure 400 {object} common.ErrorResponse "Unknown format"
+// @Router /tasks/export [get]
+func (h *TransferHandler) Export(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	format := c.DefaultQuery("format", transfer_service.FormatJSON)
+	contentType, ok := exportTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: transfer_service.ErrUnknownFormat.Error()})
+		return
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "tasks" + exportExtensions[format]}))
+	c.Status(http.StatusOK)
+	if err := h.service.Export(userID.(int), format, c.Writer); err != nil {
+		// Once tasks are sent, the status can't change; the file is cut
+		// short instead.
+		if c.Writer.Written() {
+			c.Error(err)
+			return
+		}
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		writeError(c, err)
+	}
+}
+
+// Import godoc
+// @Summary Import tasks
+// @Description Create tasks from a CSV, JSON or NDJSON file, uploaded as the "file" field of a multipart form or sent as the request body. Columns are matched to task fields by name, or by mapping[<column>]=<field> parameters. Every row is validated like a new task; if any is invalid, nothing is imported and the rows are listed. With dry_run=true the file is only checked and previewed.
+// @Tags tasks
+// @Accept multipart/form-data,text/csv,json,application/x-ndjson
+// @Produce json
+// @Param file formData file false "File to import"
+// @Param format query string false "File format; by default read from the file name or content type" Enums(csv, json, ndjson)
+// @Param dry_run query bool false "Only check and preview the file"
+// @Success 200 {object} dto.TaskImportResponse "Dry run"
+// @Success 201 {object} dto.TaskImportResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid file or mapping"
+// @Failure 413 {object} common.ErrorResponse "File too large"
+// @Failure 422 {object} dto.TaskImportResponse "Invalid rows; nothing was imported"
+// @Router /tasks/import [post]
+func (h *TransferHandler) Import(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBody)
+	var body io.Reader = c.Request.Body
+	name, contentType := "", c.ContentType()
+	if strings.HasPrefix(contentType, "multipart/") {
+		fh, err := c.FormFile("file")
+		if err != nil {
+			if isTooLarge(err) {
+				c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "file is too large"})
+				return
+			}
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "file is required"})
+			return
+		}
+		f, err := fh.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "could not read file"})
+			return
+		}
+		defer f.Close()
+		body, name, contentType = f, fh.Filename, fh.Header.Get("Content-Type")
+	}
+
+	opts := dto.TaskImportOptions{
+		Format:  c.Query("format"),
+		Mapping: c.QueryMap("mapping"),
+	}
+	if opts.Format == "" {
+		opts.Format = c.PostForm("format")
+	}
+	if opts.Format == "" {
+		opts.Format = detectFormat(name, contentType)
+	}
+	for col, field := range c.PostFormMap("mapping") {
+		opts.Mapping[col] = field
+	}
+	dryRun := c.Query("dry_run")
+	if dryRun == "" {
+		dryRun = c.PostForm("dry_run")
+	}
+	if dryRun != "" {
+		var err error
+		if opts.DryRun, err = strconv.ParseBool(dryRun); err != nil {
+			c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: "dry_run must be true or false"})
+			return
+		}
+	}
+
+	resp, err := h.service.Import(userID.(int), body, opts)
+	if errors.Is(err, transfer_service.ErrInvalidRows) {
+		c.JSON(http.StatusUnprocessableEntity, resp)
+		return
+	}
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	if opts.DryRun {
+		c.JSON(http.StatusOK, resp)
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
+
+// detectFormat guesses the format of a file from its name or content
+// type.
+func detectFormat(name, contentType string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".csv":
+		return transfer_service.FormatCSV
+	case ".json":
+		return transfer_service.FormatJSON
+	case ".ndjson", ".jsonl":
+		return transfer_service.FormatNDJSON
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return transfer_service.FormatCSV
+	case "application/json":
+		return transfer_service.FormatJSON
+	case "application/x-ndjson", "application/jsonl":
+		return transfer_service.FormatNDJSON
+	}
+	return ""
+}
+
+func isTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case isTooLarge(err):
+		c.JSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "file is too large"})
+	case errors.Is(err, task_service.ErrWIPLimitReached):
+		c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, transfer_service.ErrInvalidUser),
+		errors.Is(err, transfer_service.ErrUnknownFormat),
+		errors.Is(err, transfer_service.ErrUnknownImportFormat),
+		errors.Is(err, transfer_service.ErrInvalidFile),
+		errors.Is(err, transfer_service.ErrNoRows),
+		errors.Is(err, transfer_service.ErrTooManyRows),
+		errors.Is(err, transfer_service.ErrInvalidMapping),
+		errors.Is(err, transfer_service.ErrNoTaskColumn):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,11 @@
+package transfer_handler
+
+import "github.com/gin-gonic/gin"
+
+type TransferHandlerInterface interface {
+	// Export handles GET /api/tasks/export
+	Export(c *gin.Context)
+
+	// Import handles POST /api/tasks/import
+	Import(c *gin.Context)
+}
@@ -0,0 +1,278 @@
+package transfer_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	transfer_service "taskflow/internal/service/transfer"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestTransferHandler_Export(t *testing.T) {
+	tests := []struct {
+		name                string
+		query               string
+		setupMock           func() *transfer_service.TransferServiceMock
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			name:  "success - csv",
+			query: "?format=csv",
+			setupMock: func() *transfer_service.TransferServiceMock {
+				m := new(transfer_service.TransferServiceMock)
+				m.On("Export", 1, "csv", mock.Anything).Run(func(args mock.Arguments) {
+					io.WriteString(args.Get(2).(io.Writer), "id,task\n1,Buy milk\n")
+				}).Return(nil)
+				return m
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody:        "id,task\n1,Buy milk\n",
+		},
+		{
+			name: "success - json by default",
+			setupMock: func() *transfer_service.TransferServiceMock {
+				m := new(transfer_service.TransferServiceMock)
+				m.On("Export", 1, "json", mock.Anything).Run(func(args mock.Arguments) {
+					io.WriteString(args.Get(2).(io.Writer), `{"tasks":[]}`)
+				}).Return(nil)
+				return m
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"tasks":[]}`,
+		},
+		{
+			name:  "failure - unknown format",
+			query: "?format=xlsx",
+			setupMock: func() *transfer_service.TransferServiceMock {
+				return new(transfer_service.TransferServiceMock)
+			},
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"error":"format must be csv, json, ndjson or markdown"}`,
+		},
+		{
+			name:  "failure - before the first task",
+			query: "?format=markdown",
+			setupMock: func() *transfer_service.TransferServiceMock {
+				m := new(transfer_service.TransferServiceMock)
+				m.On("Export", 1, "markdown", mock.Anything).Return(errors.New("db error"))
+				return m
+			},
+			expectedStatus:      http.StatusInternalServerError,
+			expectedContentType: "application/json; charset=utf-8",
+			expectedBody:        `{"error":"db error"}`,
+		},
+		{
+			name:  "failure - after the first tasks",
+			query: "?format=ndjson",
+			setupMock: func() *transfer_service.TransferServiceMock {
+				m := new(transfer_service.TransferServiceMock)
+				m.On("Export", 1, "ndjson", mock.Anything).Run(func(args mock.Arguments) {
+					io.WriteString(args.Get(2).(io.Writer), "{\"id\":1}\n")
+				}).Return(errors.New("db error"))
+				return m
+			},
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        "{\"id\":1}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTransferHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.GET("/tasks/export", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.Export(c)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/tasks/export"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedContentType, w.Header().Get("Content-Type"))
+			assert.Equal(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferHandler_Export_Unauthorized(t *testing.T) {
+	handler := NewTransferHandler(new(transfer_service.TransferServiceMock), new(auth.MockUserAuth))
+	router := setupGin()
+	router.GET("/tasks/export", handler.Export)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tasks/export", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTransferHandler_Import(t *testing.T) {
+	imported := dto.TaskImportResponse{
+		Mapping:  map[string]string{"Title": "task"},
+		Rows:     1,
+		Imported: 1,
+		Tasks:    []dto.GetTaskResponse{{ID: 8, Task: "Buy milk", Status: "pending"}},
+		Errors:   []dto.TaskImportRowError{},
+	}
+	invalid := dto.TaskImportResponse{
+		Mapping: map[string]string{"Title": "task"},
+		Rows:    1,
+		Tasks:   []dto.GetTaskResponse{},
+		Errors:  []dto.TaskImportRowError{{Row: 2, Column: "Title", Message: "task name cannot be empty"}},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		contentType    string
+		body           string
+		setupMock      func() *transfer_service.TransferServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success - csv body",
+			query:       "?mapping[Title]=task",
+			contentType: "text/csv",
+			body:        "Title\nBuy milk\n",
+			setupMock: func() *transfer_service.TransferServiceMock {
+				m := new(transfer_service.TransferServiceMock)
+				m.On("Import", 1, mock.Anything, dto.TaskImportOptions{Format: "csv", Mapping: map[string]string{"Title": "task"}}).Return(imported, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   imported,
+		},
+		{
+			name:        "success - dry run",
+			query:       "?format=ndjson&dry_run=true",
+			contentType: "application/octet-stream",
+			body:        `{"task":"Buy milk"}`,
+			setupMock: func() *transfer_service.TransferServiceMock {
+				m := new(transfer_service.TransferServiceMock)
+				m.On("Import", 1, mock.Anything, dto.TaskImportOptions{Format: "ndjson", Mapping: map[string]string{}, DryRun: true}).Return(dto.TaskImportResponse{DryRun: true}, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.TaskImportResponse{DryRun: true},
+		},
+		{
+			name:        "failure - invalid rows",
+			contentType: "application/json",
+			body:        `[{"Title":""}]`,
+			setupMock: func() *transfer_service.TransferServiceMock {
+				m := new(transfer_service.TransferServiceMock)
+				m.On("Import", 1, mock.Anything, mock.Anything).Return(invalid, transfer_service.ErrInvalidRows)
+				return m
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   invalid,
+		},
+		{
+			name:        "failure - unknown format",
+			contentType: "text/plain",
+			body:        "Buy milk",
+			setupMock: func() *transfer_service.TransferServiceMock {
+				m := new(transfer_service.TransferServiceMock)
+				m.On("Import", 1, mock.Anything, dto.TaskImportOptions{Mapping: map[string]string{}}).Return(dto.TaskImportResponse{}, transfer_service.ErrUnknownImportFormat)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: transfer_service.ErrUnknownImportFormat.Error()},
+		},
+		{
+			name:        "failure - invalid dry_run",
+			query:       "?dry_run=maybe",
+			contentType: "text/csv",
+			body:        "task\nBuy milk\n",
+			setupMock: func() *transfer_service.TransferServiceMock {
+				return new(transfer_service.TransferServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "dry_run must be true or false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewTransferHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/tasks/import", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.Import(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/tasks/import"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTransferHandler_Import_Multipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "tasks.jsonl")
+	require.NoError(t, err)
+	fw.Write([]byte("{\"What\":\"Buy milk\"}\n"))
+	require.NoError(t, mw.WriteField("mapping[What]", "task"))
+	require.NoError(t, mw.WriteField("dry_run", "1"))
+	require.NoError(t, mw.Close())
+
+	mockService := new(transfer_service.TransferServiceMock)
+	mockService.On("Import", 1, mock.MatchedBy(func(r io.Reader) bool {
+		data, _ := io.ReadAll(r)
+		return string(data) == "{\"What\":\"Buy milk\"}\n"
+	}), dto.TaskImportOptions{Format: "ndjson", Mapping: map[string]string{"What": "task"}, DryRun: true}).Return(dto.TaskImportResponse{DryRun: true}, nil)
+	handler := NewTransferHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.POST("/tasks/import", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.Import(c)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/tasks/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+}
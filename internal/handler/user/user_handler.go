@@ -205,3 +205,51 @@ func (h *UserHandler) DeleteUser(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// UpdateTimeZone godoc
+// @Summary Update user time zone
+// @Description Set the IANA time zone used for day and week boundaries in statistics (requires authentication)
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UpdateTimeZoneRequest true "Time zone"
+// @Success 200 {object} dto.UpdateTimeZoneResponse
+// @Failure 400 {object} common.ErrorResponse
+// @Failure 401 {object} common.ErrorResponse
+// @Failure 404 {object} common.ErrorResponse
+// @Router /users/timezone [patch]
+func (h *UserHandler) UpdateTimeZone(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	val, ok := userID.(int)
+	if !ok {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{
+			Message: "invalid userID in context",
+		})
+		return
+	}
+
+	var req dto.UpdateTimeZoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+	req.ID = val
+
+	resp, err := h.service.UpdateTimeZone(&req)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "user not found"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
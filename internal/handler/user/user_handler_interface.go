@@ -7,4 +7,5 @@ type UserHandlerInterface interface {
 	Login(c *gin.Context)
 	UpdatePassword(c *gin.Context)
 	DeleteUser(c *gin.Context)
+	UpdateTimeZone(c *gin.Context)
 }
@@ -724,3 +724,72 @@ func TestUserHandler_DeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUserHandler_UpdateTimeZone(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func() *user_service.UserServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "success case",
+			body: `{"time_zone":"Europe/Berlin"}`,
+			setupMock: func() *user_service.UserServiceMock {
+				m := new(user_service.UserServiceMock)
+				m.On("UpdateTimeZone", &dto.UpdateTimeZoneRequest{ID: 1, TimeZone: "Europe/Berlin"}).
+					Return(&dto.UpdateTimeZoneResponse{TimeZone: "Europe/Berlin"}, nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.UpdateTimeZoneResponse{TimeZone: "Europe/Berlin"},
+		},
+		{
+			name: "unknown zone -> 400",
+			body: `{"time_zone":"Mars/Olympus"}`,
+			setupMock: func() *user_service.UserServiceMock {
+				m := new(user_service.UserServiceMock)
+				m.On("UpdateTimeZone", mock.Anything).Return(nil, user_service.ErrInvalidTimeZone)
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: user_service.ErrInvalidTimeZone.Error()},
+		},
+		{
+			name: "missing zone -> 400",
+			body: `{}`,
+			setupMock: func() *user_service.UserServiceMock {
+				return new(user_service.UserServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := tt.setupMock()
+			handler := NewUserHandler(mockSvc, nil)
+
+			router := setupGin()
+			router.PATCH("/users/timezone", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.UpdateTimeZone(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPatch, "/users/timezone", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				exp, _ := json.Marshal(tt.expectedBody)
+				assert.JSONEq(t, string(exp), w.Body.String())
+			}
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package webhook_handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	webhook_service "taskflow/internal/service/webhook"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type WebhookHandler struct {
+	service  webhook_service.WebhookServiceInterface
+	userAuth auth.UserAuthInterface
+}
+
+func NewWebhookHandler(s webhook_service.WebhookServiceInterface, ua auth.UserAuthInterface) *WebhookHandler {
+	return &WebhookHandler{service: s, userAuth: ua}
+}
+
+var _ WebhookHandlerInterface = (*WebhookHandler)(nil)
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description Register an endpoint that receives signed task events. The response contains the signing secret, which is not shown again.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body dto.WebhookRequest true "Endpoint and events"
+// @Success 201 {object} dto.WebhookResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	var req dto.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.Create(userID.(int), &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Description Returns the user's webhooks
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} dto.ListWebhooksResponse
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	resp, err := h.service.List(userID.(int))
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID" minimum(1) example(1)
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Webhook not found"
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c, "id", "invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Get(userID.(int), id)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Replace a webhook's URL and events. Set active to true to re-enable an endpoint that was disabled after repeated failures.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID" minimum(1) example(1)
+// @Param webhook body dto.WebhookRequest true "Endpoint and events"
+// @Success 200 {object} dto.WebhookResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid input"
+// @Failure 404 {object} common.ErrorResponse "Webhook not found"
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c, "id", "invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	var req dto.WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+		return
+	}
+
+	resp, err := h.service.Update(userID.(int), id, &req)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook and its delivery log. Queued deliveries are dropped.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID" minimum(1) example(1)
+// @Success 200 {object} dto.DeleteWebhookResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Webhook not found"
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c, "id", "invalid webhook ID")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(userID.(int), id); err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DeleteWebhookResponse{Message: "Webhook deleted successfully"})
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Returns a page of the webhook's delivery log, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID" minimum(1) example(1)
+// @Param page query int false "Page number" minimum(1) default(1)
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(20)
+// @Success 200 {object} dto.ListWebhookDeliveriesResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Webhook not found"
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c, "id", "invalid webhook ID")
+	if !ok {
+		return
+	}
+	page, limit := common.ParsePagination(c.Query("page"), c.Query("limit"))
+
+	resp, err := h.service.ListDeliveries(userID.(int), id, page, limit)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Redeliver godoc
+// @Summary Redeliver a webhook event
+// @Description Queue the event of an earlier delivery again. The new delivery has its own log entry and the same event ID.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID" minimum(1) example(1)
+// @Param deliveryID path int true "Delivery ID" minimum(1) example(7)
+// @Success 202 {object} dto.WebhookDeliveryResponse
+// @Failure 400 {object} common.ErrorResponse "Invalid ID"
+// @Failure 404 {object} common.ErrorResponse "Delivery not found"
+// @Failure 409 {object} common.ErrorResponse "Webhook is disabled"
+// @Router /webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, common.ErrorResponse{Message: "unauthorized"})
+		return
+	}
+
+	id, ok := parseID(c, "id", "invalid webhook ID")
+	if !ok {
+		return
+	}
+	deliveryID, ok := parseID(c, "deliveryID", "invalid delivery ID")
+	if !ok {
+		return
+	}
+
+	resp, err := h.service.Redeliver(userID.(int), id, deliveryID)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+func parseID(c *gin.Context, param string, message string) (int, bool) {
+	id, err := strconv.Atoi(c.Param(param))
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: message})
+		return 0, false
+	}
+	return id, true
+}
+
+func writeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, common.ErrorResponse{Message: "not found"})
+	case errors.Is(err, webhook_service.ErrDisabled):
+		c.JSON(http.StatusConflict, common.ErrorResponse{Message: err.Error()})
+	case errors.Is(err, webhook_service.ErrInvalidUser),
+		errors.Is(err, webhook_service.ErrInvalidURL),
+		errors.Is(err, webhook_service.ErrPrivateAddress),
+		errors.Is(err, webhook_service.ErrInvalidEvent):
+		c.JSON(http.StatusBadRequest, common.ErrorResponse{Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+	}
+}
@@ -0,0 +1,26 @@
+package webhook_handler
+
+import "github.com/gin-gonic/gin"
+
+type WebhookHandlerInterface interface {
+	// CreateWebhook handles POST /api/webhooks
+	CreateWebhook(c *gin.Context)
+
+	// ListWebhooks handles GET /api/webhooks
+	ListWebhooks(c *gin.Context)
+
+	// GetWebhook handles GET /api/webhooks/:id
+	GetWebhook(c *gin.Context)
+
+	// UpdateWebhook handles PUT /api/webhooks/:id
+	UpdateWebhook(c *gin.Context)
+
+	// DeleteWebhook handles DELETE /api/webhooks/:id
+	DeleteWebhook(c *gin.Context)
+
+	// ListDeliveries handles GET /api/webhooks/:id/deliveries
+	ListDeliveries(c *gin.Context)
+
+	// Redeliver handles POST /api/webhooks/:id/deliveries/:deliveryID/redeliver
+	Redeliver(c *gin.Context)
+}
@@ -0,0 +1,256 @@
+package webhook_handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"taskflow/internal/auth"
+	"taskflow/internal/common"
+	"taskflow/internal/dto"
+	webhook_service "taskflow/internal/service/webhook"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func setupGin() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	created := dto.WebhookResponse{
+		ID:     1,
+		URL:    "https://example.com/hooks",
+		Events: []string{"task.created"},
+		Active: true,
+		Secret: "whsec_4f0c9d2e8a7b6c5d4e3f2a1b0c9d8e7f",
+	}
+
+	tests := []struct {
+		name           string
+		requestBody    string
+		setupMock      func() *webhook_service.WebhookServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:        "success",
+			requestBody: `{"url":"https://example.com/hooks","events":["task.created"]}`,
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				m := new(webhook_service.WebhookServiceMock)
+				m.On("Create", 1, &dto.WebhookRequest{URL: "https://example.com/hooks", Events: []string{"task.created"}}).Return(created, nil)
+				return m
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   created,
+		},
+		{
+			name:        "failure - unknown event",
+			requestBody: `{"url":"https://example.com/hooks","events":["task.archived"]}`,
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				m := new(webhook_service.WebhookServiceMock)
+				m.On("Create", 1, &dto.WebhookRequest{URL: "https://example.com/hooks", Events: []string{"task.archived"}}).
+					Return(dto.WebhookResponse{}, fmt.Errorf("%w %q", webhook_service.ErrInvalidEvent, "task.archived"))
+				return m
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: fmt.Sprintf("%s %q", webhook_service.ErrInvalidEvent, "task.archived")},
+		},
+		{
+			name:        "failure - missing events",
+			requestBody: `{"url":"https://example.com/hooks"}`,
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				return new(webhook_service.WebhookServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "Key: 'WebhookRequest.Events' Error:Field validation for 'Events' failed on the 'required' tag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewWebhookHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/webhooks", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.CreateWebhook(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(tt.requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_DeleteWebhook(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		setupMock      func() *webhook_service.WebhookServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name: "success",
+			id:   "1",
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				m := new(webhook_service.WebhookServiceMock)
+				m.On("Delete", 1, 1).Return(nil)
+				return m
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   dto.DeleteWebhookResponse{Message: "Webhook deleted successfully"},
+		},
+		{
+			name: "failure - not found",
+			id:   "9",
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				m := new(webhook_service.WebhookServiceMock)
+				m.On("Delete", 1, 9).Return(gorm.ErrRecordNotFound)
+				return m
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   common.ErrorResponse{Message: "not found"},
+		},
+		{
+			name: "failure - invalid ID",
+			id:   "abc",
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				return new(webhook_service.WebhookServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid webhook ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewWebhookHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.DELETE("/webhooks/:id", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.DeleteWebhook(c)
+			})
+
+			req := httptest.NewRequest(http.MethodDelete, "/webhooks/"+tt.id, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	list := dto.ListWebhookDeliveriesResponse{
+		Deliveries: []dto.WebhookDeliveryResponse{{ID: 7, WebhookID: 1, EventID: "abc", Event: "task.created", Payload: json.RawMessage(`{}`), Status: "succeeded", Attempts: 1, ResponseStatus: 200}},
+		Page:       2,
+		Limit:      10,
+		Total:      11,
+	}
+
+	mockService := new(webhook_service.WebhookServiceMock)
+	mockService.On("ListDeliveries", 1, 1, 2, 10).Return(list, nil)
+	handler := NewWebhookHandler(mockService, new(auth.MockUserAuth))
+
+	router := setupGin()
+	router.GET("/webhooks/:id/deliveries", func(c *gin.Context) {
+		c.Set("userID", 1)
+		handler.ListDeliveries(c)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries?page=2&limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	expected, _ := json.Marshal(list)
+	assert.JSONEq(t, string(expected), w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_Redeliver(t *testing.T) {
+	original := 7
+	redelivery := dto.WebhookDeliveryResponse{ID: 8, WebhookID: 1, EventID: "abc", Event: "task.created", Payload: json.RawMessage(`{}`), Status: "pending", RedeliveryOf: &original}
+
+	tests := []struct {
+		name           string
+		deliveryID     string
+		setupMock      func() *webhook_service.WebhookServiceMock
+		expectedStatus int
+		expectedBody   any
+	}{
+		{
+			name:       "success",
+			deliveryID: "7",
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				m := new(webhook_service.WebhookServiceMock)
+				m.On("Redeliver", 1, 1, 7).Return(redelivery, nil)
+				return m
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedBody:   redelivery,
+		},
+		{
+			name:       "failure - disabled",
+			deliveryID: "7",
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				m := new(webhook_service.WebhookServiceMock)
+				m.On("Redeliver", 1, 1, 7).Return(dto.WebhookDeliveryResponse{}, webhook_service.ErrDisabled)
+				return m
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   common.ErrorResponse{Message: webhook_service.ErrDisabled.Error()},
+		},
+		{
+			name:       "failure - invalid delivery ID",
+			deliveryID: "0",
+			setupMock: func() *webhook_service.WebhookServiceMock {
+				return new(webhook_service.WebhookServiceMock)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   common.ErrorResponse{Message: "invalid delivery ID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := tt.setupMock()
+			handler := NewWebhookHandler(mockService, new(auth.MockUserAuth))
+
+			router := setupGin()
+			router.POST("/webhooks/:id/deliveries/:deliveryID/redeliver", func(c *gin.Context) {
+				c.Set("userID", 1)
+				handler.Redeliver(c)
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/1/deliveries/"+tt.deliveryID+"/redeliver", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			expected, _ := json.Marshal(tt.expectedBody)
+			assert.JSONEq(t, string(expected), w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// Package importer reads the exports of other task managers, such as
+// Todoist backups, Trello boards and todo.txt files, into tasks that can
+// be imported into TaskFlow.
+package importer
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// Sources of the importers in this package.
+const (
+	SourceTodoist = "todoist"
+	SourceTrello  = "trello"
+	SourceTodoTxt = "todotxt"
+)
+
+// ErrInvalidFile is returned, wrapped, for files an importer can't read.
+var ErrInvalidFile = errors.New("file could not be read")
+
+// Task is a task as read from another tool.
+type Task struct {
+	// ID identifies the task within its source and stays the same across
+	// exports, so that importing a file again can skip the task.
+	ID string
+	// ParentID is the ID of the task this one is a subtask of.
+	ParentID    string
+	Name        string
+	Description string
+	// Project is the name of the task's project; empty is the inbox.
+	Project string
+	Labels  []string
+	// Priority is a TaskFlow priority name, or empty for none.
+	Priority    string
+	DueAt       *time.Time
+	Completed   bool
+	CompletedAt *time.Time
+	// InProgress marks open tasks that were started.
+	InProgress bool
+	// Recurrence is an RRULE, such as FREQ=WEEKLY.
+	Recurrence string
+}
+
+// Importer reads one file format.
+type Importer interface {
+	// Parse reads the tasks of a file.
+	Parse(r io.Reader) ([]Task, error)
+}
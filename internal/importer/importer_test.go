@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func day(y int, m time.Month, d int) *time.Time {
+	t := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return &t
+}
+
+func TestTodoist_Parse(t *testing.T) {
+	backup := `{
+		"projects": [
+			{"id": "100", "name": "Inbox", "inbox_project": true},
+			{"id": "200", "name": "Home"}
+		],
+		"labels": [{"id": "9", "name": "errands"}],
+		"items": [
+			{"id": "1", "project_id": "200", "content": "Paint fence", "description": "White", "priority": 4,
+			 "due": {"date": "2025-09-01"}, "labels": ["Weekend"], "checked": false},
+			{"id": "2", "project_id": "200", "parent_id": "1", "content": "Buy paint", "priority": 1,
+			 "due": {"date": "2025-08-30T09:00:00", "timezone": "Europe/Berlin"}, "checked": true,
+			 "completed_at": "2025-08-29T18:00:00.000000Z"},
+			{"id": 3, "project_id": 100, "content": "Call mom", "priority": 3, "labels": [9], "checked": 1,
+			 "due": {"date": "2025-09-02T17:00:00Z"}},
+			{"id": "4", "project_id": "200", "content": "Gone", "is_deleted": true}
+		]
+	}`
+
+	tasks, err := NewTodoist().Parse(strings.NewReader(backup))
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+
+	assert.Equal(t, Task{
+		ID: "1", Name: "Paint fence", Description: "White", Project: "Home",
+		Labels: []string{"Weekend"}, Priority: "urgent", DueAt: day(2025, 9, 1),
+	}, tasks[0])
+
+	due := time.Date(2025, 8, 30, 7, 0, 0, 0, time.UTC)
+	completed := time.Date(2025, 8, 29, 18, 0, 0, 0, time.UTC)
+	assert.Equal(t, Task{
+		ID: "2", ParentID: "1", Name: "Buy paint", Project: "Home",
+		DueAt: &due, Completed: true, CompletedAt: &completed,
+	}, tasks[1])
+
+	assert.Equal(t, "3", tasks[2].ID, "numeric IDs of older backups")
+	assert.Equal(t, "", tasks[2].Project, "the inbox project is the inbox")
+	assert.Equal(t, []string{"errands"}, tasks[2].Labels, "label IDs are resolved")
+	assert.Equal(t, "high", tasks[2].Priority)
+	assert.True(t, tasks[2].Completed)
+	assert.Nil(t, tasks[2].CompletedAt)
+	assert.Equal(t, time.Date(2025, 9, 2, 17, 0, 0, 0, time.UTC), *tasks[2].DueAt)
+}
+
+func TestTodoist_Parse_Invalid(t *testing.T) {
+	for _, file := range []string{`not json`, `{"name": "Board"}`, `{"items": [{"id": "1", "due": {"date": "tomorrow"}}]}`} {
+		_, err := NewTodoist().Parse(strings.NewReader(file))
+		assert.ErrorIs(t, err, ErrInvalidFile, file)
+	}
+}
+
+func TestTrello_Parse(t *testing.T) {
+	board := `{
+		"name": "Launch",
+		"lists": [
+			{"id": "l1", "name": "Backlog"},
+			{"id": "l2", "name": "Doing"},
+			{"id": "l3", "name": "Done"},
+			{"id": "l4", "name": "Old", "closed": true}
+		],
+		"cards": [
+			{"id": "c1", "name": "Write copy", "desc": "For the landing page", "idList": "l1",
+			 "labels": [{"name": "Marketing", "color": "green"}, {"name": "", "color": "red"}],
+			 "due": "2025-09-01T17:00:00.000Z", "dueComplete": false},
+			{"id": "c2", "name": "Set up DNS", "idList": "l2", "labels": []},
+			{"id": "c3", "name": "Buy domain", "idList": "l3", "labels": [], "dateLastActivity": "2025-08-20T10:00:00.000Z"},
+			{"id": "c4", "name": "Order swag", "idList": "l1", "labels": [], "dueComplete": true, "dateLastActivity": "2025-08-21T10:00:00.000Z"},
+			{"id": "c5", "name": "Archived", "idList": "l1", "closed": true},
+			{"id": "c6", "name": "In an old list", "idList": "l4"}
+		],
+		"checklists": [
+			{"idCard": "c1", "name": "Sections", "pos": 2, "checkItems": [
+				{"name": "Pricing", "state": "incomplete", "pos": 2},
+				{"name": "Hero", "state": "complete", "pos": 1}
+			]},
+			{"idCard": "c1", "name": "Review", "pos": 1, "checkItems": [{"name": "Legal", "state": "incomplete", "pos": 1}]}
+		]
+	}`
+
+	tasks, err := NewTrello().Parse(strings.NewReader(board))
+	require.NoError(t, err)
+	require.Len(t, tasks, 4)
+
+	due := time.Date(2025, 9, 1, 17, 0, 0, 0, time.UTC)
+	assert.Equal(t, "c1", tasks[0].ID)
+	assert.Equal(t, "Launch", tasks[0].Project)
+	assert.Equal(t, []string{"Backlog", "Marketing", "red"}, tasks[0].Labels)
+	assert.Equal(t, &due, tasks[0].DueAt)
+	assert.False(t, tasks[0].Completed)
+	assert.Equal(t, "For the landing page\n\nReview:\n- [ ] Legal\n\nSections:\n- [x] Hero\n- [ ] Pricing", tasks[0].Description)
+
+	assert.True(t, tasks[1].InProgress)
+	assert.Empty(t, tasks[1].Labels, "status lists are not labels")
+
+	assert.True(t, tasks[2].Completed)
+	assert.Equal(t, time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC), *tasks[2].CompletedAt)
+
+	assert.True(t, tasks[3].Completed, "a completed due date completes the card")
+}
+
+func TestTrello_Parse_Invalid(t *testing.T) {
+	for _, file := range []string{`[`, `{"items": []}`} {
+		_, err := NewTrello().Parse(strings.NewReader(file))
+		assert.ErrorIs(t, err, ErrInvalidFile, file)
+	}
+}
+
+func TestTodoTxt_Parse(t *testing.T) {
+	file := "\ufeff(A) 2025-08-01 Call Mom +Family @phone due:2025-09-05\n" +
+		"\n" +
+		"x 2025-08-20 2025-08-01 Pay rent +Home +Bills pri:B rec:+1m\n" +
+		"(D) Read https://example.com later:maybe\n" +
+		"Water plants rec:2w\n" +
+		"Water plants rec:2w\n" +
+		"Stretch rec:1b\n"
+
+	tasks, err := NewTodoTxt().Parse(strings.NewReader(file))
+	require.NoError(t, err)
+	require.Len(t, tasks, 6)
+
+	assert.Equal(t, "Call Mom", tasks[0].Name)
+	assert.Equal(t, "urgent", tasks[0].Priority)
+	assert.Equal(t, "Family", tasks[0].Project)
+	assert.Equal(t, []string{"phone"}, tasks[0].Labels)
+	assert.Equal(t, day(2025, 9, 5), tasks[0].DueAt)
+	assert.False(t, tasks[0].Completed)
+
+	assert.Equal(t, "Pay rent", tasks[1].Name)
+	assert.Equal(t, "Home", tasks[1].Project)
+	assert.Equal(t, []string{"Bills"}, tasks[1].Labels)
+	assert.Equal(t, "high", tasks[1].Priority)
+	assert.True(t, tasks[1].Completed)
+	assert.Equal(t, day(2025, 8, 20), tasks[1].CompletedAt)
+	assert.Equal(t, "FREQ=MONTHLY", tasks[1].Recurrence)
+
+	assert.Equal(t, "Read https://example.com later:maybe", tasks[2].Name)
+	assert.Equal(t, "low", tasks[2].Priority)
+
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=2", tasks[3].Recurrence)
+	assert.NotEqual(t, tasks[3].ID, tasks[4].ID, "repeated lines are different tasks")
+
+	assert.Equal(t, "Stretch rec:1b", tasks[5].Name)
+	assert.Empty(t, tasks[5].Recurrence)
+}
+
+func TestTodoTxt_Parse_StableIDs(t *testing.T) {
+	before, err := NewTodoTxt().Parse(strings.NewReader("(B) 2025-08-01 Pay rent +Home due:2025-09-01\nWater plants\nWater plants\n"))
+	require.NoError(t, err)
+	after, err := NewTodoTxt().Parse(strings.NewReader("x 2025-09-01 2025-08-01 Pay rent +Home due:2025-10-01 pri:B\nWater plants\nWater plants\n"))
+	require.NoError(t, err)
+
+	require.Len(t, after, 3)
+	for i := range before {
+		assert.Equal(t, before[i].ID, after[i].ID)
+	}
+	assert.True(t, after[0].Completed)
+}
@@ -0,0 +1,188 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Todoist reads Todoist backups in the JSON format of its Sync API, with
+// projects, items and labels. Todoist's priority 4 (shown as p1) is
+// urgent; deleted items are skipped. Recurring due dates keep their next
+// occurrence only.
+type Todoist struct{}
+
+func NewTodoist() *Todoist {
+	return &Todoist{}
+}
+
+var _ Importer = (*Todoist)(nil)
+
+type todoistBackup struct {
+	Projects []struct {
+		ID           flexString `json:"id"`
+		Name         string     `json:"name"`
+		InboxProject bool       `json:"inbox_project"`
+	} `json:"projects"`
+	Items  []todoistItem `json:"items"`
+	Labels []struct {
+		ID   flexString `json:"id"`
+		Name string     `json:"name"`
+	} `json:"labels"`
+}
+
+type todoistItem struct {
+	ID          flexString `json:"id"`
+	ProjectID   flexString `json:"project_id"`
+	ParentID    flexString `json:"parent_id"`
+	Content     string     `json:"content"`
+	Description string     `json:"description"`
+	Priority    int        `json:"priority"`
+	Due         *struct {
+		Date     string `json:"date"`
+		Timezone string `json:"timezone"`
+	} `json:"due"`
+	// Labels are names, or label IDs in older backups.
+	Labels      []flexString `json:"labels"`
+	Checked     flexBool     `json:"checked"`
+	IsDeleted   flexBool     `json:"is_deleted"`
+	CompletedAt string       `json:"completed_at"`
+	// DateCompleted is CompletedAt in older backups.
+	DateCompleted string `json:"date_completed"`
+}
+
+var todoistPriorities = map[int]string{4: "urgent", 3: "high", 2: "medium"}
+
+func (Todoist) Parse(r io.Reader) ([]Task, error) {
+	var backup todoistBackup
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	if backup.Items == nil {
+		return nil, fmt.Errorf("%w: not a Todoist backup, it has no items", ErrInvalidFile)
+	}
+
+	projects := make(map[string]string, len(backup.Projects))
+	for _, p := range backup.Projects {
+		if !p.InboxProject {
+			projects[string(p.ID)] = p.Name
+		}
+	}
+	labels := make(map[string]string, len(backup.Labels))
+	for _, l := range backup.Labels {
+		labels[string(l.ID)] = l.Name
+	}
+
+	tasks := make([]Task, 0, len(backup.Items))
+	for _, item := range backup.Items {
+		if item.IsDeleted {
+			continue
+		}
+		t := Task{
+			ID:          string(item.ID),
+			ParentID:    string(item.ParentID),
+			Name:        item.Content,
+			Description: item.Description,
+			Project:     projects[string(item.ProjectID)],
+			Priority:    todoistPriorities[item.Priority],
+		}
+		for _, l := range item.Labels {
+			if name, ok := labels[string(l)]; ok {
+				l = flexString(name)
+			}
+			t.Labels = append(t.Labels, string(l))
+		}
+		if item.Due != nil && item.Due.Date != "" {
+			at, err := todoistDate(item.Due.Date, item.Due.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("%w: item %s: %w", ErrInvalidFile, item.ID, err)
+			}
+			t.DueAt = &at
+		}
+		completed := item.CompletedAt
+		if completed == "" {
+			completed = item.DateCompleted
+		}
+		t.Completed = bool(item.Checked) || completed != ""
+		if at, err := parseTodoistCompleted(completed); err == nil {
+			t.CompletedAt = &at
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// todoistDate reads a due date, which is a day, a time in the due
+// date's time zone, or a time in UTC. Days are midnight UTC.
+func todoistDate(date, timezone string) (time.Time, error) {
+	if len(date) == len("2006-01-02") {
+		return time.Parse("2006-01-02", date)
+	}
+	if strings.HasSuffix(date, "Z") {
+		return time.Parse(time.RFC3339, date)
+	}
+	loc := time.UTC
+	if timezone != "" {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return time.Time{}, err
+		}
+	}
+	at, err := time.ParseInLocation("2006-01-02T15:04:05", date, loc)
+	return at.UTC(), err
+}
+
+func parseTodoistCompleted(s string) (time.Time, error) {
+	at, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		at, err = time.Parse("Mon 02 Jan 2006 15:04:05 -0700", s)
+	}
+	return at.UTC(), err
+}
+
+// flexString reads a JSON string or number, as IDs were numbers in older
+// exports and are strings now.
+type flexString string
+
+func (s *flexString) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*s = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		*s = flexString(str)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*s = flexString(n.String())
+	return nil
+}
+
+// flexBool reads a JSON boolean, or a number where 0 is false.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true":
+		*b = true
+	case "false", "null", "0":
+		*b = false
+	default:
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("invalid boolean %s", data)
+		}
+		*b = true
+	}
+	return nil
+}
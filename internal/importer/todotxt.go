@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TodoTxt reads todo.txt files (see todotxt.org), one task per line. The
+// first +project is the project; contexts and further projects become
+// labels. Priorities (A) to (C) are urgent, high and medium, and lower
+// ones low. due:YYYY-MM-DD sets the due date and rec:1w, with d, w, m or
+// y, the recurrence; other key:value tags, and values that can't be
+// read, stay in the name.
+//
+// Lines have no IDs, so a task is identified by its text without the
+// completion mark, dates, priority and recurrence: completing a task in
+// the file, or moving its due date, and importing it again skips it rather
+// than adding it twice.
+type TodoTxt struct{}
+
+func NewTodoTxt() *TodoTxt {
+	return &TodoTxt{}
+}
+
+var _ Importer = (*TodoTxt)(nil)
+
+// maxTodoTxtLine bounds a line of a todo.txt file.
+const maxTodoTxtLine = 64 << 10
+
+var todoTxtPriorities = map[byte]string{'A': "urgent", 'B': "high", 'C': "medium"}
+
+var todoTxtFrequencies = map[byte]string{'d': "DAILY", 'w': "WEEKLY", 'm': "MONTHLY", 'y': "YEARLY"}
+
+func (TodoTxt) Parse(r io.Reader) ([]Task, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), maxTodoTxtLine)
+
+	var tasks []Task
+	seen := map[string]int{}
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		t, key := parseTodoTxtLine(line)
+		// Repeated lines are different tasks.
+		seen[key]++
+		if seen[key] > 1 {
+			key = fmt.Sprintf("%s#%d", key, seen[key])
+		}
+		sum := sha256.Sum256([]byte(key))
+		t.ID = hex.EncodeToString(sum[:16])
+		tasks = append(tasks, t)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	return tasks, nil
+}
+
+// parseTodoTxtLine reads one task and the text that identifies it.
+func parseTodoTxtLine(line string) (Task, string) {
+	var t Task
+	words := strings.Fields(line)
+	if len(words) > 0 && words[0] == "x" {
+		t.Completed = true
+		words = words[1:]
+		if len(words) > 0 {
+			if at, err := todoTxtDate(words[0]); err == nil {
+				t.CompletedAt = &at
+				words = words[1:]
+			}
+		}
+	} else if len(words) > 0 && len(words[0]) == 3 && words[0][0] == '(' && words[0][2] == ')' && words[0][1] >= 'A' && words[0][1] <= 'Z' {
+		t.Priority = todoTxtPriority(words[0][1])
+		words = words[1:]
+	}
+	// The creation date isn't kept.
+	if len(words) > 0 {
+		if _, err := todoTxtDate(words[0]); err == nil {
+			words = words[1:]
+		}
+	}
+
+	var name, key []string
+	for _, w := range words {
+		if k, v, ok := strings.Cut(w, ":"); ok && v != "" && !strings.HasPrefix(v, "//") {
+			switch k {
+			case "due":
+				if at, err := todoTxtDate(v); err == nil {
+					t.DueAt = &at
+					continue
+				}
+			case "pri":
+				// Completing a task moves its priority here.
+				if len(v) == 1 && v[0] >= 'A' && v[0] <= 'Z' {
+					t.Priority = todoTxtPriority(v[0])
+					continue
+				}
+			case "rec":
+				if rule, ok := todoTxtRecurrence(v); ok {
+					t.Recurrence = rule
+					continue
+				}
+			}
+		}
+		key = append(key, w)
+		switch {
+		case len(w) > 1 && w[0] == '+':
+			if t.Project == "" {
+				t.Project = w[1:]
+			} else {
+				t.Labels = append(t.Labels, w[1:])
+			}
+		case len(w) > 1 && w[0] == '@':
+			t.Labels = append(t.Labels, w[1:])
+		default:
+			name = append(name, w)
+		}
+	}
+	t.Name = strings.Join(name, " ")
+	return t, strings.Join(key, " ")
+}
+
+func todoTxtPriority(p byte) string {
+	if name, ok := todoTxtPriorities[p]; ok {
+		return name
+	}
+	return "low"
+}
+
+func todoTxtDate(s string) (time.Time, error) {
+	return time.Parse("2006-01-02", s)
+}
+
+// todoTxtRecurrence turns a rec: value, such as 2w or +1m, into an RRULE.
+func todoTxtRecurrence(v string) (string, bool) {
+	s := strings.TrimPrefix(v, "+")
+	if s == "" {
+		return "", false
+	}
+	freq, ok := todoTxtFrequencies[s[len(s)-1]]
+	interval := strings.TrimLeft(s[:len(s)-1], "0")
+	if !ok || strings.Trim(interval, "0123456789") != "" || interval == "" && len(s) > 1 {
+		return "", false
+	}
+	if interval == "" || interval == "1" {
+		return "FREQ=" + freq, true
+	}
+	return "FREQ=" + freq + ";INTERVAL=" + interval, true
+}
@@ -0,0 +1,164 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Trello reads the JSON export of a Trello board. The board becomes the
+// project and each card a task; archived cards and cards in archived lists
+// are skipped. A card is completed when its due date is marked complete or
+// its list is named like a done column; cards in a list named like a
+// doing column are in progress. Other list names become labels. Card
+// labels are kept by name, or by colour when unnamed, and checklists are
+// added to the description.
+type Trello struct{}
+
+func NewTrello() *Trello {
+	return &Trello{}
+}
+
+var _ Importer = (*Trello)(nil)
+
+type trelloBoard struct {
+	Name  string `json:"name"`
+	Lists []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Closed bool   `json:"closed"`
+	} `json:"lists"`
+	Cards []struct {
+		ID     string `json:"id"`
+		Name   string `json:"name"`
+		Desc   string `json:"desc"`
+		IDList string `json:"idList"`
+		Closed bool   `json:"closed"`
+		Labels []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"labels"`
+		Due              *time.Time `json:"due"`
+		DueComplete      bool       `json:"dueComplete"`
+		DateLastActivity *time.Time `json:"dateLastActivity"`
+	} `json:"cards"`
+	Checklists []trelloChecklist `json:"checklists"`
+}
+
+type trelloChecklist struct {
+	IDCard     string  `json:"idCard"`
+	Name       string  `json:"name"`
+	Pos        float64 `json:"pos"`
+	CheckItems []struct {
+		Name  string  `json:"name"`
+		State string  `json:"state"`
+		Pos   float64 `json:"pos"`
+	} `json:"checkItems"`
+}
+
+// trelloLists maps list names, lower-cased, to the state of their cards.
+var trelloLists = map[string]string{
+	"done":        "done",
+	"complete":    "done",
+	"completed":   "done",
+	"finished":    "done",
+	"doing":       "doing",
+	"in progress": "doing",
+	"in-progress": "doing",
+	"started":     "doing",
+	"to do":       "todo",
+	"todo":        "todo",
+	"to-do":       "todo",
+}
+
+func (Trello) Parse(r io.Reader) ([]Task, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	if board.Cards == nil || board.Lists == nil {
+		return nil, fmt.Errorf("%w: not a Trello board, it has no lists or cards", ErrInvalidFile)
+	}
+
+	type list struct {
+		name   string
+		closed bool
+	}
+	lists := make(map[string]list, len(board.Lists))
+	for _, l := range board.Lists {
+		lists[l.ID] = list{name: strings.TrimSpace(l.Name), closed: l.Closed}
+	}
+	checklists := map[string][]trelloChecklist{}
+	for _, c := range board.Checklists {
+		checklists[c.IDCard] = append(checklists[c.IDCard], c)
+	}
+
+	tasks := make([]Task, 0, len(board.Cards))
+	for _, card := range board.Cards {
+		l := lists[card.IDList]
+		if card.Closed || l.closed {
+			continue
+		}
+		t := Task{
+			ID:          card.ID,
+			Name:        card.Name,
+			Description: strings.TrimSpace(card.Desc),
+			Project:     strings.TrimSpace(board.Name),
+			DueAt:       card.Due,
+			Completed:   card.DueComplete,
+		}
+		switch trelloLists[strings.ToLower(l.name)] {
+		case "done":
+			t.Completed = true
+		case "doing":
+			t.InProgress = !t.Completed
+		case "todo":
+		default:
+			if l.name != "" {
+				t.Labels = append(t.Labels, l.name)
+			}
+		}
+		if t.Completed {
+			t.CompletedAt = card.DateLastActivity
+		}
+		for _, label := range card.Labels {
+			name := strings.TrimSpace(label.Name)
+			if name == "" {
+				name = label.Color
+			}
+			t.Labels = append(t.Labels, name)
+		}
+
+		cls := checklists[card.ID]
+		sort.SliceStable(cls, func(a, b int) bool { return cls[a].Pos < cls[b].Pos })
+		for _, c := range cls {
+			t.Description = appendChecklist(t.Description, c)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+// appendChecklist adds a checklist to a description as a Markdown task
+// list.
+func appendChecklist(description string, c trelloChecklist) string {
+	items := c.CheckItems
+	sort.SliceStable(items, func(a, b int) bool { return items[a].Pos < items[b].Pos })
+	var b strings.Builder
+	b.WriteString(description)
+	if description != "" {
+		b.WriteString("\n\n")
+	}
+	b.WriteString(strings.TrimSpace(c.Name) + ":")
+	for _, item := range items {
+		mark := " "
+		if item.State == "complete" {
+			mark = "x"
+		}
+		fmt.Fprintf(&b, "\n- [%s] %s", mark, strings.TrimSpace(item.Name))
+	}
+	return b.String()
+}
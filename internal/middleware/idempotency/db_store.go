@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DBStore keeps records in the database so replays work across instances
+// and restarts.
+type DBStore struct {
+	db  *gorm.DB
+	now func() time.Time
+}
+
+func NewDBStore(db *gorm.DB) *DBStore {
+	return &DBStore{db: db, now: time.Now}
+}
+
+// Compile-time check
+var _ Store = (*DBStore)(nil)
+
+// Reserve relies on the (user_id, key) primary key: the insert is a no-op
+// when another request got there first, so concurrent retries cannot both
+// run the handler.
+func (s *DBStore) Reserve(rec *Record) (*Record, error) {
+	var existing *Record
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// Clear an expired record so the key can be reused.
+		err := tx.Where("user_id = ? AND `key` = ? AND expires_at <= ?", rec.UserID, rec.Key, s.now()).
+			Delete(&Record{}).Error
+		if err != nil {
+			return err
+		}
+
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 1 {
+			return nil
+		}
+
+		var found Record
+		if err := tx.Where("user_id = ? AND `key` = ?", rec.UserID, rec.Key).First(&found).Error; err != nil {
+			return err
+		}
+		existing = &found
+		return nil
+	})
+	return existing, err
+}
+
+func (s *DBStore) Complete(userID int, key string, statusCode int, headers string, body []byte, expiresAt time.Time) error {
+	res := s.db.Model(&Record{}).
+		Where("user_id = ? AND `key` = ?", userID, key).
+		Updates(map[string]any{
+			"completed":   true,
+			"status_code": statusCode,
+			"headers":     headers,
+			"body":        body,
+			"expires_at":  expiresAt,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *DBStore) Release(userID int, key string) error {
+	return s.db.Where("user_id = ? AND `key` = ?", userID, key).Delete(&Record{}).Error
+}
+
+func (s *DBStore) DeleteExpired(now time.Time) error {
+	return s.db.Where("expires_at <= ?", now).Delete(&Record{}).Error
+}
@@ -0,0 +1,246 @@
+package idempotency
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"taskflow/internal/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// HeaderKey is the request header clients set to make a request idempotent.
+	HeaderKey = "Idempotency-Key"
+	// HeaderReplayed is set on responses served from a stored record.
+	HeaderReplayed = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	DefaultTTL   = 24 * time.Hour
+
+	// reservationTTL is how long a key stays reserved while its request
+	// runs. Should the instance die mid-request, the key can be retried
+	// once it has passed.
+	reservationTTL = 5 * time.Minute
+
+	// maxBody bounds the request bodies read into memory to fingerprint
+	// them: the largest upload an endpoint accepts, 10 MiB, with room for
+	// form overhead.
+	maxBody = 11 << 20
+)
+
+// replayedHeaders are the response headers stored with a record.
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// Idempotency replays the stored response when a mutating request is retried
+// with the same Idempotency-Key. Records are kept per user, so it must run
+// after the auth middleware.
+type Idempotency struct {
+	store    Store
+	ttl      time.Duration
+	now      func() time.Time
+	onReplay map[string]ReplayFunc
+}
+
+// ReplayFunc rewrites a stored successful response body before it is
+// replayed.
+type ReplayFunc func(c *gin.Context, body []byte) ([]byte, error)
+
+// New creates an Idempotency middleware keeping records for ttl
+// (DefaultTTL when ttl is not positive).
+func New(store Store, ttl time.Duration) *Idempotency {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Idempotency{store: store, ttl: ttl, now: time.Now, onReplay: map[string]ReplayFunc{}}
+}
+
+// OnReplay makes replays of the route, given as its full path pattern such
+// as /api/tasks/:id/attachments, pass the stored body through fn. Routes
+// whose responses hold values that expire, such as signed download URLs,
+// use it to hand out fresh ones. Register routes before serving requests.
+func (i *Idempotency) OnReplay(method, route string, fn ReplayFunc) {
+	i.onReplay[method+" "+route] = fn
+}
+
+// Middleware returns a Gin middleware implementing idempotent retries.
+// Requests without the header, safe methods and anonymous requests pass
+// through untouched.
+func (i *Idempotency) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(HeaderKey)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{Message: "Idempotency-Key must be at most 255 characters"})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBody))
+		if err != nil {
+			var maxErr *http.MaxBytesError
+			if errors.As(err, &maxErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, common.ErrorResponse{Message: "request body is too large"})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, common.ErrorResponse{Message: "could not read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		rec := &Record{
+			UserID:      userID.(int),
+			Key:         key,
+			Fingerprint: fingerprint(c.Request.Method, c.Request.URL.Path, c.Request.URL.RawQuery, body),
+			ExpiresAt:   i.now().Add(min(reservationTTL, i.ttl)),
+		}
+		existing, err := i.store.Reserve(rec)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, common.ErrorResponse{Message: err.Error()})
+			return
+		}
+
+		if existing != nil {
+			i.replay(c, existing, rec.Fingerprint)
+			return
+		}
+
+		w := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer func() {
+			// A panicking handler must not leave the key stuck in progress.
+			if r := recover(); r != nil {
+				_ = i.store.Release(rec.UserID, key)
+				panic(r)
+			}
+		}()
+		c.Next()
+
+		// Server errors are not recorded so the client can retry them.
+		if w.Status() >= http.StatusInternalServerError {
+			if err := i.store.Release(rec.UserID, key); err != nil {
+				log.Printf("idempotency: release %q: %v", key, err)
+			}
+			return
+		}
+		err = i.store.Complete(rec.UserID, key, w.Status(), encodeHeaders(w.Header()), w.body.Bytes(), i.now().Add(i.ttl))
+		if err != nil {
+			// Don't leave the key reserved: retries would get 409 until
+			// the reservation ran out.
+			log.Printf("idempotency: complete %q: %v", key, err)
+			if err := i.store.Release(rec.UserID, key); err != nil {
+				log.Printf("idempotency: release %q: %v", key, err)
+			}
+		}
+	}
+}
+
+// StartCleanupRoutine starts a goroutine that periodically deletes expired records.
+func (i *Idempotency) StartCleanupRoutine(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			if err := i.store.DeleteExpired(i.now()); err != nil {
+				log.Printf("idempotency: cleanup: %v", err)
+			}
+		}
+	}()
+}
+
+func (i *Idempotency) replay(c *gin.Context, rec *Record, fp string) {
+	switch {
+	case rec.Fingerprint != fp:
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, common.ErrorResponse{
+			Message: "Idempotency-Key was already used with a different request",
+		})
+	case !rec.Completed:
+		c.AbortWithStatusJSON(http.StatusConflict, common.ErrorResponse{
+			Message: "a request with this Idempotency-Key is still being processed",
+		})
+	default:
+		var headers map[string]string
+		_ = json.Unmarshal([]byte(rec.Headers), &headers)
+		for name, value := range headers {
+			c.Header(name, value)
+		}
+		body := rec.Body
+		if fn := i.onReplay[c.Request.Method+" "+c.FullPath()]; fn != nil && rec.StatusCode < http.StatusMultipleChoices {
+			refreshed, err := fn(c, body)
+			if err != nil {
+				// The stored response is still the right answer, if stale.
+				log.Printf("idempotency: refresh %q: %v", rec.Key, err)
+			} else {
+				body = refreshed
+			}
+		}
+		c.Header(HeaderReplayed, "true")
+		c.Status(rec.StatusCode)
+		_, _ = c.Writer.Write(body)
+		c.Abort()
+	}
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// fingerprint identifies a request by method, path, query and body, so
+// reusing a key for a different endpoint or options is rejected as well.
+func fingerprint(method, path, query string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(path))
+	h.Write([]byte{0})
+	h.Write([]byte(query))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func encodeHeaders(h http.Header) string {
+	headers := make(map[string]string)
+	for _, name := range replayedHeaders {
+		if v := h.Get(name); v != "" {
+			headers[name] = v
+		}
+	}
+	data, _ := json.Marshal(headers)
+	return string(data)
+}
+
+// recordingWriter copies the response body so it can be stored.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,287 @@
+package idempotency
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&Record{}))
+	return db
+}
+
+func stores(t *testing.T) map[string]Store {
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"db":     NewDBStore(setupTestDB(t)),
+	}
+}
+
+// newRouter counts handler calls and answers 201 with a Location header, or
+// with the given status when the request carries ?status=.
+func newRouter(store Store, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if c.GetHeader("X-Anonymous") == "" {
+			c.Set("userID", 1)
+		}
+		c.Next()
+	})
+	r.Use(New(store, time.Hour).Middleware())
+	r.POST("/tasks", func(c *gin.Context) {
+		*calls++
+		if c.Query("status") == "500" {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "boom"})
+			return
+		}
+		c.Header("Location", "/tasks/42")
+		c.JSON(http.StatusCreated, gin.H{"id": 42, "call": *calls})
+	})
+	return r
+}
+
+func post(r *gin.Engine, path, key, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(HeaderKey, key)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestMiddleware_ReplaysStoredResponse(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			r := newRouter(store, &calls)
+
+			first := post(r, "/tasks", "abc", `{"task":"Buy milk"}`)
+			second := post(r, "/tasks", "abc", `{"task":"Buy milk"}`)
+
+			assert.Equal(t, 1, calls)
+			assert.Equal(t, http.StatusCreated, second.Code)
+			assert.JSONEq(t, first.Body.String(), second.Body.String())
+			assert.Equal(t, "/tasks/42", second.Header().Get("Location"))
+			assert.Equal(t, "true", second.Header().Get(HeaderReplayed))
+			assert.Empty(t, first.Header().Get(HeaderReplayed))
+		})
+	}
+}
+
+func TestMiddleware_OnReplayRefreshesBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	idem := New(NewMemoryStore(), time.Hour)
+	idem.OnReplay(http.MethodPost, "/tasks", func(c *gin.Context, body []byte) ([]byte, error) {
+		if c.Query("fail") != "" {
+			return nil, errors.New("bad body")
+		}
+		return []byte(`{"url":"fresh"}`), nil
+	})
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("userID", 1) })
+	r.Use(idem.Middleware())
+	r.POST("/tasks", func(c *gin.Context) {
+		if c.Query("status") == "400" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bad"})
+			return
+		}
+		c.JSON(http.StatusCreated, gin.H{"url": "stale"})
+	})
+
+	first := post(r, "/tasks", "abc", `{}`)
+	assert.JSONEq(t, `{"url":"stale"}`, first.Body.String())
+	second := post(r, "/tasks", "abc", `{}`)
+	assert.JSONEq(t, `{"url":"fresh"}`, second.Body.String())
+	assert.Equal(t, "true", second.Header().Get(HeaderReplayed))
+
+	post(r, "/tasks?fail=1", "def", `{}`)
+	failed := post(r, "/tasks?fail=1", "def", `{}`)
+	assert.JSONEq(t, `{"url":"stale"}`, failed.Body.String(), "a failed refresh replays the stored body")
+
+	post(r, "/tasks?status=400", "ghi", `{}`)
+	rejected := post(r, "/tasks?status=400", "ghi", `{}`)
+	assert.JSONEq(t, `{"error":"bad"}`, rejected.Body.String(), "only successful responses are refreshed")
+}
+
+func TestMiddleware_RejectsDifferentBody(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			r := newRouter(store, &calls)
+
+			post(r, "/tasks", "abc", `{"task":"Buy milk"}`)
+			w := post(r, "/tasks", "abc", `{"task":"Buy eggs"}`)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func TestMiddleware_RejectsDifferentQuery(t *testing.T) {
+	calls := 0
+	r := newRouter(NewMemoryStore(), &calls)
+
+	post(r, "/tasks?dry_run=true", "abc", `{}`)
+	w := post(r, "/tasks", "abc", `{}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestMiddleware_BodyTooLarge(t *testing.T) {
+	calls := 0
+	r := newRouter(NewMemoryStore(), &calls)
+
+	w := post(r, "/tasks", "abc", strings.Repeat("x", maxBody+1))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestMiddleware_InProgress(t *testing.T) {
+	store := NewMemoryStore()
+	calls := 0
+	r := newRouter(store, &calls)
+
+	_, err := store.Reserve(&Record{
+		UserID:      1,
+		Key:         "abc",
+		Fingerprint: fingerprint(http.MethodPost, "/tasks", "", []byte(`{}`)),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	w := post(r, "/tasks", "abc", `{}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestMiddleware_ServerErrorsAreNotStored(t *testing.T) {
+	for name, store := range stores(t) {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			r := newRouter(store, &calls)
+
+			post(r, "/tasks?status=500", "abc", `{}`)
+			w := post(r, "/tasks?status=500", "abc", `{}`)
+
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+			assert.Equal(t, 2, calls)
+		})
+	}
+}
+
+func TestMiddleware_PassThrough(t *testing.T) {
+	calls := 0
+	r := newRouter(NewMemoryStore(), &calls)
+
+	post(r, "/tasks", "", `{}`)
+	post(r, "/tasks", "", `{}`)
+	assert.Equal(t, 2, calls, "requests without a key are not deduplicated")
+
+	post(r, "/tasks", "anon", `{}`, "X-Anonymous", "1")
+	post(r, "/tasks", "anon", `{}`, "X-Anonymous", "1")
+	assert.Equal(t, 4, calls, "anonymous requests are not deduplicated")
+}
+
+func TestMiddleware_KeysAreScopedPerUser(t *testing.T) {
+	store := NewMemoryStore()
+	_, err := store.Reserve(&Record{UserID: 2, Key: "abc", Fingerprint: "other", ExpiresAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	calls := 0
+	r := newRouter(store, &calls)
+	w := post(r, "/tasks", "abc", `{}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDBStore_ExpiredKeyCanBeReused(t *testing.T) {
+	store := NewDBStore(setupTestDB(t))
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+
+	rec := Record{UserID: 1, Key: "abc", Fingerprint: "one", ExpiresAt: now.Add(time.Minute)}
+	existing, err := store.Reserve(&rec)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+	require.NoError(t, store.Complete(1, "abc", http.StatusCreated, `{}`, []byte(`{"id":1}`), now.Add(time.Minute)))
+
+	existing, err = store.Reserve(&Record{UserID: 1, Key: "abc", Fingerprint: "two", ExpiresAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, "one", existing.Fingerprint)
+	assert.True(t, existing.Completed)
+	assert.Equal(t, []byte(`{"id":1}`), existing.Body)
+
+	now = now.Add(2 * time.Minute)
+	existing, err = store.Reserve(&Record{UserID: 1, Key: "abc", Fingerprint: "two", ExpiresAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+
+	require.NoError(t, store.DeleteExpired(now.Add(2*time.Hour)))
+	assert.ErrorIs(t, store.Complete(1, "abc", http.StatusOK, `{}`, nil, now), ErrNotFound)
+}
+
+// failingStore loses every completed response.
+type failingStore struct{ *MemoryStore }
+
+func (s failingStore) Complete(int, string, int, string, []byte, time.Time) error {
+	return errors.New("db down")
+}
+
+func TestMiddleware_ReleasesKeyWhenCompleteFails(t *testing.T) {
+	calls := 0
+	r := newRouter(failingStore{NewMemoryStore()}, &calls)
+
+	post(r, "/tasks", "abc", `{}`)
+	w := post(r, "/tasks", "abc", `{}`)
+
+	assert.Equal(t, http.StatusCreated, w.Code, "the retry runs instead of waiting for the reservation")
+	assert.Equal(t, 2, calls)
+}
+
+func TestMiddleware_ReservationExpiresBeforeRecord(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	idem := New(store, time.Hour)
+	idem.now = store.now
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) { c.Set("userID", 1) })
+	r.Use(idem.Middleware())
+	var reserved time.Time
+	r.POST("/tasks", func(c *gin.Context) {
+		reserved = store.records[memoryKey{1, "abc"}].ExpiresAt
+		c.Status(http.StatusCreated)
+	})
+	post(r, "/tasks", "abc", `{}`)
+
+	assert.Equal(t, now.Add(reservationTTL), reserved, "a crashed request frees the key soon")
+	assert.Equal(t, now.Add(time.Hour), store.records[memoryKey{1, "abc"}].ExpiresAt)
+}
@@ -0,0 +1,79 @@
+package idempotency
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryKey struct {
+	userID int
+	key    string
+}
+
+// MemoryStore keeps records in process memory. It suits a single instance
+// and tests; records are lost on restart.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[memoryKey]Record
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[memoryKey]Record), now: time.Now}
+}
+
+// Compile-time check
+var _ Store = (*MemoryStore)(nil)
+
+func (s *MemoryStore) Reserve(rec *Record) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := memoryKey{rec.UserID, rec.Key}
+	if existing, ok := s.records[k]; ok && existing.ExpiresAt.After(s.now()) {
+		return &existing, nil
+	}
+
+	stored := *rec
+	stored.CreatedAt = s.now()
+	s.records[k] = stored
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(userID int, key string, statusCode int, headers string, body []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := memoryKey{userID, key}
+	rec, ok := s.records[k]
+	if !ok {
+		return ErrNotFound
+	}
+	rec.Completed = true
+	rec.StatusCode = statusCode
+	rec.Headers = headers
+	rec.Body = append([]byte(nil), body...)
+	rec.ExpiresAt = expiresAt
+	s.records[k] = rec
+	return nil
+}
+
+func (s *MemoryStore) Release(userID int, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, memoryKey{userID, key})
+	return nil
+}
+
+func (s *MemoryStore) DeleteExpired(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k, rec := range s.records {
+		if !rec.ExpiresAt.After(now) {
+			delete(s.records, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package idempotency
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Store.Complete and Store.Release when there is
+// no reservation for the key.
+var ErrNotFound = errors.New("idempotency record not found")
+
+// Record is a stored idempotent request and, once the handler has run, its
+// response.
+type Record struct {
+	UserID      int    `gorm:"primaryKey;autoIncrement:false"`
+	Key         string `gorm:"primaryKey;size:255"`
+	Fingerprint string `gorm:"not null;size:64"`
+	// Completed is false while the original request is still being handled.
+	Completed  bool      `gorm:"not null;default:false"`
+	StatusCode int       `gorm:"not null;default:0"`
+	Headers    string    `gorm:"type:text"`     // JSON object of replayed headers
+	Body       []byte    `gorm:"type:longblob"` // blob holds only 64 KiB on MySQL
+	ExpiresAt  time.Time `gorm:"not null;index"`
+	CreatedAt  time.Time
+}
+
+func (Record) TableName() string {
+	return "idempotency_records"
+}
+
+// Store persists idempotency records per user.
+type Store interface {
+	// Reserve stores rec as an in-progress request unless an unexpired record
+	// already exists for the same user and key, in which case it returns
+	// that record and leaves it untouched.
+	Reserve(rec *Record) (existing *Record, err error)
+
+	// Complete saves the response for a reserved key and keeps it until
+	// expiresAt.
+	Complete(userID int, key string, statusCode int, headers string, body []byte, expiresAt time.Time) error
+
+	// Release drops a reservation so that the request can be retried.
+	Release(userID int, key string) error
+
+	// DeleteExpired removes records that expired before now.
+	DeleteExpired(now time.Time) error
+}
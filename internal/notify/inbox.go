@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"context"
+
+	"taskflow/internal/domain/notification"
+	"taskflow/internal/repository/gorm/gorm_notification"
+)
+
+// Inbox stores notifications for the in-app inbox.
+type Inbox struct {
+	repo gorm_notification.NotificationRepositoryInterface
+}
+
+func NewInbox(repo gorm_notification.NotificationRepositoryInterface) *Inbox {
+	return &Inbox{repo: repo}
+}
+
+// Compile-time check
+var _ Notifier = (*Inbox)(nil)
+
+func (i *Inbox) Notify(ctx context.Context, msg Message) error {
+	n := notification.Notification{
+		UserID: msg.UserID,
+		Type:   msg.Type,
+		Title:  msg.Title,
+		Body:   msg.Body,
+		TaskID: msg.TaskID,
+	}
+	if msg.Key != "" {
+		n.Key = &msg.Key
+	}
+	return i.repo.Create(&n)
+}
@@ -0,0 +1,29 @@
+package notify
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type NotifierMock struct {
+	mock.Mock
+}
+
+var _ Notifier = (*NotifierMock)(nil)
+
+func (m *NotifierMock) Notify(ctx context.Context, msg Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+
+type PublisherMock struct {
+	mock.Mock
+}
+
+var _ Publisher = (*PublisherMock)(nil)
+
+func (m *PublisherMock) Publish(ctx context.Context, msg Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
@@ -0,0 +1,58 @@
+// Package notify delivers messages to users through pluggable channels:
+// email, a webhook and the in-app inbox.
+package notify
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"taskflow/pkg"
+)
+
+// Message is one notification for one user. Key, when set, identifies the
+// event so that retried deliveries are not shown twice.
+type Message struct {
+	UserID int        `json:"user_id"`
+	Email  string     `json:"email,omitempty"`
+	Type   string     `json:"type"`
+	Title  string     `json:"title"`
+	Body   string     `json:"body"`
+	TaskID *int       `json:"task_id,omitempty"`
+	DueAt  *time.Time `json:"due_at,omitempty"`
+	Key    string     `json:"key,omitempty"`
+}
+
+// Notifier sends a message through one channel.
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Publisher delivers a message through the channels its recipient chose
+// for the message type. Producers publish rather than picking channels.
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+type Config struct {
+	SMTP SMTPConfig
+	// WebhookURL receives every notification as JSON; empty disables it.
+	WebhookURL    string
+	WebhookSecret string
+}
+
+// LoadConfigFromEnv reads the channel settings. Email and the webhook are
+// optional and stay off unless SMTP_HOST and NOTIFY_WEBHOOK_URL are set.
+func LoadConfigFromEnv() Config {
+	return Config{
+		SMTP: SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     pkg.GetEnv("SMTP_PORT", "587"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     pkg.GetEnv("SMTP_FROM", "TaskFlow <no-reply@taskflow.local>"),
+		},
+		WebhookURL:    os.Getenv("NOTIFY_WEBHOOK_URL"),
+		WebhookSecret: os.Getenv("NOTIFY_WEBHOOK_SECRET"),
+	}
+}
@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/notification"
+	"taskflow/internal/repository/gorm/gorm_notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func reminder() Message {
+	taskID := 4
+	due := time.Date(2025, 9, 8, 9, 0, 0, 0, time.UTC)
+	return Message{
+		UserID: 1,
+		Email:  "sam@example.com",
+		Type:   notification.TypeReminder,
+		Title:  "Reminder: Pay rent",
+		Body:   "\"Pay rent\" is due Mon 8 Sep 09:00 UTC.",
+		TaskID: &taskID,
+		DueAt:  &due,
+	}
+}
+
+func TestMailer_Notify(t *testing.T) {
+	m, err := NewMailer(SMTPConfig{Host: "smtp.example.com", Port: "587", From: "TaskFlow <no-reply@example.com>"})
+	require.NoError(t, err)
+	m.now = func() time.Time { return time.Date(2025, 9, 7, 9, 0, 0, 0, time.UTC) }
+
+	var to string
+	var sent []byte
+	m.send = func(ctx context.Context, rcpt string, msg []byte) error {
+		to, sent = rcpt, msg
+		return nil
+	}
+
+	require.NoError(t, m.Notify(context.Background(), reminder()))
+	assert.Equal(t, "sam@example.com", to)
+	assert.Contains(t, string(sent), "From: \"TaskFlow\" <no-reply@example.com>\r\n")
+	assert.Contains(t, string(sent), "To: <sam@example.com>\r\n")
+	assert.Contains(t, string(sent), "Subject: Reminder: Pay rent\r\n")
+	assert.True(t, strings.HasSuffix(string(sent), "\r\n\r\n\"Pay rent\" is due Mon 8 Sep 09:00 UTC.\r\n"))
+
+	// Users without an address are skipped.
+	sent = nil
+	msg := reminder()
+	msg.Email = ""
+	require.NoError(t, m.Notify(context.Background(), msg))
+	assert.Nil(t, sent)
+
+	_, err = NewMailer(SMTPConfig{From: "not an address"})
+	assert.Error(t, err)
+}
+
+func TestWebhook_Notify(t *testing.T) {
+	var got webhookPayload
+	var signature string
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		assert.Equal(t, Sign("s3cret", body), signature)
+		assert.NoError(t, json.Unmarshal(body, &got))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	w := NewWebhook(srv.URL, "s3cret")
+	require.NoError(t, w.Notify(context.Background(), reminder()))
+	assert.Equal(t, "reminder", got.Type)
+	assert.Equal(t, 4, *got.TaskID)
+	assert.True(t, strings.HasPrefix(signature, "sha256="))
+
+	status = http.StatusBadGateway
+	assert.EqualError(t, w.Notify(context.Background(), reminder()), "webhook answered 502 Bad Gateway")
+}
+
+func TestInbox_Notify(t *testing.T) {
+	repo := new(gorm_notification.NotificationRepoMock)
+	repo.On("Create", mock.MatchedBy(func(n *notification.Notification) bool {
+		return n.UserID == 1 && n.Type == "reminder" && n.Title == "Reminder: Pay rent" && *n.TaskID == 4 && n.Key == nil
+	})).Return(nil).Once()
+	repo.On("Create", mock.MatchedBy(func(n *notification.Notification) bool {
+		return n.Key != nil && *n.Key == "reminder:4"
+	})).Return(nil).Once()
+
+	require.NoError(t, NewInbox(repo).Notify(context.Background(), reminder()))
+	msg := reminder()
+	msg.Key = "reminder:4"
+	require.NoError(t, NewInbox(repo).Notify(context.Background(), msg))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,84 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, keyed
+// with the webhook secret, as "sha256=<hex>".
+const SignatureHeader = "X-TaskFlow-Signature"
+
+// Webhook posts notifications as JSON to a fixed URL.
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewWebhook(url, secret string) *Webhook {
+	return &Webhook{url: url, secret: secret, client: &http.Client{Timeout: 10 * time.Second}, now: time.Now}
+}
+
+// Compile-time check
+var _ Notifier = (*Webhook)(nil)
+
+type webhookPayload struct {
+	Type   string     `json:"type"`
+	UserID int        `json:"user_id"`
+	Title  string     `json:"title"`
+	Body   string     `json:"body"`
+	TaskID *int       `json:"task_id,omitempty"`
+	DueAt  *time.Time `json:"due_at,omitempty"`
+	SentAt time.Time  `json:"sent_at"`
+}
+
+// Notify posts msg and fails unless the receiver answers with a 2xx status.
+func (w *Webhook) Notify(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(webhookPayload{
+		Type:   msg.Type,
+		UserID: msg.UserID,
+		Title:  msg.Title,
+		Body:   msg.Body,
+		TaskID: msg.TaskID,
+		DueAt:  msg.DueAt,
+		SentAt: w.now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if w.secret != "" {
+		req.Header.Set(SignatureHeader, Sign(w.secret, body))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,58 @@
+package gorm_accesstoken
+
+import (
+	"time"
+
+	"taskflow/internal/domain/accesstoken"
+
+	"gorm.io/gorm"
+)
+
+type AccessTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewAccessTokenRepository(db *gorm.DB) *AccessTokenRepository {
+	return &AccessTokenRepository{db: db}
+}
+
+// Compile-time check
+var _ AccessTokenRepositoryInterface = (*AccessTokenRepository)(nil)
+
+func (r *AccessTokenRepository) Create(t *accesstoken.Token) error {
+	return r.db.Create(t).Error
+}
+
+func (r *AccessTokenRepository) List(userID int) ([]accesstoken.Token, error) {
+	var tokens []accesstoken.Token
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *AccessTokenRepository) GetByHash(hash string) (*accesstoken.Token, error) {
+	var t accesstoken.Token
+	if err := r.db.Where("hash = ?", hash).First(&t).Error; err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *AccessTokenRepository) Delete(userID int, id int) error {
+	res := r.db.Where("user_id = ?", userID).Delete(&accesstoken.Token{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *AccessTokenRepository) DeleteUserTokens(userID int) error {
+	return r.db.Where("user_id = ?", userID).Delete(&accesstoken.Token{}).Error
+}
+
+// Touch records when a token was last used.
+func (r *AccessTokenRepository) Touch(id int, at time.Time) error {
+	return r.db.Model(&accesstoken.Token{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
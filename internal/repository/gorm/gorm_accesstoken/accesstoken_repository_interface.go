@@ -0,0 +1,16 @@
+package gorm_accesstoken
+
+import (
+	"time"
+
+	"taskflow/internal/domain/accesstoken"
+)
+
+type AccessTokenRepositoryInterface interface {
+	Create(t *accesstoken.Token) error
+	List(userID int) ([]accesstoken.Token, error)
+	GetByHash(hash string) (*accesstoken.Token, error)
+	Delete(userID int, id int) error
+	DeleteUserTokens(userID int) error
+	Touch(id int, at time.Time) error
+}
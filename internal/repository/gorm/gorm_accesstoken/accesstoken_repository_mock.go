@@ -0,0 +1,45 @@
+package gorm_accesstoken
+
+import (
+	"time"
+
+	"taskflow/internal/domain/accesstoken"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AccessTokenRepoMock struct {
+	mock.Mock
+}
+
+var _ AccessTokenRepositoryInterface = (*AccessTokenRepoMock)(nil)
+
+func (m *AccessTokenRepoMock) Create(t *accesstoken.Token) error {
+	args := m.Called(t)
+	return args.Error(0)
+}
+
+func (m *AccessTokenRepoMock) List(userID int) ([]accesstoken.Token, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]accesstoken.Token), args.Error(1)
+}
+
+func (m *AccessTokenRepoMock) GetByHash(hash string) (*accesstoken.Token, error) {
+	args := m.Called(hash)
+	return args.Get(0).(*accesstoken.Token), args.Error(1)
+}
+
+func (m *AccessTokenRepoMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *AccessTokenRepoMock) DeleteUserTokens(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *AccessTokenRepoMock) Touch(id int, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
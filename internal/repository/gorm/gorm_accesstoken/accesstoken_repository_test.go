@@ -0,0 +1,66 @@
+package gorm_accesstoken
+
+import (
+	"taskflow/internal/domain/accesstoken"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&accesstoken.Token{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestAccessTokenRepository(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewAccessTokenRepository(db)
+
+	a := accesstoken.Token{UserID: 1, Name: "Phone", Prefix: "tfp_a", Hash: "hash-a"}
+	require.NoError(t, r.Create(&a))
+	b := accesstoken.Token{UserID: 1, Name: "Laptop", Prefix: "tfp_b", Hash: "hash-b"}
+	require.NoError(t, r.Create(&b))
+	require.NoError(t, r.Create(&accesstoken.Token{UserID: 2, Name: "Other", Prefix: "tfp_c", Hash: "hash-c"}))
+	assert.Error(t, r.Create(&accesstoken.Token{UserID: 2, Name: "Dup", Prefix: "tfp_a", Hash: "hash-a"}), "hashes are unique")
+
+	got, err := r.GetByHash("hash-b")
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, got.ID)
+	assert.Nil(t, got.LastUsedAt)
+	_, err = r.GetByHash("nope")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	at := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, r.Touch(b.ID, at))
+	got, err = r.GetByHash("hash-b")
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	assert.True(t, at.Equal(*got.LastUsedAt))
+
+	assert.ErrorIs(t, r.Delete(2, a.ID), gorm.ErrRecordNotFound, "only the owner can delete")
+	require.NoError(t, r.Delete(1, a.ID))
+	list, err := r.List(1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, b.ID, list[0].ID)
+
+	require.NoError(t, r.DeleteUserTokens(1))
+	list, err = r.List(1)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	list, err = r.List(2)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
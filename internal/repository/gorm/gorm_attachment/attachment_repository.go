@@ -0,0 +1,47 @@
+package gorm_attachment
+
+import (
+	"taskflow/internal/domain/attachment"
+
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Compile-time check
+var _ AttachmentRepositoryInterface = (*AttachmentRepository)(nil)
+
+func (r *AttachmentRepository) Create(a *attachment.Attachment) error {
+	return r.db.Create(a).Error
+}
+
+func (r *AttachmentRepository) GetByID(id int) (*attachment.Attachment, error) {
+	var a attachment.Attachment
+	if err := r.db.First(&a, id).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *AttachmentRepository) ListByTask(taskID int) ([]attachment.Attachment, error) {
+	var attachments []attachment.Attachment
+	err := r.db.Where("task_id = ?", taskID).Order("id ASC").Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) Delete(id int) error {
+	return r.db.Delete(&attachment.Attachment{}, id).Error
+}
+
+func (r *AttachmentRepository) DeleteByTask(taskID int) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&attachment.Attachment{}).Error
+}
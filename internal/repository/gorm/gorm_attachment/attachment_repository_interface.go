@@ -0,0 +1,11 @@
+package gorm_attachment
+
+import "taskflow/internal/domain/attachment"
+
+type AttachmentRepositoryInterface interface {
+	Create(attachment *attachment.Attachment) error
+	GetByID(id int) (*attachment.Attachment, error)
+	ListByTask(taskID int) ([]attachment.Attachment, error)
+	Delete(id int) error
+	DeleteByTask(taskID int) error
+}
@@ -0,0 +1,41 @@
+package gorm_attachment
+
+import (
+	"taskflow/internal/domain/attachment"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AttachmentRepoMock struct {
+	mock.Mock
+}
+
+var _ AttachmentRepositoryInterface = (*AttachmentRepoMock)(nil)
+
+func (m *AttachmentRepoMock) Create(a *attachment.Attachment) error {
+	args := m.Called(a)
+	return args.Error(0)
+}
+
+func (m *AttachmentRepoMock) GetByID(id int) (*attachment.Attachment, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*attachment.Attachment), args.Error(1)
+}
+
+func (m *AttachmentRepoMock) ListByTask(taskID int) ([]attachment.Attachment, error) {
+	args := m.Called(taskID)
+	return args.Get(0).([]attachment.Attachment), args.Error(1)
+}
+
+func (m *AttachmentRepoMock) Delete(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *AttachmentRepoMock) DeleteByTask(taskID int) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
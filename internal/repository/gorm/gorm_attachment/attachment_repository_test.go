@@ -0,0 +1,79 @@
+package gorm_attachment
+
+import (
+	"errors"
+	"taskflow/internal/domain/attachment"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&attachment.Attachment{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func newAttachment(taskID int, key string) attachment.Attachment {
+	return attachment.Attachment{
+		TaskID:      taskID,
+		UserID:      1,
+		FileName:    "spec.pdf",
+		ContentType: "application/pdf",
+		Size:        10,
+		StorageKey:  key,
+	}
+}
+
+func TestAttachmentRepository_CreateAndGet(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewAttachmentRepository(db)
+
+	a := newAttachment(1, "tasks/1/a")
+	require.NoError(t, r.Create(&a))
+	assert.NotZero(t, a.ID)
+
+	got, err := r.GetByID(a.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "tasks/1/a", got.StorageKey)
+
+	_, err = r.GetByID(999)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestAttachmentRepository_ListAndDeleteByTask(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewAttachmentRepository(db)
+
+	for _, a := range []attachment.Attachment{
+		newAttachment(1, "tasks/1/a"),
+		newAttachment(1, "tasks/1/b"),
+		newAttachment(2, "tasks/2/a"),
+	} {
+		require.NoError(t, r.Create(&a))
+	}
+
+	got, err := r.ListByTask(1)
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+
+	require.NoError(t, r.DeleteByTask(1))
+
+	got, err = r.ListByTask(1)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	got, err = r.ListByTask(2)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+}
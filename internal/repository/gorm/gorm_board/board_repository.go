@@ -0,0 +1,67 @@
+package gorm_board
+
+import (
+	"errors"
+
+	"taskflow/internal/domain/board"
+
+	"gorm.io/gorm"
+)
+
+type BoardRepository struct {
+	db *gorm.DB
+}
+
+func NewBoardRepository(db *gorm.DB) *BoardRepository {
+	return &BoardRepository{db: db}
+}
+
+// Compile-time check
+var _ BoardRepositoryInterface = (*BoardRepository)(nil)
+
+// ListColumns returns the stored column settings of a board.
+func (r *BoardRepository) ListColumns(userID int, projectID *int) ([]board.Column, error) {
+	var columns []board.Column
+	if err := r.db.Scopes(onBoard(userID, projectID)).Find(&columns).Error; err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// WIPLimit returns the work-in-progress limit of a column, or 0 when it has none.
+func (r *BoardRepository) WIPLimit(userID int, projectID *int, status string) (int, error) {
+	var c board.Column
+	err := r.db.Scopes(onBoard(userID, projectID)).Where("status = ?", status).First(&c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return c.WIPLimit, nil
+}
+
+// SetWIPLimit stores a column's work-in-progress limit; 0 removes the limit.
+func (r *BoardRepository) SetWIPLimit(userID int, projectID *int, status string, limit int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var c board.Column
+		err := tx.Scopes(onBoard(userID, projectID)).Where("status = ?", status).First(&c).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return tx.Create(&board.Column{UserID: userID, ProjectID: projectID, Status: status, WIPLimit: limit}).Error
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&c).Update("wip_limit", limit).Error
+	})
+}
+
+func onBoard(userID int, projectID *int) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ?", userID)
+		if projectID == nil {
+			return db.Where("project_id IS NULL")
+		}
+		return db.Where("project_id = ?", *projectID)
+	}
+}
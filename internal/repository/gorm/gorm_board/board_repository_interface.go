@@ -0,0 +1,9 @@
+package gorm_board
+
+import "taskflow/internal/domain/board"
+
+type BoardRepositoryInterface interface {
+	ListColumns(userID int, projectID *int) ([]board.Column, error)
+	WIPLimit(userID int, projectID *int, status string) (int, error)
+	SetWIPLimit(userID int, projectID *int, status string, limit int) error
+}
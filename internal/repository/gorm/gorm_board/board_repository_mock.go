@@ -0,0 +1,28 @@
+package gorm_board
+
+import (
+	"taskflow/internal/domain/board"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type BoardRepoMock struct {
+	mock.Mock
+}
+
+var _ BoardRepositoryInterface = (*BoardRepoMock)(nil)
+
+func (m *BoardRepoMock) ListColumns(userID int, projectID *int) ([]board.Column, error) {
+	args := m.Called(userID, projectID)
+	return args.Get(0).([]board.Column), args.Error(1)
+}
+
+func (m *BoardRepoMock) WIPLimit(userID int, projectID *int, status string) (int, error) {
+	args := m.Called(userID, projectID, status)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *BoardRepoMock) SetWIPLimit(userID int, projectID *int, status string, limit int) error {
+	args := m.Called(userID, projectID, status, limit)
+	return args.Error(0)
+}
@@ -0,0 +1,52 @@
+package gorm_board
+
+import (
+	"taskflow/internal/domain/board"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&board.Column{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestBoardRepository_WIPLimits(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewBoardRepository(db)
+	projectID := 4
+
+	limit, err := r.WIPLimit(1, nil, "in-progress")
+	require.NoError(t, err)
+	assert.Equal(t, 0, limit, "columns without settings have no limit")
+
+	require.NoError(t, r.SetWIPLimit(1, nil, "in-progress", 3))
+	require.NoError(t, r.SetWIPLimit(1, &projectID, "in-progress", 5))
+	require.NoError(t, r.SetWIPLimit(2, nil, "in-progress", 9))
+	require.NoError(t, r.SetWIPLimit(1, nil, "in-progress", 2))
+
+	limit, err = r.WIPLimit(1, nil, "in-progress")
+	require.NoError(t, err)
+	assert.Equal(t, 2, limit)
+
+	limit, err = r.WIPLimit(1, &projectID, "in-progress")
+	require.NoError(t, err)
+	assert.Equal(t, 5, limit)
+
+	columns, err := r.ListColumns(1, nil)
+	require.NoError(t, err)
+	require.Len(t, columns, 1, "updating a limit does not add a row")
+	assert.Equal(t, "in-progress", columns[0].Status)
+}
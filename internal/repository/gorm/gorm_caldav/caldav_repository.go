@@ -0,0 +1,119 @@
+package gorm_caldav
+
+import (
+	"time"
+
+	"taskflow/internal/domain/caldav"
+	"taskflow/internal/domain/task"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalDAVRepository struct {
+	db *gorm.DB
+}
+
+func NewCalDAVRepository(db *gorm.DB) *CalDAVRepository {
+	return &CalDAVRepository{db: db}
+}
+
+// Compile-time check
+var _ CalDAVRepositoryInterface = (*CalDAVRepository)(nil)
+
+func (r *CalDAVRepository) CreateObject(o *caldav.Object) error {
+	return r.db.Create(o).Error
+}
+
+func (r *CalDAVRepository) ListObjects(userID int) ([]caldav.Object, error) {
+	var objs []caldav.Object
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&objs).Error
+	return objs, err
+}
+
+func (r *CalDAVRepository) GetObjectByTask(taskID int) (*caldav.Object, error) {
+	var o caldav.Object
+	if err := r.db.Where("task_id = ?", taskID).First(&o).Error; err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func (r *CalDAVRepository) DeleteObject(taskID int) error {
+	return r.db.Where("task_id = ?", taskID).Delete(&caldav.Object{}).Error
+}
+
+// AddTombstone records a deleted task. A task already recorded is skipped.
+func (r *CalDAVRepository) AddTombstone(t *caldav.Tombstone) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(t).Error
+}
+
+func (r *CalDAVRepository) Tombstones(userID int, afterID int64) ([]caldav.Tombstone, error) {
+	var tombs []caldav.Tombstone
+	err := r.db.Where("user_id = ? AND id > ?", userID, afterID).Order("id ASC").Find(&tombs).Error
+	return tombs, err
+}
+
+func (r *CalDAVRepository) LastTombstoneID(userID int) (int64, error) {
+	var tombs []caldav.Tombstone
+	err := r.db.Select("id").Where("user_id = ?", userID).Order("id DESC").Limit(1).Find(&tombs).Error
+	if err != nil || len(tombs) == 0 {
+		return 0, err
+	}
+	return tombs[0].ID, nil
+}
+
+func (r *CalDAVRepository) PurgeTombstones(before time.Time) (int64, error) {
+	res := r.db.Where("created_at < ?", before).Delete(&caldav.Tombstone{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *CalDAVRepository) Changed(userID int, since time.Time) ([]task.Task, error) {
+	var tasks []task.Task
+	err := r.db.Unscoped().
+		Preload("Tags").
+		Where("user_id = ? AND (updated_at > ? OR deleted_at > ?)", userID, since, since).
+		Order("id").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *CalDAVRepository) LastModified(userID int) (time.Time, error) {
+	var updated, deleted []task.Task
+	err := r.db.Unscoped().
+		Select("updated_at").
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Limit(1).
+		Find(&updated).Error
+	if err != nil {
+		return time.Time{}, err
+	}
+	err = r.db.Unscoped().
+		Select("deleted_at").
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Limit(1).
+		Find(&deleted).Error
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var last time.Time
+	if len(updated) > 0 {
+		last = updated[0].UpdatedAt
+	}
+	if len(deleted) > 0 && deleted[0].DeletedAt.Time.After(last) {
+		last = deleted[0].DeletedAt.Time
+	}
+	return last, nil
+}
+
+func (r *CalDAVRepository) DeleteUserData(userID int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&caldav.Object{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&caldav.Tombstone{}).Error
+	})
+}
@@ -0,0 +1,28 @@
+package gorm_caldav
+
+import (
+	"time"
+
+	"taskflow/internal/domain/caldav"
+	"taskflow/internal/domain/task"
+)
+
+type CalDAVRepositoryInterface interface {
+	CreateObject(o *caldav.Object) error
+	ListObjects(userID int) ([]caldav.Object, error)
+	GetObjectByTask(taskID int) (*caldav.Object, error)
+	DeleteObject(taskID int) error
+
+	AddTombstone(t *caldav.Tombstone) error
+	Tombstones(userID int, afterID int64) ([]caldav.Tombstone, error)
+	LastTombstoneID(userID int) (int64, error)
+	PurgeTombstones(before time.Time) (int64, error)
+
+	// Changed returns the user's tasks changed or moved to the trash
+	// after since, including trashed ones.
+	Changed(userID int, since time.Time) ([]task.Task, error)
+	// LastModified returns when the user's tasks last changed, or the
+	// zero time.
+	LastModified(userID int) (time.Time, error)
+	DeleteUserData(userID int) error
+}
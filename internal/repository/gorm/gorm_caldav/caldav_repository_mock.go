@@ -0,0 +1,71 @@
+package gorm_caldav
+
+import (
+	"time"
+
+	"taskflow/internal/domain/caldav"
+	"taskflow/internal/domain/task"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CalDAVRepoMock struct {
+	mock.Mock
+}
+
+var _ CalDAVRepositoryInterface = (*CalDAVRepoMock)(nil)
+
+func (m *CalDAVRepoMock) CreateObject(o *caldav.Object) error {
+	args := m.Called(o)
+	return args.Error(0)
+}
+
+func (m *CalDAVRepoMock) ListObjects(userID int) ([]caldav.Object, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]caldav.Object), args.Error(1)
+}
+
+func (m *CalDAVRepoMock) GetObjectByTask(taskID int) (*caldav.Object, error) {
+	args := m.Called(taskID)
+	return args.Get(0).(*caldav.Object), args.Error(1)
+}
+
+func (m *CalDAVRepoMock) DeleteObject(taskID int) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *CalDAVRepoMock) AddTombstone(t *caldav.Tombstone) error {
+	args := m.Called(t)
+	return args.Error(0)
+}
+
+func (m *CalDAVRepoMock) Tombstones(userID int, afterID int64) ([]caldav.Tombstone, error) {
+	args := m.Called(userID, afterID)
+	return args.Get(0).([]caldav.Tombstone), args.Error(1)
+}
+
+func (m *CalDAVRepoMock) LastTombstoneID(userID int) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *CalDAVRepoMock) PurgeTombstones(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *CalDAVRepoMock) Changed(userID int, since time.Time) ([]task.Task, error) {
+	args := m.Called(userID, since)
+	return args.Get(0).([]task.Task), args.Error(1)
+}
+
+func (m *CalDAVRepoMock) LastModified(userID int) (time.Time, error) {
+	args := m.Called(userID)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *CalDAVRepoMock) DeleteUserData(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
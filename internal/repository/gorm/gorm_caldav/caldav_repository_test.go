@@ -0,0 +1,121 @@
+package gorm_caldav
+
+import (
+	"taskflow/internal/domain/caldav"
+	"taskflow/internal/domain/task"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&task.Task{}, &task.Tag{}, &caldav.Object{}, &caldav.Tombstone{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestCalDAVRepository_Objects(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewCalDAVRepository(db)
+
+	require.NoError(t, r.CreateObject(&caldav.Object{UserID: 1, TaskID: 1, Name: "a.ics", UID: "a"}))
+	require.NoError(t, r.CreateObject(&caldav.Object{UserID: 1, TaskID: 2, Name: "b.ics", UID: "b"}))
+	require.NoError(t, r.CreateObject(&caldav.Object{UserID: 2, TaskID: 3, Name: "a.ics", UID: "a"}))
+	assert.Error(t, r.CreateObject(&caldav.Object{UserID: 1, TaskID: 4, Name: "a.ics", UID: "c"}), "names are unique per user")
+
+	objs, err := r.ListObjects(1)
+	require.NoError(t, err)
+	assert.Len(t, objs, 2)
+
+	o, err := r.GetObjectByTask(2)
+	require.NoError(t, err)
+	assert.Equal(t, "b.ics", o.Name)
+
+	require.NoError(t, r.DeleteObject(2))
+	_, err = r.GetObjectByTask(2)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	require.NoError(t, r.DeleteUserData(1))
+	objs, err = r.ListObjects(1)
+	require.NoError(t, err)
+	assert.Empty(t, objs)
+	objs, err = r.ListObjects(2)
+	require.NoError(t, err)
+	assert.Len(t, objs, 1)
+}
+
+func TestCalDAVRepository_Tombstones(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewCalDAVRepository(db)
+
+	last, err := r.LastTombstoneID(1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), last)
+
+	a := caldav.Tombstone{UserID: 1, TaskID: 1, Name: "a.ics"}
+	require.NoError(t, r.AddTombstone(&a))
+	require.NoError(t, r.AddTombstone(&caldav.Tombstone{UserID: 2, TaskID: 2, Name: "task-2.ics"}))
+	b := caldav.Tombstone{UserID: 1, TaskID: 3, Name: "task-3.ics"}
+	require.NoError(t, r.AddTombstone(&b))
+	require.NoError(t, r.AddTombstone(&caldav.Tombstone{UserID: 1, TaskID: 1, Name: "again.ics"}), "a task is recorded once")
+
+	tombs, err := r.Tombstones(1, 0)
+	require.NoError(t, err)
+	require.Len(t, tombs, 2)
+	assert.Equal(t, "a.ics", tombs[0].Name)
+	tombs, err = r.Tombstones(1, a.ID)
+	require.NoError(t, err)
+	require.Len(t, tombs, 1)
+	assert.Equal(t, b.ID, tombs[0].ID)
+
+	last, err = r.LastTombstoneID(1)
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, last)
+
+	n, err := r.PurgeTombstones(time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+}
+
+func TestCalDAVRepository_Changes(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewCalDAVRepository(db)
+
+	last, err := r.LastModified(1)
+	require.NoError(t, err)
+	assert.True(t, last.IsZero())
+
+	t0 := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	old := task.Task{Task: "Old", Status: "pending", UserID: 1, UpdatedAt: t0}
+	fresh := task.Task{Task: "Fresh", Status: "pending", UserID: 1, UpdatedAt: t0.Add(time.Hour), Tags: []task.Tag{{Name: "home"}}}
+	trashed := task.Task{Task: "Trashed", Status: "pending", UserID: 1, UpdatedAt: t0}
+	theirs := task.Task{Task: "Theirs", Status: "pending", UserID: 2, UpdatedAt: t0.Add(time.Hour)}
+	for _, tk := range []*task.Task{&old, &fresh, &trashed, &theirs} {
+		require.NoError(t, db.Omit("UpdatedAt").Create(tk).Error)
+		require.NoError(t, db.Model(tk).UpdateColumn("updated_at", tk.UpdatedAt).Error)
+	}
+	require.NoError(t, db.Model(&trashed).UpdateColumn("deleted_at", t0.Add(2*time.Hour)).Error)
+
+	changed, err := r.Changed(1, t0.Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, changed, 2)
+	assert.Equal(t, "Fresh", changed[0].Task)
+	assert.Len(t, changed[0].Tags, 1)
+	assert.Equal(t, "Trashed", changed[1].Task)
+	assert.True(t, changed[1].DeletedAt.Valid)
+
+	last, err = r.LastModified(1)
+	require.NoError(t, err)
+	assert.True(t, t0.Add(2*time.Hour).Equal(last), "trashing counts as a change")
+}
@@ -0,0 +1,51 @@
+package gorm_calendar
+
+import (
+	"taskflow/internal/domain/calendar"
+
+	"gorm.io/gorm"
+)
+
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// Compile-time check
+var _ CalendarRepositoryInterface = (*CalendarRepository)(nil)
+
+func (r *CalendarRepository) Create(f *calendar.Feed) error {
+	return r.db.Create(f).Error
+}
+
+func (r *CalendarRepository) List(userID int) ([]calendar.Feed, error) {
+	var feeds []calendar.Feed
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&feeds).Error
+	return feeds, err
+}
+
+func (r *CalendarRepository) GetByToken(token string) (*calendar.Feed, error) {
+	var f calendar.Feed
+	if err := r.db.Where("token = ?", token).First(&f).Error; err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *CalendarRepository) Delete(userID int, id int) error {
+	res := r.db.Where("user_id = ?", userID).Delete(&calendar.Feed{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *CalendarRepository) DeleteUserFeeds(userID int) error {
+	return r.db.Where("user_id = ?", userID).Delete(&calendar.Feed{}).Error
+}
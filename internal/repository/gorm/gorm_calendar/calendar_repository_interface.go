@@ -0,0 +1,11 @@
+package gorm_calendar
+
+import "taskflow/internal/domain/calendar"
+
+type CalendarRepositoryInterface interface {
+	Create(f *calendar.Feed) error
+	List(userID int) ([]calendar.Feed, error)
+	GetByToken(token string) (*calendar.Feed, error)
+	Delete(userID int, id int) error
+	DeleteUserFeeds(userID int) error
+}
@@ -0,0 +1,38 @@
+package gorm_calendar
+
+import (
+	"taskflow/internal/domain/calendar"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CalendarRepoMock struct {
+	mock.Mock
+}
+
+var _ CalendarRepositoryInterface = (*CalendarRepoMock)(nil)
+
+func (m *CalendarRepoMock) Create(f *calendar.Feed) error {
+	args := m.Called(f)
+	return args.Error(0)
+}
+
+func (m *CalendarRepoMock) List(userID int) ([]calendar.Feed, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]calendar.Feed), args.Error(1)
+}
+
+func (m *CalendarRepoMock) GetByToken(token string) (*calendar.Feed, error) {
+	args := m.Called(token)
+	return args.Get(0).(*calendar.Feed), args.Error(1)
+}
+
+func (m *CalendarRepoMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *CalendarRepoMock) DeleteUserFeeds(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
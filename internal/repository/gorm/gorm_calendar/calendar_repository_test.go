@@ -0,0 +1,60 @@
+package gorm_calendar
+
+import (
+	"taskflow/internal/domain/calendar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&calendar.Feed{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestCalendarRepository(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewCalendarRepository(db)
+
+	project := 3
+	a := calendar.Feed{UserID: 1, Name: "All", Token: "tok-a", Component: calendar.ComponentEvent}
+	require.NoError(t, r.Create(&a))
+	b := calendar.Feed{UserID: 1, Name: "Work", Token: "tok-b", Component: calendar.ComponentTodo, ProjectID: &project, IncludeCompleted: true}
+	require.NoError(t, r.Create(&b))
+	require.NoError(t, r.Create(&calendar.Feed{UserID: 2, Name: "Other", Token: "tok-c", Component: calendar.ComponentEvent}))
+	assert.Error(t, r.Create(&calendar.Feed{UserID: 2, Name: "Dup", Token: "tok-a", Component: calendar.ComponentEvent}), "tokens are unique")
+
+	got, err := r.GetByToken("tok-b")
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, got.ID)
+	assert.Equal(t, &project, got.ProjectID)
+	assert.True(t, got.IncludeCompleted)
+	_, err = r.GetByToken("nope")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	assert.ErrorIs(t, r.Delete(2, a.ID), gorm.ErrRecordNotFound, "only the owner can delete")
+	require.NoError(t, r.Delete(1, a.ID))
+	list, err := r.List(1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, b.ID, list[0].ID)
+
+	require.NoError(t, r.DeleteUserFeeds(1))
+	list, err = r.List(1)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	list, err = r.List(2)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
@@ -0,0 +1,71 @@
+package gorm_comment
+
+import (
+	"taskflow/internal/domain/comment"
+
+	"gorm.io/gorm"
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// Compile-time check
+var _ CommentRepositoryInterface = (*CommentRepository)(nil)
+
+func (r *CommentRepository) Create(c *comment.Comment) error {
+	return r.db.Create(c).Error
+}
+
+func (r *CommentRepository) GetByID(taskID int, id int) (*comment.Comment, error) {
+	var c comment.Comment
+	err := r.db.Preload("Mentions").
+		Where("id = ? AND task_id = ?", id, taskID).
+		First(&c).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ListByTask returns a page of comments on a task, oldest first, along with
+// the total number of comments on the task.
+func (r *CommentRepository) ListByTask(taskID int, offset int, limit int) ([]comment.Comment, int64, error) {
+	var total int64
+	if err := r.db.Model(&comment.Comment{}).Where("task_id = ?", taskID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []comment.Comment
+	err := r.db.Preload("Mentions").
+		Where("task_id = ?", taskID).
+		Order("created_at ASC, id ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&comments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// Update saves the comment body and replaces its mentions.
+func (r *CommentRepository) Update(c *comment.Comment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(c).Update("body", c.Body).Error; err != nil {
+			return err
+		}
+		return tx.Model(c).Association("Mentions").Unscoped().Replace(c.Mentions)
+	})
+}
+
+// Delete soft-deletes a comment.
+func (r *CommentRepository) Delete(taskID int, id int) error {
+	return r.db.
+		Where("task_id = ?", taskID).
+		Delete(&comment.Comment{}, id).Error
+}
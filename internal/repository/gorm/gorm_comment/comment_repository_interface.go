@@ -0,0 +1,11 @@
+package gorm_comment
+
+import "taskflow/internal/domain/comment"
+
+type CommentRepositoryInterface interface {
+	Create(comment *comment.Comment) error
+	GetByID(taskID int, id int) (*comment.Comment, error)
+	ListByTask(taskID int, offset int, limit int) ([]comment.Comment, int64, error)
+	Update(comment *comment.Comment) error
+	Delete(taskID int, id int) error
+}
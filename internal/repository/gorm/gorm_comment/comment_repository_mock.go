@@ -0,0 +1,41 @@
+package gorm_comment
+
+import (
+	"taskflow/internal/domain/comment"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CommentRepoMock struct {
+	mock.Mock
+}
+
+var _ CommentRepositoryInterface = (*CommentRepoMock)(nil)
+
+func (m *CommentRepoMock) Create(c *comment.Comment) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *CommentRepoMock) GetByID(taskID int, id int) (*comment.Comment, error) {
+	args := m.Called(taskID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*comment.Comment), args.Error(1)
+}
+
+func (m *CommentRepoMock) ListByTask(taskID int, offset int, limit int) ([]comment.Comment, int64, error) {
+	args := m.Called(taskID, offset, limit)
+	return args.Get(0).([]comment.Comment), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *CommentRepoMock) Update(c *comment.Comment) error {
+	args := m.Called(c)
+	return args.Error(0)
+}
+
+func (m *CommentRepoMock) Delete(taskID int, id int) error {
+	args := m.Called(taskID, id)
+	return args.Error(0)
+}
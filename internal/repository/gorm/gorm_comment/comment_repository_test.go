@@ -0,0 +1,114 @@
+package gorm_comment
+
+import (
+	"errors"
+	"taskflow/internal/domain/comment"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&comment.Comment{}, &comment.Mention{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestCommentRepository_Create(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewCommentRepository(db)
+
+	c := comment.Comment{
+		TaskID:   1,
+		UserID:   1,
+		Body:     "hello @jane@example.com",
+		Mentions: []comment.Mention{{UserID: 2}},
+	}
+	require.NoError(t, r.Create(&c))
+	assert.NotZero(t, c.ID)
+
+	got, err := r.GetByID(1, c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, c.Body, got.Body)
+	require.Len(t, got.Mentions, 1)
+	assert.Equal(t, 2, got.Mentions[0].UserID)
+}
+
+func TestCommentRepository_GetByID(t *testing.T) {
+	t.Run("wrong task", func(t *testing.T) {
+		db := setupTestDB(t)
+		r := NewCommentRepository(db)
+
+		c := comment.Comment{TaskID: 1, UserID: 1, Body: "hi"}
+		require.NoError(t, db.Create(&c).Error)
+
+		got, err := r.GetByID(2, c.ID)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+		assert.Nil(t, got)
+	})
+}
+
+func TestCommentRepository_ListByTask(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewCommentRepository(db)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, db.Create(&comment.Comment{TaskID: 1, UserID: 1, Body: "c"}).Error)
+	}
+	require.NoError(t, db.Create(&comment.Comment{TaskID: 2, UserID: 1, Body: "other"}).Error)
+
+	got, total, err := r.ListByTask(1, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	assert.Len(t, got, 2)
+	assert.Equal(t, 3, got[0].ID)
+}
+
+func TestCommentRepository_Update(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewCommentRepository(db)
+
+	c := comment.Comment{TaskID: 1, UserID: 1, Body: "old", Mentions: []comment.Mention{{UserID: 2}}}
+	require.NoError(t, r.Create(&c))
+
+	c.Body = "new"
+	c.Mentions = []comment.Mention{{UserID: 3}}
+	require.NoError(t, r.Update(&c))
+
+	got, err := r.GetByID(1, c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "new", got.Body)
+	require.Len(t, got.Mentions, 1)
+	assert.Equal(t, 3, got.Mentions[0].UserID)
+
+	var count int64
+	db.Model(&comment.Mention{}).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestCommentRepository_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewCommentRepository(db)
+
+	c := comment.Comment{TaskID: 1, UserID: 1, Body: "bye"}
+	require.NoError(t, r.Create(&c))
+	require.NoError(t, r.Delete(1, c.ID))
+
+	_, err := r.GetByID(1, c.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	// Soft delete keeps the row around
+	var raw comment.Comment
+	require.NoError(t, db.Unscoped().First(&raw, c.ID).Error)
+	assert.True(t, raw.DeletedAt.Valid)
+}
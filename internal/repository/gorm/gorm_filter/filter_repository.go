@@ -0,0 +1,69 @@
+package gorm_filter
+
+import (
+	"taskflow/internal/domain/filter"
+
+	"gorm.io/gorm"
+)
+
+type FilterRepository struct {
+	db *gorm.DB
+}
+
+func NewFilterRepository(db *gorm.DB) *FilterRepository {
+	return &FilterRepository{db: db}
+}
+
+// Compile-time check
+var _ FilterRepositoryInterface = (*FilterRepository)(nil)
+
+func (r *FilterRepository) Create(f *filter.Filter) error {
+	return r.db.Create(f).Error
+}
+
+func (r *FilterRepository) CreateBatch(filters []filter.Filter) error {
+	if len(filters) == 0 {
+		return nil
+	}
+	return r.db.Create(&filters).Error
+}
+
+func (r *FilterRepository) GetByID(userID int, id int) (*filter.Filter, error) {
+	var f filter.Filter
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&f).Error
+	if err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// List returns the user's filters, built-in smart lists first.
+func (r *FilterRepository) List(userID int) ([]filter.Filter, error) {
+	var filters []filter.Filter
+	err := r.db.Where("user_id = ?", userID).
+		Order("builtin DESC, id ASC").
+		Find(&filters).Error
+	if err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// Update saves every field, so cleared criteria are persisted as NULL/empty.
+func (r *FilterRepository) Update(f *filter.Filter) error {
+	return r.db.Save(f).Error
+}
+
+func (r *FilterRepository) Delete(userID int, id int) error {
+	return r.db.
+		Where("user_id = ?", userID).
+		Delete(&filter.Filter{}, id).Error
+}
+
+func (r *FilterRepository) HasBuiltins(userID int) (bool, error) {
+	var count int64
+	err := r.db.Model(&filter.Filter{}).
+		Where("user_id = ? AND builtin = ?", userID, true).
+		Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,13 @@
+package gorm_filter
+
+import "taskflow/internal/domain/filter"
+
+type FilterRepositoryInterface interface {
+	Create(filter *filter.Filter) error
+	CreateBatch(filters []filter.Filter) error
+	GetByID(userID int, id int) (*filter.Filter, error)
+	List(userID int) ([]filter.Filter, error)
+	Update(filter *filter.Filter) error
+	Delete(userID int, id int) error
+	HasBuiltins(userID int) (bool, error)
+}
@@ -0,0 +1,51 @@
+package gorm_filter
+
+import (
+	"taskflow/internal/domain/filter"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type FilterRepoMock struct {
+	mock.Mock
+}
+
+var _ FilterRepositoryInterface = (*FilterRepoMock)(nil)
+
+func (m *FilterRepoMock) Create(f *filter.Filter) error {
+	args := m.Called(f)
+	return args.Error(0)
+}
+
+func (m *FilterRepoMock) CreateBatch(filters []filter.Filter) error {
+	args := m.Called(filters)
+	return args.Error(0)
+}
+
+func (m *FilterRepoMock) GetByID(userID int, id int) (*filter.Filter, error) {
+	args := m.Called(userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*filter.Filter), args.Error(1)
+}
+
+func (m *FilterRepoMock) List(userID int) ([]filter.Filter, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]filter.Filter), args.Error(1)
+}
+
+func (m *FilterRepoMock) Update(f *filter.Filter) error {
+	args := m.Called(f)
+	return args.Error(0)
+}
+
+func (m *FilterRepoMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *FilterRepoMock) HasBuiltins(userID int) (bool, error) {
+	args := m.Called(userID)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,87 @@
+package gorm_filter
+
+import (
+	"errors"
+	"taskflow/internal/domain/filter"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&filter.Filter{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestFilterRepository_CreateAndGet(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewFilterRepository(db)
+
+	f := filter.Filter{
+		UserID:   1,
+		Name:     "Work",
+		Criteria: filter.Criteria{Status: "pending", Text: "report"},
+		SortBy:   filter.SortPriority,
+		SortDesc: true,
+	}
+	require.NoError(t, r.Create(&f))
+	assert.NotZero(t, f.ID)
+
+	got, err := r.GetByID(1, f.ID)
+	require.NoError(t, err)
+	assert.Equal(t, f.Criteria, got.Criteria)
+	assert.True(t, got.SortDesc)
+
+	_, err = r.GetByID(2, f.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestFilterRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewFilterRepository(db)
+
+	require.NoError(t, r.Create(&filter.Filter{UserID: 1, Name: "Mine", SortBy: filter.SortCreatedAt}))
+	require.NoError(t, r.CreateBatch(filter.Defaults(1)))
+	require.NoError(t, r.Create(&filter.Filter{UserID: 2, Name: "Someone else's", SortBy: filter.SortCreatedAt}))
+
+	got, err := r.List(1)
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+	assert.True(t, got[0].Builtin)
+	assert.Equal(t, "Mine", got[3].Name)
+
+	has, err := r.HasBuiltins(1)
+	require.NoError(t, err)
+	assert.True(t, has)
+
+	has, err = r.HasBuiltins(2)
+	require.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestFilterRepository_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewFilterRepository(db)
+
+	f := filter.Filter{UserID: 1, Name: "Work", SortBy: filter.SortCreatedAt}
+	require.NoError(t, r.Create(&f))
+
+	require.NoError(t, r.Delete(2, f.ID))
+	_, err := r.GetByID(1, f.ID)
+	require.NoError(t, err, "another user's delete must not remove the filter")
+
+	require.NoError(t, r.Delete(1, f.ID))
+	_, err = r.GetByID(1, f.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
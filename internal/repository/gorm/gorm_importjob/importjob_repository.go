@@ -0,0 +1,56 @@
+package gorm_importjob
+
+import (
+	"taskflow/internal/domain/importjob"
+
+	"gorm.io/gorm"
+)
+
+type ImportJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) *ImportJobRepository {
+	return &ImportJobRepository{db: db}
+}
+
+// Compile-time check
+var _ ImportJobRepositoryInterface = (*ImportJobRepository)(nil)
+
+func (r *ImportJobRepository) Create(j *importjob.Job) error {
+	return r.db.Create(j).Error
+}
+
+func (r *ImportJobRepository) Get(userID int, id int) (*importjob.Job, error) {
+	var j importjob.Job
+	if err := r.db.Where("user_id = ?", userID).First(&j, id).Error; err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// List returns the user's jobs, newest first.
+func (r *ImportJobRepository) List(userID int) ([]importjob.Job, error) {
+	var jobs []importjob.Job
+	err := r.db.Where("user_id = ?", userID).Order("id DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+func (r *ImportJobRepository) Update(j *importjob.Job) error {
+	return r.db.Save(j).Error
+}
+
+func (r *ImportJobRepository) Delete(userID int, id int) error {
+	res := r.db.Where("user_id = ?", userID).Delete(&importjob.Job{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) DeleteUserJobs(userID int) error {
+	return r.db.Where("user_id = ?", userID).Delete(&importjob.Job{}).Error
+}
@@ -0,0 +1,12 @@
+package gorm_importjob
+
+import "taskflow/internal/domain/importjob"
+
+type ImportJobRepositoryInterface interface {
+	Create(j *importjob.Job) error
+	Get(userID int, id int) (*importjob.Job, error)
+	List(userID int) ([]importjob.Job, error)
+	Update(j *importjob.Job) error
+	Delete(userID int, id int) error
+	DeleteUserJobs(userID int) error
+}
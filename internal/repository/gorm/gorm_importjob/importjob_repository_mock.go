@@ -0,0 +1,43 @@
+package gorm_importjob
+
+import (
+	"taskflow/internal/domain/importjob"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ImportJobRepoMock struct {
+	mock.Mock
+}
+
+var _ ImportJobRepositoryInterface = (*ImportJobRepoMock)(nil)
+
+func (m *ImportJobRepoMock) Create(j *importjob.Job) error {
+	args := m.Called(j)
+	return args.Error(0)
+}
+
+func (m *ImportJobRepoMock) Get(userID int, id int) (*importjob.Job, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(*importjob.Job), args.Error(1)
+}
+
+func (m *ImportJobRepoMock) List(userID int) ([]importjob.Job, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]importjob.Job), args.Error(1)
+}
+
+func (m *ImportJobRepoMock) Update(j *importjob.Job) error {
+	args := m.Called(j)
+	return args.Error(0)
+}
+
+func (m *ImportJobRepoMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *ImportJobRepoMock) DeleteUserJobs(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
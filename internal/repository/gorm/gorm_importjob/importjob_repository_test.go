@@ -0,0 +1,69 @@
+package gorm_importjob
+
+import (
+	"taskflow/internal/domain/importjob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&importjob.Job{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestImportJobRepository(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewImportJobRepository(db)
+
+	a := importjob.Job{UserID: 1, Source: "todoist", FileName: "backup.json", StorageKey: "imports/1/a", Status: importjob.StatusPending}
+	require.NoError(t, r.Create(&a))
+	b := importjob.Job{UserID: 1, Source: "trello", FileName: "board.json", StorageKey: "imports/1/b", Status: importjob.StatusPending}
+	require.NoError(t, r.Create(&b))
+	require.NoError(t, r.Create(&importjob.Job{UserID: 2, Source: "todotxt", FileName: "todo.txt", StorageKey: "imports/2/c", Status: importjob.StatusPending}))
+	assert.Equal(t, 1, a.Run)
+
+	b.Status = importjob.StatusFailed
+	b.Failed = 1
+	b.Errors = []importjob.ItemError{{ID: "c1", Name: "Card", Message: "task name cannot be empty"}}
+	require.NoError(t, r.Update(&b))
+
+	got, err := r.Get(1, b.ID)
+	require.NoError(t, err)
+	assert.Equal(t, importjob.StatusFailed, got.Status)
+	assert.Equal(t, b.Errors, got.Errors)
+	assert.True(t, got.Finished())
+
+	_, err = r.Get(2, a.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "jobs of other users are not found")
+
+	jobs, err := r.List(1)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, b.ID, jobs[0].ID, "newest first")
+
+	assert.ErrorIs(t, r.Delete(2, a.ID), gorm.ErrRecordNotFound)
+	require.NoError(t, r.Delete(1, a.ID))
+	jobs, err = r.List(1)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+
+	require.NoError(t, r.DeleteUserJobs(1))
+	jobs, err = r.List(1)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+	jobs, err = r.List(2)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
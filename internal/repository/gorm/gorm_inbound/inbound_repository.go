@@ -0,0 +1,58 @@
+package gorm_inbound
+
+import (
+	"time"
+
+	"taskflow/internal/domain/inbound"
+
+	"gorm.io/gorm"
+)
+
+type InboundRepository struct {
+	db *gorm.DB
+}
+
+func NewInboundRepository(db *gorm.DB) *InboundRepository {
+	return &InboundRepository{db: db}
+}
+
+// Compile-time check
+var _ InboundRepositoryInterface = (*InboundRepository)(nil)
+
+func (r *InboundRepository) Create(a *inbound.Address) error {
+	return r.db.Create(a).Error
+}
+
+func (r *InboundRepository) List(userID int) ([]inbound.Address, error) {
+	var addrs []inbound.Address
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&addrs).Error
+	return addrs, err
+}
+
+func (r *InboundRepository) GetByToken(token string) (*inbound.Address, error) {
+	var a inbound.Address
+	if err := r.db.Where("token = ?", token).First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *InboundRepository) Delete(userID int, id int) error {
+	res := r.db.Where("user_id = ?", userID).Delete(&inbound.Address{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *InboundRepository) DeleteUserAddresses(userID int) error {
+	return r.db.Where("user_id = ?", userID).Delete(&inbound.Address{}).Error
+}
+
+// Touch records when an address last created a task.
+func (r *InboundRepository) Touch(id int, at time.Time) error {
+	return r.db.Model(&inbound.Address{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
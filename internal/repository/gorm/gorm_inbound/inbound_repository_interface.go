@@ -0,0 +1,16 @@
+package gorm_inbound
+
+import (
+	"time"
+
+	"taskflow/internal/domain/inbound"
+)
+
+type InboundRepositoryInterface interface {
+	Create(a *inbound.Address) error
+	List(userID int) ([]inbound.Address, error)
+	GetByToken(token string) (*inbound.Address, error)
+	Delete(userID int, id int) error
+	DeleteUserAddresses(userID int) error
+	Touch(id int, at time.Time) error
+}
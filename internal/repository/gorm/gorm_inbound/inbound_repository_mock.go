@@ -0,0 +1,45 @@
+package gorm_inbound
+
+import (
+	"time"
+
+	"taskflow/internal/domain/inbound"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type InboundRepoMock struct {
+	mock.Mock
+}
+
+var _ InboundRepositoryInterface = (*InboundRepoMock)(nil)
+
+func (m *InboundRepoMock) Create(a *inbound.Address) error {
+	args := m.Called(a)
+	return args.Error(0)
+}
+
+func (m *InboundRepoMock) List(userID int) ([]inbound.Address, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]inbound.Address), args.Error(1)
+}
+
+func (m *InboundRepoMock) GetByToken(token string) (*inbound.Address, error) {
+	args := m.Called(token)
+	return args.Get(0).(*inbound.Address), args.Error(1)
+}
+
+func (m *InboundRepoMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *InboundRepoMock) DeleteUserAddresses(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *InboundRepoMock) Touch(id int, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
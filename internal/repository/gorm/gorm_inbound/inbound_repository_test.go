@@ -0,0 +1,66 @@
+package gorm_inbound
+
+import (
+	"taskflow/internal/domain/inbound"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&inbound.Address{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestInboundRepository(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewInboundRepository(db)
+
+	a := inbound.Address{UserID: 1, Name: "Zapier", Token: "tok-a"}
+	require.NoError(t, r.Create(&a))
+	b := inbound.Address{UserID: 1, Name: "Email", Token: "tok-b"}
+	require.NoError(t, r.Create(&b))
+	require.NoError(t, r.Create(&inbound.Address{UserID: 2, Name: "Other", Token: "tok-c"}))
+	assert.Error(t, r.Create(&inbound.Address{UserID: 2, Name: "Dup", Token: "tok-a"}), "tokens are unique")
+
+	got, err := r.GetByToken("tok-b")
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, got.ID)
+	assert.Nil(t, got.LastUsedAt)
+	_, err = r.GetByToken("nope")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	at := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, r.Touch(b.ID, at))
+	got, err = r.GetByToken("tok-b")
+	require.NoError(t, err)
+	require.NotNil(t, got.LastUsedAt)
+	assert.True(t, at.Equal(*got.LastUsedAt))
+
+	assert.ErrorIs(t, r.Delete(2, a.ID), gorm.ErrRecordNotFound, "only the owner can delete")
+	require.NoError(t, r.Delete(1, a.ID))
+	list, err := r.List(1)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, b.ID, list[0].ID)
+
+	require.NoError(t, r.DeleteUserAddresses(1))
+	list, err = r.List(1)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	list, err = r.List(2)
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+}
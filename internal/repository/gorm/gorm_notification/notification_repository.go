@@ -0,0 +1,115 @@
+package gorm_notification
+
+import (
+	"time"
+
+	"taskflow/internal/domain/notification"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Compile-time check
+var _ NotificationRepositoryInterface = (*NotificationRepository)(nil)
+
+// Create stores a notification. A notification whose Key was already stored
+// is skipped, so retried deliveries do not show up twice.
+func (r *NotificationRepository) Create(n *notification.Notification) error {
+	db := r.db
+	if n.Key != nil {
+		db = db.Clauses(clause.OnConflict{DoNothing: true})
+	}
+	return db.Create(n).Error
+}
+
+// List returns a page of the user's notifications, newest first, and the
+// total number matching.
+func (r *NotificationRepository) List(userID int, unreadOnly bool, offset int, limit int) ([]notification.Notification, int64, error) {
+	query := r.db.Model(&notification.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []notification.Notification
+	err := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&notifications).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+func (r *NotificationRepository) CountUnread(userID int) (int64, error) {
+	var n int64
+	err := r.db.Model(&notification.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&n).Error
+	return n, err
+}
+
+// MarkRead marks one notification as read. Notifications that are already
+// read keep their original read time.
+func (r *NotificationRepository) MarkRead(userID int, id int, at time.Time) error {
+	result := r.db.Model(&notification.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var n notification.Notification
+		return r.db.Select("id").Where("id = ? AND user_id = ?", id, userID).First(&n).Error
+	}
+	return nil
+}
+
+// MarkAllRead marks every unread notification of the user as read and
+// returns how many there were.
+func (r *NotificationRepository) MarkAllRead(userID int, at time.Time) (int64, error) {
+	result := r.db.Model(&notification.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
+
+func (r *NotificationRepository) GetPreferences(userID int) ([]notification.Preference, error) {
+	var prefs []notification.Preference
+	err := r.db.Where("user_id = ?", userID).Order("type ASC").Find(&prefs).Error
+	return prefs, err
+}
+
+// SavePreferences inserts or updates the given preferences.
+func (r *NotificationRepository) SavePreferences(prefs []notification.Preference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"delivery"}),
+	}).Create(&prefs).Error
+}
+
+// DeleteUser removes a user's notifications and preferences.
+func (r *NotificationRepository) DeleteUser(userID int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&notification.Notification{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&notification.Preference{}).Error
+	})
+}
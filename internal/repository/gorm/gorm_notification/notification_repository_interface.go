@@ -0,0 +1,18 @@
+package gorm_notification
+
+import (
+	"time"
+
+	"taskflow/internal/domain/notification"
+)
+
+type NotificationRepositoryInterface interface {
+	Create(notification *notification.Notification) error
+	List(userID int, unreadOnly bool, offset int, limit int) ([]notification.Notification, int64, error)
+	CountUnread(userID int) (int64, error)
+	MarkRead(userID int, id int, at time.Time) error
+	MarkAllRead(userID int, at time.Time) (int64, error)
+	GetPreferences(userID int) ([]notification.Preference, error)
+	SavePreferences(prefs []notification.Preference) error
+	DeleteUser(userID int) error
+}
@@ -0,0 +1,55 @@
+package gorm_notification
+
+import (
+	"time"
+
+	"taskflow/internal/domain/notification"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type NotificationRepoMock struct {
+	mock.Mock
+}
+
+var _ NotificationRepositoryInterface = (*NotificationRepoMock)(nil)
+
+func (m *NotificationRepoMock) Create(n *notification.Notification) error {
+	args := m.Called(n)
+	return args.Error(0)
+}
+
+func (m *NotificationRepoMock) List(userID int, unreadOnly bool, offset int, limit int) ([]notification.Notification, int64, error) {
+	args := m.Called(userID, unreadOnly, offset, limit)
+	return args.Get(0).([]notification.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *NotificationRepoMock) CountUnread(userID int) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationRepoMock) MarkRead(userID int, id int, at time.Time) error {
+	args := m.Called(userID, id, at)
+	return args.Error(0)
+}
+
+func (m *NotificationRepoMock) MarkAllRead(userID int, at time.Time) (int64, error) {
+	args := m.Called(userID, at)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *NotificationRepoMock) GetPreferences(userID int) ([]notification.Preference, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]notification.Preference), args.Error(1)
+}
+
+func (m *NotificationRepoMock) SavePreferences(prefs []notification.Preference) error {
+	args := m.Called(prefs)
+	return args.Error(0)
+}
+
+func (m *NotificationRepoMock) DeleteUser(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
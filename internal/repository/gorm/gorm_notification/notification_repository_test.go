@@ -0,0 +1,150 @@
+package gorm_notification
+
+import (
+	"taskflow/internal/domain/notification"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&notification.Notification{}, &notification.Preference{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNotificationRepository_Create(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewNotificationRepository(db)
+
+	taskID := 4
+	n := notification.Notification{UserID: 1, Type: notification.TypeReminder, Title: "Reminder: Pay rent", TaskID: &taskID}
+	require.NoError(t, r.Create(&n))
+	assert.NotZero(t, n.ID)
+
+	var got notification.Notification
+	require.NoError(t, db.First(&got, n.ID).Error)
+	assert.Equal(t, "Reminder: Pay rent", got.Title)
+	assert.Nil(t, got.ReadAt)
+}
+
+func TestNotificationRepository_CreateDeduplicatesKeys(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewNotificationRepository(db)
+
+	key := "reminder:4:1757332800:1h0m0s"
+	require.NoError(t, r.Create(&notification.Notification{UserID: 1, Type: notification.TypeReminder, Title: "a", Key: &key}))
+	require.NoError(t, r.Create(&notification.Notification{UserID: 1, Type: notification.TypeReminder, Title: "a", Key: &key}))
+	// Notifications without a key are never deduplicated.
+	require.NoError(t, r.Create(&notification.Notification{UserID: 1, Type: notification.TypeSubtasksDone, Title: "b"}))
+	require.NoError(t, r.Create(&notification.Notification{UserID: 1, Type: notification.TypeSubtasksDone, Title: "b"}))
+
+	var count int64
+	require.NoError(t, db.Model(&notification.Notification{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestNotificationRepository_ListAndMarkRead(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewNotificationRepository(db)
+
+	base := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	for i, userID := range []int{1, 1, 1, 2} {
+		n := notification.Notification{UserID: userID, Type: notification.TypeSubtasksDone, Title: "n", CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, r.Create(&n))
+	}
+
+	got, total, err := r.List(1, false, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, got, 2)
+	assert.Equal(t, 3, got[0].ID, "newest first")
+	assert.Equal(t, 2, got[1].ID)
+
+	readAt := base.Add(time.Hour)
+	require.NoError(t, r.MarkRead(1, 2, readAt))
+	// Marking again keeps the first read time.
+	require.NoError(t, r.MarkRead(1, 2, readAt.Add(time.Hour)))
+	assert.ErrorIs(t, r.MarkRead(1, 4, readAt), gorm.ErrRecordNotFound, "other users' notifications")
+	assert.ErrorIs(t, r.MarkRead(1, 99, readAt), gorm.ErrRecordNotFound)
+
+	var n notification.Notification
+	require.NoError(t, db.First(&n, 2).Error)
+	require.NotNil(t, n.ReadAt)
+	assert.True(t, n.ReadAt.Equal(readAt))
+
+	unread, err := r.CountUnread(1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), unread)
+
+	got, total, err = r.List(1, true, 0, 20)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, got, 2)
+
+	marked, err := r.MarkAllRead(1, readAt)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), marked)
+
+	unread, err = r.CountUnread(1)
+	require.NoError(t, err)
+	assert.Zero(t, unread)
+	unread, err = r.CountUnread(2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), unread)
+}
+
+func TestNotificationRepository_Preferences(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewNotificationRepository(db)
+
+	require.NoError(t, r.SavePreferences([]notification.Preference{
+		{UserID: 1, Type: notification.TypeReminder, Delivery: notification.DeliveryEmail},
+		{UserID: 1, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryOff},
+		{UserID: 2, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryBoth},
+	}))
+	require.NoError(t, r.SavePreferences([]notification.Preference{
+		{UserID: 1, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryInApp},
+	}))
+
+	got, err := r.GetPreferences(1)
+	require.NoError(t, err)
+	assert.Equal(t, []notification.Preference{
+		{UserID: 1, Type: notification.TypeReminder, Delivery: notification.DeliveryEmail},
+		{UserID: 1, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryInApp},
+	}, got)
+}
+
+func TestNotificationRepository_DeleteUser(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewNotificationRepository(db)
+
+	require.NoError(t, r.Create(&notification.Notification{UserID: 1, Type: notification.TypeSubtasksDone, Title: "mine"}))
+	require.NoError(t, r.Create(&notification.Notification{UserID: 2, Type: notification.TypeSubtasksDone, Title: "theirs"}))
+	require.NoError(t, r.SavePreferences([]notification.Preference{
+		{UserID: 1, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryOff},
+	}))
+
+	require.NoError(t, r.DeleteUser(1))
+
+	_, total, err := r.List(1, false, 0, 10)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	_, total, err = r.List(2, false, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	prefs, err := r.GetPreferences(1)
+	require.NoError(t, err)
+	assert.Empty(t, prefs)
+}
@@ -0,0 +1,65 @@
+package gorm_project
+
+import (
+	"taskflow/internal/domain/board"
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+
+	"gorm.io/gorm"
+)
+
+type ProjectRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectRepository(db *gorm.DB) *ProjectRepository {
+	return &ProjectRepository{db: db}
+}
+
+// Compile-time check
+var _ ProjectRepositoryInterface = (*ProjectRepository)(nil)
+
+func (r *ProjectRepository) Create(p *project.Project) error {
+	return r.db.Create(p).Error
+}
+
+func (r *ProjectRepository) GetByID(userID int, id int) (*project.Project, error) {
+	var p project.Project
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&p).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *ProjectRepository) List(userID int) ([]project.Project, error) {
+	var projects []project.Project
+	if err := r.db.Where("user_id = ?", userID).Order("name ASC, id ASC").Find(&projects).Error; err != nil {
+		return nil, err
+	}
+	return projects, nil
+}
+
+func (r *ProjectRepository) Update(p *project.Project) error {
+	return r.db.Model(p).Update("name", p.Name).Error
+}
+
+// Delete removes a project and its board settings, and moves its tasks,
+// trashed ones included, back to the inbox.
+func (r *ProjectRepository) Delete(userID int, id int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ?", userID).Delete(&project.Project{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("user_id = ? AND project_id = ?", userID, id).Delete(&board.Column{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&task.Task{}).
+			Where("user_id = ? AND project_id = ?", userID, id).
+			Update("project_id", nil).Error
+	})
+}
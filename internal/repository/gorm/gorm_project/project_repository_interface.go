@@ -0,0 +1,11 @@
+package gorm_project
+
+import "taskflow/internal/domain/project"
+
+type ProjectRepositoryInterface interface {
+	Create(project *project.Project) error
+	GetByID(userID int, id int) (*project.Project, error)
+	List(userID int) ([]project.Project, error)
+	Update(project *project.Project) error
+	Delete(userID int, id int) error
+}
@@ -0,0 +1,41 @@
+package gorm_project
+
+import (
+	"taskflow/internal/domain/project"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ProjectRepoMock struct {
+	mock.Mock
+}
+
+var _ ProjectRepositoryInterface = (*ProjectRepoMock)(nil)
+
+func (m *ProjectRepoMock) Create(p *project.Project) error {
+	args := m.Called(p)
+	return args.Error(0)
+}
+
+func (m *ProjectRepoMock) GetByID(userID int, id int) (*project.Project, error) {
+	args := m.Called(userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.Project), args.Error(1)
+}
+
+func (m *ProjectRepoMock) List(userID int) ([]project.Project, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]project.Project), args.Error(1)
+}
+
+func (m *ProjectRepoMock) Update(p *project.Project) error {
+	args := m.Called(p)
+	return args.Error(0)
+}
+
+func (m *ProjectRepoMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
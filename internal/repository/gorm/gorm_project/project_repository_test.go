@@ -0,0 +1,93 @@
+package gorm_project
+
+import (
+	"errors"
+	"taskflow/internal/domain/board"
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&project.Project{}, &task.Task{}, &board.Column{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestProjectRepository_CreateAndList(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewProjectRepository(db)
+
+	for _, p := range []project.Project{
+		{UserID: 1, Name: "Work"},
+		{UserID: 1, Name: "Home"},
+		{UserID: 2, Name: "Theirs"},
+	} {
+		require.NoError(t, r.Create(&p))
+	}
+
+	got, err := r.List(1)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Home", got[0].Name)
+	assert.Equal(t, "Work", got[1].Name)
+}
+
+func TestProjectRepository_Update(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewProjectRepository(db)
+
+	p := project.Project{UserID: 1, Name: "Wrok"}
+	require.NoError(t, r.Create(&p))
+
+	p.Name = "Work"
+	require.NoError(t, r.Update(&p))
+
+	got, err := r.GetByID(1, p.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Work", got.Name)
+}
+
+func TestProjectRepository_Delete(t *testing.T) {
+	t.Run("tasks move back to the inbox", func(t *testing.T) {
+		db := setupTestDB(t)
+		r := NewProjectRepository(db)
+
+		p := project.Project{UserID: 1, Name: "Work"}
+		require.NoError(t, r.Create(&p))
+		tk := task.Task{Task: "Report", Status: "pending", UserID: 1, ProjectID: &p.ID}
+		require.NoError(t, db.Create(&tk).Error)
+
+		require.NoError(t, r.Delete(1, p.ID))
+
+		_, err := r.GetByID(1, p.ID)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		var got task.Task
+		require.NoError(t, db.First(&got, tk.ID).Error)
+		assert.Nil(t, got.ProjectID)
+	})
+
+	t.Run("someone else's project", func(t *testing.T) {
+		db := setupTestDB(t)
+		r := NewProjectRepository(db)
+
+		p := project.Project{UserID: 2, Name: "Theirs"}
+		require.NoError(t, r.Create(&p))
+
+		err := r.Delete(1, p.ID)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	})
+}
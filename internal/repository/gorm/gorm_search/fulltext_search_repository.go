@@ -0,0 +1,131 @@
+package gorm_search
+
+import (
+	"fmt"
+
+	"taskflow/internal/domain/comment"
+	"taskflow/internal/domain/task"
+
+	"gorm.io/gorm"
+)
+
+const (
+	taskFullTextIndex    = "idx_tasks_fulltext"
+	commentFullTextIndex = "idx_comments_fulltext"
+
+	// Title and description matches weigh twice as much as comment matches.
+	relevanceExpr = `(MATCH(tasks.task, tasks.description) AGAINST (? IN NATURAL LANGUAGE MODE) * 2 + ` +
+		`COALESCE((SELECT MAX(MATCH(c.body) AGAINST (? IN NATURAL LANGUAGE MODE)) FROM comments c ` +
+		`WHERE c.task_id = tasks.id AND c.deleted_at IS NULL), 0))`
+
+	bestCommentExpr = `(SELECT c.body FROM comments c ` +
+		`WHERE c.task_id = tasks.id AND c.deleted_at IS NULL AND MATCH(c.body) AGAINST (? IN NATURAL LANGUAGE MODE) ` +
+		`ORDER BY MATCH(c.body) AGAINST (? IN NATURAL LANGUAGE MODE) DESC LIMIT 1)`
+)
+
+// FullTextSearchRepository searches using MySQL FULLTEXT indexes.
+type FullTextSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewFullTextSearchRepository(db *gorm.DB) *FullTextSearchRepository {
+	return &FullTextSearchRepository{db: db}
+}
+
+// Compile-time check
+var _ SearchRepositoryInterface = (*FullTextSearchRepository)(nil)
+
+type fullTextRow struct {
+	ID          int
+	Score       float64
+	CommentBody *string
+}
+
+func (r *FullTextSearchRepository) Search(q SearchQuery) ([]SearchHit, int64, error) {
+	base := func() *gorm.DB {
+		db := r.db.Model(&task.Task{}).
+			Where("tasks.user_id = ?", q.UserID).
+			Where(relevanceExpr+" > 0", q.Text, q.Text)
+		if q.Status != "" {
+			db = db.Where("tasks.status = ?", q.Status)
+		}
+		if q.Filter != nil {
+			db = q.Filter(db)
+		}
+		return db
+	}
+
+	var total int64
+	if err := base().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []fullTextRow
+	err := base().
+		Select("tasks.id, "+relevanceExpr+" AS score, "+bestCommentExpr+" AS comment_body",
+			q.Text, q.Text, q.Text, q.Text).
+		Order("score DESC, tasks.id DESC").
+		Offset(q.Offset).
+		Limit(q.Limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Load the page's tasks in full, so hits carry what GET /tasks returns.
+	ids := make([]int, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	var tasks []task.Task
+	if err := r.db.Preload("Tags").Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	byID := make(map[int]task.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	hits := make([]SearchHit, 0, len(rows))
+	for _, row := range rows {
+		t, ok := byID[row.ID]
+		if !ok {
+			continue // deleted since the page was ranked
+		}
+		var comments []string
+		if row.CommentBody != nil {
+			comments = []string{*row.CommentBody}
+		}
+		hits = append(hits, buildHit(t, comments, q.Text, row.Score))
+	}
+	return hits, total, nil
+}
+
+// EnsureFullTextIndexes creates the FULLTEXT indexes used by search.
+// It is a no-op on databases other than MySQL.
+func EnsureFullTextIndexes(db *gorm.DB) error {
+	if db.Dialector.Name() != "mysql" {
+		return nil
+	}
+
+	indexes := []struct {
+		model   any
+		table   string
+		name    string
+		columns string
+	}{
+		{&task.Task{}, "tasks", taskFullTextIndex, "task, description"},
+		{&comment.Comment{}, "comments", commentFullTextIndex, "body"},
+	}
+
+	for _, idx := range indexes {
+		if db.Migrator().HasIndex(idx.model, idx.name) {
+			continue
+		}
+		sql := fmt.Sprintf("ALTER TABLE %s ADD FULLTEXT INDEX %s (%s)", idx.table, idx.name, idx.columns)
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to create full-text index %s: %w", idx.name, err)
+		}
+	}
+	return nil
+}
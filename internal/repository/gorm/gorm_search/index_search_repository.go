@@ -0,0 +1,92 @@
+package gorm_search
+
+import (
+	"taskflow/internal/domain/comment"
+	"taskflow/internal/domain/task"
+	"taskflow/pkg/textindex"
+
+	"gorm.io/gorm"
+)
+
+// Field weights for the in-process index. Titles matter most.
+const (
+	titleBoost       = 3
+	descriptionBoost = 1
+	commentBoost     = 0.5
+)
+
+// IndexSearchRepository ranks tasks with an in-process inverted index built
+// from the user's tasks and comments. It is the fallback for databases
+// without full-text support and is meant for small data sets such as tests.
+type IndexSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewIndexSearchRepository(db *gorm.DB) *IndexSearchRepository {
+	return &IndexSearchRepository{db: db}
+}
+
+// Compile-time check
+var _ SearchRepositoryInterface = (*IndexSearchRepository)(nil)
+
+func (r *IndexSearchRepository) Search(q SearchQuery) ([]SearchHit, int64, error) {
+	query := r.db.Model(&task.Task{}).
+		Preload("Tags").
+		Where("tasks.user_id = ?", q.UserID)
+	if q.Status != "" {
+		query = query.Where("tasks.status = ?", q.Status)
+	}
+	if q.Filter != nil {
+		query = q.Filter(query)
+	}
+
+	var tasks []task.Task
+	if err := query.Find(&tasks).Error; err != nil {
+		return nil, 0, err
+	}
+	if len(tasks) == 0 {
+		return []SearchHit{}, 0, nil
+	}
+
+	byID := make(map[int]task.Task, len(tasks))
+	ids := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+		ids = append(ids, t.ID)
+	}
+
+	var comments []comment.Comment
+	if err := r.db.Where("task_id IN ?", ids).Order("id ASC").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+	commentsByTask := make(map[int][]string)
+	for _, c := range comments {
+		commentsByTask[c.TaskID] = append(commentsByTask[c.TaskID], c.Body)
+	}
+
+	ix := textindex.New()
+	for _, t := range tasks {
+		ix.Add(t.ID, t.Task, titleBoost)
+		ix.Add(t.ID, t.Description, descriptionBoost)
+		for _, body := range commentsByTask[t.ID] {
+			ix.Add(t.ID, body, commentBoost)
+		}
+	}
+
+	results := ix.Search(q.Text)
+	total := int64(len(results))
+
+	if q.Offset >= len(results) {
+		return []SearchHit{}, total, nil
+	}
+	results = results[q.Offset:]
+	if q.Limit > 0 && len(results) > q.Limit {
+		results = results[:q.Limit]
+	}
+
+	hits := make([]SearchHit, 0, len(results))
+	for _, res := range results {
+		hits = append(hits, buildHit(byID[res.ID], commentsByTask[res.ID], q.Text, res.Score))
+	}
+	return hits, total, nil
+}
@@ -0,0 +1,53 @@
+package gorm_search
+
+import (
+	"taskflow/internal/domain/task"
+	"taskflow/pkg/textindex"
+
+	"gorm.io/gorm"
+)
+
+const snippetWidth = 160
+
+// NewSearchRepository picks the MySQL FULLTEXT implementation when running on
+// MySQL and the in-process index everywhere else (e.g. SQLite in tests).
+func NewSearchRepository(db *gorm.DB) SearchRepositoryInterface {
+	if db.Dialector.Name() == "mysql" {
+		return NewFullTextSearchRepository(db)
+	}
+	return NewIndexSearchRepository(db)
+}
+
+// buildHit records where the query matched and picks an excerpt, preferring
+// the description or a comment over the title, which clients show anyway.
+func buildHit(t task.Task, comments []string, text string, score float64) SearchHit {
+	hit := SearchHit{Task: t, Score: score}
+
+	title := textindex.Highlight(t.Task, text, snippetWidth)
+	if title != "" {
+		hit.MatchedIn = append(hit.MatchedIn, "task")
+	}
+
+	if snippet := textindex.Highlight(t.Description, text, snippetWidth); snippet != "" {
+		hit.MatchedIn = append(hit.MatchedIn, "description")
+		hit.Snippet = snippet
+	}
+
+	for _, body := range comments {
+		snippet := textindex.Highlight(body, text, snippetWidth)
+		if snippet == "" {
+			continue
+		}
+		hit.MatchedIn = append(hit.MatchedIn, "comment")
+		if hit.Snippet == "" {
+			hit.Snippet = snippet
+		}
+		break
+	}
+
+	if hit.Snippet == "" {
+		hit.Snippet = title
+	}
+
+	return hit
+}
@@ -0,0 +1,30 @@
+package gorm_search
+
+import (
+	"taskflow/internal/domain/task"
+	"taskflow/internal/taskquery"
+)
+
+// SearchQuery describes a relevance search over one user's tasks.
+type SearchQuery struct {
+	UserID int
+	Text   string
+	Status string
+	// Filter, when set, narrows the tasks searched, as GET /tasks?q= does.
+	Filter taskquery.Scope
+	Offset int
+	Limit  int
+}
+
+// SearchHit is a matching task with its relevance score and a highlighted excerpt.
+type SearchHit struct {
+	Task      task.Task
+	Score     float64
+	Snippet   string
+	MatchedIn []string
+}
+
+type SearchRepositoryInterface interface {
+	// Search returns a page of hits, best first, and the total number of hits.
+	Search(q SearchQuery) ([]SearchHit, int64, error)
+}
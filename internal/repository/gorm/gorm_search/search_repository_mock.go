@@ -0,0 +1,14 @@
+package gorm_search
+
+import "github.com/stretchr/testify/mock"
+
+type SearchRepoMock struct {
+	mock.Mock
+}
+
+var _ SearchRepositoryInterface = (*SearchRepoMock)(nil)
+
+func (m *SearchRepoMock) Search(q SearchQuery) ([]SearchHit, int64, error) {
+	args := m.Called(q)
+	return args.Get(0).([]SearchHit), args.Get(1).(int64), args.Error(2)
+}
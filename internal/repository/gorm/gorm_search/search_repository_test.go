@@ -0,0 +1,115 @@
+package gorm_search
+
+import (
+	"taskflow/internal/domain/comment"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/taskquery"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&task.Task{}, &task.Tag{}, &comment.Comment{}, &comment.Mention{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func seed(t *testing.T, db *gorm.DB) {
+	tasks := []task.Task{
+		{ID: 1, UserID: 1, Task: "Buy milk", Description: "semi-skimmed", Status: "pending", Priority: task.PriorityHigh,
+			Tags: []task.Tag{{Name: "home"}}},
+		{ID: 2, UserID: 1, Task: "Quarterly report", Description: "include milk price trends", Status: "completed"},
+		{ID: 3, UserID: 1, Task: "Call plumber", Status: "pending"},
+		{ID: 4, UserID: 2, Task: "Buy milk", Status: "pending"}, // other user
+	}
+	for i := range tasks {
+		require.NoError(t, db.Create(&tasks[i]).Error)
+	}
+
+	require.NoError(t, db.Create(&comment.Comment{TaskID: 3, UserID: 1, Body: "he asked about the milk pipes"}).Error)
+	deleted := comment.Comment{TaskID: 3, UserID: 1, Body: "plumber invoice"}
+	require.NoError(t, db.Create(&deleted).Error)
+	require.NoError(t, db.Delete(&deleted).Error)
+}
+
+func TestNewSearchRepository_PicksFallbackOnSQLite(t *testing.T) {
+	db := setupTestDB(t)
+	_, ok := NewSearchRepository(db).(*IndexSearchRepository)
+	assert.True(t, ok)
+}
+
+func TestIndexSearchRepository_Search(t *testing.T) {
+	db := setupTestDB(t)
+	seed(t, db)
+	r := NewIndexSearchRepository(db)
+
+	t.Run("ranks title over description over comments", func(t *testing.T) {
+		hits, total, err := r.Search(SearchQuery{UserID: 1, Text: "milk", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, hits, 3)
+
+		assert.Equal(t, 1, hits[0].Task.ID)
+		assert.Equal(t, task.PriorityHigh, hits[0].Task.Priority)
+		assert.Equal(t, []task.Tag{{TaskID: 1, Name: "home"}}, hits[0].Task.Tags)
+		assert.Equal(t, []string{"task"}, hits[0].MatchedIn)
+		assert.Equal(t, "Buy <mark>milk</mark>", hits[0].Snippet)
+
+		assert.Equal(t, 2, hits[1].Task.ID)
+		assert.Equal(t, "include <mark>milk</mark> price trends", hits[1].Snippet)
+
+		assert.Equal(t, 3, hits[2].Task.ID)
+		assert.Equal(t, []string{"comment"}, hits[2].MatchedIn)
+	})
+
+	t.Run("status filter", func(t *testing.T) {
+		hits, total, err := r.Search(SearchQuery{UserID: 1, Text: "milk", Status: "completed", Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, 2, hits[0].Task.ID)
+	})
+
+	t.Run("query filter", func(t *testing.T) {
+		node, err := taskquery.Parse("tag:home OR priority>=urgent")
+		require.NoError(t, err)
+		scope, err := taskquery.Compile(node, time.Now())
+		require.NoError(t, err)
+
+		hits, total, err := r.Search(SearchQuery{UserID: 1, Text: "milk", Filter: scope, Limit: 10})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, 1, hits[0].Task.ID)
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		hits, total, err := r.Search(SearchQuery{UserID: 1, Text: "milk", Offset: 2, Limit: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), total)
+		require.Len(t, hits, 1)
+		assert.Equal(t, 3, hits[0].Task.ID)
+	})
+
+	t.Run("soft-deleted comments are ignored", func(t *testing.T) {
+		hits, _, err := r.Search(SearchQuery{UserID: 1, Text: "invoice", Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+
+	t.Run("other users' tasks are not searched", func(t *testing.T) {
+		hits, _, err := r.Search(SearchQuery{UserID: 2, Text: "report", Limit: 10})
+		require.NoError(t, err)
+		assert.Empty(t, hits)
+	})
+}
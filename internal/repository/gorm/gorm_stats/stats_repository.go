@@ -0,0 +1,152 @@
+package gorm_stats
+
+import (
+	"fmt"
+	"taskflow/internal/domain/task"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BucketSize is the width of the UTC buckets tasks are counted in. Every
+// real-world UTC offset is a multiple of it, so callers can fold buckets
+// into days of any time zone, DST changes included.
+const BucketSize = 15 * time.Minute
+
+// completedAt falls back to updated_at for tasks completed before
+// completion times were recorded.
+const completedAt = "COALESCE(completed_at, updated_at)"
+
+// Bucket counts the tasks whose timestamp falls in [Start, Start+BucketSize).
+type Bucket struct {
+	Start time.Time
+	Count int64
+}
+
+// CompletionSummary describes the tasks completed in a period.
+type CompletionSummary struct {
+	Count int64
+	// AvgSeconds is the mean time from creation to completion.
+	AvgSeconds float64
+	// Late counts tasks completed after their due date.
+	Late int64
+}
+
+// StatsRepository is a read model that answers statistics with aggregate
+// queries instead of loading tasks. Trashed tasks are left out.
+type StatsRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRepository(db *gorm.DB) *StatsRepository {
+	return &StatsRepository{db: db}
+}
+
+// Compile-time check
+var _ StatsRepositoryInterface = (*StatsRepository)(nil)
+
+// Created counts the user's tasks created in [from, to) per bucket.
+func (r *StatsRepository) Created(userID int, from time.Time, to time.Time) ([]Bucket, error) {
+	return r.buckets(r.db.Model(&task.Task{}).Where("user_id = ?", userID), "created_at", from, to)
+}
+
+// Completed counts the user's tasks completed in [from, to) per bucket.
+func (r *StatsRepository) Completed(userID int, from time.Time, to time.Time) ([]Bucket, error) {
+	q := r.db.Model(&task.Task{}).Where("user_id = ? AND status = ?", userID, task.StatusCompleted)
+	return r.buckets(q, completedAt, from, to)
+}
+
+func (r *StatsRepository) buckets(q *gorm.DB, column string, from time.Time, to time.Time) ([]Bucket, error) {
+	size := int64(BucketSize / time.Second)
+	bucket := fmt.Sprintf("%s / %d", r.epoch(column), size)
+	if r.db.Dialector.Name() == "mysql" {
+		bucket = fmt.Sprintf("%s DIV %d", r.epoch(column), size)
+	}
+
+	var rows []struct {
+		Bucket int64
+		Count  int64
+	}
+	err := q.Select(bucket+" AS bucket, COUNT(*) AS count").
+		Where(column+" >= ? AND "+column+" < ?", from, to).
+		Group("bucket").
+		Order("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make([]Bucket, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, Bucket{Start: time.Unix(row.Bucket*size, 0).UTC(), Count: row.Count})
+	}
+	return buckets, nil
+}
+
+// Completion summarises the user's tasks completed in [from, to).
+func (r *StatsRepository) Completion(userID int, from time.Time, to time.Time) (CompletionSummary, error) {
+	var row struct {
+		Count      int64
+		AvgSeconds *float64
+		Late       *int64
+	}
+	err := r.db.Model(&task.Task{}).
+		Select(fmt.Sprintf(
+			"COUNT(*) AS count, AVG(%s - %s) AS avg_seconds, SUM(CASE WHEN due_at IS NOT NULL AND %s > due_at THEN 1 ELSE 0 END) AS late",
+			r.epoch(completedAt), r.epoch("created_at"), completedAt,
+		)).
+		Where("user_id = ? AND status = ?", userID, task.StatusCompleted).
+		Where(completedAt+" >= ? AND "+completedAt+" < ?", from, to).
+		Scan(&row).Error
+	if err != nil {
+		return CompletionSummary{}, err
+	}
+
+	summary := CompletionSummary{Count: row.Count}
+	if row.AvgSeconds != nil {
+		summary.AvgSeconds = *row.AvgSeconds
+	}
+	if row.Late != nil {
+		summary.Late = *row.Late
+	}
+	return summary, nil
+}
+
+// StatusCounts returns how many of the user's tasks are in each status.
+func (r *StatsRepository) StatusCounts(userID int) (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	err := r.db.Model(&task.Task{}).
+		Select("status, COUNT(*) AS count").
+		Where("user_id = ?", userID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// Overdue counts the user's open tasks that were due before now.
+func (r *StatsRepository) Overdue(userID int, now time.Time) (int64, error) {
+	var n int64
+	err := r.db.Model(&task.Task{}).
+		Where("user_id = ? AND status <> ? AND due_at < ?", userID, task.StatusCompleted, now).
+		Count(&n).Error
+	return n, err
+}
+
+// epoch returns an SQL expression for column as Unix seconds.
+func (r *StatsRepository) epoch(column string) string {
+	if r.db.Dialector.Name() == "mysql" {
+		return "UNIX_TIMESTAMP(" + column + ")"
+	}
+	return "CAST(strftime('%s', " + column + ") AS INTEGER)"
+}
@@ -0,0 +1,11 @@
+package gorm_stats
+
+import "time"
+
+type StatsRepositoryInterface interface {
+	Created(userID int, from time.Time, to time.Time) ([]Bucket, error)
+	Completed(userID int, from time.Time, to time.Time) ([]Bucket, error)
+	Completion(userID int, from time.Time, to time.Time) (CompletionSummary, error)
+	StatusCounts(userID int) (map[string]int64, error)
+	Overdue(userID int, now time.Time) (int64, error)
+}
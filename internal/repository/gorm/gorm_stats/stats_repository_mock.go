@@ -0,0 +1,38 @@
+package gorm_stats
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StatsRepoMock struct {
+	mock.Mock
+}
+
+var _ StatsRepositoryInterface = (*StatsRepoMock)(nil)
+
+func (m *StatsRepoMock) Created(userID int, from time.Time, to time.Time) ([]Bucket, error) {
+	args := m.Called(userID, from, to)
+	return args.Get(0).([]Bucket), args.Error(1)
+}
+
+func (m *StatsRepoMock) Completed(userID int, from time.Time, to time.Time) ([]Bucket, error) {
+	args := m.Called(userID, from, to)
+	return args.Get(0).([]Bucket), args.Error(1)
+}
+
+func (m *StatsRepoMock) Completion(userID int, from time.Time, to time.Time) (CompletionSummary, error) {
+	args := m.Called(userID, from, to)
+	return args.Get(0).(CompletionSummary), args.Error(1)
+}
+
+func (m *StatsRepoMock) StatusCounts(userID int) (map[string]int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *StatsRepoMock) Overdue(userID int, now time.Time) (int64, error) {
+	args := m.Called(userID, now)
+	return args.Get(0).(int64), args.Error(1)
+}
@@ -0,0 +1,110 @@
+package gorm_stats
+
+import (
+	"taskflow/internal/domain/task"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&task.Task{}, &task.Tag{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func at(day, hour, minute int) time.Time {
+	return time.Date(2025, 9, day, hour, minute, 0, 0, time.UTC)
+}
+
+func seed(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	due := at(2, 12, 0)
+	completed := func(tm time.Time) *time.Time { return &tm }
+	tasks := []task.Task{
+		{Task: "a", UserID: 1, Status: "completed", CreatedAt: at(1, 9, 5), CompletedAt: completed(at(1, 11, 5))},
+		{Task: "b", UserID: 1, Status: "completed", CreatedAt: at(1, 9, 10), CompletedAt: completed(at(3, 9, 10)), DueAt: &due},
+		{Task: "c", UserID: 1, Status: "pending", CreatedAt: at(2, 23, 50), DueAt: &due},
+		{Task: "d", UserID: 1, Status: "in-progress", CreatedAt: at(9, 8, 0)},
+		{Task: "other user", UserID: 2, Status: "completed", CreatedAt: at(1, 9, 0), CompletedAt: completed(at(1, 10, 0))},
+		{Task: "trashed", UserID: 1, Status: "pending", CreatedAt: at(1, 9, 0), DueAt: &due},
+	}
+	for i := range tasks {
+		require.NoError(t, db.Create(&tasks[i]).Error)
+	}
+	require.NoError(t, db.Delete(&tasks[5]).Error)
+
+	// Completed before completion times were recorded
+	legacy := task.Task{Task: "legacy", UserID: 1, Status: "completed", CreatedAt: at(2, 8, 0)}
+	require.NoError(t, db.Create(&legacy).Error)
+	require.NoError(t, db.Model(&legacy).UpdateColumn("updated_at", at(2, 10, 0)).Error)
+}
+
+func TestStatsRepository_Buckets(t *testing.T) {
+	db := setupTestDB(t)
+	seed(t, db)
+	r := NewStatsRepository(db)
+
+	created, err := r.Created(1, at(1, 0, 0), at(3, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []Bucket{
+		{Start: at(1, 9, 0), Count: 2},
+		{Start: at(2, 8, 0), Count: 1},
+		{Start: at(2, 23, 45), Count: 1},
+	}, created)
+
+	completed, err := r.Completed(1, at(1, 0, 0), at(4, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []Bucket{
+		{Start: at(1, 11, 0), Count: 1},
+		{Start: at(2, 10, 0), Count: 1},
+		{Start: at(3, 9, 0), Count: 1},
+	}, completed)
+}
+
+func TestStatsRepository_Completion(t *testing.T) {
+	db := setupTestDB(t)
+	seed(t, db)
+	r := NewStatsRepository(db)
+
+	got, err := r.Completion(1, at(1, 0, 0), at(4, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.Count)
+	// 2h, 48h and 2h
+	assert.InDelta(t, float64(52*3600)/3, got.AvgSeconds, 1)
+	assert.Equal(t, int64(1), got.Late)
+
+	empty, err := r.Completion(1, at(20, 0, 0), at(21, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, CompletionSummary{}, empty)
+}
+
+func TestStatsRepository_Current(t *testing.T) {
+	db := setupTestDB(t)
+	seed(t, db)
+	r := NewStatsRepository(db)
+
+	counts, err := r.StatusCounts(1)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"completed": 3, "pending": 1, "in-progress": 1}, counts)
+
+	overdue, err := r.Overdue(1, at(5, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), overdue)
+
+	overdue, err = r.Overdue(1, at(1, 0, 0))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), overdue)
+}
@@ -1,9 +1,13 @@
 package gorm_task
 
 import (
+	"taskflow/internal/domain/board"
 	"taskflow/internal/domain/task"
+	"taskflow/internal/events"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type TaskRepository struct {
@@ -23,7 +27,7 @@ func (r *TaskRepository) Create(t *task.Task) error {
 
 func (r *TaskRepository) GetByID(userID int, id int) (*task.Task, error) {
 	var t task.Task
-	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&t).Error
+	err := r.db.Preload("Tags").Where("id = ? AND user_id = ?", id, userID).First(&t).Error
 	if err != nil {
 		return nil, err
 	}
@@ -32,16 +36,48 @@ func (r *TaskRepository) GetByID(userID int, id int) (*task.Task, error) {
 
 func (r *TaskRepository) List(userID int) ([]task.Task, error) {
 	var tasks []task.Task
-	if err := r.db.Where("user_id = ?", userID).Find(&tasks).Error; err != nil {
+	if err := r.db.Preload("Tags").Where("user_id = ?", userID).Order("position, id").Find(&tasks).Error; err != nil {
 		return nil, err
 	}
 	return tasks, nil
 }
 
+// Find lists the user's tasks narrowed by the given scopes, e.g. a compiled
+// task query. Scopes must qualify columns with the tasks table name.
+func (r *TaskRepository) Find(userID int, scopes ...func(*gorm.DB) *gorm.DB) ([]task.Task, error) {
+	query := r.db.Model(&task.Task{}).
+		Preload("Tags").
+		Where("tasks.user_id = ?", userID)
+	// Apply scopes now rather than via Scopes(), which runs them at execution
+	// time and would put their ORDER BY after the id tie-breaker.
+	for _, scope := range scopes {
+		query = scope(query)
+	}
+
+	var tasks []task.Task
+	err := query.Order("tasks.id").Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Count returns how many of the user's tasks match the given scopes.
+func (r *TaskRepository) Count(userID int, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	var count int64
+	err := r.db.Model(&task.Task{}).
+		Where("tasks.user_id = ?", userID).
+		Scopes(scopes...).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *TaskRepository) Update(t *task.Task) error {
 	return r.db.Save(t).Error
 }
 
+// Delete moves a task to the trash, like DeleteBatch. Use Purge to remove
+// it for good.
 func (r *TaskRepository) Delete(userID int, id int) error {
 	return r.db.
 		Where("user_id = ?", userID).
@@ -49,5 +85,313 @@ func (r *TaskRepository) Delete(userID int, id int) error {
 }
 
 func (r *TaskRepository) UpdateStatus(userID int, id int, status string) error {
-	return r.db.Model(&task.Task{}).Where("user_id = ? AND id = ?", userID, id).Update("status", status).Error
+	_, err := r.UpdateStatusBatch(userID, []int{id}, status)
+	return err
+}
+
+// Transaction runs fn with a repository bound to a single database transaction.
+func (r *TaskRepository) Transaction(fn func(repo TaskRepositoryInterface) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&TaskRepository{db: tx})
+	})
+}
+
+func (r *TaskRepository) AddEvents(evs ...events.Event) error {
+	return events.NewOutbox(r.db).Append(evs...)
+}
+
+// Lookup returns the given tasks with their tags, regardless of owner and
+// including trashed tasks, so callers can tell missing tasks from someone
+// else's.
+func (r *TaskRepository) Lookup(ids []int) ([]task.Task, error) {
+	var tasks []task.Task
+	err := r.db.Unscoped().
+		Preload("Tags").
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// UpdateStatusBatch also stamps completed_at when tasks are completed, leaving
+// it alone on tasks that already were, and clears it when they are reopened.
+func (r *TaskRepository) UpdateStatusBatch(userID int, ids []int, status string) (int64, error) {
+	var completedAt any
+	if status == task.StatusCompleted {
+		completedAt = gorm.Expr("COALESCE(completed_at, ?)", time.Now())
+	}
+	res := r.db.Model(&task.Task{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Updates(map[string]any{"status": status, "completed_at": completedAt})
+	return res.RowsAffected, res.Error
+}
+
+// MoveBatch puts tasks in a project, or back in the inbox when projectID is
+// nil, giving ids[i] the list position positions[i].
+func (r *TaskRepository) MoveBatch(userID int, ids []int, projectID *int, positions []string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	position := "CASE id"
+	args := make([]any, 0, 2*len(ids))
+	for i, id := range ids {
+		position += " WHEN ? THEN ?"
+		args = append(args, id, positions[i])
+	}
+	res := r.db.Model(&task.Task{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Updates(map[string]any{"project_id": projectID, "position": gorm.Expr(position+" END", args...)})
+	return res.RowsAffected, res.Error
+}
+
+// DeleteBatch moves tasks to the trash. They can be brought back with RestoreBatch.
+func (r *TaskRepository) DeleteBatch(userID int, ids []int) (int64, error) {
+	res := r.db.
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Delete(&task.Task{})
+	return res.RowsAffected, res.Error
+}
+
+// TrashedIDs returns the ids of the user's tasks moved to the trash before
+// the given time. The rows are locked, so a restore waits for the purge
+// they are read for.
+func (r *TaskRepository) TrashedIDs(userID int, before time.Time) ([]int, error) {
+	var ids []int
+	err := r.db.Unscoped().Model(&task.Task{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND deleted_at IS NOT NULL AND deleted_at < ?", userID, before).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// TrashOwners returns the users with tasks moved to the trash before the
+// given time.
+func (r *TaskRepository) TrashOwners(before time.Time) ([]int, error) {
+	var ids []int
+	err := r.db.Unscoped().Model(&task.Task{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Distinct().
+		Order("user_id").
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// Purge permanently removes tasks that are in the trash; the database
+// removes their tags, comments, attachment records and time entries with
+// them. Subtasks of purged tasks become top-level tasks. Tasks that are not
+// in the trash are left alone.
+func (r *TaskRepository) Purge(userID int, ids []int) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	err := r.db.Unscoped().Model(&task.Task{}).
+		Where("user_id = ? AND parent_id IN ?", userID, ids).
+		Update("parent_id", nil).Error
+	if err != nil {
+		return 0, err
+	}
+	res := r.db.Unscoped().
+		Where("user_id = ? AND id IN ? AND deleted_at IS NOT NULL", userID, ids).
+		Delete(&task.Task{})
+	return res.RowsAffected, res.Error
+}
+
+func (r *TaskRepository) RestoreBatch(userID int, ids []int) (int64, error) {
+	res := r.db.Unscoped().Model(&task.Task{}).
+		Where("user_id = ? AND id IN ? AND deleted_at IS NOT NULL", userID, ids).
+		Update("deleted_at", nil)
+	return res.RowsAffected, res.Error
+}
+
+// ListScope returns the id and position of every task in one ordered list:
+// a project, or the inbox when projectID is nil. Tasks come back in list order.
+func (r *TaskRepository) ListScope(userID int, projectID *int) ([]task.Task, error) {
+	var tasks []task.Task
+	err := r.db.
+		Select("id", "project_id", "position").
+		Scopes(inScope(userID, projectID)).
+		Order("position, id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// LastPosition returns the highest position in a list, or "" when it is empty.
+func (r *TaskRepository) LastPosition(userID int, projectID *int) (string, error) {
+	var positions []string
+	err := r.db.Model(&task.Task{}).
+		Scopes(inScope(userID, projectID)).
+		Order("position DESC").
+		Limit(1).
+		Pluck("position", &positions).Error
+	if err != nil || len(positions) == 0 {
+		return "", err
+	}
+	return positions[0], nil
+}
+
+// SetPosition places a task at position in the given list.
+func (r *TaskRepository) SetPosition(userID int, id int, projectID *int, position string) error {
+	return r.db.Model(&task.Task{}).
+		Where("user_id = ? AND id = ?", userID, id).
+		Updates(map[string]any{"project_id": projectID, "position": position}).Error
+}
+
+// ListColumn returns one page of a board column: the tasks of a list with
+// the given status, in list order.
+func (r *TaskRepository) ListColumn(userID int, projectID *int, status string, offset int, limit int) ([]task.Task, error) {
+	var tasks []task.Task
+	err := r.db.Preload("Tags").
+		Scopes(inScope(userID, projectID)).
+		Where("status = ?", status).
+		Order("position, id").
+		Offset(offset).
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// CountColumn returns how many tasks of a list have the given status.
+func (r *TaskRepository) CountColumn(userID int, projectID *int, status string) (int64, error) {
+	var count int64
+	err := r.db.Model(&task.Task{}).
+		Scopes(inScope(userID, projectID)).
+		Where("status = ?", status).
+		Count(&count).Error
+	return count, err
+}
+
+// LockColumn locks the settings row of a board column until the transaction
+// ends and returns the column's WIP limit, 0 when it has none. Concurrent
+// transactions checking the same column's limit therefore run one after
+// the other. Columns without a row have no limit, and nothing is locked.
+func (r *TaskRepository) LockColumn(userID int, projectID *int, status string) (int, error) {
+	var limits []int
+	err := r.db.Model(&board.Column{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scopes(inScope(userID, projectID)).
+		Where("status = ?", status).
+		Limit(1).
+		Pluck("wip_limit", &limits).Error
+	if err != nil || len(limits) == 0 {
+		return 0, err
+	}
+	return limits[0], nil
+}
+
+// SetEstimate sets or, with nil, clears a task's effort estimate.
+func (r *TaskRepository) SetEstimate(userID int, id int, minutes *int) error {
+	return r.db.Model(&task.Task{}).
+		Where("user_id = ? AND id = ?", userID, id).
+		Update("estimate_minutes", minutes).Error
+}
+
+// DueBetween lists open tasks of every user that are due after from and no
+// later than to, soonest first.
+func (r *TaskRepository) DueBetween(from, to time.Time) ([]task.Task, error) {
+	var tasks []task.Task
+	err := r.db.
+		Where("due_at > ? AND due_at <= ? AND status <> ?", from, to, task.StatusCompleted).
+		Order("due_at, id").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Batches passes the user's tasks to fn in batches of up to size tasks,
+// ordered by ID, so large lists can be streamed without loading them at
+// once. An error from fn stops the iteration and is returned.
+func (r *TaskRepository) Batches(userID int, size int, fn func(tasks []task.Task) error) error {
+	var tasks []task.Task
+	return r.db.Preload("Tags").
+		Where("user_id = ?", userID).
+		FindInBatches(&tasks, size, func(tx *gorm.DB, batch int) error {
+			return fn(tasks)
+		}).Error
+}
+
+// ExternalIDs maps the external IDs starting with prefix of the user's
+// tasks, including trashed ones, to the tasks' IDs.
+func (r *TaskRepository) ExternalIDs(userID int, prefix string) (map[string]int, error) {
+	var tasks []task.Task
+	err := r.db.Unscoped().
+		Select("id", "external_id").
+		Where("user_id = ? AND external_id LIKE ? ESCAPE '!'", userID, escapeLike(prefix)+"%").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]int, len(tasks))
+	for _, t := range tasks {
+		ids[*t.ExternalID] = t.ID
+	}
+	return ids, nil
+}
+
+func inScope(userID int, projectID *int) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ?", userID)
+		if projectID == nil {
+			return db.Where("project_id IS NULL")
+		}
+		return db.Where("project_id = ?", *projectID)
+	}
+}
+
+// AddTagsBatch adds every tag to each of the user's tasks, skipping tags a
+// task already has, and marks the tasks updated.
+func (r *TaskRepository) AddTagsBatch(userID int, ids []int, names []string) error {
+	if len(ids) == 0 || len(names) == 0 {
+		return nil
+	}
+
+	var owned []int
+	err := r.db.Model(&task.Task{}).
+		Where("user_id = ? AND id IN ?", userID, ids).
+		Order("id").
+		Pluck("id", &owned).Error
+	if err != nil || len(owned) == 0 {
+		return err
+	}
+
+	if err := r.insertTags(owned, names); err != nil {
+		return err
+	}
+	return r.db.Model(&task.Task{}).
+		Where("user_id = ? AND id IN ?", userID, owned).
+		Update("updated_at", time.Now()).Error
+}
+
+// insertTags adds every tag to every task, skipping tags a task already has.
+func (r *TaskRepository) insertTags(ids []int, names []string) error {
+	if len(ids) == 0 || len(names) == 0 {
+		return nil
+	}
+	tags := make([]task.Tag, 0, len(ids)*len(names))
+	for _, id := range ids {
+		for _, name := range names {
+			tags = append(tags, task.Tag{TaskID: id, Name: name})
+		}
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error
+}
+
+// SetTags replaces the tags of a task. The caller must have checked that
+// the task belongs to the user.
+func (r *TaskRepository) SetTags(id int, names []string) error {
+	if err := r.db.Where("task_id = ?", id).Delete(&task.Tag{}).Error; err != nil {
+		return err
+	}
+	return r.insertTags([]int{id}, names)
 }
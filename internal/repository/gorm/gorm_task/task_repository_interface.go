@@ -2,13 +2,45 @@ package gorm_task
 
 import (
 	"taskflow/internal/domain/task"
+	"taskflow/internal/events"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type TaskRepositoryInterface interface {
 	Create(task *task.Task) error
 	GetByID(userID int, id int) (*task.Task, error)
 	List(userID int) ([]task.Task, error)
+	Find(userID int, scopes ...func(*gorm.DB) *gorm.DB) ([]task.Task, error)
+	Count(userID int, scopes ...func(*gorm.DB) *gorm.DB) (int64, error)
 	Update(task *task.Task) error
 	Delete(userID int, id int) error
 	UpdateStatus(userID int, id int, status string) error
+
+	Transaction(fn func(repo TaskRepositoryInterface) error) error
+	// AddEvents records domain events in the outbox, within the
+	// repository's transaction when called from Transaction.
+	AddEvents(evs ...events.Event) error
+	Lookup(ids []int) ([]task.Task, error)
+	UpdateStatusBatch(userID int, ids []int, status string) (int64, error)
+	MoveBatch(userID int, ids []int, projectID *int, positions []string) (int64, error)
+	DeleteBatch(userID int, ids []int) (int64, error)
+	RestoreBatch(userID int, ids []int) (int64, error)
+	TrashedIDs(userID int, before time.Time) ([]int, error)
+	TrashOwners(before time.Time) ([]int, error)
+	Purge(userID int, ids []int) (int64, error)
+	AddTagsBatch(userID int, ids []int, names []string) error
+	SetTags(id int, names []string) error
+
+	ListScope(userID int, projectID *int) ([]task.Task, error)
+	LastPosition(userID int, projectID *int) (string, error)
+	SetPosition(userID int, id int, projectID *int, position string) error
+	ListColumn(userID int, projectID *int, status string, offset int, limit int) ([]task.Task, error)
+	CountColumn(userID int, projectID *int, status string) (int64, error)
+	LockColumn(userID int, projectID *int, status string) (int, error)
+	SetEstimate(userID int, id int, minutes *int) error
+	DueBetween(from, to time.Time) ([]task.Task, error)
+	Batches(userID int, size int, fn func(tasks []task.Task) error) error
+	ExternalIDs(userID int, prefix string) (map[string]int, error)
 }
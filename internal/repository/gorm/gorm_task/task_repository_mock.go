@@ -2,8 +2,11 @@ package gorm_task
 
 import (
 	"taskflow/internal/domain/task"
+	"taskflow/internal/events"
+	"time"
 
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 type TaskRepoMock struct {
@@ -32,6 +35,16 @@ func (m *TaskRepoMock) List(userID int) ([]task.Task, error) {
 	return args.Get(0).([]task.Task), args.Error(1)
 }
 
+func (m *TaskRepoMock) Find(userID int, scopes ...func(*gorm.DB) *gorm.DB) ([]task.Task, error) {
+	args := m.Called(userID, scopes)
+	return args.Get(0).([]task.Task), args.Error(1)
+}
+
+func (m *TaskRepoMock) Count(userID int, scopes ...func(*gorm.DB) *gorm.DB) (int64, error) {
+	args := m.Called(userID, scopes)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *TaskRepoMock) Update(task *task.Task) error {
 	args := m.Called(task)
 	return args.Error(0)
@@ -48,3 +61,120 @@ func (m *TaskRepoMock) UpdateStatus(userID int, id int, status string) error {
 	args := m.Called(userID, id, status)
 	return args.Error(0)
 }
+
+// Transaction runs fn against the mock itself.
+func (m *TaskRepoMock) Transaction(fn func(repo TaskRepositoryInterface) error) error {
+	m.Called(fn)
+	return fn(m)
+}
+
+func (m *TaskRepoMock) AddEvents(evs ...events.Event) error {
+	args := m.Called(evs)
+	return args.Error(0)
+}
+
+func (m *TaskRepoMock) Lookup(ids []int) ([]task.Task, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]task.Task), args.Error(1)
+}
+
+func (m *TaskRepoMock) UpdateStatusBatch(userID int, ids []int, status string) (int64, error) {
+	args := m.Called(userID, ids, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *TaskRepoMock) MoveBatch(userID int, ids []int, projectID *int, positions []string) (int64, error) {
+	args := m.Called(userID, ids, projectID, positions)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *TaskRepoMock) DeleteBatch(userID int, ids []int) (int64, error) {
+	args := m.Called(userID, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *TaskRepoMock) RestoreBatch(userID int, ids []int) (int64, error) {
+	args := m.Called(userID, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *TaskRepoMock) TrashedIDs(userID int, before time.Time) ([]int, error) {
+	args := m.Called(userID, before)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *TaskRepoMock) TrashOwners(before time.Time) ([]int, error) {
+	args := m.Called(before)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *TaskRepoMock) Purge(userID int, ids []int) (int64, error) {
+	args := m.Called(userID, ids)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *TaskRepoMock) AddTagsBatch(userID int, ids []int, names []string) error {
+	args := m.Called(userID, ids, names)
+	return args.Error(0)
+}
+
+func (m *TaskRepoMock) SetTags(id int, names []string) error {
+	args := m.Called(id, names)
+	return args.Error(0)
+}
+
+func (m *TaskRepoMock) ListScope(userID int, projectID *int) ([]task.Task, error) {
+	args := m.Called(userID, projectID)
+	return args.Get(0).([]task.Task), args.Error(1)
+}
+
+func (m *TaskRepoMock) LastPosition(userID int, projectID *int) (string, error) {
+	args := m.Called(userID, projectID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *TaskRepoMock) SetPosition(userID int, id int, projectID *int, position string) error {
+	args := m.Called(userID, id, projectID, position)
+	return args.Error(0)
+}
+
+func (m *TaskRepoMock) ListColumn(userID int, projectID *int, status string, offset int, limit int) ([]task.Task, error) {
+	args := m.Called(userID, projectID, status, offset, limit)
+	return args.Get(0).([]task.Task), args.Error(1)
+}
+
+func (m *TaskRepoMock) CountColumn(userID int, projectID *int, status string) (int64, error) {
+	args := m.Called(userID, projectID, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *TaskRepoMock) LockColumn(userID int, projectID *int, status string) (int, error) {
+	args := m.Called(userID, projectID, status)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *TaskRepoMock) SetEstimate(userID int, id int, minutes *int) error {
+	args := m.Called(userID, id, minutes)
+	return args.Error(0)
+}
+
+func (m *TaskRepoMock) DueBetween(from, to time.Time) ([]task.Task, error) {
+	args := m.Called(from, to)
+	return args.Get(0).([]task.Task), args.Error(1)
+}
+
+// Batches passes each batch returned by the expectation to fn.
+func (m *TaskRepoMock) Batches(userID int, size int, fn func(tasks []task.Task) error) error {
+	args := m.Called(userID, size)
+	for _, batch := range args.Get(0).([][]task.Task) {
+		if err := fn(batch); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *TaskRepoMock) ExternalIDs(userID int, prefix string) (map[string]int, error) {
+	args := m.Called(userID, prefix)
+	return args.Get(0).(map[string]int), args.Error(1)
+}
@@ -2,8 +2,13 @@ package gorm_task
 
 import (
 	"errors"
+	"fmt"
+	"taskflow/internal/domain/board"
+	"taskflow/internal/domain/filter"
 	"taskflow/internal/domain/task"
+	"taskflow/internal/events"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -18,7 +23,7 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	})
 	require.NoError(t, err)
 
-	err = db.AutoMigrate(&task.Task{})
+	err = db.AutoMigrate(&task.Task{}, &task.Tag{})
 	require.NoError(t, err)
 
 	return db
@@ -194,6 +199,23 @@ func TestTaskRepository_Delete(t *testing.T) {
 		err = db.First(&fetched, taskToCreate.ID).Error
 		assert.Error(t, err)
 		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		// The task is in the trash, not gone.
+		require.NoError(t, db.Unscoped().First(&fetched, taskToCreate.ID).Error)
+		assert.True(t, fetched.DeletedAt.Valid)
+		restored, err := r.RestoreBatch(1, []int{taskToCreate.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), restored)
+	})
+
+	t.Run("other user's task is left alone", func(t *testing.T) {
+		db := setupTestDB(t)
+		taskToCreate := task.Task{UserID: 2, Task: "Buy Milk", Status: "pending"}
+		require.NoError(t, db.Create(&taskToCreate).Error)
+
+		require.NoError(t, NewTaskRepository(db).Delete(1, taskToCreate.ID))
+		var fetched task.Task
+		assert.NoError(t, db.First(&fetched, taskToCreate.ID).Error)
 	})
 
 	t.Run("delete non-existing task", func(t *testing.T) {
@@ -204,6 +226,50 @@ func TestTaskRepository_Delete(t *testing.T) {
 	})
 }
 
+func TestTaskRepository_Purge(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTaskRepository(db)
+	trashed := task.Task{UserID: 1, Task: "Old", Status: "pending"}
+	active := task.Task{UserID: 1, Task: "Current", Status: "pending"}
+	others := task.Task{UserID: 2, Task: "Theirs", Status: "pending"}
+	for _, tk := range []*task.Task{&trashed, &active, &others} {
+		require.NoError(t, db.Create(tk).Error)
+	}
+	child := task.Task{UserID: 1, Task: "Child", Status: "pending", ParentID: &trashed.ID}
+	require.NoError(t, db.Create(&child).Error)
+	require.NoError(t, r.Delete(1, trashed.ID))
+	require.NoError(t, r.Delete(2, others.ID))
+
+	later := time.Now().Add(time.Minute)
+	ids, err := r.TrashedIDs(1, later)
+	require.NoError(t, err)
+	assert.Equal(t, []int{trashed.ID}, ids)
+	ids, err = r.TrashedIDs(1, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, ids, "trashed too recently")
+
+	owners, err := r.TrashOwners(later)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, owners)
+
+	n, err := r.Purge(1, []int{trashed.ID, active.ID, others.ID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n, "only the user's trashed tasks are purged")
+
+	var count int64
+	require.NoError(t, db.Unscoped().Model(&task.Task{}).Where("id = ?", trashed.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, db.Unscoped().Model(&task.Task{}).Where("id IN ?", []int{active.ID, others.ID}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+	var fetched task.Task
+	require.NoError(t, db.First(&fetched, child.ID).Error)
+	assert.Nil(t, fetched.ParentID, "subtasks of purged tasks become top-level tasks")
+
+	ids, err = r.TrashedIDs(1, later)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
 func TestTaskRepository_UpdateStatus(t *testing.T) {
 	t.Run("successful status update", func(t *testing.T) {
 		db := setupTestDB(t)
@@ -218,6 +284,19 @@ func TestTaskRepository_UpdateStatus(t *testing.T) {
 		var updated task.Task
 		require.NoError(t, db.First(&updated, taskToCreate.ID).Error)
 		assert.Equal(t, "completed", updated.Status)
+		require.NotNil(t, updated.CompletedAt)
+		completedAt := *updated.CompletedAt
+
+		// Completing again keeps the original time; reopening clears it
+		require.NoError(t, r.UpdateStatus(1, taskToCreate.ID, "completed"))
+		require.NoError(t, db.First(&updated, taskToCreate.ID).Error)
+		require.NotNil(t, updated.CompletedAt)
+		assert.True(t, completedAt.Equal(*updated.CompletedAt))
+
+		require.NoError(t, r.UpdateStatus(1, taskToCreate.ID, "in-progress"))
+		var reopened task.Task
+		require.NoError(t, db.First(&reopened, taskToCreate.ID).Error)
+		assert.Nil(t, reopened.CompletedAt)
 	})
 
 	t.Run("update status non-existing task", func(t *testing.T) {
@@ -229,3 +308,418 @@ func TestTaskRepository_UpdateStatus(t *testing.T) {
 		assert.NoError(t, err) // GORM returns nil error if no rows affected
 	})
 }
+
+func TestTaskRepository_Find(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTaskRepository(db)
+
+	tasks := []task.Task{
+		{Task: "Write report", Status: "pending", UserID: 1, Tags: []task.Tag{{Name: "work"}}},
+		{Task: "Buy milk", Status: "completed", UserID: 1},
+		{Task: "Other user", Status: "pending", UserID: 2, Tags: []task.Tag{{Name: "work"}}},
+	}
+	for i := range tasks {
+		require.NoError(t, db.Create(&tasks[i]).Error)
+	}
+
+	t.Run("no scopes lists the user's tasks with tags", func(t *testing.T) {
+		got, err := r.Find(1)
+		assert.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, []task.Tag{{TaskID: got[0].ID, Name: "work"}}, got[0].Tags)
+	})
+
+	t.Run("scopes narrow the result", func(t *testing.T) {
+		pending := func(db *gorm.DB) *gorm.DB {
+			return db.Where("tasks.status = ?", "pending")
+		}
+		got, err := r.Find(1, pending)
+		assert.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "Write report", got[0].Task)
+	})
+}
+
+func TestTaskRepository_FindWithFilterScopes(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTaskRepository(db)
+
+	due := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	tasks := []task.Task{
+		{Task: "Write report", Status: "pending", Priority: task.PriorityLow, UserID: 1},
+		{Task: "Review report", Status: "pending", Priority: task.PriorityHigh, DueAt: &due, UserID: 1},
+		{Task: "File 100% of receipts", Status: "completed", Priority: task.PriorityMedium, UserID: 1},
+	}
+	for i := range tasks {
+		require.NoError(t, db.Create(&tasks[i]).Error)
+	}
+
+	t.Run("criteria and sort", func(t *testing.T) {
+		got, err := r.Find(1,
+			CriteriaScope(filter.Criteria{Status: "pending", Text: "report"}),
+			SortScope(filter.SortPriority, true),
+		)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "Review report", got[0].Task)
+		assert.Equal(t, "Write report", got[1].Task)
+	})
+
+	t.Run("text wildcards are literal", func(t *testing.T) {
+		got, err := r.Find(1, CriteriaScope(filter.Criteria{Text: "0%"}))
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "File 100% of receipts", got[0].Task)
+	})
+
+	t.Run("tasks without due date sort last", func(t *testing.T) {
+		got, err := r.Find(1, SortScope(filter.SortDueAt, false))
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		assert.Equal(t, "Review report", got[0].Task)
+	})
+
+	t.Run("count", func(t *testing.T) {
+		n, err := r.Count(1, CriteriaScope(filter.Criteria{Status: "pending"}))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+	})
+}
+
+func TestTaskRepository_Batch(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTaskRepository(db)
+
+	tasks := []task.Task{
+		{Task: "One", Status: "pending", UserID: 1},
+		{Task: "Two", Status: "pending", UserID: 1},
+		{Task: "Theirs", Status: "pending", UserID: 2},
+	}
+	for i := range tasks {
+		require.NoError(t, db.Create(&tasks[i]).Error)
+	}
+	ids := []int{tasks[0].ID, tasks[1].ID, tasks[2].ID}
+
+	t.Run("updates are scoped to the user", func(t *testing.T) {
+		n, err := r.UpdateStatusBatch(1, ids, "completed")
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		var theirs task.Task
+		require.NoError(t, db.First(&theirs, tasks[2].ID).Error)
+		assert.Equal(t, "pending", theirs.Status)
+	})
+
+	t.Run("move and back to inbox", func(t *testing.T) {
+		projectID := 7
+		n, err := r.MoveBatch(1, ids, &projectID, []string{"u", "v", "x"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		got, err := r.GetByID(1, tasks[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, &projectID, got.ProjectID)
+		assert.Equal(t, "u", got.Position)
+		got, err = r.GetByID(1, tasks[1].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "v", got.Position)
+		var theirs task.Task
+		require.NoError(t, db.First(&theirs, tasks[2].ID).Error)
+		assert.Nil(t, theirs.ProjectID)
+
+		_, err = r.MoveBatch(1, ids[:1], nil, []string{"w"})
+		require.NoError(t, err)
+		got, err = r.GetByID(1, tasks[0].ID)
+		require.NoError(t, err)
+		assert.Nil(t, got.ProjectID)
+	})
+
+	t.Run("tags are added once", func(t *testing.T) {
+		before, err := r.GetByID(1, ids[0])
+		require.NoError(t, err)
+		require.NoError(t, r.AddTagsBatch(1, ids[:2], []string{"cleanup"}))
+		require.NoError(t, r.AddTagsBatch(1, ids[:2], []string{"cleanup", "q3"}))
+
+		var count int64
+		require.NoError(t, db.Model(&task.Tag{}).Count(&count).Error)
+		assert.Equal(t, int64(4), count)
+		after, err := r.GetByID(1, ids[0])
+		require.NoError(t, err)
+		assert.True(t, after.UpdatedAt.After(before.UpdatedAt), "tagged tasks are marked updated")
+
+		require.NoError(t, r.AddTagsBatch(2, ids[:2], []string{"theirs"}))
+		require.NoError(t, db.Model(&task.Tag{}).Count(&count).Error)
+		assert.Equal(t, int64(4), count, "other users' tasks are not tagged")
+	})
+
+	t.Run("set tags replaces them", func(t *testing.T) {
+		require.NoError(t, r.SetTags(ids[0], []string{"q3", "home"}))
+
+		got, err := r.GetByID(1, ids[0])
+		require.NoError(t, err)
+		names := []string{}
+		for _, tag := range got.Tags {
+			names = append(names, tag.Name)
+		}
+		assert.ElementsMatch(t, []string{"q3", "home"}, names)
+
+		other, err := r.GetByID(1, ids[1])
+		require.NoError(t, err)
+		assert.Len(t, other.Tags, 2, "other tasks keep their tags")
+	})
+
+	t.Run("delete to trash and restore", func(t *testing.T) {
+		n, err := r.DeleteBatch(1, ids)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		_, err = r.GetByID(1, tasks[0].ID)
+		assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+		found, err := r.Lookup(ids)
+		require.NoError(t, err)
+		require.Len(t, found, 3)
+		assert.True(t, found[0].DeletedAt.Valid)
+		assert.False(t, found[2].DeletedAt.Valid)
+
+		n, err = r.RestoreBatch(1, ids)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		_, err = r.GetByID(1, tasks[0].ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("transaction rolls back on error", func(t *testing.T) {
+		err := r.Transaction(func(tx TaskRepositoryInterface) error {
+			if _, err := tx.UpdateStatusBatch(1, ids, "pending"); err != nil {
+				return err
+			}
+			return errors.New("abort")
+		})
+		assert.EqualError(t, err, "abort")
+
+		got, err := r.GetByID(1, tasks[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", got.Status)
+	})
+}
+
+func TestTaskRepository_AddEvents(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&events.Record{}))
+	r := NewTaskRepository(db)
+
+	err := r.Transaction(func(tx TaskRepositoryInterface) error {
+		created := task.Task{Task: "Rolled back", Status: "pending", UserID: 1}
+		if err := tx.Create(&created); err != nil {
+			return err
+		}
+		if err := tx.AddEvents(events.TaskCreated{UserID: 1}); err != nil {
+			return err
+		}
+		return errors.New("abort")
+	})
+	assert.EqualError(t, err, "abort")
+
+	err = r.Transaction(func(tx TaskRepositoryInterface) error {
+		created := task.Task{Task: "Kept", Status: "pending", UserID: 1}
+		if err := tx.Create(&created); err != nil {
+			return err
+		}
+		return tx.AddEvents(events.TaskCreated{UserID: 1})
+	})
+	require.NoError(t, err)
+
+	var records []events.Record
+	require.NoError(t, db.Find(&records).Error)
+	require.Len(t, records, 1, "events are committed with the change")
+	assert.Equal(t, events.TypeTaskCreated, records[0].Type)
+}
+
+func TestTaskRepository_Positions(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTaskRepository(db)
+
+	projectID := 3
+	tasks := []task.Task{
+		{Task: "B", Status: "pending", UserID: 1, Position: "r"},
+		{Task: "A", Status: "pending", UserID: 1, Position: "i"},
+		{Task: "In project", Status: "pending", UserID: 1, Position: "z", ProjectID: &projectID},
+		{Task: "Theirs", Status: "pending", UserID: 2, Position: "zz"},
+	}
+	for i := range tasks {
+		require.NoError(t, db.Create(&tasks[i]).Error)
+	}
+
+	t.Run("list scope is ordered by position", func(t *testing.T) {
+		got, err := r.ListScope(1, nil)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, tasks[1].ID, got[0].ID)
+		assert.Equal(t, tasks[0].ID, got[1].ID)
+	})
+
+	t.Run("last position per scope", func(t *testing.T) {
+		last, err := r.LastPosition(1, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "r", last)
+
+		last, err = r.LastPosition(1, &projectID)
+		require.NoError(t, err)
+		assert.Equal(t, "z", last)
+
+		last, err = r.LastPosition(5, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "", last)
+	})
+
+	t.Run("set position moves between lists", func(t *testing.T) {
+		require.NoError(t, r.SetPosition(1, tasks[0].ID, &projectID, "a"))
+
+		got, err := r.ListScope(1, &projectID)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, tasks[0].ID, got[0].ID)
+		assert.Equal(t, "a", got[0].Position)
+	})
+}
+
+func TestTaskRepository_Columns(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTaskRepository(db)
+
+	projectID := 3
+	for _, tk := range []task.Task{
+		{Task: "Second", Status: "in-progress", UserID: 1, Position: "r"},
+		{Task: "First", Status: "in-progress", UserID: 1, Position: "i"},
+		{Task: "Third", Status: "in-progress", UserID: 1, Position: "v"},
+		{Task: "Pending", Status: "pending", UserID: 1, Position: "a"},
+		{Task: "Other list", Status: "in-progress", UserID: 1, Position: "b", ProjectID: &projectID},
+	} {
+		require.NoError(t, db.Create(&tk).Error)
+	}
+
+	n, err := r.CountColumn(1, nil, "in-progress")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+
+	page, err := r.ListColumn(1, nil, "in-progress", 1, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, "Second", page[0].Task)
+}
+
+func TestTaskRepository_DueBetween(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTaskRepository(db)
+
+	now := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	for _, tk := range []task.Task{
+		{Task: "Later", Status: "pending", UserID: 1, DueAt: at(3 * time.Hour)},
+		{Task: "Soon", Status: "in-progress", UserID: 2, DueAt: at(time.Hour)},
+		{Task: "Done", Status: "completed", UserID: 1, DueAt: at(time.Hour)},
+		{Task: "Overdue", Status: "pending", UserID: 1, DueAt: at(-time.Hour)},
+		{Task: "Next week", Status: "pending", UserID: 1, DueAt: at(7 * 24 * time.Hour)},
+		{Task: "Undated", Status: "pending", UserID: 1},
+	} {
+		require.NoError(t, repo.Create(&tk))
+	}
+
+	got, err := repo.DueBetween(now, now.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Soon", got[0].Task)
+	assert.Equal(t, "Later", got[1].Task)
+}
+
+func TestTaskRepository_Batches(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTaskRepository(db)
+
+	for i := 1; i <= 5; i++ {
+		tk := task.Task{Task: fmt.Sprintf("Task %d", i), Status: "pending", UserID: 1, Tags: []task.Tag{{Name: "work"}}}
+		require.NoError(t, repo.Create(&tk))
+	}
+	other := task.Task{Task: "Other", Status: "pending", UserID: 2}
+	require.NoError(t, repo.Create(&other))
+	trashed := task.Task{Task: "Trashed", Status: "pending", UserID: 1}
+	require.NoError(t, repo.Create(&trashed))
+	_, err := repo.DeleteBatch(1, []int{trashed.ID})
+	require.NoError(t, err)
+
+	var sizes []int
+	var names []string
+	err = repo.Batches(1, 2, func(tasks []task.Task) error {
+		sizes = append(sizes, len(tasks))
+		for _, tk := range tasks {
+			names = append(names, tk.Task)
+			assert.Len(t, tk.Tags, 1)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Equal(t, []string{"Task 1", "Task 2", "Task 3", "Task 4", "Task 5"}, names)
+
+	stop := errors.New("stop")
+	calls := 0
+	err = repo.Batches(1, 2, func(tasks []task.Task) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls)
+}
+
+func TestTaskRepository_ExternalIDs(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTaskRepository(db)
+
+	ext := func(s string) *string { return &s }
+	imported := task.Task{Task: "Imported", Status: "pending", UserID: 1, ExternalID: ext("todoist:1")}
+	require.NoError(t, repo.Create(&imported))
+	trashed := task.Task{Task: "Trashed", Status: "pending", UserID: 1, ExternalID: ext("todoist:2")}
+	require.NoError(t, repo.Create(&trashed))
+	_, err := repo.DeleteBatch(1, []int{trashed.ID})
+	require.NoError(t, err)
+	for _, tk := range []task.Task{
+		{Task: "Local", Status: "pending", UserID: 1},
+		{Task: "Local too", Status: "pending", UserID: 1},
+		{Task: "Trello", Status: "pending", UserID: 1, ExternalID: ext("trello:1")},
+		{Task: "Other user", Status: "pending", UserID: 2, ExternalID: ext("todoist:1")},
+	} {
+		require.NoError(t, repo.Create(&tk))
+	}
+
+	ids, err := repo.ExternalIDs(1, "todoist:")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"todoist:1": imported.ID, "todoist:2": trashed.ID}, ids)
+
+	dup := task.Task{Task: "Again", Status: "pending", UserID: 1, ExternalID: ext("todoist:1")}
+	assert.Error(t, repo.Create(&dup))
+}
+
+func TestTaskRepository_LockColumn(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&board.Column{}))
+	r := NewTaskRepository(db)
+	projectID := 3
+	require.NoError(t, db.Create(&board.Column{UserID: 1, ProjectID: &projectID, Status: "in_progress", WIPLimit: 2}).Error)
+
+	err := r.Transaction(func(tx TaskRepositoryInterface) error {
+		limit, err := tx.LockColumn(1, &projectID, "in_progress")
+		require.NoError(t, err)
+		assert.Equal(t, 2, limit)
+
+		limit, err = tx.LockColumn(1, nil, "in_progress")
+		require.NoError(t, err)
+		assert.Zero(t, limit, "columns without a row have no limit")
+		return nil
+	})
+	require.NoError(t, err)
+}
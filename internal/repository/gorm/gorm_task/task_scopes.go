@@ -0,0 +1,76 @@
+package gorm_task
+
+import (
+	"strings"
+
+	"taskflow/internal/domain/filter"
+
+	"gorm.io/gorm"
+)
+
+// sortColumns whitelists the columns a caller may order by.
+var sortColumns = map[string]string{
+	filter.SortCreatedAt: "tasks.created_at",
+	filter.SortUpdatedAt: "tasks.updated_at",
+	filter.SortDueAt:     "tasks.due_at",
+	filter.SortPriority:  "tasks.priority",
+	filter.SortTask:      "tasks.task",
+	filter.SortPosition:  "tasks.position",
+}
+
+// CriteriaScope narrows a tasks query to the saved filter criteria. Empty
+// fields are ignored.
+func CriteriaScope(c filter.Criteria) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if c.Status != "" {
+			db = db.Where("tasks.status = ?", c.Status)
+		}
+		if c.CreatedFrom != nil {
+			db = db.Where("tasks.created_at >= ?", *c.CreatedFrom)
+		}
+		if c.CreatedTo != nil {
+			db = db.Where("tasks.created_at < ?", *c.CreatedTo)
+		}
+		if c.UpdatedFrom != nil {
+			db = db.Where("tasks.updated_at >= ?", *c.UpdatedFrom)
+		}
+		if c.UpdatedTo != nil {
+			db = db.Where("tasks.updated_at < ?", *c.UpdatedTo)
+		}
+		if c.Text != "" {
+			pattern := "%" + escapeLike(c.Text) + "%"
+			db = db.Where("(tasks.task LIKE ? ESCAPE '!' OR tasks.description LIKE ? ESCAPE '!')", pattern, pattern)
+		}
+		return db
+	}
+}
+
+// SortScope orders a tasks query by one of the filter.Sort* columns. Unknown
+// columns fall back to creation time. Tasks without a due date sort last.
+func SortScope(sortBy string, desc bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		column, ok := sortColumns[sortBy]
+		if !ok {
+			column = sortColumns[filter.SortCreatedAt]
+		}
+		direction := " ASC"
+		if desc {
+			direction = " DESC"
+		}
+		if sortBy == filter.SortDueAt {
+			db = db.Order("tasks.due_at IS NULL")
+		}
+		return db.Order(column + direction)
+	}
+}
+
+// IsSortColumn reports whether SortScope accepts sortBy.
+func IsSortColumn(sortBy string) bool {
+	_, ok := sortColumns[sortBy]
+	return ok
+}
+
+// escapeLike escapes LIKE wildcards using '!' as the escape character.
+func escapeLike(s string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(s)
+}
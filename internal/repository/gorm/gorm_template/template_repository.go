@@ -0,0 +1,86 @@
+package gorm_template
+
+import (
+	"taskflow/internal/domain/template"
+
+	"gorm.io/gorm"
+)
+
+type TemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewTemplateRepository(db *gorm.DB) *TemplateRepository {
+	return &TemplateRepository{db: db}
+}
+
+// Compile-time check
+var _ TemplateRepositoryInterface = (*TemplateRepository)(nil)
+
+// Create stores a template together with its subtasks.
+func (r *TemplateRepository) Create(t *template.Template) error {
+	return r.db.Create(t).Error
+}
+
+func (r *TemplateRepository) GetByID(userID int, id int) (*template.Template, error) {
+	var t template.Template
+	err := r.db.Preload("Subtasks", orderSubtasks).
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&t).Error
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TemplateRepository) List(userID int) ([]template.Template, error) {
+	var templates []template.Template
+	err := r.db.Preload("Subtasks", orderSubtasks).
+		Where("user_id = ?", userID).
+		Order("name ASC, id ASC").
+		Find(&templates).Error
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+// Update saves a template's fields and replaces its subtasks.
+func (r *TemplateRepository) Update(t *template.Template) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(t).
+			Select("name", "title", "description", "tags", "due_offset").
+			Updates(t).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", t.ID).Delete(&template.Subtask{}).Error; err != nil {
+			return err
+		}
+		for i := range t.Subtasks {
+			t.Subtasks[i].ID = 0
+			t.Subtasks[i].TemplateID = t.ID
+		}
+		if len(t.Subtasks) == 0 {
+			return nil
+		}
+		return tx.Create(&t.Subtasks).Error
+	})
+}
+
+func (r *TemplateRepository) Delete(userID int, id int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ?", userID).Delete(&template.Template{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("template_id = ?", id).Delete(&template.Subtask{}).Error
+	})
+}
+
+func orderSubtasks(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC, id ASC")
+}
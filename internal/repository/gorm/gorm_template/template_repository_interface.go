@@ -0,0 +1,11 @@
+package gorm_template
+
+import "taskflow/internal/domain/template"
+
+type TemplateRepositoryInterface interface {
+	Create(template *template.Template) error
+	GetByID(userID int, id int) (*template.Template, error)
+	List(userID int) ([]template.Template, error)
+	Update(template *template.Template) error
+	Delete(userID int, id int) error
+}
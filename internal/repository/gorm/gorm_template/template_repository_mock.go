@@ -0,0 +1,41 @@
+package gorm_template
+
+import (
+	"taskflow/internal/domain/template"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TemplateRepoMock struct {
+	mock.Mock
+}
+
+var _ TemplateRepositoryInterface = (*TemplateRepoMock)(nil)
+
+func (m *TemplateRepoMock) Create(t *template.Template) error {
+	args := m.Called(t)
+	return args.Error(0)
+}
+
+func (m *TemplateRepoMock) GetByID(userID int, id int) (*template.Template, error) {
+	args := m.Called(userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*template.Template), args.Error(1)
+}
+
+func (m *TemplateRepoMock) List(userID int) ([]template.Template, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]template.Template), args.Error(1)
+}
+
+func (m *TemplateRepoMock) Update(t *template.Template) error {
+	args := m.Called(t)
+	return args.Error(0)
+}
+
+func (m *TemplateRepoMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
@@ -0,0 +1,121 @@
+package gorm_template
+
+import (
+	"errors"
+	"taskflow/internal/domain/template"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&template.Template{}, &template.Subtask{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func releasePrep() template.Template {
+	return template.Template{
+		UserID:    1,
+		Name:      "Release prep",
+		Title:     "Release {{version}}",
+		Tags:      []string{"release"},
+		DueOffset: "5d",
+		Subtasks: []template.Subtask{
+			{Position: 1, Title: "Changelog", DueOffset: "2d"},
+			{Position: 0, Title: "Freeze {{version}}", Tags: []string{"git"}},
+		},
+	}
+}
+
+func TestTemplateRepository_CreateAndGet(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTemplateRepository(db)
+
+	tpl := releasePrep()
+	require.NoError(t, r.Create(&tpl))
+
+	got, err := r.GetByID(1, tpl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"release"}, got.Tags)
+	require.Len(t, got.Subtasks, 2)
+	assert.Equal(t, "Freeze {{version}}", got.Subtasks[0].Title)
+	assert.Equal(t, []string{"git"}, got.Subtasks[0].Tags)
+	assert.Equal(t, "Changelog", got.Subtasks[1].Title)
+
+	_, err = r.GetByID(2, tpl.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+}
+
+func TestTemplateRepository_List(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTemplateRepository(db)
+
+	for _, tpl := range []template.Template{
+		{UserID: 1, Name: "Sprint", Title: "Sprint"},
+		{UserID: 1, Name: "Onboarding", Title: "Onboard {{name}}"},
+		{UserID: 2, Name: "Theirs", Title: "Theirs"},
+	} {
+		require.NoError(t, r.Create(&tpl))
+	}
+
+	got, err := r.List(1)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "Onboarding", got[0].Name)
+	assert.Equal(t, "Sprint", got[1].Name)
+}
+
+func TestTemplateRepository_Update(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTemplateRepository(db)
+
+	tpl := releasePrep()
+	require.NoError(t, r.Create(&tpl))
+
+	tpl.Name = "Release"
+	tpl.Tags = nil
+	tpl.DueOffset = ""
+	tpl.Subtasks = []template.Subtask{{Title: "Announce"}}
+	require.NoError(t, r.Update(&tpl))
+
+	got, err := r.GetByID(1, tpl.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Release", got.Name)
+	assert.Empty(t, got.Tags)
+	assert.Equal(t, "", got.DueOffset)
+	require.Len(t, got.Subtasks, 1)
+	assert.Equal(t, "Announce", got.Subtasks[0].Title)
+
+	var n int64
+	require.NoError(t, db.Model(&template.Subtask{}).Count(&n).Error)
+	assert.Equal(t, int64(1), n)
+}
+
+func TestTemplateRepository_Delete(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTemplateRepository(db)
+
+	tpl := releasePrep()
+	require.NoError(t, r.Create(&tpl))
+
+	assert.True(t, errors.Is(r.Delete(2, tpl.ID), gorm.ErrRecordNotFound))
+	require.NoError(t, r.Delete(1, tpl.ID))
+
+	_, err := r.GetByID(1, tpl.ID)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+
+	var n int64
+	require.NoError(t, db.Model(&template.Subtask{}).Count(&n).Error)
+	assert.Zero(t, n)
+}
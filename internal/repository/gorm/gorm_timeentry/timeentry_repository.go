@@ -0,0 +1,112 @@
+package gorm_timeentry
+
+import (
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/timeentry"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReportEntry is a time entry with the project and tags of its task.
+type ReportEntry struct {
+	timeentry.Entry
+	ProjectID *int
+	Tags      []string `gorm:"-"`
+}
+
+type TimeEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeEntryRepository(db *gorm.DB) *TimeEntryRepository {
+	return &TimeEntryRepository{db: db}
+}
+
+// Compile-time check
+var _ TimeEntryRepositoryInterface = (*TimeEntryRepository)(nil)
+
+func (r *TimeEntryRepository) Create(e *timeentry.Entry) error {
+	return r.db.Create(e).Error
+}
+
+func (r *TimeEntryRepository) GetByID(taskID int, id int) (*timeentry.Entry, error) {
+	var e timeentry.Entry
+	if err := r.db.Where("id = ? AND task_id = ?", id, taskID).First(&e).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Running returns the user's running timer, or gorm.ErrRecordNotFound.
+func (r *TimeEntryRepository) Running(userID int) (*timeentry.Entry, error) {
+	var e timeentry.Entry
+	if err := r.db.Where("running_user_id = ?", userID).First(&e).Error; err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Stop saves the entry's end time and frees the user's timer slot.
+func (r *TimeEntryRepository) Stop(e *timeentry.Entry) error {
+	e.RunningUserID = nil
+	return r.db.Model(e).Updates(map[string]any{
+		"ended_at":        e.EndedAt,
+		"running_user_id": nil,
+	}).Error
+}
+
+func (r *TimeEntryRepository) Delete(taskID int, id int) error {
+	res := r.db.Where("task_id = ?", taskID).Delete(&timeentry.Entry{}, id)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ListByTask returns a task's entries, most recent first.
+func (r *TimeEntryRepository) ListByTask(taskID int) ([]timeentry.Entry, error) {
+	var entries []timeentry.Entry
+	err := r.db.Where("task_id = ?", taskID).
+		Order("started_at DESC, id DESC").
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListForReport returns the user's entries started in [from, to), with the
+// project and tags of their tasks. Entries on trashed tasks are included.
+func (r *TimeEntryRepository) ListForReport(userID int, from time.Time, to time.Time) ([]ReportEntry, error) {
+	var entries []ReportEntry
+	err := r.db.Model(&timeentry.Entry{}).
+		Select("time_entries.*, tasks.project_id").
+		Joins("JOIN tasks ON tasks.id = time_entries.task_id").
+		Where("time_entries.user_id = ? AND time_entries.started_at >= ? AND time_entries.started_at < ?", userID, from, to).
+		Order("time_entries.started_at, time_entries.id").
+		Scan(&entries).Error
+	if err != nil || len(entries) == 0 {
+		return entries, err
+	}
+
+	taskIDs := make([]int, 0, len(entries))
+	for _, e := range entries {
+		taskIDs = append(taskIDs, e.TaskID)
+	}
+	var tags []task.Tag
+	if err := r.db.Where("task_id IN ?", taskIDs).Order("name").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	byTask := make(map[int][]string)
+	for _, t := range tags {
+		byTask[t.TaskID] = append(byTask[t.TaskID], t.Name)
+	}
+	for i := range entries {
+		entries[i].Tags = byTask[entries[i].TaskID]
+	}
+	return entries, nil
+}
@@ -0,0 +1,16 @@
+package gorm_timeentry
+
+import (
+	"taskflow/internal/domain/timeentry"
+	"time"
+)
+
+type TimeEntryRepositoryInterface interface {
+	Create(entry *timeentry.Entry) error
+	GetByID(taskID int, id int) (*timeentry.Entry, error)
+	Running(userID int) (*timeentry.Entry, error)
+	Stop(entry *timeentry.Entry) error
+	Delete(taskID int, id int) error
+	ListByTask(taskID int) ([]timeentry.Entry, error)
+	ListForReport(userID int, from time.Time, to time.Time) ([]ReportEntry, error)
+}
@@ -0,0 +1,55 @@
+package gorm_timeentry
+
+import (
+	"taskflow/internal/domain/timeentry"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TimeEntryRepoMock struct {
+	mock.Mock
+}
+
+var _ TimeEntryRepositoryInterface = (*TimeEntryRepoMock)(nil)
+
+func (m *TimeEntryRepoMock) Create(e *timeentry.Entry) error {
+	args := m.Called(e)
+	return args.Error(0)
+}
+
+func (m *TimeEntryRepoMock) GetByID(taskID int, id int) (*timeentry.Entry, error) {
+	args := m.Called(taskID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*timeentry.Entry), args.Error(1)
+}
+
+func (m *TimeEntryRepoMock) Running(userID int) (*timeentry.Entry, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*timeentry.Entry), args.Error(1)
+}
+
+func (m *TimeEntryRepoMock) Stop(e *timeentry.Entry) error {
+	args := m.Called(e)
+	return args.Error(0)
+}
+
+func (m *TimeEntryRepoMock) Delete(taskID int, id int) error {
+	args := m.Called(taskID, id)
+	return args.Error(0)
+}
+
+func (m *TimeEntryRepoMock) ListByTask(taskID int) ([]timeentry.Entry, error) {
+	args := m.Called(taskID)
+	return args.Get(0).([]timeentry.Entry), args.Error(1)
+}
+
+func (m *TimeEntryRepoMock) ListForReport(userID int, from time.Time, to time.Time) ([]ReportEntry, error) {
+	args := m.Called(userID, from, to)
+	return args.Get(0).([]ReportEntry), args.Error(1)
+}
@@ -0,0 +1,93 @@
+package gorm_timeentry
+
+import (
+	"errors"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/timeentry"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&task.Task{}, &task.Tag{}, &timeentry.Entry{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestTimeEntryRepository_Timer(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTimeEntryRepository(db)
+	user := 1
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	_, err := r.Running(1)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	running := timeentry.Entry{TaskID: 10, UserID: 1, StartedAt: start, RunningUserID: &user}
+	require.NoError(t, r.Create(&running))
+
+	second := timeentry.Entry{TaskID: 11, UserID: 1, StartedAt: start, RunningUserID: &user}
+	assert.Error(t, r.Create(&second), "only one running timer per user")
+
+	got, err := r.Running(1)
+	require.NoError(t, err)
+	assert.Equal(t, running.ID, got.ID)
+
+	end := start.Add(90 * time.Minute)
+	got.EndedAt = &end
+	require.NoError(t, r.Stop(got))
+
+	_, err = r.Running(1)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	require.NoError(t, r.Create(&timeentry.Entry{TaskID: 11, UserID: 1, StartedAt: end, RunningUserID: &user}))
+
+	stored, err := r.GetByID(10, running.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.EndedAt)
+	assert.Equal(t, 90*time.Minute, stored.Duration(time.Now()))
+}
+
+func TestTimeEntryRepository_ListForReport(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewTimeEntryRepository(db)
+
+	projectID := 5
+	tasks := []task.Task{
+		{Task: "Design", Status: "pending", UserID: 1, ProjectID: &projectID, Tags: []task.Tag{{Name: "work"}, {Name: "client"}}},
+		{Task: "Errands", Status: "pending", UserID: 1},
+	}
+	for i := range tasks {
+		require.NoError(t, db.Create(&tasks[i]).Error)
+	}
+
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := day.Add(10 * time.Hour)
+	for _, e := range []timeentry.Entry{
+		{TaskID: tasks[0].ID, UserID: 1, StartedAt: day.Add(9 * time.Hour), EndedAt: &end},
+		{TaskID: tasks[1].ID, UserID: 1, StartedAt: day.Add(11 * time.Hour), EndedAt: &end},
+		{TaskID: tasks[1].ID, UserID: 1, StartedAt: day.AddDate(0, 0, 3), EndedAt: &end},
+		{TaskID: tasks[0].ID, UserID: 2, StartedAt: day.Add(9 * time.Hour), EndedAt: &end},
+	} {
+		require.NoError(t, r.Create(&e))
+	}
+
+	got, err := r.ListForReport(1, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, &projectID, got[0].ProjectID)
+	assert.Equal(t, []string{"client", "work"}, got[0].Tags)
+	assert.Nil(t, got[1].ProjectID)
+	assert.Empty(t, got[1].Tags)
+}
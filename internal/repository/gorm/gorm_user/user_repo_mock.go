@@ -2,6 +2,7 @@ package gorm_user
 
 import (
 	"taskflow/internal/domain/user"
+	"taskflow/internal/events"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -40,3 +41,14 @@ func (m *MockUserRepository) Delete(id int) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
+
+// Transaction runs fn against the mock itself.
+func (m *MockUserRepository) Transaction(fn func(repo UserRepositoryInterface) error) error {
+	m.Called(fn)
+	return fn(m)
+}
+
+func (m *MockUserRepository) AddEvents(evs ...events.Event) error {
+	args := m.Called(evs)
+	return args.Error(0)
+}
@@ -3,6 +3,7 @@ package gorm_user
 import (
 	"fmt"
 	"taskflow/internal/domain/user"
+	"taskflow/internal/events"
 
 	"gorm.io/gorm"
 )
@@ -48,6 +49,17 @@ func (r *UserRepository) Delete(id int) error {
 	return r.db.Delete(&user.User{}, id).Error
 }
 
+// Transaction runs fn with a repository bound to a single database transaction.
+func (r *UserRepository) Transaction(fn func(repo UserRepositoryInterface) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&UserRepository{db: tx})
+	})
+}
+
+func (r *UserRepository) AddEvents(evs ...events.Event) error {
+	return events.NewOutbox(r.db).Append(evs...)
+}
+
 func (r *UserRepository) GetByID(id int) (*user.User, error) {
 	if id == 0 {
 		return nil, fmt.Errorf("missing user ID")
@@ -1,6 +1,9 @@
 package gorm_user
 
-import "taskflow/internal/domain/user"
+import (
+	"taskflow/internal/domain/user"
+	"taskflow/internal/events"
+)
 
 type UserRepositoryInterface interface {
 	Create(user *user.User) error
@@ -8,4 +11,9 @@ type UserRepositoryInterface interface {
 	GetByEmail(email string) (*user.User, error)
 	Update(user *user.User) error
 	Delete(id int) error
+
+	Transaction(fn func(repo UserRepositoryInterface) error) error
+	// AddEvents records domain events in the outbox, within the
+	// repository's transaction when called from Transaction.
+	AddEvents(evs ...events.Event) error
 }
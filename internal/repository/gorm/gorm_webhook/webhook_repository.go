@@ -0,0 +1,161 @@
+package gorm_webhook
+
+import (
+	"time"
+
+	"taskflow/internal/domain/webhook"
+
+	"gorm.io/gorm"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// Compile-time check
+var _ WebhookRepositoryInterface = (*WebhookRepository)(nil)
+
+func (r *WebhookRepository) CreateSubscription(s *webhook.Subscription) error {
+	return r.db.Create(s).Error
+}
+
+func (r *WebhookRepository) GetSubscription(userID int, id int) (*webhook.Subscription, error) {
+	var s webhook.Subscription
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&s).Error; err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *WebhookRepository) ListSubscriptions(userID int) ([]webhook.Subscription, error) {
+	var subs []webhook.Subscription
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&subs).Error
+	return subs, err
+}
+
+// ListActive returns the user's subscriptions that are not disabled.
+func (r *WebhookRepository) ListActive(userID int) ([]webhook.Subscription, error) {
+	var subs []webhook.Subscription
+	err := r.db.Where("user_id = ? AND active = ?", userID, true).Order("id ASC").Find(&subs).Error
+	return subs, err
+}
+
+// UpdateSubscription saves the endpoint settings, including whether it is
+// active and its failure count.
+func (r *WebhookRepository) UpdateSubscription(s *webhook.Subscription) error {
+	return r.db.Model(s).
+		Select("url", "events", "active", "failures", "disabled_at").
+		Updates(s).Error
+}
+
+// DeleteSubscription removes a subscription and its delivery log.
+func (r *WebhookRepository) DeleteSubscription(userID int, id int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("user_id = ?", userID).Delete(&webhook.Subscription{}, id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("subscription_id = ?", id).Delete(&webhook.Delivery{}).Error
+	})
+}
+
+// DeleteUserSubscriptions removes all of a user's subscriptions and their
+// delivery logs.
+func (r *WebhookRepository) DeleteUserSubscriptions(userID int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids := tx.Model(&webhook.Subscription{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("subscription_id IN (?)", ids).Delete(&webhook.Delivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&webhook.Subscription{}).Error
+	})
+}
+
+// RecordFailure counts a failed attempt and returns the number of failures
+// since the last success. The increment happens in the database so that
+// concurrent deliveries do not lose counts.
+func (r *WebhookRepository) RecordFailure(id int) (int, error) {
+	err := r.db.Model(&webhook.Subscription{}).
+		Where("id = ?", id).
+		UpdateColumn("failures", gorm.Expr("failures + 1")).Error
+	if err != nil {
+		return 0, err
+	}
+	var s webhook.Subscription
+	if err := r.db.Select("failures").First(&s, id).Error; err != nil {
+		return 0, err
+	}
+	return s.Failures, nil
+}
+
+func (r *WebhookRepository) ResetFailures(id int) error {
+	return r.db.Model(&webhook.Subscription{}).
+		Where("id = ? AND failures > 0", id).
+		UpdateColumn("failures", 0).Error
+}
+
+func (r *WebhookRepository) Disable(id int, at time.Time) error {
+	return r.db.Model(&webhook.Subscription{}).
+		Where("id = ? AND active = ?", id, true).
+		Updates(map[string]any{"active": false, "disabled_at": at}).Error
+}
+
+func (r *WebhookRepository) CreateDelivery(d *webhook.Delivery) error {
+	return r.db.Create(d).Error
+}
+
+func (r *WebhookRepository) GetDelivery(subscriptionID int, id int) (*webhook.Delivery, error) {
+	var d webhook.Delivery
+	if err := r.db.Where("id = ? AND subscription_id = ?", id, subscriptionID).First(&d).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// GetEventDelivery returns the first delivery of an event to a
+// subscription, ignoring redeliveries.
+func (r *WebhookRepository) GetEventDelivery(subscriptionID int, eventID string) (*webhook.Delivery, error) {
+	var d webhook.Delivery
+	err := r.db.Where("subscription_id = ? AND event_id = ? AND redelivery_of IS NULL", subscriptionID, eventID).
+		Order("id ASC").
+		First(&d).Error
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListDeliveries returns a page of a subscription's deliveries, newest
+// first, and their total number.
+func (r *WebhookRepository) ListDeliveries(subscriptionID int, offset int, limit int) ([]webhook.Delivery, int64, error) {
+	query := r.db.Model(&webhook.Delivery{}).Where("subscription_id = ?", subscriptionID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []webhook.Delivery
+	err := query.Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
+
+// UpdateDelivery saves the outcome of a delivery attempt.
+func (r *WebhookRepository) UpdateDelivery(d *webhook.Delivery) error {
+	return r.db.Model(d).
+		Select("status", "attempts", "response_status", "error", "duration_ms", "last_attempt_at").
+		Updates(d).Error
+}
@@ -0,0 +1,26 @@
+package gorm_webhook
+
+import (
+	"time"
+
+	"taskflow/internal/domain/webhook"
+)
+
+type WebhookRepositoryInterface interface {
+	CreateSubscription(s *webhook.Subscription) error
+	GetSubscription(userID int, id int) (*webhook.Subscription, error)
+	ListSubscriptions(userID int) ([]webhook.Subscription, error)
+	ListActive(userID int) ([]webhook.Subscription, error)
+	UpdateSubscription(s *webhook.Subscription) error
+	DeleteSubscription(userID int, id int) error
+	DeleteUserSubscriptions(userID int) error
+	RecordFailure(id int) (int, error)
+	ResetFailures(id int) error
+	Disable(id int, at time.Time) error
+
+	CreateDelivery(d *webhook.Delivery) error
+	GetDelivery(subscriptionID int, id int) (*webhook.Delivery, error)
+	GetEventDelivery(subscriptionID int, eventID string) (*webhook.Delivery, error)
+	ListDeliveries(subscriptionID int, offset int, limit int) ([]webhook.Delivery, int64, error)
+	UpdateDelivery(d *webhook.Delivery) error
+}
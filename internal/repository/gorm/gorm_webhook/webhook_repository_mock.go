@@ -0,0 +1,90 @@
+package gorm_webhook
+
+import (
+	"time"
+
+	"taskflow/internal/domain/webhook"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookRepoMock struct {
+	mock.Mock
+}
+
+var _ WebhookRepositoryInterface = (*WebhookRepoMock)(nil)
+
+func (m *WebhookRepoMock) CreateSubscription(s *webhook.Subscription) error {
+	args := m.Called(s)
+	return args.Error(0)
+}
+
+func (m *WebhookRepoMock) GetSubscription(userID int, id int) (*webhook.Subscription, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(*webhook.Subscription), args.Error(1)
+}
+
+func (m *WebhookRepoMock) ListSubscriptions(userID int) ([]webhook.Subscription, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]webhook.Subscription), args.Error(1)
+}
+
+func (m *WebhookRepoMock) ListActive(userID int) ([]webhook.Subscription, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]webhook.Subscription), args.Error(1)
+}
+
+func (m *WebhookRepoMock) UpdateSubscription(s *webhook.Subscription) error {
+	args := m.Called(s)
+	return args.Error(0)
+}
+
+func (m *WebhookRepoMock) DeleteSubscription(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *WebhookRepoMock) DeleteUserSubscriptions(userID int) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *WebhookRepoMock) RecordFailure(id int) (int, error) {
+	args := m.Called(id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *WebhookRepoMock) ResetFailures(id int) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *WebhookRepoMock) Disable(id int, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *WebhookRepoMock) CreateDelivery(d *webhook.Delivery) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
+
+func (m *WebhookRepoMock) GetDelivery(subscriptionID int, id int) (*webhook.Delivery, error) {
+	args := m.Called(subscriptionID, id)
+	return args.Get(0).(*webhook.Delivery), args.Error(1)
+}
+
+func (m *WebhookRepoMock) GetEventDelivery(subscriptionID int, eventID string) (*webhook.Delivery, error) {
+	args := m.Called(subscriptionID, eventID)
+	return args.Get(0).(*webhook.Delivery), args.Error(1)
+}
+
+func (m *WebhookRepoMock) ListDeliveries(subscriptionID int, offset int, limit int) ([]webhook.Delivery, int64, error) {
+	args := m.Called(subscriptionID, offset, limit)
+	return args.Get(0).([]webhook.Delivery), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *WebhookRepoMock) UpdateDelivery(d *webhook.Delivery) error {
+	args := m.Called(d)
+	return args.Error(0)
+}
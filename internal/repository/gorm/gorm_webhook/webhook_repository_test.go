@@ -0,0 +1,142 @@
+package gorm_webhook
+
+import (
+	"taskflow/internal/domain/webhook"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&webhook.Subscription{}, &webhook.Delivery{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestWebhookRepository_Subscriptions(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewWebhookRepository(db)
+
+	s := webhook.Subscription{UserID: 1, URL: "https://example.com/a", Secret: "whsec_a", Events: []string{webhook.EventTaskCreated}, Active: true}
+	require.NoError(t, r.CreateSubscription(&s))
+	other := webhook.Subscription{UserID: 1, URL: "https://example.com/b", Secret: "whsec_b", Events: []string{webhook.EventTaskDeleted}, Active: true}
+	require.NoError(t, r.CreateSubscription(&other))
+
+	got, err := r.GetSubscription(1, s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{webhook.EventTaskCreated}, got.Events)
+	_, err = r.GetSubscription(2, s.ID)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	failures, err := r.RecordFailure(s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
+	failures, err = r.RecordFailure(s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, failures)
+
+	at := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, r.Disable(s.ID, at))
+	active, err := r.ListActive(1)
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, other.ID, active[0].ID)
+
+	// Re-enabling goes through UpdateSubscription.
+	got, err = r.GetSubscription(1, s.ID)
+	require.NoError(t, err)
+	assert.False(t, got.Active)
+	assert.Equal(t, 2, got.Failures)
+	got.Active, got.Failures, got.DisabledAt = true, 0, nil
+	require.NoError(t, r.UpdateSubscription(got))
+	active, err = r.ListActive(1)
+	require.NoError(t, err)
+	assert.Len(t, active, 2)
+
+	require.NoError(t, r.CreateDelivery(&webhook.Delivery{SubscriptionID: s.ID, EventID: "e1", Event: webhook.EventTaskCreated, Payload: "{}", Status: webhook.StatusPending}))
+	require.NoError(t, r.DeleteSubscription(1, s.ID))
+	assert.ErrorIs(t, r.DeleteSubscription(1, s.ID), gorm.ErrRecordNotFound)
+	var deliveries int64
+	require.NoError(t, db.Model(&webhook.Delivery{}).Count(&deliveries).Error)
+	assert.Zero(t, deliveries, "the delivery log goes with the subscription")
+}
+
+func TestWebhookRepository_Deliveries(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewWebhookRepository(db)
+
+	base := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		d := webhook.Delivery{SubscriptionID: 1, EventID: "e", Event: webhook.EventTaskCreated, Payload: "{}", Status: webhook.StatusPending, CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+		require.NoError(t, r.CreateDelivery(&d))
+	}
+	require.NoError(t, r.CreateDelivery(&webhook.Delivery{SubscriptionID: 2, EventID: "e", Event: webhook.EventTaskCreated, Payload: "{}", Status: webhook.StatusPending}))
+
+	got, total, err := r.ListDeliveries(1, 0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	require.Len(t, got, 2)
+	assert.Equal(t, 3, got[0].ID, "newest first")
+
+	d := got[0]
+	d.Status, d.Attempts, d.ResponseStatus, d.LastAttemptAt = webhook.StatusSucceeded, 1, 204, &base
+	require.NoError(t, r.UpdateDelivery(&d))
+
+	saved, err := r.GetDelivery(1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.StatusSucceeded, saved.Status)
+	assert.Equal(t, 204, saved.ResponseStatus)
+	_, err = r.GetDelivery(2, 3)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestWebhookRepository_GetEventDelivery(t *testing.T) {
+	r := NewWebhookRepository(setupTestDB(t))
+
+	first := webhook.Delivery{SubscriptionID: 1, EventID: "e1", Event: webhook.EventTaskCreated, Payload: "{}", Status: webhook.StatusFailed}
+	require.NoError(t, r.CreateDelivery(&first))
+	require.NoError(t, r.CreateDelivery(&webhook.Delivery{SubscriptionID: 1, EventID: "e1", Event: webhook.EventTaskCreated, Payload: "{}", Status: webhook.StatusPending, RedeliveryOf: &first.ID}))
+
+	got, err := r.GetEventDelivery(1, "e1")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, got.ID, "redeliveries are ignored")
+	_, err = r.GetEventDelivery(2, "e1")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestWebhookRepository_DeleteUserSubscriptions(t *testing.T) {
+	db := setupTestDB(t)
+	r := NewWebhookRepository(db)
+
+	mine := webhook.Subscription{UserID: 1, URL: "https://example.com/a", Secret: "whsec_a", Events: []string{webhook.EventTaskCreated}, Active: true}
+	require.NoError(t, r.CreateSubscription(&mine))
+	theirs := webhook.Subscription{UserID: 2, URL: "https://example.com/b", Secret: "whsec_b", Events: []string{webhook.EventTaskCreated}, Active: true}
+	require.NoError(t, r.CreateSubscription(&theirs))
+	require.NoError(t, r.CreateDelivery(&webhook.Delivery{SubscriptionID: mine.ID, EventID: "e1", Event: webhook.EventTaskCreated, Payload: "{}", Status: webhook.StatusPending}))
+	require.NoError(t, r.CreateDelivery(&webhook.Delivery{SubscriptionID: theirs.ID, EventID: "e2", Event: webhook.EventTaskCreated, Payload: "{}", Status: webhook.StatusPending}))
+
+	require.NoError(t, r.DeleteUserSubscriptions(1))
+	require.NoError(t, r.DeleteUserSubscriptions(1), "deleting again is a no-op")
+
+	subs, err := r.ListSubscriptions(1)
+	require.NoError(t, err)
+	assert.Empty(t, subs)
+	subs, err = r.ListSubscriptions(2)
+	require.NoError(t, err)
+	assert.Len(t, subs, 1)
+	var deliveries []webhook.Delivery
+	require.NoError(t, db.Find(&deliveries).Error)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, theirs.ID, deliveries[0].SubscriptionID)
+}
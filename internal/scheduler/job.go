@@ -0,0 +1,64 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job statuses.
+const (
+	StatusPending = "pending"
+	StatusDone    = "done"
+	StatusFailed  = "failed"
+)
+
+// DefaultMaxAttempts is used for jobs enqueued without a limit.
+const DefaultMaxAttempts = 5
+
+// Job is a unit of background work that runs at or after RunAt. DedupKey
+// makes enqueueing idempotent: a job whose key already exists is not added
+// again, so every replica may enqueue the same work.
+//
+// A worker owns a job while LockedUntil is in the future. Attempts counts
+// leases, so a worker that dies mid-job still uses up an attempt.
+type Job struct {
+	ID          int64      `gorm:"primaryKey"`
+	Kind        string     `gorm:"size:50;not null"`
+	DedupKey    string     `gorm:"size:191;not null;uniqueIndex"`
+	Payload     string     `gorm:"type:text"` // JSON
+	Status      string     `gorm:"size:20;not null;default:'pending';index:idx_scheduled_jobs_due,priority:1"`
+	RunAt       time.Time  `gorm:"not null;index:idx_scheduled_jobs_due,priority:2"`
+	Attempts    int        `gorm:"not null;default:0"`
+	MaxAttempts int        `gorm:"not null;default:5"`
+	LockedBy    string     `gorm:"size:100;not null;default:''"`
+	LockedUntil *time.Time `gorm:"index"`
+	LastError   string     `gorm:"type:text"`
+	FinishedAt  *time.Time `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (Job) TableName() string {
+	return "scheduled_jobs"
+}
+
+// NewJob builds a pending job with payload encoded as JSON.
+func NewJob(kind, dedupKey string, runAt time.Time, payload any) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Job{
+		Kind:        kind,
+		DedupKey:    dedupKey,
+		Payload:     string(data),
+		Status:      StatusPending,
+		RunAt:       runAt,
+		MaxAttempts: DefaultMaxAttempts,
+	}, nil
+}
+
+// Decode unmarshals the job's JSON payload into v.
+func (j *Job) Decode(v any) error {
+	return json.Unmarshal([]byte(j.Payload), v)
+}
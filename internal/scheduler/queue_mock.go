@@ -0,0 +1,14 @@
+package scheduler
+
+import "github.com/stretchr/testify/mock"
+
+type QueueMock struct {
+	mock.Mock
+}
+
+var _ Queue = (*QueueMock)(nil)
+
+func (m *QueueMock) Enqueue(job *Job) (bool, error) {
+	args := m.Called(job)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,181 @@
+// Package scheduler runs background jobs stored in the database.
+//
+// Jobs are polled from the scheduled_jobs table, leased to one worker at a
+// time and retried with backoff when their handler fails. Any number of
+// server replicas can run a scheduler against the same database.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"taskflow/pkg"
+)
+
+// KindPurge is the recurring job that deletes old finished jobs.
+const KindPurge = "scheduler.purge"
+
+// Handler runs one job. Returning an error schedules a retry.
+type Handler func(ctx context.Context, job *Job) error
+
+type Config struct {
+	Enabled      bool
+	WorkerID     string
+	PollInterval time.Duration
+	// Lease is how long a worker owns a job. Handlers get half of it, so
+	// a job is finished or abandoned before another worker can take it.
+	Lease     time.Duration
+	BatchSize int
+	// Retention is how long finished jobs are kept before being purged.
+	Retention time.Duration
+}
+
+func LoadConfigFromEnv() Config {
+	host, _ := os.Hostname()
+	return Config{
+		Enabled:      pkg.GetEnv("SCHEDULER_ENABLED", "true") == "true",
+		WorkerID:     pkg.GetEnv("SCHEDULER_WORKER_ID", fmt.Sprintf("%s-%d", host, os.Getpid())),
+		PollInterval: pkg.DurationEnv("SCHEDULER_POLL_INTERVAL", 5*time.Second),
+		Lease:        pkg.DurationEnv("SCHEDULER_LEASE", 2*time.Minute),
+		BatchSize:    pkg.IntEnv("SCHEDULER_BATCH_SIZE", 20),
+		Retention:    pkg.DurationEnv("SCHEDULER_RETENTION", 30*24*time.Hour),
+	}
+}
+
+type recurring struct {
+	kind     string
+	interval time.Duration
+}
+
+type Scheduler struct {
+	store     *Store
+	cfg       Config
+	handlers  map[string]Handler
+	recurring []recurring
+	now       func() time.Time
+}
+
+func New(store *Store, cfg Config) *Scheduler {
+	s := &Scheduler{
+		store:    store,
+		cfg:      cfg,
+		handlers: map[string]Handler{},
+		now:      time.Now,
+	}
+	s.Every(KindPurge, time.Hour, func(ctx context.Context, job *Job) error {
+		n, err := store.Purge(s.now().Add(-cfg.Retention))
+		if n > 0 {
+			log.Printf("scheduler: purged %d finished jobs", n)
+		}
+		return err
+	})
+	return s
+}
+
+// Handle registers the handler for jobs of the given kind.
+func (s *Scheduler) Handle(kind string, h Handler) {
+	s.handlers[kind] = h
+}
+
+// Every runs h once per interval. Each replica enqueues the run for the
+// current interval and the dedup key keeps only one of them.
+func (s *Scheduler) Every(kind string, interval time.Duration, h Handler) {
+	s.Handle(kind, h)
+	s.recurring = append(s.recurring, recurring{kind: kind, interval: interval})
+}
+
+// Start polls for jobs until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.cfg.PollInterval)
+		defer ticker.Stop()
+		for {
+			if _, err := s.RunOnce(ctx); err != nil {
+				log.Printf("scheduler: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// RunOnce enqueues due recurring jobs, then leases and runs one batch. It
+// returns how many jobs ran.
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	now := s.now()
+	for _, r := range s.recurring {
+		slot := now.Truncate(r.interval)
+		job, err := NewJob(r.kind, fmt.Sprintf("%s:%d", r.kind, slot.Unix()), slot, nil)
+		if err != nil {
+			return 0, err
+		}
+		// The next interval runs it again, so a failed run is not retried.
+		job.MaxAttempts = 1
+		if _, err := s.store.Enqueue(job); err != nil {
+			return 0, err
+		}
+	}
+
+	jobs, err := s.store.Lease(s.cfg.WorkerID, now, s.cfg.Lease, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range jobs {
+		if ctx.Err() != nil {
+			break
+		}
+		s.run(ctx, &jobs[i])
+	}
+	return len(jobs), nil
+}
+
+func (s *Scheduler) run(ctx context.Context, job *Job) {
+	h, ok := s.handlers[job.Kind]
+	if !ok {
+		s.report(job, s.store.Fail(job, s.now(), fmt.Errorf("no handler for job kind %q", job.Kind)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Lease/2)
+	defer cancel()
+	err := safeRun(ctx, h, job)
+	now := s.now()
+	if err == nil {
+		s.report(job, s.store.Complete(job, now))
+		return
+	}
+	log.Printf("scheduler: job %d (%s) attempt %d failed: %v", job.ID, job.Kind, job.Attempts, err)
+	s.report(job, s.store.Retry(job, now, now.Add(Backoff(job.Attempts)), err))
+}
+
+func (s *Scheduler) report(job *Job, err error) {
+	if err != nil {
+		log.Printf("scheduler: job %d (%s): %v", job.ID, job.Kind, err)
+	}
+}
+
+// safeRun turns a panicking handler into a failed attempt.
+func safeRun(ctx context.Context, h Handler, job *Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return h(ctx, job)
+}
+
+// Backoff returns the delay before retrying after the given attempt:
+// 30s, 1m, 2m, ... up to an hour.
+func Backoff(attempt int) time.Duration {
+	d := 30 * time.Second
+	for i := 1; i < attempt && d < time.Hour; i++ {
+		d *= 2
+	}
+	return min(d, time.Hour)
+}
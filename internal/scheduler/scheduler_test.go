@@ -0,0 +1,229 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent), // Disable logs during tests
+	})
+	require.NoError(t, err)
+
+	// Every connection to :memory: is a separate database.
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, db.AutoMigrate(&Job{}))
+	return db
+}
+
+func testConfig(worker string) Config {
+	return Config{WorkerID: worker, PollInterval: time.Second, Lease: time.Minute, BatchSize: 10, Retention: 24 * time.Hour}
+}
+
+var t0 = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+func TestStore_Enqueue(t *testing.T) {
+	store := NewStore(setupTestDB(t))
+
+	job, err := NewJob("mail", "mail:1", t0, map[string]int{"task_id": 1})
+	require.NoError(t, err)
+	added, err := store.Enqueue(job)
+	require.NoError(t, err)
+	assert.True(t, added)
+
+	again, _ := NewJob("mail", "mail:1", t0.Add(time.Hour), nil)
+	added, err = store.Enqueue(again)
+	require.NoError(t, err)
+	assert.False(t, added)
+
+	var payload map[string]int
+	require.NoError(t, job.Decode(&payload))
+	assert.Equal(t, 1, payload["task_id"])
+}
+
+func TestStore_Lease(t *testing.T) {
+	store := NewStore(setupTestDB(t))
+
+	due, _ := NewJob("mail", "due", t0, nil)
+	later, _ := NewJob("mail", "later", t0.Add(time.Hour), nil)
+	for _, j := range []*Job{due, later} {
+		_, err := store.Enqueue(j)
+		require.NoError(t, err)
+	}
+
+	jobs, err := store.Lease("a", t0, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "due", jobs[0].DedupKey)
+	assert.Equal(t, "a", jobs[0].LockedBy)
+	assert.Equal(t, 1, jobs[0].Attempts)
+
+	// Held by a until its lease runs out.
+	jobs, err = store.Lease("b", t0.Add(30*time.Second), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	jobs, err = store.Lease("b", t0.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "b", jobs[0].LockedBy)
+	assert.Equal(t, 2, jobs[0].Attempts)
+
+	// a's lease was taken over, so it can no longer finish the job.
+	stale := jobs[0]
+	stale.LockedBy = "a"
+	assert.ErrorIs(t, store.Complete(&stale, t0), ErrLeaseLost)
+	assert.NoError(t, store.Complete(&jobs[0], t0.Add(2*time.Minute)))
+}
+
+func TestStore_LeaseExpiredOnLastAttempt(t *testing.T) {
+	store := NewStore(setupTestDB(t))
+
+	job, _ := NewJob("mail", "once", t0, nil)
+	job.MaxAttempts = 1
+	_, err := store.Enqueue(job)
+	require.NoError(t, err)
+
+	jobs, err := store.Lease("a", t0, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+
+	// Worker a never reports back.
+	jobs, err = store.Lease("b", t0.Add(2*time.Minute), time.Minute, 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+
+	var got Job
+	require.NoError(t, store.db.First(&got, job.ID).Error)
+	assert.Equal(t, StatusFailed, got.Status)
+}
+
+func TestScheduler_RunOnce(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewStore(db)
+	s := New(store, testConfig("a"))
+	now := t0
+	s.now = func() time.Time { return now }
+
+	calls := map[string]int{}
+	s.Handle("ok", func(ctx context.Context, job *Job) error {
+		calls[job.DedupKey]++
+		return nil
+	})
+	s.Handle("flaky", func(ctx context.Context, job *Job) error {
+		calls[job.DedupKey]++
+		if job.Attempts < 2 {
+			return errors.New("smtp timeout")
+		}
+		return nil
+	})
+	s.Handle("broken", func(ctx context.Context, job *Job) error {
+		calls[job.DedupKey]++
+		panic("boom")
+	})
+
+	for _, j := range []struct{ kind, key string }{{"ok", "ok"}, {"flaky", "flaky"}, {"broken", "broken"}, {"unknown", "unknown"}} {
+		job, _ := NewJob(j.kind, j.key, t0, nil)
+		job.MaxAttempts = 2
+		_, err := store.Enqueue(job)
+		require.NoError(t, err)
+	}
+
+	n, err := s.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 5, n) // the four jobs and the purge
+
+	status := func(key string) Job {
+		var j Job
+		require.NoError(t, db.Where("dedup_key = ?", key).First(&j).Error)
+		return j
+	}
+	assert.Equal(t, StatusDone, status("ok").Status)
+	assert.Equal(t, StatusFailed, status("unknown").Status)
+	flaky := status("flaky")
+	assert.Equal(t, StatusPending, flaky.Status)
+	assert.Equal(t, "smtp timeout", flaky.LastError)
+	assert.True(t, flaky.RunAt.Equal(t0.Add(30*time.Second)))
+	assert.Equal(t, StatusPending, status("broken").Status)
+
+	// Nothing is due until the backoff has passed.
+	now = t0.Add(10 * time.Second)
+	n, err = s.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	now = t0.Add(time.Minute)
+	_, err = s.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, StatusDone, status("flaky").Status)
+	broken := status("broken")
+	assert.Equal(t, StatusFailed, broken.Status)
+	assert.Contains(t, broken.LastError, "panicked")
+	assert.Equal(t, map[string]int{"ok": 1, "flaky": 2, "broken": 2}, calls)
+}
+
+func TestScheduler_EveryRunsOncePerIntervalAcrossReplicas(t *testing.T) {
+	db := setupTestDB(t)
+	runs := 0
+	newReplica := func(worker string) *Scheduler {
+		s := New(NewStore(db), testConfig(worker))
+		s.now = func() time.Time { return t0.Add(20 * time.Second) }
+		s.Every("reminders.plan", time.Minute, func(ctx context.Context, job *Job) error {
+			runs++
+			return nil
+		})
+		return s
+	}
+	a, b := newReplica("a"), newReplica("b")
+
+	_, err := a.RunOnce(context.Background())
+	require.NoError(t, err)
+	_, err = b.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, runs)
+
+	b.now = func() time.Time { return t0.Add(80 * time.Second) }
+	_, err = b.RunOnce(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs)
+}
+
+func TestScheduler_Purge(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewStore(db)
+
+	old, _ := NewJob("ok", "old", t0.Add(-48*time.Hour), nil)
+	_, err := store.Enqueue(old)
+	require.NoError(t, err)
+	jobs, err := store.Lease("a", t0.Add(-48*time.Hour), time.Minute, 10)
+	require.NoError(t, err)
+	require.NoError(t, store.Complete(&jobs[0], t0.Add(-48*time.Hour)))
+
+	s := New(store, testConfig("a"))
+	s.now = func() time.Time { return t0 }
+	_, err = s.RunOnce(context.Background())
+	require.NoError(t, err)
+
+	var n int64
+	require.NoError(t, db.Model(&Job{}).Where("dedup_key = ?", "old").Count(&n).Error)
+	assert.Zero(t, n)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(1))
+	assert.Equal(t, time.Minute, Backoff(2))
+	assert.Equal(t, 4*time.Minute, Backoff(4))
+	assert.Equal(t, time.Hour, Backoff(20))
+}
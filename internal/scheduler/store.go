@@ -0,0 +1,164 @@
+package scheduler
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrLeaseLost is returned when a worker reports on a job whose lease has
+// expired and been taken over by another worker.
+var ErrLeaseLost = errors.New("job lease was lost")
+
+// Queue adds jobs. Producers depend on it rather than on the whole Store.
+type Queue interface {
+	// Enqueue stores job unless a job with the same DedupKey exists, and
+	// reports whether it was added.
+	Enqueue(job *Job) (bool, error)
+}
+
+// Store keeps jobs in the database. Leases are taken with a conditional
+// UPDATE that only succeeds while the job is unclaimed, so several replicas
+// can poll the same table and each job is run by one worker at a time.
+type Store struct {
+	db *gorm.DB
+}
+
+func NewStore(db *gorm.DB) *Store {
+	return &Store{db: db}
+}
+
+// Compile-time check
+var _ Queue = (*Store)(nil)
+
+func (s *Store) Enqueue(job *Job) (bool, error) {
+	if job.Status == "" {
+		job.Status = StatusPending
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = DefaultMaxAttempts
+	}
+	res := s.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedup_key"}},
+		DoNothing: true,
+	}).Create(job)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// Lease claims up to limit due jobs for worker until now+lease and returns
+// them. Jobs whose last lease expired after their final attempt are marked
+// failed first.
+func (s *Store) Lease(worker string, now time.Time, lease time.Duration, limit int) ([]Job, error) {
+	err := s.db.Model(&Job{}).
+		Where("status = ? AND attempts >= max_attempts AND locked_until < ?", StatusPending, now).
+		Updates(map[string]any{
+			"status":       StatusFailed,
+			"last_error":   "lease expired on the last attempt",
+			"locked_by":    "",
+			"locked_until": nil,
+			"finished_at":  now,
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int64
+	err = s.db.Model(&Job{}).
+		Where("status = ? AND run_at <= ? AND (locked_until IS NULL OR locked_until < ?)", StatusPending, now, now).
+		Order("run_at, id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	until := now.Add(lease)
+	claimed := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		// Another worker may have claimed the job since it was selected;
+		// then no row matches and the job is skipped.
+		res := s.db.Model(&Job{}).
+			Where("id = ? AND status = ? AND (locked_until IS NULL OR locked_until < ?)", id, StatusPending, now).
+			Updates(map[string]any{
+				"locked_by":    worker,
+				"locked_until": until,
+				"attempts":     gorm.Expr("attempts + 1"),
+			})
+		if res.Error != nil {
+			return nil, res.Error
+		}
+		if res.RowsAffected == 1 {
+			claimed = append(claimed, id)
+		}
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	var jobs []Job
+	if err := s.db.Where("id IN ?", claimed).Order("run_at, id").Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Complete marks a leased job as done.
+func (s *Store) Complete(job *Job, now time.Time) error {
+	return s.finish(job, map[string]any{
+		"status":       StatusDone,
+		"locked_by":    "",
+		"locked_until": nil,
+		"finished_at":  now,
+	})
+}
+
+// Retry releases a leased job to run again at runAt, or marks it failed
+// when it has no attempts left.
+func (s *Store) Retry(job *Job, now, runAt time.Time, cause error) error {
+	if job.Attempts >= job.MaxAttempts {
+		return s.Fail(job, now, cause)
+	}
+	return s.finish(job, map[string]any{
+		"run_at":       runAt,
+		"last_error":   cause.Error(),
+		"locked_by":    "",
+		"locked_until": nil,
+	})
+}
+
+// Fail marks a leased job as failed for good.
+func (s *Store) Fail(job *Job, now time.Time, cause error) error {
+	return s.finish(job, map[string]any{
+		"status":       StatusFailed,
+		"last_error":   cause.Error(),
+		"locked_by":    "",
+		"locked_until": nil,
+		"finished_at":  now,
+	})
+}
+
+// Purge deletes jobs that finished before the given time. Their dedup keys
+// become free again.
+func (s *Store) Purge(before time.Time) (int64, error) {
+	res := s.db.Where("finished_at < ?", before).Delete(&Job{})
+	return res.RowsAffected, res.Error
+}
+
+// finish updates a job this worker still holds the lease on.
+func (s *Store) finish(job *Job, updates map[string]any) error {
+	res := s.db.Model(&Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", job.ID, StatusPending, job.LockedBy).
+		Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package accesstoken_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"taskflow/internal/domain/accesstoken"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_accesstoken"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxTokens is how many access tokens a user may have.
+	MaxTokens = 20
+
+	// tokenPrefix marks TaskFlow tokens, so leaked ones are easy to spot.
+	tokenPrefix = "tfp_"
+	// prefixLength is how much of a token is kept to tell tokens apart.
+	prefixLength = len(tokenPrefix) + 6
+	// touchInterval limits how often last_used_at is written, since
+	// calendar apps authenticate every request.
+	touchInterval = time.Minute
+)
+
+var (
+	ErrInvalidUser   = errors.New("invalid user")
+	ErrTooManyTokens = errors.New("too many access tokens")
+	ErrInvalidToken  = errors.New("invalid or expired access token")
+	ErrExpiresInPast = errors.New("expires_at must be in the future")
+)
+
+type AccessTokenService struct {
+	repo gorm_accesstoken.AccessTokenRepositoryInterface
+	now  func() time.Time
+}
+
+func NewAccessTokenService(repo gorm_accesstoken.AccessTokenRepositoryInterface) *AccessTokenService {
+	return &AccessTokenService{repo: repo, now: time.Now}
+}
+
+var _ AccessTokenServiceInterface = (*AccessTokenService)(nil)
+
+// CreateToken creates a token and returns its secret, which is not stored
+// and can't be shown again.
+func (s *AccessTokenService) CreateToken(userID int, req *dto.CreateAccessTokenRequest) (dto.CreateAccessTokenResponse, error) {
+	if userID == 0 {
+		return dto.CreateAccessTokenResponse{}, ErrInvalidUser
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(s.now()) {
+		return dto.CreateAccessTokenResponse{}, ErrExpiresInPast
+	}
+
+	existing, err := s.repo.List(userID)
+	if err != nil {
+		return dto.CreateAccessTokenResponse{}, err
+	}
+	if len(existing) >= MaxTokens {
+		return dto.CreateAccessTokenResponse{}, ErrTooManyTokens
+	}
+
+	random, err := randomHex(20)
+	if err != nil {
+		return dto.CreateAccessTokenResponse{}, err
+	}
+	secret := tokenPrefix + random
+	t := accesstoken.Token{
+		UserID:    userID,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    secret[:prefixLength],
+		Hash:      hashToken(secret),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if err := s.repo.Create(&t); err != nil {
+		return dto.CreateAccessTokenResponse{}, err
+	}
+	return dto.CreateAccessTokenResponse{AccessTokenResponse: toTokenResponse(t), Token: secret}, nil
+}
+
+func (s *AccessTokenService) ListTokens(userID int) (dto.ListAccessTokensResponse, error) {
+	if userID == 0 {
+		return dto.ListAccessTokensResponse{}, ErrInvalidUser
+	}
+
+	tokens, err := s.repo.List(userID)
+	if err != nil {
+		return dto.ListAccessTokensResponse{}, err
+	}
+	resp := dto.ListAccessTokensResponse{Tokens: make([]dto.AccessTokenResponse, 0, len(tokens))}
+	for _, t := range tokens {
+		resp.Tokens = append(resp.Tokens, toTokenResponse(t))
+	}
+	return resp, nil
+}
+
+// DeleteToken deletes a token, which revokes it.
+func (s *AccessTokenService) DeleteToken(userID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+	return s.repo.Delete(userID, id)
+}
+
+// Authenticate returns the ID of the user the token belongs to and
+// records its use.
+func (s *AccessTokenService) Authenticate(secret string) (int, error) {
+	if !strings.HasPrefix(secret, tokenPrefix) {
+		return 0, ErrInvalidToken
+	}
+	t, err := s.repo.GetByHash(hashToken(secret))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, ErrInvalidToken
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	if t.ExpiresAt != nil && !now.Before(*t.ExpiresAt) {
+		return 0, ErrInvalidToken
+	}
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= touchInterval {
+		if err := s.repo.Touch(t.ID, now); err != nil {
+			return 0, err
+		}
+	}
+	return t.UserID, nil
+}
+
+// HandleEvent removes the tokens of deleted users.
+func (s *AccessTokenService) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	if e.Type != events.TypeUserDeleted {
+		return nil
+	}
+	return s.repo.DeleteUserTokens(e.UserID)
+}
+
+// hashToken is what is stored of a token. The secret is random, so a
+// fast hash is enough.
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func toTokenResponse(t accesstoken.Token) dto.AccessTokenResponse {
+	return dto.AccessTokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		LastUsedAt: t.LastUsedAt,
+		ExpiresAt:  t.ExpiresAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,18 @@
+package accesstoken_service
+
+import (
+	"context"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+)
+
+type AccessTokenServiceInterface interface {
+	CreateToken(userID int, req *dto.CreateAccessTokenRequest) (dto.CreateAccessTokenResponse, error)
+	ListTokens(userID int) (dto.ListAccessTokensResponse, error)
+	DeleteToken(userID int, id int) error
+	// Authenticate returns the ID of the user a token belongs to.
+	Authenticate(secret string) (int, error)
+	// HandleEvent consumes domain events.
+	HandleEvent(ctx context.Context, e *events.Envelope) error
+}
@@ -0,0 +1,41 @@
+package accesstoken_service
+
+import (
+	"context"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AccessTokenServiceMock struct {
+	mock.Mock
+}
+
+var _ AccessTokenServiceInterface = (*AccessTokenServiceMock)(nil)
+
+func (m *AccessTokenServiceMock) CreateToken(userID int, req *dto.CreateAccessTokenRequest) (dto.CreateAccessTokenResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.CreateAccessTokenResponse), args.Error(1)
+}
+
+func (m *AccessTokenServiceMock) ListTokens(userID int) (dto.ListAccessTokensResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.ListAccessTokensResponse), args.Error(1)
+}
+
+func (m *AccessTokenServiceMock) DeleteToken(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *AccessTokenServiceMock) Authenticate(secret string) (int, error) {
+	args := m.Called(secret)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *AccessTokenServiceMock) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
@@ -0,0 +1,146 @@
+package accesstoken_service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/accesstoken"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_accesstoken"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var now = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+func setup() (*AccessTokenService, *gorm_accesstoken.AccessTokenRepoMock) {
+	repo := new(gorm_accesstoken.AccessTokenRepoMock)
+	s := NewAccessTokenService(repo)
+	s.now = func() time.Time { return now }
+	return s, repo
+}
+
+func TestAccessTokenService_CreateToken(t *testing.T) {
+	s, repo := setup()
+	repo.On("List", 1).Return([]accesstoken.Token{}, nil)
+	var stored *accesstoken.Token
+	repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*accesstoken.Token)
+		stored.ID = 3
+	}).Return(nil)
+
+	resp, err := s.CreateToken(1, &dto.CreateAccessTokenRequest{Name: " Phone "})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.ID)
+	assert.Equal(t, "Phone", resp.Name)
+	assert.True(t, strings.HasPrefix(resp.Token, "tfp_"))
+	assert.Len(t, resp.Token, 44)
+	assert.Equal(t, resp.Token[:10], resp.Prefix)
+
+	require.NotNil(t, stored)
+	assert.Equal(t, hashToken(resp.Token), stored.Hash, "only the hash is stored")
+	assert.NotContains(t, stored.Hash, resp.Token[4:])
+}
+
+func TestAccessTokenService_CreateToken_Errors(t *testing.T) {
+	s, repo := setup()
+	repo.On("List", 1).Return(make([]accesstoken.Token, MaxTokens), nil)
+
+	_, err := s.CreateToken(1, &dto.CreateAccessTokenRequest{Name: "Phone"})
+	assert.ErrorIs(t, err, ErrTooManyTokens)
+
+	past := now.Add(-time.Hour)
+	_, err = s.CreateToken(1, &dto.CreateAccessTokenRequest{Name: "Phone", ExpiresAt: &past})
+	assert.ErrorIs(t, err, ErrExpiresInPast)
+
+	_, err = s.CreateToken(0, &dto.CreateAccessTokenRequest{Name: "Phone"})
+	assert.ErrorIs(t, err, ErrInvalidUser)
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestAccessTokenService_Authenticate(t *testing.T) {
+	const secret = "tfp_0123456789abcdef0123456789abcdef01234567"
+	recent := now.Add(-10 * time.Second)
+	expired := now.Add(-time.Second)
+
+	tests := []struct {
+		name      string
+		secret    string
+		token     *accesstoken.Token
+		wantUser  int
+		wantErr   error
+		wantTouch bool
+	}{
+		{
+			name:      "valid",
+			secret:    secret,
+			token:     &accesstoken.Token{ID: 3, UserID: 1},
+			wantUser:  1,
+			wantTouch: true,
+		},
+		{
+			name:     "recently used",
+			secret:   secret,
+			token:    &accesstoken.Token{ID: 3, UserID: 1, LastUsedAt: &recent},
+			wantUser: 1,
+		},
+		{
+			name:    "expired",
+			secret:  secret,
+			token:   &accesstoken.Token{ID: 3, UserID: 1, ExpiresAt: &expired},
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "unknown",
+			secret:  secret,
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "not a token",
+			secret:  "hunter2",
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := setup()
+			if tt.token != nil {
+				repo.On("GetByHash", hashToken(tt.secret)).Return(tt.token, nil)
+			} else {
+				repo.On("GetByHash", mock.Anything).Return((*accesstoken.Token)(nil), gorm.ErrRecordNotFound)
+			}
+			repo.On("Touch", 3, now).Return(nil)
+
+			userID, err := s.Authenticate(tt.secret)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantUser, userID)
+			}
+			if tt.wantTouch {
+				repo.AssertCalled(t, "Touch", 3, now)
+			} else {
+				repo.AssertNotCalled(t, "Touch", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestAccessTokenService_HandleEvent(t *testing.T) {
+	s, repo := setup()
+	repo.On("DeleteUserTokens", 1).Return(nil)
+
+	rec, err := events.NewRecord(events.UserDeleted{UserID: 1}, now)
+	require.NoError(t, err)
+	env := rec.Envelope()
+	require.NoError(t, s.HandleEvent(context.Background(), &env))
+	repo.AssertCalled(t, "DeleteUserTokens", 1)
+}
@@ -0,0 +1,294 @@
+package attachment_service
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskflow/internal/domain/attachment"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_attachment"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/pkg/blobstore"
+	"taskflow/pkg/signedurl"
+)
+
+const (
+	DefaultMaxSize = 10 << 20 // 10 MiB
+	DefaultURLTTL  = 15 * time.Minute
+	sniffLen       = 512
+)
+
+// DefaultAllowedTypes lists the sniffed MIME types accepted for upload.
+// Office documents are zip containers and sniff as application/zip.
+var DefaultAllowedTypes = []string{
+	"image/png",
+	"image/jpeg",
+	"image/gif",
+	"image/webp",
+	"application/pdf",
+	"application/zip",
+	"text/plain",
+}
+
+var (
+	ErrInvalidUser       = errors.New("invalid user")
+	ErrEmptyFile         = errors.New("file is empty")
+	ErrFileTooLarge      = errors.New("file exceeds the maximum allowed size")
+	ErrUnsupportedType   = errors.New("file type is not allowed")
+	ErrInvalidDownload   = errors.New("download link is invalid or has expired")
+	ErrAttachmentMissing = errors.New("attachment not found")
+)
+
+type AttachmentService struct {
+	repo         gorm_attachment.AttachmentRepositoryInterface
+	taskRepo     gorm_task.TaskRepositoryInterface
+	blobs        blobstore.BlobStore
+	signer       *signedurl.Signer
+	MaxSize      int64
+	AllowedTypes []string
+	URLTTL       time.Duration
+}
+
+func NewAttachmentService(
+	repo gorm_attachment.AttachmentRepositoryInterface,
+	taskRepo gorm_task.TaskRepositoryInterface,
+	blobs blobstore.BlobStore,
+	signer *signedurl.Signer,
+) *AttachmentService {
+	return &AttachmentService{
+		repo:         repo,
+		taskRepo:     taskRepo,
+		blobs:        blobs,
+		signer:       signer,
+		MaxSize:      DefaultMaxSize,
+		AllowedTypes: DefaultAllowedTypes,
+		URLTTL:       DefaultURLTTL,
+	}
+}
+
+var _ AttachmentServiceInterface = (*AttachmentService)(nil)
+
+func (s *AttachmentService) Upload(userID int, taskID int, fileName string, size int64, r io.Reader) (dto.AttachmentResponse, error) {
+	if userID == 0 {
+		return dto.AttachmentResponse{}, ErrInvalidUser
+	}
+	if size == 0 {
+		return dto.AttachmentResponse{}, ErrEmptyFile
+	}
+	if size > s.MaxSize {
+		return dto.AttachmentResponse{}, ErrFileTooLarge
+	}
+
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return dto.AttachmentResponse{}, err
+	}
+
+	// Trust the bytes, not the client-supplied Content-Type.
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return dto.AttachmentResponse{}, err
+	}
+	head = head[:n]
+
+	contentType := sniff(head)
+	if !s.allowed(contentType) {
+		return dto.AttachmentResponse{}, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+
+	key, err := storageKey(taskID)
+	if err != nil {
+		return dto.AttachmentResponse{}, err
+	}
+
+	body := io.MultiReader(bytes.NewReader(head), r)
+	if err := s.blobs.Put(key, body, size, contentType); err != nil {
+		return dto.AttachmentResponse{}, fmt.Errorf("failed to store file: %w", err)
+	}
+
+	a := attachment.Attachment{
+		TaskID:      taskID,
+		UserID:      userID,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  key,
+	}
+	if err := s.repo.Create(&a); err != nil {
+		_ = s.blobs.Delete(key)
+		return dto.AttachmentResponse{}, err
+	}
+
+	return s.toResponse(a), nil
+}
+
+func (s *AttachmentService) List(userID int, taskID int) (dto.ListAttachmentsResponse, error) {
+	if userID == 0 {
+		return dto.ListAttachmentsResponse{}, ErrInvalidUser
+	}
+
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return dto.ListAttachmentsResponse{}, err
+	}
+
+	attachments, err := s.repo.ListByTask(taskID)
+	if err != nil {
+		return dto.ListAttachmentsResponse{}, err
+	}
+
+	resp := dto.ListAttachmentsResponse{Attachments: make([]dto.AttachmentResponse, 0, len(attachments))}
+	for _, a := range attachments {
+		resp.Attachments = append(resp.Attachments, s.toResponse(a))
+	}
+	return resp, nil
+}
+
+func (s *AttachmentService) Delete(userID int, taskID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return err
+	}
+
+	a, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if a.TaskID != taskID {
+		return ErrAttachmentMissing
+	}
+
+	if err := s.blobs.Delete(a.StorageKey); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return s.repo.Delete(id)
+}
+
+// Open verifies a signed download link and opens the attachment contents.
+func (s *AttachmentService) Open(id int, expires int64, signature string) (dto.AttachmentResponse, io.ReadCloser, error) {
+	if err := s.signer.Verify(resource(id), expires, signature); err != nil {
+		return dto.AttachmentResponse{}, nil, ErrInvalidDownload
+	}
+
+	a, err := s.repo.GetByID(id)
+	if err != nil {
+		return dto.AttachmentResponse{}, nil, err
+	}
+
+	rc, err := s.blobs.Get(a.StorageKey)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			return dto.AttachmentResponse{}, nil, ErrAttachmentMissing
+		}
+		return dto.AttachmentResponse{}, nil, err
+	}
+
+	return s.toResponse(*a), rc, nil
+}
+
+// StorageKeys returns where the attachments of the given tasks are stored.
+// The caller is responsible for checking that the user owns the tasks.
+func (s *AttachmentService) StorageKeys(taskIDs []int) ([]string, error) {
+	var keys []string
+	for _, id := range taskIDs {
+		attachments, err := s.repo.ListByTask(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range attachments {
+			keys = append(keys, a.StorageKey)
+		}
+	}
+	return keys, nil
+}
+
+// DeleteBlobs removes stored files whose attachment records are gone. A
+// file that can't be removed is logged and left behind: the records are
+// already deleted, so there is nothing to roll back.
+func (s *AttachmentService) DeleteBlobs(keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(key); err != nil {
+			log.Printf("attachments: leaked file %q: %v", key, err)
+		}
+	}
+}
+
+func (s *AttachmentService) allowed(contentType string) bool {
+	for _, t := range s.AllowedTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// Resign gives a previously returned attachment a fresh download URL.
+func (s *AttachmentService) Resign(resp dto.AttachmentResponse) dto.AttachmentResponse {
+	resp.DownloadURL, resp.ExpiresAt = s.sign(resp.ID)
+	return resp
+}
+
+func (s *AttachmentService) toResponse(a attachment.Attachment) dto.AttachmentResponse {
+	downloadURL, expiresAt := s.sign(a.ID)
+
+	return dto.AttachmentResponse{
+		ID:          a.ID,
+		TaskID:      a.TaskID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		Size:        a.Size,
+		DownloadURL: downloadURL,
+		ExpiresAt:   expiresAt,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+// sign returns a download URL for the attachment and when it expires.
+func (s *AttachmentService) sign(id int) (string, time.Time) {
+	expires, signature := s.signer.Sign(resource(id), s.URLTTL)
+	return fmt.Sprintf("/attachments/%d/download?expires=%d&signature=%s", id, expires, signature), time.Unix(expires, 0).UTC()
+}
+
+func resource(id int) string {
+	return "attachments/" + strconv.Itoa(id)
+}
+
+func sniff(head []byte) string {
+	mediaType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "application/octet-stream"
+	}
+	return mediaType
+}
+
+func storageKey(taskID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("tasks/%d/%s", taskID, hex.EncodeToString(b)), nil
+}
+
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
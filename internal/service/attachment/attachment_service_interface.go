@@ -0,0 +1,16 @@
+package attachment_service
+
+import (
+	"io"
+	"taskflow/internal/dto"
+)
+
+type AttachmentServiceInterface interface {
+	Upload(userID int, taskID int, fileName string, size int64, r io.Reader) (dto.AttachmentResponse, error)
+	List(userID int, taskID int) (dto.ListAttachmentsResponse, error)
+	Delete(userID int, taskID int, id int) error
+	Open(id int, expires int64, signature string) (dto.AttachmentResponse, io.ReadCloser, error)
+	StorageKeys(taskIDs []int) ([]string, error)
+	DeleteBlobs(keys []string)
+	Resign(resp dto.AttachmentResponse) dto.AttachmentResponse
+}
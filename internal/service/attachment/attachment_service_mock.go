@@ -0,0 +1,52 @@
+package attachment_service
+
+import (
+	"io"
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type AttachmentServiceMock struct {
+	mock.Mock
+}
+
+var _ AttachmentServiceInterface = (*AttachmentServiceMock)(nil)
+
+func (m *AttachmentServiceMock) Upload(userID int, taskID int, fileName string, size int64, r io.Reader) (dto.AttachmentResponse, error) {
+	args := m.Called(userID, taskID, fileName, size, r)
+	return args.Get(0).(dto.AttachmentResponse), args.Error(1)
+}
+
+func (m *AttachmentServiceMock) List(userID int, taskID int) (dto.ListAttachmentsResponse, error) {
+	args := m.Called(userID, taskID)
+	return args.Get(0).(dto.ListAttachmentsResponse), args.Error(1)
+}
+
+func (m *AttachmentServiceMock) Delete(userID int, taskID int, id int) error {
+	args := m.Called(userID, taskID, id)
+	return args.Error(0)
+}
+
+func (m *AttachmentServiceMock) Open(id int, expires int64, signature string) (dto.AttachmentResponse, io.ReadCloser, error) {
+	args := m.Called(id, expires, signature)
+	var rc io.ReadCloser
+	if r := args.Get(1); r != nil {
+		rc = r.(io.ReadCloser)
+	}
+	return args.Get(0).(dto.AttachmentResponse), rc, args.Error(2)
+}
+
+func (m *AttachmentServiceMock) StorageKeys(taskIDs []int) ([]string, error) {
+	args := m.Called(taskIDs)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *AttachmentServiceMock) DeleteBlobs(keys []string) {
+	m.Called(keys)
+}
+
+func (m *AttachmentServiceMock) Resign(resp dto.AttachmentResponse) dto.AttachmentResponse {
+	args := m.Called(resp)
+	return args.Get(0).(dto.AttachmentResponse)
+}
@@ -0,0 +1,170 @@
+package attachment_service
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"strconv"
+	"taskflow/internal/domain/attachment"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_attachment"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/pkg/blobstore"
+	"taskflow/pkg/signedurl"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newTestService(t *testing.T) (*AttachmentService, *gorm_attachment.AttachmentRepoMock, *gorm_task.TaskRepoMock, *blobstore.LocalStore) {
+	store, err := blobstore.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	repo := new(gorm_attachment.AttachmentRepoMock)
+	taskRepo := new(gorm_task.TaskRepoMock)
+	return NewAttachmentService(repo, taskRepo, store, signedurl.NewSigner("secret")), repo, taskRepo, store
+}
+
+func TestAttachmentService_Upload(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   []byte
+		size      int64
+		setupMock func(repo *gorm_attachment.AttachmentRepoMock, taskRepo *gorm_task.TaskRepoMock)
+		wantErr   error
+		wantType  string
+	}{
+		{
+			name:    "success - png",
+			content: pngHeader,
+			setupMock: func(repo *gorm_attachment.AttachmentRepoMock, taskRepo *gorm_task.TaskRepoMock) {
+				taskRepo.On("GetByID", 1, 10).Return(&task.Task{ID: 10, UserID: 1}, nil)
+				repo.On("Create", mock.MatchedBy(func(a *attachment.Attachment) bool {
+					return a.TaskID == 10 && a.FileName == "shot.png" && a.ContentType == "image/png"
+				})).Run(func(args mock.Arguments) {
+					args.Get(0).(*attachment.Attachment).ID = 7
+				}).Return(nil)
+			},
+			wantType: "image/png",
+		},
+		{
+			name:    "failure - html is rejected even if named png",
+			content: []byte("<html><script>alert(1)</script></html>"),
+			setupMock: func(repo *gorm_attachment.AttachmentRepoMock, taskRepo *gorm_task.TaskRepoMock) {
+				taskRepo.On("GetByID", 1, 10).Return(&task.Task{ID: 10, UserID: 1}, nil)
+			},
+			wantErr: ErrUnsupportedType,
+		},
+		{
+			name:      "failure - too large",
+			content:   pngHeader,
+			size:      DefaultMaxSize + 1,
+			setupMock: func(repo *gorm_attachment.AttachmentRepoMock, taskRepo *gorm_task.TaskRepoMock) {},
+			wantErr:   ErrFileTooLarge,
+		},
+		{
+			name:    "failure - task not visible",
+			content: pngHeader,
+			setupMock: func(repo *gorm_attachment.AttachmentRepoMock, taskRepo *gorm_task.TaskRepoMock) {
+				taskRepo.On("GetByID", 1, 10).Return((*task.Task)(nil), gorm.ErrRecordNotFound)
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo, taskRepo, _ := newTestService(t)
+			tt.setupMock(repo, taskRepo)
+
+			size := tt.size
+			if size == 0 {
+				size = int64(len(tt.content))
+			}
+
+			got, err := s.Upload(1, 10, "../../shot.png", size, bytes.NewReader(tt.content))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantType, got.ContentType)
+				assert.Contains(t, got.DownloadURL, "/attachments/7/download?expires=")
+			}
+			repo.AssertExpectations(t)
+			taskRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAttachmentService_Open(t *testing.T) {
+	s, repo, _, store := newTestService(t)
+	require.NoError(t, store.Put("tasks/10/abc", bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"))
+
+	a := attachment.Attachment{ID: 7, TaskID: 10, FileName: "shot.png", ContentType: "image/png", StorageKey: "tasks/10/abc"}
+	repo.On("GetByID", 7).Return(&a, nil)
+
+	link, err := url.Parse(s.toResponse(a).DownloadURL)
+	require.NoError(t, err)
+	expires, _ := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+	signature := link.Query().Get("signature")
+
+	t.Run("valid signature", func(t *testing.T) {
+		meta, rc, err := s.Open(7, expires, signature)
+		require.NoError(t, err)
+		defer rc.Close()
+
+		data, _ := io.ReadAll(rc)
+		assert.Equal(t, pngHeader, data)
+		assert.Equal(t, "shot.png", meta.FileName)
+	})
+
+	t.Run("tampered signature", func(t *testing.T) {
+		_, _, err := s.Open(7, expires+60, signature)
+		assert.ErrorIs(t, err, ErrInvalidDownload)
+	})
+}
+
+func TestAttachmentService_Resign(t *testing.T) {
+	s, _, _, _ := newTestService(t)
+	stale := dto.AttachmentResponse{ID: 7, FileName: "shot.png", DownloadURL: "/attachments/7/download?expires=1&signature=x", ExpiresAt: time.Unix(1, 0).UTC()}
+
+	got := s.Resign(stale)
+
+	assert.Equal(t, "shot.png", got.FileName)
+	assert.True(t, got.ExpiresAt.After(time.Now()))
+	link, err := url.Parse(got.DownloadURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/attachments/7/download", link.Path)
+	assert.Equal(t, strconv.FormatInt(got.ExpiresAt.Unix(), 10), link.Query().Get("expires"))
+}
+
+func TestAttachmentService_StorageKeys(t *testing.T) {
+	s, repo, _, _ := newTestService(t)
+	repo.On("ListByTask", 10).Return([]attachment.Attachment{{ID: 1, TaskID: 10, StorageKey: "tasks/10/a"}, {ID: 2, TaskID: 10, StorageKey: "tasks/10/b"}}, nil)
+	repo.On("ListByTask", 11).Return([]attachment.Attachment{}, nil)
+
+	keys, err := s.StorageKeys([]int{10, 11})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tasks/10/a", "tasks/10/b"}, keys)
+}
+
+func TestAttachmentService_DeleteBlobs(t *testing.T) {
+	s, _, _, store := newTestService(t)
+	require.NoError(t, store.Put("tasks/10/a", bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"))
+	require.NoError(t, store.Put("tasks/10/b", bytes.NewReader(pngHeader), int64(len(pngHeader)), "image/png"))
+
+	// The invalid key fails; the files after it are still removed.
+	s.DeleteBlobs([]string{"tasks/10/a", "../outside", "tasks/10/b"})
+
+	for _, key := range []string{"tasks/10/a", "tasks/10/b"} {
+		_, err := store.Get(key)
+		assert.ErrorIs(t, err, blobstore.ErrNotFound, key)
+	}
+}
@@ -0,0 +1,120 @@
+package board_service
+
+import (
+	"errors"
+
+	"taskflow/internal/common"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_board"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	task_service "taskflow/internal/service/task"
+)
+
+var (
+	ErrInvalidUser   = errors.New("invalid user")
+	ErrUnknownColumn = errors.New("unknown column: must be pending, in-progress or completed")
+)
+
+// BoardService presents a list (the inbox or a project) as a kanban board
+// with one column per task status.
+type BoardService struct {
+	boards   gorm_board.BoardRepositoryInterface
+	tasks    gorm_task.TaskRepositoryInterface
+	projects gorm_project.ProjectRepositoryInterface
+}
+
+func NewBoardService(boards gorm_board.BoardRepositoryInterface, tasks gorm_task.TaskRepositoryInterface, projects gorm_project.ProjectRepositoryInterface) *BoardService {
+	return &BoardService{boards: boards, tasks: tasks, projects: projects}
+}
+
+var _ BoardServiceInterface = (*BoardService)(nil)
+
+// GetBoard returns one page of every column, or only of the status column
+// when status is set.
+func (s *BoardService) GetBoard(userID int, projectID *int, status string, page int, limit int) (dto.BoardResponse, error) {
+	if userID == 0 {
+		return dto.BoardResponse{}, ErrInvalidUser
+	}
+	if status != "" && !task.ValidStatus(status) {
+		return dto.BoardResponse{}, ErrUnknownColumn
+	}
+	if err := s.checkProject(userID, projectID); err != nil {
+		return dto.BoardResponse{}, err
+	}
+
+	settings, err := s.boards.ListColumns(userID, projectID)
+	if err != nil {
+		return dto.BoardResponse{}, err
+	}
+	limits := make(map[string]int, len(settings))
+	for _, c := range settings {
+		limits[c.Status] = c.WIPLimit
+	}
+
+	resp := dto.BoardResponse{ProjectID: projectID, Columns: []dto.BoardColumnResponse{}}
+	for _, st := range task.Statuses {
+		if status != "" && st != status {
+			continue
+		}
+		column, err := s.column(userID, projectID, st, limits[st], page, limit)
+		if err != nil {
+			return dto.BoardResponse{}, err
+		}
+		resp.Columns = append(resp.Columns, column)
+	}
+	return resp, nil
+}
+
+// SetWIPLimit changes a column's WIP limit and returns the column's first page.
+// A limit of 0 removes it. Columns already over the new limit keep their tasks.
+func (s *BoardService) SetWIPLimit(userID int, status string, req *dto.SetWIPLimitRequest) (dto.BoardColumnResponse, error) {
+	if userID == 0 {
+		return dto.BoardColumnResponse{}, ErrInvalidUser
+	}
+	if !task.ValidStatus(status) {
+		return dto.BoardColumnResponse{}, ErrUnknownColumn
+	}
+	if err := s.checkProject(userID, req.ProjectID); err != nil {
+		return dto.BoardColumnResponse{}, err
+	}
+
+	if err := s.boards.SetWIPLimit(userID, req.ProjectID, status, req.WIPLimit); err != nil {
+		return dto.BoardColumnResponse{}, err
+	}
+	return s.column(userID, req.ProjectID, status, req.WIPLimit, 1, common.DefaultPageSize)
+}
+
+func (s *BoardService) column(userID int, projectID *int, status string, wipLimit int, page int, limit int) (dto.BoardColumnResponse, error) {
+	count, err := s.tasks.CountColumn(userID, projectID, status)
+	if err != nil {
+		return dto.BoardColumnResponse{}, err
+	}
+	tasks, err := s.tasks.ListColumn(userID, projectID, status, (page-1)*limit, limit)
+	if err != nil {
+		return dto.BoardColumnResponse{}, err
+	}
+
+	column := dto.BoardColumnResponse{
+		Status:   status,
+		WIPLimit: wipLimit,
+		Count:    count,
+		Tasks:    make([]dto.GetTaskResponse, 0, len(tasks)),
+		Page:     page,
+		Limit:    limit,
+	}
+	for _, t := range tasks {
+		column.Tasks = append(column.Tasks, task_service.ToTaskResponse(t))
+	}
+	return column, nil
+}
+
+// checkProject makes sure a project board belongs to the user.
+func (s *BoardService) checkProject(userID int, projectID *int) error {
+	if projectID == nil {
+		return nil
+	}
+	_, err := s.projects.GetByID(userID, *projectID)
+	return err
+}
@@ -0,0 +1,8 @@
+package board_service
+
+import "taskflow/internal/dto"
+
+type BoardServiceInterface interface {
+	GetBoard(userID int, projectID *int, status string, page int, limit int) (dto.BoardResponse, error)
+	SetWIPLimit(userID int, status string, req *dto.SetWIPLimitRequest) (dto.BoardColumnResponse, error)
+}
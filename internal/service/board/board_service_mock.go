@@ -0,0 +1,23 @@
+package board_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type BoardServiceMock struct {
+	mock.Mock
+}
+
+var _ BoardServiceInterface = (*BoardServiceMock)(nil)
+
+func (m *BoardServiceMock) GetBoard(userID int, projectID *int, status string, page int, limit int) (dto.BoardResponse, error) {
+	args := m.Called(userID, projectID, status, page, limit)
+	return args.Get(0).(dto.BoardResponse), args.Error(1)
+}
+
+func (m *BoardServiceMock) SetWIPLimit(userID int, status string, req *dto.SetWIPLimitRequest) (dto.BoardColumnResponse, error) {
+	args := m.Called(userID, status, req)
+	return args.Get(0).(dto.BoardColumnResponse), args.Error(1)
+}
@@ -0,0 +1,93 @@
+package board_service
+
+import (
+	"taskflow/internal/domain/board"
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_board"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestBoardService_GetBoard(t *testing.T) {
+	boards := new(gorm_board.BoardRepoMock)
+	tasks := new(gorm_task.TaskRepoMock)
+	projects := new(gorm_project.ProjectRepoMock)
+
+	boards.On("ListColumns", 1, (*int)(nil)).Return([]board.Column{{Status: "in-progress", WIPLimit: 3}}, nil)
+	counts := map[string]int64{"in-progress": 12}
+	pages := map[string][]task.Task{
+		"in-progress": {{ID: 7, Task: "Write report", Status: "in-progress", Position: "i"}},
+	}
+	for _, st := range task.Statuses {
+		page := pages[st]
+		if page == nil {
+			page = []task.Task{}
+		}
+		tasks.On("CountColumn", 1, (*int)(nil), st).Return(counts[st], nil)
+		tasks.On("ListColumn", 1, (*int)(nil), st, 10, 10).Return(page, nil)
+	}
+
+	s := NewBoardService(boards, tasks, projects)
+	got, err := s.GetBoard(1, nil, "", 2, 10)
+	require.NoError(t, err)
+
+	require.Len(t, got.Columns, 3)
+	assert.Equal(t, []string{"pending", "in-progress", "completed"},
+		[]string{got.Columns[0].Status, got.Columns[1].Status, got.Columns[2].Status})
+	assert.Equal(t, dto.BoardColumnResponse{
+		Status:   "in-progress",
+		WIPLimit: 3,
+		Count:    12,
+		Tasks:    []dto.GetTaskResponse{{ID: 7, Task: "Write report", Status: "in-progress", Position: "i"}},
+		Page:     2,
+		Limit:    10,
+	}, got.Columns[1])
+	assert.Equal(t, 0, got.Columns[0].WIPLimit)
+	projects.AssertNotCalled(t, "GetByID")
+}
+
+func TestBoardService_GetBoard_Errors(t *testing.T) {
+	projectID := 9
+
+	t.Run("unknown column", func(t *testing.T) {
+		s := NewBoardService(new(gorm_board.BoardRepoMock), new(gorm_task.TaskRepoMock), new(gorm_project.ProjectRepoMock))
+		_, err := s.GetBoard(1, nil, "blocked", 1, 20)
+		assert.ErrorIs(t, err, ErrUnknownColumn)
+	})
+
+	t.Run("someone else's project", func(t *testing.T) {
+		projects := new(gorm_project.ProjectRepoMock)
+		projects.On("GetByID", 1, 9).Return(nil, gorm.ErrRecordNotFound)
+
+		s := NewBoardService(new(gorm_board.BoardRepoMock), new(gorm_task.TaskRepoMock), projects)
+		_, err := s.GetBoard(1, &projectID, "", 1, 20)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestBoardService_SetWIPLimit(t *testing.T) {
+	projectID := 4
+	boards := new(gorm_board.BoardRepoMock)
+	tasks := new(gorm_task.TaskRepoMock)
+	projects := new(gorm_project.ProjectRepoMock)
+
+	projects.On("GetByID", 1, 4).Return(&project.Project{ID: 4, UserID: 1}, nil)
+	boards.On("SetWIPLimit", 1, &projectID, "in-progress", 2).Return(nil)
+	tasks.On("CountColumn", 1, &projectID, "in-progress").Return(int64(1), nil)
+	tasks.On("ListColumn", 1, &projectID, "in-progress", 0, 20).Return([]task.Task{}, nil)
+
+	s := NewBoardService(boards, tasks, projects)
+	got, err := s.SetWIPLimit(1, "in-progress", &dto.SetWIPLimitRequest{ProjectID: &projectID, WIPLimit: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, got.WIPLimit)
+	assert.Equal(t, int64(1), got.Count)
+	boards.AssertExpectations(t)
+}
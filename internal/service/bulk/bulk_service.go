@@ -0,0 +1,510 @@
+package bulk_service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	task_service "taskflow/internal/service/task"
+	"taskflow/internal/taskquery"
+	"taskflow/pkg/lexorank"
+
+	"gorm.io/gorm"
+)
+
+// MaxItems caps how many tasks one bulk request may touch.
+const MaxItems = 500
+
+const (
+	ActionUpdateStatus = "update_status"
+	ActionDelete       = "delete"
+	ActionTag          = "tag"
+	ActionMove         = "move"
+	ActionRestore      = "restore"
+)
+
+// Per-item outcomes reported in dto.BulkItemResult.
+const (
+	ResultSuccess   = "success"
+	ResultNotFound  = "not_found"
+	ResultForbidden = "forbidden"
+	// ResultWIPLimit means the task would have entered a board column that
+	// is at its WIP limit.
+	ResultWIPLimit = "wip_limit"
+)
+
+var (
+	ErrInvalidUser     = errors.New("invalid user")
+	ErrSelection       = errors.New("provide exactly one of ids, filter_id or query")
+	ErrTooManyTasks    = fmt.Errorf("a bulk request may affect at most %d tasks", MaxItems)
+	ErrMissingStatus   = errors.New("status is required for update_status")
+	ErrMissingTags     = errors.New("tags are required for tag")
+	ErrRestoreNeedsIDs = errors.New("restore only accepts ids")
+	ErrUnknownProject  = errors.New("project not found")
+	ErrUnknownAction   = errors.New("unknown action")
+)
+
+// FilterScoper resolves a saved filter to task repository scopes.
+type FilterScoper interface {
+	Scopes(userID int, id int) ([]func(*gorm.DB) *gorm.DB, error)
+}
+
+type BulkService struct {
+	taskRepo    gorm_task.TaskRepositoryInterface
+	projectRepo gorm_project.ProjectRepositoryInterface
+	filters     FilterScoper
+	wip         task_service.WIPLimiter
+	users       task_service.UserLookup
+	now         func() time.Time
+}
+
+// Option configures optional BulkService collaborators.
+type Option func(*BulkService)
+
+// WithWIPLimits makes update_status and move skip tasks that would enter a
+// board column at its WIP limit.
+func WithWIPLimits(l task_service.WIPLimiter) Option {
+	return func(s *BulkService) {
+		s.wip = l
+	}
+}
+
+// WithUsers makes queries such as due:today resolve relative dates in the
+// user's time zone instead of UTC.
+func WithUsers(u task_service.UserLookup) Option {
+	return func(s *BulkService) {
+		s.users = u
+	}
+}
+
+func NewBulkService(
+	taskRepo gorm_task.TaskRepositoryInterface,
+	projectRepo gorm_project.ProjectRepositoryInterface,
+	filters FilterScoper,
+	opts ...Option,
+) *BulkService {
+	s := &BulkService{taskRepo: taskRepo, projectRepo: projectRepo, filters: filters, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ BulkServiceInterface = (*BulkService)(nil)
+
+// Apply runs one action over the selected tasks inside a single transaction
+// and reports the outcome for each requested ID. Tasks that do not exist (or
+// are in the trash, for anything but restore) are not_found; tasks owned by
+// someone else are forbidden; tasks that would enter a board column at its
+// WIP limit are wip_limit. None of these aborts the rest of the batch. The
+// same domain events as the single-task endpoints are recorded for every
+// task changed.
+func (s *BulkService) Apply(userID int, req *dto.BulkTaskRequest) (dto.BulkTaskResponse, error) {
+	if userID == 0 {
+		return dto.BulkTaskResponse{}, ErrInvalidUser
+	}
+	if err := validate(req); err != nil {
+		return dto.BulkTaskResponse{}, err
+	}
+
+	tags := task_service.NormalizeTagNames(req.Tags)
+	if req.Action == ActionTag && len(tags) == 0 {
+		return dto.BulkTaskResponse{}, ErrMissingTags
+	}
+	if req.Action == ActionMove && req.ProjectID != nil {
+		if _, err := s.projectRepo.GetByID(userID, *req.ProjectID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.BulkTaskResponse{}, ErrUnknownProject
+			}
+			return dto.BulkTaskResponse{}, err
+		}
+	}
+
+	resp := dto.BulkTaskResponse{Action: req.Action}
+	err := s.taskRepo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		ids, err := s.selectIDs(repo, userID, req)
+		if err != nil {
+			return err
+		}
+
+		results, allowed, err := classify(repo, userID, ids, req.Action == ActionRestore)
+		if err != nil {
+			return err
+		}
+		resp.Results = results
+
+		if req.Action == ActionUpdateStatus || req.Action == ActionMove {
+			if allowed, err = s.admit(repo, userID, allowed, req, results); err != nil {
+				return err
+			}
+		}
+		if len(allowed) == 0 {
+			return nil
+		}
+		return run(repo, userID, allowed, req, tags)
+	})
+	if err != nil {
+		return dto.BulkTaskResponse{}, err
+	}
+
+	for _, r := range resp.Results {
+		if r.Result == ResultSuccess {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	return resp, nil
+}
+
+func validate(req *dto.BulkTaskRequest) error {
+	selectors := 0
+	if len(req.IDs) > 0 {
+		selectors++
+	}
+	if req.FilterID != 0 {
+		selectors++
+	}
+	if req.Query != "" {
+		selectors++
+	}
+	if selectors != 1 {
+		return ErrSelection
+	}
+
+	if len(req.IDs) > MaxItems {
+		return ErrTooManyTasks
+	}
+	if req.Action == ActionRestore && len(req.IDs) == 0 {
+		return ErrRestoreNeedsIDs
+	}
+	if req.Action == ActionUpdateStatus && req.Status == "" {
+		return ErrMissingStatus
+	}
+	return nil
+}
+
+// selectIDs returns the requested IDs, or the IDs of the user's tasks that
+// match the filter or query, without duplicates.
+func (s *BulkService) selectIDs(repo gorm_task.TaskRepositoryInterface, userID int, req *dto.BulkTaskRequest) ([]int, error) {
+	if len(req.IDs) > 0 {
+		return dedupe(req.IDs), nil
+	}
+
+	var scopes []func(*gorm.DB) *gorm.DB
+	if req.FilterID != 0 {
+		var err error
+		if scopes, err = s.filters.Scopes(userID, req.FilterID); err != nil {
+			return nil, err
+		}
+	} else {
+		node, err := taskquery.Parse(req.Query)
+		if err != nil {
+			return nil, err
+		}
+		loc, err := task_service.UserLocation(s.users, userID)
+		if err != nil {
+			return nil, err
+		}
+		scope, err := taskquery.Compile(node, s.now().In(loc))
+		if err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+
+	tasks, err := repo.Find(userID, scopes...)
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) > MaxItems {
+		return nil, ErrTooManyTasks
+	}
+
+	ids := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids, nil
+}
+
+// classify reports an outcome for every ID and returns the tasks the action
+// may be applied to, in request order.
+func classify(repo gorm_task.TaskRepositoryInterface, userID int, ids []int, includeTrashed bool) ([]dto.BulkItemResult, []task.Task, error) {
+	results := make([]dto.BulkItemResult, 0, len(ids))
+	if len(ids) == 0 {
+		return results, nil, nil
+	}
+
+	found, err := repo.Lookup(ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[int]task.Task, len(found))
+	for _, t := range found {
+		byID[t.ID] = t
+	}
+
+	var allowed []task.Task
+	for _, id := range ids {
+		t, ok := byID[id]
+		switch {
+		case !ok, t.DeletedAt.Valid && !includeTrashed:
+			results = append(results, dto.BulkItemResult{ID: id, Result: ResultNotFound})
+		case t.UserID != userID:
+			results = append(results, dto.BulkItemResult{ID: id, Result: ResultForbidden})
+		default:
+			results = append(results, dto.BulkItemResult{ID: id, Result: ResultSuccess})
+			allowed = append(allowed, t)
+		}
+	}
+	return results, allowed, nil
+}
+
+// admit checks the WIP limit of every board column the tasks would enter,
+// marks the tasks that don't fit as wip_limit in results and returns the
+// rest. Tasks are admitted in request order until a column is full.
+func (s *BulkService) admit(repo gorm_task.TaskRepositoryInterface, userID int, tasks []task.Task, req *dto.BulkTaskRequest, results []dto.BulkItemResult) ([]task.Task, error) {
+	if s.wip == nil {
+		return tasks, nil
+	}
+
+	// room holds the free places left in each column; -1 means no limit.
+	room := map[string]int64{}
+	admitted := make([]task.Task, 0, len(tasks))
+	for _, t := range tasks {
+		projectID, status := target(t, req)
+		if t.Status == status && sameProject(t.ProjectID, projectID) {
+			admitted = append(admitted, t)
+			continue
+		}
+
+		key := columnKey(projectID, status)
+		left, ok := room[key]
+		if !ok {
+			limit, err := s.wip.WIPLimit(userID, projectID, status)
+			if err != nil {
+				return nil, err
+			}
+			if limit > 0 {
+				// Lock the column and read its limit again, as
+				// TaskService does.
+				if limit, err = repo.LockColumn(userID, projectID, status); err != nil {
+					return nil, err
+				}
+			}
+			left = -1
+			if limit > 0 {
+				n, err := repo.CountColumn(userID, projectID, status)
+				if err != nil {
+					return nil, err
+				}
+				left = max(int64(limit)-n, 0)
+			}
+		}
+		if left == 0 {
+			room[key] = 0
+			for i := range results {
+				if results[i].ID == t.ID {
+					results[i].Result = ResultWIPLimit
+				}
+			}
+			continue
+		}
+		if left > 0 {
+			left--
+		}
+		room[key] = left
+		admitted = append(admitted, t)
+	}
+	return admitted, nil
+}
+
+// target returns the list and status a task ends up in after req.
+func target(t task.Task, req *dto.BulkTaskRequest) (*int, string) {
+	if req.Action == ActionMove {
+		return req.ProjectID, t.Status
+	}
+	return t.ProjectID, req.Status
+}
+
+// run applies the action and records a domain event for every task it
+// changed, within the caller's transaction.
+func run(repo gorm_task.TaskRepositoryInterface, userID int, tasks []task.Task, req *dto.BulkTaskRequest, tags []string) error {
+	ids := taskIDs(tasks)
+	var evs []events.Event
+	switch req.Action {
+	case ActionUpdateStatus:
+		if _, err := repo.UpdateStatusBatch(userID, ids, req.Status); err != nil {
+			return err
+		}
+		from := map[int]string{}
+		for _, t := range tasks {
+			if t.Status != req.Status {
+				from[t.ID] = t.Status
+			}
+		}
+		changed, err := reload(repo, userID, keys(from))
+		if err != nil {
+			return err
+		}
+		for _, t := range changed {
+			evs = append(evs, events.StatusChanged{UserID: userID, Task: task_service.ToTaskResponse(t), From: from[t.ID]})
+		}
+	case ActionDelete:
+		if _, err := repo.DeleteBatch(userID, ids); err != nil {
+			return err
+		}
+		for _, t := range tasks {
+			evs = append(evs, events.TaskDeleted{UserID: userID, Task: task_service.ToTaskResponse(t)})
+		}
+	case ActionRestore:
+		if _, err := repo.RestoreBatch(userID, ids); err != nil {
+			return err
+		}
+		var trashed []int
+		for _, t := range tasks {
+			if t.DeletedAt.Valid {
+				trashed = append(trashed, t.ID)
+			}
+		}
+		restored, err := reload(repo, userID, trashed)
+		if err != nil {
+			return err
+		}
+		for _, t := range restored {
+			evs = append(evs, events.TaskRestored{UserID: userID, Task: task_service.ToTaskResponse(t)})
+		}
+	case ActionMove:
+		// Tasks already in the project keep their place; the others go to
+		// the end of it, in request order.
+		var moving []int
+		for _, t := range tasks {
+			if !sameProject(t.ProjectID, req.ProjectID) {
+				moving = append(moving, t.ID)
+			}
+		}
+		if len(moving) == 0 {
+			return nil
+		}
+		last, err := repo.LastPosition(userID, req.ProjectID)
+		if err != nil {
+			return err
+		}
+		if !lexorank.Valid(last) {
+			last = ""
+		}
+		positions, err := lexorank.After(last, len(moving))
+		if err != nil {
+			return err
+		}
+		if _, err := repo.MoveBatch(userID, moving, req.ProjectID, positions); err != nil {
+			return err
+		}
+		moved, err := reload(repo, userID, moving)
+		if err != nil {
+			return err
+		}
+		for _, t := range moved {
+			evs = append(evs, events.TaskMoved{UserID: userID, Task: task_service.ToTaskResponse(t)})
+		}
+	case ActionTag:
+		// Only tasks that lack one of the tags change.
+		var tagging []int
+		for _, t := range tasks {
+			if !hasTags(t, tags) {
+				tagging = append(tagging, t.ID)
+			}
+		}
+		if len(tagging) == 0 {
+			return nil
+		}
+		if err := repo.AddTagsBatch(userID, tagging, tags); err != nil {
+			return err
+		}
+		tagged, err := reload(repo, userID, tagging)
+		if err != nil {
+			return err
+		}
+		for _, t := range tagged {
+			evs = append(evs, events.TaskUpdated{UserID: userID, Task: task_service.ToTaskResponse(t)})
+		}
+	default:
+		return ErrUnknownAction
+	}
+
+	if len(evs) == 0 {
+		return nil
+	}
+	return repo.AddEvents(evs...)
+}
+
+// reload reads the given tasks back after a change, in id order.
+func reload(repo gorm_task.TaskRepositoryInterface, userID int, ids []int) ([]task.Task, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return repo.Find(userID, func(db *gorm.DB) *gorm.DB {
+		return db.Where("tasks.id IN ?", ids)
+	})
+}
+
+func taskIDs(tasks []task.Task) []int {
+	ids := make([]int, 0, len(tasks))
+	for _, t := range tasks {
+		ids = append(ids, t.ID)
+	}
+	return ids
+}
+
+func keys(m map[int]string) []int {
+	ids := make([]int, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func columnKey(projectID *int, status string) string {
+	if projectID == nil {
+		return "inbox/" + status
+	}
+	return fmt.Sprintf("%d/%s", *projectID, status)
+}
+
+// hasTags reports whether t has every one of names.
+func hasTags(t task.Task, names []string) bool {
+	for _, name := range names {
+		if !slices.ContainsFunc(t.Tags, func(tag task.Tag) bool { return tag.Name == name }) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameProject(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func dedupe(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	out := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
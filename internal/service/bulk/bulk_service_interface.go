@@ -0,0 +1,7 @@
+package bulk_service
+
+import "taskflow/internal/dto"
+
+type BulkServiceInterface interface {
+	Apply(userID int, req *dto.BulkTaskRequest) (dto.BulkTaskResponse, error)
+}
@@ -0,0 +1,18 @@
+package bulk_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type BulkServiceMock struct {
+	mock.Mock
+}
+
+var _ BulkServiceInterface = (*BulkServiceMock)(nil)
+
+func (m *BulkServiceMock) Apply(userID int, req *dto.BulkTaskRequest) (dto.BulkTaskResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.BulkTaskResponse), args.Error(1)
+}
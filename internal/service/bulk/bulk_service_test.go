@@ -0,0 +1,265 @@
+package bulk_service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/user"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_user"
+	task_service "taskflow/internal/service/task"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type fakeFilters struct {
+	scopes []func(*gorm.DB) *gorm.DB
+	err    error
+}
+
+func (f fakeFilters) Scopes(userID int, id int) ([]func(*gorm.DB) *gorm.DB, error) {
+	return f.scopes, f.err
+}
+
+func trashed() gorm.DeletedAt {
+	return gorm.DeletedAt{Time: time.Now(), Valid: true}
+}
+
+func TestBulkService_Apply(t *testing.T) {
+	lookup := []task.Task{
+		{ID: 1, UserID: 1},
+		{ID: 2, UserID: 2},
+		{ID: 3, UserID: 1, DeletedAt: trashed()},
+	}
+	projectID := 7
+
+	tests := []struct {
+		name        string
+		req         dto.BulkTaskRequest
+		setupMock   func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock)
+		wantErr     error
+		wantResults []dto.BulkItemResult
+	}{
+		{
+			name: "update status reports each item",
+			req:  dto.BulkTaskRequest{Action: ActionUpdateStatus, IDs: []int{1, 2, 3, 4, 1}, Status: "completed"},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {
+				tr.On("Transaction", mock.Anything).Return(nil)
+				tr.On("Lookup", []int{1, 2, 3, 4}).Return(lookup, nil)
+				tr.On("UpdateStatusBatch", 1, []int{1}, "completed").Return(int64(1), nil)
+				tr.On("Find", 1, mock.Anything).Return([]task.Task{{ID: 1, UserID: 1, Status: "completed"}}, nil)
+				tr.On("AddEvents", []events.Event{events.StatusChanged{
+					UserID: 1,
+					Task:   task_service.ToTaskResponse(task.Task{ID: 1, UserID: 1, Status: "completed"}),
+				}}).Return(nil)
+			},
+			wantResults: []dto.BulkItemResult{
+				{ID: 1, Result: ResultSuccess},
+				{ID: 2, Result: ResultForbidden},
+				{ID: 3, Result: ResultNotFound},
+				{ID: 4, Result: ResultNotFound},
+			},
+		},
+		{
+			name: "restore includes trashed tasks",
+			req:  dto.BulkTaskRequest{Action: ActionRestore, IDs: []int{3}},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {
+				tr.On("Transaction", mock.Anything).Return(nil)
+				tr.On("Lookup", []int{3}).Return(lookup[2:], nil)
+				tr.On("RestoreBatch", 1, []int{3}).Return(int64(1), nil)
+				tr.On("Find", 1, mock.Anything).Return([]task.Task{{ID: 3, UserID: 1}}, nil)
+				tr.On("AddEvents", mock.MatchedBy(func(evs []events.Event) bool {
+					return len(evs) == 1 && evs[0].EventType() == events.TypeTaskRestored
+				})).Return(nil)
+			},
+			wantResults: []dto.BulkItemResult{{ID: 3, Result: ResultSuccess}},
+		},
+		{
+			name: "delete by query",
+			req:  dto.BulkTaskRequest{Action: ActionDelete, Query: "status:completed"},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {
+				tr.On("Transaction", mock.Anything).Return(nil)
+				tr.On("Find", 1, mock.Anything).Return([]task.Task{{ID: 1, UserID: 1}}, nil)
+				tr.On("Lookup", []int{1}).Return(lookup[:1], nil)
+				tr.On("DeleteBatch", 1, []int{1}).Return(int64(1), nil)
+				tr.On("AddEvents", mock.MatchedBy(func(evs []events.Event) bool {
+					return len(evs) == 1 && evs[0].EventType() == events.TypeTaskDeleted
+				})).Return(nil)
+			},
+			wantResults: []dto.BulkItemResult{{ID: 1, Result: ResultSuccess}},
+		},
+		{
+			name: "move checks project ownership",
+			req:  dto.BulkTaskRequest{Action: ActionMove, IDs: []int{1}, ProjectID: &projectID},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {
+				pr.On("GetByID", 1, 7).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrUnknownProject,
+		},
+		{
+			name: "tag with normalized names",
+			req:  dto.BulkTaskRequest{Action: ActionTag, IDs: []int{1}, Tags: []string{" Cleanup", "cleanup"}},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {
+				tr.On("Transaction", mock.Anything).Return(nil)
+				tr.On("Lookup", []int{1}).Return(lookup[:1], nil)
+				tr.On("AddTagsBatch", 1, []int{1}, []string{"cleanup"}).Return(nil)
+				tagged := task.Task{ID: 1, UserID: 1, Tags: []task.Tag{{TaskID: 1, Name: "cleanup"}}}
+				tr.On("Find", 1, mock.Anything).Return([]task.Task{tagged}, nil)
+				tr.On("AddEvents", []events.Event{events.TaskUpdated{UserID: 1, Task: task_service.ToTaskResponse(tagged)}}).Return(nil)
+			},
+			wantResults: []dto.BulkItemResult{{ID: 1, Result: ResultSuccess}},
+		},
+		{
+			name: "tagging tasks that have the tags changes nothing",
+			req:  dto.BulkTaskRequest{Action: ActionTag, IDs: []int{1}, Tags: []string{"cleanup"}},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {
+				tr.On("Transaction", mock.Anything).Return(nil)
+				tr.On("Lookup", []int{1}).Return([]task.Task{{ID: 1, UserID: 1, Tags: []task.Tag{{TaskID: 1, Name: "cleanup"}}}}, nil)
+			},
+			wantResults: []dto.BulkItemResult{{ID: 1, Result: ResultSuccess}},
+		},
+		{
+			name:      "ids and query together",
+			req:       dto.BulkTaskRequest{Action: ActionDelete, IDs: []int{1}, Query: "tag:x"},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {},
+			wantErr:   ErrSelection,
+		},
+		{
+			name:      "restore by filter",
+			req:       dto.BulkTaskRequest{Action: ActionRestore, FilterID: 2},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {},
+			wantErr:   ErrRestoreNeedsIDs,
+		},
+		{
+			name: "repository failure rolls back",
+			req:  dto.BulkTaskRequest{Action: ActionDelete, IDs: []int{1}},
+			setupMock: func(tr *gorm_task.TaskRepoMock, pr *gorm_project.ProjectRepoMock) {
+				tr.On("Transaction", mock.Anything).Return(nil)
+				tr.On("Lookup", []int{1}).Return(lookup[:1], nil)
+				tr.On("DeleteBatch", 1, []int{1}).Return(int64(0), errors.New("db error"))
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taskRepo := new(gorm_task.TaskRepoMock)
+			projectRepo := new(gorm_project.ProjectRepoMock)
+			tt.setupMock(taskRepo, projectRepo)
+
+			s := NewBulkService(taskRepo, projectRepo, fakeFilters{})
+			got, err := s.Apply(1, &tt.req)
+
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantResults, got.Results)
+			}
+			taskRepo.AssertExpectations(t)
+			projectRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestBulkService_Apply_MoveToProject(t *testing.T) {
+	taskRepo := new(gorm_task.TaskRepoMock)
+	projectRepo := new(gorm_project.ProjectRepoMock)
+	projectID := 7
+
+	projectRepo.On("GetByID", 1, 7).Return(&project.Project{ID: 7, UserID: 1}, nil)
+	taskRepo.On("Transaction", mock.Anything).Return(nil)
+	taskRepo.On("Find", 1, mock.Anything).Return([]task.Task{{ID: 1}, {ID: 5}}, nil)
+	taskRepo.On("Lookup", []int{1, 5}).Return([]task.Task{{ID: 1, UserID: 1}, {ID: 5, UserID: 1}}, nil)
+	taskRepo.On("LastPosition", 1, &projectID).Return("i", nil)
+	taskRepo.On("MoveBatch", 1, []int{1, 5}, &projectID, mock.MatchedBy(func(positions []string) bool {
+		// Both go after the project's last task, in order.
+		return len(positions) == 2 && "i" < positions[0] && positions[0] < positions[1]
+	})).Return(int64(2), nil)
+	taskRepo.On("AddEvents", mock.MatchedBy(func(evs []events.Event) bool {
+		return len(evs) == 2 && evs[0].EventType() == events.TypeTaskMoved
+	})).Return(nil)
+
+	s := NewBulkService(taskRepo, projectRepo, fakeFilters{})
+	got, err := s.Apply(1, &dto.BulkTaskRequest{Action: ActionMove, FilterID: 3, ProjectID: &projectID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got.Succeeded)
+	assert.Equal(t, 0, got.Failed)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestBulkService_Apply_QueryInUserTimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	var vars []any
+	taskRepo := new(gorm_task.TaskRepoMock)
+	taskRepo.On("Transaction", mock.Anything).Return(nil)
+	taskRepo.On("Find", 1, mock.Anything).Run(func(args mock.Arguments) {
+		scopes := args.Get(1).([]func(*gorm.DB) *gorm.DB)
+		vars = db.Scopes(scopes...).Find(&[]task.Task{}).Statement.Vars
+	}).Return([]task.Task{}, nil)
+	users := new(gorm_user.MockUserRepository)
+	users.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "America/New_York"}, nil)
+
+	s := NewBulkService(taskRepo, new(gorm_project.ProjectRepoMock), fakeFilters{}, WithUsers(users))
+	// 02:00 UTC on the 10th is still the evening of the 9th in New York.
+	s.now = func() time.Time { return time.Date(2025, 9, 10, 2, 0, 0, 0, time.UTC) }
+	_, err = s.Apply(1, &dto.BulkTaskRequest{Action: ActionDelete, Query: "due:today"})
+
+	require.NoError(t, err)
+	require.Len(t, vars, 2)
+	assert.True(t, time.Date(2025, 9, 9, 0, 0, 0, 0, newYork).Equal(vars[0].(time.Time)), "start is %v", vars[0])
+	assert.True(t, time.Date(2025, 9, 10, 0, 0, 0, 0, newYork).Equal(vars[1].(time.Time)), "end is %v", vars[1])
+}
+
+type fakeWIP map[string]int
+
+func (f fakeWIP) WIPLimit(userID int, projectID *int, status string) (int, error) {
+	return f[status], nil
+}
+
+func TestBulkService_Apply_WIPLimit(t *testing.T) {
+	taskRepo := new(gorm_task.TaskRepoMock)
+	taskRepo.On("Transaction", mock.Anything).Return(nil)
+	taskRepo.On("Lookup", []int{1, 2, 3}).Return([]task.Task{
+		{ID: 1, UserID: 1, Status: "pending"},
+		{ID: 2, UserID: 1, Status: "pending"},
+		{ID: 3, UserID: 1, Status: "in-progress"},
+	}, nil)
+	// One place left in the inbox's in_progress column.
+	taskRepo.On("LockColumn", 1, (*int)(nil), "in-progress").Return(3, nil).Once()
+	taskRepo.On("CountColumn", 1, (*int)(nil), "in-progress").Return(int64(2), nil).Once()
+	taskRepo.On("UpdateStatusBatch", 1, []int{1, 3}, "in-progress").Return(int64(2), nil)
+	taskRepo.On("Find", 1, mock.Anything).Return([]task.Task{{ID: 1, UserID: 1, Status: "in-progress"}}, nil)
+	taskRepo.On("AddEvents", mock.MatchedBy(func(evs []events.Event) bool {
+		return len(evs) == 1 && evs[0].(events.StatusChanged).From == "pending"
+	})).Return(nil)
+
+	s := NewBulkService(taskRepo, new(gorm_project.ProjectRepoMock), fakeFilters{}, WithWIPLimits(fakeWIP{"in-progress": 3}))
+	got, err := s.Apply(1, &dto.BulkTaskRequest{Action: ActionUpdateStatus, IDs: []int{1, 2, 3}, Status: "in-progress"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []dto.BulkItemResult{
+		{ID: 1, Result: ResultSuccess},
+		{ID: 2, Result: ResultWIPLimit},
+		{ID: 3, Result: ResultSuccess},
+	}, got.Results)
+	assert.Equal(t, 2, got.Succeeded)
+	assert.Equal(t, 1, got.Failed)
+	taskRepo.AssertExpectations(t)
+}
@@ -0,0 +1,609 @@
+package caldav_service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskflow/internal/domain/caldav"
+	"taskflow/internal/domain/calendar"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_caldav"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/scheduler"
+	calendar_service "taskflow/internal/service/calendar"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg/ical"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// InboxName is the calendar of tasks without a project.
+	InboxName = "inbox"
+	// KindPurge is the scheduler job that purges old tombstones.
+	KindPurge = "caldav.purge"
+	// PurgeInterval is how often tombstones are purged.
+	PurgeInterval = 24 * time.Hour
+	// TombstoneTTL is how long deletions are kept for sync reports. Sync
+	// tokens from before then are refused, and clients sync in full.
+	TombstoneTTL = 90 * 24 * time.Hour
+
+	projectPrefix   = "project-"
+	syncTokenPrefix = "http://taskflow/ns/sync/"
+	prodID          = "-//TaskFlow//TaskFlow CalDAV//EN"
+)
+
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrPreconditionFailed  = errors.New("precondition failed")
+	ErrInvalidCalendarData = errors.New("invalid calendar data")
+	// ErrUnsupportedComponent is returned for objects without a VTODO,
+	// such as events.
+	ErrUnsupportedComponent = errors.New("only VTODO objects are supported")
+	// ErrUIDConflict is returned when another resource has the UID.
+	ErrUIDConflict      = errors.New("another task has this UID")
+	ErrInvalidSyncToken = errors.New("invalid or expired sync token")
+)
+
+// Collection is a calendar of tasks: the inbox, or a project.
+type Collection struct {
+	Name        string
+	DisplayName string
+	// ProjectID is nil for the inbox.
+	ProjectID *int
+	// SyncToken changes whenever a task of the user changes.
+	SyncToken string
+}
+
+// Object is a task as an iCalendar resource.
+type Object struct {
+	Name     string
+	ETag     string
+	Modified time.Time
+	Data     []byte
+}
+
+// Changes are the objects changed and removed since a sync token.
+type Changes struct {
+	Updated   []Object
+	Removed   []string
+	SyncToken string
+}
+
+// Conditions are the If-Match and If-None-Match headers of a request.
+type Conditions struct {
+	IfMatch     string
+	IfNoneMatch string
+}
+
+type CalDAVService struct {
+	repo     gorm_caldav.CalDAVRepositoryInterface
+	taskRepo gorm_task.TaskRepositoryInterface
+	projects gorm_project.ProjectRepositoryInterface
+	tasks    task_service.TaskServiceInterface
+	now      func() time.Time
+}
+
+func NewCalDAVService(repo gorm_caldav.CalDAVRepositoryInterface, taskRepo gorm_task.TaskRepositoryInterface, projects gorm_project.ProjectRepositoryInterface, tasks task_service.TaskServiceInterface) *CalDAVService {
+	return &CalDAVService{
+		repo:     repo,
+		taskRepo: taskRepo,
+		projects: projects,
+		tasks:    tasks,
+		now:      time.Now,
+	}
+}
+
+var _ CalDAVServiceInterface = (*CalDAVService)(nil)
+
+func (s *CalDAVService) Collections(userID int) ([]Collection, error) {
+	token, err := s.syncToken(userID)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := s.projects.List(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := []Collection{{Name: InboxName, DisplayName: "Tasks", SyncToken: token}}
+	for _, p := range projects {
+		id := p.ID
+		cols = append(cols, Collection{
+			Name:        projectPrefix + strconv.Itoa(id),
+			DisplayName: p.Name,
+			ProjectID:   &id,
+			SyncToken:   token,
+		})
+	}
+	return cols, nil
+}
+
+func (s *CalDAVService) Collection(userID int, name string) (Collection, error) {
+	c := Collection{Name: name, DisplayName: "Tasks"}
+	if name != InboxName {
+		idStr, ok := strings.CutPrefix(name, projectPrefix)
+		id, err := strconv.Atoi(idStr)
+		if !ok || err != nil || id < 1 {
+			return Collection{}, ErrNotFound
+		}
+		p, err := s.projects.GetByID(userID, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Collection{}, ErrNotFound
+		}
+		if err != nil {
+			return Collection{}, err
+		}
+		c.DisplayName, c.ProjectID = p.Name, &p.ID
+	}
+
+	token, err := s.syncToken(userID)
+	if err != nil {
+		return Collection{}, err
+	}
+	c.SyncToken = token
+	return c, nil
+}
+
+func (s *CalDAVService) Objects(userID int, collection string) ([]Object, error) {
+	c, err := s.Collection(userID, collection)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := s.taskRepo.Find(userID, func(db *gorm.DB) *gorm.DB {
+		if c.ProjectID == nil {
+			return db.Where("tasks.project_id IS NULL")
+		}
+		return db.Where("tasks.project_id = ?", *c.ProjectID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idx, err := s.index(userID)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]Object, 0, len(tasks))
+	for i := range tasks {
+		obj, err := idx.render(&tasks[i])
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func (s *CalDAVService) Object(userID int, collection string, name string) (Object, error) {
+	c, err := s.Collection(userID, collection)
+	if err != nil {
+		return Object{}, err
+	}
+	idx, err := s.index(userID)
+	if err != nil {
+		return Object{}, err
+	}
+	t, err := s.find(userID, idx, c, name)
+	if err != nil {
+		return Object{}, err
+	}
+	return idx.render(t)
+}
+
+// Put stores a VTODO as a task of the calendar. Properties tasks have no
+// field for, such as alarms, are not kept.
+func (s *CalDAVService) Put(userID int, collection string, name string, data []byte, cond Conditions) (Object, bool, error) {
+	c, err := s.Collection(userID, collection)
+	if err != nil {
+		return Object{}, false, err
+	}
+	todo, err := parseTodo(data)
+	if err != nil {
+		return Object{}, false, err
+	}
+	uid := todo.Text("UID")
+	if uid == "" || len(uid) > 255 || len(name) > 255 {
+		return Object{}, false, fmt.Errorf("%w: UID is missing or too long", ErrInvalidCalendarData)
+	}
+	req, err := calendar_service.TodoRequest(todo)
+	if err == nil {
+		err = task_service.ValidateImport(req)
+	}
+	if err != nil {
+		return Object{}, false, fmt.Errorf("%w: %v", ErrInvalidCalendarData, err)
+	}
+
+	idx, err := s.index(userID)
+	if err != nil {
+		return Object{}, false, err
+	}
+	existing, err := s.find(userID, idx, c, name)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return Object{}, false, err
+	}
+
+	if existing != nil {
+		if err := cond.check(idx, existing); err != nil {
+			return Object{}, false, err
+		}
+		if idx.uid(existing.ID) != uid {
+			return Object{}, false, ErrUIDConflict
+		}
+		if _, err := s.tasks.Replace(userID, existing.ID, req); err != nil {
+			return Object{}, false, err
+		}
+		obj, err := s.Object(userID, collection, name)
+		return obj, false, err
+	}
+
+	if err := cond.check(idx, nil); err != nil {
+		return Object{}, false, err
+	}
+	if _, taken := idx.byName[name]; taken || s.uidTaken(userID, idx, uid) {
+		return Object{}, false, ErrUIDConflict
+	}
+	req.ProjectID = c.ProjectID
+	created, err := s.tasks.Import(userID, []dto.ImportTaskRequest{*req})
+	if err != nil {
+		return Object{}, false, err
+	}
+	id := created[0].ID
+	if err := s.repo.CreateObject(&caldav.Object{UserID: userID, TaskID: id, Name: name, UID: uid}); err != nil {
+		// Without its name the client couldn't find the task again, so
+		// it goes to the trash.
+		_ = s.tasks.Delete(userID, id)
+		return Object{}, false, err
+	}
+	obj, err := s.Object(userID, collection, name)
+	return obj, true, err
+}
+
+// Delete moves a task to the trash, from where the REST API can restore it.
+func (s *CalDAVService) Delete(userID int, collection string, name string, cond Conditions) error {
+	c, err := s.Collection(userID, collection)
+	if err != nil {
+		return err
+	}
+	idx, err := s.index(userID)
+	if err != nil {
+		return err
+	}
+	t, err := s.find(userID, idx, c, name)
+	if err != nil {
+		return err
+	}
+	if err := cond.check(idx, t); err != nil {
+		return err
+	}
+
+	if err := s.tasks.Delete(userID, t.ID); err != nil {
+		return err
+	}
+	// The TaskDeleted consumer would record this too, but only once the
+	// scheduler gets to it.
+	if err := s.repo.AddTombstone(&caldav.Tombstone{UserID: userID, TaskID: t.ID, ProjectID: t.ProjectID, Name: name}); err != nil {
+		return err
+	}
+	return s.repo.DeleteObject(t.ID)
+}
+
+// Changes lists the tasks of the calendar changed since the sync token,
+// and those deleted or moved elsewhere as removed. Tasks are matched by
+// modification time, so changes to other calendars of the user show up as
+// removals of resources the client doesn't have, which clients ignore.
+func (s *CalDAVService) Changes(userID int, collection string, syncToken string) (Changes, error) {
+	c, err := s.Collection(userID, collection)
+	if err != nil {
+		return Changes{}, err
+	}
+	if syncToken == "" {
+		objs, err := s.Objects(userID, collection)
+		if err != nil {
+			return Changes{}, err
+		}
+		return Changes{Updated: objs, Removed: []string{}, SyncToken: c.SyncToken}, nil
+	}
+
+	since, afterID, ok := parseSyncToken(syncToken)
+	// Tombstones of tasks the client knew may have been purged when the
+	// token is that old.
+	if !ok || (!since.IsZero() && since.Before(s.now().Add(-TombstoneTTL))) {
+		return Changes{}, ErrInvalidSyncToken
+	}
+
+	changed, err := s.repo.Changed(userID, since)
+	if err != nil {
+		return Changes{}, err
+	}
+	tombs, err := s.repo.Tombstones(userID, afterID)
+	if err != nil {
+		return Changes{}, err
+	}
+	idx, err := s.index(userID)
+	if err != nil {
+		return Changes{}, err
+	}
+
+	resp := Changes{Updated: []Object{}, Removed: []string{}, SyncToken: c.SyncToken}
+	removed := map[string]bool{}
+	remove := func(name string) {
+		if !removed[name] {
+			removed[name] = true
+			resp.Removed = append(resp.Removed, name)
+		}
+	}
+	live := map[int]bool{}
+	for i := range changed {
+		t := &changed[i]
+		if t.DeletedAt.Valid || !sameProject(t.ProjectID, c.ProjectID) {
+			remove(idx.name(t.ID))
+			continue
+		}
+		obj, err := idx.render(t)
+		if err != nil {
+			return Changes{}, err
+		}
+		resp.Updated = append(resp.Updated, obj)
+		live[t.ID] = true
+	}
+	for _, tomb := range tombs {
+		// A task restored from the trash keeps the tombstone recorded when
+		// it was trashed.
+		if sameProject(tomb.ProjectID, c.ProjectID) && !live[tomb.TaskID] {
+			remove(tomb.Name)
+		}
+	}
+	return resp, nil
+}
+
+// Purge deletes tombstones older than TombstoneTTL.
+func (s *CalDAVService) Purge(ctx context.Context, job *scheduler.Job) error {
+	_, err := s.repo.PurgeTombstones(s.now().Add(-TombstoneTTL))
+	return err
+}
+
+// HandleEvent records tombstones of deleted tasks and removes the data of
+// deleted users.
+func (s *CalDAVService) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	switch e.Type {
+	case events.TypeTaskDeleted:
+		var ev events.TaskDeleted
+		if err := e.Decode(&ev); err != nil {
+			return err
+		}
+		name := defaultName(ev.Task.ID)
+		o, err := s.repo.GetObjectByTask(ev.Task.ID)
+		if err == nil {
+			name = o.Name
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		err = s.repo.AddTombstone(&caldav.Tombstone{UserID: ev.UserID, TaskID: ev.Task.ID, ProjectID: ev.Task.ProjectID, Name: name})
+		if err != nil {
+			return err
+		}
+		return s.repo.DeleteObject(ev.Task.ID)
+	case events.TypeUserDeleted:
+		return s.repo.DeleteUserData(e.UserID)
+	}
+	return nil
+}
+
+// find returns the task with the given resource name in the calendar.
+func (s *CalDAVService) find(userID int, idx *index, c Collection, name string) (*task.Task, error) {
+	id, ok := idx.byName[name]
+	if !ok {
+		id, ok = parseDefaultName(name)
+		if _, renamed := idx.byTask[id]; !ok || renamed {
+			return nil, ErrNotFound
+		}
+	}
+	t, err := s.taskRepo.GetByID(userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !sameProject(t.ProjectID, c.ProjectID) {
+		return nil, ErrNotFound
+	}
+	return t, nil
+}
+
+// uidTaken reports whether a task of the user already has the UID.
+func (s *CalDAVService) uidTaken(userID int, idx *index, uid string) bool {
+	if _, ok := idx.byUID[uid]; ok {
+		return true
+	}
+	var id int
+	if _, err := fmt.Sscanf(uid, "task-%d@taskflow", &id); err != nil || calendar_service.TaskUID(id) != uid {
+		return false
+	}
+	_, err := s.taskRepo.GetByID(userID, id)
+	return err == nil
+}
+
+func (s *CalDAVService) syncToken(userID int) (string, error) {
+	last, err := s.repo.LastModified(userID)
+	if err != nil {
+		return "", err
+	}
+	tomb, err := s.repo.LastTombstoneID(userID)
+	if err != nil {
+		return "", err
+	}
+	var nanos int64
+	if !last.IsZero() {
+		nanos = last.UnixNano()
+	}
+	return fmt.Sprintf("%s%d-%d", syncTokenPrefix, nanos, tomb), nil
+}
+
+func parseSyncToken(token string) (since time.Time, afterID int64, ok bool) {
+	rest, ok := strings.CutPrefix(token, syncTokenPrefix)
+	nanosStr, idStr, found := strings.Cut(rest, "-")
+	if !ok || !found {
+		return time.Time{}, 0, false
+	}
+	nanos, err := strconv.ParseInt(nanosStr, 10, 64)
+	if err != nil || nanos < 0 {
+		return time.Time{}, 0, false
+	}
+	afterID, err = strconv.ParseInt(idStr, 10, 64)
+	if err != nil || afterID < 0 {
+		return time.Time{}, 0, false
+	}
+	if nanos > 0 {
+		since = time.Unix(0, nanos).UTC()
+	}
+	return since, afterID, true
+}
+
+// check applies the conditions to the current version of a resource, or
+// to a missing one when t is nil.
+func (c Conditions) check(idx *index, t *task.Task) error {
+	if t != nil && c.IfNoneMatch == "*" {
+		return ErrPreconditionFailed
+	}
+	if c.IfMatch == "" {
+		return nil
+	}
+	if t == nil {
+		return ErrPreconditionFailed
+	}
+	if c.IfMatch == "*" {
+		return nil
+	}
+	obj, err := idx.render(t)
+	if err != nil {
+		return err
+	}
+	for _, tag := range strings.Split(c.IfMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == obj.ETag {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
+
+// parseTodo returns the VTODO of an iCalendar object. Of a recurring
+// to-do, only the master, without RECURRENCE-ID, is used.
+func parseTodo(data []byte) (*ical.Component, error) {
+	roots, err := ical.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCalendarData, err)
+	}
+	var other bool
+	for _, root := range roots {
+		if root.Name != "VCALENDAR" {
+			continue
+		}
+		for _, todo := range root.Children("VTODO") {
+			if todo.Get("RECURRENCE-ID") == nil {
+				return todo, nil
+			}
+		}
+		other = other || len(root.Components) > 0
+	}
+	if other {
+		return nil, ErrUnsupportedComponent
+	}
+	return nil, fmt.Errorf("%w: no VTODO", ErrInvalidCalendarData)
+}
+
+// index maps tasks to the names and UIDs clients gave them.
+type index struct {
+	byTask map[int]caldav.Object
+	byName map[string]int
+	byUID  map[string]int
+}
+
+func (s *CalDAVService) index(userID int) (*index, error) {
+	objs, err := s.repo.ListObjects(userID)
+	if err != nil {
+		return nil, err
+	}
+	idx := &index{
+		byTask: make(map[int]caldav.Object, len(objs)),
+		byName: make(map[string]int, len(objs)),
+		byUID:  make(map[string]int, len(objs)),
+	}
+	for _, o := range objs {
+		idx.byTask[o.TaskID] = o
+		idx.byName[o.Name] = o.TaskID
+		idx.byUID[o.UID] = o.TaskID
+	}
+	return idx, nil
+}
+
+func (idx *index) name(taskID int) string {
+	if o, ok := idx.byTask[taskID]; ok {
+		return o.Name
+	}
+	return defaultName(taskID)
+}
+
+func (idx *index) uid(taskID int) string {
+	if o, ok := idx.byTask[taskID]; ok {
+		return o.UID
+	}
+	return calendar_service.TaskUID(taskID)
+}
+
+// render encodes a task as a VCALENDAR with one VTODO. DTSTAMP is the
+// modification time, so the data, and the ETag hashed from it, only
+// change when the task does.
+func (idx *index) render(t *task.Task) (Object, error) {
+	todo := calendar_service.TaskComponent(t, calendar.ComponentTodo, t.UpdatedAt)
+	todo.Get("UID").Value = ical.EscapeText(idx.uid(t.ID))
+	if t.ParentID != nil {
+		todo.AddText("RELATED-TO", idx.uid(*t.ParentID)).Params = ical.Params{"RELTYPE": {"PARENT"}}
+	}
+	cal := ical.NewCalendar(prodID)
+	cal.Components = append(cal.Components, todo)
+
+	var buf bytes.Buffer
+	if err := ical.NewEncoder(&buf).Encode(cal); err != nil {
+		return Object{}, err
+	}
+	sum := sha256.Sum256(buf.Bytes())
+	return Object{
+		Name:     idx.name(t.ID),
+		ETag:     `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Modified: t.UpdatedAt,
+		Data:     buf.Bytes(),
+	}, nil
+}
+
+func defaultName(taskID int) string {
+	return "task-" + strconv.Itoa(taskID) + ".ics"
+}
+
+func parseDefaultName(name string) (int, bool) {
+	rest, ok := strings.CutPrefix(name, "task-")
+	idStr, ok2 := strings.CutSuffix(rest, ".ics")
+	id, err := strconv.Atoi(idStr)
+	if !ok || !ok2 || err != nil || id < 1 || defaultName(id) != name {
+		return 0, false
+	}
+	return id, true
+}
+
+func sameProject(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,29 @@
+package caldav_service
+
+import (
+	"context"
+
+	"taskflow/internal/events"
+	"taskflow/internal/scheduler"
+)
+
+type CalDAVServiceInterface interface {
+	// Collections lists the user's task calendars: the inbox and one per
+	// project.
+	Collections(userID int) ([]Collection, error)
+	Collection(userID int, name string) (Collection, error)
+	// Objects lists the tasks of a calendar.
+	Objects(userID int, collection string) ([]Object, error)
+	Object(userID int, collection string, name string) (Object, error)
+	// Put creates or replaces a task from an iCalendar object. created
+	// reports whether the task is new.
+	Put(userID int, collection string, name string, data []byte, cond Conditions) (obj Object, created bool, err error)
+	Delete(userID int, collection string, name string, cond Conditions) error
+	// Changes lists what changed in a calendar since a sync token. An
+	// empty token lists everything.
+	Changes(userID int, collection string, syncToken string) (Changes, error)
+	// Purge handles KindPurge jobs.
+	Purge(ctx context.Context, job *scheduler.Job) error
+	// HandleEvent consumes domain events.
+	HandleEvent(ctx context.Context, e *events.Envelope) error
+}
@@ -0,0 +1,61 @@
+package caldav_service
+
+import (
+	"context"
+
+	"taskflow/internal/events"
+	"taskflow/internal/scheduler"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CalDAVServiceMock struct {
+	mock.Mock
+}
+
+var _ CalDAVServiceInterface = (*CalDAVServiceMock)(nil)
+
+func (m *CalDAVServiceMock) Collections(userID int) ([]Collection, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]Collection), args.Error(1)
+}
+
+func (m *CalDAVServiceMock) Collection(userID int, name string) (Collection, error) {
+	args := m.Called(userID, name)
+	return args.Get(0).(Collection), args.Error(1)
+}
+
+func (m *CalDAVServiceMock) Objects(userID int, collection string) ([]Object, error) {
+	args := m.Called(userID, collection)
+	return args.Get(0).([]Object), args.Error(1)
+}
+
+func (m *CalDAVServiceMock) Object(userID int, collection string, name string) (Object, error) {
+	args := m.Called(userID, collection, name)
+	return args.Get(0).(Object), args.Error(1)
+}
+
+func (m *CalDAVServiceMock) Put(userID int, collection string, name string, data []byte, cond Conditions) (Object, bool, error) {
+	args := m.Called(userID, collection, name, data, cond)
+	return args.Get(0).(Object), args.Bool(1), args.Error(2)
+}
+
+func (m *CalDAVServiceMock) Delete(userID int, collection string, name string, cond Conditions) error {
+	args := m.Called(userID, collection, name, cond)
+	return args.Error(0)
+}
+
+func (m *CalDAVServiceMock) Changes(userID int, collection string, syncToken string) (Changes, error) {
+	args := m.Called(userID, collection, syncToken)
+	return args.Get(0).(Changes), args.Error(1)
+}
+
+func (m *CalDAVServiceMock) Purge(ctx context.Context, job *scheduler.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *CalDAVServiceMock) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
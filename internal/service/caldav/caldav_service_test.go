@@ -0,0 +1,283 @@
+package caldav_service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/caldav"
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_caldav"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	task_service "taskflow/internal/service/task"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var (
+	now      = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	modified = now.Add(-time.Hour)
+	token    = fmt.Sprintf("http://taskflow/ns/sync/%d-4", modified.UnixNano())
+)
+
+type fixture struct {
+	repo     *gorm_caldav.CalDAVRepoMock
+	taskRepo *gorm_task.TaskRepoMock
+	projects *gorm_project.ProjectRepoMock
+	tasks    *task_service.TaskServiceMock
+	service  *CalDAVService
+}
+
+func setup() *fixture {
+	f := &fixture{
+		repo:     new(gorm_caldav.CalDAVRepoMock),
+		taskRepo: new(gorm_task.TaskRepoMock),
+		projects: new(gorm_project.ProjectRepoMock),
+		tasks:    new(task_service.TaskServiceMock),
+	}
+	f.repo.On("LastModified", 1).Return(modified, nil)
+	f.repo.On("LastTombstoneID", 1).Return(int64(4), nil)
+	f.projects.On("GetByID", 1, 2).Return(&project.Project{ID: 2, UserID: 1, Name: "Work"}, nil)
+	f.projects.On("GetByID", 1, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	f.service = NewCalDAVService(f.repo, f.taskRepo, f.projects, f.tasks)
+	f.service.now = func() time.Time { return now }
+	return f
+}
+
+func TestCalDAVService_Collections(t *testing.T) {
+	f := setup()
+	f.projects.On("List", 1).Return([]project.Project{{ID: 2, Name: "Work"}}, nil)
+
+	cols, err := f.service.Collections(1)
+	require.NoError(t, err)
+	require.Len(t, cols, 2)
+	assert.Equal(t, Collection{Name: "inbox", DisplayName: "Tasks", SyncToken: token}, cols[0])
+	assert.Equal(t, "project-2", cols[1].Name)
+	assert.Equal(t, "Work", cols[1].DisplayName)
+	assert.Equal(t, 2, *cols[1].ProjectID)
+
+	_, err = f.service.Collection(1, "project-3")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = f.service.Collection(1, "calendar")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCalDAVService_Object(t *testing.T) {
+	f := setup()
+	project2 := 2
+	f.repo.On("ListObjects", 1).Return([]caldav.Object{{TaskID: 8, Name: "ABC.ics", UID: "ABC"}}, nil)
+	f.taskRepo.On("GetByID", 1, 7).Return(&task.Task{ID: 7, UserID: 1, Task: "Inbox task", Status: "pending", UpdatedAt: modified}, nil)
+	f.taskRepo.On("GetByID", 1, 8).Return(&task.Task{ID: 8, UserID: 1, Task: "Work task", Status: "in-progress", ProjectID: &project2, ParentID: new(int), UpdatedAt: modified}, nil)
+
+	obj, err := f.service.Object(1, "inbox", "task-7.ics")
+	require.NoError(t, err)
+	assert.Equal(t, "task-7.ics", obj.Name)
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, obj.ETag)
+	data := string(obj.Data)
+	assert.Contains(t, data, "BEGIN:VTODO\r\nUID:task-7@taskflow\r\nDTSTAMP:20250908T110000Z\r\n")
+	assert.Contains(t, data, "SUMMARY:Inbox task\r\n")
+	assert.Contains(t, data, "STATUS:NEEDS-ACTION\r\n")
+
+	again, err := f.service.Object(1, "inbox", "task-7.ics")
+	require.NoError(t, err)
+	assert.Equal(t, obj.ETag, again.ETag, "ETags are stable")
+
+	obj, err = f.service.Object(1, "project-2", "ABC.ics")
+	require.NoError(t, err)
+	assert.Contains(t, string(obj.Data), "UID:ABC\r\n")
+	assert.Contains(t, string(obj.Data), "RELATED-TO;RELTYPE=PARENT:task-0@taskflow\r\n")
+
+	for _, tt := range []struct{ collection, name string }{
+		{"inbox", "ABC.ics"},        // in another calendar
+		{"project-2", "task-8.ics"}, // has a client-given name
+		{"inbox", "task-07.ics"},    // not a canonical name
+		{"project-3", "task-7.ics"}, // no such calendar
+		{"inbox", "something.ics"},  // unknown
+	} {
+		_, err := f.service.Object(1, tt.collection, tt.name)
+		assert.ErrorIs(t, err, ErrNotFound, tt.collection+"/"+tt.name)
+	}
+}
+
+const todoData = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Apple Inc.//Reminders//EN\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:5E2A1F0C\r\n" +
+	"SUMMARY:Buy milk\r\n" +
+	"DUE:20250909T170000Z\r\n" +
+	"PRIORITY:1\r\n" +
+	"BEGIN:VALARM\r\n" +
+	"ACTION:DISPLAY\r\n" +
+	"END:VALARM\r\n" +
+	"END:VTODO\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestCalDAVService_Put_Create(t *testing.T) {
+	f := setup()
+	project2 := 2
+	mapped := caldav.Object{UserID: 1, TaskID: 9, Name: "5E2A1F0C.ics", UID: "5E2A1F0C"}
+	f.repo.On("ListObjects", 1).Return([]caldav.Object{}, nil).Once()
+	f.repo.On("ListObjects", 1).Return([]caldav.Object{mapped}, nil)
+	due := time.Date(2025, 9, 9, 17, 0, 0, 0, time.UTC)
+	f.tasks.On("Import", 1, []dto.ImportTaskRequest{{
+		CreateTaskRequest: dto.CreateTaskRequest{Task: "Buy milk", DueAt: &due, Priority: "urgent"},
+		ProjectID:         &project2,
+	}}).Return([]dto.GetTaskResponse{{ID: 9}}, nil)
+	f.repo.On("CreateObject", &caldav.Object{UserID: 1, TaskID: 9, Name: "5E2A1F0C.ics", UID: "5E2A1F0C"}).Return(nil)
+	f.taskRepo.On("GetByID", 1, 9).Return(&task.Task{ID: 9, UserID: 1, Task: "Buy milk", Status: "pending", DueAt: &due, ProjectID: &project2, UpdatedAt: now}, nil)
+
+	obj, created, err := f.service.Put(1, "project-2", "5E2A1F0C.ics", []byte(todoData), Conditions{IfNoneMatch: "*"})
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "5E2A1F0C.ics", obj.Name)
+	assert.Contains(t, string(obj.Data), "UID:5E2A1F0C\r\n")
+	f.tasks.AssertExpectations(t)
+	f.repo.AssertCalled(t, "CreateObject", mock.Anything)
+}
+
+func TestCalDAVService_Put_Update(t *testing.T) {
+	f := setup()
+	f.repo.On("ListObjects", 1).Return([]caldav.Object{{UserID: 1, TaskID: 9, Name: "5E2A1F0C.ics", UID: "5E2A1F0C"}}, nil)
+	f.taskRepo.On("GetByID", 1, 9).Return(&task.Task{ID: 9, UserID: 1, Task: "Milk", Status: "pending", UpdatedAt: modified}, nil)
+	f.tasks.On("Replace", 1, 9, mock.MatchedBy(func(req *dto.ImportTaskRequest) bool {
+		return req.Task == "Buy milk" && req.Priority == "urgent"
+	})).Return(dto.GetTaskResponse{ID: 9}, nil)
+
+	current, err := f.service.Object(1, "inbox", "5E2A1F0C.ics")
+	require.NoError(t, err)
+
+	_, _, err = f.service.Put(1, "inbox", "5E2A1F0C.ics", []byte(todoData), Conditions{IfMatch: `"stale"`})
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	_, _, err = f.service.Put(1, "inbox", "5E2A1F0C.ics", []byte(todoData), Conditions{IfNoneMatch: "*"})
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	f.tasks.AssertNotCalled(t, "Replace", mock.Anything, mock.Anything, mock.Anything)
+
+	_, created, err := f.service.Put(1, "inbox", "5E2A1F0C.ics", []byte(todoData), Conditions{IfMatch: current.ETag})
+	require.NoError(t, err)
+	assert.False(t, created)
+	f.tasks.AssertExpectations(t)
+
+	other := strings.Replace(todoData, "UID:5E2A1F0C", "UID:OTHER", 1)
+	_, _, err = f.service.Put(1, "inbox", "5E2A1F0C.ics", []byte(other), Conditions{})
+	assert.ErrorIs(t, err, ErrUIDConflict, "a resource keeps its UID")
+}
+
+func TestCalDAVService_Put_Invalid(t *testing.T) {
+	f := setup()
+	f.repo.On("ListObjects", 1).Return([]caldav.Object{{UserID: 1, TaskID: 9, Name: "taken.ics", UID: "5E2A1F0C"}}, nil)
+	f.taskRepo.On("GetByID", 1, 9).Return(&task.Task{ID: 9, UserID: 1, Task: "Milk", Status: "pending"}, nil)
+	f.taskRepo.On("GetByID", 1, mock.Anything).Return((*task.Task)(nil), gorm.ErrRecordNotFound)
+
+	event := strings.ReplaceAll(todoData, "VTODO", "VEVENT")
+	_, _, err := f.service.Put(1, "inbox", "a.ics", []byte(event), Conditions{})
+	assert.ErrorIs(t, err, ErrUnsupportedComponent)
+
+	_, _, err = f.service.Put(1, "inbox", "a.ics", []byte("BEGIN:VCALENDAR\r\n"), Conditions{})
+	assert.ErrorIs(t, err, ErrInvalidCalendarData)
+
+	noSummary := strings.Replace(todoData, "SUMMARY:Buy milk\r\n", "", 1)
+	_, _, err = f.service.Put(1, "inbox", "a.ics", []byte(noSummary), Conditions{})
+	assert.ErrorIs(t, err, ErrInvalidCalendarData)
+
+	_, _, err = f.service.Put(1, "inbox", "a.ics", []byte(todoData), Conditions{})
+	assert.ErrorIs(t, err, ErrUIDConflict, "the UID belongs to another resource")
+
+	_, _, err = f.service.Put(1, "inbox", "a.ics", []byte(todoData), Conditions{IfMatch: "*"})
+	assert.ErrorIs(t, err, ErrPreconditionFailed, "If-Match needs an existing resource")
+
+	_, _, err = f.service.Put(1, "project-3", "a.ics", []byte(todoData), Conditions{})
+	assert.ErrorIs(t, err, ErrNotFound)
+	f.tasks.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+}
+
+func TestCalDAVService_Delete(t *testing.T) {
+	f := setup()
+	f.repo.On("ListObjects", 1).Return([]caldav.Object{}, nil)
+	f.taskRepo.On("GetByID", 1, 7).Return(&task.Task{ID: 7, UserID: 1, Task: "Old", Status: "pending"}, nil)
+	f.tasks.On("Delete", 1, 7).Return(nil)
+	f.repo.On("AddTombstone", &caldav.Tombstone{UserID: 1, TaskID: 7, Name: "task-7.ics"}).Return(nil)
+	f.repo.On("DeleteObject", 7).Return(nil)
+
+	assert.ErrorIs(t, f.service.Delete(1, "inbox", "task-7.ics", Conditions{IfMatch: `"stale"`}), ErrPreconditionFailed)
+	require.NoError(t, f.service.Delete(1, "inbox", "task-7.ics", Conditions{}))
+	f.tasks.AssertExpectations(t)
+	f.repo.AssertExpectations(t)
+}
+
+func TestCalDAVService_Changes(t *testing.T) {
+	project2 := 2
+	since := now.Add(-24 * time.Hour)
+	old := fmt.Sprintf("http://taskflow/ns/sync/%d-1", since.UnixNano())
+
+	f := setup()
+	f.repo.On("ListObjects", 1).Return([]caldav.Object{{TaskID: 5, Name: "moved.ics", UID: "moved"}}, nil)
+	f.repo.On("Changed", 1, since).Return([]task.Task{
+		{ID: 3, UserID: 1, Task: "Edited", Status: "pending", UpdatedAt: modified},
+		{ID: 4, UserID: 1, Task: "Trashed", Status: "pending", DeletedAt: gorm.DeletedAt{Time: modified, Valid: true}},
+		{ID: 5, UserID: 1, Task: "Moved", Status: "pending", ProjectID: &project2},
+	}, nil)
+	f.repo.On("Tombstones", 1, int64(1)).Return([]caldav.Tombstone{
+		{ID: 2, TaskID: 6, Name: "gone.ics"},
+		{ID: 3, TaskID: 7, Name: "elsewhere.ics", ProjectID: &project2},
+		{ID: 4, TaskID: 4, Name: "task-4.ics"},
+		{ID: 5, TaskID: 3, Name: "task-3.ics"},
+	}, nil)
+
+	changes, err := f.service.Changes(1, "inbox", old)
+	require.NoError(t, err)
+	assert.Equal(t, token, changes.SyncToken)
+	require.Len(t, changes.Updated, 1)
+	assert.Equal(t, "task-3.ics", changes.Updated[0].Name)
+	assert.Equal(t, []string{"task-4.ics", "moved.ics", "gone.ics"}, changes.Removed)
+
+	f.taskRepo.On("Find", 1, mock.Anything).Return([]task.Task{{ID: 3, UserID: 1, Task: "Edited", Status: "pending"}}, nil)
+	changes, err = f.service.Changes(1, "inbox", "")
+	require.NoError(t, err)
+	assert.Len(t, changes.Updated, 1, "an initial sync lists everything")
+	assert.Empty(t, changes.Removed)
+
+	expired := fmt.Sprintf("http://taskflow/ns/sync/%d-1", now.Add(-TombstoneTTL-time.Hour).UnixNano())
+	for _, bad := range []string{expired, "http://taskflow/ns/sync/x-1", "urn:other:1"} {
+		_, err = f.service.Changes(1, "inbox", bad)
+		assert.ErrorIs(t, err, ErrInvalidSyncToken, bad)
+	}
+	f.repo.On("Changed", 1, time.Time{}).Return([]task.Task{}, nil)
+	f.repo.On("Tombstones", 1, int64(0)).Return([]caldav.Tombstone{}, nil)
+	_, err = f.service.Changes(1, "inbox", "http://taskflow/ns/sync/0-0")
+	assert.NoError(t, err, "a token from before any task never expires")
+}
+
+func TestCalDAVService_HandleEvent(t *testing.T) {
+	project2 := 2
+	f := setup()
+	f.repo.On("GetObjectByTask", 7).Return(&caldav.Object{TaskID: 7, Name: "ABC.ics"}, nil)
+	f.repo.On("GetObjectByTask", 8).Return((*caldav.Object)(nil), gorm.ErrRecordNotFound)
+	f.repo.On("AddTombstone", mock.Anything).Return(nil)
+	f.repo.On("DeleteObject", mock.Anything).Return(nil)
+	f.repo.On("DeleteUserData", 1).Return(nil)
+
+	for _, e := range []events.Event{
+		events.TaskDeleted{UserID: 1, Task: dto.GetTaskResponse{ID: 7, ProjectID: &project2}},
+		events.TaskDeleted{UserID: 1, Task: dto.GetTaskResponse{ID: 8}},
+		events.UserDeleted{UserID: 1},
+	} {
+		rec, err := events.NewRecord(e, now)
+		require.NoError(t, err)
+		env := rec.Envelope()
+		require.NoError(t, f.service.HandleEvent(context.Background(), &env))
+	}
+	f.repo.AssertCalled(t, "AddTombstone", &caldav.Tombstone{UserID: 1, TaskID: 7, ProjectID: &project2, Name: "ABC.ics"})
+	f.repo.AssertCalled(t, "AddTombstone", &caldav.Tombstone{UserID: 1, TaskID: 8, Name: "task-8.ics"})
+	f.repo.AssertCalled(t, "DeleteObject", 7)
+	f.repo.AssertCalled(t, "DeleteUserData", 1)
+}
@@ -0,0 +1,384 @@
+package calendar_service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"taskflow/internal/domain/calendar"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_calendar"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg/ical"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxFeeds is how many feeds a user may have.
+	MaxFeeds = 20
+	// MaxImportTasks is how many VTODOs one import may contain.
+	MaxImportTasks = 1000
+
+	prodID = "-//TaskFlow//TaskFlow Tasks//EN"
+	// refreshInterval is how often calendar apps are asked to poll a feed.
+	refreshInterval = time.Hour
+)
+
+var (
+	ErrInvalidUser  = errors.New("invalid user")
+	ErrTooManyFeeds = errors.New("too many calendar feeds")
+	ErrUnknownFeed  = errors.New("unknown calendar feed")
+	ErrInvalidFile  = errors.New("file is not a valid iCalendar file")
+	ErrTooManyTasks = errors.New("file contains more than 1000 tasks")
+	ErrNoTasks      = errors.New("file contains no tasks (VTODO)")
+)
+
+type CalendarService struct {
+	repo     gorm_calendar.CalendarRepositoryInterface
+	taskRepo gorm_task.TaskRepositoryInterface
+	projects gorm_project.ProjectRepositoryInterface
+	tasks    task_service.TaskServiceInterface
+	now      func() time.Time
+}
+
+func NewCalendarService(repo gorm_calendar.CalendarRepositoryInterface, taskRepo gorm_task.TaskRepositoryInterface, projects gorm_project.ProjectRepositoryInterface, tasks task_service.TaskServiceInterface) *CalendarService {
+	return &CalendarService{
+		repo:     repo,
+		taskRepo: taskRepo,
+		projects: projects,
+		tasks:    tasks,
+		now:      time.Now,
+	}
+}
+
+var _ CalendarServiceInterface = (*CalendarService)(nil)
+
+// CreateFeed creates a feed of the user's tasks, or of one project's.
+func (s *CalendarService) CreateFeed(userID int, req *dto.CreateCalendarFeedRequest) (dto.CalendarFeedResponse, error) {
+	if userID == 0 {
+		return dto.CalendarFeedResponse{}, ErrInvalidUser
+	}
+	if req.ProjectID != nil {
+		if _, err := s.projects.GetByID(userID, *req.ProjectID); err != nil {
+			return dto.CalendarFeedResponse{}, err
+		}
+	}
+
+	existing, err := s.repo.List(userID)
+	if err != nil {
+		return dto.CalendarFeedResponse{}, err
+	}
+	if len(existing) >= MaxFeeds {
+		return dto.CalendarFeedResponse{}, ErrTooManyFeeds
+	}
+
+	token, err := randomHex(16)
+	if err != nil {
+		return dto.CalendarFeedResponse{}, err
+	}
+	f := calendar.Feed{
+		UserID:           userID,
+		Name:             strings.TrimSpace(req.Name),
+		Token:            token,
+		ProjectID:        req.ProjectID,
+		Component:        req.Component,
+		IncludeCompleted: req.IncludeCompleted,
+	}
+	if f.Component == "" {
+		f.Component = calendar.ComponentEvent
+	}
+	if err := s.repo.Create(&f); err != nil {
+		return dto.CalendarFeedResponse{}, err
+	}
+	return toFeedResponse(f), nil
+}
+
+func (s *CalendarService) ListFeeds(userID int) (dto.ListCalendarFeedsResponse, error) {
+	if userID == 0 {
+		return dto.ListCalendarFeedsResponse{}, ErrInvalidUser
+	}
+
+	feeds, err := s.repo.List(userID)
+	if err != nil {
+		return dto.ListCalendarFeedsResponse{}, err
+	}
+	resp := dto.ListCalendarFeedsResponse{Feeds: make([]dto.CalendarFeedResponse, 0, len(feeds))}
+	for _, f := range feeds {
+		resp.Feeds = append(resp.Feeds, toFeedResponse(f))
+	}
+	return resp, nil
+}
+
+// DeleteFeed deletes a feed, which revokes its URL.
+func (s *CalendarService) DeleteFeed(userID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+	return s.repo.Delete(userID, id)
+}
+
+// Feed renders the tasks with due dates of the feed with the given token.
+func (s *CalendarService) Feed(token string) (*ical.Component, error) {
+	if token == "" {
+		return nil, ErrUnknownFeed
+	}
+	f, err := s.repo.GetByToken(token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownFeed
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.taskRepo.Find(f.UserID, func(db *gorm.DB) *gorm.DB {
+		db = db.Where("tasks.due_at IS NOT NULL")
+		if f.ProjectID != nil {
+			db = db.Where("tasks.project_id = ?", *f.ProjectID)
+		}
+		if !f.IncludeCompleted {
+			db = db.Where("tasks.status <> ?", task.StatusCompleted)
+		}
+		return db
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	cal := ical.NewCalendar(prodID)
+	cal.Add("METHOD", "PUBLISH")
+	cal.AddText("X-WR-CALNAME", f.Name)
+	cal.AddText("NAME", f.Name)
+	cal.Add("REFRESH-INTERVAL", ical.FormatDuration(refreshInterval)).Params = ical.Params{"VALUE": {"DURATION"}}
+	cal.Add("X-PUBLISHED-TTL", ical.FormatDuration(refreshInterval))
+	for i := range tasks {
+		cal.Components = append(cal.Components, TaskComponent(&tasks[i], f.Component, now))
+	}
+	return cal, nil
+}
+
+// Import creates tasks from the VTODOs of an iCalendar file in one go.
+// VTODOs that don't make valid tasks, and cancelled ones, are skipped and
+// reported. Importing a file twice creates its tasks twice.
+func (s *CalendarService) Import(userID int, r io.Reader) (dto.CalendarImportResponse, error) {
+	if userID == 0 {
+		return dto.CalendarImportResponse{}, ErrInvalidUser
+	}
+
+	roots, err := ical.Decode(r)
+	if err != nil {
+		return dto.CalendarImportResponse{}, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	var todos []*ical.Component
+	for _, root := range roots {
+		todos = append(todos, root.Children("VTODO")...)
+	}
+	if len(todos) == 0 {
+		return dto.CalendarImportResponse{}, ErrNoTasks
+	}
+	if len(todos) > MaxImportTasks {
+		return dto.CalendarImportResponse{}, ErrTooManyTasks
+	}
+
+	resp := dto.CalendarImportResponse{Skipped: []dto.CalendarImportSkipped{}}
+	var reqs []dto.ImportTaskRequest
+	for _, todo := range todos {
+		req, err := TodoRequest(todo)
+		if err == nil && strings.EqualFold(todo.Text("STATUS"), "CANCELLED") {
+			err = errors.New("task is cancelled")
+		}
+		if err == nil {
+			err = task_service.ValidateImport(req)
+		}
+		if err != nil {
+			resp.Skipped = append(resp.Skipped, dto.CalendarImportSkipped{UID: todo.Text("UID"), Summary: todo.Text("SUMMARY"), Error: err.Error()})
+			continue
+		}
+		reqs = append(reqs, *req)
+	}
+
+	resp.Tasks = []dto.GetTaskResponse{}
+	if len(reqs) > 0 {
+		created, err := s.tasks.Import(userID, reqs)
+		if err != nil {
+			return dto.CalendarImportResponse{}, err
+		}
+		resp.Tasks = created
+	}
+	resp.Imported = len(resp.Tasks)
+	return resp, nil
+}
+
+// HandleEvent removes the feeds of deleted users.
+func (s *CalendarService) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	if e.Type != events.TypeUserDeleted {
+		return nil
+	}
+	return s.repo.DeleteUserFeeds(e.UserID)
+}
+
+// Priorities are mapped to the PRIORITY property, where 1 is the highest,
+// 9 the lowest and 0 undefined.
+var icalPriority = map[task.Priority]string{
+	task.PriorityUrgent: "1",
+	task.PriorityHigh:   "3",
+	task.PriorityMedium: "5",
+	task.PriorityLow:    "9",
+}
+
+var icalStatus = map[string]string{
+	task.StatusPending:    "NEEDS-ACTION",
+	task.StatusInProgress: "IN-PROCESS",
+	task.StatusCompleted:  "COMPLETED",
+}
+
+// TaskUID is the iCalendar UID of a task.
+func TaskUID(id int) string {
+	return fmt.Sprintf("task-%d@taskflow", id)
+}
+
+// TaskComponent renders a task as a VTODO or VEVENT.
+func TaskComponent(t *task.Task, component string, now time.Time) *ical.Component {
+	c := &ical.Component{Name: component}
+	c.Add("UID", TaskUID(t.ID))
+	c.AddDateTime("DTSTAMP", now)
+	c.AddDateTime("CREATED", t.CreatedAt)
+	c.AddDateTime("LAST-MODIFIED", t.UpdatedAt)
+	c.AddText("SUMMARY", t.Task)
+	if t.Description != "" {
+		c.AddText("DESCRIPTION", t.Description)
+	}
+
+	if component == calendar.ComponentTodo {
+		if t.DueAt != nil {
+			c.AddDateTime("DUE", *t.DueAt)
+		}
+		c.Add("STATUS", icalStatus[t.Status])
+		if t.CompletedAt != nil && t.Status == task.StatusCompleted {
+			c.AddDateTime("COMPLETED", *t.CompletedAt)
+		}
+	} else {
+		c.AddDateTime("DTSTART", *t.DueAt)
+		if t.EstimateMinutes != nil {
+			c.Add("DURATION", ical.FormatDuration(time.Duration(*t.EstimateMinutes)*time.Minute))
+		}
+		c.Add("TRANSP", "TRANSPARENT")
+	}
+
+	if p, ok := icalPriority[t.Priority]; ok {
+		c.Add("PRIORITY", p)
+	}
+	if len(t.Tags) > 0 {
+		names := make([]string, len(t.Tags))
+		for i, tag := range t.Tags {
+			names[i] = ical.EscapeText(tag.Name)
+		}
+		c.Add("CATEGORIES", strings.Join(names, ","))
+	}
+	if t.Recurrence != "" {
+		c.Add("RRULE", t.Recurrence)
+	}
+	return c
+}
+
+// TodoRequest maps a VTODO to a task. The due date falls back to DTSTART;
+// all-day dates are due at midnight UTC. Cancelled to-dos map to
+// completed tasks.
+func TodoRequest(todo *ical.Component) (*dto.ImportTaskRequest, error) {
+	title, description := task_service.FitTitle(todo.Text("SUMMARY"), strings.TrimSpace(todo.Text("DESCRIPTION")))
+	req := &dto.ImportTaskRequest{}
+	req.Task = title
+	req.Description = truncate(description, task_service.MaxDescriptionLength)
+
+	due := todo.Get("DUE")
+	if due == nil {
+		due = todo.Get("DTSTART")
+	}
+	if due != nil {
+		at, _, err := due.Time(time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("invalid due date %q", due.Value)
+		}
+		req.DueAt = &at
+	}
+
+	switch p, _ := strconv.Atoi(todo.Text("PRIORITY")); {
+	case p == 1:
+		req.Priority = "urgent"
+	case p >= 2 && p <= 4:
+		req.Priority = "high"
+	case p == 5:
+		req.Priority = "medium"
+	case p >= 6 && p <= 9:
+		req.Priority = "low"
+	}
+
+	for _, p := range todo.Props {
+		if p.Name != "CATEGORIES" {
+			continue
+		}
+		for _, name := range p.Texts() {
+			name = strings.TrimSpace(name)
+			if name != "" && utf8.RuneCountInString(name) <= task_service.MaxTagLength && len(req.Tags) < task_service.MaxTags {
+				req.Tags = append(req.Tags, name)
+			}
+		}
+	}
+
+	switch strings.ToUpper(todo.Text("STATUS")) {
+	case "COMPLETED", "CANCELLED":
+		req.Status = task.StatusCompleted
+		if p := todo.Get("COMPLETED"); p != nil {
+			if at, _, err := p.Time(time.UTC); err == nil {
+				req.CompletedAt = &at
+			}
+		}
+	case "IN-PROCESS":
+		req.Status = task.StatusInProgress
+	}
+
+	if rrule := todo.Get("RRULE"); rrule != nil {
+		req.Recurrence = rrule.Value
+	}
+	return req, nil
+}
+
+func toFeedResponse(f calendar.Feed) dto.CalendarFeedResponse {
+	return dto.CalendarFeedResponse{
+		ID:               f.ID,
+		Name:             f.Name,
+		ProjectID:        f.ProjectID,
+		Component:        f.Component,
+		IncludeCompleted: f.IncludeCompleted,
+		URL:              "/calendar/" + f.Token + ".ics",
+		CreatedAt:        f.CreatedAt,
+	}
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,22 @@
+package calendar_service
+
+import (
+	"context"
+	"io"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/pkg/ical"
+)
+
+type CalendarServiceInterface interface {
+	CreateFeed(userID int, req *dto.CreateCalendarFeedRequest) (dto.CalendarFeedResponse, error)
+	ListFeeds(userID int) (dto.ListCalendarFeedsResponse, error)
+	DeleteFeed(userID int, id int) error
+	// Feed renders the feed with the given token as a VCALENDAR.
+	Feed(token string) (*ical.Component, error)
+	// Import creates tasks from the VTODOs of an iCalendar file.
+	Import(userID int, r io.Reader) (dto.CalendarImportResponse, error)
+	// HandleEvent consumes domain events.
+	HandleEvent(ctx context.Context, e *events.Envelope) error
+}
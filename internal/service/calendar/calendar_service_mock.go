@@ -0,0 +1,48 @@
+package calendar_service
+
+import (
+	"context"
+	"io"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/pkg/ical"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CalendarServiceMock struct {
+	mock.Mock
+}
+
+var _ CalendarServiceInterface = (*CalendarServiceMock)(nil)
+
+func (m *CalendarServiceMock) CreateFeed(userID int, req *dto.CreateCalendarFeedRequest) (dto.CalendarFeedResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.CalendarFeedResponse), args.Error(1)
+}
+
+func (m *CalendarServiceMock) ListFeeds(userID int) (dto.ListCalendarFeedsResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.ListCalendarFeedsResponse), args.Error(1)
+}
+
+func (m *CalendarServiceMock) DeleteFeed(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *CalendarServiceMock) Feed(token string) (*ical.Component, error) {
+	args := m.Called(token)
+	return args.Get(0).(*ical.Component), args.Error(1)
+}
+
+func (m *CalendarServiceMock) Import(userID int, r io.Reader) (dto.CalendarImportResponse, error) {
+	args := m.Called(userID, r)
+	return args.Get(0).(dto.CalendarImportResponse), args.Error(1)
+}
+
+func (m *CalendarServiceMock) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
@@ -0,0 +1,228 @@
+package calendar_service
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/calendar"
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_calendar"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg/ical"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var now = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+type fixture struct {
+	repo     *gorm_calendar.CalendarRepoMock
+	taskRepo *gorm_task.TaskRepoMock
+	projects *gorm_project.ProjectRepoMock
+	tasks    *task_service.TaskServiceMock
+	service  *CalendarService
+}
+
+func setup() *fixture {
+	f := &fixture{
+		repo:     new(gorm_calendar.CalendarRepoMock),
+		taskRepo: new(gorm_task.TaskRepoMock),
+		projects: new(gorm_project.ProjectRepoMock),
+		tasks:    new(task_service.TaskServiceMock),
+	}
+	f.service = NewCalendarService(f.repo, f.taskRepo, f.projects, f.tasks)
+	f.service.now = func() time.Time { return now }
+	return f
+}
+
+func TestCalendarService_CreateFeed(t *testing.T) {
+	f := setup()
+	f.repo.On("List", 1).Return([]calendar.Feed{}, nil)
+	f.repo.On("Create", mock.MatchedBy(func(feed *calendar.Feed) bool {
+		return feed.Component == calendar.ComponentEvent && len(feed.Token) == 32
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*calendar.Feed).ID = 3
+	}).Return(nil)
+
+	resp, err := f.service.CreateFeed(1, &dto.CreateCalendarFeedRequest{Name: "All"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.ID)
+	assert.Equal(t, calendar.ComponentEvent, resp.Component)
+	assert.Regexp(t, `^/calendar/[0-9a-f]{32}\.ics$`, resp.URL)
+
+	other := 7
+	f.projects.On("GetByID", 1, 7).Return((*project.Project)(nil), gorm.ErrRecordNotFound)
+	_, err = f.service.CreateFeed(1, &dto.CreateCalendarFeedRequest{Name: "Work", ProjectID: &other})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	f.repo.On("List", 2).Return(make([]calendar.Feed, MaxFeeds), nil)
+	_, err = f.service.CreateFeed(2, &dto.CreateCalendarFeedRequest{Name: "One too many"})
+	assert.ErrorIs(t, err, ErrTooManyFeeds)
+	f.repo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func encode(t *testing.T, c *ical.Component) string {
+	var buf bytes.Buffer
+	require.NoError(t, ical.NewEncoder(&buf).Encode(c))
+	return buf.String()
+}
+
+func TestCalendarService_Feed(t *testing.T) {
+	due := time.Date(2025, 9, 10, 9, 0, 0, 0, time.UTC)
+	completed := time.Date(2025, 9, 9, 18, 0, 0, 0, time.UTC)
+	estimate := 90
+	tasks := []task.Task{
+		{ID: 4, Task: "Pay rent", Description: "Landlord, via bank", Status: "pending", Priority: task.PriorityHigh, DueAt: &due,
+			Recurrence: "FREQ=MONTHLY", Tags: []task.Tag{{Name: "finance"}, {Name: "home"}}, EstimateMinutes: &estimate, CreatedAt: now, UpdatedAt: now},
+		{ID: 5, Task: "File taxes", Status: "completed", DueAt: &due, CompletedAt: &completed, CreatedAt: now, UpdatedAt: now},
+	}
+
+	t.Run("events", func(t *testing.T) {
+		f := setup()
+		f.repo.On("GetByToken", "tok").Return(&calendar.Feed{UserID: 1, Name: "My tasks", Component: calendar.ComponentEvent}, nil)
+		f.taskRepo.On("Find", 1, mock.Anything).Return(tasks[:1], nil)
+
+		cal, err := f.service.Feed("tok")
+		require.NoError(t, err)
+		assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+			"VERSION:2.0\r\n"+
+			"PRODID:-//TaskFlow//TaskFlow Tasks//EN\r\n"+
+			"CALSCALE:GREGORIAN\r\n"+
+			"METHOD:PUBLISH\r\n"+
+			"X-WR-CALNAME:My tasks\r\n"+
+			"NAME:My tasks\r\n"+
+			"REFRESH-INTERVAL;VALUE=DURATION:PT1H\r\n"+
+			"X-PUBLISHED-TTL:PT1H\r\n"+
+			"BEGIN:VEVENT\r\n"+
+			"UID:task-4@taskflow\r\n"+
+			"DTSTAMP:20250908T120000Z\r\n"+
+			"CREATED:20250908T120000Z\r\n"+
+			"LAST-MODIFIED:20250908T120000Z\r\n"+
+			"SUMMARY:Pay rent\r\n"+
+			"DESCRIPTION:Landlord\\, via bank\r\n"+
+			"DTSTART:20250910T090000Z\r\n"+
+			"DURATION:PT1H30M\r\n"+
+			"TRANSP:TRANSPARENT\r\n"+
+			"PRIORITY:3\r\n"+
+			"CATEGORIES:finance,home\r\n"+
+			"RRULE:FREQ=MONTHLY\r\n"+
+			"END:VEVENT\r\n"+
+			"END:VCALENDAR\r\n", encode(t, cal))
+	})
+
+	t.Run("todos", func(t *testing.T) {
+		f := setup()
+		f.repo.On("GetByToken", "tok").Return(&calendar.Feed{UserID: 1, Name: "My tasks", Component: calendar.ComponentTodo, IncludeCompleted: true}, nil)
+		f.taskRepo.On("Find", 1, mock.Anything).Return(tasks, nil)
+
+		cal, err := f.service.Feed("tok")
+		require.NoError(t, err)
+		todos := cal.Children("VTODO")
+		require.Len(t, todos, 2)
+		assert.Equal(t, "20250910T090000Z", todos[0].Get("DUE").Value)
+		assert.Equal(t, "NEEDS-ACTION", todos[0].Get("STATUS").Value)
+		assert.Equal(t, "COMPLETED", todos[1].Get("STATUS").Value)
+		assert.Equal(t, "20250909T180000Z", todos[1].Get("COMPLETED").Value)
+		assert.Nil(t, todos[1].Get("PRIORITY"))
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		f := setup()
+		f.repo.On("GetByToken", "nope").Return((*calendar.Feed)(nil), gorm.ErrRecordNotFound)
+		_, err := f.service.Feed("nope")
+		assert.ErrorIs(t, err, ErrUnknownFeed)
+	})
+}
+
+const importFile = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Example//EN\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:a\r\n" +
+	"SUMMARY:Renew the car insurance before it lapses\r\n" +
+	"DESCRIPTION:Compare quotes\r\n" +
+	"DUE;TZID=Europe/Berlin:20250901T190000\r\n" +
+	"PRIORITY:2\r\n" +
+	"CATEGORIES:Car,Admin\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:b\r\n" +
+	"SUMMARY:Book dentist\r\n" +
+	"DTSTART;VALUE=DATE:20250905\r\n" +
+	"STATUS:COMPLETED\r\n" +
+	"COMPLETED:20250904T100000Z\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:c\r\n" +
+	"SUMMARY:Old plan\r\n" +
+	"STATUS:CANCELLED\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VTODO\r\n" +
+	"UID:d\r\n" +
+	"END:VTODO\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:e\r\n" +
+	"SUMMARY:Meeting\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestCalendarService_Import(t *testing.T) {
+	f := setup()
+	due := time.Date(2025, 9, 1, 17, 0, 0, 0, time.UTC)
+	start := time.Date(2025, 9, 5, 0, 0, 0, 0, time.UTC)
+	completed := time.Date(2025, 9, 4, 10, 0, 0, 0, time.UTC)
+	f.tasks.On("Import", 1, mock.MatchedBy(func(reqs []dto.ImportTaskRequest) bool {
+		if len(reqs) != 2 {
+			return false
+		}
+		a, b := reqs[0], reqs[1]
+		return a.Task == "Renew the car insur…" &&
+			a.Description == "Renew the car insurance before it lapses\n\nCompare quotes" &&
+			a.DueAt.Equal(due) && a.Priority == "high" && assert.ObjectsAreEqual([]string{"Car", "Admin"}, a.Tags) &&
+			a.Recurrence == "FREQ=YEARLY" && a.Status == "" &&
+			b.Task == "Book dentist" && b.DueAt.Equal(start) && b.Status == "completed" && b.CompletedAt.Equal(completed)
+	})).Return([]dto.GetTaskResponse{{ID: 1}, {ID: 2}}, nil)
+
+	resp, err := f.service.Import(1, strings.NewReader(importFile))
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Imported)
+	assert.Equal(t, []dto.CalendarImportSkipped{
+		{UID: "c", Summary: "Old plan", Error: "task is cancelled"},
+		{UID: "d", Error: task_service.ErrEmptyTaskName.Error()},
+	}, resp.Skipped)
+	f.tasks.AssertExpectations(t)
+}
+
+func TestCalendarService_Import_Errors(t *testing.T) {
+	f := setup()
+	_, err := f.service.Import(1, strings.NewReader("not a calendar"))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+	_, err = f.service.Import(1, strings.NewReader("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"))
+	assert.ErrorIs(t, err, ErrNoTasks)
+	_, err = f.service.Import(1, strings.NewReader("BEGIN:VCALENDAR\r\n"+strings.Repeat("BEGIN:VTODO\r\nSUMMARY:x\r\nEND:VTODO\r\n", MaxImportTasks+1)+"END:VCALENDAR\r\n"))
+	assert.ErrorIs(t, err, ErrTooManyTasks)
+	f.tasks.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+}
+
+func TestCalendarService_HandleEvent(t *testing.T) {
+	f := setup()
+	f.repo.On("DeleteUserFeeds", 1).Return(nil)
+
+	rec, err := events.NewRecord(events.UserDeleted{UserID: 1}, now)
+	require.NoError(t, err)
+	env := rec.Envelope()
+	require.NoError(t, f.service.HandleEvent(context.Background(), &env))
+	f.repo.AssertCalled(t, "DeleteUserFeeds", 1)
+}
@@ -0,0 +1,200 @@
+package comment_service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"taskflow/internal/domain/comment"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_comment"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_user"
+	"taskflow/pkg/markdown"
+)
+
+// EditWindow is how long after creation a comment may still be edited by its author.
+const EditWindow = 15 * time.Minute
+
+var (
+	ErrEmptyComment      = errors.New("comment body cannot be empty")
+	ErrNotCommentAuthor  = errors.New("only the author can modify this comment")
+	ErrEditWindowExpired = errors.New("comment can no longer be edited")
+	ErrInvalidUser       = errors.New("invalid user")
+)
+
+type CommentService struct {
+	repo     gorm_comment.CommentRepositoryInterface
+	taskRepo gorm_task.TaskRepositoryInterface
+	userRepo gorm_user.UserRepositoryInterface
+	now      func() time.Time
+}
+
+func NewCommentService(
+	repo gorm_comment.CommentRepositoryInterface,
+	taskRepo gorm_task.TaskRepositoryInterface,
+	userRepo gorm_user.UserRepositoryInterface,
+) *CommentService {
+	return &CommentService{repo: repo, taskRepo: taskRepo, userRepo: userRepo, now: time.Now}
+}
+
+var _ CommentServiceInterface = (*CommentService)(nil)
+
+func (s *CommentService) CreateComment(userID int, taskID int, req *dto.CreateCommentRequest) (dto.CommentResponse, error) {
+	if userID == 0 {
+		return dto.CommentResponse{}, ErrInvalidUser
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return dto.CommentResponse{}, ErrEmptyComment
+	}
+
+	// Anyone who can see the task can comment on it.
+	t, err := s.taskRepo.GetByID(userID, taskID)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	c := comment.Comment{
+		TaskID:   taskID,
+		UserID:   userID,
+		Body:     req.Body,
+		Mentions: s.resolveMentions(t, req.Body),
+	}
+	if err := s.repo.Create(&c); err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	return toCommentResponse(c), nil
+}
+
+func (s *CommentService) ListComments(userID int, taskID int, page int, limit int) (dto.ListCommentsResponse, error) {
+	if userID == 0 {
+		return dto.ListCommentsResponse{}, ErrInvalidUser
+	}
+
+	if _, err := s.taskRepo.GetByID(userID, taskID); err != nil {
+		return dto.ListCommentsResponse{}, err
+	}
+
+	comments, total, err := s.repo.ListByTask(taskID, (page-1)*limit, limit)
+	if err != nil {
+		return dto.ListCommentsResponse{}, err
+	}
+
+	resp := dto.ListCommentsResponse{
+		Comments: make([]dto.CommentResponse, 0, len(comments)),
+		Page:     page,
+		Limit:    limit,
+		Total:    total,
+	}
+	for _, c := range comments {
+		resp.Comments = append(resp.Comments, toCommentResponse(c))
+	}
+	return resp, nil
+}
+
+func (s *CommentService) UpdateComment(userID int, taskID int, id int, req *dto.UpdateCommentRequest) (dto.CommentResponse, error) {
+	if userID == 0 {
+		return dto.CommentResponse{}, ErrInvalidUser
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		return dto.CommentResponse{}, ErrEmptyComment
+	}
+
+	t, err := s.taskRepo.GetByID(userID, taskID)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+
+	c, err := s.repo.GetByID(taskID, id)
+	if err != nil {
+		return dto.CommentResponse{}, err
+	}
+	if c.UserID != userID {
+		return dto.CommentResponse{}, ErrNotCommentAuthor
+	}
+	if s.now().Sub(c.CreatedAt) > EditWindow {
+		return dto.CommentResponse{}, ErrEditWindowExpired
+	}
+
+	c.Body = req.Body
+	c.Mentions = s.resolveMentions(t, req.Body)
+	if err := s.repo.Update(c); err != nil {
+		return dto.CommentResponse{}, err
+	}
+	c.UpdatedAt = s.now()
+
+	return toCommentResponse(*c), nil
+}
+
+// DeleteComment soft-deletes a comment. The comment author and the task
+// owner may delete it.
+func (s *CommentService) DeleteComment(userID int, taskID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+
+	t, err := s.taskRepo.GetByID(userID, taskID)
+	if err != nil {
+		return err
+	}
+
+	c, err := s.repo.GetByID(taskID, id)
+	if err != nil {
+		return err
+	}
+	if c.UserID != userID && t.UserID != userID {
+		return ErrNotCommentAuthor
+	}
+
+	return s.repo.Delete(taskID, id)
+}
+
+// resolveMentions maps @email mentions to users who can see the task.
+// Other addresses are ignored, whether or not they belong to an account,
+// so a comment can't be used to find out who has one.
+func (s *CommentService) resolveMentions(t *task.Task, body string) []comment.Mention {
+	emails := markdown.ExtractMentions(body)
+	if len(emails) == 0 {
+		return nil
+	}
+	readers := s.readers(t)
+
+	var mentions []comment.Mention
+	for _, email := range emails {
+		if id, ok := readers[email]; ok {
+			mentions = append(mentions, comment.Mention{UserID: id})
+		}
+	}
+	return mentions
+}
+
+// readers returns the users who can see a task, keyed by lower-cased
+// email. Tasks aren't shared, so that is only the owner.
+func (s *CommentService) readers(t *task.Task) map[string]int {
+	owner, err := s.userRepo.GetByID(t.UserID)
+	if err != nil {
+		return nil
+	}
+	return map[string]int{strings.ToLower(owner.Email): owner.ID}
+}
+
+func toCommentResponse(c comment.Comment) dto.CommentResponse {
+	mentions := make([]int, 0, len(c.Mentions))
+	for _, m := range c.Mentions {
+		mentions = append(mentions, m.UserID)
+	}
+
+	return dto.CommentResponse{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		UserID:    c.UserID,
+		Body:      c.Body,
+		BodyHTML:  markdown.Render(c.Body),
+		Mentions:  mentions,
+		Edited:    c.UpdatedAt.Sub(c.CreatedAt) > time.Second,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
@@ -0,0 +1,10 @@
+package comment_service
+
+import "taskflow/internal/dto"
+
+type CommentServiceInterface interface {
+	CreateComment(userID int, taskID int, req *dto.CreateCommentRequest) (dto.CommentResponse, error)
+	ListComments(userID int, taskID int, page int, limit int) (dto.ListCommentsResponse, error)
+	UpdateComment(userID int, taskID int, id int, req *dto.UpdateCommentRequest) (dto.CommentResponse, error)
+	DeleteComment(userID int, taskID int, id int) error
+}
@@ -0,0 +1,33 @@
+package comment_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type CommentServiceMock struct {
+	mock.Mock
+}
+
+var _ CommentServiceInterface = (*CommentServiceMock)(nil)
+
+func (m *CommentServiceMock) CreateComment(userID int, taskID int, req *dto.CreateCommentRequest) (dto.CommentResponse, error) {
+	args := m.Called(userID, taskID, req)
+	return args.Get(0).(dto.CommentResponse), args.Error(1)
+}
+
+func (m *CommentServiceMock) ListComments(userID int, taskID int, page int, limit int) (dto.ListCommentsResponse, error) {
+	args := m.Called(userID, taskID, page, limit)
+	return args.Get(0).(dto.ListCommentsResponse), args.Error(1)
+}
+
+func (m *CommentServiceMock) UpdateComment(userID int, taskID int, id int, req *dto.UpdateCommentRequest) (dto.CommentResponse, error) {
+	args := m.Called(userID, taskID, id, req)
+	return args.Get(0).(dto.CommentResponse), args.Error(1)
+}
+
+func (m *CommentServiceMock) DeleteComment(userID int, taskID int, id int) error {
+	args := m.Called(userID, taskID, id)
+	return args.Error(0)
+}
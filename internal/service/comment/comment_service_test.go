@@ -0,0 +1,220 @@
+package comment_service
+
+import (
+	"errors"
+	"taskflow/internal/domain/comment"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/user"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_comment"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_user"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCommentService_CreateComment(t *testing.T) {
+	tests := []struct {
+		name       string
+		userID     int
+		body       string
+		setupMock  func(c *gorm_comment.CommentRepoMock, tr *gorm_task.TaskRepoMock, ur *gorm_user.MockUserRepository)
+		wantErr    error
+		wantMentns []int
+	}{
+		{
+			name:   "success - only users who can see the task are resolved",
+			userID: 1,
+			body:   "note to self @Sam@Example.com, ask @jane@example.com and @ghost@example.com",
+			setupMock: func(c *gorm_comment.CommentRepoMock, tr *gorm_task.TaskRepoMock, ur *gorm_user.MockUserRepository) {
+				tr.On("GetByID", 1, 10).Return(&task.Task{ID: 10, UserID: 1}, nil)
+				ur.On("GetByID", 1).Return(&user.User{ID: 1, Email: "sam@example.com"}, nil)
+				c.On("Create", mock.MatchedBy(func(cm *comment.Comment) bool {
+					return cm.TaskID == 10 && cm.UserID == 1 && len(cm.Mentions) == 1
+				})).Return(nil)
+			},
+			wantMentns: []int{1},
+		},
+		{
+			name:   "success - no mentions",
+			userID: 1,
+			body:   "looks good",
+			setupMock: func(c *gorm_comment.CommentRepoMock, tr *gorm_task.TaskRepoMock, ur *gorm_user.MockUserRepository) {
+				tr.On("GetByID", 1, 10).Return(&task.Task{ID: 10, UserID: 1}, nil)
+				c.On("Create", mock.Anything).Return(nil)
+			},
+			wantMentns: []int{},
+		},
+		{
+			name:      "failure - empty body",
+			userID:    1,
+			body:      "   ",
+			setupMock: func(c *gorm_comment.CommentRepoMock, tr *gorm_task.TaskRepoMock, ur *gorm_user.MockUserRepository) {},
+			wantErr:   ErrEmptyComment,
+		},
+		{
+			name:   "failure - task not visible",
+			userID: 1,
+			body:   "hi",
+			setupMock: func(c *gorm_comment.CommentRepoMock, tr *gorm_task.TaskRepoMock, ur *gorm_user.MockUserRepository) {
+				tr.On("GetByID", 1, 10).Return((*task.Task)(nil), gorm.ErrRecordNotFound)
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+		{
+			name:      "failure - invalid user",
+			userID:    0,
+			body:      "hi",
+			setupMock: func(c *gorm_comment.CommentRepoMock, tr *gorm_task.TaskRepoMock, ur *gorm_user.MockUserRepository) {},
+			wantErr:   ErrInvalidUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentRepo := new(gorm_comment.CommentRepoMock)
+			taskRepo := new(gorm_task.TaskRepoMock)
+			userRepo := new(gorm_user.MockUserRepository)
+			tt.setupMock(commentRepo, taskRepo, userRepo)
+
+			s := NewCommentService(commentRepo, taskRepo, userRepo)
+			got, err := s.CreateComment(tt.userID, 10, &dto.CreateCommentRequest{Body: tt.body})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantMentns, got.Mentions)
+				assert.NotEmpty(t, got.BodyHTML)
+			}
+			commentRepo.AssertExpectations(t)
+			taskRepo.AssertExpectations(t)
+			userRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func ptr(i int) *int {
+	return &i
+}
+
+func TestCommentService_ListComments(t *testing.T) {
+	commentRepo := new(gorm_comment.CommentRepoMock)
+	taskRepo := new(gorm_task.TaskRepoMock)
+	userRepo := new(gorm_user.MockUserRepository)
+
+	taskRepo.On("GetByID", 1, 10).Return(&task.Task{ID: 10, UserID: 1}, nil)
+	commentRepo.On("ListByTask", 10, 20, 20).Return([]comment.Comment{
+		{ID: 21, TaskID: 10, UserID: 1, Body: "**hi**"},
+	}, int64(21), nil)
+
+	s := NewCommentService(commentRepo, taskRepo, userRepo)
+	got, err := s.ListComments(1, 10, 2, 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(21), got.Total)
+	assert.Equal(t, 2, got.Page)
+	assert.Len(t, got.Comments, 1)
+	assert.Equal(t, "<p><strong>hi</strong></p>", got.Comments[0].BodyHTML)
+}
+
+func TestCommentService_UpdateComment(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		existing  *comment.Comment
+		updateErr error
+		wantErr   error
+	}{
+		{
+			name:     "success within window",
+			existing: &comment.Comment{ID: 5, TaskID: 10, UserID: 1, Body: "old", CreatedAt: now.Add(-5 * time.Minute)},
+		},
+		{
+			name:     "failure - window expired",
+			existing: &comment.Comment{ID: 5, TaskID: 10, UserID: 1, Body: "old", CreatedAt: now.Add(-EditWindow - time.Minute)},
+			wantErr:  ErrEditWindowExpired,
+		},
+		{
+			name:     "failure - not the author",
+			existing: &comment.Comment{ID: 5, TaskID: 10, UserID: 2, Body: "old", CreatedAt: now},
+			wantErr:  ErrNotCommentAuthor,
+		},
+		{
+			name:      "failure - repo error",
+			existing:  &comment.Comment{ID: 5, TaskID: 10, UserID: 1, Body: "old", CreatedAt: now},
+			updateErr: errors.New("db error"),
+			wantErr:   errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentRepo := new(gorm_comment.CommentRepoMock)
+			taskRepo := new(gorm_task.TaskRepoMock)
+			userRepo := new(gorm_user.MockUserRepository)
+
+			taskRepo.On("GetByID", 1, 10).Return(&task.Task{ID: 10, UserID: 1}, nil)
+			commentRepo.On("GetByID", 10, 5).Return(tt.existing, nil)
+			if tt.wantErr == nil || tt.updateErr != nil {
+				commentRepo.On("Update", mock.Anything).Return(tt.updateErr)
+			}
+
+			s := NewCommentService(commentRepo, taskRepo, userRepo)
+			s.now = func() time.Time { return now }
+
+			got, err := s.UpdateComment(1, 10, 5, &dto.UpdateCommentRequest{Body: "new"})
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "new", got.Body)
+				assert.True(t, got.Edited)
+			}
+			commentRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCommentService_DeleteComment(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		taskOwner int
+		author    int
+		wantErr   error
+	}{
+		{name: "author deletes", userID: 1, taskOwner: 1, author: 1},
+		{name: "task owner deletes someone else's comment", userID: 1, taskOwner: 1, author: 2},
+		{name: "neither author nor owner", userID: 3, taskOwner: 1, author: 2, wantErr: ErrNotCommentAuthor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentRepo := new(gorm_comment.CommentRepoMock)
+			taskRepo := new(gorm_task.TaskRepoMock)
+			userRepo := new(gorm_user.MockUserRepository)
+
+			taskRepo.On("GetByID", tt.userID, 10).Return(&task.Task{ID: 10, UserID: tt.taskOwner}, nil)
+			commentRepo.On("GetByID", 10, 5).Return(&comment.Comment{ID: 5, TaskID: 10, UserID: tt.author}, nil)
+			if tt.wantErr == nil {
+				commentRepo.On("Delete", 10, 5).Return(nil)
+			}
+
+			s := NewCommentService(commentRepo, taskRepo, userRepo)
+			err := s.DeleteComment(tt.userID, 10, 5)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			commentRepo.AssertExpectations(t)
+		})
+	}
+}
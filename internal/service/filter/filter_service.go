@@ -0,0 +1,295 @@
+package filter_service
+
+import (
+	"errors"
+	"time"
+
+	"taskflow/internal/domain/filter"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_filter"
+	"taskflow/internal/repository/gorm/gorm_task"
+	task_service "taskflow/internal/service/task"
+	"taskflow/internal/taskquery"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidUser   = errors.New("invalid user")
+	ErrBuiltinFilter = errors.New("built-in filters cannot be changed")
+	ErrInvalidRange  = errors.New("range start must be before its end")
+)
+
+type FilterService struct {
+	repo     gorm_filter.FilterRepositoryInterface
+	taskRepo gorm_task.TaskRepositoryInterface
+	users    task_service.UserLookup
+	now      func() time.Time
+}
+
+// Option configures optional FilterService collaborators.
+type Option func(*FilterService)
+
+// WithUsers makes filter queries resolve relative dates, such as the
+// built-in Today list's due:today, in the user's time zone instead of UTC.
+func WithUsers(u task_service.UserLookup) Option {
+	return func(s *FilterService) {
+		s.users = u
+	}
+}
+
+func NewFilterService(repo gorm_filter.FilterRepositoryInterface, taskRepo gorm_task.TaskRepositoryInterface, opts ...Option) *FilterService {
+	s := &FilterService{repo: repo, taskRepo: taskRepo, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ FilterServiceInterface = (*FilterService)(nil)
+
+func (s *FilterService) CreateFilter(userID int, req *dto.CreateFilterRequest) (dto.FilterResponse, error) {
+	if userID == 0 {
+		return dto.FilterResponse{}, ErrInvalidUser
+	}
+	if err := validateCriteria(req.Criteria); err != nil {
+		return dto.FilterResponse{}, err
+	}
+
+	f := filter.Filter{
+		UserID:   userID,
+		Name:     req.Name,
+		Criteria: toCriteria(req.Criteria),
+		SortBy:   sortOrDefault(req.SortBy),
+		SortDesc: req.SortDesc,
+	}
+	if err := s.repo.Create(&f); err != nil {
+		return dto.FilterResponse{}, err
+	}
+	return s.withCount(f)
+}
+
+// ListFilters returns the user's filters with a live count of matching
+// tasks. The built-in smart lists are created on first use.
+func (s *FilterService) ListFilters(userID int) (dto.ListFiltersResponse, error) {
+	if userID == 0 {
+		return dto.ListFiltersResponse{}, ErrInvalidUser
+	}
+	if err := s.ensureDefaults(userID); err != nil {
+		return dto.ListFiltersResponse{}, err
+	}
+
+	filters, err := s.repo.List(userID)
+	if err != nil {
+		return dto.ListFiltersResponse{}, err
+	}
+
+	resp := dto.ListFiltersResponse{Filters: make([]dto.FilterResponse, 0, len(filters))}
+	for _, f := range filters {
+		fr, err := s.withCount(f)
+		if err != nil {
+			return dto.ListFiltersResponse{}, err
+		}
+		resp.Filters = append(resp.Filters, fr)
+	}
+	return resp, nil
+}
+
+func (s *FilterService) GetFilter(userID int, id int) (dto.FilterResponse, error) {
+	if userID == 0 {
+		return dto.FilterResponse{}, ErrInvalidUser
+	}
+
+	f, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return dto.FilterResponse{}, err
+	}
+	return s.withCount(*f)
+}
+
+func (s *FilterService) UpdateFilter(userID int, id int, req *dto.UpdateFilterRequest) (dto.FilterResponse, error) {
+	if userID == 0 {
+		return dto.FilterResponse{}, ErrInvalidUser
+	}
+	if err := validateCriteria(req.Criteria); err != nil {
+		return dto.FilterResponse{}, err
+	}
+
+	f, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return dto.FilterResponse{}, err
+	}
+	if f.Builtin {
+		return dto.FilterResponse{}, ErrBuiltinFilter
+	}
+
+	f.Name = req.Name
+	f.Criteria = toCriteria(req.Criteria)
+	f.SortBy = sortOrDefault(req.SortBy)
+	f.SortDesc = req.SortDesc
+	if err := s.repo.Update(f); err != nil {
+		return dto.FilterResponse{}, err
+	}
+	return s.withCount(*f)
+}
+
+func (s *FilterService) DeleteFilter(userID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+
+	f, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return err
+	}
+	if f.Builtin {
+		return ErrBuiltinFilter
+	}
+	return s.repo.Delete(userID, id)
+}
+
+// RunFilter lists the tasks matching a saved filter in the filter's sort order.
+func (s *FilterService) RunFilter(userID int, id int) (dto.ListTasksResponse, error) {
+	if userID == 0 {
+		return dto.ListTasksResponse{}, ErrInvalidUser
+	}
+
+	f, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return dto.ListTasksResponse{}, err
+	}
+
+	scopes, err := s.scopes(*f)
+	if err != nil {
+		return dto.ListTasksResponse{}, err
+	}
+	scopes = append(scopes, gorm_task.SortScope(f.SortBy, f.SortDesc))
+
+	tasks, err := s.taskRepo.Find(userID, scopes...)
+	if err != nil {
+		return dto.ListTasksResponse{}, err
+	}
+	return toListResponse(tasks), nil
+}
+
+// Scopes returns the task repository scopes for one of the user's saved
+// filters, without its sort order.
+func (s *FilterService) Scopes(userID int, id int) ([]func(*gorm.DB) *gorm.DB, error) {
+	f, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.scopes(*f)
+}
+
+func (s *FilterService) ensureDefaults(userID int) error {
+	has, err := s.repo.HasBuiltins(userID)
+	if err != nil || has {
+		return err
+	}
+	return s.repo.CreateBatch(filter.Defaults(userID))
+}
+
+// scopes translates a filter's criteria into task repository scopes.
+func (s *FilterService) scopes(f filter.Filter) ([]func(*gorm.DB) *gorm.DB, error) {
+	scopes := []func(*gorm.DB) *gorm.DB{gorm_task.CriteriaScope(f.Criteria)}
+	if f.Criteria.Query == "" {
+		return scopes, nil
+	}
+
+	node, err := taskquery.Parse(f.Criteria.Query)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := task_service.UserLocation(s.users, f.UserID)
+	if err != nil {
+		return nil, err
+	}
+	scope, err := taskquery.Compile(node, s.now().In(loc))
+	if err != nil {
+		return nil, err
+	}
+	return append(scopes, scope), nil
+}
+
+func (s *FilterService) withCount(f filter.Filter) (dto.FilterResponse, error) {
+	scopes, err := s.scopes(f)
+	if err != nil {
+		return dto.FilterResponse{}, err
+	}
+	count, err := s.taskRepo.Count(f.UserID, scopes...)
+	if err != nil {
+		return dto.FilterResponse{}, err
+	}
+
+	resp := toFilterResponse(f)
+	resp.Count = count
+	return resp, nil
+}
+
+func validateCriteria(c dto.FilterCriteria) error {
+	if c.CreatedFrom != nil && c.CreatedTo != nil && !c.CreatedFrom.Before(*c.CreatedTo) {
+		return ErrInvalidRange
+	}
+	if c.UpdatedFrom != nil && c.UpdatedTo != nil && !c.UpdatedFrom.Before(*c.UpdatedTo) {
+		return ErrInvalidRange
+	}
+	if c.Query == "" {
+		return nil
+	}
+
+	node, err := taskquery.Parse(c.Query)
+	if err != nil {
+		return err
+	}
+	return taskquery.Validate(node)
+}
+
+func sortOrDefault(sortBy string) string {
+	if sortBy == "" {
+		return filter.SortCreatedAt
+	}
+	return sortBy
+}
+
+func toCriteria(c dto.FilterCriteria) filter.Criteria {
+	return filter.Criteria{
+		Status:      c.Status,
+		CreatedFrom: c.CreatedFrom,
+		CreatedTo:   c.CreatedTo,
+		UpdatedFrom: c.UpdatedFrom,
+		UpdatedTo:   c.UpdatedTo,
+		Text:        c.Text,
+		Query:       c.Query,
+	}
+}
+
+func toFilterResponse(f filter.Filter) dto.FilterResponse {
+	return dto.FilterResponse{
+		ID:      f.ID,
+		Name:    f.Name,
+		Builtin: f.Builtin,
+		Criteria: dto.FilterCriteria{
+			Status:      f.Criteria.Status,
+			CreatedFrom: f.Criteria.CreatedFrom,
+			CreatedTo:   f.Criteria.CreatedTo,
+			UpdatedFrom: f.Criteria.UpdatedFrom,
+			UpdatedTo:   f.Criteria.UpdatedTo,
+			Text:        f.Criteria.Text,
+			Query:       f.Criteria.Query,
+		},
+		SortBy:    f.SortBy,
+		SortDesc:  f.SortDesc,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+func toListResponse(tasks []task.Task) dto.ListTasksResponse {
+	var taskResponses []dto.GetTaskResponse
+	for _, t := range tasks {
+		taskResponses = append(taskResponses, task_service.ToTaskResponse(t))
+	}
+	return dto.ListTasksResponse{Tasks: taskResponses}
+}
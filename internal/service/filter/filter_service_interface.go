@@ -0,0 +1,12 @@
+package filter_service
+
+import "taskflow/internal/dto"
+
+type FilterServiceInterface interface {
+	CreateFilter(userID int, req *dto.CreateFilterRequest) (dto.FilterResponse, error)
+	ListFilters(userID int) (dto.ListFiltersResponse, error)
+	GetFilter(userID int, id int) (dto.FilterResponse, error)
+	UpdateFilter(userID int, id int, req *dto.UpdateFilterRequest) (dto.FilterResponse, error)
+	DeleteFilter(userID int, id int) error
+	RunFilter(userID int, id int) (dto.ListTasksResponse, error)
+}
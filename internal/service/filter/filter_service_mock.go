@@ -0,0 +1,43 @@
+package filter_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type FilterServiceMock struct {
+	mock.Mock
+}
+
+var _ FilterServiceInterface = (*FilterServiceMock)(nil)
+
+func (m *FilterServiceMock) CreateFilter(userID int, req *dto.CreateFilterRequest) (dto.FilterResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.FilterResponse), args.Error(1)
+}
+
+func (m *FilterServiceMock) ListFilters(userID int) (dto.ListFiltersResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.ListFiltersResponse), args.Error(1)
+}
+
+func (m *FilterServiceMock) GetFilter(userID int, id int) (dto.FilterResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.FilterResponse), args.Error(1)
+}
+
+func (m *FilterServiceMock) UpdateFilter(userID int, id int, req *dto.UpdateFilterRequest) (dto.FilterResponse, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(dto.FilterResponse), args.Error(1)
+}
+
+func (m *FilterServiceMock) DeleteFilter(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *FilterServiceMock) RunFilter(userID int, id int) (dto.ListTasksResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.ListTasksResponse), args.Error(1)
+}
@@ -0,0 +1,203 @@
+package filter_service
+
+import (
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/filter"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/user"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_filter"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_user"
+	"taskflow/internal/taskquery"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func scopeCount(n int) any {
+	return mock.MatchedBy(func(scopes []func(*gorm.DB) *gorm.DB) bool {
+		return len(scopes) == n
+	})
+}
+
+func TestFilterService_CreateFilter(t *testing.T) {
+	from := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	tests := []struct {
+		name      string
+		userID    int
+		req       dto.CreateFilterRequest
+		setupMock func(r *gorm_filter.FilterRepoMock, tr *gorm_task.TaskRepoMock)
+		wantErr   error
+		wantCount int64
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			req: dto.CreateFilterRequest{
+				Name:     "Work",
+				Criteria: dto.FilterCriteria{Status: "pending", CreatedFrom: &from, CreatedTo: &to, Query: "tag:work"},
+			},
+			setupMock: func(r *gorm_filter.FilterRepoMock, tr *gorm_task.TaskRepoMock) {
+				r.On("Create", mock.MatchedBy(func(f *filter.Filter) bool {
+					return f.UserID == 1 && f.SortBy == filter.SortCreatedAt && f.Criteria.Query == "tag:work"
+				})).Return(nil)
+				tr.On("Count", 1, scopeCount(2)).Return(int64(3), nil)
+			},
+			wantCount: 3,
+		},
+		{
+			name:   "failure - inverted range",
+			userID: 1,
+			req: dto.CreateFilterRequest{
+				Name:     "Backwards",
+				Criteria: dto.FilterCriteria{CreatedFrom: &to, CreatedTo: &from},
+			},
+			setupMock: func(r *gorm_filter.FilterRepoMock, tr *gorm_task.TaskRepoMock) {},
+			wantErr:   ErrInvalidRange,
+		},
+		{
+			name:      "failure - invalid user",
+			req:       dto.CreateFilterRequest{Name: "Work"},
+			setupMock: func(r *gorm_filter.FilterRepoMock, tr *gorm_task.TaskRepoMock) {},
+			wantErr:   ErrInvalidUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(gorm_filter.FilterRepoMock)
+			taskRepo := new(gorm_task.TaskRepoMock)
+			tt.setupMock(repo, taskRepo)
+
+			s := NewFilterService(repo, taskRepo)
+			got, err := s.CreateFilter(tt.userID, &tt.req)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantCount, got.Count)
+			}
+			repo.AssertExpectations(t)
+			taskRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFilterService_CreateFilter_InvalidQuery(t *testing.T) {
+	s := NewFilterService(new(gorm_filter.FilterRepoMock), new(gorm_task.TaskRepoMock))
+
+	_, err := s.CreateFilter(1, &dto.CreateFilterRequest{
+		Name:     "Broken",
+		Criteria: dto.FilterCriteria{Query: "priority>=extreme"},
+	})
+
+	var qerr *taskquery.Error
+	assert.ErrorAs(t, err, &qerr)
+	assert.Equal(t, 11, qerr.Pos)
+}
+
+func TestFilterService_ListFilters(t *testing.T) {
+	t.Run("seeds built-ins on first use", func(t *testing.T) {
+		repo := new(gorm_filter.FilterRepoMock)
+		taskRepo := new(gorm_task.TaskRepoMock)
+
+		defaults := filter.Defaults(1)
+		repo.On("HasBuiltins", 1).Return(false, nil)
+		repo.On("CreateBatch", defaults).Return(nil)
+		repo.On("List", 1).Return(defaults, nil)
+		taskRepo.On("Count", 1, scopeCount(2)).Return(int64(2), nil)
+
+		s := NewFilterService(repo, taskRepo)
+		got, err := s.ListFilters(1)
+
+		assert.NoError(t, err)
+		assert.Len(t, got.Filters, 3)
+		assert.Equal(t, "Today", got.Filters[0].Name)
+		assert.True(t, got.Filters[0].Builtin)
+		assert.Equal(t, int64(2), got.Filters[0].Count)
+		repo.AssertExpectations(t)
+		taskRepo.AssertExpectations(t)
+	})
+
+	t.Run("does not seed twice", func(t *testing.T) {
+		repo := new(gorm_filter.FilterRepoMock)
+		taskRepo := new(gorm_task.TaskRepoMock)
+
+		repo.On("HasBuiltins", 1).Return(true, nil)
+		repo.On("List", 1).Return([]filter.Filter{{ID: 9, UserID: 1, Name: "Mine", SortBy: filter.SortCreatedAt}}, nil)
+		taskRepo.On("Count", 1, scopeCount(1)).Return(int64(5), nil)
+
+		s := NewFilterService(repo, taskRepo)
+		got, err := s.ListFilters(1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []dto.FilterResponse{{ID: 9, Name: "Mine", SortBy: filter.SortCreatedAt, Count: 5}}, got.Filters)
+		repo.AssertNotCalled(t, "CreateBatch", mock.Anything)
+	})
+}
+
+func TestFilterService_UpdateAndDelete_Builtin(t *testing.T) {
+	repo := new(gorm_filter.FilterRepoMock)
+	repo.On("GetByID", 1, 4).Return(&filter.Filter{ID: 4, UserID: 1, Builtin: true}, nil)
+	s := NewFilterService(repo, new(gorm_task.TaskRepoMock))
+
+	_, err := s.UpdateFilter(1, 4, &dto.UpdateFilterRequest{Name: "Renamed"})
+	assert.ErrorIs(t, err, ErrBuiltinFilter)
+
+	err = s.DeleteFilter(1, 4)
+	assert.ErrorIs(t, err, ErrBuiltinFilter)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
+
+func TestFilterService_RunFilter(t *testing.T) {
+	repo := new(gorm_filter.FilterRepoMock)
+	taskRepo := new(gorm_task.TaskRepoMock)
+
+	repo.On("GetByID", 1, 4).Return(&filter.Filter{
+		ID:       4,
+		UserID:   1,
+		Criteria: filter.Criteria{Query: "due<now"},
+		SortBy:   filter.SortDueAt,
+	}, nil)
+	// criteria, compiled query, sort
+	taskRepo.On("Find", 1, scopeCount(3)).Return([]task.Task{{ID: 2, Task: "Pay rent", Status: "pending"}}, nil)
+
+	s := NewFilterService(repo, taskRepo)
+	got, err := s.RunFilter(1, 4)
+
+	assert.NoError(t, err)
+	assert.Equal(t, dto.ListTasksResponse{Tasks: []dto.GetTaskResponse{{ID: 2, Task: "Pay rent", Status: "pending"}}}, got)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestFilterService_Scopes_UserTimeZone(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	repo := new(gorm_filter.FilterRepoMock)
+	repo.On("GetByID", 1, 4).Return(&filter.Filter{ID: 4, UserID: 1, Criteria: filter.Criteria{Query: "due:today"}}, nil)
+	users := new(gorm_user.MockUserRepository)
+	users.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "America/New_York"}, nil)
+
+	s := NewFilterService(repo, new(gorm_task.TaskRepoMock), WithUsers(users))
+	// 02:00 UTC on the 10th is still the evening of the 9th in New York.
+	s.now = func() time.Time { return time.Date(2025, 9, 10, 2, 0, 0, 0, time.UTC) }
+	scopes, err := s.Scopes(1, 4)
+	require.NoError(t, err)
+
+	vars := db.Scopes(scopes...).Find(&[]task.Task{}).Statement.Vars
+	require.Len(t, vars, 2)
+	assert.True(t, time.Date(2025, 9, 9, 0, 0, 0, 0, newYork).Equal(vars[0].(time.Time)), "start is %v", vars[0])
+	assert.True(t, time.Date(2025, 9, 10, 0, 0, 0, 0, newYork).Equal(vars[1].(time.Time)), "end is %v", vars[1])
+}
@@ -0,0 +1,580 @@
+package importjob_service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"taskflow/internal/domain/importjob"
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/importer"
+	"taskflow/internal/repository/gorm/gorm_importjob"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/scheduler"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg/blobstore"
+
+	"gorm.io/gorm"
+)
+
+// KindRun is the scheduler job kind that runs an import.
+const KindRun = "imports.run"
+
+const (
+	// MaxTasks is the most tasks a file may hold.
+	MaxTasks = 10000
+	// MaxErrors is how many of the tasks that could not be imported a job
+	// lists; the rest are only counted.
+	MaxErrors = 100
+	// batchSize is how many tasks are imported in one transaction. Progress
+	// is saved after each batch.
+	batchSize = 100
+	// maxProjectName is the length of a project name.
+	maxProjectName = 100
+)
+
+var (
+	ErrInvalidUser   = errors.New("invalid user")
+	ErrUnknownSource = errors.New("unknown import source")
+	ErrEmptyFile     = errors.New("file is empty")
+	ErrInvalidFile   = importer.ErrInvalidFile
+	ErrNoTasks       = errors.New("file contains no tasks")
+	ErrTooManyTasks  = fmt.Errorf("file contains more than %d tasks", MaxTasks)
+	ErrJobRunning    = errors.New("import is running; wait for it to finish")
+)
+
+// job is the payload of a KindRun job.
+type job struct {
+	ImportID int `json:"import_id"`
+	UserID   int `json:"user_id"`
+	Run      int `json:"run"`
+}
+
+type ImportJobService struct {
+	repo      gorm_importjob.ImportJobRepositoryInterface
+	taskRepo  gorm_task.TaskRepositoryInterface
+	projects  gorm_project.ProjectRepositoryInterface
+	tasks     task_service.TaskServiceInterface
+	blobs     blobstore.BlobStore
+	queue     scheduler.Queue
+	importers map[string]importer.Importer
+	now       func() time.Time
+}
+
+// NewImportJobService builds the service with importers keyed by source
+// name, such as importer.SourceTodoist.
+func NewImportJobService(
+	repo gorm_importjob.ImportJobRepositoryInterface,
+	taskRepo gorm_task.TaskRepositoryInterface,
+	projects gorm_project.ProjectRepositoryInterface,
+	tasks task_service.TaskServiceInterface,
+	blobs blobstore.BlobStore,
+	queue scheduler.Queue,
+	importers map[string]importer.Importer,
+) *ImportJobService {
+	return &ImportJobService{
+		repo:      repo,
+		taskRepo:  taskRepo,
+		projects:  projects,
+		tasks:     tasks,
+		blobs:     blobs,
+		queue:     queue,
+		importers: importers,
+		now:       time.Now,
+	}
+}
+
+var _ ImportJobServiceInterface = (*ImportJobService)(nil)
+
+// Create stores a file and queues its import. The file is read right away,
+// so files that can't be imported are rejected here.
+func (s *ImportJobService) Create(userID int, source string, fileName string, r io.Reader) (dto.ImportJobResponse, error) {
+	if userID == 0 {
+		return dto.ImportJobResponse{}, ErrInvalidUser
+	}
+	imp, ok := s.importers[source]
+	if !ok {
+		return dto.ImportJobResponse{}, fmt.Errorf("%w %q; use %s", ErrUnknownSource, source, strings.Join(s.Sources(), ", "))
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return dto.ImportJobResponse{}, err
+	}
+	if len(data) == 0 {
+		return dto.ImportJobResponse{}, ErrEmptyFile
+	}
+	tasks, err := imp.Parse(bytes.NewReader(data))
+	if err != nil {
+		return dto.ImportJobResponse{}, err
+	}
+	if len(tasks) == 0 {
+		return dto.ImportJobResponse{}, ErrNoTasks
+	}
+	if len(tasks) > MaxTasks {
+		return dto.ImportJobResponse{}, ErrTooManyTasks
+	}
+
+	key, err := storageKey(userID)
+	if err != nil {
+		return dto.ImportJobResponse{}, err
+	}
+	if err := s.blobs.Put(key, bytes.NewReader(data), int64(len(data)), "application/octet-stream"); err != nil {
+		return dto.ImportJobResponse{}, fmt.Errorf("failed to store file: %w", err)
+	}
+	j := importjob.Job{
+		UserID:     userID,
+		Source:     source,
+		FileName:   cleanFileName(fileName),
+		StorageKey: key,
+		Status:     importjob.StatusPending,
+		Run:        1,
+		Total:      len(tasks),
+	}
+	if err := s.repo.Create(&j); err != nil {
+		_ = s.blobs.Delete(key)
+		return dto.ImportJobResponse{}, err
+	}
+	if err := s.enqueue(&j); err != nil {
+		return dto.ImportJobResponse{}, err
+	}
+	return toImportJobResponse(j), nil
+}
+
+func (s *ImportJobService) Get(userID int, id int) (dto.ImportJobResponse, error) {
+	if userID == 0 {
+		return dto.ImportJobResponse{}, ErrInvalidUser
+	}
+
+	j, err := s.repo.Get(userID, id)
+	if err != nil {
+		return dto.ImportJobResponse{}, err
+	}
+	return toImportJobResponse(*j), nil
+}
+
+func (s *ImportJobService) List(userID int) (dto.ListImportJobsResponse, error) {
+	if userID == 0 {
+		return dto.ListImportJobsResponse{}, ErrInvalidUser
+	}
+
+	jobs, err := s.repo.List(userID)
+	if err != nil {
+		return dto.ListImportJobsResponse{}, err
+	}
+	resp := dto.ListImportJobsResponse{Jobs: make([]dto.ImportJobResponse, 0, len(jobs))}
+	for _, j := range jobs {
+		resp.Jobs = append(resp.Jobs, toImportJobResponse(j))
+	}
+	return resp, nil
+}
+
+// Rerun imports a job's file again. Tasks that were imported before, by
+// any job, are skipped, so only tasks that failed, or that a run didn't
+// get to, are added. A pending job is queued again, in case it was lost.
+func (s *ImportJobService) Rerun(userID int, id int) (dto.ImportJobResponse, error) {
+	if userID == 0 {
+		return dto.ImportJobResponse{}, ErrInvalidUser
+	}
+
+	j, err := s.repo.Get(userID, id)
+	if err != nil {
+		return dto.ImportJobResponse{}, err
+	}
+	if j.Status == importjob.StatusRunning {
+		return dto.ImportJobResponse{}, ErrJobRunning
+	}
+
+	j.Run++
+	j.Status = importjob.StatusPending
+	j.Processed, j.Imported, j.Skipped, j.Failed = 0, 0, 0, 0
+	j.Errors, j.Error = nil, ""
+	j.StartedAt, j.FinishedAt = nil, nil
+	if err := s.repo.Update(j); err != nil {
+		return dto.ImportJobResponse{}, err
+	}
+	if err := s.enqueue(j); err != nil {
+		return dto.ImportJobResponse{}, err
+	}
+	return toImportJobResponse(*j), nil
+}
+
+// Delete removes a job and its file. The tasks it imported are kept.
+func (s *ImportJobService) Delete(userID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+
+	j, err := s.repo.Get(userID, id)
+	if err != nil {
+		return err
+	}
+	if j.Status == importjob.StatusRunning {
+		return ErrJobRunning
+	}
+	if err := s.repo.Delete(userID, id); err != nil {
+		return err
+	}
+	return s.blobs.Delete(j.StorageKey)
+}
+
+func (s *ImportJobService) Sources() []string {
+	sources := make([]string, 0, len(s.importers))
+	for source := range s.importers {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// HandleEvent removes the jobs and files of deleted users.
+func (s *ImportJobService) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	if e.Type != events.TypeUserDeleted {
+		return nil
+	}
+	jobs, err := s.repo.List(e.UserID)
+	if err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		if err := s.blobs.Delete(j.StorageKey); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteUserJobs(e.UserID)
+}
+
+func (s *ImportJobService) enqueue(j *importjob.Job) error {
+	sj, err := scheduler.NewJob(KindRun, fmt.Sprintf("import:%d:%d", j.ID, j.Run), s.now(), job{
+		ImportID: j.ID,
+		UserID:   j.UserID,
+		Run:      j.Run,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.queue.Enqueue(sj)
+	return err
+}
+
+// Run imports a job's file in batches, saving progress after each one.
+// An attempt that fails, or runs out of time, returns an error so that the
+// scheduler retries it; the retry skips the tasks already imported. Runs
+// that were replaced by a re-run are dropped.
+func (s *ImportJobService) Run(ctx context.Context, sj *scheduler.Job) error {
+	var p job
+	if err := sj.Decode(&p); err != nil {
+		return err
+	}
+
+	j, err := s.repo.Get(p.UserID, p.ImportID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if j.Run != p.Run || j.Finished() {
+		return nil
+	}
+
+	runErr := s.run(ctx, j)
+	if runErr != nil && sj.Attempts >= sj.MaxAttempts {
+		if err := s.fail(j, runErr); err != nil {
+			return err
+		}
+	}
+	return runErr
+}
+
+// run makes one attempt at importing a job's file. Files that can't be
+// imported fail the job rather than return an error, as retrying won't
+// help.
+func (s *ImportJobService) run(ctx context.Context, j *importjob.Job) error {
+	imp, ok := s.importers[j.Source]
+	if !ok {
+		return s.fail(j, fmt.Errorf("%w %q", ErrUnknownSource, j.Source))
+	}
+	rc, err := s.blobs.Get(j.StorageKey)
+	if errors.Is(err, blobstore.ErrNotFound) {
+		return s.fail(j, errors.New("the imported file is missing"))
+	}
+	if err != nil {
+		return err
+	}
+	items, err := imp.Parse(rc)
+	rc.Close()
+	if err != nil {
+		return s.fail(j, err)
+	}
+
+	now := s.now()
+	j.Status = importjob.StatusRunning
+	if j.StartedAt == nil {
+		j.StartedAt = &now
+	}
+	j.Total = len(items)
+	j.Processed, j.Imported, j.Skipped, j.Failed = 0, 0, 0, 0
+	j.Errors = nil
+	if err := s.repo.Update(j); err != nil {
+		return err
+	}
+
+	imported, err := s.taskRepo.ExternalIDs(j.UserID, j.Source+":")
+	if err != nil {
+		return err
+	}
+	projects, err := s.projects.List(j.UserID)
+	if err != nil {
+		return err
+	}
+	projectIDs := make(map[string]int, len(projects))
+	for _, p := range projects {
+		projectIDs[strings.ToLower(p.Name)] = p.ID
+	}
+
+	for _, level := range levels(items) {
+		for start := 0; start < len(level); start += batchSize {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			batch := level[start:min(start+batchSize, len(level))]
+			if err := s.importBatch(j, batch, imported, projectIDs); err != nil {
+				return err
+			}
+			if err := s.repo.Update(j); err != nil {
+				return err
+			}
+		}
+	}
+
+	finished := s.now()
+	j.Status = importjob.StatusSucceeded
+	j.FinishedAt = &finished
+	return s.repo.Update(j)
+}
+
+// importBatch imports the tasks of batch that aren't imported yet and
+// records the new ones in imported. A task the task service refuses, for
+// example because its board column is at its WIP limit, fails on its own
+// and the rest of the batch is imported.
+func (s *ImportJobService) importBatch(j *importjob.Job, batch []importer.Task, imported map[string]int, projectIDs map[string]int) error {
+	var reqs []dto.ImportTaskRequest
+	var items []*importer.Task
+	queued := map[string]bool{}
+	for i := range batch {
+		t := &batch[i]
+		if t.ID == "" {
+			s.itemFailed(j, t, errors.New("task has no ID"))
+			continue
+		}
+		externalID := j.Source + ":" + t.ID
+		if _, ok := imported[externalID]; ok || queued[externalID] {
+			j.Skipped++
+			continue
+		}
+		req, err := s.request(j, t, externalID, imported, projectIDs)
+		if err != nil {
+			s.itemFailed(j, t, err)
+			continue
+		}
+		queued[externalID] = true
+		reqs = append(reqs, *req)
+		items = append(items, t)
+	}
+
+	for len(reqs) > 0 {
+		created, err := s.tasks.Import(j.UserID, reqs)
+		var importErr *task_service.ImportError
+		if errors.As(err, &importErr) && importErr.Index < len(reqs) {
+			i := importErr.Index
+			s.itemFailed(j, items[i], importErr.Err)
+			reqs = append(reqs[:i:i], reqs[i+1:]...)
+			items = append(items[:i:i], items[i+1:]...)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for i := range created {
+			imported[reqs[i].ExternalID] = created[i].ID
+		}
+		j.Imported += len(created)
+		break
+	}
+	j.Processed += len(batch)
+	return nil
+}
+
+// request maps a task onto a new TaskFlow task. Names that are too long
+// are cut short and kept in full in the description; projects are matched
+// by name, ignoring case, and created when missing.
+func (s *ImportJobService) request(j *importjob.Job, t *importer.Task, externalID string, imported map[string]int, projectIDs map[string]int) (*dto.ImportTaskRequest, error) {
+	name, description := task_service.FitTitle(t.Name, strings.TrimSpace(t.Description))
+	req := &dto.ImportTaskRequest{
+		CreateTaskRequest: dto.CreateTaskRequest{
+			Task:        name,
+			Description: truncate(description, task_service.MaxDescriptionLength),
+			Priority:    t.Priority,
+			DueAt:       t.DueAt,
+			Tags:        tags(t.Labels),
+		},
+		Recurrence: t.Recurrence,
+		ExternalID: externalID,
+	}
+	switch {
+	case t.Completed:
+		req.Status = task.StatusCompleted
+		req.CompletedAt = t.CompletedAt
+	case t.InProgress:
+		req.Status = task.StatusInProgress
+	}
+	if t.ParentID != "" {
+		if id, ok := imported[j.Source+":"+t.ParentID]; ok {
+			req.ParentID = &id
+		}
+	}
+	if err := task_service.ValidateImport(req); err != nil {
+		return nil, err
+	}
+
+	if projectName := truncate(strings.TrimSpace(t.Project), maxProjectName); projectName != "" {
+		id, ok := projectIDs[strings.ToLower(projectName)]
+		if !ok {
+			p := project.Project{UserID: j.UserID, Name: projectName}
+			if err := s.projects.Create(&p); err != nil {
+				return nil, err
+			}
+			id = p.ID
+			projectIDs[strings.ToLower(projectName)] = id
+		}
+		req.ProjectID = &id
+	}
+	return req, nil
+}
+
+func (s *ImportJobService) itemFailed(j *importjob.Job, t *importer.Task, err error) {
+	j.Failed++
+	if len(j.Errors) < MaxErrors {
+		j.Errors = append(j.Errors, importjob.ItemError{ID: t.ID, Name: t.Name, Message: err.Error()})
+	}
+}
+
+func (s *ImportJobService) fail(j *importjob.Job, cause error) error {
+	now := s.now()
+	j.Status = importjob.StatusFailed
+	j.Error = cause.Error()
+	j.FinishedAt = &now
+	return s.repo.Update(j)
+}
+
+// levels orders tasks so that parents are imported before their subtasks:
+// first the tasks without a parent in the file, then their subtasks, and
+// so on. Tasks in a cycle of parents go first.
+func levels(items []importer.Task) [][]importer.Task {
+	byID := make(map[string]int, len(items))
+	for i, t := range items {
+		byID[t.ID] = i
+	}
+
+	var lv [][]importer.Task
+	for _, t := range items {
+		depth, cur := 0, t
+		for cur.ParentID != "" && depth <= len(items) {
+			p, ok := byID[cur.ParentID]
+			if !ok {
+				break
+			}
+			depth++
+			cur = items[p]
+		}
+		if depth > len(items) {
+			depth = 0
+		}
+		for len(lv) <= depth {
+			lv = append(lv, nil)
+		}
+		lv[depth] = append(lv[depth], t)
+	}
+	return lv
+}
+
+// tags turns labels into tag names, within the limits of a task.
+func tags(labels []string) []string {
+	names := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = truncate(strings.TrimSpace(l), task_service.MaxTagLength)
+	}
+	names = task_service.NormalizeTagNames(names)
+	if len(names) > task_service.MaxTags {
+		names = names[:task_service.MaxTags]
+	}
+	return names
+}
+
+func toImportJobResponse(j importjob.Job) dto.ImportJobResponse {
+	resp := dto.ImportJobResponse{
+		ID:         j.ID,
+		Source:     j.Source,
+		FileName:   j.FileName,
+		Status:     j.Status,
+		Run:        j.Run,
+		Total:      j.Total,
+		Processed:  j.Processed,
+		Imported:   j.Imported,
+		Skipped:    j.Skipped,
+		Failed:     j.Failed,
+		Errors:     make([]dto.ImportJobError, 0, len(j.Errors)),
+		Error:      j.Error,
+		StartedAt:  j.StartedAt,
+		FinishedAt: j.FinishedAt,
+		CreatedAt:  j.CreatedAt,
+	}
+	if j.Total > 0 {
+		resp.Progress = j.Processed * 100 / j.Total
+	}
+	for _, e := range j.Errors {
+		resp.Errors = append(resp.Errors, dto.ImportJobError{ID: e.ID, Name: e.Name, Message: e.Message})
+	}
+	return resp
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+func storageKey(userID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("imports/%d/%s", userID, hex.EncodeToString(b)), nil
+}
+
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = name[:255]
+	}
+	return name
+}
@@ -0,0 +1,24 @@
+package importjob_service
+
+import (
+	"context"
+	"io"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/scheduler"
+)
+
+type ImportJobServiceInterface interface {
+	Create(userID int, source string, fileName string, r io.Reader) (dto.ImportJobResponse, error)
+	Get(userID int, id int) (dto.ImportJobResponse, error)
+	List(userID int) (dto.ListImportJobsResponse, error)
+	Rerun(userID int, id int) (dto.ImportJobResponse, error)
+	Delete(userID int, id int) error
+	// Sources lists the formats that can be imported.
+	Sources() []string
+	// HandleEvent consumes domain events.
+	HandleEvent(ctx context.Context, e *events.Envelope) error
+	// Run handles KindRun jobs.
+	Run(ctx context.Context, job *scheduler.Job) error
+}
@@ -0,0 +1,58 @@
+package importjob_service
+
+import (
+	"context"
+	"io"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/scheduler"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ImportJobServiceMock struct {
+	mock.Mock
+}
+
+var _ ImportJobServiceInterface = (*ImportJobServiceMock)(nil)
+
+func (m *ImportJobServiceMock) Create(userID int, source string, fileName string, r io.Reader) (dto.ImportJobResponse, error) {
+	args := m.Called(userID, source, fileName, r)
+	return args.Get(0).(dto.ImportJobResponse), args.Error(1)
+}
+
+func (m *ImportJobServiceMock) Get(userID int, id int) (dto.ImportJobResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.ImportJobResponse), args.Error(1)
+}
+
+func (m *ImportJobServiceMock) List(userID int) (dto.ListImportJobsResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.ListImportJobsResponse), args.Error(1)
+}
+
+func (m *ImportJobServiceMock) Rerun(userID int, id int) (dto.ImportJobResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.ImportJobResponse), args.Error(1)
+}
+
+func (m *ImportJobServiceMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *ImportJobServiceMock) Sources() []string {
+	args := m.Called()
+	return args.Get(0).([]string)
+}
+
+func (m *ImportJobServiceMock) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	args := m.Called(e)
+	return args.Error(0)
+}
+
+func (m *ImportJobServiceMock) Run(ctx context.Context, job *scheduler.Job) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
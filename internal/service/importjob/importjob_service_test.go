@@ -0,0 +1,350 @@
+package importjob_service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/importjob"
+	"taskflow/internal/domain/project"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/importer"
+	"taskflow/internal/repository/gorm/gorm_importjob"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/scheduler"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg/blobstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var now = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+// fakeImporter reads the tasks it was given from any file but "bad".
+type fakeImporter struct {
+	tasks []importer.Task
+}
+
+func (f *fakeImporter) Parse(r io.Reader) ([]importer.Task, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "bad" {
+		return nil, importer.ErrInvalidFile
+	}
+	return f.tasks, nil
+}
+
+type fixture struct {
+	repo     *gorm_importjob.ImportJobRepoMock
+	taskRepo *gorm_task.TaskRepoMock
+	projects *gorm_project.ProjectRepoMock
+	tasks    *task_service.TaskServiceMock
+	blobs    *blobstore.LocalStore
+	queue    *scheduler.QueueMock
+	importer *fakeImporter
+	service  *ImportJobService
+}
+
+func setup(t *testing.T) *fixture {
+	blobs, err := blobstore.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	f := &fixture{
+		repo:     new(gorm_importjob.ImportJobRepoMock),
+		taskRepo: new(gorm_task.TaskRepoMock),
+		projects: new(gorm_project.ProjectRepoMock),
+		tasks:    new(task_service.TaskServiceMock),
+		blobs:    blobs,
+		queue:    new(scheduler.QueueMock),
+		importer: &fakeImporter{},
+	}
+	f.service = NewImportJobService(f.repo, f.taskRepo, f.projects, f.tasks, f.blobs, f.queue, map[string]importer.Importer{
+		importer.SourceTodoist: f.importer,
+		importer.SourceTodoTxt: importer.NewTodoTxt(),
+	})
+	f.service.now = func() time.Time { return now }
+	return f
+}
+
+// stored puts a file in the blob store and returns a job importing it.
+func (f *fixture) stored(t *testing.T, file string) *importjob.Job {
+	require.NoError(t, f.blobs.Put("imports/1/abc", strings.NewReader(file), int64(len(file)), "application/octet-stream"))
+	return &importjob.Job{ID: 5, UserID: 1, Source: importer.SourceTodoist, FileName: "backup.json", StorageKey: "imports/1/abc", Status: importjob.StatusPending, Run: 1}
+}
+
+func runJob(t *testing.T, run int, attempts int) *scheduler.Job {
+	sj, err := scheduler.NewJob(KindRun, "import:5:1", now, job{ImportID: 5, UserID: 1, Run: run})
+	require.NoError(t, err)
+	sj.Attempts = attempts
+	return sj
+}
+
+func TestImportJobService_Create(t *testing.T) {
+	f := setup(t)
+	f.importer.tasks = []importer.Task{{ID: "1", Name: "Buy milk"}, {ID: "2", Name: "Pay rent"}}
+	var created *importjob.Job
+	f.repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*importjob.Job)
+		created.ID = 5
+		created.CreatedAt = now
+	}).Return(nil)
+	var queued *scheduler.Job
+	f.queue.On("Enqueue", mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(0).(*scheduler.Job)
+	}).Return(true, nil)
+
+	resp, err := f.service.Create(1, importer.SourceTodoist, "../backups/todoist.json", strings.NewReader(`{"items":[]}`))
+	require.NoError(t, err)
+	assert.Equal(t, dto.ImportJobResponse{
+		ID: 5, Source: "todoist", FileName: "todoist.json", Status: "pending", Run: 1, Total: 2,
+		Errors: []dto.ImportJobError{}, CreatedAt: now,
+	}, resp)
+
+	rc, err := f.blobs.Get(created.StorageKey)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, `{"items":[]}`, string(data), "the file is kept for the job")
+	assert.Regexp(t, `^imports/1/[0-9a-f]{32}$`, created.StorageKey)
+
+	require.NotNil(t, queued)
+	assert.Equal(t, KindRun, queued.Kind)
+	assert.Equal(t, "import:5:1", queued.DedupKey)
+	assert.JSONEq(t, `{"import_id":5,"user_id":1,"run":1}`, queued.Payload)
+}
+
+func TestImportJobService_Create_Invalid(t *testing.T) {
+	f := setup(t)
+	f.importer.tasks = []importer.Task{{ID: "1", Name: "Buy milk"}}
+
+	_, err := f.service.Create(0, importer.SourceTodoist, "f.json", strings.NewReader("{}"))
+	assert.ErrorIs(t, err, ErrInvalidUser)
+	_, err = f.service.Create(1, "asana", "f.json", strings.NewReader("{}"))
+	assert.ErrorIs(t, err, ErrUnknownSource)
+	assert.EqualError(t, err, `unknown import source "asana"; use todoist, todotxt`)
+	_, err = f.service.Create(1, importer.SourceTodoist, "f.json", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrEmptyFile)
+	_, err = f.service.Create(1, importer.SourceTodoist, "f.json", strings.NewReader("bad"))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+	_, err = f.service.Create(1, importer.SourceTodoTxt, "todo.txt", strings.NewReader("\n\n"))
+	assert.ErrorIs(t, err, ErrNoTasks)
+
+	f.importer.tasks = make([]importer.Task, MaxTasks+1)
+	_, err = f.service.Create(1, importer.SourceTodoist, "f.json", strings.NewReader("{}"))
+	assert.ErrorIs(t, err, ErrTooManyTasks)
+	f.repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestImportJobService_Run(t *testing.T) {
+	f := setup(t)
+	due := now.Add(24 * time.Hour)
+	f.importer.tasks = []importer.Task{
+		{ID: "2", ParentID: "1", Name: "Buy paint", Project: "Home"},
+		{ID: "1", Name: "Paint the garden fence", Description: "White", Project: "Home", Labels: []string{"Weekend", "weekend"}, Priority: "urgent", DueAt: &due},
+		{ID: "3", Name: "Imported before"},
+		{ID: "4", Name: " "},
+		{ID: "5", Name: "Ship it", Project: "work", Completed: true},
+		{ID: "6", Name: "Draft", InProgress: true, Recurrence: "FREQ=WEEKLY"},
+	}
+	j := f.stored(t, "{}")
+	f.repo.On("Get", 1, 5).Return(j, nil)
+	var saved []importjob.Job
+	f.repo.On("Update", j).Run(func(args mock.Arguments) {
+		saved = append(saved, *args.Get(0).(*importjob.Job))
+	}).Return(nil)
+	f.taskRepo.On("ExternalIDs", 1, "todoist:").Return(map[string]int{"todoist:3": 30}, nil)
+	f.projects.On("List", 1).Return([]project.Project{{ID: 2, UserID: 1, Name: "Work"}}, nil)
+	f.projects.On("Create", mock.MatchedBy(func(p *project.Project) bool { return p.Name == "Home" && p.UserID == 1 })).Run(func(args mock.Arguments) {
+		args.Get(0).(*project.Project).ID = 9
+	}).Return(nil).Once()
+
+	var batches [][]dto.ImportTaskRequest
+	record := func(args mock.Arguments) {
+		batches = append(batches, args.Get(1).([]dto.ImportTaskRequest))
+	}
+	f.tasks.On("Import", 1, mock.Anything).Run(record).Return([]dto.GetTaskResponse{{ID: 50}, {ID: 51}, {ID: 52}}, nil).Once()
+	f.tasks.On("Import", 1, mock.Anything).Run(record).Return([]dto.GetTaskResponse{{ID: 60}}, nil).Once()
+
+	require.NoError(t, f.service.Run(context.Background(), runJob(t, 1, 1)))
+
+	require.Len(t, batches, 2, "subtasks are imported after their parents")
+	top := batches[0]
+	require.Len(t, top, 3)
+	assert.Equal(t, "Paint the garden fe…", top[0].Task)
+	assert.Equal(t, "Paint the garden fence\n\nWhite", top[0].Description)
+	assert.Equal(t, []string{"weekend"}, top[0].Tags)
+	assert.Equal(t, "urgent", top[0].Priority)
+	assert.Equal(t, &due, top[0].DueAt)
+	assert.Equal(t, 9, *top[0].ProjectID)
+	assert.Equal(t, "todoist:1", top[0].ExternalID)
+	assert.Equal(t, "completed", top[1].Status)
+	assert.Equal(t, 2, *top[1].ProjectID, "projects are matched ignoring case")
+	assert.Equal(t, "in-progress", top[2].Status)
+	assert.Equal(t, "FREQ=WEEKLY", top[2].Recurrence)
+	assert.Nil(t, top[2].ProjectID)
+
+	require.Len(t, batches[1], 1)
+	assert.Equal(t, "todoist:2", batches[1][0].ExternalID)
+	assert.Equal(t, 50, *batches[1][0].ParentID)
+	assert.Equal(t, 9, *batches[1][0].ProjectID, "a created project is reused")
+
+	final := saved[len(saved)-1]
+	assert.Equal(t, importjob.StatusSucceeded, final.Status)
+	assert.Equal(t, 6, final.Total)
+	assert.Equal(t, 6, final.Processed)
+	assert.Equal(t, 4, final.Imported)
+	assert.Equal(t, 1, final.Skipped)
+	assert.Equal(t, 1, final.Failed)
+	assert.Equal(t, []importjob.ItemError{{ID: "4", Name: " ", Message: "task name cannot be empty"}}, final.Errors)
+	assert.Equal(t, &now, final.StartedAt)
+	assert.Equal(t, &now, final.FinishedAt)
+	assert.Equal(t, importjob.StatusRunning, saved[0].Status, "progress is saved while running")
+	f.projects.AssertExpectations(t)
+}
+
+func TestImportJobService_Run_Retry(t *testing.T) {
+	f := setup(t)
+	f.importer.tasks = []importer.Task{{ID: "1", Name: "Buy milk"}}
+	j := f.stored(t, "{}")
+	f.repo.On("Get", 1, 5).Return(j, nil)
+	f.repo.On("Update", j).Return(nil)
+	f.taskRepo.On("ExternalIDs", 1, "todoist:").Return(map[string]int{}, nil)
+	f.projects.On("List", 1).Return([]project.Project{}, nil)
+	f.tasks.On("Import", 1, mock.Anything).Return([]dto.GetTaskResponse(nil), errors.New("db down"))
+
+	err := f.service.Run(context.Background(), runJob(t, 1, 1))
+	assert.EqualError(t, err, "db down", "failed attempts are retried")
+	assert.Equal(t, importjob.StatusRunning, j.Status)
+
+	err = f.service.Run(context.Background(), runJob(t, 1, scheduler.DefaultMaxAttempts))
+	assert.EqualError(t, err, "db down")
+	assert.Equal(t, importjob.StatusFailed, j.Status, "the last attempt fails the job")
+	assert.Equal(t, "db down", j.Error)
+	assert.Equal(t, &now, j.FinishedAt)
+}
+
+func TestImportJobService_Run_RefusedTask(t *testing.T) {
+	f := setup(t)
+	f.importer.tasks = []importer.Task{
+		{ID: "1", Name: "Draft", InProgress: true},
+		{ID: "2", Name: "Review", InProgress: true},
+		{ID: "3", Name: "Ship"},
+	}
+	j := f.stored(t, "{}")
+	f.repo.On("Get", 1, 5).Return(j, nil)
+	f.repo.On("Update", j).Return(nil)
+	f.taskRepo.On("ExternalIDs", 1, "todoist:").Return(map[string]int{}, nil)
+	f.projects.On("List", 1).Return([]project.Project{}, nil)
+
+	var batches [][]dto.ImportTaskRequest
+	record := func(args mock.Arguments) {
+		batches = append(batches, args.Get(1).([]dto.ImportTaskRequest))
+	}
+	refused := &task_service.ImportError{Index: 1, Err: task_service.ErrWIPLimitReached}
+	f.tasks.On("Import", 1, mock.Anything).Run(record).Return([]dto.GetTaskResponse(nil), refused).Once()
+	f.tasks.On("Import", 1, mock.Anything).Run(record).Return([]dto.GetTaskResponse{{ID: 50}, {ID: 51}}, nil).Once()
+
+	require.NoError(t, f.service.Run(context.Background(), runJob(t, 1, 1)))
+
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], 3)
+	require.Len(t, batches[1], 2, "the refused task is left out")
+	assert.Equal(t, "todoist:3", batches[1][1].ExternalID)
+	assert.Equal(t, "todoist:3", batches[0][2].ExternalID, "the first attempt is untouched")
+	assert.Equal(t, importjob.StatusSucceeded, j.Status)
+	assert.Equal(t, 2, j.Imported)
+	assert.Equal(t, 1, j.Failed)
+	assert.Equal(t, []importjob.ItemError{{ID: "2", Name: "Review", Message: task_service.ErrWIPLimitReached.Error()}}, j.Errors)
+}
+
+func TestImportJobService_Run_Skips(t *testing.T) {
+	t.Run("replaced run", func(t *testing.T) {
+		f := setup(t)
+		j := f.stored(t, "{}")
+		j.Run = 2
+		f.repo.On("Get", 1, 5).Return(j, nil)
+		require.NoError(t, f.service.Run(context.Background(), runJob(t, 1, 1)))
+		f.repo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("deleted job", func(t *testing.T) {
+		f := setup(t)
+		f.repo.On("Get", 1, 5).Return((*importjob.Job)(nil), gorm.ErrRecordNotFound)
+		require.NoError(t, f.service.Run(context.Background(), runJob(t, 1, 1)))
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		f := setup(t)
+		j := f.stored(t, "bad")
+		f.repo.On("Get", 1, 5).Return(j, nil)
+		f.repo.On("Update", j).Return(nil)
+		require.NoError(t, f.service.Run(context.Background(), runJob(t, 1, 1)), "retrying won't help")
+		assert.Equal(t, importjob.StatusFailed, j.Status)
+		assert.Equal(t, "file could not be read", j.Error)
+	})
+}
+
+func TestImportJobService_Rerun(t *testing.T) {
+	f := setup(t)
+	finished := now.Add(-time.Hour)
+	j := &importjob.Job{ID: 5, UserID: 1, Source: importer.SourceTodoist, Status: importjob.StatusSucceeded, Run: 1, Total: 3, Processed: 3, Imported: 2, Failed: 1,
+		Errors: []importjob.ItemError{{ID: "4", Message: "task name cannot be empty"}}, StartedAt: &finished, FinishedAt: &finished}
+	f.repo.On("Get", 1, 5).Return(j, nil)
+	f.repo.On("Update", j).Return(nil)
+	f.queue.On("Enqueue", mock.MatchedBy(func(sj *scheduler.Job) bool { return sj.DedupKey == "import:5:2" })).Return(true, nil)
+
+	resp, err := f.service.Rerun(1, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "pending", resp.Status)
+	assert.Equal(t, 2, resp.Run)
+	assert.Equal(t, 3, resp.Total)
+	assert.Zero(t, resp.Processed)
+	assert.Zero(t, resp.Imported)
+	assert.Empty(t, resp.Errors)
+	assert.Nil(t, resp.FinishedAt)
+	f.queue.AssertExpectations(t)
+
+	j.Status = importjob.StatusRunning
+	_, err = f.service.Rerun(1, 5)
+	assert.ErrorIs(t, err, ErrJobRunning)
+}
+
+func TestImportJobService_Delete(t *testing.T) {
+	f := setup(t)
+	j := f.stored(t, "{}")
+	j.Status = importjob.StatusSucceeded
+	f.repo.On("Get", 1, 5).Return(j, nil)
+	f.repo.On("Delete", 1, 5).Return(nil)
+
+	require.NoError(t, f.service.Delete(1, 5))
+	_, err := f.blobs.Get(j.StorageKey)
+	assert.ErrorIs(t, err, blobstore.ErrNotFound)
+
+	j.Status = importjob.StatusRunning
+	assert.ErrorIs(t, f.service.Delete(1, 5), ErrJobRunning)
+}
+
+func TestImportJobService_HandleEvent(t *testing.T) {
+	f := setup(t)
+	j := f.stored(t, "{}")
+	f.repo.On("List", 1).Return([]importjob.Job{*j}, nil)
+	f.repo.On("DeleteUserJobs", 1).Return(nil)
+
+	rec, err := events.NewRecord(events.UserDeleted{UserID: 1}, now)
+	require.NoError(t, err)
+	env := rec.Envelope()
+	require.NoError(t, f.service.HandleEvent(context.Background(), &env))
+
+	_, err = f.blobs.Get(j.StorageKey)
+	assert.ErrorIs(t, err, blobstore.ErrNotFound)
+	f.repo.AssertExpectations(t)
+}
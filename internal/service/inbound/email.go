@@ -0,0 +1,170 @@
+package inbound_service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+)
+
+// maxPartDepth bounds how deeply multipart bodies are followed.
+const maxPartDepth = 5
+
+type email struct {
+	Subject string
+	Body    string
+	Files   []File
+}
+
+var (
+	htmlBreak = regexp.MustCompile(`(?i)<br\s*/?>|</p>|</div>|</li>`)
+	htmlTag   = regexp.MustCompile(`(?s)<[^>]*>`)
+	headDrop  = regexp.MustCompile(`(?is)<(head|style|script)[^>]*>.*?</(head|style|script)>`)
+	blankRuns = regexp.MustCompile(`\n{3,}`)
+)
+
+// parseEmail reads the subject, the plain-text body and the attachments of
+// a message. An HTML body is only used, with its markup stripped, when
+// there is no plain-text one. Text in charsets other than UTF-8 and ASCII
+// is kept as sent.
+func parseEmail(data []byte) (*email, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+
+	dec := new(mime.WordDecoder)
+	subject, err := dec.DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		subject = msg.Header.Get("Subject")
+	}
+
+	p := &emailParser{dec: dec}
+	if err := p.part(msg.Header, msg.Body, 0); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedMessage, err)
+	}
+
+	body := p.text
+	if body == "" && p.html != "" {
+		body = stripHTML(p.html)
+	}
+	return &email{
+		Subject: strings.TrimSpace(subject),
+		Body:    strings.TrimSpace(strings.ReplaceAll(body, "\r\n", "\n")),
+		Files:   p.files,
+	}, nil
+}
+
+// header is what mail.Header and the headers of multipart parts share.
+type header interface {
+	Get(key string) string
+}
+
+type emailParser struct {
+	dec   *mime.WordDecoder
+	text  string
+	html  string
+	files []File
+}
+
+func (p *emailParser) part(h header, body io.Reader, depth int) error {
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxPartDepth || params["boundary"] == "" {
+			return nil
+		}
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := mr.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.part(part.Header, part, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	content, err := io.ReadAll(decodeTransfer(h.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	if name := p.fileName(h, params); name != "" {
+		p.files = append(p.files, File{
+			Name: name,
+			Size: int64(len(content)),
+			Open: func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(content)), nil },
+		})
+		return nil
+	}
+
+	switch mediaType {
+	case "text/plain":
+		if p.text == "" {
+			p.text = string(content)
+		}
+	case "text/html":
+		if p.html == "" {
+			p.html = string(content)
+		}
+	}
+	return nil
+}
+
+// fileName returns the name of a part that is an attachment, or "" for
+// one that is part of the body.
+func (p *emailParser) fileName(h header, typeParams map[string]string) string {
+	disposition, params, _ := mime.ParseMediaType(h.Get("Content-Disposition"))
+	name := params["filename"]
+	if name == "" {
+		name = typeParams["name"]
+	}
+	if name == "" {
+		if disposition != "attachment" {
+			return ""
+		}
+		name = "attachment"
+	}
+	if decoded, err := p.dec.DecodeHeader(name); err == nil {
+		name = decoded
+	}
+	return name
+}
+
+func decodeTransfer(encoding string, r io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	default:
+		return r
+	}
+}
+
+func stripHTML(s string) string {
+	s = headDrop.ReplaceAllString(s, "")
+	s = htmlBreak.ReplaceAllString(s, "\n")
+	s = html.UnescapeString(htmlTag.ReplaceAllString(s, ""))
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Join(strings.Fields(line), " ")
+	}
+	return strings.TrimSpace(blankRuns.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
@@ -0,0 +1,290 @@
+package inbound_service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"taskflow/internal/domain/inbound"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_inbound"
+	attachment_service "taskflow/internal/service/attachment"
+	task_service "taskflow/internal/service/task"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxAddresses is how many capture addresses a user may have.
+	MaxAddresses = 20
+	// MaxFiles is how many files one capture attaches; the rest are skipped.
+	MaxFiles = 10
+)
+
+var (
+	ErrInvalidUser       = errors.New("invalid user")
+	ErrTooManyAddresses  = errors.New("too many inbound addresses")
+	ErrUnknownAddress    = errors.New("unknown inbound address")
+	ErrEmptyTitle        = errors.New("a title or text is required")
+	ErrMalformedMessage  = errors.New("malformed email message")
+	ErrAddressNotInbound = errors.New("address is not an inbound address")
+)
+
+// File is an attachment sent along with a capture.
+type File struct {
+	Name string
+	Size int64
+	Open func() (io.ReadCloser, error)
+}
+
+type InboundService struct {
+	repo        gorm_inbound.InboundRepositoryInterface
+	tasks       task_service.TaskServiceInterface
+	attachments attachment_service.AttachmentServiceInterface
+	emailDomain string
+	now         func() time.Time
+}
+
+// NewInboundService creates the service. emailDomain is the domain inbound
+// mail is addressed to; when empty, addresses have no email and mail to
+// any domain is accepted.
+func NewInboundService(repo gorm_inbound.InboundRepositoryInterface, tasks task_service.TaskServiceInterface, attachments attachment_service.AttachmentServiceInterface, emailDomain string) *InboundService {
+	return &InboundService{
+		repo:        repo,
+		tasks:       tasks,
+		attachments: attachments,
+		emailDomain: strings.ToLower(emailDomain),
+		now:         time.Now,
+	}
+}
+
+var _ InboundServiceInterface = (*InboundService)(nil)
+
+func (s *InboundService) CreateAddress(userID int, req *dto.CreateInboundAddressRequest) (dto.InboundAddressResponse, error) {
+	if userID == 0 {
+		return dto.InboundAddressResponse{}, ErrInvalidUser
+	}
+
+	existing, err := s.repo.List(userID)
+	if err != nil {
+		return dto.InboundAddressResponse{}, err
+	}
+	if len(existing) >= MaxAddresses {
+		return dto.InboundAddressResponse{}, ErrTooManyAddresses
+	}
+
+	token, err := randomHex(16)
+	if err != nil {
+		return dto.InboundAddressResponse{}, err
+	}
+	a := inbound.Address{UserID: userID, Name: strings.TrimSpace(req.Name), Token: token}
+	if err := s.repo.Create(&a); err != nil {
+		return dto.InboundAddressResponse{}, err
+	}
+	return s.toAddressResponse(a), nil
+}
+
+func (s *InboundService) ListAddresses(userID int) (dto.ListInboundAddressesResponse, error) {
+	if userID == 0 {
+		return dto.ListInboundAddressesResponse{}, ErrInvalidUser
+	}
+
+	addrs, err := s.repo.List(userID)
+	if err != nil {
+		return dto.ListInboundAddressesResponse{}, err
+	}
+	resp := dto.ListInboundAddressesResponse{Addresses: make([]dto.InboundAddressResponse, 0, len(addrs))}
+	for _, a := range addrs {
+		resp.Addresses = append(resp.Addresses, s.toAddressResponse(a))
+	}
+	return resp, nil
+}
+
+func (s *InboundService) DeleteAddress(userID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+	return s.repo.Delete(userID, id)
+}
+
+// Capture creates a task for the owner of the address with the given
+// token and attaches the files. Files that are rejected, or beyond
+// MaxFiles, are reported as skipped rather than failing the capture,
+// since the task already exists by then.
+func (s *InboundService) Capture(token string, req *dto.InboundCaptureRequest, files []File) (dto.InboundCaptureResponse, error) {
+	a, err := s.lookup(token)
+	if err != nil {
+		return dto.InboundCaptureResponse{}, err
+	}
+
+	taskReq, err := toTaskRequest(req)
+	if err != nil {
+		return dto.InboundCaptureResponse{}, err
+	}
+	t, err := s.tasks.CreateTask(a.UserID, taskReq)
+	if err != nil {
+		return dto.InboundCaptureResponse{}, err
+	}
+
+	resp := dto.InboundCaptureResponse{Task: t, Attachments: []dto.AttachmentResponse{}}
+	for i, f := range files {
+		if i >= MaxFiles {
+			resp.Skipped = append(resp.Skipped, f.Name)
+			continue
+		}
+		att, err := s.attach(a.UserID, t.ID, f)
+		if err != nil {
+			log.Printf("inbound: attaching %q to task %d: %v", f.Name, t.ID, err)
+			resp.Skipped = append(resp.Skipped, f.Name)
+			continue
+		}
+		resp.Attachments = append(resp.Attachments, att)
+	}
+
+	if err := s.repo.Touch(a.ID, s.now()); err != nil {
+		log.Printf("inbound: recording use of address %d: %v", a.ID, err)
+	}
+	return resp, nil
+}
+
+// CheckRecipient accepts addresses of the form <token>@<email domain>.
+func (s *InboundService) CheckRecipient(addr string) error {
+	token, err := s.tokenFromEmail(addr)
+	if err != nil {
+		return err
+	}
+	_, err = s.lookup(token)
+	return err
+}
+
+// CaptureEmail creates a task from an email: the subject is the title,
+// the plain-text body the description and attachments are attached.
+func (s *InboundService) CaptureEmail(addr string, message []byte) (dto.InboundCaptureResponse, error) {
+	token, err := s.tokenFromEmail(addr)
+	if err != nil {
+		return dto.InboundCaptureResponse{}, err
+	}
+
+	m, err := parseEmail(message)
+	if err != nil {
+		return dto.InboundCaptureResponse{}, err
+	}
+	req := dto.InboundCaptureRequest{Title: m.Subject, Description: m.Body}
+	if req.Title == "" {
+		req.Text, req.Description = m.Body, ""
+	}
+	return s.Capture(token, &req, m.Files)
+}
+
+// HandleEvent removes the addresses of deleted users.
+func (s *InboundService) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	if e.Type != events.TypeUserDeleted {
+		return nil
+	}
+	return s.repo.DeleteUserAddresses(e.UserID)
+}
+
+func (s *InboundService) lookup(token string) (*inbound.Address, error) {
+	if token == "" {
+		return nil, ErrUnknownAddress
+	}
+	a, err := s.repo.GetByToken(token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUnknownAddress
+	}
+	return a, err
+}
+
+func (s *InboundService) tokenFromEmail(addr string) (string, error) {
+	local, domain, ok := strings.Cut(strings.ToLower(addr), "@")
+	if !ok || (s.emailDomain != "" && domain != s.emailDomain) {
+		return "", ErrAddressNotInbound
+	}
+	return local, nil
+}
+
+func (s *InboundService) attach(userID int, taskID int, f File) (dto.AttachmentResponse, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return dto.AttachmentResponse{}, err
+	}
+	defer rc.Close()
+	return s.attachments.Upload(userID, taskID, f.Name, f.Size, rc)
+}
+
+func (s *InboundService) toAddressResponse(a inbound.Address) dto.InboundAddressResponse {
+	resp := dto.InboundAddressResponse{
+		ID:         a.ID,
+		Name:       a.Name,
+		URL:        "/inbound/" + a.Token,
+		LastUsedAt: a.LastUsedAt,
+		CreatedAt:  a.CreatedAt,
+	}
+	if s.emailDomain != "" {
+		resp.Email = a.Token + "@" + s.emailDomain
+	}
+	return resp
+}
+
+// toTaskRequest maps a capture to a task. A title that is too long for a
+// task name is shortened, and kept in full at the top of the description.
+func toTaskRequest(req *dto.InboundCaptureRequest) (*dto.CreateTaskRequest, error) {
+	title := strings.TrimSpace(req.Title)
+	description := strings.TrimSpace(req.Description)
+	if title == "" {
+		first, rest, _ := strings.Cut(strings.TrimSpace(req.Text), "\n")
+		title = strings.TrimSpace(first)
+		if description == "" {
+			description = strings.TrimSpace(rest)
+		}
+	}
+	title, description = task_service.FitTitle(title, description)
+	if title == "" {
+		return nil, ErrEmptyTitle
+	}
+
+	var tags []string
+	for _, t := range req.Tags {
+		for _, name := range strings.Split(t, ",") {
+			name = strings.TrimSpace(name)
+			if name != "" && utf8.RuneCountInString(name) <= task_service.MaxTagLength && len(tags) < task_service.MaxTags {
+				tags = append(tags, name)
+			}
+		}
+	}
+
+	return &dto.CreateTaskRequest{
+		Task:        title,
+		Description: truncate(description, task_service.MaxDescriptionLength),
+		Priority:    req.Priority,
+		DueAt:       req.DueAt,
+		Tags:        tags,
+	}, nil
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	i := 0
+	for j := range s {
+		if i == n {
+			return strings.TrimSpace(s[:j])
+		}
+		i++
+	}
+	return s
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,21 @@
+package inbound_service
+
+import (
+	"context"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+)
+
+type InboundServiceInterface interface {
+	CreateAddress(userID int, req *dto.CreateInboundAddressRequest) (dto.InboundAddressResponse, error)
+	ListAddresses(userID int) (dto.ListInboundAddressesResponse, error)
+	DeleteAddress(userID int, id int) error
+	Capture(token string, req *dto.InboundCaptureRequest, files []File) (dto.InboundCaptureResponse, error)
+	// CheckRecipient reports whether mail to addr can be captured.
+	CheckRecipient(addr string) error
+	// CaptureEmail turns a raw RFC 5322 message sent to addr into a task.
+	CaptureEmail(addr string, message []byte) (dto.InboundCaptureResponse, error)
+	// HandleEvent consumes domain events.
+	HandleEvent(ctx context.Context, e *events.Envelope) error
+}
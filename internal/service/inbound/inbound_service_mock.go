@@ -0,0 +1,51 @@
+package inbound_service
+
+import (
+	"context"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type InboundServiceMock struct {
+	mock.Mock
+}
+
+var _ InboundServiceInterface = (*InboundServiceMock)(nil)
+
+func (m *InboundServiceMock) CreateAddress(userID int, req *dto.CreateInboundAddressRequest) (dto.InboundAddressResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.InboundAddressResponse), args.Error(1)
+}
+
+func (m *InboundServiceMock) ListAddresses(userID int) (dto.ListInboundAddressesResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.ListInboundAddressesResponse), args.Error(1)
+}
+
+func (m *InboundServiceMock) DeleteAddress(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *InboundServiceMock) Capture(token string, req *dto.InboundCaptureRequest, files []File) (dto.InboundCaptureResponse, error) {
+	args := m.Called(token, req, files)
+	return args.Get(0).(dto.InboundCaptureResponse), args.Error(1)
+}
+
+func (m *InboundServiceMock) CheckRecipient(addr string) error {
+	args := m.Called(addr)
+	return args.Error(0)
+}
+
+func (m *InboundServiceMock) CaptureEmail(addr string, message []byte) (dto.InboundCaptureResponse, error) {
+	args := m.Called(addr, message)
+	return args.Get(0).(dto.InboundCaptureResponse), args.Error(1)
+}
+
+func (m *InboundServiceMock) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
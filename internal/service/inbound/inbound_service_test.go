@@ -0,0 +1,243 @@
+package inbound_service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/inbound"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_inbound"
+	attachment_service "taskflow/internal/service/attachment"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg/smtpd"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var now = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+const token = "0123456789abcdef0123456789abcdef"
+
+type fixture struct {
+	repo        *gorm_inbound.InboundRepoMock
+	tasks       *task_service.TaskServiceMock
+	attachments *attachment_service.AttachmentServiceMock
+	service     *InboundService
+}
+
+func setup(emailDomain string) *fixture {
+	f := &fixture{
+		repo:        new(gorm_inbound.InboundRepoMock),
+		tasks:       new(task_service.TaskServiceMock),
+		attachments: new(attachment_service.AttachmentServiceMock),
+	}
+	f.repo.On("GetByToken", token).Return(&inbound.Address{ID: 4, UserID: 1, Token: token}, nil)
+	f.repo.On("GetByToken", mock.Anything).Return((*inbound.Address)(nil), gorm.ErrRecordNotFound)
+	f.repo.On("Touch", 4, now).Return(nil)
+	f.service = NewInboundService(f.repo, f.tasks, f.attachments, emailDomain)
+	f.service.now = func() time.Time { return now }
+	return f
+}
+
+func TestInboundService_CreateAddress(t *testing.T) {
+	f := setup("in.example.com")
+	f.repo.On("List", 1).Return([]inbound.Address{}, nil)
+	f.repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*inbound.Address).ID = 3
+	}).Return(nil)
+
+	resp, err := f.service.CreateAddress(1, &dto.CreateInboundAddressRequest{Name: " Zapier "})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.ID)
+	assert.Equal(t, "Zapier", resp.Name)
+	assert.Regexp(t, `^/inbound/[0-9a-f]{32}$`, resp.URL)
+	assert.Equal(t, strings.TrimPrefix(resp.URL, "/inbound/")+"@in.example.com", resp.Email)
+
+	f.repo.On("List", 2).Return(make([]inbound.Address, MaxAddresses), nil)
+	_, err = f.service.CreateAddress(2, &dto.CreateInboundAddressRequest{Name: "One too many"})
+	assert.ErrorIs(t, err, ErrTooManyAddresses)
+	_, err = f.service.CreateAddress(0, &dto.CreateInboundAddressRequest{Name: "x"})
+	assert.ErrorIs(t, err, ErrInvalidUser)
+	f.repo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestInboundService_Capture(t *testing.T) {
+	due := now.Add(24 * time.Hour)
+	tests := []struct {
+		name string
+		req  dto.InboundCaptureRequest
+		want dto.CreateTaskRequest
+	}{
+		{
+			name: "fields",
+			req:  dto.InboundCaptureRequest{Title: " Buy milk ", Description: "Semi-skimmed", DueAt: &due, Priority: "high", Tags: []string{"home, errands", " ", "shop"}},
+			want: dto.CreateTaskRequest{Task: "Buy milk", Description: "Semi-skimmed", DueAt: &due, Priority: "high", Tags: []string{"home", "errands", "shop"}},
+		},
+		{
+			name: "text",
+			req:  dto.InboundCaptureRequest{Text: "Call plumber\nThe tap\nis leaking"},
+			want: dto.CreateTaskRequest{Task: "Call plumber", Description: "The tap\nis leaking"},
+		},
+		{
+			name: "long title",
+			req:  dto.InboundCaptureRequest{Title: "Renew the car insurance before it lapses", Description: "Compare quotes"},
+			want: dto.CreateTaskRequest{Task: "Renew the car insur…", Description: "Renew the car insurance before it lapses\n\nCompare quotes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := setup("")
+			f.tasks.On("CreateTask", 1, &tt.want).Return(dto.GetTaskResponse{ID: 9, Task: tt.want.Task}, nil)
+
+			resp, err := f.service.Capture(token, &tt.req, nil)
+			require.NoError(t, err)
+			assert.Equal(t, 9, resp.Task.ID)
+			assert.Empty(t, resp.Attachments)
+			f.tasks.AssertExpectations(t)
+			f.repo.AssertCalled(t, "Touch", 4, now)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		f := setup("")
+		_, err := f.service.Capture("nope", &dto.InboundCaptureRequest{Title: "x"}, nil)
+		assert.ErrorIs(t, err, ErrUnknownAddress)
+		_, err = f.service.Capture(token, &dto.InboundCaptureRequest{Text: " \n\t", Description: "body only"}, nil)
+		assert.ErrorIs(t, err, ErrEmptyTitle)
+		f.tasks.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+	})
+}
+
+func file(name, content string) File {
+	return File{Name: name, Size: int64(len(content)), Open: func() (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(content)), nil
+	}}
+}
+
+func TestInboundService_Capture_Files(t *testing.T) {
+	f := setup("")
+	f.tasks.On("CreateTask", 1, mock.Anything).Return(dto.GetTaskResponse{ID: 9}, nil)
+	f.attachments.On("Upload", 1, 9, "notes.txt", int64(5), mock.Anything).Return(dto.AttachmentResponse{ID: 2, FileName: "notes.txt"}, nil)
+	f.attachments.On("Upload", 1, 9, "setup.exe", int64(2), mock.Anything).Return(dto.AttachmentResponse{}, attachment_service.ErrUnsupportedType)
+
+	resp, err := f.service.Capture(token, &dto.InboundCaptureRequest{Title: "Read notes"}, []File{file("notes.txt", "hello"), file("setup.exe", "MZ")})
+	require.NoError(t, err)
+	require.Len(t, resp.Attachments, 1)
+	assert.Equal(t, 2, resp.Attachments[0].ID)
+	assert.Equal(t, []string{"setup.exe"}, resp.Skipped)
+}
+
+const multipartEmail = "From: Sam <sam@example.com>\r\n" +
+	"To: " + token + "@in.example.com\r\n" +
+	"Subject: =?UTF-8?Q?Caf=C3=A9_order?=\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Two flat whites =E2=80=94 no sugar.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<p>Two flat whites</p>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"menu.txt\"\r\n" +
+	"Content-Disposition: attachment; filename=\"menu.txt\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"Y29m\r\n" +
+	"ZmVl\r\n" +
+	"--outer--\r\n"
+
+func TestParseEmail(t *testing.T) {
+	m, err := parseEmail([]byte(multipartEmail))
+	require.NoError(t, err)
+	assert.Equal(t, "Café order", m.Subject)
+	assert.Equal(t, "Two flat whites — no sugar.", m.Body)
+	require.Len(t, m.Files, 1)
+	assert.Equal(t, "menu.txt", m.Files[0].Name)
+	rc, err := m.Files[0].Open()
+	require.NoError(t, err)
+	content, _ := io.ReadAll(rc)
+	assert.Equal(t, "coffee", string(content))
+	assert.Equal(t, int64(6), m.Files[0].Size)
+
+	m, err = parseEmail([]byte("Subject: Hi\r\nContent-Type: text/html\r\n\r\n<html><head><style>p{}</style></head><body><p>Line&nbsp;one</p><p>Line <b>two</b></p></body></html>\r\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "Line one\nLine two", m.Body, "HTML is used when there is no plain text")
+
+	_, err = parseEmail([]byte("not an email"))
+	assert.ErrorIs(t, err, ErrMalformedMessage)
+}
+
+func TestInboundService_CheckRecipient(t *testing.T) {
+	f := setup("in.example.com")
+	assert.NoError(t, f.service.CheckRecipient(strings.ToUpper(token)+"@IN.example.com"))
+	assert.ErrorIs(t, f.service.CheckRecipient(token+"@elsewhere.com"), ErrAddressNotInbound)
+	assert.ErrorIs(t, f.service.CheckRecipient("nope@in.example.com"), ErrUnknownAddress)
+	assert.ErrorIs(t, f.service.CheckRecipient("nobody"), ErrAddressNotInbound)
+}
+
+func TestMailBackend_SMTP(t *testing.T) {
+	f := setup("in.example.com")
+	f.tasks.On("CreateTask", 1, &dto.CreateTaskRequest{Task: "Café order", Description: "Two flat whites — no sugar."}).
+		Return(dto.GetTaskResponse{ID: 9}, nil)
+	f.attachments.On("Upload", 1, 9, "menu.txt", int64(6), mock.Anything).Return(dto.AttachmentResponse{ID: 2}, nil)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &smtpd.Server{Backend: NewMailBackend(f.service)}
+	go srv.Serve(ln)
+	defer srv.Close()
+	addr := ln.Addr().String()
+
+	// The same address twice makes one task.
+	err = smtp.SendMail(addr, nil, "sam@example.com", []string{token + "@in.example.com", token + "@in.example.com"}, []byte(multipartEmail))
+	require.NoError(t, err)
+	f.tasks.AssertNumberOfCalls(t, "CreateTask", 1)
+	f.attachments.AssertExpectations(t)
+
+	err = smtp.SendMail(addr, nil, "sam@example.com", []string{"nope@in.example.com"}, []byte(multipartEmail))
+	assert.ErrorContains(t, err, "550")
+
+	err = smtp.SendMail(addr, nil, "sam@example.com", []string{token + "@in.example.com"}, []byte("Subject: \r\n\r\n"))
+	assert.ErrorContains(t, err, "550", "a message without subject or text is bounced")
+	f.tasks.AssertNumberOfCalls(t, "CreateTask", 1)
+}
+
+func TestMailBackend_TemporaryFailure(t *testing.T) {
+	f := setup("")
+	f.tasks.On("CreateTask", 1, mock.Anything).Return(dto.GetTaskResponse{}, errors.New("database is down"))
+
+	err := NewMailBackend(f.service).Deliver("sam@example.com", []string{token + "@any.example.com"}, []byte("Subject: Hi\n\nBody\n"))
+	var smtpErr *smtpd.Error
+	assert.False(t, errors.As(err, &smtpErr), "the sender should retry")
+	assert.Error(t, err)
+}
+
+func TestInboundService_HandleEvent(t *testing.T) {
+	f := setup("")
+	f.repo.On("DeleteUserAddresses", 1).Return(nil)
+
+	rec, err := events.NewRecord(events.UserDeleted{UserID: 1}, now)
+	require.NoError(t, err)
+	env := rec.Envelope()
+	require.NoError(t, f.service.HandleEvent(context.Background(), &env))
+	f.repo.AssertCalled(t, "DeleteUserAddresses", 1)
+}
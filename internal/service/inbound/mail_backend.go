@@ -0,0 +1,56 @@
+package inbound_service
+
+import (
+	"errors"
+	"strings"
+
+	"taskflow/pkg/smtpd"
+)
+
+// MailBackend hands mail received by the SMTP listener to the service.
+type MailBackend struct {
+	service InboundServiceInterface
+}
+
+func NewMailBackend(s InboundServiceInterface) *MailBackend {
+	return &MailBackend{service: s}
+}
+
+// Compile-time check
+var _ smtpd.Backend = (*MailBackend)(nil)
+
+func (b *MailBackend) Recipient(addr string) error {
+	return mailError(b.service.CheckRecipient(addr))
+}
+
+// Deliver creates a task for every inbound address the message was sent
+// to. A message that can't become a task is bounced; other errors make
+// the sender retry, which may repeat tasks already created for earlier
+// recipients.
+func (b *MailBackend) Deliver(from string, to []string, data []byte) error {
+	seen := make(map[string]bool, len(to))
+	for _, addr := range to {
+		key := strings.ToLower(addr)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if _, err := b.service.CaptureEmail(addr, data); err != nil {
+			return mailError(err)
+		}
+	}
+	return nil
+}
+
+func mailError(err error) error {
+	switch {
+	case errors.Is(err, ErrUnknownAddress), errors.Is(err, ErrAddressNotInbound):
+		return smtpd.ErrNoSuchRecipient
+	case errors.Is(err, ErrEmptyTitle):
+		return &smtpd.Error{Code: 550, Message: "5.6.0 Message has no subject or text"}
+	case errors.Is(err, ErrMalformedMessage):
+		return &smtpd.Error{Code: 550, Message: "5.6.0 Malformed message"}
+	default:
+		return err
+	}
+}
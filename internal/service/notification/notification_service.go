@@ -0,0 +1,295 @@
+package notification_service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"taskflow/internal/domain/notification"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/notify"
+	"taskflow/internal/repository/gorm/gorm_notification"
+	"taskflow/internal/scheduler"
+	task_service "taskflow/internal/service/task"
+
+	"gorm.io/gorm"
+)
+
+// KindDeliver is the scheduler job kind that sends one message through one
+// external channel.
+const KindDeliver = "notifications.deliver"
+
+// External channels. They are delivered by the scheduler so that a slow or
+// failing mail server is retried without holding up the producer.
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+var (
+	ErrInvalidUser     = errors.New("invalid user")
+	ErrInvalidType     = errors.New("unknown notification type")
+	ErrInvalidDelivery = errors.New("delivery must be in_app, email, both or off")
+)
+
+// delivery is the payload of a KindDeliver job.
+type delivery struct {
+	Channel string         `json:"channel"`
+	Message notify.Message `json:"message"`
+}
+
+type NotificationService struct {
+	repo     gorm_notification.NotificationRepositoryInterface
+	users    task_service.UserLookup
+	queue    scheduler.Queue
+	inbox    notify.Notifier
+	channels map[string]notify.Notifier
+	now      func() time.Time
+}
+
+// NewNotificationService creates a NotificationService. channels holds the
+// configured external channels by name; missing channels are skipped.
+func NewNotificationService(
+	repo gorm_notification.NotificationRepositoryInterface,
+	users task_service.UserLookup,
+	queue scheduler.Queue,
+	channels map[string]notify.Notifier,
+) *NotificationService {
+	return &NotificationService{
+		repo:     repo,
+		users:    users,
+		queue:    queue,
+		inbox:    notify.NewInbox(repo),
+		channels: channels,
+		now:      time.Now,
+	}
+}
+
+var _ NotificationServiceInterface = (*NotificationService)(nil)
+
+// Publish delivers msg as its recipient prefers for msg.Type. The in-app
+// copy is stored right away; email and the webhook are queued. Users who
+// chose email only while no mail server is configured get the in-app copy
+// instead. The webhook receives everything that is not turned off.
+func (s *NotificationService) Publish(ctx context.Context, msg notify.Message) error {
+	if msg.UserID == 0 {
+		return ErrInvalidUser
+	}
+
+	d, err := s.deliveryFor(msg.UserID, msg.Type)
+	if err != nil {
+		return err
+	}
+	if d == notification.DeliveryOff {
+		return nil
+	}
+
+	email := d == notification.DeliveryEmail || d == notification.DeliveryBoth
+	inApp := d == notification.DeliveryInApp || d == notification.DeliveryBoth ||
+		(email && s.channels[ChannelEmail] == nil)
+
+	if inApp {
+		if err := s.inbox.Notify(ctx, msg); err != nil {
+			return err
+		}
+	}
+	if email {
+		if err := s.enqueue(ChannelEmail, msg); err != nil {
+			return err
+		}
+	}
+	return s.enqueue(ChannelWebhook, msg)
+}
+
+func (s *NotificationService) deliveryFor(userID int, typ string) (string, error) {
+	prefs, err := s.repo.GetPreferences(userID)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range prefs {
+		if p.Type == typ {
+			return p.Delivery, nil
+		}
+	}
+	return notification.DefaultDelivery(typ), nil
+}
+
+func (s *NotificationService) enqueue(channel string, msg notify.Message) error {
+	if s.channels[channel] == nil {
+		return nil
+	}
+
+	key := msg.Key
+	if key == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		key = hex.EncodeToString(b)
+	}
+	job, err := scheduler.NewJob(KindDeliver, fmt.Sprintf("notify:%s:%s", channel, key), s.now(), delivery{
+		Channel: channel,
+		Message: msg,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.queue.Enqueue(job)
+	return err
+}
+
+// Deliver sends one queued message. Emails go to the user's current address.
+func (s *NotificationService) Deliver(ctx context.Context, job *scheduler.Job) error {
+	var d delivery
+	if err := job.Decode(&d); err != nil {
+		return err
+	}
+	n := s.channels[d.Channel]
+	if n == nil {
+		return nil
+	}
+
+	if d.Channel == ChannelEmail {
+		u, err := s.users.GetByID(d.Message.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		d.Message.Email = u.Email
+	}
+	return n.Notify(ctx, d.Message)
+}
+
+// HandleEvent consumes domain events: deleting a user deletes their
+// notifications and preferences.
+func (s *NotificationService) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	if e.Type != events.TypeUserDeleted {
+		return nil
+	}
+	return s.repo.DeleteUser(e.UserID)
+}
+
+func (s *NotificationService) List(userID int, unreadOnly bool, page int, limit int) (dto.ListNotificationsResponse, error) {
+	if userID == 0 {
+		return dto.ListNotificationsResponse{}, ErrInvalidUser
+	}
+
+	notifications, total, err := s.repo.List(userID, unreadOnly, (page-1)*limit, limit)
+	if err != nil {
+		return dto.ListNotificationsResponse{}, err
+	}
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return dto.ListNotificationsResponse{}, err
+	}
+
+	resp := dto.ListNotificationsResponse{
+		Notifications: make([]dto.NotificationResponse, 0, len(notifications)),
+		Unread:        unread,
+		Page:          page,
+		Limit:         limit,
+		Total:         total,
+	}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, toNotificationResponse(n))
+	}
+	return resp, nil
+}
+
+func (s *NotificationService) MarkRead(userID int, id int) (dto.MarkReadResponse, error) {
+	if userID == 0 {
+		return dto.MarkReadResponse{}, ErrInvalidUser
+	}
+
+	if err := s.repo.MarkRead(userID, id, s.now()); err != nil {
+		return dto.MarkReadResponse{}, err
+	}
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return dto.MarkReadResponse{}, err
+	}
+	return dto.MarkReadResponse{Marked: 1, Unread: unread}, nil
+}
+
+func (s *NotificationService) MarkAllRead(userID int) (dto.MarkReadResponse, error) {
+	if userID == 0 {
+		return dto.MarkReadResponse{}, ErrInvalidUser
+	}
+
+	marked, err := s.repo.MarkAllRead(userID, s.now())
+	if err != nil {
+		return dto.MarkReadResponse{}, err
+	}
+	return dto.MarkReadResponse{Marked: marked}, nil
+}
+
+// GetPreferences returns the delivery for every notification type,
+// including the defaults for types the user never changed.
+func (s *NotificationService) GetPreferences(userID int) (dto.NotificationPreferencesResponse, error) {
+	if userID == 0 {
+		return dto.NotificationPreferencesResponse{}, ErrInvalidUser
+	}
+
+	prefs, err := s.repo.GetPreferences(userID)
+	if err != nil {
+		return dto.NotificationPreferencesResponse{}, err
+	}
+	saved := make(map[string]string, len(prefs))
+	for _, p := range prefs {
+		saved[p.Type] = p.Delivery
+	}
+
+	resp := dto.NotificationPreferencesResponse{
+		Preferences: make([]dto.NotificationPreference, 0, len(notification.Types)),
+	}
+	for _, t := range notification.Types {
+		d, ok := saved[t]
+		if !ok {
+			d = notification.DefaultDelivery(t)
+		}
+		resp.Preferences = append(resp.Preferences, dto.NotificationPreference{Type: t, Delivery: d})
+	}
+	return resp, nil
+}
+
+// UpdatePreferences changes the given types and leaves the others alone.
+func (s *NotificationService) UpdatePreferences(userID int, req *dto.NotificationPreferencesRequest) (dto.NotificationPreferencesResponse, error) {
+	if userID == 0 {
+		return dto.NotificationPreferencesResponse{}, ErrInvalidUser
+	}
+
+	prefs := make([]notification.Preference, 0, len(req.Preferences))
+	for _, p := range req.Preferences {
+		if !notification.ValidType(p.Type) {
+			return dto.NotificationPreferencesResponse{}, fmt.Errorf("%w %q", ErrInvalidType, p.Type)
+		}
+		if !notification.ValidDelivery(p.Delivery) {
+			return dto.NotificationPreferencesResponse{}, ErrInvalidDelivery
+		}
+		prefs = append(prefs, notification.Preference{UserID: userID, Type: p.Type, Delivery: p.Delivery})
+	}
+
+	if err := s.repo.SavePreferences(prefs); err != nil {
+		return dto.NotificationPreferencesResponse{}, err
+	}
+	return s.GetPreferences(userID)
+}
+
+func toNotificationResponse(n notification.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		Title:     n.Title,
+		Body:      n.Body,
+		TaskID:    n.TaskID,
+		Read:      n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
@@ -0,0 +1,23 @@
+package notification_service
+
+import (
+	"context"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/notify"
+	"taskflow/internal/scheduler"
+)
+
+type NotificationServiceInterface interface {
+	notify.Publisher
+	List(userID int, unreadOnly bool, page int, limit int) (dto.ListNotificationsResponse, error)
+	MarkRead(userID int, id int) (dto.MarkReadResponse, error)
+	MarkAllRead(userID int) (dto.MarkReadResponse, error)
+	GetPreferences(userID int) (dto.NotificationPreferencesResponse, error)
+	UpdatePreferences(userID int, req *dto.NotificationPreferencesRequest) (dto.NotificationPreferencesResponse, error)
+	// Deliver handles KindDeliver jobs.
+	Deliver(ctx context.Context, job *scheduler.Job) error
+	// HandleEvent consumes domain events.
+	HandleEvent(ctx context.Context, e *events.Envelope) error
+}
@@ -0,0 +1,58 @@
+package notification_service
+
+import (
+	"context"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/notify"
+	"taskflow/internal/scheduler"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type NotificationServiceMock struct {
+	mock.Mock
+}
+
+var _ NotificationServiceInterface = (*NotificationServiceMock)(nil)
+
+func (m *NotificationServiceMock) Publish(ctx context.Context, msg notify.Message) error {
+	args := m.Called(msg)
+	return args.Error(0)
+}
+
+func (m *NotificationServiceMock) List(userID int, unreadOnly bool, page int, limit int) (dto.ListNotificationsResponse, error) {
+	args := m.Called(userID, unreadOnly, page, limit)
+	return args.Get(0).(dto.ListNotificationsResponse), args.Error(1)
+}
+
+func (m *NotificationServiceMock) MarkRead(userID int, id int) (dto.MarkReadResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.MarkReadResponse), args.Error(1)
+}
+
+func (m *NotificationServiceMock) MarkAllRead(userID int) (dto.MarkReadResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.MarkReadResponse), args.Error(1)
+}
+
+func (m *NotificationServiceMock) GetPreferences(userID int) (dto.NotificationPreferencesResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.NotificationPreferencesResponse), args.Error(1)
+}
+
+func (m *NotificationServiceMock) UpdatePreferences(userID int, req *dto.NotificationPreferencesRequest) (dto.NotificationPreferencesResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.NotificationPreferencesResponse), args.Error(1)
+}
+
+func (m *NotificationServiceMock) Deliver(ctx context.Context, job *scheduler.Job) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
+
+func (m *NotificationServiceMock) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	args := m.Called(e)
+	return args.Error(0)
+}
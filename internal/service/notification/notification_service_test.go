@@ -0,0 +1,236 @@
+package notification_service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/notification"
+	"taskflow/internal/domain/user"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/notify"
+	"taskflow/internal/repository/gorm/gorm_notification"
+	"taskflow/internal/repository/gorm/gorm_user"
+	"taskflow/internal/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var now = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+func subtasksDone() notify.Message {
+	taskID := 4
+	return notify.Message{UserID: 2, Type: notification.TypeSubtasksDone, Title: "Subtasks done: Write report", TaskID: &taskID, Key: "subtasks-done:4:7"}
+}
+
+func TestNotificationService_Publish(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefs       []notification.Preference
+		noMailer    bool
+		wantInbox   bool
+		wantQueued  []string
+		wantErr     error
+		invalidUser bool
+	}{
+		{name: "default is in-app", wantInbox: true, wantQueued: []string{"notify:webhook:subtasks-done:4:7"}},
+		{name: "both", prefs: []notification.Preference{{UserID: 2, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryBoth}},
+			wantInbox: true, wantQueued: []string{"notify:email:subtasks-done:4:7", "notify:webhook:subtasks-done:4:7"}},
+		{name: "email only", prefs: []notification.Preference{{UserID: 2, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryEmail}},
+			wantQueued: []string{"notify:email:subtasks-done:4:7", "notify:webhook:subtasks-done:4:7"}},
+		{name: "email only without a mail server falls back to in-app", prefs: []notification.Preference{{UserID: 2, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryEmail}},
+			noMailer: true, wantInbox: true, wantQueued: []string{"notify:webhook:subtasks-done:4:7"}},
+		{name: "off", prefs: []notification.Preference{{UserID: 2, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryOff}}},
+		{name: "preferences for other types are ignored", prefs: []notification.Preference{{UserID: 2, Type: notification.TypeReminder, Delivery: notification.DeliveryOff}},
+			wantInbox: true, wantQueued: []string{"notify:webhook:subtasks-done:4:7"}},
+		{name: "invalid user", invalidUser: true, wantErr: ErrInvalidUser},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(gorm_notification.NotificationRepoMock)
+			repo.On("GetPreferences", 2).Return(tt.prefs, nil)
+			repo.On("Create", mock.MatchedBy(func(n *notification.Notification) bool {
+				return n.UserID == 2 && n.Type == notification.TypeSubtasksDone && *n.Key == "subtasks-done:4:7"
+			})).Return(nil)
+
+			var queued []string
+			queue := new(scheduler.QueueMock)
+			queue.On("Enqueue", mock.Anything).Run(func(args mock.Arguments) {
+				job := args.Get(0).(*scheduler.Job)
+				assert.Equal(t, KindDeliver, job.Kind)
+				assert.Equal(t, now, job.RunAt)
+				queued = append(queued, job.DedupKey)
+			}).Return(true, nil)
+
+			channels := map[string]notify.Notifier{ChannelWebhook: new(notify.NotifierMock)}
+			if !tt.noMailer {
+				channels[ChannelEmail] = new(notify.NotifierMock)
+			}
+			s := NewNotificationService(repo, nil, queue, channels)
+			s.now = func() time.Time { return now }
+
+			msg := subtasksDone()
+			if tt.invalidUser {
+				msg.UserID = 0
+			}
+			err := s.Publish(context.Background(), msg)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			if tt.wantInbox {
+				repo.AssertNumberOfCalls(t, "Create", 1)
+			} else {
+				repo.AssertNotCalled(t, "Create", mock.Anything)
+			}
+			assert.Equal(t, tt.wantQueued, queued)
+		})
+	}
+}
+
+func TestNotificationService_PublishWithoutKey(t *testing.T) {
+	repo := new(gorm_notification.NotificationRepoMock)
+	repo.On("GetPreferences", 2).Return([]notification.Preference(nil), nil)
+	repo.On("Create", mock.Anything).Return(nil)
+	queue := new(scheduler.QueueMock)
+	queue.On("Enqueue", mock.MatchedBy(func(job *scheduler.Job) bool {
+		return strings.HasPrefix(job.DedupKey, "notify:webhook:") && len(job.DedupKey) == len("notify:webhook:")+32
+	})).Return(true, nil).Twice()
+
+	s := NewNotificationService(repo, nil, queue, map[string]notify.Notifier{ChannelWebhook: new(notify.NotifierMock)})
+	msg := subtasksDone()
+	msg.Key = ""
+	require.NoError(t, s.Publish(context.Background(), msg))
+	require.NoError(t, s.Publish(context.Background(), msg))
+
+	queue.AssertExpectations(t)
+	first := queue.Calls[0].Arguments.Get(0).(*scheduler.Job).DedupKey
+	second := queue.Calls[1].Arguments.Get(0).(*scheduler.Job).DedupKey
+	assert.NotEqual(t, first, second)
+}
+
+func TestNotificationService_Deliver(t *testing.T) {
+	users := new(gorm_user.MockUserRepository)
+	users.On("GetByID", 2).Return(&user.User{ID: 2, Email: "jane@example.com"}, nil)
+	users.On("GetByID", 3).Return((*user.User)(nil), gorm.ErrRecordNotFound)
+
+	mailer := new(notify.NotifierMock)
+	mailer.On("Notify", mock.MatchedBy(func(msg notify.Message) bool {
+		return msg.Email == "jane@example.com" && msg.Title == "Subtasks done: Write report"
+	})).Return(nil).Once()
+	mailer.On("Notify", mock.Anything).Return(errors.New("smtp down")).Once()
+
+	s := NewNotificationService(nil, users, nil, map[string]notify.Notifier{ChannelEmail: mailer})
+
+	job, err := scheduler.NewJob(KindDeliver, "k", now, delivery{Channel: ChannelEmail, Message: subtasksDone()})
+	require.NoError(t, err)
+	require.NoError(t, s.Deliver(context.Background(), job))
+	assert.EqualError(t, s.Deliver(context.Background(), job), "smtp down", "errors are retried by the scheduler")
+
+	// Deleted users and channels that were switched off are dropped.
+	msg := subtasksDone()
+	msg.UserID = 3
+	job, err = scheduler.NewJob(KindDeliver, "k", now, delivery{Channel: ChannelEmail, Message: msg})
+	require.NoError(t, err)
+	assert.NoError(t, s.Deliver(context.Background(), job))
+	job, err = scheduler.NewJob(KindDeliver, "k", now, delivery{Channel: ChannelWebhook, Message: subtasksDone()})
+	require.NoError(t, err)
+	assert.NoError(t, s.Deliver(context.Background(), job))
+
+	mailer.AssertNumberOfCalls(t, "Notify", 2)
+}
+
+func TestNotificationService_HandleEvent(t *testing.T) {
+	repo := new(gorm_notification.NotificationRepoMock)
+	repo.On("DeleteUser", 2).Return(nil).Once()
+	s := NewNotificationService(repo, nil, nil, nil)
+
+	for _, e := range []events.Event{events.UserDeleted{UserID: 2}, events.TaskCreated{UserID: 2}} {
+		rec, err := events.NewRecord(e, now)
+		require.NoError(t, err)
+		env := rec.Envelope()
+		require.NoError(t, s.HandleEvent(context.Background(), &env))
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestNotificationService_List(t *testing.T) {
+	repo := new(gorm_notification.NotificationRepoMock)
+	readAt := now.Add(-time.Hour)
+	repo.On("List", 1, true, 20, 10).Return([]notification.Notification{
+		{ID: 3, UserID: 1, Type: notification.TypeSubtasksDone, Title: "a", CreatedAt: now},
+		{ID: 2, UserID: 1, Type: notification.TypeReminder, Title: "b", ReadAt: &readAt, CreatedAt: now},
+	}, int64(22), nil)
+	repo.On("CountUnread", 1).Return(int64(5), nil)
+
+	s := NewNotificationService(repo, nil, nil, nil)
+	resp, err := s.List(1, true, 3, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), resp.Unread)
+	assert.Equal(t, int64(22), resp.Total)
+	assert.Equal(t, 3, resp.Page)
+	require.Len(t, resp.Notifications, 2)
+	assert.False(t, resp.Notifications[0].Read)
+	assert.True(t, resp.Notifications[1].Read)
+
+	_, err = s.List(0, false, 1, 20)
+	assert.ErrorIs(t, err, ErrInvalidUser)
+}
+
+func TestNotificationService_MarkRead(t *testing.T) {
+	repo := new(gorm_notification.NotificationRepoMock)
+	repo.On("MarkRead", 1, 3, now).Return(nil)
+	repo.On("MarkRead", 1, 9, now).Return(gorm.ErrRecordNotFound)
+	repo.On("CountUnread", 1).Return(int64(4), nil)
+	repo.On("MarkAllRead", 1, now).Return(int64(4), nil)
+
+	s := NewNotificationService(repo, nil, nil, nil)
+	s.now = func() time.Time { return now }
+
+	resp, err := s.MarkRead(1, 3)
+	require.NoError(t, err)
+	assert.Equal(t, dto.MarkReadResponse{Marked: 1, Unread: 4}, resp)
+
+	_, err = s.MarkRead(1, 9)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	resp, err = s.MarkAllRead(1)
+	require.NoError(t, err)
+	assert.Equal(t, dto.MarkReadResponse{Marked: 4, Unread: 0}, resp)
+}
+
+func TestNotificationService_Preferences(t *testing.T) {
+	repo := new(gorm_notification.NotificationRepoMock)
+	repo.On("GetPreferences", 1).Return([]notification.Preference{
+		{UserID: 1, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryOff},
+	}, nil)
+	repo.On("SavePreferences", []notification.Preference{
+		{UserID: 1, Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryOff},
+	}).Return(nil)
+
+	s := NewNotificationService(repo, nil, nil, nil)
+
+	resp, err := s.UpdatePreferences(1, &dto.NotificationPreferencesRequest{Preferences: []dto.NotificationPreference{
+		{Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryOff},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []dto.NotificationPreference{
+		{Type: notification.TypeReminder, Delivery: notification.DeliveryBoth},
+		{Type: notification.TypeSubtasksDone, Delivery: notification.DeliveryOff},
+	}, resp.Preferences)
+
+	_, err = s.UpdatePreferences(1, &dto.NotificationPreferencesRequest{Preferences: []dto.NotificationPreference{{Type: "digest", Delivery: "off"}}})
+	assert.ErrorIs(t, err, ErrInvalidType)
+	_, err = s.UpdatePreferences(1, &dto.NotificationPreferencesRequest{Preferences: []dto.NotificationPreference{{Type: notification.TypeSubtasksDone, Delivery: "sms"}}})
+	assert.ErrorIs(t, err, ErrInvalidDelivery)
+	repo.AssertNumberOfCalls(t, "SavePreferences", 1)
+}
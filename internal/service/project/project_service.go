@@ -0,0 +1,107 @@
+package project_service
+
+import (
+	"errors"
+	"strings"
+
+	"taskflow/internal/domain/project"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_project"
+)
+
+var (
+	ErrInvalidUser = errors.New("invalid user")
+	ErrEmptyName   = errors.New("project name cannot be empty")
+)
+
+type ProjectService struct {
+	repo gorm_project.ProjectRepositoryInterface
+}
+
+func NewProjectService(repo gorm_project.ProjectRepositoryInterface) *ProjectService {
+	return &ProjectService{repo: repo}
+}
+
+var _ ProjectServiceInterface = (*ProjectService)(nil)
+
+func (s *ProjectService) CreateProject(userID int, req *dto.CreateProjectRequest) (dto.ProjectResponse, error) {
+	if userID == 0 {
+		return dto.ProjectResponse{}, ErrInvalidUser
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return dto.ProjectResponse{}, ErrEmptyName
+	}
+
+	p := project.Project{UserID: userID, Name: name}
+	if err := s.repo.Create(&p); err != nil {
+		return dto.ProjectResponse{}, err
+	}
+	return toProjectResponse(p), nil
+}
+
+func (s *ProjectService) ListProjects(userID int) (dto.ListProjectsResponse, error) {
+	if userID == 0 {
+		return dto.ListProjectsResponse{}, ErrInvalidUser
+	}
+
+	projects, err := s.repo.List(userID)
+	if err != nil {
+		return dto.ListProjectsResponse{}, err
+	}
+
+	resp := dto.ListProjectsResponse{Projects: make([]dto.ProjectResponse, 0, len(projects))}
+	for _, p := range projects {
+		resp.Projects = append(resp.Projects, toProjectResponse(p))
+	}
+	return resp, nil
+}
+
+func (s *ProjectService) GetProject(userID int, id int) (dto.ProjectResponse, error) {
+	if userID == 0 {
+		return dto.ProjectResponse{}, ErrInvalidUser
+	}
+
+	p, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return dto.ProjectResponse{}, err
+	}
+	return toProjectResponse(*p), nil
+}
+
+func (s *ProjectService) UpdateProject(userID int, id int, req *dto.UpdateProjectRequest) (dto.ProjectResponse, error) {
+	if userID == 0 {
+		return dto.ProjectResponse{}, ErrInvalidUser
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return dto.ProjectResponse{}, ErrEmptyName
+	}
+
+	p, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return dto.ProjectResponse{}, err
+	}
+	p.Name = name
+	if err := s.repo.Update(p); err != nil {
+		return dto.ProjectResponse{}, err
+	}
+	return toProjectResponse(*p), nil
+}
+
+// DeleteProject removes a project. Its tasks are kept and move to the inbox.
+func (s *ProjectService) DeleteProject(userID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+	return s.repo.Delete(userID, id)
+}
+
+func toProjectResponse(p project.Project) dto.ProjectResponse {
+	return dto.ProjectResponse{
+		ID:        p.ID,
+		Name:      p.Name,
+		CreatedAt: p.CreatedAt,
+		UpdatedAt: p.UpdatedAt,
+	}
+}
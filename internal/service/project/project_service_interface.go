@@ -0,0 +1,11 @@
+package project_service
+
+import "taskflow/internal/dto"
+
+type ProjectServiceInterface interface {
+	CreateProject(userID int, req *dto.CreateProjectRequest) (dto.ProjectResponse, error)
+	ListProjects(userID int) (dto.ListProjectsResponse, error)
+	GetProject(userID int, id int) (dto.ProjectResponse, error)
+	UpdateProject(userID int, id int, req *dto.UpdateProjectRequest) (dto.ProjectResponse, error)
+	DeleteProject(userID int, id int) error
+}
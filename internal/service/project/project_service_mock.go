@@ -0,0 +1,38 @@
+package project_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type ProjectServiceMock struct {
+	mock.Mock
+}
+
+var _ ProjectServiceInterface = (*ProjectServiceMock)(nil)
+
+func (m *ProjectServiceMock) CreateProject(userID int, req *dto.CreateProjectRequest) (dto.ProjectResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.ProjectResponse), args.Error(1)
+}
+
+func (m *ProjectServiceMock) ListProjects(userID int) (dto.ListProjectsResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.ListProjectsResponse), args.Error(1)
+}
+
+func (m *ProjectServiceMock) GetProject(userID int, id int) (dto.ProjectResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.ProjectResponse), args.Error(1)
+}
+
+func (m *ProjectServiceMock) UpdateProject(userID int, id int, req *dto.UpdateProjectRequest) (dto.ProjectResponse, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(dto.ProjectResponse), args.Error(1)
+}
+
+func (m *ProjectServiceMock) DeleteProject(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
@@ -0,0 +1,112 @@
+package project_service
+
+import (
+	"testing"
+
+	"taskflow/internal/domain/project"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_project"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestProjectService_CreateProject(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		req       dto.CreateProjectRequest
+		setupMock func(r *gorm_project.ProjectRepoMock)
+		wantErr   error
+	}{
+		{
+			name:   "success - name is trimmed",
+			userID: 1,
+			req:    dto.CreateProjectRequest{Name: "  Work "},
+			setupMock: func(r *gorm_project.ProjectRepoMock) {
+				r.On("Create", mock.MatchedBy(func(p *project.Project) bool {
+					return p.UserID == 1 && p.Name == "Work"
+				})).Return(nil)
+			},
+		},
+		{
+			name:      "failure - blank name",
+			userID:    1,
+			req:       dto.CreateProjectRequest{Name: "   "},
+			setupMock: func(r *gorm_project.ProjectRepoMock) {},
+			wantErr:   ErrEmptyName,
+		},
+		{
+			name:      "failure - invalid user",
+			req:       dto.CreateProjectRequest{Name: "Work"},
+			setupMock: func(r *gorm_project.ProjectRepoMock) {},
+			wantErr:   ErrInvalidUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(gorm_project.ProjectRepoMock)
+			tt.setupMock(repo)
+
+			got, err := NewProjectService(repo).CreateProject(tt.userID, &tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Work", got.Name)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestProjectService_GetProject(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo := new(gorm_project.ProjectRepoMock)
+		repo.On("GetByID", 1, 3).Return(&project.Project{ID: 3, UserID: 1, Name: "Work"}, nil)
+
+		got, err := NewProjectService(repo).GetProject(1, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ProjectResponse{ID: 3, Name: "Work"}, got)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := new(gorm_project.ProjectRepoMock)
+		repo.On("GetByID", 1, 3).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := NewProjectService(repo).GetProject(1, 3)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestProjectService_UpdateProject(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		repo := new(gorm_project.ProjectRepoMock)
+		repo.On("GetByID", 1, 3).Return(&project.Project{ID: 3, UserID: 1, Name: "Wrok"}, nil)
+		repo.On("Update", mock.MatchedBy(func(p *project.Project) bool { return p.Name == "Work" })).Return(nil)
+
+		got, err := NewProjectService(repo).UpdateProject(1, 3, &dto.UpdateProjectRequest{Name: "Work"})
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ProjectResponse{ID: 3, Name: "Work"}, got)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := new(gorm_project.ProjectRepoMock)
+		repo.On("GetByID", 1, 3).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := NewProjectService(repo).UpdateProject(1, 3, &dto.UpdateProjectRequest{Name: "Work"})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestProjectService_ListProjects(t *testing.T) {
+	repo := new(gorm_project.ProjectRepoMock)
+	repo.On("List", 1).Return([]project.Project{}, nil)
+
+	got, err := NewProjectService(repo).ListProjects(1)
+	assert.NoError(t, err)
+	assert.Equal(t, dto.ListProjectsResponse{Projects: []dto.ProjectResponse{}}, got)
+}
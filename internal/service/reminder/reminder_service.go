@@ -0,0 +1,185 @@
+package reminder_service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"taskflow/internal/domain/notification"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/notify"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/scheduler"
+	task_service "taskflow/internal/service/task"
+
+	"gorm.io/gorm"
+)
+
+// Scheduler job kinds.
+const (
+	KindPlan    = "reminders.plan"
+	KindDeliver = "reminders.deliver"
+)
+
+// PlanInterval is how often upcoming due dates are scanned. Each scan
+// enqueues the reminders that fire before the next two scans.
+const PlanInterval = time.Minute
+
+// delivery is the payload of a KindDeliver job.
+type delivery struct {
+	TaskID int       `json:"task_id"`
+	UserID int       `json:"user_id"`
+	DueAt  time.Time `json:"due_at"`
+	Offset string    `json:"offset"`
+}
+
+type ReminderService struct {
+	tasks     gorm_task.TaskRepositoryInterface
+	users     task_service.UserLookup
+	queue     scheduler.Queue
+	publisher notify.Publisher
+	offsets   []time.Duration
+	now       func() time.Time
+}
+
+// NewReminderService creates a ReminderService that reminds users at each
+// offset before a task is due. Reminders are published, so they reach
+// users through the channels they chose for reminders.
+func NewReminderService(
+	tasks gorm_task.TaskRepositoryInterface,
+	users task_service.UserLookup,
+	queue scheduler.Queue,
+	offsets []time.Duration,
+	publisher notify.Publisher,
+) *ReminderService {
+	sorted := slices.Clone(offsets)
+	slices.SortFunc(sorted, func(a, b time.Duration) int { return cmp.Compare(b, a) })
+	return &ReminderService{
+		tasks:     tasks,
+		users:     users,
+		queue:     queue,
+		publisher: publisher,
+		offsets:   sorted,
+		now:       time.Now,
+	}
+}
+
+var _ ReminderServiceInterface = (*ReminderService)(nil)
+
+// ParseOffsets reads a comma-separated list of durations such as "24h,1h".
+func ParseOffsets(s string) ([]time.Duration, error) {
+	var offsets []time.Duration
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		d, err := time.ParseDuration(part)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid reminder offset %q", part)
+		}
+		offsets = append(offsets, d)
+	}
+	return offsets, nil
+}
+
+// Plan enqueues a delivery job for every reminder that fires before the
+// next scans. When every reminder of a task was missed, for example because
+// it was created shortly before it is due, the one closest to the due date
+// is sent right away. Jobs are keyed by task, due date and offset, so
+// scanning again never sends a reminder twice.
+func (s *ReminderService) Plan(ctx context.Context, job *scheduler.Job) error {
+	if len(s.offsets) == 0 {
+		return nil
+	}
+
+	now := s.now()
+	horizon := now.Add(2 * PlanInterval)
+	tasks, err := s.tasks.DueBetween(now, horizon.Add(s.offsets[0]))
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tasks {
+		missed, upcoming := time.Duration(-1), false
+		for _, offset := range s.offsets {
+			at := t.DueAt.Add(-offset)
+			switch {
+			case !at.After(now):
+				missed = offset
+			case !at.After(horizon):
+				upcoming = true
+				if err := s.enqueue(t, offset, at); err != nil {
+					return err
+				}
+			default:
+				upcoming = true
+			}
+		}
+		if missed >= 0 && !upcoming {
+			if err := s.enqueue(t, missed, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *ReminderService) enqueue(t task.Task, offset time.Duration, at time.Time) error {
+	key := fmt.Sprintf("reminder:%d:%d:%s", t.ID, t.DueAt.Unix(), offset)
+	job, err := scheduler.NewJob(KindDeliver, key, at, delivery{
+		TaskID: t.ID,
+		UserID: t.UserID,
+		DueAt:  *t.DueAt,
+		Offset: offset.String(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.queue.Enqueue(job)
+	return err
+}
+
+// Deliver sends one reminder. Reminders for tasks that were deleted,
+// completed or given another due date since they were planned are dropped.
+func (s *ReminderService) Deliver(ctx context.Context, job *scheduler.Job) error {
+	var d delivery
+	if err := job.Decode(&d); err != nil {
+		return err
+	}
+
+	t, err := s.tasks.GetByID(d.UserID, d.TaskID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if t.Status == task.StatusCompleted || t.DueAt == nil || !t.DueAt.Equal(d.DueAt) {
+		return nil
+	}
+
+	u, err := s.users.GetByID(d.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	due := t.DueAt.In(u.Location())
+	return s.publisher.Publish(ctx, notify.Message{
+		UserID: u.ID,
+		Email:  u.Email,
+		Type:   notification.TypeReminder,
+		Title:  "Reminder: " + t.Task,
+		Body:   fmt.Sprintf("%q is due %s.", t.Task, due.Format("Mon 2 Jan 15:04 MST")),
+		TaskID: &t.ID,
+		DueAt:  t.DueAt,
+		Key:    job.DedupKey,
+	})
+}
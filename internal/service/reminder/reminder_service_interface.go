@@ -0,0 +1,15 @@
+package reminder_service
+
+import (
+	"context"
+
+	"taskflow/internal/scheduler"
+)
+
+// ReminderServiceInterface holds the scheduler handlers for reminders.
+type ReminderServiceInterface interface {
+	// Plan handles KindPlan jobs.
+	Plan(ctx context.Context, job *scheduler.Job) error
+	// Deliver handles KindDeliver jobs.
+	Deliver(ctx context.Context, job *scheduler.Job) error
+}
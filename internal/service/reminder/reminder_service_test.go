@@ -0,0 +1,131 @@
+package reminder_service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/user"
+	"taskflow/internal/notify"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_user"
+	"taskflow/internal/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var now = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+func TestParseOffsets(t *testing.T) {
+	got, err := ParseOffsets("24h, 1h,,15m")
+	require.NoError(t, err)
+	assert.Equal(t, []time.Duration{24 * time.Hour, time.Hour, 15 * time.Minute}, got)
+
+	got, err = ParseOffsets("")
+	require.NoError(t, err)
+	assert.Empty(t, got)
+
+	_, err = ParseOffsets("1d")
+	assert.Error(t, err)
+	_, err = ParseOffsets("-1h")
+	assert.Error(t, err)
+}
+
+func TestReminderService_Plan(t *testing.T) {
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	tasks := new(gorm_task.TaskRepoMock)
+	tasks.On("DueBetween", now, now.Add(24*time.Hour+2*time.Minute)).Return([]task.Task{
+		// The 24h reminder fires within the next scans.
+		{ID: 1, UserID: 1, Task: "Pay rent", DueAt: at(24*time.Hour + time.Minute)},
+		// Both reminders were missed: only the 1h one is sent, now.
+		{ID: 2, UserID: 1, Task: "Call mum", DueAt: at(10 * time.Minute)},
+		// Nothing fires yet: the 24h reminder is long past and the 1h one is hours away.
+		{ID: 3, UserID: 2, Task: "Report", DueAt: at(5 * time.Hour)},
+	}, nil)
+
+	var keys []string
+	runAt := map[string]time.Time{}
+	queue := new(scheduler.QueueMock)
+	queue.On("Enqueue", mock.Anything).Run(func(args mock.Arguments) {
+		job := args.Get(0).(*scheduler.Job)
+		assert.Equal(t, KindDeliver, job.Kind)
+		keys = append(keys, job.DedupKey)
+		runAt[job.DedupKey] = job.RunAt
+	}).Return(true, nil)
+
+	s := NewReminderService(tasks, nil, queue, []time.Duration{time.Hour, 24 * time.Hour}, nil)
+	s.now = func() time.Time { return now }
+
+	require.NoError(t, s.Plan(context.Background(), nil))
+
+	due1 := now.Add(24*time.Hour + time.Minute).Unix()
+	due2 := now.Add(10 * time.Minute).Unix()
+	task1 := "reminder:1:" + strconv.FormatInt(due1, 10) + ":24h0m0s"
+	task2 := "reminder:2:" + strconv.FormatInt(due2, 10) + ":1h0m0s"
+	assert.Equal(t, []string{task1, task2}, keys)
+	assert.Equal(t, now.Add(time.Minute), runAt[task1])
+	assert.Equal(t, now, runAt[task2])
+}
+
+func TestReminderService_Deliver(t *testing.T) {
+	due := time.Date(2025, 9, 8, 9, 0, 0, 0, time.UTC)
+	job, err := scheduler.NewJob(KindDeliver, "reminder:4:1757322000:1h0m0s", now, delivery{TaskID: 4, UserID: 1, DueAt: due, Offset: "1h0m0s"})
+	require.NoError(t, err)
+
+	users := new(gorm_user.MockUserRepository)
+	users.On("GetByID", 1).Return(&user.User{ID: 1, Email: "sam@example.com", TimeZone: "Europe/Berlin"}, nil)
+
+	tests := []struct {
+		name     string
+		task     *task.Task
+		taskErr  error
+		sendErr  error
+		wantSent bool
+		wantErr  bool
+	}{
+		{name: "sends the reminder", task: &task.Task{ID: 4, UserID: 1, Task: "Pay rent", Status: "pending", DueAt: &due}, wantSent: true},
+		{name: "publish errors are retried", task: &task.Task{ID: 4, UserID: 1, Task: "Pay rent", Status: "pending", DueAt: &due}, sendErr: errors.New("smtp down"), wantSent: true, wantErr: true},
+		{name: "task was deleted", taskErr: gorm.ErrRecordNotFound},
+		{name: "task was completed", task: &task.Task{ID: 4, UserID: 1, Task: "Pay rent", Status: "completed", DueAt: &due}},
+		{name: "due date moved", task: &task.Task{ID: 4, UserID: 1, Task: "Pay rent", Status: "pending", DueAt: ptr(due.Add(time.Hour))}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks := new(gorm_task.TaskRepoMock)
+			tasks.On("GetByID", 1, 4).Return(tt.task, tt.taskErr)
+			publisher := new(notify.PublisherMock)
+			publisher.On("Publish", mock.MatchedBy(func(msg notify.Message) bool {
+				return msg.UserID == 1 && msg.Title == "Reminder: Pay rent" &&
+					msg.Body == `"Pay rent" is due Mon 8 Sep 11:00 CEST.` && *msg.TaskID == 4 &&
+					msg.Key == "reminder:4:1757322000:1h0m0s"
+			})).Return(tt.sendErr)
+
+			s := NewReminderService(tasks, users, nil, []time.Duration{time.Hour}, publisher)
+			err := s.Deliver(context.Background(), job)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.wantSent {
+				publisher.AssertNumberOfCalls(t, "Publish", 1)
+			} else {
+				publisher.AssertNotCalled(t, "Publish", mock.Anything)
+			}
+		})
+	}
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
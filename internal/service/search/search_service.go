@@ -0,0 +1,118 @@
+package search_service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_search"
+	task_service "taskflow/internal/service/task"
+	"taskflow/internal/taskquery"
+)
+
+const maxQueryLength = 200
+
+var (
+	ErrInvalidUser   = errors.New("invalid user")
+	ErrEmptyQuery    = errors.New("search query cannot be empty")
+	ErrQueryTooLong  = errors.New("search query is too long")
+	ErrInvalidStatus = errors.New("invalid status")
+)
+
+type SearchService struct {
+	repo  gorm_search.SearchRepositoryInterface
+	users task_service.UserLookup
+	now   func() time.Time
+}
+
+// Option configures optional SearchService collaborators.
+type Option func(*SearchService)
+
+// WithUsers makes query filters such as due:today resolve relative dates in
+// the user's time zone instead of UTC.
+func WithUsers(u task_service.UserLookup) Option {
+	return func(s *SearchService) {
+		s.users = u
+	}
+}
+
+func NewSearchService(repo gorm_search.SearchRepositoryInterface, opts ...Option) *SearchService {
+	s := &SearchService{repo: repo, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+var _ SearchServiceInterface = (*SearchService)(nil)
+
+// SearchTasks ranks the user's tasks against query, which uses the language
+// of GET /tasks?q=. Its top-level words and phrases are the search text and
+// everything else, such as tag:work, filters the tasks searched. Syntax and
+// validation problems are returned as *taskquery.Error.
+func (s *SearchService) SearchTasks(userID int, query string, status string, page int, limit int) (dto.SearchTasksResponse, error) {
+	if userID == 0 {
+		return dto.SearchTasksResponse{}, ErrInvalidUser
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return dto.SearchTasksResponse{}, ErrEmptyQuery
+	}
+	if len(query) > maxQueryLength {
+		return dto.SearchTasksResponse{}, ErrQueryTooLong
+	}
+	if status != "" && status != "pending" && status != "in-progress" && status != "completed" {
+		return dto.SearchTasksResponse{}, ErrInvalidStatus
+	}
+
+	node, err := taskquery.Parse(query)
+	if err != nil {
+		return dto.SearchTasksResponse{}, err
+	}
+	terms, rest := taskquery.SplitText(node)
+	text := strings.Join(terms, " ")
+	if strings.TrimSpace(text) == "" {
+		return dto.SearchTasksResponse{}, ErrEmptyQuery
+	}
+	var filter taskquery.Scope
+	if rest != nil {
+		loc, err := task_service.UserLocation(s.users, userID)
+		if err != nil {
+			return dto.SearchTasksResponse{}, err
+		}
+		if filter, err = taskquery.Compile(rest, s.now().In(loc)); err != nil {
+			return dto.SearchTasksResponse{}, err
+		}
+	}
+
+	hits, total, err := s.repo.Search(gorm_search.SearchQuery{
+		UserID: userID,
+		Text:   text,
+		Status: status,
+		Filter: filter,
+		Offset: (page - 1) * limit,
+		Limit:  limit,
+	})
+	if err != nil {
+		return dto.SearchTasksResponse{}, err
+	}
+
+	resp := dto.SearchTasksResponse{
+		Query:   query,
+		Results: make([]dto.SearchTaskResult, 0, len(hits)),
+		Page:    page,
+		Limit:   limit,
+		Total:   total,
+	}
+	for _, h := range hits {
+		resp.Results = append(resp.Results, dto.SearchTaskResult{
+			Task:      task_service.ToTaskResponse(h.Task),
+			Score:     h.Score,
+			Snippet:   h.Snippet,
+			MatchedIn: h.MatchedIn,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,7 @@
+package search_service
+
+import "taskflow/internal/dto"
+
+type SearchServiceInterface interface {
+	SearchTasks(userID int, query string, status string, page int, limit int) (dto.SearchTasksResponse, error)
+}
@@ -0,0 +1,18 @@
+package search_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type SearchServiceMock struct {
+	mock.Mock
+}
+
+var _ SearchServiceInterface = (*SearchServiceMock)(nil)
+
+func (m *SearchServiceMock) SearchTasks(userID int, query string, status string, page int, limit int) (dto.SearchTasksResponse, error) {
+	args := m.Called(userID, query, status, page, limit)
+	return args.Get(0).(dto.SearchTasksResponse), args.Error(1)
+}
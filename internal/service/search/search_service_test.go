@@ -0,0 +1,148 @@
+package search_service
+
+import (
+	"errors"
+	"strings"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/user"
+	"taskflow/internal/repository/gorm/gorm_search"
+	"taskflow/internal/repository/gorm/gorm_user"
+	"taskflow/internal/taskquery"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSearchService_SearchTasks(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		query     string
+		status    string
+		setupMock func() *gorm_search.SearchRepoMock
+		wantErr   error
+		wantTotal int64
+	}{
+		{
+			name:   "success",
+			userID: 1,
+			query:  "  milk ",
+			setupMock: func() *gorm_search.SearchRepoMock {
+				m := new(gorm_search.SearchRepoMock)
+				m.On("Search", gorm_search.SearchQuery{UserID: 1, Text: "milk", Offset: 20, Limit: 20}).
+					Return([]gorm_search.SearchHit{{Task: task.Task{ID: 1, Task: "Buy milk"}, Score: 1, Snippet: "Buy <mark>milk</mark>"}}, int64(21), nil)
+				return m
+			},
+			wantTotal: 21,
+		},
+		{
+			name:      "failure - empty query",
+			userID:    1,
+			query:     "   ",
+			setupMock: func() *gorm_search.SearchRepoMock { return new(gorm_search.SearchRepoMock) },
+			wantErr:   ErrEmptyQuery,
+		},
+		{
+			name:      "failure - query too long",
+			userID:    1,
+			query:     strings.Repeat("a", maxQueryLength+1),
+			setupMock: func() *gorm_search.SearchRepoMock { return new(gorm_search.SearchRepoMock) },
+			wantErr:   ErrQueryTooLong,
+		},
+		{
+			name:      "failure - invalid status",
+			userID:    1,
+			query:     "milk",
+			status:    "archived",
+			setupMock: func() *gorm_search.SearchRepoMock { return new(gorm_search.SearchRepoMock) },
+			wantErr:   ErrInvalidStatus,
+		},
+		{
+			name:   "failure - repo error",
+			userID: 1,
+			query:  "milk",
+			setupMock: func() *gorm_search.SearchRepoMock {
+				m := new(gorm_search.SearchRepoMock)
+				m.On("Search", gorm_search.SearchQuery{UserID: 1, Text: "milk", Offset: 20, Limit: 20}).
+					Return([]gorm_search.SearchHit{}, int64(0), errors.New("db error"))
+				return m
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := tt.setupMock()
+			s := NewSearchService(repo)
+
+			got, err := s.SearchTasks(tt.userID, tt.query, tt.status, 2, 20)
+			if tt.wantErr != nil {
+				assert.EqualError(t, err, tt.wantErr.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantTotal, got.Total)
+				assert.Equal(t, "milk", got.Query)
+				assert.Len(t, got.Results, 1)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestSearchService_SearchTasks_Query(t *testing.T) {
+	t.Run("fields filter the search", func(t *testing.T) {
+		repo := new(gorm_search.SearchRepoMock)
+		repo.On("Search", mock.MatchedBy(func(q gorm_search.SearchQuery) bool {
+			return q.Text == "oat milk" && q.Filter != nil
+		})).Return([]gorm_search.SearchHit{}, int64(0), nil)
+
+		got, err := NewSearchService(repo).SearchTasks(1, `"oat milk" tag:home due<7d`, "", 1, 20)
+		require.NoError(t, err)
+		assert.Equal(t, `"oat milk" tag:home due<7d`, got.Query)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("relative dates follow the user's time zone", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+		require.NoError(t, err)
+
+		var vars []any
+		repo := new(gorm_search.SearchRepoMock)
+		repo.On("Search", mock.Anything).Run(func(args mock.Arguments) {
+			q := args.Get(0).(gorm_search.SearchQuery)
+			vars = db.Scopes(q.Filter).Find(&[]task.Task{}).Statement.Vars
+		}).Return([]gorm_search.SearchHit{}, int64(0), nil)
+		users := new(gorm_user.MockUserRepository)
+		users.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "America/New_York"}, nil)
+
+		s := NewSearchService(repo, WithUsers(users))
+		// 02:00 UTC on the 10th is still the evening of the 9th in New York.
+		s.now = func() time.Time { return time.Date(2025, 9, 10, 2, 0, 0, 0, time.UTC) }
+		_, err = s.SearchTasks(1, "milk due:today", "", 1, 20)
+
+		require.NoError(t, err)
+		require.Len(t, vars, 2)
+		assert.True(t, time.Date(2025, 9, 9, 0, 0, 0, 0, newYork).Equal(vars[0].(time.Time)), "start is %v", vars[0])
+		assert.True(t, time.Date(2025, 9, 10, 0, 0, 0, 0, newYork).Equal(vars[1].(time.Time)), "end is %v", vars[1])
+	})
+
+	t.Run("fields alone are not a search", func(t *testing.T) {
+		_, err := NewSearchService(new(gorm_search.SearchRepoMock)).SearchTasks(1, "tag:home", "", 1, 20)
+		assert.ErrorIs(t, err, ErrEmptyQuery)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		_, err := NewSearchService(new(gorm_search.SearchRepoMock)).SearchTasks(1, "milk colour:red", "", 1, 20)
+		var qerr *taskquery.Error
+		require.ErrorAs(t, err, &qerr)
+		assert.Equal(t, 6, qerr.Pos)
+	})
+}
@@ -0,0 +1,205 @@
+package stats_service
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_stats"
+	"taskflow/internal/repository/gorm/gorm_user"
+)
+
+// Period lengths.
+const (
+	IntervalDay  = "day"
+	IntervalWeek = "week"
+)
+
+const (
+	// DefaultRangeDays is the number of days reported when no range is given.
+	DefaultRangeDays = 30
+	// MaxRangeDays is the widest date range a request may cover.
+	MaxRangeDays = 366
+
+	dateLayout = "2006-01-02"
+)
+
+var (
+	ErrInvalidUser     = errors.New("invalid user")
+	ErrInvalidRange    = errors.New("from and to must be YYYY-MM-DD dates, from not after to, at most 366 days apart")
+	ErrInvalidInterval = errors.New("interval must be day or week")
+)
+
+// StatsService reports productivity statistics in the user's time zone.
+type StatsService struct {
+	stats gorm_stats.StatsRepositoryInterface
+	users gorm_user.UserRepositoryInterface
+	now   func() time.Time
+}
+
+func NewStatsService(stats gorm_stats.StatsRepositoryInterface, users gorm_user.UserRepositoryInterface) *StatsService {
+	return &StatsService{stats: stats, users: users, now: time.Now}
+}
+
+var _ StatsServiceInterface = (*StatsService)(nil)
+
+// GetStats reports on the days from and to, both inclusive, in the user's
+// time zone. The last DefaultRangeDays days are reported when they are empty.
+// Overdue open tasks and the status distribution describe the present, not
+// the range.
+func (s *StatsService) GetStats(userID int, from string, to string, interval string) (dto.StatsResponse, error) {
+	if userID == 0 {
+		return dto.StatsResponse{}, ErrInvalidUser
+	}
+	if interval == "" {
+		interval = IntervalDay
+	}
+	if interval != IntervalDay && interval != IntervalWeek {
+		return dto.StatsResponse{}, ErrInvalidInterval
+	}
+
+	u, err := s.users.GetByID(userID)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	loc := u.Location()
+
+	now := s.now()
+	today := startOfDay(now.In(loc))
+	first, last, err := parseRange(from, to, today)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	end := last.AddDate(0, 0, 1)
+
+	created, err := s.stats.Created(userID, first, end)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	completed, err := s.stats.Completed(userID, first, end)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	summary, err := s.stats.Completion(userID, first, end)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	overdue, err := s.stats.Overdue(userID, now)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	statuses, err := s.stats.StatusCounts(userID)
+	if err != nil {
+		return dto.StatsResponse{}, err
+	}
+	for _, st := range task.Statuses {
+		if _, ok := statuses[st]; !ok {
+			statuses[st] = 0
+		}
+	}
+
+	createdPerDay := perDay(created, loc)
+	completedPerDay := perDay(completed, loc)
+
+	resp := dto.StatsResponse{
+		From:     first.Format(dateLayout),
+		To:       last.Format(dateLayout),
+		TimeZone: loc.String(),
+		Interval: interval,
+		Periods:  []dto.StatsPeriod{},
+		Streaks:  streaks(completedPerDay, first, last, today),
+		Overdue:  dto.StatsOverdue{Open: overdue, CompletedLate: summary.Late},
+		Statuses: statuses,
+	}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		start := date
+		if interval == IntervalWeek {
+			start = startOfWeek(day).Format(dateLayout)
+		}
+		if n := len(resp.Periods); n == 0 || resp.Periods[n-1].Start != start {
+			resp.Periods = append(resp.Periods, dto.StatsPeriod{Start: start})
+		}
+		p := &resp.Periods[len(resp.Periods)-1]
+		p.Created += createdPerDay[date]
+		p.Completed += completedPerDay[date]
+		resp.Created += createdPerDay[date]
+		resp.Completed += completedPerDay[date]
+	}
+	if summary.Count > 0 {
+		avg := int64(math.Round(summary.AvgSeconds))
+		resp.AvgCompletionSeconds = &avg
+	}
+	return resp, nil
+}
+
+// parseRange returns local midnight of the first and last day.
+func parseRange(from string, to string, today time.Time) (time.Time, time.Time, error) {
+	loc := today.Location()
+	last := today
+	if to != "" {
+		d, err := time.ParseInLocation(dateLayout, to, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidRange
+		}
+		last = d
+	}
+	first := last.AddDate(0, 0, -(DefaultRangeDays - 1))
+	if from != "" {
+		d, err := time.ParseInLocation(dateLayout, from, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidRange
+		}
+		first = d
+	}
+	if first.After(last) || !first.AddDate(0, 0, MaxRangeDays).After(last) {
+		return time.Time{}, time.Time{}, ErrInvalidRange
+	}
+	return first, last, nil
+}
+
+// perDay folds UTC buckets into counts per local date.
+func perDay(buckets []gorm_stats.Bucket, loc *time.Location) map[string]int64 {
+	days := make(map[string]int64)
+	for _, b := range buckets {
+		days[b.Start.In(loc).Format(dateLayout)] += b.Count
+	}
+	return days
+}
+
+// streaks finds runs of consecutive days with at least one completion.
+func streaks(completed map[string]int64, first time.Time, last time.Time, today time.Time) dto.StatsStreaks {
+	var st dto.StatsStreaks
+	run := 0
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		if completed[day.Format(dateLayout)] == 0 {
+			run = 0
+			continue
+		}
+		run++
+		st.Longest = max(st.Longest, run)
+	}
+
+	// Today still counts as part of the streak before anything is completed
+	day := last
+	if day.Equal(today) && completed[day.Format(dateLayout)] == 0 {
+		day = day.AddDate(0, 0, -1)
+	}
+	for ; !day.Before(first) && completed[day.Format(dateLayout)] > 0; day = day.AddDate(0, 0, -1) {
+		st.Current++
+	}
+	return st
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// startOfWeek returns the Monday on or before day.
+func startOfWeek(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,7 @@
+package stats_service
+
+import "taskflow/internal/dto"
+
+type StatsServiceInterface interface {
+	GetStats(userID int, from string, to string, interval string) (dto.StatsResponse, error)
+}
@@ -0,0 +1,18 @@
+package stats_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type StatsServiceMock struct {
+	mock.Mock
+}
+
+var _ StatsServiceInterface = (*StatsServiceMock)(nil)
+
+func (m *StatsServiceMock) GetStats(userID int, from string, to string, interval string) (dto.StatsResponse, error) {
+	args := m.Called(userID, from, to, interval)
+	return args.Get(0).(dto.StatsResponse), args.Error(1)
+}
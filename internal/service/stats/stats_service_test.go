@@ -0,0 +1,120 @@
+package stats_service
+
+import (
+	"taskflow/internal/domain/user"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_stats"
+	"taskflow/internal/repository/gorm/gorm_user"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func utc(month time.Month, day, hour, minute int) time.Time {
+	return time.Date(2025, month, day, hour, minute, 0, 0, time.UTC)
+}
+
+func instant(want time.Time) any {
+	return mock.MatchedBy(func(t time.Time) bool { return t.Equal(want) })
+}
+
+// The range spans the end of daylight saving time in New York on 2 November
+// 2025, when the offset changes from -4h to -5h.
+func setup(t *testing.T) *StatsService {
+	t.Helper()
+
+	now := utc(time.November, 3, 15, 0) // 10:00 on 3 November in New York
+	first := utc(time.October, 31, 4, 0)
+	end := utc(time.November, 4, 5, 0)
+
+	users := new(gorm_user.MockUserRepository)
+	users.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "America/New_York"}, nil)
+
+	stats := new(gorm_stats.StatsRepoMock)
+	stats.On("Created", 1, instant(first), instant(end)).Return([]gorm_stats.Bucket{
+		{Start: utc(time.November, 1, 3, 45), Count: 2}, // 23:45 on 31 October
+		{Start: utc(time.November, 3, 4, 30), Count: 1}, // 23:30 on 2 November
+	}, nil)
+	stats.On("Completed", 1, instant(first), instant(end)).Return([]gorm_stats.Bucket{
+		{Start: utc(time.November, 1, 12, 0), Count: 1},
+		{Start: utc(time.November, 2, 12, 0), Count: 1},
+		{Start: utc(time.November, 3, 4, 30), Count: 1},
+	}, nil)
+	stats.On("Completion", 1, instant(first), instant(end)).Return(gorm_stats.CompletionSummary{Count: 3, AvgSeconds: 3600.4, Late: 1}, nil)
+	stats.On("Overdue", 1, now).Return(int64(2), nil)
+	stats.On("StatusCounts", 1).Return(map[string]int64{"pending": 4, "completed": 3}, nil)
+
+	s := NewStatsService(stats, users)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestStatsService_GetStats(t *testing.T) {
+	got, err := setup(t).GetStats(1, "2025-10-31", "2025-11-03", "")
+	require.NoError(t, err)
+
+	avg := int64(3600)
+	assert.Equal(t, dto.StatsResponse{
+		From:     "2025-10-31",
+		To:       "2025-11-03",
+		TimeZone: "America/New_York",
+		Interval: "day",
+		Periods: []dto.StatsPeriod{
+			{Start: "2025-10-31", Created: 2},
+			{Start: "2025-11-01", Completed: 1},
+			{Start: "2025-11-02", Created: 1, Completed: 2},
+			{Start: "2025-11-03"},
+		},
+		Created:              3,
+		Completed:            3,
+		AvgCompletionSeconds: &avg,
+		Streaks:              dto.StatsStreaks{Current: 2, Longest: 2},
+		Overdue:              dto.StatsOverdue{Open: 2, CompletedLate: 1},
+		Statuses:             map[string]int64{"pending": 4, "in-progress": 0, "completed": 3},
+	}, got)
+}
+
+func TestStatsService_GetStats_Weekly(t *testing.T) {
+	got, err := setup(t).GetStats(1, "2025-10-31", "2025-11-03", "week")
+	require.NoError(t, err)
+
+	assert.Equal(t, []dto.StatsPeriod{
+		{Start: "2025-10-27", Created: 3, Completed: 3},
+		{Start: "2025-11-03"},
+	}, got.Periods)
+}
+
+func TestStatsService_GetStats_Errors(t *testing.T) {
+	users := new(gorm_user.MockUserRepository)
+	users.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "UTC"}, nil)
+	s := NewStatsService(new(gorm_stats.StatsRepoMock), users)
+
+	_, err := s.GetStats(1, "", "", "month")
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+
+	_, err = s.GetStats(1, "2025-09-05", "2025-09-01", "")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = s.GetStats(1, "2024-01-01", "2025-09-01", "")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = s.GetStats(1, "last week", "", "")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = s.GetStats(0, "", "", "")
+	assert.ErrorIs(t, err, ErrInvalidUser)
+}
+
+func TestStreaks(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 9, d, 0, 0, 0, 0, time.UTC) }
+	completed := map[string]int64{"2025-09-01": 1, "2025-09-02": 2, "2025-09-03": 1, "2025-09-06": 1, "2025-09-07": 1}
+
+	// Today has no completion yet, so the streak up to yesterday counts
+	assert.Equal(t, dto.StatsStreaks{Current: 2, Longest: 3}, streaks(completed, day(1), day(8), day(8)))
+	// A past day without completions ends the streak
+	assert.Equal(t, dto.StatsStreaks{Current: 0, Longest: 3}, streaks(completed, day(1), day(8), day(20)))
+	assert.Equal(t, dto.StatsStreaks{Current: 2, Longest: 2}, streaks(completed, day(2), day(7), day(20)))
+}
@@ -1,38 +1,462 @@
 package task_service
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"taskflow/internal/domain/notification"
 	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/user"
 	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/notify"
 	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/scheduler"
+	"taskflow/internal/taskquery"
+	"taskflow/pkg/lexorank"
+	"taskflow/pkg/quickadd"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Limits matching the validation of dto.CreateTaskRequest.
+const (
+	MaxTaskLength        = 20
+	MaxDescriptionLength = 2000
+	MaxTags              = 20
+	MaxTagLength         = 50
+)
+
+const (
+	// KindPurgeTrash is the scheduler job that empties old trash.
+	KindPurgeTrash = "tasks.purge_trash"
+	// PurgeTrashInterval is how often old trash is emptied.
+	PurgeTrashInterval = 24 * time.Hour
+	// TrashRetention is how long a task stays in the trash before it is
+	// deleted for good.
+	TrashRetention = 30 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidAnchor is returned by Move when the anchors are not other
+	// tasks of a single list in the right order.
+	ErrInvalidAnchor = errors.New("after_id and before_id must be other tasks in the same list, in that order")
+	// ErrWIPLimitReached is returned when a task would enter a board column
+	// that is already at its work-in-progress limit.
+	ErrWIPLimitReached = errors.New("column has reached its WIP limit")
+	// ErrTaskTooLong is returned when a title built from text, such as the
+	// one QuickAdd parses, is longer than a task name may be.
+	ErrTaskTooLong = errors.New("task name must be at most 20 characters")
+	// Errors of ValidateImport.
+	ErrEmptyTaskName      = errors.New("task name cannot be empty")
+	ErrDescriptionTooLong = errors.New("description must be at most 2000 characters")
+	ErrInvalidTags        = errors.New("tasks can have at most 20 tags of at most 50 characters")
+	ErrInvalidStatus      = errors.New("status must be pending, in-progress or completed")
+	ErrInvalidRecurrence  = errors.New("recurrence must be an RRULE such as FREQ=WEEKLY")
+	ErrInvalidEstimate    = errors.New("estimate must be between 1 and 100000 minutes")
 )
 
+// ImportError reports which task of an import is invalid.
+type ImportError struct {
+	Index int
+	Err   error
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("task %d: %v", e.Index+1, e.Err)
+}
+
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// AttachmentCleaner removes the stored files of purged tasks. The
+// attachment records go with the tasks.
+type AttachmentCleaner interface {
+	StorageKeys(taskIDs []int) ([]string, error)
+	DeleteBlobs(keys []string)
+}
+
+// WIPLimiter looks up the work-in-progress limit of a board column; 0 means
+// the column has no limit.
+type WIPLimiter interface {
+	WIPLimit(userID int, projectID *int, status string) (int, error)
+}
+
+// UserLookup finds users, whose time zones QuickAdd and FilterTasks read
+// dates in.
+type UserLookup interface {
+	GetByID(id int) (*user.User, error)
+}
+
 type TaskService struct {
-	repo gorm_task.TaskRepositoryInterface
+	repo        gorm_task.TaskRepositoryInterface
+	attachments AttachmentCleaner
+	wip         WIPLimiter
+	users       UserLookup
+	publisher   notify.Publisher
+	now         func() time.Time
+}
+
+// Option configures optional TaskService collaborators.
+type Option func(*TaskService)
+
+// WithAttachmentCleaner makes EmptyTrash and PurgeTrash remove the
+// attachments of purged tasks.
+func WithAttachmentCleaner(c AttachmentCleaner) Option {
+	return func(s *TaskService) {
+		s.attachments = c
+	}
+}
+
+// WithWIPLimits makes UpdateStatus, Move, Replace and Import refuse to put a
+// task into a board column that is at its WIP limit.
+func WithWIPLimits(l WIPLimiter) Option {
+	return func(s *TaskService) {
+		s.wip = l
+	}
+}
+
+// WithUsers makes QuickAdd and FilterTasks resolve dates in the user's time
+// zone instead of UTC.
+func WithUsers(u UserLookup) Option {
+	return func(s *TaskService) {
+		s.users = u
+	}
+}
+
+// WithPublisher makes NotifySubtasksDone notify the owner when the last
+// open subtask of a task is completed.
+func WithPublisher(p notify.Publisher) Option {
+	return func(s *TaskService) {
+		s.publisher = p
+	}
 }
 
-func NewTaskService(repo gorm_task.TaskRepositoryInterface) *TaskService {
-	return &TaskService{repo: repo}
+func NewTaskService(repo gorm_task.TaskRepositoryInterface, opts ...Option) *TaskService {
+	s := &TaskService{repo: repo, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 var _ TaskServiceInterface = (*TaskService)(nil)
 
-func (s *TaskService) CreateTask(userID int, taskRequest *dto.CreateTaskRequest) error {
+// CreateTask persists a new task and returns its stored representation.
+func (s *TaskService) CreateTask(userID int, taskRequest *dto.CreateTaskRequest) (dto.GetTaskResponse, error) {
 	if userID == 0 {
-		return errors.New("invalid user")
+		return dto.GetTaskResponse{}, errors.New("invalid user")
 	}
 
 	if taskRequest.Task == "" {
-		return errors.New("task name cannot be empty")
+		return dto.GetTaskResponse{}, errors.New("task name cannot be empty")
+	}
+
+	priority, err := task.ParsePriority(taskRequest.Priority)
+	if err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+
+	return s.create(task.Task{
+		UserID:      userID,
+		Task:        taskRequest.Task,
+		Description: taskRequest.Description,
+		Status:      "pending",
+		Priority:    priority,
+		DueAt:       taskRequest.DueAt,
+		Tags:        normalizeTags(taskRequest.Tags),
+
+		EstimateMinutes: taskRequest.EstimateMinutes,
+	})
+}
+
+// QuickAdd creates a task from a line such as "Pay rent tomorrow 9am
+// #finance !high every month" and reports how the line was read. A dry run
+// only reports it.
+func (s *TaskService) QuickAdd(userID int, req *dto.QuickAddRequest) (dto.QuickAddResponse, error) {
+	if userID == 0 {
+		return dto.QuickAddResponse{}, errors.New("invalid user")
+	}
+
+	loc, err := s.location(userID)
+	if err != nil {
+		return dto.QuickAddResponse{}, err
+	}
+
+	parsed, err := quickadd.Parse(req.Text, s.now().In(loc))
+	if err != nil {
+		return dto.QuickAddResponse{}, err
+	}
+	if utf8.RuneCountInString(parsed.Title) > MaxTaskLength {
+		return dto.QuickAddResponse{}, ErrTaskTooLong
 	}
 
-	task := task.Task{
-		UserID: userID,
-		Task:   taskRequest.Task,
-		Status: "pending",
+	resp := dto.QuickAddResponse{
+		Parsed: dto.QuickAddParsed{
+			Task:       parsed.Title,
+			DueAt:      parsed.DueAt,
+			Tags:       NormalizeTagNames(parsed.Tags),
+			Priority:   parsed.Priority,
+			Recurrence: parsed.Recurrence,
+		},
+		Tokens:   make([]dto.QuickAddToken, 0, len(parsed.Tokens)),
+		TimeZone: loc.String(),
+	}
+	for _, t := range parsed.Tokens {
+		resp.Tokens = append(resp.Tokens, dto.QuickAddToken{Text: t.Text, Kind: string(t.Kind), Value: t.Value})
+	}
+	if req.DryRun {
+		return resp, nil
 	}
 
-	return s.repo.Create(&task)
+	priority, err := task.ParsePriority(parsed.Priority)
+	if err != nil {
+		return dto.QuickAddResponse{}, err
+	}
+	created, err := s.create(task.Task{
+		UserID:     userID,
+		Task:       parsed.Title,
+		Status:     "pending",
+		Priority:   priority,
+		DueAt:      parsed.DueAt,
+		Tags:       normalizeTags(parsed.Tags),
+		Recurrence: parsed.Recurrence,
+	})
+	if err != nil {
+		return dto.QuickAddResponse{}, err
+	}
+	resp.Task = &created
+	return resp, nil
+}
+
+// Import creates tasks brought in from elsewhere, all of them or, when
+// one fails, none. Invalid tasks, and tasks that would enter a board column
+// at its WIP limit, are reported as an *ImportError. Tasks are added in
+// order at the end of their lists, and each records TaskCreated.
+func (s *TaskService) Import(userID int, reqs []dto.ImportTaskRequest) ([]dto.GetTaskResponse, error) {
+	if userID == 0 {
+		return nil, errors.New("invalid user")
+	}
+
+	tasks := make([]task.Task, len(reqs))
+	for i := range reqs {
+		if err := ValidateImport(&reqs[i]); err != nil {
+			return nil, &ImportError{Index: i, Err: err}
+		}
+		tasks[i] = importedTask(userID, &reqs[i], s.now())
+	}
+
+	resps := make([]dto.GetTaskResponse, 0, len(tasks))
+	err := s.repo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		last := map[int]string{}
+		for i := range tasks {
+			t := &tasks[i]
+			scope := 0
+			if t.ProjectID != nil {
+				scope = *t.ProjectID
+			}
+			prev, ok := last[scope]
+			if !ok {
+				var err error
+				if prev, err = repo.LastPosition(userID, t.ProjectID); err != nil {
+					return err
+				}
+				if !lexorank.Valid(prev) {
+					prev = ""
+				}
+			}
+			pos, err := lexorank.Between(prev, "")
+			if err != nil {
+				return err
+			}
+			t.Position, last[scope] = pos, pos
+
+			// Tasks created earlier in this transaction are counted.
+			if err := s.checkWIP(repo, userID, &task.Task{}, t.ProjectID, t.Status); err != nil {
+				return &ImportError{Index: i, Err: err}
+			}
+			if err := repo.Create(t); err != nil {
+				return err
+			}
+			resp := ToTaskResponse(*t)
+			if err := repo.AddEvents(events.TaskCreated{UserID: userID, Task: resp}); err != nil {
+				return err
+			}
+			resps = append(resps, resp)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resps, nil
+}
+
+// Replace overwrites what a task has in common with other task formats,
+// such as a VTODO: its name, description, status, priority, due date, tags
+// and recurrence, and its estimate when req has one. The project, parent
+// and position are kept; req.ProjectID is ignored. Changing the status
+// records StatusChanged.
+func (s *TaskService) Replace(userID int, id int, req *dto.ImportTaskRequest) (dto.GetTaskResponse, error) {
+	if userID == 0 {
+		return dto.GetTaskResponse{}, errors.New("invalid user")
+	}
+	if err := ValidateImport(req); err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+
+	var resp dto.GetTaskResponse
+	err := s.repo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		t, err := repo.GetByID(userID, id)
+		if err != nil {
+			return err
+		}
+		r := importedTask(userID, req, s.now())
+		if err := s.checkWIP(repo, userID, t, t.ProjectID, r.Status); err != nil {
+			return err
+		}
+
+		from := t.Status
+		if r.Status == task.StatusCompleted && req.CompletedAt == nil && t.CompletedAt != nil {
+			r.CompletedAt = t.CompletedAt
+		}
+		t.Task, t.Description, t.Status, t.Priority = r.Task, r.Description, r.Status, r.Priority
+		t.DueAt, t.CompletedAt, t.Recurrence = r.DueAt, r.CompletedAt, r.Recurrence
+		if r.EstimateMinutes != nil {
+			t.EstimateMinutes = r.EstimateMinutes
+		}
+
+		// Save would only add tags, so they are replaced separately.
+		t.Tags = nil
+		if err := repo.Update(t); err != nil {
+			return err
+		}
+		names := make([]string, len(r.Tags))
+		for i, tag := range r.Tags {
+			names[i] = tag.Name
+		}
+		if err := repo.SetTags(id, names); err != nil {
+			return err
+		}
+		t.Tags = r.Tags
+
+		resp = ToTaskResponse(*t)
+		if from == t.Status {
+			return nil
+		}
+		return repo.AddEvents(events.StatusChanged{UserID: userID, Task: resp, From: from})
+	})
+	if err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+	return resp, nil
+}
+
+// ValidateImport checks a task the way Import does, so importers can
+// report every invalid task before importing.
+func ValidateImport(req *dto.ImportTaskRequest) error {
+	name := strings.TrimSpace(req.Task)
+	switch {
+	case name == "":
+		return ErrEmptyTaskName
+	case utf8.RuneCountInString(name) > MaxTaskLength:
+		return ErrTaskTooLong
+	case utf8.RuneCountInString(req.Description) > MaxDescriptionLength:
+		return ErrDescriptionTooLong
+	case len(req.Tags) > MaxTags:
+		return ErrInvalidTags
+	case req.Status != "" && !task.ValidStatus(req.Status):
+		return ErrInvalidStatus
+	case req.Recurrence != "" && (len(req.Recurrence) > 255 || !strings.HasPrefix(strings.ToUpper(req.Recurrence), "FREQ=")):
+		return ErrInvalidRecurrence
+	}
+	for _, tag := range req.Tags {
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return ErrInvalidTags
+		}
+	}
+	if req.EstimateMinutes != nil && (*req.EstimateMinutes < 1 || *req.EstimateMinutes > 100000) {
+		return ErrInvalidEstimate
+	}
+	_, err := task.ParsePriority(req.Priority)
+	return err
+}
+
+// FitTitle makes a title from text fit a task name: a title longer than
+// MaxTaskLength is cut short with "…" and kept in full at the top of the
+// description.
+func FitTitle(title string, description string) (string, string) {
+	title = strings.Join(strings.Fields(title), " ")
+	if utf8.RuneCountInString(title) <= MaxTaskLength {
+		return title, description
+	}
+	if description == "" {
+		description = title
+	} else {
+		description = title + "\n\n" + description
+	}
+	runes := []rune(title)[:MaxTaskLength-1]
+	return strings.TrimSpace(string(runes)) + "…", description
+}
+
+func importedTask(userID int, req *dto.ImportTaskRequest, now time.Time) task.Task {
+	priority, _ := task.ParsePriority(req.Priority)
+	t := task.Task{
+		UserID:      userID,
+		Task:        strings.TrimSpace(req.Task),
+		Description: req.Description,
+		Status:      task.StatusPending,
+		Priority:    priority,
+		DueAt:       req.DueAt,
+		Tags:        normalizeTags(req.Tags),
+		Recurrence:  strings.ToUpper(req.Recurrence),
+		ProjectID:   req.ProjectID,
+		ParentID:    req.ParentID,
+
+		EstimateMinutes: req.EstimateMinutes,
+	}
+	if req.ExternalID != "" {
+		t.ExternalID = &req.ExternalID
+	}
+	if req.Status != "" {
+		t.Status = req.Status
+	}
+	if t.Status == task.StatusCompleted {
+		t.CompletedAt = req.CompletedAt
+		if t.CompletedAt == nil {
+			t.CompletedAt = &now
+		}
+	}
+	return t
+}
+
+// create stores a new task at the end of the inbox and records TaskCreated.
+func (s *TaskService) create(t task.Task) (dto.GetTaskResponse, error) {
+	last, err := s.repo.LastPosition(t.UserID, nil)
+	if err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+	if !lexorank.Valid(last) {
+		last = ""
+	}
+	if t.Position, err = lexorank.Between(last, ""); err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+
+	err = s.repo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		if err := repo.Create(&t); err != nil {
+			return err
+		}
+		return repo.AddEvents(events.TaskCreated{UserID: t.UserID, Task: ToTaskResponse(t)})
+	})
+	if err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+	return ToTaskResponse(t), nil
 }
 
 func (s *TaskService) GetTask(userID int, id int) (dto.GetTaskResponse, error) {
@@ -44,11 +468,7 @@ func (s *TaskService) GetTask(userID int, id int) (dto.GetTaskResponse, error) {
 	if err != nil {
 		return dto.GetTaskResponse{}, err
 	}
-	return dto.GetTaskResponse{
-		ID:     t.ID,
-		Task:   t.Task,
-		Status: t.Status,
-	}, nil
+	return ToTaskResponse(*t), nil
 }
 
 func (s *TaskService) ListTasks(userID int) (dto.ListTasksResponse, error) {
@@ -61,28 +481,479 @@ func (s *TaskService) ListTasks(userID int) (dto.ListTasksResponse, error) {
 		return dto.ListTasksResponse{}, err
 	}
 
+	return toListResponse(tasks), nil
+}
+
+// FilterTasks lists the user's tasks that match a query-language expression
+// such as `status:pending AND tag:work`. Syntax and validation problems are
+// returned as *taskquery.Error so callers can point at the offending position.
+func (s *TaskService) FilterTasks(userID int, query string) (dto.ListTasksResponse, error) {
+	if userID == 0 {
+		return dto.ListTasksResponse{}, errors.New("invalid user")
+	}
+
+	node, err := taskquery.Parse(query)
+	if err != nil {
+		return dto.ListTasksResponse{}, err
+	}
+	// Relative dates such as due:today follow the user's day, not the server's.
+	loc, err := s.location(userID)
+	if err != nil {
+		return dto.ListTasksResponse{}, err
+	}
+	scope, err := taskquery.Compile(node, s.now().In(loc))
+	if err != nil {
+		return dto.ListTasksResponse{}, err
+	}
+
+	tasks, err := s.repo.Find(userID, scope)
+	if err != nil {
+		return dto.ListTasksResponse{}, err
+	}
+	return toListResponse(tasks), nil
+}
+
+// location returns the user's time zone, or UTC without a UserLookup.
+func (s *TaskService) location(userID int) (*time.Location, error) {
+	return UserLocation(s.users, userID)
+}
+
+// UserLocation returns the time zone of a user, or UTC when users is nil.
+// Services that compile task queries use it so relative dates such as
+// due:today follow the user's day.
+func UserLocation(users UserLookup, userID int) (*time.Location, error) {
+	if users == nil {
+		return time.UTC, nil
+	}
+	u, err := users.GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return u.Location(), nil
+}
+
+// UpdateStatus changes a task's status, records StatusChanged when the
+// status is a different one and returns the updated task.
+func (s *TaskService) UpdateStatus(userID int, id int, status string) (dto.GetTaskResponse, error) {
+	if !task.ValidStatus(status) {
+		return dto.GetTaskResponse{}, errors.New("invalid status")
+	}
+
+	var resp dto.GetTaskResponse
+	err := s.repo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		t, err := repo.GetByID(userID, id)
+		if err != nil {
+			return err
+		}
+		if err := s.checkWIP(repo, userID, t, t.ProjectID, status); err != nil {
+			return err
+		}
+		if err := repo.UpdateStatus(userID, id, status); err != nil {
+			return err
+		}
+
+		updated, err := repo.GetByID(userID, id)
+		if err != nil {
+			return err
+		}
+		resp = ToTaskResponse(*updated)
+		if t.Status == status {
+			return nil
+		}
+		return repo.AddEvents(events.StatusChanged{UserID: userID, Task: resp, From: t.Status})
+	})
+	if err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+	return resp, nil
+}
+
+// NotifySubtasksDone consumes StatusChanged events and tells the owner when
+// completing a subtask finished the last open subtask of its parent.
+func (s *TaskService) NotifySubtasksDone(ctx context.Context, e *events.Envelope) error {
+	var ev events.StatusChanged
+	if err := e.Decode(&ev); err != nil {
+		return err
+	}
+	if s.publisher == nil || ev.Task.Status != task.StatusCompleted || ev.Task.ParentID == nil {
+		return nil
+	}
+	parentID := *ev.Task.ParentID
+
+	open, err := s.repo.Count(ev.UserID, func(db *gorm.DB) *gorm.DB {
+		return db.Where("parent_id = ? AND status <> ?", parentID, task.StatusCompleted)
+	})
+	if err != nil || open > 0 {
+		return err
+	}
+	parent, err := s.repo.GetByID(ev.UserID, parentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil || parent.Status == task.StatusCompleted {
+		return err
+	}
+
+	// The key makes redelivered events notify only once.
+	return s.publisher.Publish(ctx, notify.Message{
+		UserID: ev.UserID,
+		Type:   notification.TypeSubtasksDone,
+		Title:  "Subtasks done: " + parent.Task,
+		Body:   fmt.Sprintf("All subtasks of %q are completed.", parent.Task),
+		TaskID: &parent.ID,
+		Key:    fmt.Sprintf("subtasks-done:%d:%d", parentID, ev.Task.ID),
+	})
+}
+
+// Restore brings a task back from the trash, records TaskRestored and
+// returns it. Restoring a task that is not in the trash simply returns it.
+func (s *TaskService) Restore(userID int, id int) (dto.GetTaskResponse, error) {
+	if userID == 0 {
+		return dto.GetTaskResponse{}, errors.New("invalid user")
+	}
+
+	var resp dto.GetTaskResponse
+	err := s.repo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		restored, err := repo.RestoreBatch(userID, []int{id})
+		if err != nil {
+			return err
+		}
+		t, err := repo.GetByID(userID, id)
+		if err != nil {
+			return err
+		}
+		resp = ToTaskResponse(*t)
+		if restored == 0 {
+			return nil
+		}
+		return repo.AddEvents(events.TaskRestored{UserID: userID, Task: resp})
+	})
+	if err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+	return resp, nil
+}
+
+// Move places a task between two neighbours, moving it into their list
+// (a project or the inbox) and into req.Status's board column if needed.
+// It records TaskMoved, and StatusChanged when the column changed.
+func (s *TaskService) Move(userID int, id int, req *dto.MoveTaskRequest) (dto.GetTaskResponse, error) {
+	if userID == 0 {
+		return dto.GetTaskResponse{}, errors.New("invalid user")
+	}
+	if req.AfterID == id || req.BeforeID == id || (req.AfterID != 0 && req.AfterID == req.BeforeID) {
+		return dto.GetTaskResponse{}, ErrInvalidAnchor
+	}
+	if req.Status != "" && !task.ValidStatus(req.Status) {
+		return dto.GetTaskResponse{}, errors.New("invalid status")
+	}
+
+	var resp dto.GetTaskResponse
+	err := s.repo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		t, err := repo.GetByID(userID, id)
+		if err != nil {
+			return err
+		}
+
+		projectID := t.ProjectID
+		first := true
+		for _, anchorID := range []int{req.AfterID, req.BeforeID} {
+			if anchorID == 0 {
+				continue
+			}
+			anchor, err := repo.GetByID(userID, anchorID)
+			if err != nil {
+				return err
+			}
+			if first {
+				projectID = anchor.ProjectID
+				first = false
+			} else if !sameProject(projectID, anchor.ProjectID) {
+				return ErrInvalidAnchor
+			}
+		}
+
+		status := t.Status
+		if req.Status != "" {
+			status = req.Status
+		}
+		if err := s.checkWIP(repo, userID, t, projectID, status); err != nil {
+			return err
+		}
+		if status != t.Status {
+			if err := repo.UpdateStatus(userID, id, status); err != nil {
+				return err
+			}
+		}
+
+		if err := placeTask(repo, userID, id, projectID, req); err != nil {
+			return err
+		}
+
+		moved, err := repo.GetByID(userID, id)
+		if err != nil {
+			return err
+		}
+		resp = ToTaskResponse(*moved)
+		evs := []events.Event{events.TaskMoved{UserID: userID, Task: resp}}
+		if status != t.Status {
+			evs = append(evs, events.StatusChanged{UserID: userID, Task: resp, From: t.Status})
+		}
+		return repo.AddEvents(evs...)
+	})
+	if err != nil {
+		return dto.GetTaskResponse{}, err
+	}
+	return resp, nil
+}
+
+// placeTask gives task id a position key between req's anchors in list
+// projectID. Only the moved task gets a new key, unless keys have grown
+// too long and the whole list is rebalanced.
+func placeTask(repo gorm_task.TaskRepositoryInterface, userID int, id int, projectID *int, req *dto.MoveTaskRequest) error {
+	list, err := repo.ListScope(userID, projectID)
+	if err != nil {
+		return err
+	}
+	list = withoutTask(list, id)
+
+	// The task goes in at index at, below the neighbour at index next.
+	at, next := len(list), len(list)
+	if req.AfterID != 0 {
+		i := indexOfTask(list, req.AfterID)
+		if i < 0 {
+			return ErrInvalidAnchor
+		}
+		at, next = i+1, i+1
+	}
+	if req.BeforeID != 0 {
+		j := indexOfTask(list, req.BeforeID)
+		if j < 0 || j < at && req.AfterID != 0 {
+			return ErrInvalidAnchor
+		}
+		if req.AfterID == 0 {
+			at = j
+		}
+		next = j
+	}
+
+	lo, hi := "", ""
+	if at > 0 {
+		lo = list[at-1].Position
+	}
+	if next < len(list) {
+		hi = list[next].Position
+	}
+	if (at == 0 || lexorank.Valid(lo)) && (next == len(list) || lexorank.Valid(hi)) {
+		key, err := lexorank.Between(lo, hi)
+		if err == nil && len(key) <= lexorank.MaxLength {
+			return repo.SetPosition(userID, id, projectID, key)
+		}
+	}
+
+	// Keys are too long, duplicated or missing: respace the whole list.
+	ordered := make([]task.Task, 0, len(list)+1)
+	ordered = append(ordered, list[:at]...)
+	ordered = append(ordered, task.Task{ID: id})
+	ordered = append(ordered, list[at:]...)
+	for i, key := range lexorank.Spread(len(ordered)) {
+		if ordered[i].ID != id && ordered[i].Position == key {
+			continue
+		}
+		if err := repo.SetPosition(userID, ordered[i].ID, projectID, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete moves a task to the trash and records TaskDeleted. Its comments
+// and attachments are kept until the trash is emptied, by the user or by
+// PurgeTrash after TrashRetention.
+func (s *TaskService) Delete(userID int, id int) error {
+	// The repository delete silently ignores tasks that belong to someone
+	// else, so look the task up first.
+	t, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		if err := repo.Delete(userID, id); err != nil {
+			return err
+		}
+		return repo.AddEvents(events.TaskDeleted{UserID: userID, Task: ToTaskResponse(*t)})
+	})
+}
+
+// EmptyTrash permanently deletes the user's trashed tasks with their
+// comments, attachments and time entries. TaskDeleted was recorded when
+// they were moved to the trash, so no events are added. Attachment files
+// are removed only once the purge has committed, so a failed purge never
+// leaves tasks pointing at missing files.
+func (s *TaskService) EmptyTrash(userID int) (dto.EmptyTrashResponse, error) {
+	purged, err := s.purgeTrash(userID, s.now())
+	if err != nil {
+		return dto.EmptyTrashResponse{}, err
+	}
+	return dto.EmptyTrashResponse{Deleted: int(purged)}, nil
+}
+
+// PurgeTrash handles KindPurgeTrash jobs. It empties what has been in the
+// trash for longer than TrashRetention, as EmptyTrash does.
+func (s *TaskService) PurgeTrash(ctx context.Context, job *scheduler.Job) error {
+	before := s.now().Add(-TrashRetention)
+	owners, err := s.repo.TrashOwners(before)
+	if err != nil {
+		return err
+	}
+	for _, userID := range owners {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := s.purgeTrash(userID, before); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// purgeTrash deletes the user's tasks trashed before the given time.
+func (s *TaskService) purgeTrash(userID int, before time.Time) (int64, error) {
+	var purged int64
+	var blobs []string
+	err := s.repo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		ids, err := repo.TrashedIDs(userID, before)
+		if err != nil {
+			return err
+		}
+		if s.attachments != nil {
+			if blobs, err = s.attachments.StorageKeys(ids); err != nil {
+				return err
+			}
+		}
+		purged, err = repo.Purge(userID, ids)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if s.attachments != nil && len(blobs) > 0 {
+		s.attachments.DeleteBlobs(blobs)
+	}
+	return purged, nil
+}
+
+// ToTaskResponse maps a task entity to its API representation.
+func ToTaskResponse(t task.Task) dto.GetTaskResponse {
+	resp := dto.GetTaskResponse{
+		ID:          t.ID,
+		Task:        t.Task,
+		Description: t.Description,
+		Status:      t.Status,
+		DueAt:       t.DueAt,
+		ProjectID:   t.ProjectID,
+		ParentID:    t.ParentID,
+		Position:    t.Position,
+
+		EstimateMinutes: t.EstimateMinutes,
+		CompletedAt:     t.CompletedAt,
+		Recurrence:      t.Recurrence,
+		CreatedAt:       t.CreatedAt,
+		UpdatedAt:       t.UpdatedAt,
+	}
+	if t.Priority != task.PriorityNone {
+		resp.Priority = t.Priority.String()
+	}
+	for _, tag := range t.Tags {
+		resp.Tags = append(resp.Tags, tag.Name)
+	}
+	return resp
+}
+
+func toListResponse(tasks []task.Task) dto.ListTasksResponse {
 	// Convert []task.Task to []dto.GetTaskResponse
 	var taskResponses []dto.GetTaskResponse
-	for _, task := range tasks {
-		taskResponses = append(taskResponses, dto.GetTaskResponse{
-			ID:     task.ID,
-			Task:   task.Task,
-			Status: task.Status,
-		})
+	for _, t := range tasks {
+		taskResponses = append(taskResponses, ToTaskResponse(t))
 	}
-
 	return dto.ListTasksResponse{
 		Tasks: taskResponses,
-	}, nil
+	}
 }
 
-func (s *TaskService) UpdateStatus(userID int, id int, status string) error {
-	if status != "pending" && status != "completed" {
-		return errors.New("invalid status")
+// normalizeTags converts tag names to Tag entities. See NormalizeTagNames.
+func normalizeTags(names []string) []task.Tag {
+	var tags []task.Tag
+	for _, name := range NormalizeTagNames(names) {
+		tags = append(tags, task.Tag{Name: name})
 	}
-	return s.repo.UpdateStatus(userID, id, status)
+	return tags
 }
 
-func (s *TaskService) Delete(userID int, id int) error {
-	return s.repo.Delete(userID, id)
+// checkWIP fails when t would enter the status column of list projectID
+// while that column is at its WIP limit. Columns with a limit are locked
+// for the rest of the transaction, so two concurrent requests can't both
+// take the last place.
+func (s *TaskService) checkWIP(repo gorm_task.TaskRepositoryInterface, userID int, t *task.Task, projectID *int, status string) error {
+	if s.wip == nil || (t.Status == status && sameProject(t.ProjectID, projectID)) {
+		return nil
+	}
+	// Columns without a limit, most of them, are not locked.
+	limit, err := s.wip.WIPLimit(userID, projectID, status)
+	if err != nil || limit == 0 {
+		return err
+	}
+	// Read the limit again under the lock: it may have changed.
+	if limit, err = repo.LockColumn(userID, projectID, status); err != nil || limit == 0 {
+		return err
+	}
+	n, err := repo.CountColumn(userID, projectID, status)
+	if err != nil {
+		return err
+	}
+	if n >= int64(limit) {
+		return ErrWIPLimitReached
+	}
+	return nil
+}
+
+func sameProject(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func withoutTask(tasks []task.Task, id int) []task.Task {
+	out := tasks[:0]
+	for _, t := range tasks {
+		if t.ID != id {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func indexOfTask(tasks []task.Task, id int) int {
+	for i, t := range tasks {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// NormalizeTagNames lower-cases and trims tag names, dropping blanks and duplicates.
+func NormalizeTagNames(names []string) []string {
+	var normalized []string
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
 }
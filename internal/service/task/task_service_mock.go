@@ -1,7 +1,10 @@
 package task_service
 
 import (
+	"context"
 	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/scheduler"
 
 	"github.com/stretchr/testify/mock"
 )
@@ -12,10 +15,10 @@ type TaskServiceMock struct {
 
 var _ TaskServiceInterface = (*TaskServiceMock)(nil)
 
-func (m *TaskServiceMock) CreateTask(userID int, taskRequest *dto.CreateTaskRequest) error {
+func (m *TaskServiceMock) CreateTask(userID int, taskRequest *dto.CreateTaskRequest) (dto.GetTaskResponse, error) {
 
 	args := m.Called(userID, taskRequest)
-	return args.Error(0)
+	return args.Get(0).(dto.GetTaskResponse), args.Error(1)
 }
 func (m *TaskServiceMock) GetTask(userID int, id int) (dto.GetTaskResponse, error) {
 	args := m.Called(userID, id)
@@ -25,11 +28,52 @@ func (m *TaskServiceMock) ListTasks(userID int) (dto.ListTasksResponse, error) {
 	args := m.Called(userID)
 	return args.Get(0).(dto.ListTasksResponse), args.Error(1)
 }
-func (m *TaskServiceMock) UpdateStatus(userID int, id int, status string) error {
+func (m *TaskServiceMock) FilterTasks(userID int, query string) (dto.ListTasksResponse, error) {
+	args := m.Called(userID, query)
+	return args.Get(0).(dto.ListTasksResponse), args.Error(1)
+}
+func (m *TaskServiceMock) UpdateStatus(userID int, id int, status string) (dto.GetTaskResponse, error) {
 	args := m.Called(userID, id, status)
+	return args.Get(0).(dto.GetTaskResponse), args.Error(1)
+}
+func (m *TaskServiceMock) Restore(userID int, id int) (dto.GetTaskResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.GetTaskResponse), args.Error(1)
+}
+func (m *TaskServiceMock) Move(userID int, id int, req *dto.MoveTaskRequest) (dto.GetTaskResponse, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(dto.GetTaskResponse), args.Error(1)
+}
+func (m *TaskServiceMock) EmptyTrash(userID int) (dto.EmptyTrashResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.EmptyTrashResponse), args.Error(1)
+}
+
+func (m *TaskServiceMock) PurgeTrash(ctx context.Context, job *scheduler.Job) error {
+	args := m.Called(ctx, job)
 	return args.Error(0)
 }
+
 func (m *TaskServiceMock) Delete(userID int, id int) error {
 	args := m.Called(userID, id)
 	return args.Error(0)
 }
+func (m *TaskServiceMock) QuickAdd(userID int, req *dto.QuickAddRequest) (dto.QuickAddResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.QuickAddResponse), args.Error(1)
+}
+
+func (m *TaskServiceMock) Import(userID int, reqs []dto.ImportTaskRequest) ([]dto.GetTaskResponse, error) {
+	args := m.Called(userID, reqs)
+	return args.Get(0).([]dto.GetTaskResponse), args.Error(1)
+}
+
+func (m *TaskServiceMock) Replace(userID int, id int, req *dto.ImportTaskRequest) (dto.GetTaskResponse, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(dto.GetTaskResponse), args.Error(1)
+}
+
+func (m *TaskServiceMock) NotifySubtasksDone(ctx context.Context, e *events.Envelope) error {
+	args := m.Called(ctx, e)
+	return args.Error(0)
+}
@@ -1,15 +1,31 @@
 package task_service
 
 import (
+	"context"
 	"errors"
+	"strings"
+	"taskflow/internal/domain/notification"
 	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/user"
 	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/notify"
+	"taskflow/internal/repository/gorm/gorm_board"
 	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_user"
+	"taskflow/internal/scheduler"
+	attachment_service "taskflow/internal/service/attachment"
+	"taskflow/internal/taskquery"
+	"taskflow/pkg/lexorank"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
 )
 
 func TestTaskService_CreateTask(t *testing.T) {
@@ -18,6 +34,7 @@ func TestTaskService_CreateTask(t *testing.T) {
 		userID      int
 		taskRequest *dto.CreateTaskRequest
 		setupMock   func() *gorm_task.TaskRepoMock
+		wantID      int
 		wantErr     bool
 		errMessage  string
 	}{
@@ -29,11 +46,42 @@ func TestTaskService_CreateTask(t *testing.T) {
 			},
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("LastPosition", 1, (*int)(nil)).Return("i", nil)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
 				mockRepo.On("Create", mock.MatchedBy(func(tk *task.Task) bool {
-					return tk.UserID == 1 && tk.Task == "Buy Milk" && tk.Status == "pending"
+					return tk.UserID == 1 && tk.Task == "Buy Milk" && tk.Status == "pending" && tk.Position == "r"
+				})).Run(func(args mock.Arguments) {
+					args.Get(0).(*task.Task).ID = 7
+				}).Return(nil)
+				mockRepo.On("AddEvents", mock.MatchedBy(func(evs []events.Event) bool {
+					e, ok := evs[0].(events.TaskCreated)
+					return len(evs) == 1 && ok && e.UserID == 1 && e.Task.ID == 7 && e.Task.Task == "Buy Milk"
 				})).Return(nil)
 				return mockRepo
 			},
+			wantID:     7,
+			wantErr:    false,
+			errMessage: "",
+		},
+		{
+			name:   "success case - priority and tags are normalized",
+			userID: 1,
+			taskRequest: &dto.CreateTaskRequest{
+				Task:     "Write report",
+				Priority: "high",
+				Tags:     []string{" Work", "work", "", "Q3"},
+			},
+			setupMock: func() *gorm_task.TaskRepoMock {
+				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("LastPosition", 1, (*int)(nil)).Return("", nil)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("Create", mock.MatchedBy(func(tk *task.Task) bool {
+					return tk.Priority == task.PriorityHigh &&
+						assert.ObjectsAreEqual([]task.Tag{{Name: "work"}, {Name: "q3"}}, tk.Tags)
+				})).Return(nil)
+				mockRepo.On("AddEvents", mock.Anything).Return(nil)
+				return mockRepo
+			},
 			wantErr:    false,
 			errMessage: "",
 		},
@@ -69,6 +117,8 @@ func TestTaskService_CreateTask(t *testing.T) {
 			},
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("LastPosition", 2, (*int)(nil)).Return("", nil)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
 				mockRepo.On("Create", mock.MatchedBy(func(tk *task.Task) bool {
 					return tk.UserID == 2 && tk.Task == "Buy Eggs" && tk.Status == "pending"
 				})).Return(errors.New("db error"))
@@ -84,13 +134,16 @@ func TestTaskService_CreateTask(t *testing.T) {
 			mockRepo := tt.setupMock()
 			service := NewTaskService(mockRepo)
 
-			err := service.CreateTask(tt.userID, tt.taskRequest)
+			got, err := service.CreateTask(tt.userID, tt.taskRequest)
 
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.EqualError(t, err, tt.errMessage)
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, got.ID)
+				assert.Equal(t, tt.taskRequest.Task, got.Task)
+				assert.Equal(t, "pending", got.Status)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -162,6 +215,8 @@ func TestTaskService_GetTask(t *testing.T) {
 }
 
 func TestTaskService_ListTasks(t *testing.T) {
+	created := time.Date(2025, 8, 30, 9, 15, 0, 0, time.UTC)
+	updated := time.Date(2025, 9, 2, 16, 20, 0, 0, time.UTC)
 	tests := []struct {
 		name      string
 		userID    int
@@ -175,13 +230,13 @@ func TestTaskService_ListTasks(t *testing.T) {
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
 				mockRepo.On("List", 1).Return([]task.Task{
-					{ID: 1, Task: "Buy milk", Status: "pending", CreatedAt: time.Now()},
+					{ID: 1, Task: "Buy milk", Status: "pending", CreatedAt: created, UpdatedAt: updated},
 				}, nil)
 				return mockRepo
 			},
 			want: dto.ListTasksResponse{
 				Tasks: []dto.GetTaskResponse{
-					{ID: 1, Task: "Buy milk", Status: "pending"},
+					{ID: 1, Task: "Buy milk", Status: "pending", CreatedAt: created, UpdatedAt: updated},
 				},
 			},
 			wantErr: false,
@@ -228,6 +283,81 @@ func TestTaskService_ListTasks(t *testing.T) {
 	}
 }
 
+func TestTaskService_FilterTasks(t *testing.T) {
+	t.Run("success case - compiled query reaches the repository", func(t *testing.T) {
+		mockRepo := new(gorm_task.TaskRepoMock)
+		mockRepo.On("Find", 1, mock.MatchedBy(func(scopes []func(*gorm.DB) *gorm.DB) bool {
+			return len(scopes) == 1
+		})).Return([]task.Task{
+			{ID: 3, Task: "Write report", Status: "pending", Priority: task.PriorityHigh, Tags: []task.Tag{{Name: "work"}}},
+		}, nil)
+
+		service := NewTaskService(mockRepo)
+		got, err := service.FilterTasks(1, "tag:work priority>=high")
+
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ListTasksResponse{
+			Tasks: []dto.GetTaskResponse{
+				{ID: 3, Task: "Write report", Status: "pending", Priority: "high", Tags: []string{"work"}},
+			},
+		}, got)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("success case - relative dates follow the user's time zone", func(t *testing.T) {
+		newYork, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{DryRun: true})
+		require.NoError(t, err)
+
+		// 02:00 UTC on the 10th is still the evening of the 9th in New York.
+		var vars []any
+		mockRepo := new(gorm_task.TaskRepoMock)
+		mockRepo.On("Find", 1, mock.Anything).Run(func(args mock.Arguments) {
+			scopes := args.Get(1).([]func(*gorm.DB) *gorm.DB)
+			vars = db.Scopes(scopes...).Find(&[]task.Task{}).Statement.Vars
+		}).Return([]task.Task{}, nil)
+		users := new(gorm_user.MockUserRepository)
+		users.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "America/New_York"}, nil)
+
+		service := NewTaskService(mockRepo, WithUsers(users))
+		service.now = func() time.Time { return time.Date(2025, 9, 10, 2, 0, 0, 0, time.UTC) }
+		_, err = service.FilterTasks(1, "due:today")
+
+		require.NoError(t, err)
+		require.Len(t, vars, 2)
+		assert.True(t, time.Date(2025, 9, 9, 0, 0, 0, 0, newYork).Equal(vars[0].(time.Time)), "start is %v", vars[0])
+		assert.True(t, time.Date(2025, 9, 10, 0, 0, 0, 0, newYork).Equal(vars[1].(time.Time)), "end is %v", vars[1])
+	})
+
+	t.Run("failure case - unknown user", func(t *testing.T) {
+		users := new(gorm_user.MockUserRepository)
+		users.On("GetByID", 1).Return(nil, gorm.ErrRecordNotFound)
+		service := NewTaskService(new(gorm_task.TaskRepoMock), WithUsers(users))
+
+		_, err := service.FilterTasks(1, "due:today")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("failure case - invalid query", func(t *testing.T) {
+		mockRepo := new(gorm_task.TaskRepoMock)
+		service := NewTaskService(mockRepo)
+
+		_, err := service.FilterTasks(1, "colour:red")
+
+		var qerr *taskquery.Error
+		assert.ErrorAs(t, err, &qerr)
+		assert.Equal(t, 1, qerr.Pos)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("failure case - invalid user", func(t *testing.T) {
+		service := NewTaskService(new(gorm_task.TaskRepoMock))
+		_, err := service.FilterTasks(0, "tag:work")
+		assert.EqualError(t, err, "invalid user")
+	})
+}
+
 func TestTaskService_UpdateStatus(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -244,23 +374,33 @@ func TestTaskService_UpdateStatus(t *testing.T) {
 			status: "pending",
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("GetByID", 123, 1).Return(&task.Task{ID: 1, UserID: 123, Status: "completed"}, nil).Once()
 				mockRepo.On("UpdateStatus",
 					123,
 					1,
 					"pending",
 				).Return(nil)
+				mockRepo.On("GetByID", 123, 1).Return(&task.Task{ID: 1, UserID: 123, Status: "pending"}, nil).Once()
+				mockRepo.On("AddEvents", []events.Event{events.StatusChanged{
+					UserID: 123,
+					Task:   dto.GetTaskResponse{ID: 1, Status: "pending"},
+					From:   "completed",
+				}}).Return(nil)
 				return mockRepo
 			},
 			wantErr: false,
 		},
 		{
-			name:   "success - completed",
+			name:   "success - unchanged status records no event",
 			userID: 123,
 			id:     2,
 			status: "completed",
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
 				mockRepo.On("UpdateStatus", 123, 2, "completed").Return(nil)
+				mockRepo.On("GetByID", 123, 2).Return(&task.Task{ID: 2, UserID: 123, Status: "completed"}, nil).Twice()
 				return mockRepo
 			},
 			wantErr: false,
@@ -282,6 +422,8 @@ func TestTaskService_UpdateStatus(t *testing.T) {
 			status: "pending",
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("GetByID", 123, 4).Return(&task.Task{ID: 4, UserID: 123, Status: "completed"}, nil)
 				mockRepo.On("UpdateStatus", 123, 4, "pending").Return(errors.New("db error"))
 				return mockRepo
 			},
@@ -294,12 +436,14 @@ func TestTaskService_UpdateStatus(t *testing.T) {
 			mockRepo := tt.setupMock()
 			s := NewTaskService(mockRepo)
 
-			gotErr := s.UpdateStatus(tt.userID, tt.id, tt.status)
+			got, gotErr := s.UpdateStatus(tt.userID, tt.id, tt.status)
 
 			if tt.wantErr {
 				assert.Error(t, gotErr)
 			} else {
 				assert.NoError(t, gotErr)
+				assert.Equal(t, tt.id, got.ID)
+				assert.Equal(t, tt.status, got.Status)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -307,6 +451,329 @@ func TestTaskService_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestTaskService_UpdateStatus_WIPLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		status    string
+		setupMock func(repo *gorm_task.TaskRepoMock, limits *gorm_board.BoardRepoMock)
+		wantErr   error
+	}{
+		{
+			name:    "column has room",
+			current: "pending",
+			status:  "in-progress",
+			setupMock: func(repo *gorm_task.TaskRepoMock, limits *gorm_board.BoardRepoMock) {
+				limits.On("WIPLimit", 1, (*int)(nil), "in-progress").Return(3, nil)
+				repo.On("LockColumn", 1, (*int)(nil), "in-progress").Return(3, nil)
+				repo.On("CountColumn", 1, (*int)(nil), "in-progress").Return(int64(2), nil)
+				repo.On("UpdateStatus", 1, 5, "in-progress").Return(nil)
+			},
+		},
+		{
+			name:    "column is full",
+			current: "pending",
+			status:  "in-progress",
+			setupMock: func(repo *gorm_task.TaskRepoMock, limits *gorm_board.BoardRepoMock) {
+				limits.On("WIPLimit", 1, (*int)(nil), "in-progress").Return(3, nil)
+				repo.On("LockColumn", 1, (*int)(nil), "in-progress").Return(3, nil)
+				repo.On("CountColumn", 1, (*int)(nil), "in-progress").Return(int64(3), nil)
+			},
+			wantErr: ErrWIPLimitReached,
+		},
+		{
+			name:    "limit removed before the lock",
+			current: "pending",
+			status:  "in-progress",
+			setupMock: func(repo *gorm_task.TaskRepoMock, limits *gorm_board.BoardRepoMock) {
+				limits.On("WIPLimit", 1, (*int)(nil), "in-progress").Return(3, nil)
+				repo.On("LockColumn", 1, (*int)(nil), "in-progress").Return(0, nil)
+				repo.On("UpdateStatus", 1, 5, "in-progress").Return(nil)
+			},
+		},
+		{
+			name:    "column without a limit",
+			current: "pending",
+			status:  "completed",
+			setupMock: func(repo *gorm_task.TaskRepoMock, limits *gorm_board.BoardRepoMock) {
+				limits.On("WIPLimit", 1, (*int)(nil), "completed").Return(0, nil)
+				repo.On("UpdateStatus", 1, 5, "completed").Return(nil)
+			},
+		},
+		{
+			name:    "staying in a full column",
+			current: "in-progress",
+			status:  "in-progress",
+			setupMock: func(repo *gorm_task.TaskRepoMock, limits *gorm_board.BoardRepoMock) {
+				repo.On("UpdateStatus", 1, 5, "in-progress").Return(nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(gorm_task.TaskRepoMock)
+			limits := new(gorm_board.BoardRepoMock)
+			repo.On("Transaction", mock.Anything).Return(nil)
+			repo.On("GetByID", 1, 5).Return(&task.Task{ID: 5, UserID: 1, Status: tt.current}, nil)
+			repo.On("AddEvents", mock.Anything).Return(nil).Maybe()
+			tt.setupMock(repo, limits)
+
+			s := NewTaskService(repo, WithWIPLimits(limits))
+			_, err := s.UpdateStatus(1, 5, tt.status)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			repo.AssertExpectations(t)
+			limits.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTaskService_NotifySubtasksDone(t *testing.T) {
+	parentID := 1
+	tests := []struct {
+		name       string
+		status     string
+		parentID   *int
+		open       int64
+		parent     string
+		wantNotify bool
+	}{
+		{name: "last subtask completed", status: "completed", parentID: &parentID, parent: "pending", wantNotify: true},
+		{name: "other subtasks still open", status: "completed", parentID: &parentID, open: 1, parent: "pending"},
+		{name: "parent already completed", status: "completed", parentID: &parentID, parent: "completed"},
+		{name: "subtask reopened", status: "pending", parentID: &parentID, parent: "pending"},
+		{name: "top-level task completed", status: "completed", parent: "pending"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, err := events.NewRecord(events.StatusChanged{
+				UserID: 1,
+				Task:   dto.GetTaskResponse{ID: 5, Status: tt.status, ParentID: tt.parentID},
+				From:   "in-progress",
+			}, time.Now())
+			assert.NoError(t, err)
+			e := rec.Envelope()
+
+			repo := new(gorm_task.TaskRepoMock)
+			repo.On("Count", 1, mock.Anything).Return(tt.open, nil)
+			repo.On("GetByID", 1, 1).Return(&task.Task{ID: 1, UserID: 1, Task: "Release 2.4", Status: tt.parent}, nil)
+
+			publisher := new(notify.PublisherMock)
+			publisher.On("Publish", notify.Message{
+				UserID: 1,
+				Type:   notification.TypeSubtasksDone,
+				Title:  "Subtasks done: Release 2.4",
+				Body:   `All subtasks of "Release 2.4" are completed.`,
+				TaskID: &parentID,
+				Key:    "subtasks-done:1:5",
+			}).Return(nil)
+
+			s := NewTaskService(repo, WithPublisher(publisher))
+			assert.NoError(t, s.NotifySubtasksDone(context.Background(), &e))
+			// Redelivered events publish the same key, which the inbox
+			// stores only once.
+
+			if tt.wantNotify {
+				publisher.AssertNumberOfCalls(t, "Publish", 1)
+			} else {
+				publisher.AssertNotCalled(t, "Publish", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestTaskService_Move_ChangesColumn(t *testing.T) {
+	repo := new(gorm_task.TaskRepoMock)
+	limits := new(gorm_board.BoardRepoMock)
+
+	repo.On("Transaction", mock.Anything).Return(nil)
+	repo.On("GetByID", 1, 3).Return(&task.Task{ID: 3, Status: "pending", Position: "c"}, nil)
+	repo.On("GetByID", 1, 1).Return(&task.Task{ID: 1, Status: "in-progress", Position: "a"}, nil)
+	limits.On("WIPLimit", 1, (*int)(nil), "in-progress").Return(2, nil)
+	repo.On("LockColumn", 1, (*int)(nil), "in-progress").Return(2, nil)
+	repo.On("CountColumn", 1, (*int)(nil), "in-progress").Return(int64(1), nil)
+	repo.On("UpdateStatus", 1, 3, "in-progress").Return(nil)
+	repo.On("ListScope", 1, (*int)(nil)).Return([]task.Task{{ID: 1, Position: "a"}, {ID: 2, Position: "b"}, {ID: 3, Position: "c"}}, nil)
+	repo.On("SetPosition", 1, 3, (*int)(nil), "ai").Return(nil)
+	repo.On("AddEvents", mock.MatchedBy(func(evs []events.Event) bool {
+		return len(evs) == 2 &&
+			evs[0].EventType() == events.TypeTaskMoved &&
+			evs[1].(events.StatusChanged).From == "pending"
+	})).Return(nil)
+
+	s := NewTaskService(repo, WithWIPLimits(limits))
+	_, err := s.Move(1, 3, &dto.MoveTaskRequest{AfterID: 1, Status: "in-progress"})
+
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	limits.AssertExpectations(t)
+}
+
+func TestTaskService_Restore(t *testing.T) {
+	tests := []struct {
+		name      string
+		setupMock func() *gorm_task.TaskRepoMock
+		wantErr   error
+	}{
+		{
+			name: "success",
+			setupMock: func() *gorm_task.TaskRepoMock {
+				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("RestoreBatch", 1, []int{5}).Return(int64(1), nil)
+				mockRepo.On("GetByID", 1, 5).Return(&task.Task{ID: 5, UserID: 1, Task: "Buy milk", Status: "pending"}, nil)
+				mockRepo.On("AddEvents", []events.Event{events.TaskRestored{
+					UserID: 1,
+					Task:   dto.GetTaskResponse{ID: 5, Task: "Buy milk", Status: "pending"},
+				}}).Return(nil)
+				return mockRepo
+			},
+		},
+		{
+			name: "success - not in the trash records no event",
+			setupMock: func() *gorm_task.TaskRepoMock {
+				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("RestoreBatch", 1, []int{5}).Return(int64(0), nil)
+				mockRepo.On("GetByID", 1, 5).Return(&task.Task{ID: 5, UserID: 1, Task: "Buy milk", Status: "pending"}, nil)
+				return mockRepo
+			},
+		},
+		{
+			name: "failure - not found or not owned",
+			setupMock: func() *gorm_task.TaskRepoMock {
+				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("RestoreBatch", 1, []int{5}).Return(int64(0), nil)
+				mockRepo.On("GetByID", 1, 5).Return((*task.Task)(nil), gorm.ErrRecordNotFound)
+				return mockRepo
+			},
+			wantErr: gorm.ErrRecordNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := tt.setupMock()
+			s := NewTaskService(mockRepo)
+
+			got, err := s.Restore(1, 5)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, dto.GetTaskResponse{ID: 5, Task: "Buy milk", Status: "pending"}, got)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTaskService_Move(t *testing.T) {
+	project := 4
+	list := func(positions ...string) []task.Task {
+		tasks := make([]task.Task, len(positions))
+		for i, p := range positions {
+			tasks[i] = task.Task{ID: i + 1, Position: p}
+		}
+		return tasks
+	}
+
+	tests := []struct {
+		name      string
+		req       dto.MoveTaskRequest
+		setupMock func(m *gorm_task.TaskRepoMock)
+		noRepo    bool
+		wantErr   error
+	}{
+		{
+			name: "between adjacent tasks",
+			req:  dto.MoveTaskRequest{AfterID: 1, BeforeID: 2},
+			setupMock: func(m *gorm_task.TaskRepoMock) {
+				m.On("GetByID", 1, 1).Return(&task.Task{ID: 1}, nil)
+				m.On("GetByID", 1, 2).Return(&task.Task{ID: 2}, nil)
+				m.On("ListScope", 1, (*int)(nil)).Return(list("a", "b", "c"), nil)
+				m.On("SetPosition", 1, 3, (*int)(nil), "ai").Return(nil)
+			},
+		},
+		{
+			name: "to the end of another project",
+			req:  dto.MoveTaskRequest{AfterID: 2},
+			setupMock: func(m *gorm_task.TaskRepoMock) {
+				m.On("GetByID", 1, 2).Return(&task.Task{ID: 2, ProjectID: &project}, nil)
+				m.On("ListScope", 1, &project).Return(list("a", "b"), nil)
+				m.On("SetPosition", 1, 3, &project, "o").Return(nil)
+			},
+		},
+		{
+			name: "missing keys rebalance the list",
+			req:  dto.MoveTaskRequest{BeforeID: 1},
+			setupMock: func(m *gorm_task.TaskRepoMock) {
+				m.On("GetByID", 1, 1).Return(&task.Task{ID: 1}, nil)
+				m.On("ListScope", 1, (*int)(nil)).Return(list("", "", ""), nil)
+				keys := lexorank.Spread(3)
+				m.On("SetPosition", 1, 3, (*int)(nil), keys[0]).Return(nil)
+				m.On("SetPosition", 1, 1, (*int)(nil), keys[1]).Return(nil)
+				m.On("SetPosition", 1, 2, (*int)(nil), keys[2]).Return(nil)
+			},
+		},
+		{
+			name: "failure - anchors in different lists",
+			req:  dto.MoveTaskRequest{AfterID: 1, BeforeID: 2},
+			setupMock: func(m *gorm_task.TaskRepoMock) {
+				m.On("GetByID", 1, 1).Return(&task.Task{ID: 1}, nil)
+				m.On("GetByID", 1, 2).Return(&task.Task{ID: 2, ProjectID: &project}, nil)
+			},
+			wantErr: ErrInvalidAnchor,
+		},
+		{
+			name: "failure - anchors out of order",
+			req:  dto.MoveTaskRequest{AfterID: 2, BeforeID: 1},
+			setupMock: func(m *gorm_task.TaskRepoMock) {
+				m.On("GetByID", 1, 1).Return(&task.Task{ID: 1}, nil)
+				m.On("GetByID", 1, 2).Return(&task.Task{ID: 2}, nil)
+				m.On("ListScope", 1, (*int)(nil)).Return(list("a", "b", "c"), nil)
+			},
+			wantErr: ErrInvalidAnchor,
+		},
+		{
+			name:      "failure - anchored to itself",
+			req:       dto.MoveTaskRequest{AfterID: 3},
+			setupMock: func(m *gorm_task.TaskRepoMock) {},
+			noRepo:    true,
+			wantErr:   ErrInvalidAnchor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(gorm_task.TaskRepoMock)
+			if !tt.noRepo {
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
+				mockRepo.On("GetByID", 1, 3).Return(&task.Task{ID: 3, Position: "c"}, nil)
+				if tt.wantErr == nil {
+					mockRepo.On("AddEvents", []events.Event{events.TaskMoved{UserID: 1, Task: dto.GetTaskResponse{ID: 3, Position: "c"}}}).Return(nil)
+				}
+			}
+			tt.setupMock(mockRepo)
+			s := NewTaskService(mockRepo)
+
+			_, err := s.Move(1, 3, &tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestTaskService_Delete(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -321,7 +788,13 @@ func TestTaskService_Delete(t *testing.T) {
 			id:     1,
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("GetByID", 1, 1).Return(&task.Task{ID: 1, UserID: 1, Task: "Pay rent"}, nil)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
 				mockRepo.On("Delete", 1, 1).Return(nil)
+				mockRepo.On("AddEvents", []events.Event{events.TaskDeleted{
+					UserID: 1,
+					Task:   dto.GetTaskResponse{ID: 1, Task: "Pay rent"},
+				}}).Return(nil)
 				return mockRepo
 			},
 			wantErr: false,
@@ -332,6 +805,8 @@ func TestTaskService_Delete(t *testing.T) {
 			id:     2,
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
+				mockRepo.On("GetByID", 1, 2).Return(&task.Task{ID: 2, UserID: 1}, nil)
+				mockRepo.On("Transaction", mock.Anything).Return(nil)
 				mockRepo.On("Delete", 1, 2).Return(errors.New("db error"))
 				return mockRepo
 			},
@@ -343,7 +818,7 @@ func TestTaskService_Delete(t *testing.T) {
 			id:     3,
 			setupMock: func() *gorm_task.TaskRepoMock {
 				mockRepo := new(gorm_task.TaskRepoMock)
-				mockRepo.On("Delete", 1, 3).Return(errors.New("not found"))
+				mockRepo.On("GetByID", 1, 3).Return((*task.Task)(nil), gorm.ErrRecordNotFound)
 				return mockRepo
 			},
 			wantErr: true,
@@ -367,3 +842,345 @@ func TestTaskService_Delete(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskService_Delete_KeepsAttachments(t *testing.T) {
+	mockRepo := new(gorm_task.TaskRepoMock)
+	mockRepo.On("GetByID", 1, 5).Return(&task.Task{ID: 5, UserID: 1}, nil)
+	mockRepo.On("Transaction", mock.Anything).Return(nil)
+	mockRepo.On("Delete", 1, 5).Return(nil)
+	mockRepo.On("AddEvents", mock.Anything).Return(nil)
+	cleaner := new(attachment_service.AttachmentServiceMock)
+
+	s := NewTaskService(mockRepo, WithAttachmentCleaner(cleaner))
+	assert.NoError(t, s.Delete(1, 5))
+
+	// A trashed task can be restored, so its files stay.
+	cleaner.AssertNotCalled(t, "StorageKeys", mock.Anything)
+	cleaner.AssertNotCalled(t, "DeleteBlobs", mock.Anything)
+}
+
+func TestTaskService_EmptyTrash(t *testing.T) {
+	t.Run("purges trashed tasks and their attachments", func(t *testing.T) {
+		mockRepo := new(gorm_task.TaskRepoMock)
+		mockRepo.On("Transaction", mock.Anything).Return(nil)
+		mockRepo.On("TrashedIDs", 1, mock.Anything).Return([]int{4, 5}, nil)
+		mockRepo.On("Purge", 1, []int{4, 5}).Return(int64(2), nil)
+		cleaner := new(attachment_service.AttachmentServiceMock)
+		cleaner.On("StorageKeys", []int{4, 5}).Return([]string{"tasks/5/a"}, nil)
+		cleaner.On("DeleteBlobs", []string{"tasks/5/a"}).Return()
+
+		s := NewTaskService(mockRepo, WithAttachmentCleaner(cleaner))
+		resp, err := s.EmptyTrash(1)
+		require.NoError(t, err)
+		assert.Equal(t, dto.EmptyTrashResponse{Deleted: 2}, resp)
+		mockRepo.AssertExpectations(t)
+		cleaner.AssertExpectations(t)
+	})
+
+	t.Run("keeps files when the purge fails", func(t *testing.T) {
+		mockRepo := new(gorm_task.TaskRepoMock)
+		mockRepo.On("Transaction", mock.Anything).Return(nil)
+		mockRepo.On("TrashedIDs", 1, mock.Anything).Return([]int{5}, nil)
+		mockRepo.On("Purge", 1, []int{5}).Return(int64(0), errors.New("database error"))
+		cleaner := new(attachment_service.AttachmentServiceMock)
+		cleaner.On("StorageKeys", []int{5}).Return([]string{"tasks/5/a"}, nil)
+
+		s := NewTaskService(mockRepo, WithAttachmentCleaner(cleaner))
+		_, err := s.EmptyTrash(1)
+		assert.Error(t, err)
+		cleaner.AssertNotCalled(t, "DeleteBlobs", mock.Anything)
+	})
+
+	t.Run("empty trash", func(t *testing.T) {
+		mockRepo := new(gorm_task.TaskRepoMock)
+		mockRepo.On("Transaction", mock.Anything).Return(nil)
+		mockRepo.On("TrashedIDs", 1, mock.Anything).Return([]int{}, nil)
+		mockRepo.On("Purge", 1, []int{}).Return(int64(0), nil)
+
+		resp, err := NewTaskService(mockRepo).EmptyTrash(1)
+		require.NoError(t, err)
+		assert.Zero(t, resp.Deleted)
+	})
+}
+
+func TestTaskService_PurgeTrash(t *testing.T) {
+	now := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	before := now.Add(-TrashRetention)
+	mockRepo := new(gorm_task.TaskRepoMock)
+	mockRepo.On("TrashOwners", before).Return([]int{1, 2}, nil)
+	mockRepo.On("Transaction", mock.Anything).Return(nil)
+	mockRepo.On("TrashedIDs", 1, before).Return([]int{4}, nil)
+	mockRepo.On("Purge", 1, []int{4}).Return(int64(1), nil)
+	mockRepo.On("TrashedIDs", 2, before).Return([]int{7}, nil)
+	mockRepo.On("Purge", 2, []int{7}).Return(int64(1), nil)
+	cleaner := new(attachment_service.AttachmentServiceMock)
+	cleaner.On("StorageKeys", []int{4}).Return([]string{"tasks/4/a"}, nil)
+	cleaner.On("DeleteBlobs", []string{"tasks/4/a"}).Return()
+	cleaner.On("StorageKeys", []int{7}).Return([]string{}, nil)
+
+	s := NewTaskService(mockRepo, WithAttachmentCleaner(cleaner))
+	s.now = func() time.Time { return now }
+	require.NoError(t, s.PurgeTrash(context.Background(), &scheduler.Job{}))
+	mockRepo.AssertExpectations(t)
+	cleaner.AssertExpectations(t)
+}
+
+func TestTaskService_QuickAdd(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 9, 3, 22, 30, 0, 0, time.UTC) // already 4 September in Berlin
+	due := time.Date(2025, 9, 5, 9, 0, 0, 0, berlin)
+
+	newService := func(repo *gorm_task.TaskRepoMock) *TaskService {
+		users := new(gorm_user.MockUserRepository)
+		users.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "Europe/Berlin"}, nil)
+		s := NewTaskService(repo, WithUsers(users))
+		s.now = func() time.Time { return now }
+		return s
+	}
+
+	t.Run("creates the task", func(t *testing.T) {
+		repo := new(gorm_task.TaskRepoMock)
+		repo.On("LastPosition", 1, (*int)(nil)).Return("i", nil)
+		repo.On("Transaction", mock.Anything).Return(nil)
+		repo.On("AddEvents", mock.Anything).Return(nil)
+		repo.On("Create", mock.MatchedBy(func(tk *task.Task) bool {
+			return tk.UserID == 1 && tk.Task == "Pay rent" && tk.Status == "pending" &&
+				tk.Priority == task.PriorityHigh && tk.DueAt != nil && tk.DueAt.Equal(due) &&
+				len(tk.Tags) == 1 && tk.Tags[0].Name == "finance" &&
+				tk.Recurrence == "FREQ=MONTHLY" && tk.Position == "r"
+		})).Run(func(args mock.Arguments) { args.Get(0).(*task.Task).ID = 9 }).Return(nil)
+
+		got, err := newService(repo).QuickAdd(1, &dto.QuickAddRequest{Text: "Pay rent tomorrow 9am #Finance !high every month"})
+		assert.NoError(t, err)
+
+		if assert.NotNil(t, got.Task) {
+			assert.Equal(t, 9, got.Task.ID)
+			assert.Equal(t, "FREQ=MONTHLY", got.Task.Recurrence)
+		}
+		assert.Equal(t, "Pay rent", got.Parsed.Task)
+		assert.Equal(t, []string{"finance"}, got.Parsed.Tags)
+		assert.Equal(t, "Europe/Berlin", got.TimeZone)
+		assert.Equal(t, dto.QuickAddToken{Text: "tomorrow", Kind: "date", Value: "2025-09-05"}, got.Tokens[0])
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		repo := new(gorm_task.TaskRepoMock)
+
+		got, err := newService(repo).QuickAdd(1, &dto.QuickAddRequest{Text: "Pay rent tomorrow 9am", DryRun: true})
+		assert.NoError(t, err)
+		assert.Nil(t, got.Task)
+		if assert.NotNil(t, got.Parsed.DueAt) {
+			assert.True(t, due.Equal(*got.Parsed.DueAt))
+		}
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("title too long", func(t *testing.T) {
+		_, err := newService(new(gorm_task.TaskRepoMock)).QuickAdd(1, &dto.QuickAddRequest{Text: "Renew the passport and the ID card tomorrow"})
+		assert.ErrorIs(t, err, ErrTaskTooLong)
+	})
+
+	t.Run("nothing left for the title", func(t *testing.T) {
+		_, err := newService(new(gorm_task.TaskRepoMock)).QuickAdd(1, &dto.QuickAddRequest{Text: "tomorrow #work"})
+		assert.Error(t, err)
+	})
+
+	t.Run("without a user lookup dates are UTC", func(t *testing.T) {
+		s := NewTaskService(new(gorm_task.TaskRepoMock))
+		s.now = func() time.Time { return now }
+
+		got, err := s.QuickAdd(1, &dto.QuickAddRequest{Text: "Pay rent tomorrow", DryRun: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "UTC", got.TimeZone)
+		assert.Equal(t, "2025-09-04", got.Parsed.DueAt.Format("2006-01-02"))
+	})
+}
+
+func TestTaskService_Import(t *testing.T) {
+	now := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	project := 2
+	parent := 5
+
+	t.Run("creates all tasks", func(t *testing.T) {
+		repo := new(gorm_task.TaskRepoMock)
+		repo.On("Transaction", mock.Anything).Return(nil)
+		repo.On("LastPosition", 1, (*int)(nil)).Return("i", nil)
+		repo.On("LastPosition", 1, &project).Return("", nil)
+		repo.On("AddEvents", mock.Anything).Return(nil)
+		var created []task.Task
+		repo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			tk := args.Get(0).(*task.Task)
+			tk.ID = len(created) + 1
+			created = append(created, *tk)
+		}).Return(nil)
+
+		s := NewTaskService(repo)
+		s.now = func() time.Time { return now }
+		got, err := s.Import(1, []dto.ImportTaskRequest{
+			{CreateTaskRequest: dto.CreateTaskRequest{Task: "Pay rent", Priority: "high", Tags: []string{"Finance"}}, Recurrence: "freq=monthly"},
+			{CreateTaskRequest: dto.CreateTaskRequest{Task: "File taxes"}, Status: "completed"},
+			{CreateTaskRequest: dto.CreateTaskRequest{Task: "Plan trip"}, ProjectID: &project, ParentID: &parent, ExternalID: "todoist:7"},
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Len(t, created, 3)
+
+		assert.Equal(t, "r", created[0].Position)
+		assert.Equal(t, "FREQ=MONTHLY", created[0].Recurrence)
+		assert.Equal(t, []task.Tag{{Name: "finance"}}, created[0].Tags)
+		assert.Equal(t, "pending", created[0].Status)
+		assert.True(t, created[1].Position > created[0].Position, "tasks keep their order")
+		assert.Equal(t, "completed", created[1].Status)
+		assert.Equal(t, &now, created[1].CompletedAt)
+		assert.Equal(t, "i", created[2].Position, "positions are per list")
+		assert.Equal(t, &parent, created[2].ParentID)
+		assert.Equal(t, "todoist:7", *created[2].ExternalID)
+		assert.Nil(t, created[0].ExternalID)
+		assert.Equal(t, 3, got[2].ID)
+		repo.AssertNumberOfCalls(t, "AddEvents", 3)
+		repo.AssertNumberOfCalls(t, "LastPosition", 2)
+	})
+
+	t.Run("rejects all when one is invalid", func(t *testing.T) {
+		repo := new(gorm_task.TaskRepoMock)
+		_, err := NewTaskService(repo).Import(1, []dto.ImportTaskRequest{
+			{CreateTaskRequest: dto.CreateTaskRequest{Task: "Fine"}},
+			{CreateTaskRequest: dto.CreateTaskRequest{Task: "Fine", Priority: "asap"}},
+		})
+		var importErr *ImportError
+		require.ErrorAs(t, err, &importErr)
+		assert.Equal(t, 1, importErr.Index)
+		assert.EqualError(t, err, `task 2: invalid priority "asap"`)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("enforces WIP limits", func(t *testing.T) {
+		repo := new(gorm_task.TaskRepoMock)
+		limits := new(gorm_board.BoardRepoMock)
+		repo.On("Transaction", mock.Anything).Return(nil)
+		repo.On("LastPosition", 1, (*int)(nil)).Return("", nil)
+		repo.On("AddEvents", mock.Anything).Return(nil)
+		repo.On("Create", mock.Anything).Return(nil)
+		limits.On("WIPLimit", 1, (*int)(nil), "pending").Return(0, nil)
+		limits.On("WIPLimit", 1, (*int)(nil), "in-progress").Return(1, nil)
+		repo.On("LockColumn", 1, (*int)(nil), "in-progress").Return(1, nil)
+		// The first task fills the column; the second finds it full.
+		repo.On("CountColumn", 1, (*int)(nil), "in-progress").Return(int64(0), nil).Once()
+		repo.On("CountColumn", 1, (*int)(nil), "in-progress").Return(int64(1), nil).Once()
+
+		_, err := NewTaskService(repo, WithWIPLimits(limits)).Import(1, []dto.ImportTaskRequest{
+			{CreateTaskRequest: dto.CreateTaskRequest{Task: "Started"}, Status: "in-progress"},
+			{CreateTaskRequest: dto.CreateTaskRequest{Task: "Open"}},
+			{CreateTaskRequest: dto.CreateTaskRequest{Task: "Also started"}, Status: "in-progress"},
+		})
+		var importErr *ImportError
+		require.ErrorAs(t, err, &importErr)
+		assert.Equal(t, 2, importErr.Index)
+		assert.ErrorIs(t, err, ErrWIPLimitReached)
+		repo.AssertNumberOfCalls(t, "Create", 2)
+	})
+}
+
+func TestTaskService_Replace(t *testing.T) {
+	now := time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+	project := 2
+	estimate := 30
+	due := now.Add(24 * time.Hour)
+
+	t.Run("overwrites fields and keeps the list", func(t *testing.T) {
+		repo := new(gorm_task.TaskRepoMock)
+		repo.On("Transaction", mock.Anything).Return(nil)
+		repo.On("GetByID", 1, 7).Return(&task.Task{
+			ID: 7, UserID: 1, Task: "Old", Description: "old", Status: "pending", Priority: task.PriorityHigh,
+			ProjectID: &project, Position: "k", EstimateMinutes: &estimate, Tags: []task.Tag{{TaskID: 7, Name: "old"}},
+		}, nil)
+		var saved task.Task
+		repo.On("Update", mock.Anything).Run(func(args mock.Arguments) {
+			saved = *args.Get(0).(*task.Task)
+		}).Return(nil)
+		repo.On("SetTags", 7, []string{"home"}).Return(nil)
+		repo.On("AddEvents", mock.Anything).Return(nil)
+
+		s := NewTaskService(repo)
+		s.now = func() time.Time { return now }
+		got, err := s.Replace(1, 7, &dto.ImportTaskRequest{
+			CreateTaskRequest: dto.CreateTaskRequest{Task: "New", DueAt: &due, Tags: []string{"Home"}},
+			Status:            "completed",
+			ProjectID:         new(int),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "New", saved.Task)
+		assert.Equal(t, "", saved.Description)
+		assert.Equal(t, task.PriorityNone, saved.Priority)
+		assert.Equal(t, &due, saved.DueAt)
+		assert.Equal(t, &now, saved.CompletedAt)
+		assert.Nil(t, saved.Tags, "tags are set separately")
+		assert.Equal(t, &project, saved.ProjectID)
+		assert.Equal(t, "k", saved.Position)
+		assert.Equal(t, &estimate, saved.EstimateMinutes)
+		assert.Equal(t, []string{"home"}, got.Tags)
+		repo.AssertCalled(t, "AddEvents", []events.Event{events.StatusChanged{UserID: 1, Task: got, From: "pending"}})
+	})
+
+	t.Run("keeps the completion time", func(t *testing.T) {
+		done := now.Add(-time.Hour)
+		repo := new(gorm_task.TaskRepoMock)
+		repo.On("Transaction", mock.Anything).Return(nil)
+		repo.On("GetByID", 1, 7).Return(&task.Task{ID: 7, UserID: 1, Task: "Old", Status: "completed", CompletedAt: &done}, nil)
+		repo.On("Update", mock.Anything).Return(nil)
+		repo.On("SetTags", 7, []string{}).Return(nil)
+
+		got, err := NewTaskService(repo).Replace(1, 7, &dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: "Old"}, Status: "completed"})
+		require.NoError(t, err)
+		assert.Equal(t, &done, got.CompletedAt)
+		repo.AssertNotCalled(t, "AddEvents", mock.Anything)
+	})
+
+	t.Run("validates", func(t *testing.T) {
+		repo := new(gorm_task.TaskRepoMock)
+		_, err := NewTaskService(repo).Replace(1, 7, &dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: " "}})
+		assert.ErrorIs(t, err, ErrEmptyTaskName)
+		repo.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+}
+
+func TestValidateImport(t *testing.T) {
+	tooLong := strings.Repeat("x", 21)
+	zero := 0
+	for _, tt := range []struct {
+		req  dto.ImportTaskRequest
+		want error
+	}{
+		{dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: "  "}}, ErrEmptyTaskName},
+		{dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: tooLong}}, ErrTaskTooLong},
+		{dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: "x", Description: strings.Repeat("d", 2001)}}, ErrDescriptionTooLong},
+		{dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: "x", Tags: []string{strings.Repeat("t", 51)}}}, ErrInvalidTags},
+		{dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: "x", EstimateMinutes: &zero}}, ErrInvalidEstimate},
+		{dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: "x"}, Status: "done"}, ErrInvalidStatus},
+		{dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: "x"}, Recurrence: "every day"}, ErrInvalidRecurrence},
+		{dto.ImportTaskRequest{CreateTaskRequest: dto.CreateTaskRequest{Task: "x"}, Status: "in-progress", Recurrence: "FREQ=DAILY"}, nil},
+	} {
+		err := ValidateImport(&tt.req)
+		if tt.want == nil {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorIs(t, err, tt.want)
+		}
+	}
+}
+
+func TestFitTitle(t *testing.T) {
+	title, description := FitTitle("Buy  milk", "Semi-skimmed")
+	assert.Equal(t, "Buy milk", title)
+	assert.Equal(t, "Semi-skimmed", description)
+
+	title, description = FitTitle("Renew the car insurance before it lapses", "Compare quotes")
+	assert.Equal(t, "Renew the car insur…", title)
+	assert.Equal(t, "Renew the car insurance before it lapses\n\nCompare quotes", description)
+	assert.Equal(t, MaxTaskLength, utf8.RuneCountInString(title))
+}
@@ -1,13 +1,27 @@
 package task_service
 
 import (
+	"context"
 	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/scheduler"
 )
 
 type TaskServiceInterface interface {
-	CreateTask(userID int, taskRequest *dto.CreateTaskRequest) error
+	CreateTask(userID int, taskRequest *dto.CreateTaskRequest) (dto.GetTaskResponse, error)
 	GetTask(userID int, id int) (dto.GetTaskResponse, error)
 	ListTasks(userID int) (dto.ListTasksResponse, error)
-	UpdateStatus(userID int, id int, status string) error
+	FilterTasks(userID int, query string) (dto.ListTasksResponse, error)
+	UpdateStatus(userID int, id int, status string) (dto.GetTaskResponse, error)
+	Restore(userID int, id int) (dto.GetTaskResponse, error)
+	Move(userID int, id int, req *dto.MoveTaskRequest) (dto.GetTaskResponse, error)
 	Delete(userID int, id int) error
+	EmptyTrash(userID int) (dto.EmptyTrashResponse, error)
+	// PurgeTrash handles KindPurgeTrash jobs.
+	PurgeTrash(ctx context.Context, job *scheduler.Job) error
+	QuickAdd(userID int, req *dto.QuickAddRequest) (dto.QuickAddResponse, error)
+	Import(userID int, reqs []dto.ImportTaskRequest) ([]dto.GetTaskResponse, error)
+	Replace(userID int, id int, req *dto.ImportTaskRequest) (dto.GetTaskResponse, error)
+	// NotifySubtasksDone consumes StatusChanged events.
+	NotifySubtasksDone(ctx context.Context, e *events.Envelope) error
 }
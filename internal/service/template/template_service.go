@@ -0,0 +1,385 @@
+package template_service
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/template"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_template"
+	task_service "taskflow/internal/service/task"
+	"taskflow/pkg/lexorank"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidUser     = errors.New("invalid user")
+	ErrEmptyName       = errors.New("template name cannot be empty")
+	ErrEmptyTitle      = errors.New("title cannot be empty")
+	ErrInvalidBaseDate = errors.New("base_date must be a date in YYYY-MM-DD format")
+	ErrUnknownProject  = errors.New("project not found")
+)
+
+type TemplateService struct {
+	repo     gorm_template.TemplateRepositoryInterface
+	taskRepo gorm_task.TaskRepositoryInterface
+	projects gorm_project.ProjectRepositoryInterface
+	users    task_service.UserLookup
+	now      func() time.Time
+}
+
+// NewTemplateService creates a TemplateService. users resolves the time zone
+// that base dates are read in; when it is nil they are UTC days.
+func NewTemplateService(
+	repo gorm_template.TemplateRepositoryInterface,
+	taskRepo gorm_task.TaskRepositoryInterface,
+	projects gorm_project.ProjectRepositoryInterface,
+	users task_service.UserLookup,
+) *TemplateService {
+	return &TemplateService{repo: repo, taskRepo: taskRepo, projects: projects, users: users, now: time.Now}
+}
+
+var _ TemplateServiceInterface = (*TemplateService)(nil)
+
+func (s *TemplateService) CreateTemplate(userID int, req *dto.TemplateRequest) (dto.TemplateResponse, error) {
+	if userID == 0 {
+		return dto.TemplateResponse{}, ErrInvalidUser
+	}
+	t, err := fromRequest(req)
+	if err != nil {
+		return dto.TemplateResponse{}, err
+	}
+
+	t.UserID = userID
+	if err := s.repo.Create(&t); err != nil {
+		return dto.TemplateResponse{}, err
+	}
+	return toTemplateResponse(t), nil
+}
+
+func (s *TemplateService) GetTemplate(userID int, id int) (dto.TemplateResponse, error) {
+	if userID == 0 {
+		return dto.TemplateResponse{}, ErrInvalidUser
+	}
+	t, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return dto.TemplateResponse{}, err
+	}
+	return toTemplateResponse(*t), nil
+}
+
+func (s *TemplateService) ListTemplates(userID int) (dto.ListTemplatesResponse, error) {
+	if userID == 0 {
+		return dto.ListTemplatesResponse{}, ErrInvalidUser
+	}
+
+	templates, err := s.repo.List(userID)
+	if err != nil {
+		return dto.ListTemplatesResponse{}, err
+	}
+
+	resp := dto.ListTemplatesResponse{Templates: make([]dto.TemplateResponse, 0, len(templates))}
+	for _, t := range templates {
+		resp.Templates = append(resp.Templates, toTemplateResponse(t))
+	}
+	return resp, nil
+}
+
+// UpdateTemplate replaces a template's fields and subtasks. Tasks created
+// from it earlier are not changed.
+func (s *TemplateService) UpdateTemplate(userID int, id int, req *dto.TemplateRequest) (dto.TemplateResponse, error) {
+	if userID == 0 {
+		return dto.TemplateResponse{}, ErrInvalidUser
+	}
+	updated, err := fromRequest(req)
+	if err != nil {
+		return dto.TemplateResponse{}, err
+	}
+
+	t, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return dto.TemplateResponse{}, err
+	}
+	updated.ID, updated.UserID, updated.CreatedAt = t.ID, t.UserID, t.CreatedAt
+	if err := s.repo.Update(&updated); err != nil {
+		return dto.TemplateResponse{}, err
+	}
+	return toTemplateResponse(updated), nil
+}
+
+func (s *TemplateService) DeleteTemplate(userID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+	return s.repo.Delete(userID, id)
+}
+
+// Instantiate creates a task with one subtask per template step, all in one
+// transaction. Variables are substituted in titles, descriptions and tags;
+// {{date}} is the base date unless the request sets it. Due offsets are
+// applied to the base date in the user's time zone.
+func (s *TemplateService) Instantiate(userID int, id int, req *dto.InstantiateTemplateRequest) (dto.InstantiateTemplateResponse, error) {
+	if userID == 0 {
+		return dto.InstantiateTemplateResponse{}, ErrInvalidUser
+	}
+
+	tpl, err := s.repo.GetByID(userID, id)
+	if err != nil {
+		return dto.InstantiateTemplateResponse{}, err
+	}
+
+	base, err := s.baseDate(userID, req.BaseDate)
+	if err != nil {
+		return dto.InstantiateTemplateResponse{}, err
+	}
+	vars := map[string]string{"date": base.Format("2006-01-02")}
+	for k, v := range req.Variables {
+		vars[k] = v
+	}
+
+	if req.ProjectID != nil {
+		if _, err := s.projects.GetByID(userID, *req.ProjectID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return dto.InstantiateTemplateResponse{}, ErrUnknownProject
+			}
+			return dto.InstantiateTemplateResponse{}, err
+		}
+	}
+
+	root, err := newTask(userID, req.ProjectID, tpl.Title, tpl.Description, tpl.Tags, tpl.DueOffset, base, vars)
+	if err != nil {
+		return dto.InstantiateTemplateResponse{}, err
+	}
+	subtasks := make([]task.Task, 0, len(tpl.Subtasks))
+	for _, st := range tpl.Subtasks {
+		t, err := newTask(userID, req.ProjectID, st.Title, st.Description, st.Tags, st.DueOffset, base, vars)
+		if err != nil {
+			return dto.InstantiateTemplateResponse{}, err
+		}
+		subtasks = append(subtasks, t)
+	}
+
+	err = s.taskRepo.Transaction(func(repo gorm_task.TaskRepositoryInterface) error {
+		last, err := repo.LastPosition(userID, req.ProjectID)
+		if err != nil {
+			return err
+		}
+		if !lexorank.Valid(last) {
+			last = ""
+		}
+
+		// The subtasks follow their parent at the end of the list.
+		if root.Position, err = lexorank.Between(last, ""); err != nil {
+			return err
+		}
+		if err := repo.Create(&root); err != nil {
+			return err
+		}
+		last = root.Position
+		for i := range subtasks {
+			subtasks[i].ParentID = &root.ID
+			if subtasks[i].Position, err = lexorank.Between(last, ""); err != nil {
+				return err
+			}
+			if err := repo.Create(&subtasks[i]); err != nil {
+				return err
+			}
+			last = subtasks[i].Position
+		}
+
+		evs := []events.Event{events.TaskCreated{UserID: userID, Task: task_service.ToTaskResponse(root)}}
+		for _, t := range subtasks {
+			evs = append(evs, events.TaskCreated{UserID: userID, Task: task_service.ToTaskResponse(t)})
+		}
+		return repo.AddEvents(evs...)
+	})
+	if err != nil {
+		return dto.InstantiateTemplateResponse{}, err
+	}
+
+	resp := dto.InstantiateTemplateResponse{
+		Task:     task_service.ToTaskResponse(root),
+		Subtasks: make([]dto.GetTaskResponse, 0, len(subtasks)),
+	}
+	for _, t := range subtasks {
+		resp.Subtasks = append(resp.Subtasks, task_service.ToTaskResponse(t))
+	}
+	return resp, nil
+}
+
+// baseDate returns midnight of the requested day, or of today, in the
+// user's time zone.
+func (s *TemplateService) baseDate(userID int, day string) (time.Time, error) {
+	loc := time.UTC
+	if s.users != nil {
+		u, err := s.users.GetByID(userID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		loc = u.Location()
+	}
+
+	if day == "" {
+		now := s.now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc), nil
+	}
+	base, err := time.ParseInLocation("2006-01-02", day, loc)
+	if err != nil {
+		return time.Time{}, ErrInvalidBaseDate
+	}
+	return base, nil
+}
+
+// newTask expands one template entry into a pending task.
+func newTask(userID int, projectID *int, title, description string, tags []string, offset string, base time.Time, vars map[string]string) (task.Task, error) {
+	title, err := template.Expand(title, vars)
+	if err != nil {
+		return task.Task{}, err
+	}
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return task.Task{}, ErrEmptyTitle
+	}
+	if utf8.RuneCountInString(title) > task_service.MaxTaskLength {
+		return task.Task{}, task_service.ErrTaskTooLong
+	}
+
+	description, err = template.Expand(description, vars)
+	if err != nil {
+		return task.Task{}, err
+	}
+
+	t := task.Task{
+		UserID:      userID,
+		ProjectID:   projectID,
+		Task:        title,
+		Description: description,
+		Status:      task.StatusPending,
+	}
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		name, err := template.Expand(tag, vars)
+		if err != nil {
+			return task.Task{}, err
+		}
+		names = append(names, name)
+	}
+	for _, name := range task_service.NormalizeTagNames(names) {
+		t.Tags = append(t.Tags, task.Tag{Name: name})
+	}
+
+	if offset != "" {
+		o, err := template.ParseOffset(offset)
+		if err != nil {
+			return task.Task{}, err
+		}
+		due := o.Apply(base)
+		t.DueAt = &due
+	}
+	return t, nil
+}
+
+// fromRequest validates a template request and converts it to an entity.
+func fromRequest(req *dto.TemplateRequest) (template.Template, error) {
+	t := template.Template{
+		Name:        strings.TrimSpace(req.Name),
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		Tags:        trimTags(req.Tags),
+		DueOffset:   strings.TrimSpace(req.DueOffset),
+	}
+	if t.Name == "" {
+		return template.Template{}, ErrEmptyName
+	}
+	if err := checkEntry(t.Title, t.DueOffset); err != nil {
+		return template.Template{}, err
+	}
+
+	for i, st := range req.Subtasks {
+		sub := template.Subtask{
+			Position:    i,
+			Title:       strings.TrimSpace(st.Title),
+			Description: st.Description,
+			Tags:        trimTags(st.Tags),
+			DueOffset:   strings.TrimSpace(st.DueOffset),
+		}
+		if err := checkEntry(sub.Title, sub.DueOffset); err != nil {
+			return template.Template{}, err
+		}
+		t.Subtasks = append(t.Subtasks, sub)
+	}
+	return t, nil
+}
+
+// checkEntry validates a title and due offset. Only the fixed text of the
+// title is checked against the task name limit, since variables are not
+// known yet.
+func checkEntry(title, offset string) error {
+	if title == "" {
+		return ErrEmptyTitle
+	}
+	blank := map[string]string{}
+	for _, name := range template.Variables(title) {
+		blank[name] = ""
+	}
+	fixed, _ := template.Expand(title, blank)
+	if utf8.RuneCountInString(strings.TrimSpace(fixed)) > task_service.MaxTaskLength {
+		return task_service.ErrTaskTooLong
+	}
+	if offset != "" {
+		if _, err := template.ParseOffset(offset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// trimTags drops blank tags. Tags are lower-cased only when a template is
+// instantiated, so variables in them keep their case.
+func trimTags(names []string) []string {
+	var tags []string
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+func toTemplateResponse(t template.Template) dto.TemplateResponse {
+	resp := dto.TemplateResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		Title:       t.Title,
+		Description: t.Description,
+		Tags:        t.Tags,
+		DueOffset:   t.DueOffset,
+		Subtasks:    make([]dto.TemplateSubtask, 0, len(t.Subtasks)),
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+
+	texts := append([]string{t.Title, t.Description}, t.Tags...)
+	for _, st := range t.Subtasks {
+		resp.Subtasks = append(resp.Subtasks, dto.TemplateSubtask{
+			Title:       st.Title,
+			Description: st.Description,
+			Tags:        st.Tags,
+			DueOffset:   st.DueOffset,
+		})
+		texts = append(texts, st.Title, st.Description)
+		texts = append(texts, st.Tags...)
+	}
+	resp.Variables = template.Variables(texts...)
+	if resp.Variables == nil {
+		resp.Variables = []string{}
+	}
+	return resp
+}
@@ -0,0 +1,12 @@
+package template_service
+
+import "taskflow/internal/dto"
+
+type TemplateServiceInterface interface {
+	CreateTemplate(userID int, req *dto.TemplateRequest) (dto.TemplateResponse, error)
+	GetTemplate(userID int, id int) (dto.TemplateResponse, error)
+	ListTemplates(userID int) (dto.ListTemplatesResponse, error)
+	UpdateTemplate(userID int, id int, req *dto.TemplateRequest) (dto.TemplateResponse, error)
+	DeleteTemplate(userID int, id int) error
+	Instantiate(userID int, id int, req *dto.InstantiateTemplateRequest) (dto.InstantiateTemplateResponse, error)
+}
@@ -0,0 +1,43 @@
+package template_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TemplateServiceMock struct {
+	mock.Mock
+}
+
+var _ TemplateServiceInterface = (*TemplateServiceMock)(nil)
+
+func (m *TemplateServiceMock) CreateTemplate(userID int, req *dto.TemplateRequest) (dto.TemplateResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.TemplateResponse), args.Error(1)
+}
+
+func (m *TemplateServiceMock) GetTemplate(userID int, id int) (dto.TemplateResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.TemplateResponse), args.Error(1)
+}
+
+func (m *TemplateServiceMock) ListTemplates(userID int) (dto.ListTemplatesResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.ListTemplatesResponse), args.Error(1)
+}
+
+func (m *TemplateServiceMock) UpdateTemplate(userID int, id int, req *dto.TemplateRequest) (dto.TemplateResponse, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(dto.TemplateResponse), args.Error(1)
+}
+
+func (m *TemplateServiceMock) DeleteTemplate(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *TemplateServiceMock) Instantiate(userID int, id int, req *dto.InstantiateTemplateRequest) (dto.InstantiateTemplateResponse, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(dto.InstantiateTemplateResponse), args.Error(1)
+}
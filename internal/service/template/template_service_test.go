@@ -0,0 +1,263 @@
+package template_service
+
+import (
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/template"
+	"taskflow/internal/domain/user"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_template"
+	"taskflow/internal/repository/gorm/gorm_user"
+	task_service "taskflow/internal/service/task"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	tests := []struct {
+		name      string
+		userID    int
+		req       dto.TemplateRequest
+		setupMock func(r *gorm_template.TemplateRepoMock)
+		wantErr   error
+	}{
+		{
+			name:   "success - subtasks keep their order",
+			userID: 1,
+			req: dto.TemplateRequest{
+				Name:  " Release prep ",
+				Title: "Release {{version}}",
+				Tags:  []string{"Release", " "},
+				Subtasks: []dto.TemplateSubtask{
+					{Title: "Freeze", DueOffset: "1d"},
+					{Title: "Tag {{version}}", DueOffset: "3d17h"},
+				},
+			},
+			setupMock: func(r *gorm_template.TemplateRepoMock) {
+				r.On("Create", mock.MatchedBy(func(tpl *template.Template) bool {
+					return tpl.UserID == 1 && tpl.Name == "Release prep" &&
+						len(tpl.Tags) == 1 && tpl.Tags[0] == "Release" &&
+						len(tpl.Subtasks) == 2 && tpl.Subtasks[1].Position == 1 && tpl.Subtasks[1].Title == "Tag {{version}}"
+				})).Return(nil)
+			},
+		},
+		{
+			name:      "failure - blank name",
+			userID:    1,
+			req:       dto.TemplateRequest{Name: "  ", Title: "Release"},
+			setupMock: func(r *gorm_template.TemplateRepoMock) {},
+			wantErr:   ErrEmptyName,
+		},
+		{
+			name:   "failure - invalid subtask offset",
+			userID: 1,
+			req: dto.TemplateRequest{
+				Name:     "Release prep",
+				Title:    "Release",
+				Subtasks: []dto.TemplateSubtask{{Title: "Freeze", DueOffset: "tomorrow"}},
+			},
+			setupMock: func(r *gorm_template.TemplateRepoMock) {},
+			wantErr:   template.ErrInvalidOffset,
+		},
+		{
+			name:      "failure - fixed text of the title is too long",
+			userID:    1,
+			req:       dto.TemplateRequest{Name: "Release prep", Title: "Prepare the quarterly release {{version}}"},
+			setupMock: func(r *gorm_template.TemplateRepoMock) {},
+			wantErr:   task_service.ErrTaskTooLong,
+		},
+		{
+			name:      "failure - invalid user",
+			req:       dto.TemplateRequest{Name: "Release prep", Title: "Release"},
+			setupMock: func(r *gorm_template.TemplateRepoMock) {},
+			wantErr:   ErrInvalidUser,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(gorm_template.TemplateRepoMock)
+			tt.setupMock(repo)
+
+			got, err := NewTemplateService(repo, nil, nil, nil).CreateTemplate(tt.userID, &tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"version"}, got.Variables)
+				assert.Len(t, got.Subtasks, 2)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	t.Run("replaces the template", func(t *testing.T) {
+		repo := new(gorm_template.TemplateRepoMock)
+		created := time.Date(2025, 9, 1, 8, 0, 0, 0, time.UTC)
+		repo.On("GetByID", 1, 4).Return(&template.Template{ID: 4, UserID: 1, Name: "Old", Title: "Old", CreatedAt: created}, nil)
+		repo.On("Update", mock.MatchedBy(func(tpl *template.Template) bool {
+			return tpl.ID == 4 && tpl.UserID == 1 && tpl.Name == "New" && len(tpl.Subtasks) == 1
+		})).Return(nil)
+
+		got, err := NewTemplateService(repo, nil, nil, nil).UpdateTemplate(1, 4, &dto.TemplateRequest{
+			Name:     "New",
+			Title:    "New",
+			Subtasks: []dto.TemplateSubtask{{Title: "Step"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, created, got.CreatedAt)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := new(gorm_template.TemplateRepoMock)
+		repo.On("GetByID", 1, 4).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := NewTemplateService(repo, nil, nil, nil).UpdateTemplate(1, 4, &dto.TemplateRequest{Name: "New", Title: "New"})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestTemplateService_Instantiate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	now := time.Date(2025, 9, 7, 22, 30, 0, 0, time.UTC) // already 8 September in Berlin
+
+	releasePrep := &template.Template{
+		ID:          3,
+		UserID:      1,
+		Name:        "Release prep",
+		Title:       "Release {{version}}",
+		Description: "Ship {{version}} on {{date}}",
+		Tags:        []string{"Release", "{{team}}"},
+		DueOffset:   "5d",
+		Subtasks: []template.Subtask{
+			{Title: "Freeze", DueOffset: "-1d"},
+			{Title: "Tag v{{version}}", DueOffset: "2d9h"},
+		},
+	}
+
+	newService := func(repo *gorm_template.TemplateRepoMock, tasks *gorm_task.TaskRepoMock, projects *gorm_project.ProjectRepoMock) *TemplateService {
+		users := new(gorm_user.MockUserRepository)
+		users.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "Europe/Berlin"}, nil)
+		s := NewTemplateService(repo, tasks, projects, users)
+		s.now = func() time.Time { return now }
+		return s
+	}
+
+	t.Run("creates the task tree", func(t *testing.T) {
+		repo := new(gorm_template.TemplateRepoMock)
+		repo.On("GetByID", 1, 3).Return(releasePrep, nil)
+		tasks := new(gorm_task.TaskRepoMock)
+		tasks.On("Transaction", mock.Anything).Return(nil)
+		tasks.On("LastPosition", 1, (*int)(nil)).Return("i", nil)
+		nextID := 10
+		tasks.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(0).(*task.Task).ID = nextID
+			nextID++
+		}).Return(nil)
+		var created []int
+		tasks.On("AddEvents", mock.Anything).Run(func(args mock.Arguments) {
+			for _, e := range args.Get(0).([]events.Event) {
+				created = append(created, e.(events.TaskCreated).Task.ID)
+			}
+		}).Return(nil)
+
+		got, err := newService(repo, tasks, nil).Instantiate(1, 3, &dto.InstantiateTemplateRequest{
+			Variables: map[string]string{"version": "2.4", "team": "Core"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 10, got.Task.ID)
+		assert.Equal(t, "Release 2.4", got.Task.Task)
+		assert.Equal(t, "Ship 2.4 on 2025-09-08", got.Task.Description)
+		assert.Equal(t, []string{"release", "core"}, got.Task.Tags)
+		assert.True(t, time.Date(2025, 9, 13, 23, 59, 0, 0, berlin).Equal(*got.Task.DueAt))
+		assert.Equal(t, "r", got.Task.Position)
+
+		require.Len(t, got.Subtasks, 2)
+		assert.Equal(t, "Freeze", got.Subtasks[0].Task)
+		assert.Equal(t, 10, *got.Subtasks[0].ParentID)
+		assert.True(t, time.Date(2025, 9, 7, 23, 59, 0, 0, berlin).Equal(*got.Subtasks[0].DueAt))
+		assert.Equal(t, "Tag v2.4", got.Subtasks[1].Task)
+		assert.True(t, time.Date(2025, 9, 10, 9, 0, 0, 0, berlin).Equal(*got.Subtasks[1].DueAt))
+		assert.Less(t, got.Task.Position, got.Subtasks[0].Position)
+		assert.Less(t, got.Subtasks[0].Position, got.Subtasks[1].Position)
+		tasks.AssertNumberOfCalls(t, "Create", 3)
+		assert.Equal(t, []int{10, 11, 12}, created, "every created task is recorded")
+	})
+
+	t.Run("base date and project", func(t *testing.T) {
+		repo := new(gorm_template.TemplateRepoMock)
+		repo.On("GetByID", 1, 3).Return(&template.Template{ID: 3, UserID: 1, Title: "Retro {{date}}", DueOffset: "0d"}, nil)
+		projects := new(gorm_project.ProjectRepoMock)
+		projects.On("GetByID", 1, 2).Return(&project.Project{ID: 2, UserID: 1}, nil)
+		tasks := new(gorm_task.TaskRepoMock)
+		tasks.On("Transaction", mock.Anything).Return(nil)
+		tasks.On("LastPosition", 1, mock.MatchedBy(func(p *int) bool { return p != nil && *p == 2 })).Return("", nil)
+		tasks.On("Create", mock.MatchedBy(func(tk *task.Task) bool {
+			return tk.ProjectID != nil && *tk.ProjectID == 2
+		})).Return(nil)
+		tasks.On("AddEvents", mock.Anything).Return(nil)
+
+		got, err := newService(repo, tasks, projects).Instantiate(1, 3, &dto.InstantiateTemplateRequest{BaseDate: "2025-10-01", ProjectID: intPtr(2)})
+		require.NoError(t, err)
+		assert.Equal(t, "Retro 2025-10-01", got.Task.Task)
+		assert.True(t, time.Date(2025, 10, 1, 23, 59, 0, 0, berlin).Equal(*got.Task.DueAt))
+		assert.Empty(t, got.Subtasks)
+		tasks.AssertExpectations(t)
+	})
+
+	t.Run("missing variable creates nothing", func(t *testing.T) {
+		repo := new(gorm_template.TemplateRepoMock)
+		repo.On("GetByID", 1, 3).Return(releasePrep, nil)
+		tasks := new(gorm_task.TaskRepoMock)
+
+		_, err := newService(repo, tasks, nil).Instantiate(1, 3, &dto.InstantiateTemplateRequest{Variables: map[string]string{"version": "2.4"}})
+		assert.ErrorIs(t, err, template.ErrMissingVariable)
+		tasks.AssertNotCalled(t, "Transaction", mock.Anything)
+	})
+
+	t.Run("title too long after substitution", func(t *testing.T) {
+		repo := new(gorm_template.TemplateRepoMock)
+		repo.On("GetByID", 1, 3).Return(releasePrep, nil)
+
+		_, err := newService(repo, new(gorm_task.TaskRepoMock), nil).Instantiate(1, 3, &dto.InstantiateTemplateRequest{
+			Variables: map[string]string{"version": "2025.09-release-candidate", "team": "core"},
+		})
+		assert.ErrorIs(t, err, task_service.ErrTaskTooLong)
+	})
+
+	t.Run("invalid base date", func(t *testing.T) {
+		repo := new(gorm_template.TemplateRepoMock)
+		repo.On("GetByID", 1, 3).Return(releasePrep, nil)
+
+		_, err := newService(repo, nil, nil).Instantiate(1, 3, &dto.InstantiateTemplateRequest{BaseDate: "next monday"})
+		assert.ErrorIs(t, err, ErrInvalidBaseDate)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		repo := new(gorm_template.TemplateRepoMock)
+		repo.On("GetByID", 1, 3).Return(releasePrep, nil)
+		projects := new(gorm_project.ProjectRepoMock)
+		projects.On("GetByID", 1, 9).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := newService(repo, nil, projects).Instantiate(1, 3, &dto.InstantiateTemplateRequest{ProjectID: intPtr(9)})
+		assert.ErrorIs(t, err, ErrUnknownProject)
+	})
+}
+
+func intPtr(i int) *int {
+	return &i
+}
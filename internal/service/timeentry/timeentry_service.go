@@ -0,0 +1,343 @@
+package timeentry_service
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"taskflow/internal/domain/timeentry"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_timeentry"
+
+	"gorm.io/gorm"
+)
+
+// Report groupings.
+const (
+	GroupByDay     = "day"
+	GroupByProject = "project"
+	GroupByTag     = "tag"
+)
+
+const (
+	// MaxEntryDuration is the longest manual entry.
+	MaxEntryDuration = 24 * time.Hour
+	// MaxReportDays is the widest date range a report may cover.
+	MaxReportDays = 366
+
+	dateLayout = "2006-01-02"
+)
+
+var (
+	ErrInvalidUser    = errors.New("invalid user")
+	ErrTimerRunning   = errors.New("a timer is already running; stop it first")
+	ErrNoRunningTimer = errors.New("no timer is running for this task")
+	ErrInvalidEntry   = errors.New("a time entry needs exactly one of ended_at or duration_minutes and may last at most 24 hours")
+	ErrInvalidRange   = errors.New("from and to must be YYYY-MM-DD dates, from not after to, at most 366 days apart")
+	ErrInvalidGroup   = errors.New("group_by must be day, project or tag")
+)
+
+// TimeEntryService tracks time spent on tasks, with at most one running
+// timer per user, and summarises it in reports.
+type TimeEntryService struct {
+	entries  gorm_timeentry.TimeEntryRepositoryInterface
+	tasks    gorm_task.TaskRepositoryInterface
+	projects gorm_project.ProjectRepositoryInterface
+	now      func() time.Time
+}
+
+func NewTimeEntryService(entries gorm_timeentry.TimeEntryRepositoryInterface, tasks gorm_task.TaskRepositoryInterface, projects gorm_project.ProjectRepositoryInterface) *TimeEntryService {
+	return &TimeEntryService{entries: entries, tasks: tasks, projects: projects, now: time.Now}
+}
+
+var _ TimeEntryServiceInterface = (*TimeEntryService)(nil)
+
+// StartTimer starts a timer on the task, failing if one is already running.
+func (s *TimeEntryService) StartTimer(userID int, taskID int, req *dto.StartTimerRequest) (dto.TimeEntryResponse, error) {
+	if userID == 0 {
+		return dto.TimeEntryResponse{}, ErrInvalidUser
+	}
+	if _, err := s.tasks.GetByID(userID, taskID); err != nil {
+		return dto.TimeEntryResponse{}, err
+	}
+
+	_, err := s.entries.Running(userID)
+	if err == nil {
+		return dto.TimeEntryResponse{}, ErrTimerRunning
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return dto.TimeEntryResponse{}, err
+	}
+
+	e := &timeentry.Entry{
+		TaskID:        taskID,
+		UserID:        userID,
+		StartedAt:     s.now().UTC(),
+		Note:          req.Note,
+		RunningUserID: &userID,
+	}
+	if err := s.entries.Create(e); err != nil {
+		return dto.TimeEntryResponse{}, err
+	}
+	return s.toResponse(*e), nil
+}
+
+// StopTimer stops the user's timer if it runs on the task.
+func (s *TimeEntryService) StopTimer(userID int, taskID int) (dto.TimeEntryResponse, error) {
+	if userID == 0 {
+		return dto.TimeEntryResponse{}, ErrInvalidUser
+	}
+
+	e, err := s.entries.Running(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || err == nil && e.TaskID != taskID {
+		return dto.TimeEntryResponse{}, ErrNoRunningTimer
+	}
+	if err != nil {
+		return dto.TimeEntryResponse{}, err
+	}
+
+	now := s.now().UTC()
+	e.EndedAt = &now
+	if err := s.entries.Stop(e); err != nil {
+		return dto.TimeEntryResponse{}, err
+	}
+	return s.toResponse(*e), nil
+}
+
+// AddEntry records time spent on the task by hand.
+func (s *TimeEntryService) AddEntry(userID int, taskID int, req *dto.CreateTimeEntryRequest) (dto.TimeEntryResponse, error) {
+	if userID == 0 {
+		return dto.TimeEntryResponse{}, ErrInvalidUser
+	}
+	if (req.EndedAt == nil) == (req.DurationMinutes == 0) {
+		return dto.TimeEntryResponse{}, ErrInvalidEntry
+	}
+
+	start := req.StartedAt.UTC()
+	end := start.Add(time.Duration(req.DurationMinutes) * time.Minute)
+	if req.EndedAt != nil {
+		end = req.EndedAt.UTC()
+	}
+	if !end.After(start) || end.Sub(start) > MaxEntryDuration {
+		return dto.TimeEntryResponse{}, ErrInvalidEntry
+	}
+
+	if _, err := s.tasks.GetByID(userID, taskID); err != nil {
+		return dto.TimeEntryResponse{}, err
+	}
+
+	e := &timeentry.Entry{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: start,
+		EndedAt:   &end,
+		Note:      req.Note,
+	}
+	if err := s.entries.Create(e); err != nil {
+		return dto.TimeEntryResponse{}, err
+	}
+	return s.toResponse(*e), nil
+}
+
+// ListEntries returns the task's entries with its total and estimate.
+func (s *TimeEntryService) ListEntries(userID int, taskID int) (dto.TaskTimeResponse, error) {
+	if userID == 0 {
+		return dto.TaskTimeResponse{}, ErrInvalidUser
+	}
+	t, err := s.tasks.GetByID(userID, taskID)
+	if err != nil {
+		return dto.TaskTimeResponse{}, err
+	}
+
+	entries, err := s.entries.ListByTask(taskID)
+	if err != nil {
+		return dto.TaskTimeResponse{}, err
+	}
+
+	resp := dto.TaskTimeResponse{
+		TaskID:          taskID,
+		EstimateMinutes: t.EstimateMinutes,
+		Entries:         make([]dto.TimeEntryResponse, 0, len(entries)),
+	}
+	for _, e := range entries {
+		r := s.toResponse(e)
+		resp.TotalSeconds += r.DurationSeconds
+		resp.Entries = append(resp.Entries, r)
+	}
+	if t.EstimateMinutes != nil {
+		remaining := int64(*t.EstimateMinutes)*60 - resp.TotalSeconds
+		resp.RemainingSeconds = &remaining
+	}
+	return resp, nil
+}
+
+// DeleteEntry removes an entry, including a running timer.
+func (s *TimeEntryService) DeleteEntry(userID int, taskID int, entryID int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+	if _, err := s.tasks.GetByID(userID, taskID); err != nil {
+		return err
+	}
+	return s.entries.Delete(taskID, entryID)
+}
+
+// SetEstimate sets or clears the task's estimate and returns its time summary.
+func (s *TimeEntryService) SetEstimate(userID int, taskID int, req *dto.SetEstimateRequest) (dto.TaskTimeResponse, error) {
+	if userID == 0 {
+		return dto.TaskTimeResponse{}, ErrInvalidUser
+	}
+	if _, err := s.tasks.GetByID(userID, taskID); err != nil {
+		return dto.TaskTimeResponse{}, err
+	}
+	if err := s.tasks.SetEstimate(userID, taskID, req.EstimateMinutes); err != nil {
+		return dto.TaskTimeResponse{}, err
+	}
+	return s.ListEntries(userID, taskID)
+}
+
+// Report sums the time of entries started between the from and to dates,
+// both inclusive, grouped by day, project or tag. Days are UTC dates. The
+// last seven days are reported when from and to are empty. An entry with
+// several tags counts towards each of them, so tag totals may add up to
+// more than the overall total.
+func (s *TimeEntryService) Report(userID int, from string, to string, groupBy string) (dto.TimeReportResponse, error) {
+	if userID == 0 {
+		return dto.TimeReportResponse{}, ErrInvalidUser
+	}
+	if groupBy == "" {
+		groupBy = GroupByDay
+	}
+	if groupBy != GroupByDay && groupBy != GroupByProject && groupBy != GroupByTag {
+		return dto.TimeReportResponse{}, ErrInvalidGroup
+	}
+	start, end, err := s.parseRange(from, to)
+	if err != nil {
+		return dto.TimeReportResponse{}, err
+	}
+
+	entries, err := s.entries.ListForReport(userID, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return dto.TimeReportResponse{}, err
+	}
+
+	now := s.now()
+	totals := make(map[string]int64)
+	var total int64
+	for _, e := range entries {
+		seconds := int64(e.Duration(now) / time.Second)
+		total += seconds
+		for _, key := range groupKeys(e, groupBy) {
+			totals[key] += seconds
+		}
+	}
+
+	labels, err := s.labels(userID, groupBy, totals)
+	if err != nil {
+		return dto.TimeReportResponse{}, err
+	}
+	rows := make([]dto.TimeReportRow, 0, len(totals))
+	for key, seconds := range totals {
+		rows = append(rows, dto.TimeReportRow{Key: key, Label: labels[key], Seconds: seconds, Hours: hours(seconds)})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if groupBy != GroupByDay && rows[i].Seconds != rows[j].Seconds {
+			return rows[i].Seconds > rows[j].Seconds
+		}
+		return rows[i].Key < rows[j].Key
+	})
+
+	return dto.TimeReportResponse{
+		From:         start.Format(dateLayout),
+		To:           end.Format(dateLayout),
+		GroupBy:      groupBy,
+		Rows:         rows,
+		TotalSeconds: total,
+		TotalHours:   hours(total),
+	}, nil
+}
+
+func (s *TimeEntryService) parseRange(from string, to string) (time.Time, time.Time, error) {
+	end := s.now().UTC().Truncate(24 * time.Hour)
+	if to != "" {
+		d, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidRange
+		}
+		end = d
+	}
+	start := end.AddDate(0, 0, -6)
+	if from != "" {
+		d, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidRange
+		}
+		start = d
+	}
+	if start.After(end) || end.Sub(start) >= MaxReportDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrInvalidRange
+	}
+	return start, end, nil
+}
+
+// groupKeys returns the report rows an entry counts towards. Project keys
+// are IDs with "" for the inbox, and untagged entries have the tag key "".
+func groupKeys(e gorm_timeentry.ReportEntry, groupBy string) []string {
+	switch groupBy {
+	case GroupByProject:
+		if e.ProjectID == nil {
+			return []string{""}
+		}
+		return []string{strconv.Itoa(*e.ProjectID)}
+	case GroupByTag:
+		if len(e.Tags) == 0 {
+			return []string{""}
+		}
+		return e.Tags
+	default:
+		return []string{e.StartedAt.UTC().Format(dateLayout)}
+	}
+}
+
+func (s *TimeEntryService) labels(userID int, groupBy string, totals map[string]int64) (map[string]string, error) {
+	labels := make(map[string]string, len(totals))
+	for key := range totals {
+		labels[key] = key
+	}
+	switch groupBy {
+	case GroupByTag:
+		labels[""] = "untagged"
+	case GroupByProject:
+		labels[""] = "Inbox"
+		projects, err := s.projects.List(userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range projects {
+			if key := strconv.Itoa(p.ID); labels[key] != "" {
+				labels[key] = p.Name
+			}
+		}
+	}
+	return labels, nil
+}
+
+func (s *TimeEntryService) toResponse(e timeentry.Entry) dto.TimeEntryResponse {
+	return dto.TimeEntryResponse{
+		ID:              e.ID,
+		TaskID:          e.TaskID,
+		StartedAt:       e.StartedAt,
+		EndedAt:         e.EndedAt,
+		DurationSeconds: int64(e.Duration(s.now()) / time.Second),
+		Running:         e.Running(),
+		Note:            e.Note,
+	}
+}
+
+func hours(seconds int64) float64 {
+	return math.Round(float64(seconds)/36) / 100
+}
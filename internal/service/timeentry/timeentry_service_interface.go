@@ -0,0 +1,13 @@
+package timeentry_service
+
+import "taskflow/internal/dto"
+
+type TimeEntryServiceInterface interface {
+	StartTimer(userID int, taskID int, req *dto.StartTimerRequest) (dto.TimeEntryResponse, error)
+	StopTimer(userID int, taskID int) (dto.TimeEntryResponse, error)
+	AddEntry(userID int, taskID int, req *dto.CreateTimeEntryRequest) (dto.TimeEntryResponse, error)
+	ListEntries(userID int, taskID int) (dto.TaskTimeResponse, error)
+	DeleteEntry(userID int, taskID int, entryID int) error
+	SetEstimate(userID int, taskID int, req *dto.SetEstimateRequest) (dto.TaskTimeResponse, error)
+	Report(userID int, from string, to string, groupBy string) (dto.TimeReportResponse, error)
+}
@@ -0,0 +1,48 @@
+package timeentry_service
+
+import (
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TimeEntryServiceMock struct {
+	mock.Mock
+}
+
+var _ TimeEntryServiceInterface = (*TimeEntryServiceMock)(nil)
+
+func (m *TimeEntryServiceMock) StartTimer(userID int, taskID int, req *dto.StartTimerRequest) (dto.TimeEntryResponse, error) {
+	args := m.Called(userID, taskID, req)
+	return args.Get(0).(dto.TimeEntryResponse), args.Error(1)
+}
+
+func (m *TimeEntryServiceMock) StopTimer(userID int, taskID int) (dto.TimeEntryResponse, error) {
+	args := m.Called(userID, taskID)
+	return args.Get(0).(dto.TimeEntryResponse), args.Error(1)
+}
+
+func (m *TimeEntryServiceMock) AddEntry(userID int, taskID int, req *dto.CreateTimeEntryRequest) (dto.TimeEntryResponse, error) {
+	args := m.Called(userID, taskID, req)
+	return args.Get(0).(dto.TimeEntryResponse), args.Error(1)
+}
+
+func (m *TimeEntryServiceMock) ListEntries(userID int, taskID int) (dto.TaskTimeResponse, error) {
+	args := m.Called(userID, taskID)
+	return args.Get(0).(dto.TaskTimeResponse), args.Error(1)
+}
+
+func (m *TimeEntryServiceMock) DeleteEntry(userID int, taskID int, entryID int) error {
+	args := m.Called(userID, taskID, entryID)
+	return args.Error(0)
+}
+
+func (m *TimeEntryServiceMock) SetEstimate(userID int, taskID int, req *dto.SetEstimateRequest) (dto.TaskTimeResponse, error) {
+	args := m.Called(userID, taskID, req)
+	return args.Get(0).(dto.TaskTimeResponse), args.Error(1)
+}
+
+func (m *TimeEntryServiceMock) Report(userID int, from string, to string, groupBy string) (dto.TimeReportResponse, error) {
+	args := m.Called(userID, from, to, groupBy)
+	return args.Get(0).(dto.TimeReportResponse), args.Error(1)
+}
@@ -0,0 +1,242 @@
+package timeentry_service
+
+import (
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/timeentry"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_timeentry"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var now = time.Date(2025, 9, 3, 12, 0, 0, 0, time.UTC)
+
+func newService(entries *gorm_timeentry.TimeEntryRepoMock, tasks *gorm_task.TaskRepoMock, projects *gorm_project.ProjectRepoMock) *TimeEntryService {
+	s := NewTimeEntryService(entries, tasks, projects)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestTimeEntryService_StartTimer(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		entries := new(gorm_timeentry.TimeEntryRepoMock)
+		tasks := new(gorm_task.TaskRepoMock)
+		tasks.On("GetByID", 1, 5).Return(&task.Task{ID: 5}, nil)
+		entries.On("Running", 1).Return(nil, gorm.ErrRecordNotFound)
+		entries.On("Create", mock.MatchedBy(func(e *timeentry.Entry) bool {
+			return e.TaskID == 5 && e.UserID == 1 && e.StartedAt.Equal(now) && e.EndedAt == nil &&
+				e.RunningUserID != nil && *e.RunningUserID == 1 && e.Note == "Client call"
+		})).Run(func(args mock.Arguments) { args.Get(0).(*timeentry.Entry).ID = 7 }).Return(nil)
+
+		got, err := newService(entries, tasks, nil).StartTimer(1, 5, &dto.StartTimerRequest{Note: "Client call"})
+		require.NoError(t, err)
+		assert.Equal(t, dto.TimeEntryResponse{ID: 7, TaskID: 5, StartedAt: now, Running: true, Note: "Client call"}, got)
+		entries.AssertExpectations(t)
+	})
+
+	t.Run("timer already running", func(t *testing.T) {
+		entries := new(gorm_timeentry.TimeEntryRepoMock)
+		tasks := new(gorm_task.TaskRepoMock)
+		tasks.On("GetByID", 1, 5).Return(&task.Task{ID: 5}, nil)
+		entries.On("Running", 1).Return(&timeentry.Entry{ID: 3, TaskID: 4}, nil)
+
+		_, err := newService(entries, tasks, nil).StartTimer(1, 5, &dto.StartTimerRequest{})
+		assert.ErrorIs(t, err, ErrTimerRunning)
+		entries.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("task not found", func(t *testing.T) {
+		tasks := new(gorm_task.TaskRepoMock)
+		tasks.On("GetByID", 1, 5).Return((*task.Task)(nil), gorm.ErrRecordNotFound)
+
+		_, err := newService(new(gorm_timeentry.TimeEntryRepoMock), tasks, nil).StartTimer(1, 5, &dto.StartTimerRequest{})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestTimeEntryService_StopTimer(t *testing.T) {
+	started := now.Add(-90 * time.Minute)
+
+	t.Run("success", func(t *testing.T) {
+		entries := new(gorm_timeentry.TimeEntryRepoMock)
+		entries.On("Running", 1).Return(&timeentry.Entry{ID: 7, TaskID: 5, StartedAt: started}, nil)
+		entries.On("Stop", mock.MatchedBy(func(e *timeentry.Entry) bool {
+			return e.ID == 7 && e.EndedAt != nil && e.EndedAt.Equal(now)
+		})).Return(nil)
+
+		got, err := newService(entries, nil, nil).StopTimer(1, 5)
+		require.NoError(t, err)
+		assert.Equal(t, int64(90*60), got.DurationSeconds)
+		assert.False(t, got.Running)
+		entries.AssertExpectations(t)
+	})
+
+	t.Run("timer runs on another task", func(t *testing.T) {
+		entries := new(gorm_timeentry.TimeEntryRepoMock)
+		entries.On("Running", 1).Return(&timeentry.Entry{ID: 7, TaskID: 6, StartedAt: started}, nil)
+
+		_, err := newService(entries, nil, nil).StopTimer(1, 5)
+		assert.ErrorIs(t, err, ErrNoRunningTimer)
+		entries.AssertNotCalled(t, "Stop", mock.Anything)
+	})
+
+	t.Run("no timer", func(t *testing.T) {
+		entries := new(gorm_timeentry.TimeEntryRepoMock)
+		entries.On("Running", 1).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := newService(entries, nil, nil).StopTimer(1, 5)
+		assert.ErrorIs(t, err, ErrNoRunningTimer)
+	})
+}
+
+func TestTimeEntryService_AddEntry(t *testing.T) {
+	start := now.Add(-3 * time.Hour)
+	end := start.Add(time.Hour)
+	before := start.Add(-time.Minute)
+	tooLong := start.Add(25 * time.Hour)
+
+	tests := []struct {
+		name    string
+		req     dto.CreateTimeEntryRequest
+		wantEnd time.Time
+		wantErr error
+	}{
+		{name: "with end", req: dto.CreateTimeEntryRequest{StartedAt: start, EndedAt: &end}, wantEnd: end},
+		{name: "with duration", req: dto.CreateTimeEntryRequest{StartedAt: start, DurationMinutes: 45}, wantEnd: start.Add(45 * time.Minute)},
+		{name: "neither", req: dto.CreateTimeEntryRequest{StartedAt: start}, wantErr: ErrInvalidEntry},
+		{name: "both", req: dto.CreateTimeEntryRequest{StartedAt: start, EndedAt: &end, DurationMinutes: 45}, wantErr: ErrInvalidEntry},
+		{name: "ends before start", req: dto.CreateTimeEntryRequest{StartedAt: start, EndedAt: &before}, wantErr: ErrInvalidEntry},
+		{name: "longer than a day", req: dto.CreateTimeEntryRequest{StartedAt: start, EndedAt: &tooLong}, wantErr: ErrInvalidEntry},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := new(gorm_timeentry.TimeEntryRepoMock)
+			tasks := new(gorm_task.TaskRepoMock)
+			tasks.On("GetByID", 1, 5).Return(&task.Task{ID: 5}, nil)
+			entries.On("Create", mock.Anything).Return(nil)
+
+			got, err := newService(entries, tasks, nil).AddEntry(1, 5, &tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				entries.AssertNotCalled(t, "Create", mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, got.EndedAt)
+			assert.True(t, got.EndedAt.Equal(tt.wantEnd))
+			assert.Equal(t, int64(tt.wantEnd.Sub(start)/time.Second), got.DurationSeconds)
+		})
+	}
+}
+
+func TestTimeEntryService_ListEntries(t *testing.T) {
+	estimate := 120
+	end := now.Add(-2 * time.Hour)
+	entries := new(gorm_timeentry.TimeEntryRepoMock)
+	tasks := new(gorm_task.TaskRepoMock)
+	tasks.On("GetByID", 1, 5).Return(&task.Task{ID: 5, EstimateMinutes: &estimate}, nil)
+	entries.On("ListByTask", 5).Return([]timeentry.Entry{
+		{ID: 8, TaskID: 5, StartedAt: now.Add(-30 * time.Minute)},
+		{ID: 7, TaskID: 5, StartedAt: end.Add(-time.Hour), EndedAt: &end},
+	}, nil)
+
+	got, err := newService(entries, tasks, nil).ListEntries(1, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(90*60), got.TotalSeconds)
+	require.NotNil(t, got.RemainingSeconds)
+	assert.Equal(t, int64(30*60), *got.RemainingSeconds)
+	require.Len(t, got.Entries, 2)
+	assert.True(t, got.Entries[0].Running)
+	assert.Equal(t, int64(30*60), got.Entries[0].DurationSeconds)
+}
+
+func TestTimeEntryService_Report(t *testing.T) {
+	projectID := 2
+	day := func(d, h int) time.Time { return time.Date(2025, 9, d, h, 0, 0, 0, time.UTC) }
+	ended := func(t time.Time, minutes int) *time.Time {
+		end := t.Add(time.Duration(minutes) * time.Minute)
+		return &end
+	}
+	report := []gorm_timeentry.ReportEntry{
+		{Entry: timeentry.Entry{TaskID: 1, StartedAt: day(1, 9), EndedAt: ended(day(1, 9), 60)}, Tags: []string{"client", "work"}},
+		{Entry: timeentry.Entry{TaskID: 2, StartedAt: day(1, 14), EndedAt: ended(day(1, 14), 30)}, ProjectID: &projectID},
+		{Entry: timeentry.Entry{TaskID: 2, StartedAt: day(3, 11)}, ProjectID: &projectID, Tags: []string{"work"}},
+	}
+
+	tests := []struct {
+		groupBy string
+		want    []dto.TimeReportRow
+	}{
+		{groupBy: "", want: []dto.TimeReportRow{
+			{Key: "2025-09-01", Label: "2025-09-01", Seconds: 5400, Hours: 1.5},
+			{Key: "2025-09-03", Label: "2025-09-03", Seconds: 3600, Hours: 1},
+		}},
+		{groupBy: GroupByProject, want: []dto.TimeReportRow{
+			{Key: "2", Label: "Website", Seconds: 5400, Hours: 1.5},
+			{Key: "", Label: "Inbox", Seconds: 3600, Hours: 1},
+		}},
+		{groupBy: GroupByTag, want: []dto.TimeReportRow{
+			{Key: "work", Label: "work", Seconds: 7200, Hours: 2},
+			{Key: "client", Label: "client", Seconds: 3600, Hours: 1},
+			{Key: "", Label: "untagged", Seconds: 1800, Hours: 0.5},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run("group by "+tt.groupBy, func(t *testing.T) {
+			entries := new(gorm_timeentry.TimeEntryRepoMock)
+			projects := new(gorm_project.ProjectRepoMock)
+			entries.On("ListForReport", 1, day(1, 0), day(4, 0)).Return(report, nil)
+			projects.On("List", 1).Return([]project.Project{{ID: 2, Name: "Website"}, {ID: 3, Name: "Garden"}}, nil)
+
+			got, err := newService(entries, nil, projects).Report(1, "2025-09-01", "2025-09-03", tt.groupBy)
+			require.NoError(t, err)
+
+			assert.Equal(t, "2025-09-01", got.From)
+			assert.Equal(t, "2025-09-03", got.To)
+			assert.Equal(t, int64(9000), got.TotalSeconds)
+			assert.Equal(t, 2.5, got.TotalHours)
+			assert.Equal(t, tt.want, got.Rows)
+		})
+	}
+}
+
+func TestTimeEntryService_Report_Errors(t *testing.T) {
+	s := newService(new(gorm_timeentry.TimeEntryRepoMock), nil, nil)
+
+	_, err := s.Report(1, "", "", "week")
+	assert.ErrorIs(t, err, ErrInvalidGroup)
+
+	_, err = s.Report(1, "2025-09-05", "2025-09-01", "day")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = s.Report(1, "2024-01-01", "2025-09-01", "day")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = s.Report(1, "yesterday", "", "day")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestTimeEntryService_Report_DefaultsToLastWeek(t *testing.T) {
+	entries := new(gorm_timeentry.TimeEntryRepoMock)
+	entries.On("ListForReport", 1, time.Date(2025, 8, 28, 0, 0, 0, 0, time.UTC), time.Date(2025, 9, 4, 0, 0, 0, 0, time.UTC)).
+		Return([]gorm_timeentry.ReportEntry{}, nil)
+
+	got, err := newService(entries, nil, nil).Report(1, "", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-08-28", got.From)
+	assert.Equal(t, "2025-09-03", got.To)
+	assert.Equal(t, GroupByDay, got.GroupBy)
+	assert.Empty(t, got.Rows)
+	entries.AssertExpectations(t)
+}
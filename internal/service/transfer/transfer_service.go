@@ -0,0 +1,814 @@
+package transfer_service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	task_service "taskflow/internal/service/task"
+)
+
+const (
+	FormatCSV      = "csv"
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+	FormatMarkdown = "markdown"
+
+	// MaxImportRows is how many tasks one import may contain.
+	MaxImportRows = 5000
+	// MaxPreview is how many tasks a dry run previews.
+	MaxPreview = 50
+
+	// exportBatch is how many tasks an export reads at a time.
+	exportBatch = 500
+	// maxLine bounds an NDJSON line.
+	maxLine = 1 << 20
+)
+
+// Task fields the columns of an imported file can be mapped to.
+const (
+	FieldTask            = "task"
+	FieldDescription     = "description"
+	FieldStatus          = "status"
+	FieldPriority        = "priority"
+	FieldDueAt           = "due_at"
+	FieldTags            = "tags"
+	FieldProject         = "project"
+	FieldEstimateMinutes = "estimate_minutes"
+	FieldCompletedAt     = "completed_at"
+	FieldRecurrence      = "recurrence"
+	// FieldIgnore skips a column that would otherwise be matched by name.
+	FieldIgnore = "-"
+)
+
+var (
+	ErrInvalidUser         = errors.New("invalid user")
+	ErrUnknownFormat       = errors.New("format must be csv, json, ndjson or markdown")
+	ErrUnknownImportFormat = errors.New("format must be csv, json or ndjson")
+	ErrInvalidFile         = errors.New("file could not be read")
+	ErrNoRows              = errors.New("file contains no tasks")
+	ErrTooManyRows         = fmt.Errorf("file contains more than %d tasks", MaxImportRows)
+	ErrInvalidMapping      = errors.New("invalid column mapping")
+	ErrNoTaskColumn        = errors.New("no column is mapped to task")
+	// ErrInvalidRows is returned with the response listing the rows that
+	// aren't valid tasks. Nothing is imported.
+	ErrInvalidRows = errors.New("some rows are not valid tasks")
+)
+
+// ExportFormats lists the formats Export writes.
+var ExportFormats = []string{FormatCSV, FormatJSON, FormatNDJSON, FormatMarkdown}
+
+// aliases are the column names matched to each field when the mapping
+// leaves a column out, after lower-casing and turning spaces and hyphens
+// into underscores.
+var aliases = map[string][]string{
+	FieldTask:            {"task", "title", "name", "summary"},
+	FieldDescription:     {"description", "notes", "note", "details"},
+	FieldStatus:          {"status", "state"},
+	FieldPriority:        {"priority"},
+	FieldDueAt:           {"due_at", "due", "due_date", "deadline"},
+	FieldTags:            {"tags", "tag", "labels", "label"},
+	FieldProject:         {"project", "list"},
+	FieldEstimateMinutes: {"estimate_minutes", "estimate"},
+	FieldCompletedAt:     {"completed_at", "completed", "done"},
+	FieldRecurrence:      {"recurrence", "rrule", "repeat"},
+}
+
+// csvHeader is the first row of a CSV export. An exported file imports as
+// is: the columns TaskFlow assigns itself, like id, are ignored.
+var csvHeader = []string{
+	"id", "task", "description", "status", "priority", "due_at", "tags", "project",
+	"project_id", "parent_id", "estimate_minutes", "completed_at", "recurrence", "created_at", "updated_at",
+}
+
+type TransferService struct {
+	taskRepo gorm_task.TaskRepositoryInterface
+	projects gorm_project.ProjectRepositoryInterface
+	tasks    task_service.TaskServiceInterface
+}
+
+func NewTransferService(taskRepo gorm_task.TaskRepositoryInterface, projects gorm_project.ProjectRepositoryInterface, tasks task_service.TaskServiceInterface) *TransferService {
+	return &TransferService{taskRepo: taskRepo, projects: projects, tasks: tasks}
+}
+
+var _ TransferServiceInterface = (*TransferService)(nil)
+
+// Export streams the user's tasks, excluding the trash, ordered by ID.
+// Nothing is written to w before the first batch is read, so an error
+// returned then can still be reported in its place.
+func (s *TransferService) Export(userID int, format string, w io.Writer) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+	bw := bufio.NewWriter(w)
+	var e exporter
+	switch format {
+	case FormatCSV:
+		e = &csvExporter{w: csv.NewWriter(bw)}
+	case FormatJSON:
+		e = &jsonExporter{w: bw}
+	case FormatNDJSON:
+		e = &ndjsonExporter{enc: json.NewEncoder(bw)}
+	case FormatMarkdown:
+		e = &markdownExporter{w: bw}
+	default:
+		return ErrUnknownFormat
+	}
+
+	projects, err := s.projects.List(userID)
+	if err != nil {
+		return err
+	}
+	names := make(map[int]string, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+	}
+
+	if err := e.begin(); err != nil {
+		return err
+	}
+	err = s.taskRepo.Batches(userID, exportBatch, func(tasks []task.Task) error {
+		for i := range tasks {
+			t := exportedTask(&tasks[i], names)
+			if err := e.write(&t); err != nil {
+				return err
+			}
+		}
+		return flush(e, bw, w)
+	})
+	if err != nil {
+		return err
+	}
+	if err := e.end(); err != nil {
+		return err
+	}
+	return flush(e, bw, w)
+}
+
+// Import reads the rows of a file as tasks. Columns are mapped to task
+// fields by opts.Mapping or by name, and every row is checked like
+// TaskService.Import checks tasks; invalid rows are listed with
+// ErrInvalidRows. The tasks are then created in one transaction, unless
+// opts.DryRun asks for a preview.
+func (s *TransferService) Import(userID int, r io.Reader, opts dto.TaskImportOptions) (dto.TaskImportResponse, error) {
+	if userID == 0 {
+		return dto.TaskImportResponse{}, ErrInvalidUser
+	}
+
+	var recs []record
+	var columns []string
+	var err error
+	switch opts.Format {
+	case FormatCSV:
+		columns, recs, err = readCSV(r)
+	case FormatJSON:
+		columns, recs, err = readJSON(r)
+	case FormatNDJSON:
+		columns, recs, err = readNDJSON(r)
+	default:
+		return dto.TaskImportResponse{}, ErrUnknownImportFormat
+	}
+	if err != nil {
+		return dto.TaskImportResponse{}, err
+	}
+	if len(recs) == 0 {
+		return dto.TaskImportResponse{}, ErrNoRows
+	}
+
+	mapping, err := mapColumns(columns, opts.Mapping)
+	if err != nil {
+		return dto.TaskImportResponse{}, err
+	}
+	projects, err := s.projects.List(userID)
+	if err != nil {
+		return dto.TaskImportResponse{}, err
+	}
+	projectIDs := make(map[string]int, len(projects))
+	for _, p := range projects {
+		projectIDs[strings.ToLower(strings.TrimSpace(p.Name))] = p.ID
+	}
+
+	resp := dto.TaskImportResponse{
+		DryRun:  opts.DryRun,
+		Mapping: mapping,
+		Rows:    len(recs),
+		Tasks:   []dto.GetTaskResponse{},
+		Errors:  []dto.TaskImportRowError{},
+	}
+	reqs := make([]dto.ImportTaskRequest, 0, len(recs))
+	for _, rec := range recs {
+		req, errs := toRequest(rec, columns, mapping, projectIDs)
+		if len(errs) > 0 {
+			resp.Errors = append(resp.Errors, errs...)
+			continue
+		}
+		reqs = append(reqs, *req)
+	}
+
+	if opts.DryRun {
+		resp.Preview = reqs[:min(len(reqs), MaxPreview)]
+		return resp, nil
+	}
+	if len(resp.Errors) > 0 {
+		return resp, ErrInvalidRows
+	}
+
+	created, err := s.tasks.Import(userID, reqs)
+	if err != nil {
+		return dto.TaskImportResponse{}, err
+	}
+	resp.Tasks = created
+	resp.Imported = len(created)
+	return resp, nil
+}
+
+// record is a row of an imported file, by column.
+type record struct {
+	row    int
+	values map[string]string
+}
+
+func readCSV(r io.Reader) ([]string, []record, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	// Spreadsheet apps may start the file with a byte order mark.
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	for i, col := range header {
+		header[i] = strings.TrimSpace(col)
+	}
+
+	var recs []record
+	for {
+		fields, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+		if len(recs) == MaxImportRows {
+			return nil, nil, ErrTooManyRows
+		}
+		line, _ := cr.FieldPos(0)
+		rec := record{row: line, values: make(map[string]string, len(header))}
+		for i, col := range header {
+			rec.values[col] = csvUnquote(fields[i])
+		}
+		recs = append(recs, rec)
+	}
+	return header, recs, nil
+}
+
+// readJSON reads an array of objects, or an object with one under
+// "tasks", as a JSON export has.
+func readJSON(r io.Reader) ([]string, []record, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	if tok == json.Delim('{') {
+		for {
+			if !dec.More() {
+				return nil, nil, fmt.Errorf("%w: no \"tasks\" array", ErrInvalidFile)
+			}
+			key, err := dec.Token()
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+			}
+			if key == "tasks" {
+				break
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+			}
+		}
+		if tok, err = dec.Token(); err != nil {
+			return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+	}
+	if tok != json.Delim('[') {
+		return nil, nil, fmt.Errorf("%w: expected an array of tasks", ErrInvalidFile)
+	}
+
+	var cols columnSet
+	var recs []record
+	for dec.More() {
+		if len(recs) == MaxImportRows {
+			return nil, nil, ErrTooManyRows
+		}
+		var obj map[string]any
+		if err := dec.Decode(&obj); err != nil {
+			return nil, nil, fmt.Errorf("%w: task %d: %w", ErrInvalidFile, len(recs)+1, err)
+		}
+		recs = append(recs, record{row: len(recs) + 1, values: cols.add(obj)})
+	}
+	return cols.names(), recs, nil
+}
+
+func readNDJSON(r io.Reader) ([]string, []record, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLine)
+	var cols columnSet
+	var recs []record
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" {
+			continue
+		}
+		if len(recs) == MaxImportRows {
+			return nil, nil, ErrTooManyRows
+		}
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.UseNumber()
+		var obj map[string]any
+		if err := dec.Decode(&obj); err != nil {
+			return nil, nil, fmt.Errorf("%w: line %d: %w", ErrInvalidFile, line, err)
+		}
+		recs = append(recs, record{row: line, values: cols.add(obj)})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	return cols.names(), recs, nil
+}
+
+// columnSet collects the keys of JSON objects as columns.
+type columnSet map[string]bool
+
+// add returns the values of obj as text: arrays as comma-separated lists,
+// and nested objects as JSON.
+func (cs *columnSet) add(obj map[string]any) map[string]string {
+	if *cs == nil {
+		*cs = columnSet{}
+	}
+	values := make(map[string]string, len(obj))
+	for k, v := range obj {
+		(*cs)[k] = true
+		values[k] = jsonText(v)
+	}
+	return values
+}
+
+func (cs columnSet) names() []string {
+	names := make([]string, 0, len(cs))
+	for k := range cs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func jsonText(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = jsonText(item)
+		}
+		return strings.Join(parts, ",")
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// mapColumns returns the field of every column that is read. Columns in
+// mapping get the field given there, or none for "" and FieldIgnore;
+// other columns are matched by name.
+func mapColumns(columns []string, mapping map[string]string) (map[string]string, error) {
+	known := make(map[string]bool, len(columns))
+	for _, col := range columns {
+		known[col] = true
+	}
+	for col, field := range mapping {
+		if !known[col] {
+			return nil, fmt.Errorf("%w: the file has no column %q", ErrInvalidMapping, col)
+		}
+		if _, ok := aliases[field]; !ok && field != "" && field != FieldIgnore {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidMapping, field)
+		}
+	}
+
+	fields := map[string]string{}
+	byField := map[string]string{}
+	for _, col := range columns {
+		field, ok := mapping[col]
+		if !ok {
+			field = fieldNamed(col)
+		}
+		if field == "" || field == FieldIgnore {
+			continue
+		}
+		if other, taken := byField[field]; taken {
+			return nil, fmt.Errorf("%w: columns %q and %q are both read as %s", ErrInvalidMapping, other, col, field)
+		}
+		byField[field] = col
+		fields[col] = field
+	}
+	if byField[FieldTask] == "" {
+		return nil, ErrNoTaskColumn
+	}
+	return fields, nil
+}
+
+// fieldNamed returns the field a column is read as by its name.
+func fieldNamed(col string) string {
+	name := strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToLower(strings.TrimSpace(col)))
+	for field, names := range aliases {
+		for _, n := range names {
+			if n == name {
+				return field
+			}
+		}
+	}
+	return ""
+}
+
+// toRequest reads a row as a task, or lists what is wrong with it.
+func toRequest(rec record, columns []string, mapping map[string]string, projects map[string]int) (*dto.ImportTaskRequest, []dto.TaskImportRowError) {
+	req := &dto.ImportTaskRequest{}
+	var errs []dto.TaskImportRowError
+	fail := func(col string, err error) {
+		errs = append(errs, dto.TaskImportRowError{Row: rec.row, Column: col, Message: err.Error()})
+	}
+	columnOf := map[string]string{}
+	for _, col := range columns {
+		field, ok := mapping[col]
+		if !ok {
+			continue
+		}
+		columnOf[field] = col
+		value := strings.TrimSpace(rec.values[col])
+		if value == "" {
+			continue
+		}
+		switch field {
+		case FieldTask:
+			req.Task = value
+		case FieldDescription:
+			req.Description = rec.values[col]
+		case FieldStatus:
+			req.Status = parseStatus(value)
+		case FieldPriority:
+			req.Priority = strings.ToLower(value)
+		case FieldDueAt:
+			at, err := parseTime(value)
+			if err != nil {
+				fail(col, err)
+			}
+			req.DueAt = at
+		case FieldTags:
+			req.Tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+			for i, tag := range req.Tags {
+				req.Tags[i] = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+			}
+			req.Tags = task_service.NormalizeTagNames(req.Tags)
+		case FieldProject:
+			id, ok := projects[strings.ToLower(value)]
+			if !ok {
+				fail(col, fmt.Errorf("project %q not found", value))
+			}
+			req.ProjectID = &id
+		case FieldEstimateMinutes:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				fail(col, errors.New("estimate must be a whole number of minutes"))
+			}
+			req.EstimateMinutes = &n
+		case FieldCompletedAt:
+			// A column of checkmarks, such as "done", only completes the task.
+			switch strings.ToLower(value) {
+			case "true", "yes", "x", "1":
+				req.Status = task.StatusCompleted
+			case "false", "no", "0":
+			default:
+				at, err := parseTime(value)
+				if err != nil {
+					fail(col, err)
+				}
+				req.CompletedAt = at
+			}
+		case FieldRecurrence:
+			req.Recurrence = value
+		}
+	}
+	if req.CompletedAt != nil && req.Status == "" {
+		req.Status = task.StatusCompleted
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	if err := task_service.ValidateImport(req); err != nil {
+		fail(columnOf[errorField(err)], err)
+		return nil, errs
+	}
+	return req, nil
+}
+
+// errorField returns the field a ValidateImport error is about.
+func errorField(err error) string {
+	switch {
+	case errors.Is(err, task_service.ErrEmptyTaskName), errors.Is(err, task_service.ErrTaskTooLong):
+		return FieldTask
+	case errors.Is(err, task_service.ErrDescriptionTooLong):
+		return FieldDescription
+	case errors.Is(err, task_service.ErrInvalidTags):
+		return FieldTags
+	case errors.Is(err, task_service.ErrInvalidStatus):
+		return FieldStatus
+	case errors.Is(err, task_service.ErrInvalidRecurrence):
+		return FieldRecurrence
+	case errors.Is(err, task_service.ErrInvalidEstimate):
+		return FieldEstimateMinutes
+	}
+	return FieldPriority
+}
+
+// parseStatus accepts the statuses as exported and a few common spellings
+// of them.
+func parseStatus(s string) string {
+	s = strings.NewReplacer(" ", "-", "_", "-").Replace(strings.ToLower(s))
+	switch s {
+	case "done", "complete", "closed":
+		return task.StatusCompleted
+	case "todo", "to-do", "open":
+		return task.StatusPending
+	case "doing", "in-process", "started":
+		return task.StatusInProgress
+	}
+	return s
+}
+
+// timeLayouts are the date formats read on import. Times without a zone
+// are UTC.
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func parseTime(s string) (*time.Time, error) {
+	for _, layout := range timeLayouts {
+		if at, err := time.Parse(layout, s); err == nil {
+			return &at, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q, expected a date such as 2025-09-01 or 2025-09-01T17:00:00Z", s)
+}
+
+func exportedTask(t *task.Task, projects map[int]string) dto.ExportedTask {
+	e := dto.ExportedTask{
+		GetTaskResponse: task_service.ToTaskResponse(*t),
+		CreatedAt:       t.CreatedAt.UTC(),
+		UpdatedAt:       t.UpdatedAt.UTC(),
+	}
+	if t.ProjectID != nil {
+		e.Project = projects[*t.ProjectID]
+	}
+	return e
+}
+
+// exporter writes tasks in one format.
+type exporter interface {
+	begin() error
+	write(t *dto.ExportedTask) error
+	// flush passes what the exporter buffers on to the underlying writer.
+	flush() error
+	end() error
+}
+
+// flush sends a batch on to the client.
+func flush(e exporter, bw *bufio.Writer, w io.Writer) error {
+	if err := e.flush(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	if f, ok := w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) begin() error {
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvExporter) write(t *dto.ExportedTask) error {
+	return e.w.Write([]string{
+		strconv.Itoa(t.ID),
+		csvText(t.Task),
+		csvText(t.Description),
+		t.Status,
+		t.Priority,
+		formatTime(t.DueAt),
+		csvText(strings.Join(t.Tags, ",")),
+		csvText(t.Project),
+		formatInt(t.ProjectID),
+		formatInt(t.ParentID),
+		formatInt(t.EstimateMinutes),
+		formatTime(t.CompletedAt),
+		csvText(t.Recurrence),
+		formatTime(&t.CreatedAt),
+		formatTime(&t.UpdatedAt),
+	})
+}
+
+// formulaPrefixes are the first characters that make a spreadsheet read
+// a cell as a formula.
+const formulaPrefixes = "=+-@\t\r"
+
+// csvText quotes s with a leading apostrophe when a spreadsheet would
+// otherwise evaluate it, so that a task titled "=HYPERLINK(...)" stays
+// text when the export is opened.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune(formulaPrefixes, rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// csvUnquote undoes csvText, so that an export imports unchanged.
+func csvUnquote(s string) string {
+	if len(s) > 1 && s[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(s[1])) {
+		return s[1:]
+	}
+	return s
+}
+
+func (e *csvExporter) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvExporter) end() error {
+	return nil
+}
+
+// jsonExporter writes {"tasks": [...]}, the shape of GET /tasks.
+type jsonExporter struct {
+	w       io.Writer
+	written bool
+}
+
+func (e *jsonExporter) begin() error {
+	_, err := io.WriteString(e.w, `{"tasks":[`)
+	return err
+}
+
+func (e *jsonExporter) write(t *dto.ExportedTask) error {
+	b, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	if e.written {
+		if _, err := io.WriteString(e.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	e.written = true
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonExporter) flush() error {
+	return nil
+}
+
+func (e *jsonExporter) end() error {
+	_, err := io.WriteString(e.w, "]}\n")
+	return err
+}
+
+type ndjsonExporter struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonExporter) begin() error {
+	return nil
+}
+
+func (e *ndjsonExporter) write(t *dto.ExportedTask) error {
+	return e.enc.Encode(t)
+}
+
+func (e *ndjsonExporter) flush() error {
+	return nil
+}
+
+func (e *ndjsonExporter) end() error {
+	return nil
+}
+
+// markdownExporter writes a checklist, one task per item with its details
+// after the name and its description indented below.
+type markdownExporter struct {
+	w io.Writer
+}
+
+func (e *markdownExporter) begin() error {
+	_, err := io.WriteString(e.w, "# Tasks\n\n")
+	return err
+}
+
+func (e *markdownExporter) write(t *dto.ExportedTask) error {
+	var b strings.Builder
+	box := " "
+	if t.Status == task.StatusCompleted {
+		box = "x"
+	}
+	b.WriteString("- [" + box + "] " + markdownEscape(t.Task))
+
+	var details []string
+	if t.Status == task.StatusInProgress {
+		details = append(details, "in progress")
+	}
+	if t.DueAt != nil {
+		details = append(details, "due "+t.DueAt.UTC().Format("2006-01-02 15:04")+" UTC")
+	}
+	if t.Priority != "" {
+		details = append(details, t.Priority+" priority")
+	}
+	if t.Project != "" {
+		details = append(details, markdownEscape(t.Project))
+	}
+	if len(details) > 0 {
+		b.WriteString(" — " + strings.Join(details, " · "))
+	}
+	for _, tag := range t.Tags {
+		b.WriteString(" `#" + tag + "`")
+	}
+	b.WriteString("\n")
+	if t.Description != "" {
+		for _, line := range strings.Split(strings.TrimRight(t.Description, "\n"), "\n") {
+			b.WriteString("  " + markdownEscape(line) + "\n")
+		}
+	}
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *markdownExporter) flush() error {
+	return nil
+}
+
+func (e *markdownExporter) end() error {
+	return nil
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "#", `\#`, "<", `\<`, ">", `\>`,
+)
+
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
@@ -0,0 +1,16 @@
+package transfer_service
+
+import (
+	"io"
+
+	"taskflow/internal/dto"
+)
+
+type TransferServiceInterface interface {
+	// Export writes all of the user's tasks to w in one of ExportFormats,
+	// a batch at a time.
+	Export(userID int, format string, w io.Writer) error
+	// Import creates tasks from a CSV, JSON or NDJSON file: all of them, or
+	// none when a row is invalid.
+	Import(userID int, r io.Reader, opts dto.TaskImportOptions) (dto.TaskImportResponse, error)
+}
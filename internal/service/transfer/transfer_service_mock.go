@@ -0,0 +1,25 @@
+package transfer_service
+
+import (
+	"io"
+
+	"taskflow/internal/dto"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type TransferServiceMock struct {
+	mock.Mock
+}
+
+var _ TransferServiceInterface = (*TransferServiceMock)(nil)
+
+func (m *TransferServiceMock) Export(userID int, format string, w io.Writer) error {
+	args := m.Called(userID, format, w)
+	return args.Error(0)
+}
+
+func (m *TransferServiceMock) Import(userID int, r io.Reader, opts dto.TaskImportOptions) (dto.TaskImportResponse, error) {
+	args := m.Called(userID, r, opts)
+	return args.Get(0).(dto.TaskImportResponse), args.Error(1)
+}
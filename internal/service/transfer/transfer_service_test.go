@@ -0,0 +1,322 @@
+package transfer_service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/project"
+	"taskflow/internal/domain/task"
+	"taskflow/internal/dto"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_task"
+	task_service "taskflow/internal/service/task"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	created = time.Date(2025, 9, 1, 9, 0, 0, 0, time.UTC)
+	due     = time.Date(2025, 9, 10, 17, 0, 0, 0, time.UTC)
+)
+
+type fixture struct {
+	taskRepo *gorm_task.TaskRepoMock
+	projects *gorm_project.ProjectRepoMock
+	tasks    *task_service.TaskServiceMock
+	service  *TransferService
+}
+
+func setup() *fixture {
+	f := &fixture{
+		taskRepo: new(gorm_task.TaskRepoMock),
+		projects: new(gorm_project.ProjectRepoMock),
+		tasks:    new(task_service.TaskServiceMock),
+	}
+	f.projects.On("List", 1).Return([]project.Project{{ID: 2, UserID: 1, Name: "Home Renovation"}}, nil)
+	f.service = NewTransferService(f.taskRepo, f.projects, f.tasks)
+	return f
+}
+
+func exportFixture() *fixture {
+	f := setup()
+	project2, estimate := 2, 30
+	f.taskRepo.On("Batches", 1, exportBatch).Return([][]task.Task{
+		{
+			{ID: 1, Task: "Buy paint", Description: "White, *matte*\nTwo tins", Status: "in-progress", Priority: task.PriorityHigh, DueAt: &due,
+				ProjectID: &project2, EstimateMinutes: &estimate, Tags: []task.Tag{{Name: "diy"}, {Name: "shop"}}, CreatedAt: created, UpdatedAt: created},
+		},
+		{
+			{ID: 3, Task: "Call plumber", Status: "completed", CompletedAt: &due, Recurrence: "FREQ=MONTHLY", CreatedAt: created, UpdatedAt: created},
+		},
+	}, nil)
+	return f
+}
+
+func TestTransferService_Export(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		f := exportFixture()
+		var buf bytes.Buffer
+		require.NoError(t, f.service.Export(1, FormatCSV, &buf))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, csvHeader, rows[0])
+		assert.Equal(t, []string{"1", "Buy paint", "White, *matte*\nTwo tins", "in-progress", "high", "2025-09-10T17:00:00Z", "diy,shop", "Home Renovation",
+			"2", "", "30", "", "", "2025-09-01T09:00:00Z", "2025-09-01T09:00:00Z"}, rows[1])
+		assert.Equal(t, "completed", rows[2][3])
+		assert.Equal(t, "", rows[2][4], "no priority")
+		assert.Equal(t, "FREQ=MONTHLY", rows[2][12])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		f := exportFixture()
+		var buf bytes.Buffer
+		require.NoError(t, f.service.Export(1, FormatJSON, &buf))
+
+		var out struct {
+			Tasks []dto.ExportedTask `json:"tasks"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		require.Len(t, out.Tasks, 2)
+		assert.Equal(t, "Home Renovation", out.Tasks[0].Project)
+		assert.Equal(t, []string{"diy", "shop"}, out.Tasks[0].Tags)
+		assert.Equal(t, 3, out.Tasks[1].ID)
+		assert.Equal(t, created, out.Tasks[1].CreatedAt)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		f := exportFixture()
+		var buf bytes.Buffer
+		require.NoError(t, f.service.Export(1, FormatNDJSON, &buf))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[1], `{"id":3,"task":"Call plumber","status":"completed"`), lines[1])
+	})
+
+	t.Run("markdown", func(t *testing.T) {
+		f := exportFixture()
+		var buf bytes.Buffer
+		require.NoError(t, f.service.Export(1, FormatMarkdown, &buf))
+
+		assert.Equal(t, "# Tasks\n\n"+
+			"- [ ] Buy paint — in progress · due 2025-09-10 17:00 UTC · high priority · Home Renovation `#diy` `#shop`\n"+
+			"  White, \\*matte\\*\n"+
+			"  Two tins\n"+
+			"- [x] Call plumber\n", buf.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		f := setup()
+		var buf bytes.Buffer
+		assert.ErrorIs(t, f.service.Export(1, "xlsx", &buf), ErrUnknownFormat)
+		assert.ErrorIs(t, f.service.Export(0, FormatCSV, &buf), ErrInvalidUser)
+
+		f.taskRepo.On("Batches", 1, exportBatch).Return([][]task.Task{}, errors.New("db error"))
+		assert.Error(t, f.service.Export(1, FormatJSON, &buf))
+		assert.Empty(t, buf.String(), "nothing is written before the first batch")
+	})
+}
+
+func TestTransferService_Import_CSV(t *testing.T) {
+	file := "\ufeffTitle,Notes,Due Date,Labels,Project,Done,Owner\n" +
+		"Buy paint,\"White, matte\",2025-09-10,\"diy; #Shop\",home renovation,,Sam\n" +
+		"Call plumber,,2025-09-10T17:00:00Z,,,x,Alex\n"
+
+	f := setup()
+	project2 := 2
+	dueDate := time.Date(2025, 9, 10, 0, 0, 0, 0, time.UTC)
+	expected := []dto.ImportTaskRequest{
+		{CreateTaskRequest: dto.CreateTaskRequest{Task: "Buy paint", Description: "White, matte", DueAt: &dueDate, Tags: []string{"diy", "shop"}}, ProjectID: &project2},
+		{CreateTaskRequest: dto.CreateTaskRequest{Task: "Call plumber", DueAt: &due}, Status: "completed"},
+	}
+	f.tasks.On("Import", 1, expected).Return([]dto.GetTaskResponse{{ID: 8}, {ID: 9}}, nil)
+
+	resp, err := f.service.Import(1, strings.NewReader(file), dto.TaskImportOptions{Format: FormatCSV})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Rows)
+	assert.Equal(t, 2, resp.Imported)
+	assert.Len(t, resp.Tasks, 2)
+	assert.Empty(t, resp.Errors)
+	assert.Equal(t, map[string]string{
+		"Title": "task", "Notes": "description", "Due Date": "due_at", "Labels": "tags", "Project": "project", "Done": "completed_at",
+	}, resp.Mapping, "Owner matches no field")
+	f.tasks.AssertExpectations(t)
+}
+
+func TestTransferService_Export_CSVFormulas(t *testing.T) {
+	f := setup()
+	f.taskRepo.On("Batches", 1, exportBatch).Return([][]task.Task{
+		{{ID: 1, Task: "=SUM(A1:A9)", Description: "@SUM(A1)", Status: "pending", Tags: []task.Tag{{Name: "-x"}},
+			CreatedAt: created, UpdatedAt: created}},
+	}, nil)
+	var buf bytes.Buffer
+	require.NoError(t, f.service.Export(1, FormatCSV, &buf))
+	file := buf.String()
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, "'=SUM(A1:A9)", rows[1][1])
+	assert.Equal(t, "'@SUM(A1)", rows[1][2])
+	assert.Equal(t, "'-x", rows[1][6])
+
+	// Importing the export gives back the original text.
+	f.tasks.On("Import", 1, mock.MatchedBy(func(reqs []dto.ImportTaskRequest) bool {
+		return len(reqs) == 1 && reqs[0].Task == "=SUM(A1:A9)" && reqs[0].Description == "@SUM(A1)"
+	})).Return([]dto.GetTaskResponse{{ID: 8}}, nil)
+	_, err = f.service.Import(1, strings.NewReader(file), dto.TaskImportOptions{Format: FormatCSV})
+	require.NoError(t, err)
+	f.tasks.AssertExpectations(t)
+}
+
+func TestTransferService_Import_Mapping(t *testing.T) {
+	file := "What,When,Title\nBuy paint,2025-09-10,ignored\n"
+
+	f := setup()
+	f.tasks.On("Import", 1, mock.MatchedBy(func(reqs []dto.ImportTaskRequest) bool {
+		return len(reqs) == 1 && reqs[0].Task == "Buy paint" && reqs[0].DueAt != nil
+	})).Return([]dto.GetTaskResponse{{ID: 8}}, nil)
+
+	resp, err := f.service.Import(1, strings.NewReader(file), dto.TaskImportOptions{
+		Format:  FormatCSV,
+		Mapping: map[string]string{"What": "task", "When": "due_at", "Title": "-"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"What": "task", "When": "due_at"}, resp.Mapping)
+
+	for _, tt := range []struct {
+		mapping map[string]string
+		err     error
+	}{
+		{map[string]string{"Missing": "task"}, ErrInvalidMapping},
+		{map[string]string{"What": "assignee"}, ErrInvalidMapping},
+		{map[string]string{"What": "task"}, ErrInvalidMapping}, // Title is read as task too
+		{map[string]string{"Title": ""}, ErrNoTaskColumn},
+	} {
+		_, err := f.service.Import(1, strings.NewReader(file), dto.TaskImportOptions{Format: FormatCSV, Mapping: tt.mapping})
+		assert.ErrorIs(t, err, tt.err, fmt.Sprint(tt.mapping))
+	}
+}
+
+func TestTransferService_Import_Invalid(t *testing.T) {
+	file := "task,due,priority,estimate,status,project\n" +
+		"Fine,,,,,\n" +
+		",,,,,\n" +
+		"A name that is far too long,,,,,\n" +
+		"Bad date,next week,,,,\n" +
+		"Bad values,,critical,,,\n" +
+		"Bad estimate,,,half an hour,,Garden\n" +
+		"Bad status,,,,blocked,\n"
+
+	t.Run("commit imports nothing", func(t *testing.T) {
+		f := setup()
+		resp, err := f.service.Import(1, strings.NewReader(file), dto.TaskImportOptions{Format: FormatCSV})
+		assert.ErrorIs(t, err, ErrInvalidRows)
+		assert.Equal(t, 7, resp.Rows)
+		assert.Equal(t, []dto.TaskImportRowError{
+			{Row: 3, Column: "task", Message: task_service.ErrEmptyTaskName.Error()},
+			{Row: 4, Column: "task", Message: task_service.ErrTaskTooLong.Error()},
+			{Row: 5, Column: "due", Message: `invalid date "next week", expected a date such as 2025-09-01 or 2025-09-01T17:00:00Z`},
+			{Row: 6, Column: "priority", Message: `invalid priority "critical"`},
+			{Row: 7, Column: "estimate", Message: "estimate must be a whole number of minutes"},
+			{Row: 7, Column: "project", Message: `project "Garden" not found`},
+			{Row: 8, Column: "status", Message: task_service.ErrInvalidStatus.Error()},
+		}, resp.Errors)
+		f.tasks.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+
+	t.Run("dry run previews the valid rows", func(t *testing.T) {
+		f := setup()
+		resp, err := f.service.Import(1, strings.NewReader(file), dto.TaskImportOptions{Format: FormatCSV, DryRun: true})
+		require.NoError(t, err)
+		assert.True(t, resp.DryRun)
+		assert.Len(t, resp.Errors, 7)
+		require.Len(t, resp.Preview, 1)
+		assert.Equal(t, "Fine", resp.Preview[0].Task)
+		assert.Zero(t, resp.Imported)
+		assert.Empty(t, resp.Tasks)
+		f.tasks.AssertNotCalled(t, "Import", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTransferService_Import_JSON(t *testing.T) {
+	f := setup()
+	f.tasks.On("Import", 1, mock.Anything).Return([]dto.GetTaskResponse{{ID: 8}, {ID: 9}}, nil)
+
+	export := `{"tasks":[
+		{"id":1,"task":"Buy paint","status":"in-progress","priority":"high","tags":["diy","shop"],"project":"Home Renovation","project_id":2,"estimate_minutes":30,"created_at":"2025-09-01T09:00:00Z"},
+		{"id":3,"task":"Call plumber","status":"completed","completed_at":"2025-09-10T17:00:00Z","recurrence":"FREQ=MONTHLY","position":"i"}
+	]}`
+	resp, err := f.service.Import(1, strings.NewReader(export), dto.TaskImportOptions{Format: FormatJSON})
+	require.NoError(t, err)
+	assert.Equal(t, 2, resp.Imported)
+
+	reqs := f.tasks.Calls[0].Arguments.Get(1).([]dto.ImportTaskRequest)
+	require.Len(t, reqs, 2)
+	project2, estimate := 2, 30
+	assert.Equal(t, dto.ImportTaskRequest{
+		CreateTaskRequest: dto.CreateTaskRequest{Task: "Buy paint", Priority: "high", Tags: []string{"diy", "shop"}, EstimateMinutes: &estimate},
+		Status:            "in-progress",
+		ProjectID:         &project2,
+	}, reqs[0])
+	assert.Equal(t, dto.ImportTaskRequest{
+		CreateTaskRequest: dto.CreateTaskRequest{Task: "Call plumber"},
+		Status:            "completed",
+		CompletedAt:       &due,
+		Recurrence:        "FREQ=MONTHLY",
+	}, reqs[1])
+
+	_, err = f.service.Import(1, strings.NewReader(`[{"task":"A"},{"task":"B"}]`), dto.TaskImportOptions{Format: FormatJSON, DryRun: true})
+	assert.NoError(t, err, "a bare array")
+
+	for _, bad := range []string{`{"items":[]}`, `"tasks"`, `[{"task":"A"},`, `[1]`} {
+		_, err = f.service.Import(1, strings.NewReader(bad), dto.TaskImportOptions{Format: FormatJSON})
+		assert.ErrorIs(t, err, ErrInvalidFile, bad)
+	}
+	_, err = f.service.Import(1, strings.NewReader(`[]`), dto.TaskImportOptions{Format: FormatJSON})
+	assert.ErrorIs(t, err, ErrNoRows)
+}
+
+func TestTransferService_Import_NDJSON(t *testing.T) {
+	f := setup()
+	file := "{\"task\":\"Buy paint\"}\n\n{\"task\":\"\"}\n"
+
+	resp, err := f.service.Import(1, strings.NewReader(file), dto.TaskImportOptions{Format: FormatNDJSON})
+	assert.ErrorIs(t, err, ErrInvalidRows)
+	assert.Equal(t, []dto.TaskImportRowError{{Row: 3, Column: "task", Message: task_service.ErrEmptyTaskName.Error()}}, resp.Errors)
+
+	_, err = f.service.Import(1, strings.NewReader("{\"task\":\"A\"}\nnot json\n"), dto.TaskImportOptions{Format: FormatNDJSON})
+	assert.ErrorIs(t, err, ErrInvalidFile)
+	assert.ErrorContains(t, err, "line 2")
+}
+
+func TestTransferService_Import_Limits(t *testing.T) {
+	f := setup()
+
+	var b strings.Builder
+	b.WriteString("task\n")
+	for i := 0; i <= MaxImportRows; i++ {
+		b.WriteString("Task\n")
+	}
+	_, err := f.service.Import(1, strings.NewReader(b.String()), dto.TaskImportOptions{Format: FormatCSV})
+	assert.ErrorIs(t, err, ErrTooManyRows)
+
+	_, err = f.service.Import(1, strings.NewReader("task,due\nA\n"), dto.TaskImportOptions{Format: FormatCSV})
+	assert.ErrorIs(t, err, ErrInvalidFile)
+	_, err = f.service.Import(1, strings.NewReader("task\n"), dto.TaskImportOptions{Format: FormatCSV})
+	assert.ErrorIs(t, err, ErrNoRows)
+	_, err = f.service.Import(1, strings.NewReader("# Tasks"), dto.TaskImportOptions{Format: FormatMarkdown})
+	assert.ErrorIs(t, err, ErrUnknownImportFormat)
+	_, err = f.service.Import(0, strings.NewReader("task\nA\n"), dto.TaskImportOptions{Format: FormatCSV})
+	assert.ErrorIs(t, err, ErrInvalidUser)
+}
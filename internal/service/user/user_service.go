@@ -4,9 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"taskflow/internal/domain/user"
 	"taskflow/internal/dto"
+	"taskflow/internal/events"
 	"taskflow/internal/repository/gorm/gorm_user"
 	"taskflow/pkg/jwt"
 	"taskflow/pkg/validator"
@@ -15,6 +17,8 @@ import (
 	"gorm.io/gorm"
 )
 
+var ErrInvalidTimeZone = errors.New("unknown time zone: use an IANA name such as Europe/Berlin")
+
 type UserService struct {
 	repo      gorm_user.UserRepositoryInterface
 	jwtSecret []byte
@@ -129,7 +133,14 @@ func (s *UserService) DeleteUser(req *dto.DeleteUserRequest) (*dto.DeleteUserRes
 		return nil, errors.New("user ID is required")
 	}
 
-	if err := s.repo.Delete(req.ID); err != nil {
+	// Other services clean up the user's data when they see the event.
+	err := s.repo.Transaction(func(repo gorm_user.UserRepositoryInterface) error {
+		if err := repo.Delete(req.ID); err != nil {
+			return err
+		}
+		return repo.AddEvents(events.UserDeleted{UserID: req.ID})
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to delete user: %w", err)
 	}
 
@@ -138,6 +149,31 @@ func (s *UserService) DeleteUser(req *dto.DeleteUserRequest) (*dto.DeleteUserRes
 	}, nil
 }
 
+// UpdateTimeZone sets the IANA time zone used for the user's statistics.
+func (s *UserService) UpdateTimeZone(req *dto.UpdateTimeZoneRequest) (*dto.UpdateTimeZoneResponse, error) {
+	if req.ID == 0 {
+		return nil, errors.New("user ID is required")
+	}
+
+	// time.LoadLocation also accepts "" and "Local", which mean nothing to clients
+	loc, err := time.LoadLocation(req.TimeZone)
+	if err != nil || req.TimeZone == "" || req.TimeZone == "Local" {
+		return nil, ErrInvalidTimeZone
+	}
+
+	u, err := s.repo.GetByID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	u.TimeZone = loc.String()
+	if err := s.repo.Update(u); err != nil {
+		return nil, fmt.Errorf("failed to update time zone: %w", err)
+	}
+
+	return &dto.UpdateTimeZoneResponse{TimeZone: u.TimeZone}, nil
+}
+
 func HashPassword(p string) (string, error) {
 	b, err := bcrypt.GenerateFromPassword([]byte(p), bcrypt.DefaultCost)
 	return string(b), err
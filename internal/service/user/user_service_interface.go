@@ -7,4 +7,5 @@ type UserServiceInterface interface {
 	AuthenticateUser(req *dto.AuthRequest) (*dto.AuthResponse, error)
 	UpdatePassword(req *dto.UpdatePasswordRequest) (*dto.UpdatePasswordResponse, error)
 	DeleteUser(req *dto.DeleteUserRequest) (*dto.DeleteUserResponse, error)
+	UpdateTimeZone(req *dto.UpdateTimeZoneRequest) (*dto.UpdateTimeZoneResponse, error)
 }
@@ -49,3 +49,12 @@ func (m *UserServiceMock) DeleteUser(req *dto.DeleteUserRequest) (*dto.DeleteUse
 	}
 	return resp, args.Error(1)
 }
+
+func (m *UserServiceMock) UpdateTimeZone(req *dto.UpdateTimeZoneRequest) (*dto.UpdateTimeZoneResponse, error) {
+	args := m.Called(req)
+	var resp *dto.UpdateTimeZoneResponse
+	if r := args.Get(0); r != nil {
+		resp = r.(*dto.UpdateTimeZoneResponse)
+	}
+	return resp, args.Error(1)
+}
@@ -6,6 +6,7 @@ import (
 
 	"taskflow/internal/domain/user"
 	"taskflow/internal/dto"
+	"taskflow/internal/events"
 	"taskflow/internal/repository/gorm/gorm_user"
 
 	"github.com/stretchr/testify/mock"
@@ -198,10 +199,21 @@ func TestDeleteUser(t *testing.T) {
 			name: "success",
 			req:  &dto.DeleteUserRequest{ID: 1},
 			mockSetup: func(m *gorm_user.MockUserRepository) {
+				m.On("Transaction", mock.Anything).Return(nil).Once()
 				m.On("Delete", 1).Return(nil).Once()
+				m.On("AddEvents", []events.Event{events.UserDeleted{UserID: 1}}).Return(nil).Once()
 			},
 			wantErr: false,
 		},
+		{
+			name: "delete fails",
+			req:  &dto.DeleteUserRequest{ID: 1},
+			mockSetup: func(m *gorm_user.MockUserRepository) {
+				m.On("Transaction", mock.Anything).Return(nil).Once()
+				m.On("Delete", 1).Return(errors.New("db down")).Once()
+			},
+			wantErr: true,
+		},
 		{
 			name:    "missing ID",
 			req:     &dto.DeleteUserRequest{ID: 0},
@@ -231,3 +243,54 @@ func TestDeleteUser(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateTimeZone(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       *dto.UpdateTimeZoneRequest
+		mockSetup func(m *gorm_user.MockUserRepository)
+		wantErr   error
+	}{
+		{
+			name: "success",
+			req:  &dto.UpdateTimeZoneRequest{ID: 1, TimeZone: "Europe/Berlin"},
+			mockSetup: func(m *gorm_user.MockUserRepository) {
+				m.On("GetByID", 1).Return(&user.User{ID: 1, TimeZone: "UTC"}, nil).Once()
+				m.On("Update", mock.MatchedBy(func(u *user.User) bool {
+					return u.ID == 1 && u.TimeZone == "Europe/Berlin"
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:    "unknown zone",
+			req:     &dto.UpdateTimeZoneRequest{ID: 1, TimeZone: "Mars/Olympus"},
+			wantErr: ErrInvalidTimeZone,
+		},
+		{
+			name:    "local is not a zone",
+			req:     &dto.UpdateTimeZoneRequest{ID: 1, TimeZone: "Local"},
+			wantErr: ErrInvalidTimeZone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(gorm_user.MockUserRepository)
+			svc := NewUserService(mockRepo, "secret")
+
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			resp, err := svc.UpdateTimeZone(tt.req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				require.Nil(t, resp)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, "Europe/Berlin", resp.TimeZone)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
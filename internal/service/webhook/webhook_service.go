@@ -0,0 +1,489 @@
+package webhook_service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskflow/internal/domain/webhook"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_webhook"
+	"taskflow/internal/scheduler"
+
+	"gorm.io/gorm"
+)
+
+// KindDeliver is the scheduler job kind that sends one delivery.
+const KindDeliver = "webhooks.deliver"
+
+// MaxAttempts is how often a delivery is tried. With the scheduler's
+// backoff the last try happens a little over an hour after the first.
+const MaxAttempts = 8
+
+// DisableAfter is the number of failed attempts in a row after which an
+// endpoint is disabled.
+const DisableAfter = 20
+
+var (
+	ErrInvalidUser = errors.New("invalid user")
+	ErrInvalidURL  = errors.New("url must be an absolute http or https URL")
+	// ErrPrivateAddress is returned for URLs of loopback, private or
+	// link-local addresses, such as a cloud metadata service.
+	ErrPrivateAddress = errors.New("url must point to a public address")
+	ErrInvalidEvent   = errors.New("unknown webhook event")
+	// ErrDisabled is returned when redelivering to a disabled endpoint.
+	ErrDisabled = errors.New("webhook is disabled; re-enable it first")
+)
+
+// job is the payload of a KindDeliver job.
+type job struct {
+	DeliveryID     int `json:"delivery_id"`
+	SubscriptionID int `json:"webhook_id"`
+	UserID         int `json:"user_id"`
+}
+
+// payload is the JSON body sent to receivers.
+type payload struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type WebhookService struct {
+	repo         gorm_webhook.WebhookRepositoryInterface
+	queue        scheduler.Queue
+	client       *http.Client
+	disableAfter int
+	now          func() time.Time
+}
+
+func NewWebhookService(repo gorm_webhook.WebhookRepositoryInterface, queue scheduler.Queue) *WebhookService {
+	return &WebhookService{
+		repo:         repo,
+		queue:        queue,
+		client:       newClient(),
+		disableAfter: DisableAfter,
+		now:          time.Now,
+	}
+}
+
+var _ WebhookServiceInterface = (*WebhookService)(nil)
+
+// Create registers an endpoint and returns it with its signing secret,
+// which is not shown again.
+func (s *WebhookService) Create(userID int, req *dto.WebhookRequest) (dto.WebhookResponse, error) {
+	if userID == 0 {
+		return dto.WebhookResponse{}, ErrInvalidUser
+	}
+	if err := validate(req); err != nil {
+		return dto.WebhookResponse{}, err
+	}
+
+	secret, err := randomHex(16)
+	if err != nil {
+		return dto.WebhookResponse{}, err
+	}
+	sub := webhook.Subscription{
+		UserID: userID,
+		URL:    req.URL,
+		Secret: "whsec_" + secret,
+		Events: req.Events,
+		Active: true,
+	}
+	if err := s.repo.CreateSubscription(&sub); err != nil {
+		return dto.WebhookResponse{}, err
+	}
+
+	resp := toWebhookResponse(sub)
+	resp.Secret = sub.Secret
+	return resp, nil
+}
+
+func (s *WebhookService) Get(userID int, id int) (dto.WebhookResponse, error) {
+	if userID == 0 {
+		return dto.WebhookResponse{}, ErrInvalidUser
+	}
+
+	sub, err := s.repo.GetSubscription(userID, id)
+	if err != nil {
+		return dto.WebhookResponse{}, err
+	}
+	return toWebhookResponse(*sub), nil
+}
+
+func (s *WebhookService) List(userID int) (dto.ListWebhooksResponse, error) {
+	if userID == 0 {
+		return dto.ListWebhooksResponse{}, ErrInvalidUser
+	}
+
+	subs, err := s.repo.ListSubscriptions(userID)
+	if err != nil {
+		return dto.ListWebhooksResponse{}, err
+	}
+	resp := dto.ListWebhooksResponse{Webhooks: make([]dto.WebhookResponse, 0, len(subs))}
+	for _, sub := range subs {
+		resp.Webhooks = append(resp.Webhooks, toWebhookResponse(sub))
+	}
+	return resp, nil
+}
+
+// Update changes an endpoint's URL and events. Setting Active re-enables a
+// disabled endpoint, with a clean failure count, or disables it.
+func (s *WebhookService) Update(userID int, id int, req *dto.WebhookRequest) (dto.WebhookResponse, error) {
+	if userID == 0 {
+		return dto.WebhookResponse{}, ErrInvalidUser
+	}
+	if err := validate(req); err != nil {
+		return dto.WebhookResponse{}, err
+	}
+
+	sub, err := s.repo.GetSubscription(userID, id)
+	if err != nil {
+		return dto.WebhookResponse{}, err
+	}
+	sub.URL = req.URL
+	sub.Events = req.Events
+	if req.Active != nil && *req.Active != sub.Active {
+		sub.Active = *req.Active
+		sub.Failures = 0
+		sub.DisabledAt = nil
+		if !sub.Active {
+			now := s.now()
+			sub.DisabledAt = &now
+		}
+	}
+	if err := s.repo.UpdateSubscription(sub); err != nil {
+		return dto.WebhookResponse{}, err
+	}
+	sub.UpdatedAt = s.now()
+	return toWebhookResponse(*sub), nil
+}
+
+func (s *WebhookService) Delete(userID int, id int) error {
+	if userID == 0 {
+		return ErrInvalidUser
+	}
+	return s.repo.DeleteSubscription(userID, id)
+}
+
+func (s *WebhookService) ListDeliveries(userID int, id int, page int, limit int) (dto.ListWebhookDeliveriesResponse, error) {
+	if userID == 0 {
+		return dto.ListWebhookDeliveriesResponse{}, ErrInvalidUser
+	}
+
+	if _, err := s.repo.GetSubscription(userID, id); err != nil {
+		return dto.ListWebhookDeliveriesResponse{}, err
+	}
+	deliveries, total, err := s.repo.ListDeliveries(id, (page-1)*limit, limit)
+	if err != nil {
+		return dto.ListWebhookDeliveriesResponse{}, err
+	}
+
+	resp := dto.ListWebhookDeliveriesResponse{
+		Deliveries: make([]dto.WebhookDeliveryResponse, 0, len(deliveries)),
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+	}
+	for _, d := range deliveries {
+		resp.Deliveries = append(resp.Deliveries, toDeliveryResponse(d))
+	}
+	return resp, nil
+}
+
+// Redeliver queues a new delivery with the same event and payload as an
+// earlier one. Receivers see the same event ID and can ignore repeats.
+func (s *WebhookService) Redeliver(userID int, id int, deliveryID int) (dto.WebhookDeliveryResponse, error) {
+	if userID == 0 {
+		return dto.WebhookDeliveryResponse{}, ErrInvalidUser
+	}
+
+	sub, err := s.repo.GetSubscription(userID, id)
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	if !sub.Active {
+		return dto.WebhookDeliveryResponse{}, ErrDisabled
+	}
+	orig, err := s.repo.GetDelivery(id, deliveryID)
+	if err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+
+	d := webhook.Delivery{
+		SubscriptionID: sub.ID,
+		EventID:        orig.EventID,
+		Event:          orig.Event,
+		Payload:        orig.Payload,
+		Status:         webhook.StatusPending,
+		RedeliveryOf:   &orig.ID,
+	}
+	if err := s.enqueue(sub, &d); err != nil {
+		return dto.WebhookDeliveryResponse{}, err
+	}
+	return toDeliveryResponse(d), nil
+}
+
+// HandleEvent consumes domain events. Task events are delivered to the
+// user's endpoints under the domain event's ID; deleting a user deletes
+// their webhooks.
+func (s *WebhookService) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	if e.Type == events.TypeUserDeleted {
+		return s.repo.DeleteUserSubscriptions(e.UserID)
+	}
+	if !webhook.ValidEvent(e.Type) {
+		return nil
+	}
+
+	var ev struct {
+		Task dto.GetTaskResponse `json:"task"`
+		From string              `json:"from"`
+	}
+	if err := e.Decode(&ev); err != nil {
+		return err
+	}
+	return s.dispatch(e.UserID, e.ID, e.Type, e.OccurredAt, dto.TaskEventData{Task: ev.Task, PreviousStatus: ev.From})
+}
+
+// dispatch queues a delivery of an event to every active endpoint of the
+// user that listens to it. Events are delivered at least once, so an event
+// that already has a delivery to an endpoint only has its job queued again,
+// which the job's dedup key turns into a no-op unless the job was lost.
+func (s *WebhookService) dispatch(userID int, eventID string, event string, at time.Time, data any) error {
+	subs, err := s.repo.ListActive(userID)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	for i := range subs {
+		if !subs[i].Subscribed(event) {
+			continue
+		}
+
+		d, err := s.repo.GetEventDelivery(subs[i].ID, eventID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if body == nil {
+				body, err = json.Marshal(payload{ID: eventID, Event: event, CreatedAt: at.UTC(), Data: data})
+				if err != nil {
+					return err
+				}
+			}
+			d = &webhook.Delivery{
+				SubscriptionID: subs[i].ID,
+				EventID:        eventID,
+				Event:          event,
+				Payload:        string(body),
+				Status:         webhook.StatusPending,
+			}
+			if err := s.repo.CreateDelivery(d); err != nil {
+				return err
+			}
+		} else if err != nil {
+			return err
+		}
+
+		if err := s.queueDelivery(&subs[i], d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *WebhookService) enqueue(sub *webhook.Subscription, d *webhook.Delivery) error {
+	if err := s.repo.CreateDelivery(d); err != nil {
+		return err
+	}
+	return s.queueDelivery(sub, d)
+}
+
+func (s *WebhookService) queueDelivery(sub *webhook.Subscription, d *webhook.Delivery) error {
+	j, err := scheduler.NewJob(KindDeliver, "webhook:"+strconv.Itoa(d.ID), s.now(), job{
+		DeliveryID:     d.ID,
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+	})
+	if err != nil {
+		return err
+	}
+	j.MaxAttempts = MaxAttempts
+	_, err = s.queue.Enqueue(j)
+	return err
+}
+
+// Deliver sends one delivery and logs the outcome. Failed attempts return
+// an error so that the scheduler retries them with backoff, until the last
+// attempt marks the delivery failed. Deliveries to endpoints that were
+// deleted or disabled in the meantime are dropped.
+func (s *WebhookService) Deliver(ctx context.Context, j *scheduler.Job) error {
+	var p job
+	if err := j.Decode(&p); err != nil {
+		return err
+	}
+
+	sub, err := s.repo.GetSubscription(p.UserID, p.SubscriptionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	d, err := s.repo.GetDelivery(sub.ID, p.DeliveryID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if d.Status != webhook.StatusPending {
+		return nil
+	}
+	if !sub.Active {
+		d.Status = webhook.StatusFailed
+		d.Error = "webhook is disabled"
+		return s.repo.UpdateDelivery(d)
+	}
+
+	sendErr := s.send(ctx, sub, d)
+	if sendErr == nil {
+		d.Status = webhook.StatusSucceeded
+		if err := s.repo.UpdateDelivery(d); err != nil {
+			return err
+		}
+		if sub.Failures > 0 {
+			return s.repo.ResetFailures(sub.ID)
+		}
+		return nil
+	}
+
+	d.Error = sendErr.Error()
+	failures, err := s.repo.RecordFailure(sub.ID)
+	if err != nil {
+		return err
+	}
+	disabled := failures >= s.disableAfter
+	if disabled {
+		if err := s.repo.Disable(sub.ID, s.now()); err != nil {
+			return err
+		}
+	}
+	if disabled || j.Attempts >= j.MaxAttempts {
+		d.Status = webhook.StatusFailed
+	}
+	if err := s.repo.UpdateDelivery(d); err != nil {
+		return err
+	}
+	if disabled {
+		return nil
+	}
+	return sendErr
+}
+
+// send makes one attempt and records its result on d.
+func (s *WebhookService) send(ctx context.Context, sub *webhook.Subscription, d *webhook.Delivery) error {
+	now := s.now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus = 0
+	d.Error = ""
+
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ts := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TaskFlow-Webhooks/1.0")
+	req.Header.Set(webhook.EventHeader, d.Event)
+	req.Header.Set(webhook.DeliveryHeader, strconv.Itoa(d.ID))
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(sub.Secret, ts, body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	d.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		return err
+	}
+	// Only the status is kept; the body could be anything the receiver,
+	// or whatever it redirects to, chooses to reveal.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	d.ResponseStatus = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return nil
+}
+
+func validate(req *dto.WebhookRequest) error {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	// Names are checked again when a delivery connects; addresses and
+	// localhost can be refused right away.
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); (err == nil && !isPublic(ip)) || strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return ErrPrivateAddress
+	}
+	for _, e := range req.Events {
+		if !webhook.ValidEvent(e) {
+			return fmt.Errorf("%w %q", ErrInvalidEvent, e)
+		}
+	}
+	return nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func toWebhookResponse(s webhook.Subscription) dto.WebhookResponse {
+	return dto.WebhookResponse{
+		ID:         s.ID,
+		URL:        s.URL,
+		Events:     s.Events,
+		Active:     s.Active,
+		Failures:   s.Failures,
+		DisabledAt: s.DisabledAt,
+		CreatedAt:  s.CreatedAt,
+		UpdatedAt:  s.UpdatedAt,
+	}
+}
+
+func toDeliveryResponse(d webhook.Delivery) dto.WebhookDeliveryResponse {
+	return dto.WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.SubscriptionID,
+		EventID:        d.EventID,
+		Event:          d.Event,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		ResponseStatus: d.ResponseStatus,
+		Error:          d.Error,
+		DurationMS:     d.DurationMS,
+		RedeliveryOf:   d.RedeliveryOf,
+		LastAttemptAt:  d.LastAttemptAt,
+		CreatedAt:      d.CreatedAt,
+	}
+}
@@ -0,0 +1,23 @@
+package webhook_service
+
+import (
+	"context"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/scheduler"
+)
+
+type WebhookServiceInterface interface {
+	Create(userID int, req *dto.WebhookRequest) (dto.WebhookResponse, error)
+	Get(userID int, id int) (dto.WebhookResponse, error)
+	List(userID int) (dto.ListWebhooksResponse, error)
+	Update(userID int, id int, req *dto.WebhookRequest) (dto.WebhookResponse, error)
+	Delete(userID int, id int) error
+	ListDeliveries(userID int, id int, page int, limit int) (dto.ListWebhookDeliveriesResponse, error)
+	Redeliver(userID int, id int, deliveryID int) (dto.WebhookDeliveryResponse, error)
+	// HandleEvent consumes domain events.
+	HandleEvent(ctx context.Context, e *events.Envelope) error
+	// Deliver handles KindDeliver jobs.
+	Deliver(ctx context.Context, job *scheduler.Job) error
+}
@@ -0,0 +1,62 @@
+package webhook_service
+
+import (
+	"context"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/scheduler"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type WebhookServiceMock struct {
+	mock.Mock
+}
+
+var _ WebhookServiceInterface = (*WebhookServiceMock)(nil)
+
+func (m *WebhookServiceMock) Create(userID int, req *dto.WebhookRequest) (dto.WebhookResponse, error) {
+	args := m.Called(userID, req)
+	return args.Get(0).(dto.WebhookResponse), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Get(userID int, id int) (dto.WebhookResponse, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(dto.WebhookResponse), args.Error(1)
+}
+
+func (m *WebhookServiceMock) List(userID int) (dto.ListWebhooksResponse, error) {
+	args := m.Called(userID)
+	return args.Get(0).(dto.ListWebhooksResponse), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Update(userID int, id int, req *dto.WebhookRequest) (dto.WebhookResponse, error) {
+	args := m.Called(userID, id, req)
+	return args.Get(0).(dto.WebhookResponse), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Delete(userID int, id int) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) ListDeliveries(userID int, id int, page int, limit int) (dto.ListWebhookDeliveriesResponse, error) {
+	args := m.Called(userID, id, page, limit)
+	return args.Get(0).(dto.ListWebhookDeliveriesResponse), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Redeliver(userID int, id int, deliveryID int) (dto.WebhookDeliveryResponse, error) {
+	args := m.Called(userID, id, deliveryID)
+	return args.Get(0).(dto.WebhookDeliveryResponse), args.Error(1)
+}
+
+func (m *WebhookServiceMock) HandleEvent(ctx context.Context, e *events.Envelope) error {
+	args := m.Called(e)
+	return args.Error(0)
+}
+
+func (m *WebhookServiceMock) Deliver(ctx context.Context, job *scheduler.Job) error {
+	args := m.Called(job)
+	return args.Error(0)
+}
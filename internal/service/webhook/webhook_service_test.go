@@ -0,0 +1,357 @@
+package webhook_service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/webhook"
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/internal/repository/gorm/gorm_webhook"
+	"taskflow/internal/scheduler"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var now = time.Date(2025, 9, 8, 12, 0, 0, 0, time.UTC)
+
+func TestWebhookService_Create(t *testing.T) {
+	repo := new(gorm_webhook.WebhookRepoMock)
+	repo.On("CreateSubscription", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*webhook.Subscription).ID = 3
+	}).Return(nil)
+	s := NewWebhookService(repo, nil)
+
+	resp, err := s.Create(1, &dto.WebhookRequest{URL: "https://example.com/hook", Events: []string{webhook.EventTaskCreated}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, resp.ID)
+	assert.True(t, resp.Active)
+	assert.Regexp(t, `^whsec_[0-9a-f]{32}$`, resp.Secret)
+
+	_, err = s.Create(1, &dto.WebhookRequest{URL: "ftp://example.com/hook", Events: []string{webhook.EventTaskCreated}})
+	assert.ErrorIs(t, err, ErrInvalidURL)
+	for _, u := range []string{"http://127.0.0.1:8080/hook", "http://169.254.169.254/latest/meta-data", "http://[::1]/hook", "http://10.0.0.5/hook", "http://localhost/hook"} {
+		_, err = s.Create(1, &dto.WebhookRequest{URL: u, Events: []string{webhook.EventTaskCreated}})
+		assert.ErrorIs(t, err, ErrPrivateAddress, u)
+	}
+	_, err = s.Create(1, &dto.WebhookRequest{URL: "https://example.com/hook", Events: []string{"task.exploded"}})
+	assert.ErrorIs(t, err, ErrInvalidEvent)
+	_, err = s.Create(0, &dto.WebhookRequest{URL: "https://example.com/hook", Events: []string{webhook.EventTaskCreated}})
+	assert.ErrorIs(t, err, ErrInvalidUser)
+	repo.AssertNumberOfCalls(t, "CreateSubscription", 1)
+}
+
+func TestWebhookService_Update(t *testing.T) {
+	disabledAt := now.Add(-time.Hour)
+	repo := new(gorm_webhook.WebhookRepoMock)
+	repo.On("GetSubscription", 1, 3).Return(&webhook.Subscription{ID: 3, UserID: 1, Active: false, Failures: 20, DisabledAt: &disabledAt}, nil)
+	repo.On("UpdateSubscription", mock.MatchedBy(func(sub *webhook.Subscription) bool {
+		return sub.Active && sub.Failures == 0 && sub.DisabledAt == nil && sub.URL == "https://example.com/new"
+	})).Return(nil)
+
+	s := NewWebhookService(repo, nil)
+	active := true
+	resp, err := s.Update(1, 3, &dto.WebhookRequest{URL: "https://example.com/new", Events: []string{webhook.EventTaskDeleted}, Active: &active})
+	require.NoError(t, err)
+	assert.True(t, resp.Active)
+	assert.Empty(t, resp.Secret, "the secret is only shown on create")
+	repo.AssertExpectations(t)
+}
+
+func taskEvent(t *testing.T, e events.Event) *events.Envelope {
+	rec, err := events.NewRecord(e, now)
+	require.NoError(t, err)
+	env := rec.Envelope()
+	return &env
+}
+
+func TestWebhookService_HandleEvent(t *testing.T) {
+	repo := new(gorm_webhook.WebhookRepoMock)
+	repo.On("ListActive", 1).Return([]webhook.Subscription{
+		{ID: 3, UserID: 1, Events: []string{webhook.EventTaskStatusChanged, webhook.EventTaskDeleted}, Active: true},
+		{ID: 4, UserID: 1, Events: []string{webhook.EventTaskDeleted}, Active: true},
+		{ID: 5, UserID: 1, Events: []string{webhook.EventTaskStatusChanged}, Active: true},
+	}, nil)
+	repo.On("GetEventDelivery", mock.Anything, mock.Anything).Return((*webhook.Delivery)(nil), gorm.ErrRecordNotFound)
+
+	var deliveries []*webhook.Delivery
+	repo.On("CreateDelivery", mock.Anything).Run(func(args mock.Arguments) {
+		d := args.Get(0).(*webhook.Delivery)
+		d.ID = 10 + len(deliveries)
+		deliveries = append(deliveries, d)
+	}).Return(nil)
+
+	var jobs []*scheduler.Job
+	queue := new(scheduler.QueueMock)
+	queue.On("Enqueue", mock.Anything).Run(func(args mock.Arguments) {
+		jobs = append(jobs, args.Get(0).(*scheduler.Job))
+	}).Return(true, nil)
+
+	s := NewWebhookService(repo, queue)
+	e := taskEvent(t, events.StatusChanged{UserID: 1, Task: dto.GetTaskResponse{ID: 7, Task: "Pay rent", Status: "completed"}, From: "pending"})
+	require.NoError(t, s.HandleEvent(context.Background(), e))
+
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, 3, deliveries[0].SubscriptionID)
+	assert.Equal(t, 5, deliveries[1].SubscriptionID)
+	assert.Equal(t, e.ID, deliveries[0].EventID, "deliveries carry the domain event ID")
+	assert.Equal(t, e.ID, deliveries[1].EventID)
+	assert.Equal(t, webhook.StatusPending, deliveries[0].Status)
+
+	var body struct {
+		ID        string    `json:"id"`
+		Event     string    `json:"event"`
+		CreatedAt time.Time `json:"created_at"`
+		Data      struct {
+			Task struct {
+				ID int `json:"id"`
+			} `json:"task"`
+			PreviousStatus string `json:"previous_status"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(deliveries[0].Payload), &body))
+	assert.Equal(t, e.ID, body.ID)
+	assert.Equal(t, webhook.EventTaskStatusChanged, body.Event)
+	assert.Equal(t, now, body.CreatedAt)
+	assert.Equal(t, 7, body.Data.Task.ID)
+	assert.Equal(t, "pending", body.Data.PreviousStatus)
+
+	require.Len(t, jobs, 2)
+	assert.Equal(t, KindDeliver, jobs[0].Kind)
+	assert.Equal(t, "webhook:10", jobs[0].DedupKey)
+	assert.Equal(t, MaxAttempts, jobs[0].MaxAttempts)
+	assert.JSONEq(t, `{"delivery_id":10,"webhook_id":3,"user_id":1}`, jobs[0].Payload)
+}
+
+func TestWebhookService_HandleEventTwice(t *testing.T) {
+	e := taskEvent(t, events.TaskCreated{UserID: 1, Task: dto.GetTaskResponse{ID: 7}})
+
+	repo := new(gorm_webhook.WebhookRepoMock)
+	repo.On("ListActive", 1).Return([]webhook.Subscription{
+		{ID: 3, UserID: 1, Events: []string{webhook.EventTaskCreated}, Active: true},
+	}, nil)
+	repo.On("GetEventDelivery", 3, e.ID).Return(&webhook.Delivery{ID: 10, SubscriptionID: 3, EventID: e.ID}, nil)
+	queue := new(scheduler.QueueMock)
+	queue.On("Enqueue", mock.MatchedBy(func(j *scheduler.Job) bool { return j.DedupKey == "webhook:10" })).Return(false, nil)
+
+	s := NewWebhookService(repo, queue)
+	require.NoError(t, s.HandleEvent(context.Background(), e))
+
+	repo.AssertNotCalled(t, "CreateDelivery", mock.Anything)
+	queue.AssertExpectations(t)
+}
+
+func TestWebhookService_HandleUserDeleted(t *testing.T) {
+	repo := new(gorm_webhook.WebhookRepoMock)
+	repo.On("DeleteUserSubscriptions", 1).Return(nil)
+
+	s := NewWebhookService(repo, nil)
+	require.NoError(t, s.HandleEvent(context.Background(), taskEvent(t, events.UserDeleted{UserID: 1})))
+	repo.AssertExpectations(t)
+}
+
+func TestWebhookService_Deliver(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       int
+		active       bool
+		failures     int
+		attempt      int
+		wantErr      bool
+		wantStatus   string
+		wantRequest  bool
+		wantDisabled bool
+		wantReset    bool
+	}{
+		{name: "success", status: http.StatusAccepted, active: true, attempt: 1, wantStatus: webhook.StatusSucceeded, wantRequest: true},
+		{name: "success resets the failure count", status: http.StatusOK, active: true, failures: 3, attempt: 2, wantStatus: webhook.StatusSucceeded, wantRequest: true, wantReset: true},
+		{name: "failure is retried", status: http.StatusInternalServerError, active: true, attempt: 1, wantErr: true, wantStatus: webhook.StatusPending, wantRequest: true},
+		{name: "last attempt fails the delivery", status: http.StatusInternalServerError, active: true, attempt: MaxAttempts, wantErr: true, wantStatus: webhook.StatusFailed, wantRequest: true},
+		{name: "endpoint that keeps failing is disabled", status: http.StatusBadGateway, active: true, failures: DisableAfter - 1, attempt: 1, wantStatus: webhook.StatusFailed, wantRequest: true, wantDisabled: true},
+		{name: "disabled endpoint is not called", active: false, attempt: 1, wantStatus: webhook.StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, `{"event":"task.created"}`, string(body))
+				assert.Equal(t, webhook.EventTaskCreated, r.Header.Get(webhook.EventHeader))
+				assert.Equal(t, "10", r.Header.Get(webhook.DeliveryHeader))
+				assert.NoError(t, webhook.Verify("whsec_test", r.Header.Get(webhook.TimestampHeader), r.Header.Get(webhook.SignatureHeader), body, now, time.Minute))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("thanks"))
+			}))
+			defer receiver.Close()
+
+			sub := &webhook.Subscription{ID: 3, UserID: 1, URL: receiver.URL, Secret: "whsec_test", Active: tt.active, Failures: tt.failures}
+			d := &webhook.Delivery{ID: 10, SubscriptionID: 3, EventID: "e1", Event: webhook.EventTaskCreated, Payload: `{"event":"task.created"}`, Status: webhook.StatusPending, Attempts: tt.attempt - 1}
+
+			repo := new(gorm_webhook.WebhookRepoMock)
+			repo.On("GetSubscription", 1, 3).Return(sub, nil)
+			repo.On("GetDelivery", 3, 10).Return(d, nil)
+			repo.On("UpdateDelivery", d).Return(nil)
+			repo.On("RecordFailure", 3).Return(tt.failures+1, nil)
+			repo.On("ResetFailures", 3).Return(nil)
+			repo.On("Disable", 3, now).Return(nil)
+
+			s := NewWebhookService(repo, nil)
+			s.now = func() time.Time { return now }
+			s.client = receiver.Client()
+
+			job, err := scheduler.NewJob(KindDeliver, "webhook:10", now, map[string]int{"delivery_id": 10, "webhook_id": 3, "user_id": 1})
+			require.NoError(t, err)
+			job.Attempts, job.MaxAttempts = tt.attempt, MaxAttempts
+
+			err = s.Deliver(context.Background(), job)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tt.wantStatus, d.Status)
+			if tt.wantRequest {
+				assert.Equal(t, 1, requests)
+				assert.Equal(t, tt.attempt, d.Attempts)
+				assert.Equal(t, tt.status, d.ResponseStatus)
+			} else {
+				assert.Zero(t, requests)
+			}
+			if tt.wantDisabled {
+				repo.AssertCalled(t, "Disable", 3, now)
+			} else {
+				repo.AssertNotCalled(t, "Disable", mock.Anything, mock.Anything)
+			}
+			if tt.wantReset {
+				repo.AssertCalled(t, "ResetFailures", 3)
+			} else {
+				repo.AssertNotCalled(t, "ResetFailures", mock.Anything)
+			}
+		})
+	}
+}
+
+func TestWebhookService_DeliverDropsDeletedEndpoints(t *testing.T) {
+	repo := new(gorm_webhook.WebhookRepoMock)
+	repo.On("GetSubscription", 1, 3).Return((*webhook.Subscription)(nil), gorm.ErrRecordNotFound)
+
+	s := NewWebhookService(repo, nil)
+	job, err := scheduler.NewJob(KindDeliver, "webhook:10", now, map[string]int{"delivery_id": 10, "webhook_id": 3, "user_id": 1})
+	require.NoError(t, err)
+	assert.NoError(t, s.Deliver(context.Background(), job))
+}
+
+func TestWebhookService_Redeliver(t *testing.T) {
+	repo := new(gorm_webhook.WebhookRepoMock)
+	repo.On("GetSubscription", 1, 3).Return(&webhook.Subscription{ID: 3, UserID: 1, Active: true}, nil)
+	repo.On("GetSubscription", 1, 4).Return(&webhook.Subscription{ID: 4, UserID: 1, Active: false}, nil)
+	repo.On("GetDelivery", 3, 10).Return(&webhook.Delivery{ID: 10, SubscriptionID: 3, EventID: "e1", Event: webhook.EventTaskCreated, Payload: `{"id":"e1"}`, Status: webhook.StatusFailed, Attempts: 8}, nil)
+	repo.On("CreateDelivery", mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*webhook.Delivery).ID = 11
+	}).Return(nil)
+	queue := new(scheduler.QueueMock)
+	queue.On("Enqueue", mock.MatchedBy(func(j *scheduler.Job) bool { return j.DedupKey == "webhook:11" })).Return(true, nil)
+
+	s := NewWebhookService(repo, queue)
+	resp, err := s.Redeliver(1, 3, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 11, resp.ID)
+	assert.Equal(t, "e1", resp.EventID)
+	assert.Equal(t, webhook.StatusPending, resp.Status)
+	assert.Zero(t, resp.Attempts)
+	assert.Equal(t, 10, *resp.RedeliveryOf)
+	assert.JSONEq(t, `{"id":"e1"}`, string(resp.Payload))
+
+	_, err = s.Redeliver(1, 4, 10)
+	assert.ErrorIs(t, err, ErrDisabled)
+	queue.AssertExpectations(t)
+}
+
+func TestWebhookService_Deliver_PrivateAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		client  func(*httptest.Server) *http.Client
+		wantErr string
+	}{
+		{
+			name:    "loopback receiver",
+			handler: func(w http.ResponseWriter, r *http.Request) {},
+			client:  func(*httptest.Server) *http.Client { return newClient() },
+			wantErr: "public address",
+		},
+		{
+			name: "redirect is not followed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+			},
+			client: func(srv *httptest.Server) *http.Client {
+				c := newClient()
+				c.Transport = srv.Client().Transport
+				return c
+			},
+			wantErr: "receiver answered 302 Found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := httptest.NewServer(tt.handler)
+			defer receiver.Close()
+
+			sub := &webhook.Subscription{ID: 3, UserID: 1, URL: receiver.URL, Secret: "whsec_test", Active: true}
+			d := &webhook.Delivery{ID: 10, SubscriptionID: 3, Event: webhook.EventTaskCreated, Payload: `{}`, Status: webhook.StatusPending}
+			repo := new(gorm_webhook.WebhookRepoMock)
+			repo.On("GetSubscription", 1, 3).Return(sub, nil)
+			repo.On("GetDelivery", 3, 10).Return(d, nil)
+			repo.On("UpdateDelivery", d).Return(nil)
+			repo.On("RecordFailure", 3).Return(1, nil)
+
+			s := NewWebhookService(repo, nil)
+			s.now = func() time.Time { return now }
+			s.client = tt.client(receiver)
+
+			job, err := scheduler.NewJob(KindDeliver, "webhook:10", now, map[string]int{"delivery_id": 10, "webhook_id": 3, "user_id": 1})
+			require.NoError(t, err)
+			job.Attempts, job.MaxAttempts = 1, MaxAttempts
+
+			err = s.Deliver(context.Background(), job)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Contains(t, d.Error, tt.wantErr)
+		})
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"93.184.216.34":          true,
+		"2606:2800:220:1::1":     true,
+		"127.0.0.1":              false,
+		"10.1.2.3":               false,
+		"172.16.0.1":             false,
+		"192.168.1.1":            false,
+		"169.254.169.254":        false,
+		"100.64.0.1":             false,
+		"0.0.0.0":                false,
+		"::1":                    false,
+		"fd00::1":                false,
+		"fe80::1":                false,
+		"::ffff:127.0.0.1":       false,
+		"::ffff:169.254.169.254": false,
+	} {
+		assert.Equal(t, want, isPublic(netip.MustParseAddr(addr)), addr)
+	}
+}
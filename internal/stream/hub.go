@@ -0,0 +1,91 @@
+// Package stream fans task events out to connected clients for live
+// updates.
+//
+// The Dispatcher in package events hands relayed events to Relay, which
+// turns task events into Messages and publishes them on a Hub. MemoryHub
+// fans out within one process. Deployments with several replicas relay
+// each event on only one of them, so they plug in a Hub backed by a shared
+// broker instead.
+package stream
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+	"taskflow/pkg"
+)
+
+// Message is one update for one user's clients. ID is the ID of the
+// domain event, so clients can resume after it.
+type Message struct {
+	ID     string
+	Event  string
+	UserID int
+	Data   json.RawMessage
+}
+
+// Subscription receives a user's messages until it is closed.
+type Subscription struct {
+	// Replay holds the buffered messages after the requested event.
+	Replay []Message
+	// Reset is set when the requested event is no longer buffered, so
+	// messages may have been missed and the client should reload.
+	Reset bool
+	// C delivers live messages. It is closed when the subscription is
+	// closed or the hub dropped a subscriber that fell behind.
+	C <-chan Message
+	// Close ends the subscription. It is safe to call more than once.
+	Close func()
+}
+
+// Hub fans messages out to subscribers and keeps recent ones for replay.
+type Hub interface {
+	// Publish delivers m to the subscribers of m.UserID.
+	Publish(m Message)
+	// Subscribe starts receiving userID's messages, replaying those after
+	// lastEventID unless it is empty.
+	Subscribe(userID int, lastEventID string) (*Subscription, error)
+}
+
+type Config struct {
+	// ReplaySize is how many recent messages, of all users, are kept for
+	// clients that reconnect.
+	ReplaySize int
+	// Heartbeat is how often idle connections get a comment, so proxies
+	// don't close them.
+	Heartbeat time.Duration
+}
+
+func LoadConfigFromEnv() Config {
+	return Config{
+		ReplaySize: pkg.IntEnv("STREAM_REPLAY_SIZE", 1000),
+		Heartbeat:  pkg.DurationEnv("STREAM_HEARTBEAT", 15*time.Second),
+	}
+}
+
+// Relay returns a listener that publishes task events on hub.
+func Relay(hub Hub) events.Listener {
+	return func(e *events.Envelope) {
+		if !strings.HasPrefix(e.Type, "task.") {
+			return
+		}
+		var ev struct {
+			Task dto.GetTaskResponse `json:"task"`
+			From string              `json:"from"`
+		}
+		if err := e.Decode(&ev); err != nil {
+			log.Printf("stream: decoding %s %s: %v", e.Type, e.ID, err)
+			return
+		}
+		data, err := json.Marshal(dto.TaskEventData{Task: ev.Task, PreviousStatus: ev.From})
+		if err != nil {
+			log.Printf("stream: encoding %s %s: %v", e.Type, e.ID, err)
+			return
+		}
+		hub.Publish(Message{ID: e.ID, Event: e.Type, UserID: e.UserID, Data: data})
+	}
+}
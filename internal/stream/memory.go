@@ -0,0 +1,100 @@
+package stream
+
+import "sync"
+
+// subscriberBuffer is how many messages a subscriber may fall behind
+// before it is dropped. Dropped clients reconnect and catch up from the
+// replay buffer.
+const subscriberBuffer = 64
+
+type subscriber struct {
+	userID int
+	ch     chan Message
+}
+
+// MemoryHub is a Hub within one process. It keeps the last size messages
+// in a ring buffer for replay.
+type MemoryHub struct {
+	mu    sync.Mutex
+	ring  []Message
+	start int // index of the oldest message once the ring is full
+	size  int
+	subs  map[int]map[*subscriber]struct{}
+}
+
+func NewMemoryHub(size int) *MemoryHub {
+	return &MemoryHub{
+		ring: make([]Message, 0, size),
+		size: size,
+		subs: map[int]map[*subscriber]struct{}{},
+	}
+}
+
+// Compile-time check
+var _ Hub = (*MemoryHub)(nil)
+
+func (h *MemoryHub) Publish(m Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.ring) < h.size {
+		h.ring = append(h.ring, m)
+	} else {
+		h.ring[h.start] = m
+		h.start = (h.start + 1) % h.size
+	}
+
+	for s := range h.subs[m.UserID] {
+		select {
+		case s.ch <- m:
+		default:
+			h.remove(s)
+		}
+	}
+}
+
+func (h *MemoryHub) Subscribe(userID int, lastEventID string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{}
+	if lastEventID != "" {
+		found := false
+		for i := range h.ring {
+			m := h.ring[(h.start+i)%len(h.ring)]
+			if found && m.UserID == userID {
+				sub.Replay = append(sub.Replay, m)
+			}
+			if m.ID == lastEventID {
+				found = true
+			}
+		}
+		sub.Reset = !found
+	}
+
+	s := &subscriber{userID: userID, ch: make(chan Message, subscriberBuffer)}
+	if h.subs[userID] == nil {
+		h.subs[userID] = map[*subscriber]struct{}{}
+	}
+	h.subs[userID][s] = struct{}{}
+
+	sub.C = s.ch
+	sub.Close = func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.remove(s)
+	}
+	return sub, nil
+}
+
+// remove unregisters s and closes its channel. h.mu must be held.
+func (h *MemoryHub) remove(s *subscriber) {
+	if _, ok := h.subs[s.userID][s]; !ok {
+		return
+	}
+	delete(h.subs[s.userID], s)
+	if len(h.subs[s.userID]) == 0 {
+		delete(h.subs, s.userID)
+	}
+	close(s.ch)
+}
@@ -0,0 +1,120 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"taskflow/internal/dto"
+	"taskflow/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func msg(id string, userID int) Message {
+	return Message{ID: id, Event: events.TypeTaskCreated, UserID: userID, Data: json.RawMessage(`{}`)}
+}
+
+func ids(msgs []Message) []string {
+	out := []string{}
+	for _, m := range msgs {
+		out = append(out, m.ID)
+	}
+	return out
+}
+
+func TestMemoryHub_PublishIsScopedToTheUser(t *testing.T) {
+	hub := NewMemoryHub(10)
+	mine, err := hub.Subscribe(1, "")
+	require.NoError(t, err)
+	theirs, err := hub.Subscribe(2, "")
+	require.NoError(t, err)
+
+	hub.Publish(msg("a", 1))
+
+	assert.Equal(t, "a", (<-mine.C).ID)
+	assert.Empty(t, theirs.C)
+	assert.False(t, mine.Reset)
+	assert.Empty(t, mine.Replay)
+}
+
+func TestMemoryHub_Replay(t *testing.T) {
+	hub := NewMemoryHub(4)
+	for i, userID := range []int{1, 2, 1, 1, 2, 1} {
+		hub.Publish(msg(fmt.Sprint(i), userID))
+	}
+	// The ring now holds events 2 to 5.
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantReplay  []string
+		wantReset   bool
+	}{
+		{name: "no last event", lastEventID: "", wantReplay: []string{}},
+		{name: "resume", lastEventID: "2", wantReplay: []string{"3", "5"}},
+		{name: "resume after another user's event", lastEventID: "4", wantReplay: []string{"5"}},
+		{name: "up to date", lastEventID: "5", wantReplay: []string{}},
+		{name: "evicted event", lastEventID: "1", wantReplay: []string{}, wantReset: true},
+		{name: "unknown event", lastEventID: "nope", wantReplay: []string{}, wantReset: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, err := hub.Subscribe(1, tt.lastEventID)
+			require.NoError(t, err)
+			defer sub.Close()
+
+			assert.Equal(t, tt.wantReplay, ids(sub.Replay))
+			assert.Equal(t, tt.wantReset, sub.Reset)
+		})
+	}
+}
+
+func TestMemoryHub_DropsSlowSubscribers(t *testing.T) {
+	hub := NewMemoryHub(1000)
+	sub, err := hub.Subscribe(1, "")
+	require.NoError(t, err)
+
+	for i := range subscriberBuffer + 1 {
+		hub.Publish(msg(fmt.Sprint(i), 1))
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received, "the channel is closed once it is full")
+
+	sub.Close()
+	sub.Close()
+}
+
+func TestRelay(t *testing.T) {
+	hub := NewMemoryHub(10)
+	sub, err := hub.Subscribe(1, "")
+	require.NoError(t, err)
+	relay := Relay(hub)
+
+	task := dto.GetTaskResponse{ID: 4, Task: "Write report", Status: "completed"}
+	for _, ev := range []events.Event{
+		events.StatusChanged{UserID: 1, Task: task, From: "pending"},
+		events.UserDeleted{UserID: 1},
+	} {
+		r, err := events.NewRecord(ev, time.Now())
+		require.NoError(t, err)
+		e := r.Envelope()
+		relay(&e)
+	}
+
+	require.Len(t, sub.C, 1, "only task events are relayed")
+	m := <-sub.C
+	assert.Len(t, m.ID, 32)
+	assert.Equal(t, events.TypeStatusChanged, m.Event)
+	assert.Equal(t, 1, m.UserID)
+	want, err := json.Marshal(dto.TaskEventData{Task: task, PreviousStatus: "pending"})
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(m.Data))
+}
@@ -0,0 +1,72 @@
+package taskquery
+
+// Node is an element of a parsed query expression.
+type Node interface {
+	// Pos is the 1-based character position where the node starts.
+	Pos() int
+}
+
+// BinaryExpr joins two expressions with AND or OR.
+type BinaryExpr struct {
+	Op    string // "AND" or "OR"
+	Left  Node
+	Right Node
+	pos   int
+}
+
+func (n *BinaryExpr) Pos() int { return n.pos }
+
+// NotExpr negates an expression.
+type NotExpr struct {
+	X   Node
+	pos int
+}
+
+func (n *NotExpr) Pos() int { return n.pos }
+
+// Comparison is a field test such as status:pending or priority>=high.
+type Comparison struct {
+	Field    string
+	Op       string // one of ":", "=", "!=", "<", "<=", ">", ">="
+	Value    string
+	pos      int
+	valuePos int
+}
+
+func (n *Comparison) Pos() int { return n.pos }
+
+// ValuePos is the 1-based position of the comparison value.
+func (n *Comparison) ValuePos() int { return n.valuePos }
+
+// TextTerm is a bare word or quoted phrase matched against title and description.
+type TextTerm struct {
+	Text string
+	pos  int
+}
+
+func (n *TextTerm) Pos() int { return n.pos }
+
+// SplitText separates the text terms that n joins with AND at its top level
+// from the rest of the expression, which is nil when nothing else is left.
+// Text under OR or NOT stays in the rest, where it compiles to a LIKE match.
+func SplitText(n Node) ([]string, Node) {
+	switch n := n.(type) {
+	case *TextTerm:
+		return []string{n.Text}, nil
+	case *BinaryExpr:
+		if n.Op != "AND" {
+			return nil, n
+		}
+		leftText, left := SplitText(n.Left)
+		rightText, right := SplitText(n.Right)
+		text := append(leftText, rightText...)
+		switch {
+		case left == nil:
+			return text, right
+		case right == nil:
+			return text, left
+		}
+		return text, &BinaryExpr{Op: "AND", Left: left, Right: right, pos: n.pos}
+	}
+	return nil, n
+}
@@ -0,0 +1,270 @@
+package taskquery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskflow/internal/domain/task"
+
+	"gorm.io/gorm"
+)
+
+// Scope is a GORM scope that applies a compiled query to a tasks query.
+type Scope = func(*gorm.DB) *gorm.DB
+
+type fieldKind int
+
+const (
+	kindEnum fieldKind = iota
+	kindOrdered
+	kindText
+	kindTag
+	kindDate
+)
+
+type field struct {
+	column string
+	kind   fieldKind
+	values []string // allowed values for enum fields
+}
+
+// fields maps query field names to the columns they filter on.
+var fields = map[string]field{
+	"status":      {column: "tasks.status", kind: kindEnum, values: []string{"pending", "in-progress", "completed"}},
+	"priority":    {column: "tasks.priority", kind: kindOrdered},
+	"tag":         {kind: kindTag},
+	"title":       {column: "tasks.task", kind: kindText},
+	"task":        {column: "tasks.task", kind: kindText},
+	"description": {column: "tasks.description", kind: kindText},
+	"due":         {column: "tasks.due_at", kind: kindDate},
+	"created":     {column: "tasks.created_at", kind: kindDate},
+	"updated":     {column: "tasks.updated_at", kind: kindDate},
+}
+
+// Validate checks that every comparison names a known field with an operator
+// and value that field supports.
+func Validate(n Node) error {
+	_, _, err := compileNode(n, time.Now())
+	return err
+}
+
+// Compile validates n and turns it into a GORM scope. Relative dates such as
+// today or 7d are resolved against now, in now's location. Every value is
+// bound as a parameter; field names only ever select from a fixed column list.
+func Compile(n Node, now time.Time) (Scope, error) {
+	if n == nil {
+		return func(db *gorm.DB) *gorm.DB { return db }, nil
+	}
+
+	sql, args, err := compileNode(n, now)
+	if err != nil {
+		return nil, err
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(sql, args...)
+	}, nil
+}
+
+func compileNode(n Node, now time.Time) (string, []any, error) {
+	switch n := n.(type) {
+	case *BinaryExpr:
+		left, leftArgs, err := compileNode(n.Left, now)
+		if err != nil {
+			return "", nil, err
+		}
+		right, rightArgs, err := compileNode(n.Right, now)
+		if err != nil {
+			return "", nil, err
+		}
+		return "(" + left + " " + n.Op + " " + right + ")", append(leftArgs, rightArgs...), nil
+
+	case *NotExpr:
+		x, args, err := compileNode(n.X, now)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT " + x, args, nil
+
+	case *TextTerm:
+		pattern := "%" + escapeLike(n.Text) + "%"
+		return "(tasks.task LIKE ? ESCAPE '!' OR tasks.description LIKE ? ESCAPE '!')", []any{pattern, pattern}, nil
+
+	case *Comparison:
+		return compileComparison(n, now)
+
+	default:
+		return "", nil, fmt.Errorf("taskquery: unexpected node %T", n)
+	}
+}
+
+func compileComparison(c *Comparison, now time.Time) (string, []any, error) {
+	f, ok := fields[strings.ToLower(c.Field)]
+	if !ok {
+		return "", nil, &Error{Pos: c.Pos(), Message: fmt.Sprintf("unknown field '%s'", c.Field)}
+	}
+
+	switch f.kind {
+	case kindEnum:
+		if err := requireEquality(c); err != nil {
+			return "", nil, err
+		}
+		value := strings.ToLower(c.Value)
+		if !contains(f.values, value) {
+			return "", nil, &Error{Pos: c.ValuePos(), Message: fmt.Sprintf("invalid %s '%s', expected one of %s", c.Field, c.Value, strings.Join(f.values, ", "))}
+		}
+		return f.column + " " + sqlOp(c.Op) + " ?", []any{value}, nil
+
+	case kindOrdered:
+		p, err := task.ParsePriority(strings.ToLower(c.Value))
+		if err != nil || c.Value == "" {
+			return "", nil, &Error{Pos: c.ValuePos(), Message: fmt.Sprintf("invalid priority '%s', expected one of none, low, medium, high, urgent", c.Value)}
+		}
+		return f.column + " " + sqlOp(c.Op) + " ?", []any{int(p)}, nil
+
+	case kindTag:
+		if err := requireEquality(c); err != nil {
+			return "", nil, err
+		}
+		sql := "EXISTS (SELECT 1 FROM task_tags tt WHERE tt.task_id = tasks.id AND tt.name = ?)"
+		if c.Op == "!=" {
+			sql = "NOT " + sql
+		}
+		return sql, []any{strings.ToLower(c.Value)}, nil
+
+	case kindText:
+		switch c.Op {
+		case ":":
+			return f.column + " LIKE ? ESCAPE '!'", []any{"%" + escapeLike(c.Value) + "%"}, nil
+		case "=":
+			return f.column + " = ?", []any{c.Value}, nil
+		case "!=":
+			return f.column + " NOT LIKE ? ESCAPE '!'", []any{"%" + escapeLike(c.Value) + "%"}, nil
+		}
+		return "", nil, &Error{Pos: c.Pos(), Message: fmt.Sprintf("operator '%s' is not supported for %s", c.Op, c.Field)}
+
+	default:
+		return compileDate(c, f, now)
+	}
+}
+
+func compileDate(c *Comparison, f field, now time.Time) (string, []any, error) {
+	value := strings.ToLower(c.Value)
+
+	if value == "none" {
+		if f.column != "tasks.due_at" {
+			return "", nil, &Error{Pos: c.ValuePos(), Message: fmt.Sprintf("%s cannot be none", c.Field)}
+		}
+		if err := requireEquality(c); err != nil {
+			return "", nil, err
+		}
+		if c.Op == "!=" {
+			return f.column + " IS NOT NULL", nil, nil
+		}
+		return f.column + " IS NULL", nil, nil
+	}
+
+	// Relative offsets name an instant, so only ordering makes sense.
+	if at, ok := parseOffset(value, now); ok {
+		if c.Op == ":" || c.Op == "=" || c.Op == "!=" {
+			return "", nil, &Error{Pos: c.Pos(), Message: fmt.Sprintf("relative date '%s' needs <, <=, > or >=", c.Value)}
+		}
+		return f.column + " " + c.Op + " ?", []any{at}, nil
+	}
+
+	start, ok := parseDay(value, now)
+	if !ok {
+		return "", nil, &Error{Pos: c.ValuePos(), Message: fmt.Sprintf("invalid date '%s', expected today, tomorrow, yesterday, now, YYYY-MM-DD or an offset like 7d", c.Value)}
+	}
+	end := start.AddDate(0, 0, 1)
+
+	// A day is a range: due:today matches anything due during today.
+	switch c.Op {
+	case ":", "=":
+		return "(" + f.column + " >= ? AND " + f.column + " < ?)", []any{start, end}, nil
+	case "!=":
+		return "(" + f.column + " IS NULL OR " + f.column + " < ? OR " + f.column + " >= ?)", []any{start, end}, nil
+	case "<":
+		return f.column + " < ?", []any{start}, nil
+	case "<=":
+		return f.column + " < ?", []any{end}, nil
+	case ">":
+		return f.column + " >= ?", []any{end}, nil
+	default: // ">="
+		return f.column + " >= ?", []any{start}, nil
+	}
+}
+
+// parseDay resolves a named day or an ISO date to midnight in now's location.
+func parseDay(value string, now time.Time) (time.Time, bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch value {
+	case "today":
+		return today, true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, now.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// parseOffset resolves now and offsets such as 7d, -3d, 12h or 2w relative to now.
+func parseOffset(value string, now time.Time) (time.Time, bool) {
+	if value == "now" {
+		return now, true
+	}
+	if len(value) < 2 {
+		return time.Time{}, false
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch value[len(value)-1] {
+	case 'h':
+		return now.Add(time.Duration(n) * time.Hour), true
+	case 'd':
+		return now.AddDate(0, 0, n), true
+	case 'w':
+		return now.AddDate(0, 0, 7*n), true
+	}
+	return time.Time{}, false
+}
+
+func requireEquality(c *Comparison) error {
+	if c.Op == ":" || c.Op == "=" || c.Op == "!=" {
+		return nil
+	}
+	return &Error{Pos: c.Pos(), Message: fmt.Sprintf("operator '%s' is not supported for %s", c.Op, c.Field)}
+}
+
+func sqlOp(op string) string {
+	if op == ":" {
+		return "="
+	}
+	return op
+}
+
+// escapeLike escapes LIKE wildcards using '!' as the escape character.
+func escapeLike(s string) string {
+	r := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+	return r.Replace(s)
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package taskquery
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // 1-based character position
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of query"
+	case tokString:
+		return "\"" + t.text + "\""
+	default:
+		return "'" + t.text + "'"
+	}
+}
+
+func isOpChar(r rune) bool {
+	return r == ':' || r == '=' || r == '!' || r == '<' || r == '>'
+}
+
+func isWordChar(r rune) bool {
+	return !unicode.IsSpace(r) && !isOpChar(r) && r != '(' && r != ')' && r != '"'
+}
+
+// lex splits input into tokens. Positions count characters, not bytes.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: pos})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: pos})
+			i++
+
+		case r == '"':
+			var b strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					b.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, &Error{Pos: pos, Message: "unterminated quoted string"}
+			}
+			tokens = append(tokens, token{kind: tokString, text: b.String(), pos: pos})
+
+		case isOpChar(r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != ':' && r != '=' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, &Error{Pos: pos, Message: "unexpected '!', did you mean '!='?"}
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: pos})
+			i += utf8.RuneCountInString(op)
+
+		default:
+			start := i
+			for i < len(runes) && isWordChar(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: string(runes[start:i]), pos: pos})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
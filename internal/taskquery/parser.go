@@ -0,0 +1,159 @@
+package taskquery
+
+import "fmt"
+
+// maxDepth bounds nesting so hostile input cannot exhaust the stack.
+const maxDepth = 32
+
+// Error is a query syntax or validation error with the position it refers to.
+type Error struct {
+	Pos     int // 1-based character position
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos, e.Message)
+}
+
+type parser struct {
+	tokens []token
+	i      int
+	depth  int
+}
+
+// Parse turns a query such as
+//
+//	status:pending AND (tag:work OR priority>=high) AND due<7d
+//
+// into an AST. Adjacent terms are joined with an implicit AND, and AND binds
+// tighter than OR. An empty query yields a nil Node.
+func Parse(input string) (Node, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, nil
+	}
+
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &Error{Pos: t.pos, Message: "unexpected " + t.describe()}
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func isKeyword(t token, kw string) bool {
+	return t.kind == tokWord && t.text == kw
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for isKeyword(p.peek(), "OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: "OR", Left: left, Right: right, pos: left.Pos()}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if isKeyword(t, "AND") {
+			p.next()
+		} else if t.kind == tokEOF || t.kind == tokRParen || isKeyword(t, "OR") {
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &BinaryExpr{Op: "AND", Left: left, Right: right, pos: left.Pos()}
+	}
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if t := p.peek(); isKeyword(t, "NOT") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotExpr{X: x, pos: t.pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+
+	switch t.kind {
+	case tokLParen:
+		p.depth++
+		if p.depth > maxDepth {
+			return nil, &Error{Pos: t.pos, Message: "expression is nested too deeply"}
+		}
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		closing := p.next()
+		if closing.kind != tokRParen {
+			return nil, &Error{Pos: closing.pos, Message: "expected ')' but found " + closing.describe()}
+		}
+		p.depth--
+		return n, nil
+
+	case tokString:
+		return &TextTerm{Text: t.text, pos: t.pos}, nil
+
+	case tokWord:
+		if t.text == "AND" || t.text == "OR" {
+			return nil, &Error{Pos: t.pos, Message: "expected a term before " + t.describe()}
+		}
+		if p.peek().kind != tokOp {
+			return &TextTerm{Text: t.text, pos: t.pos}, nil
+		}
+
+		op := p.next()
+		value := p.next()
+		if value.kind != tokWord && value.kind != tokString {
+			return nil, &Error{Pos: value.pos, Message: fmt.Sprintf("expected a value after '%s' but found %s", op.text, value.describe())}
+		}
+		return &Comparison{Field: t.text, Op: op.text, Value: value.text, pos: t.pos, valuePos: value.pos}, nil
+
+	default:
+		return nil, &Error{Pos: t.pos, Message: "unexpected " + t.describe()}
+	}
+}
@@ -0,0 +1,167 @@
+package taskquery
+
+import (
+	"testing"
+	"time"
+
+	"taskflow/internal/domain/task"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestParse(t *testing.T) {
+	n, err := Parse(`status:pending AND (tag:work OR priority>=high) due<7d`)
+	require.NoError(t, err)
+
+	root, ok := n.(*BinaryExpr)
+	require.True(t, ok)
+	assert.Equal(t, "AND", root.Op)
+	assert.Equal(t, &Comparison{Field: "due", Op: "<", Value: "7d", pos: 49, valuePos: 53}, root.Right)
+
+	inner := root.Left.(*BinaryExpr).Right.(*BinaryExpr)
+	assert.Equal(t, "OR", inner.Op)
+	assert.Equal(t, ">=", inner.Right.(*Comparison).Op)
+}
+
+func TestSplitText(t *testing.T) {
+	n, err := Parse(`buy "oat milk" status:pending (tag:home OR shop) NOT eggs`)
+	require.NoError(t, err)
+
+	text, rest := SplitText(n)
+	assert.Equal(t, []string{"buy", "oat milk"}, text)
+	require.NotNil(t, rest)
+	root := rest.(*BinaryExpr)
+	assert.Equal(t, "AND", root.Op)
+	assert.IsType(t, &NotExpr{}, root.Right)
+	assert.Equal(t, "status", root.Left.(*BinaryExpr).Left.(*Comparison).Field)
+
+	n, err = Parse(`milk`)
+	require.NoError(t, err)
+	text, rest = SplitText(n)
+	assert.Equal(t, []string{"milk"}, text)
+	assert.Nil(t, rest)
+
+	text, rest = SplitText(nil)
+	assert.Empty(t, text)
+	assert.Nil(t, rest)
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantPos int
+	}{
+		{query: `status:pending AND`, wantPos: 19},
+		{query: `(tag:work`, wantPos: 10},
+		{query: `tag:work)`, wantPos: 9},
+		{query: `title:"unterminated`, wantPos: 7},
+		{query: `priority>`, wantPos: 10},
+		{query: `OR tag:work`, wantPos: 1},
+		{query: `due!3d`, wantPos: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := Parse(tt.query)
+			var qerr *Error
+			require.ErrorAs(t, err, &qerr)
+			assert.Equal(t, tt.wantPos, qerr.Pos)
+		})
+	}
+}
+
+func TestValidate_Errors(t *testing.T) {
+	tests := []struct {
+		query   string
+		wantPos int
+	}{
+		{query: `colour:red`, wantPos: 1},
+		{query: `status:done`, wantPos: 8},
+		{query: `priority>=extreme`, wantPos: 11},
+		{query: `tag>work`, wantPos: 1},
+		{query: `due:7d`, wantPos: 1},
+		{query: `created:none`, wantPos: 9},
+		{query: `due<soon`, wantPos: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			n, err := Parse(tt.query)
+			require.NoError(t, err)
+
+			var qerr *Error
+			require.ErrorAs(t, Validate(n), &qerr)
+			assert.Equal(t, tt.wantPos, qerr.Pos)
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&task.Task{}, &task.Tag{}))
+
+	now := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	day := func(offset int) *time.Time {
+		d := now.AddDate(0, 0, offset)
+		return &d
+	}
+
+	tasks := []task.Task{
+		{Task: "Write report", Status: "pending", Priority: task.PriorityHigh, DueAt: day(0), Tags: []task.Tag{{Name: "work"}}},
+		{Task: "Buy milk", Description: "100% semi-skimmed", Status: "pending", Priority: task.PriorityLow, DueAt: day(3)},
+		{Task: "Call mum", Status: "completed", Priority: task.PriorityUrgent, Tags: []task.Tag{{Name: "family"}}},
+		{Task: "Plan trip", Status: "pending", DueAt: day(14), Tags: []task.Tag{{Name: "work"}}},
+	}
+	for i := range tasks {
+		tasks[i].UserID = 1
+		require.NoError(t, db.Create(&tasks[i]).Error)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: ``, want: []string{"Write report", "Buy milk", "Call mum", "Plan trip"}},
+		{query: `status:pending AND (tag:work OR priority>=high) AND due<7d`, want: []string{"Write report"}},
+		{query: `tag:work`, want: []string{"Write report", "Plan trip"}},
+		{query: `NOT tag:work`, want: []string{"Buy milk", "Call mum"}},
+		{query: `tag!=work status:pending`, want: []string{"Buy milk"}},
+		{query: `priority>low priority<urgent`, want: []string{"Write report"}},
+		{query: `due:today`, want: []string{"Write report"}},
+		{query: `due:none`, want: []string{"Call mum"}},
+		{query: `due<now`, want: nil},
+		{query: `updated<-1d`, want: nil},
+		{query: `due>today`, want: []string{"Buy milk", "Plan trip"}},
+		{query: `due<=2025-03-13`, want: []string{"Write report", "Buy milk"}},
+		{query: `milk OR mum`, want: []string{"Buy milk", "Call mum"}},
+		{query: `"100%"`, want: []string{"Buy milk"}},
+		{query: `"100"`, want: []string{"Buy milk"}},
+		{query: `description:"%"`, want: []string{"Buy milk"}},
+		{query: `title:"x' OR 1=1 --"`, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			n, err := Parse(tt.query)
+			require.NoError(t, err)
+			scope, err := Compile(n, now)
+			require.NoError(t, err)
+
+			var got []task.Task
+			require.NoError(t, db.Scopes(scope).Order("id").Find(&got).Error)
+
+			var titles []string
+			for _, g := range got {
+				titles = append(titles, g.Task)
+			}
+			assert.Equal(t, tt.want, titles)
+		})
+	}
+}
@@ -1,21 +1,103 @@
 package main
 
 import (
+	"context"
 	"log"
+	"net/http"
 	"time"
+	_ "time/tzdata" // user time zones must resolve without system zoneinfo
 
 	"taskflow/internal/auth"
+	"taskflow/internal/collab"
+	"taskflow/internal/domain/accesstoken"
+	"taskflow/internal/domain/attachment"
+	"taskflow/internal/domain/board"
+	"taskflow/internal/domain/caldav"
+	"taskflow/internal/domain/calendar"
+	"taskflow/internal/domain/comment"
+	"taskflow/internal/domain/filter"
+	"taskflow/internal/domain/importjob"
+	"taskflow/internal/domain/inbound"
+	"taskflow/internal/domain/notification"
+	"taskflow/internal/domain/project"
 	"taskflow/internal/domain/task"
+	"taskflow/internal/domain/template"
+	"taskflow/internal/domain/timeentry"
 	"taskflow/internal/domain/user"
+	"taskflow/internal/domain/webhook"
+	"taskflow/internal/events"
+	accesstoken_handler "taskflow/internal/handler/accesstoken"
+	attachment_handler "taskflow/internal/handler/attachment"
+	board_handler "taskflow/internal/handler/board"
+	bulk_handler "taskflow/internal/handler/bulk"
+	caldav_handler "taskflow/internal/handler/caldav"
+	calendar_handler "taskflow/internal/handler/calendar"
+	collab_handler "taskflow/internal/handler/collab"
+	comment_handler "taskflow/internal/handler/comment"
+	filter_handler "taskflow/internal/handler/filter"
+	importjob_handler "taskflow/internal/handler/importjob"
+	inbound_handler "taskflow/internal/handler/inbound"
+	notification_handler "taskflow/internal/handler/notification"
+	project_handler "taskflow/internal/handler/project"
+	search_handler "taskflow/internal/handler/search"
+	stats_handler "taskflow/internal/handler/stats"
+	stream_handler "taskflow/internal/handler/stream"
 	task_handler "taskflow/internal/handler/task"
+	template_handler "taskflow/internal/handler/template"
+	timeentry_handler "taskflow/internal/handler/timeentry"
+	transfer_handler "taskflow/internal/handler/transfer"
 	user_handler "taskflow/internal/handler/user"
+	webhook_handler "taskflow/internal/handler/webhook"
+	"taskflow/internal/importer"
+	"taskflow/internal/middleware/idempotency"
 	"taskflow/internal/middleware/ratelimiter"
+	"taskflow/internal/notify"
+	"taskflow/internal/repository/gorm/gorm_accesstoken"
+	"taskflow/internal/repository/gorm/gorm_attachment"
+	"taskflow/internal/repository/gorm/gorm_board"
+	"taskflow/internal/repository/gorm/gorm_caldav"
+	"taskflow/internal/repository/gorm/gorm_calendar"
+	"taskflow/internal/repository/gorm/gorm_comment"
+	"taskflow/internal/repository/gorm/gorm_filter"
+	"taskflow/internal/repository/gorm/gorm_importjob"
+	"taskflow/internal/repository/gorm/gorm_inbound"
+	"taskflow/internal/repository/gorm/gorm_notification"
+	"taskflow/internal/repository/gorm/gorm_project"
+	"taskflow/internal/repository/gorm/gorm_search"
+	"taskflow/internal/repository/gorm/gorm_stats"
 	"taskflow/internal/repository/gorm/gorm_task"
+	"taskflow/internal/repository/gorm/gorm_template"
+	"taskflow/internal/repository/gorm/gorm_timeentry"
 	"taskflow/internal/repository/gorm/gorm_user"
+	"taskflow/internal/repository/gorm/gorm_webhook"
+	"taskflow/internal/scheduler"
+	accesstoken_service "taskflow/internal/service/accesstoken"
+	attachment_service "taskflow/internal/service/attachment"
+	board_service "taskflow/internal/service/board"
+	bulk_service "taskflow/internal/service/bulk"
+	caldav_service "taskflow/internal/service/caldav"
+	calendar_service "taskflow/internal/service/calendar"
+	comment_service "taskflow/internal/service/comment"
+	filter_service "taskflow/internal/service/filter"
+	importjob_service "taskflow/internal/service/importjob"
+	inbound_service "taskflow/internal/service/inbound"
+	notification_service "taskflow/internal/service/notification"
+	project_service "taskflow/internal/service/project"
+	reminder_service "taskflow/internal/service/reminder"
+	search_service "taskflow/internal/service/search"
+	stats_service "taskflow/internal/service/stats"
 	task_service "taskflow/internal/service/task"
+	template_service "taskflow/internal/service/template"
+	timeentry_service "taskflow/internal/service/timeentry"
+	transfer_service "taskflow/internal/service/transfer"
 	user_service "taskflow/internal/service/user"
+	webhook_service "taskflow/internal/service/webhook"
+	"taskflow/internal/stream"
 	"taskflow/pkg"
+	"taskflow/pkg/blobstore"
 	"taskflow/pkg/database"
+	"taskflow/pkg/signedurl"
+	"taskflow/pkg/smtpd"
 
 	docs "taskflow/docs"
 
@@ -39,23 +121,157 @@ func main() {
 		log.Fatal(err)
 	}
 
-	if err := database.MigrateModels(db, &user.User{}, &task.Task{}); err != nil {
+	if err := database.MigrateModels(db, &user.User{}, &task.Task{}, &task.Tag{}, &comment.Comment{}, &comment.Mention{}, &attachment.Attachment{}, &filter.Filter{}, &project.Project{}, &board.Column{}, &timeentry.Entry{}, &template.Template{}, &template.Subtask{}, &notification.Notification{}, &notification.Preference{}, &webhook.Subscription{}, &webhook.Delivery{}, &inbound.Address{}, &calendar.Feed{}, &accesstoken.Token{}, &caldav.Object{}, &caldav.Tombstone{}, &importjob.Job{}, &events.Record{}, &scheduler.Job{}, &idempotency.Record{}); err != nil {
+		log.Fatal(err)
+	}
+	if err := gorm_search.EnsureFullTextIndexes(db); err != nil {
 		log.Fatal(err)
 	}
 	sqlDB, _ := db.DB()
 	defer sqlDB.Close()
 
 	secretKey := []byte(pkg.GetEnv("JWT_SECRET", ""))
+	blobs, err := blobstore.New(blobstore.LoadConfigFromEnv())
+	if err != nil {
+		log.Fatal(err)
+	}
+	urlSigner := signedurl.NewSigner(pkg.GetEnv("ATTACHMENT_URL_SECRET", string(secretKey)))
+
 	// Dependency wiring
 	taskRepo := gorm_task.NewTaskRepository(db)
-	taskSvc := task_service.NewTaskService(taskRepo)
+	attachmentRepo := gorm_attachment.NewAttachmentRepository(db)
+	attachmentSvc := attachment_service.NewAttachmentService(attachmentRepo, taskRepo, blobs, urlSigner)
+	boardRepo := gorm_board.NewBoardRepository(db)
 	userRepo := gorm_user.NewUserRepository(db)
+
+	// Notifications are stored in the in-app inbox right away; email and a
+	// webhook, when configured, are sent by the background scheduler.
+	jobStore := scheduler.NewStore(db)
+	notifyCfg := notify.LoadConfigFromEnv()
+	channels := map[string]notify.Notifier{}
+	if notifyCfg.SMTP.Host != "" {
+		mailer, err := notify.NewMailer(notifyCfg.SMTP)
+		if err != nil {
+			log.Fatal(err)
+		}
+		channels[notification_service.ChannelEmail] = mailer
+	}
+	if notifyCfg.WebhookURL != "" {
+		channels[notification_service.ChannelWebhook] = notify.NewWebhook(notifyCfg.WebhookURL, notifyCfg.WebhookSecret)
+	}
+	notificationSvc := notification_service.NewNotificationService(gorm_notification.NewNotificationRepository(db), userRepo, jobStore, channels)
+	webhookSvc := webhook_service.NewWebhookService(gorm_webhook.NewWebhookRepository(db), jobStore)
+
+	taskSvc := task_service.NewTaskService(taskRepo,
+		task_service.WithAttachmentCleaner(attachmentSvc),
+		task_service.WithWIPLimits(boardRepo),
+		task_service.WithUsers(userRepo),
+		task_service.WithPublisher(notificationSvc),
+	)
 	userSvc := user_service.NewUserService(userRepo, string(secretKey))
+	commentRepo := gorm_comment.NewCommentRepository(db)
+	commentSvc := comment_service.NewCommentService(commentRepo, taskRepo, userRepo)
+	searchSvc := search_service.NewSearchService(gorm_search.NewSearchRepository(db), search_service.WithUsers(userRepo))
+	filterSvc := filter_service.NewFilterService(gorm_filter.NewFilterRepository(db), taskRepo, filter_service.WithUsers(userRepo))
+	projectRepo := gorm_project.NewProjectRepository(db)
+	projectSvc := project_service.NewProjectService(projectRepo)
+	bulkSvc := bulk_service.NewBulkService(taskRepo, projectRepo, filterSvc, bulk_service.WithWIPLimits(boardRepo), bulk_service.WithUsers(userRepo))
+	boardSvc := board_service.NewBoardService(boardRepo, taskRepo, projectRepo)
+	statsSvc := stats_service.NewStatsService(gorm_stats.NewStatsRepository(db), userRepo)
+	timeEntrySvc := timeentry_service.NewTimeEntryService(gorm_timeentry.NewTimeEntryRepository(db), taskRepo, projectRepo)
+	templateSvc := template_service.NewTemplateService(gorm_template.NewTemplateRepository(db), taskRepo, projectRepo, userRepo)
+	inboundDomain := pkg.GetEnv("INBOUND_EMAIL_DOMAIN", "")
+	inboundSvc := inbound_service.NewInboundService(gorm_inbound.NewInboundRepository(db), taskSvc, attachmentSvc, inboundDomain)
+	calendarSvc := calendar_service.NewCalendarService(gorm_calendar.NewCalendarRepository(db), taskRepo, projectRepo, taskSvc)
+	accessTokenSvc := accesstoken_service.NewAccessTokenService(gorm_accesstoken.NewAccessTokenRepository(db))
+	caldavSvc := caldav_service.NewCalDAVService(gorm_caldav.NewCalDAVRepository(db), taskRepo, projectRepo, taskSvc)
+	transferSvc := transfer_service.NewTransferService(taskRepo, projectRepo, taskSvc)
+	importers := map[string]importer.Importer{
+		importer.SourceTodoist: importer.NewTodoist(),
+		importer.SourceTrello:  importer.NewTrello(),
+		importer.SourceTodoTxt: importer.NewTodoTxt(),
+	}
+	importJobSvc := importjob_service.NewImportJobService(gorm_importjob.NewImportJobRepository(db), taskRepo, projectRepo, taskSvc, blobs, jobStore, importers)
+
+	// Background jobs
+	reminderOffsets, err := reminder_service.ParseOffsets(pkg.GetEnv("REMINDER_OFFSETS", "24h,1h"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	reminderSvc := reminder_service.NewReminderService(taskRepo, userRepo, jobStore, reminderOffsets, notificationSvc)
+
+	// Domain events are relayed from the outbox to their consumers, which
+	// the scheduler runs, and to clients of the live event stream and the
+	// collaboration channel.
+	streamCfg := stream.LoadConfigFromEnv()
+	hub := stream.NewMemoryHub(streamCfg.ReplaySize)
+	collabHub := collab.NewHub()
+	bus := events.NewDispatcher(events.NewOutbox(db), jobStore, events.LoadConfigFromEnv())
+	bus.Subscribe("webhooks", webhookSvc.HandleEvent,
+		events.TypeTaskCreated, events.TypeStatusChanged, events.TypeTaskDeleted, events.TypeTaskRestored, events.TypeTaskUpdated, events.TypeUserDeleted)
+	bus.Subscribe("notifications", notificationSvc.HandleEvent, events.TypeUserDeleted)
+	bus.Subscribe("subtasks-done", taskSvc.NotifySubtasksDone, events.TypeStatusChanged)
+	bus.Subscribe("inbound", inboundSvc.HandleEvent, events.TypeUserDeleted)
+	bus.Subscribe("calendar", calendarSvc.HandleEvent, events.TypeUserDeleted)
+	bus.Subscribe("access-tokens", accessTokenSvc.HandleEvent, events.TypeUserDeleted)
+	bus.Subscribe("caldav", caldavSvc.HandleEvent, events.TypeTaskDeleted, events.TypeUserDeleted)
+	bus.Subscribe("imports", importJobSvc.HandleEvent, events.TypeUserDeleted)
+	if url := pkg.GetEnv("EVENTS_PUBLISH_URL", ""); url != "" {
+		bus.AddPublisher("http", events.NewHTTPPublisher(url, pkg.GetEnv("EVENTS_PUBLISH_SECRET", "")))
+	}
+	bus.Listen(stream.Relay(hub))
+	bus.Listen(collabHub.HandleEvent)
+	bus.Start(context.Background())
+
+	if schedCfg := scheduler.LoadConfigFromEnv(); schedCfg.Enabled {
+		sched := scheduler.New(jobStore, schedCfg)
+		bus.Register(sched)
+		sched.Every(reminder_service.KindPlan, reminder_service.PlanInterval, reminderSvc.Plan)
+		sched.Handle(reminder_service.KindDeliver, reminderSvc.Deliver)
+		sched.Handle(notification_service.KindDeliver, notificationSvc.Deliver)
+		sched.Handle(webhook_service.KindDeliver, webhookSvc.Deliver)
+		sched.Handle(importjob_service.KindRun, importJobSvc.Run)
+		sched.Every(caldav_service.KindPurge, caldav_service.PurgeInterval, caldavSvc.Purge)
+		sched.Every(task_service.KindPurgeTrash, task_service.PurgeTrashInterval, taskSvc.PurgeTrash)
+		sched.Start(context.Background())
+	}
+
+	// Mail to <token>@INBOUND_EMAIL_DOMAIN becomes a task when the SMTP
+	// listener is enabled.
+	if addr := pkg.GetEnv("INBOUND_SMTP_ADDR", ""); addr != "" {
+		mailServer := &smtpd.Server{Addr: addr, Hostname: inboundDomain, Backend: inbound_service.NewMailBackend(inboundSvc)}
+		go func() {
+			if err := mailServer.ListenAndServe(); err != nil {
+				log.Fatalf("Inbound SMTP listener failed: %v", err)
+			}
+		}()
+	}
 
 	userAuth := auth.NewUserAuth(string(secretKey), userRepo)
 
 	taskHandler := task_handler.NewTaskHandler(taskSvc, userAuth)
 	userHandler := user_handler.NewUserHandler(userSvc, userAuth)
+	commentHandler := comment_handler.NewCommentHandler(commentSvc, userAuth)
+	attachmentHandler := attachment_handler.NewAttachmentHandler(attachmentSvc, userAuth)
+	searchHandler := search_handler.NewSearchHandler(searchSvc, userAuth)
+	filterHandler := filter_handler.NewFilterHandler(filterSvc, userAuth)
+	projectHandler := project_handler.NewProjectHandler(projectSvc, userAuth)
+	bulkHandler := bulk_handler.NewBulkHandler(bulkSvc, userAuth)
+	boardHandler := board_handler.NewBoardHandler(boardSvc, userAuth)
+	statsHandler := stats_handler.NewStatsHandler(statsSvc, userAuth)
+	timeEntryHandler := timeentry_handler.NewTimeEntryHandler(timeEntrySvc, userAuth)
+	templateHandler := template_handler.NewTemplateHandler(templateSvc, userAuth)
+	notificationHandler := notification_handler.NewNotificationHandler(notificationSvc, userAuth)
+	webhookHandler := webhook_handler.NewWebhookHandler(webhookSvc, userAuth)
+	streamHandler := stream_handler.NewStreamHandler(hub, streamCfg.Heartbeat)
+	collabServer := collab.NewServer(collabHub, userAuth, taskSvc, projectSvc, collab.LoadConfigFromEnv())
+	collabHandler := collab_handler.NewCollabHandler(collabServer, userAuth)
+	inboundHandler := inbound_handler.NewInboundHandler(inboundSvc, userAuth)
+	calendarHandler := calendar_handler.NewCalendarHandler(calendarSvc, userAuth)
+	accessTokenHandler := accesstoken_handler.NewAccessTokenHandler(accessTokenSvc, userAuth)
+	caldavHandler := caldav_handler.NewCalDAVHandler(caldavSvc, accessTokenSvc)
+	transferHandler := transfer_handler.NewTransferHandler(transferSvc, userAuth)
+	importJobHandler := importjob_handler.NewImportJobHandler(importJobSvc, userAuth)
 
 	// Rate limiter setup for auth endpoints
 	// Allows 5 requests per second with a burst of 10 requests
@@ -63,6 +279,15 @@ func main() {
 	// Clean up old IP entries every hour to prevent memory leaks
 	authRateLimiter.StartCleanupRoutine(1 * time.Hour)
 
+	// Idempotency keys for mutating endpoints; the database store survives
+	// restarts and is shared between instances
+	var idemStore idempotency.Store = idempotency.NewDBStore(db)
+	if pkg.GetEnv("IDEMPOTENCY_STORE", "db") == "memory" {
+		idemStore = idempotency.NewMemoryStore()
+	}
+	idem := idempotency.New(idemStore, pkg.DurationEnv("IDEMPOTENCY_TTL", idempotency.DefaultTTL))
+	idem.StartCleanupRoutine(1 * time.Hour)
+
 	// Router setup
 	r := gin.Default()
 	docs.SwaggerInfo.BasePath = "/api"
@@ -80,20 +305,174 @@ func main() {
 		}
 
 		taskRoutes := api.Group("/tasks")
-		taskRoutes.Use(userAuth.AuthMiddleware())
+		taskRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
 		{
 			taskRoutes.POST("", taskHandler.CreateTask)
+			taskRoutes.GET("/search", searchHandler.SearchTasks)
+			taskRoutes.POST("/quick", taskHandler.QuickAdd)
+			taskRoutes.POST("/bulk", bulkHandler.BulkTasks)
+			taskRoutes.GET("/export", transferHandler.Export)
+			taskRoutes.POST("/import", transferHandler.Import)
 			taskRoutes.GET("/:id", taskHandler.GetTask)
 			taskRoutes.GET("", taskHandler.ListTasks)
 			taskRoutes.PATCH("/:id/status", taskHandler.UpdateStatus)
+			taskRoutes.POST("/:id/restore", taskHandler.Restore)
+			taskRoutes.POST("/:id/move", taskHandler.MoveTask)
+			taskRoutes.DELETE("/trash", taskHandler.EmptyTrash)
 			taskRoutes.DELETE("/:id", taskHandler.Delete)
+
+			taskRoutes.GET("/:id/comments", commentHandler.ListComments)
+			taskRoutes.POST("/:id/comments", commentHandler.CreateComment)
+			taskRoutes.PATCH("/:id/comments/:commentID", commentHandler.UpdateComment)
+			taskRoutes.DELETE("/:id/comments/:commentID", commentHandler.DeleteComment)
+
+			taskRoutes.GET("/:id/attachments", attachmentHandler.List)
+			taskRoutes.POST("/:id/attachments", attachmentHandler.Upload)
+			idem.OnReplay(http.MethodPost, taskRoutes.BasePath()+"/:id/attachments", attachmentHandler.ReplayUpload)
+			taskRoutes.DELETE("/:id/attachments/:attachmentID", attachmentHandler.Delete)
+
+			taskRoutes.POST("/:id/timer/start", timeEntryHandler.StartTimer)
+			taskRoutes.POST("/:id/timer/stop", timeEntryHandler.StopTimer)
+			taskRoutes.GET("/:id/time-entries", timeEntryHandler.ListEntries)
+			taskRoutes.POST("/:id/time-entries", timeEntryHandler.CreateEntry)
+			taskRoutes.DELETE("/:id/time-entries/:entryID", timeEntryHandler.DeleteEntry)
+			taskRoutes.PUT("/:id/estimate", timeEntryHandler.SetEstimate)
+		}
+
+		// Downloads are authorized by the signed URL, not the bearer token
+		attachmentRoutes := api.Group("/attachments")
+		{
+			attachmentRoutes.GET("/:id/download", attachmentHandler.Download)
+		}
+
+		projectRoutes := api.Group("/projects")
+		projectRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			projectRoutes.POST("", projectHandler.CreateProject)
+			projectRoutes.GET("", projectHandler.ListProjects)
+			projectRoutes.PATCH("/:id", projectHandler.UpdateProject)
+			projectRoutes.DELETE("/:id", projectHandler.DeleteProject)
+		}
+
+		templateRoutes := api.Group("/templates")
+		templateRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			templateRoutes.POST("", templateHandler.CreateTemplate)
+			templateRoutes.GET("", templateHandler.ListTemplates)
+			templateRoutes.GET("/:id", templateHandler.GetTemplate)
+			templateRoutes.PUT("/:id", templateHandler.UpdateTemplate)
+			templateRoutes.DELETE("/:id", templateHandler.DeleteTemplate)
+			templateRoutes.POST("/:id/instantiate", templateHandler.Instantiate)
+		}
+
+		notificationRoutes := api.Group("/notifications")
+		notificationRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			notificationRoutes.GET("", notificationHandler.ListNotifications)
+			notificationRoutes.POST("/read-all", notificationHandler.MarkAllRead)
+			notificationRoutes.POST("/:id/read", notificationHandler.MarkRead)
+			notificationRoutes.GET("/preferences", notificationHandler.GetPreferences)
+			notificationRoutes.PUT("/preferences", notificationHandler.UpdatePreferences)
+		}
+
+		webhookRoutes := api.Group("/webhooks")
+		webhookRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			webhookRoutes.POST("", webhookHandler.CreateWebhook)
+			webhookRoutes.GET("", webhookHandler.ListWebhooks)
+			webhookRoutes.GET("/:id", webhookHandler.GetWebhook)
+			webhookRoutes.PUT("/:id", webhookHandler.UpdateWebhook)
+			webhookRoutes.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhookRoutes.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhookRoutes.POST("/:id/deliveries/:deliveryID/redeliver", webhookHandler.Redeliver)
+		}
+
+		importRoutes := api.Group("/imports")
+		importRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			importRoutes.POST("", importJobHandler.CreateImport)
+			importRoutes.GET("", importJobHandler.ListImports)
+			importRoutes.GET("/:id", importJobHandler.GetImport)
+			importRoutes.POST("/:id/rerun", importJobHandler.RerunImport)
+			importRoutes.DELETE("/:id", importJobHandler.DeleteImport)
+		}
+
+		eventRoutes := api.Group("/events")
+		eventRoutes.Use(userAuth.AuthMiddleware())
+		{
+			eventRoutes.GET("/stream", streamHandler.Stream)
+		}
+
+		// The collaboration channel authenticates itself, since browsers
+		// can't set headers on WebSocket connections.
+		api.GET("/collab", collabHandler.Connect)
+
+		inboundRoutes := api.Group("/inbound-addresses")
+		inboundRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			inboundRoutes.POST("", inboundHandler.CreateAddress)
+			inboundRoutes.GET("", inboundHandler.ListAddresses)
+			inboundRoutes.DELETE("/:id", inboundHandler.DeleteAddress)
+		}
+
+		calendarFeedRoutes := api.Group("/calendar-feeds")
+		calendarFeedRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			calendarFeedRoutes.POST("", calendarHandler.CreateFeed)
+			calendarFeedRoutes.GET("", calendarHandler.ListFeeds)
+			calendarFeedRoutes.DELETE("/:id", calendarHandler.DeleteFeed)
+		}
+
+		calendarRoutes := api.Group("/calendar")
+		calendarRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			calendarRoutes.POST("/import", calendarHandler.Import)
+		}
+
+		accessTokenRoutes := api.Group("/access-tokens")
+		accessTokenRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			accessTokenRoutes.POST("", accessTokenHandler.CreateToken)
+			accessTokenRoutes.GET("", accessTokenHandler.ListTokens)
+			accessTokenRoutes.DELETE("/:id", accessTokenHandler.DeleteToken)
+		}
+
+		boardRoutes := api.Group("/boards")
+		boardRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			boardRoutes.GET("", boardHandler.GetBoard)
+			boardRoutes.PUT("/columns/:status", boardHandler.SetWIPLimit)
+		}
+
+		reportRoutes := api.Group("/reports")
+		reportRoutes.Use(userAuth.AuthMiddleware())
+		{
+			reportRoutes.GET("/time", timeEntryHandler.TimeReport)
+		}
+
+		statsRoutes := api.Group("/stats")
+		statsRoutes.Use(userAuth.AuthMiddleware())
+		{
+			statsRoutes.GET("", statsHandler.GetStats)
+		}
+
+		filterRoutes := api.Group("/filters")
+		filterRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
+		{
+			filterRoutes.POST("", filterHandler.CreateFilter)
+			filterRoutes.GET("", filterHandler.ListFilters)
+			filterRoutes.GET("/:id", filterHandler.GetFilter)
+			filterRoutes.PUT("/:id", filterHandler.UpdateFilter)
+			filterRoutes.DELETE("/:id", filterHandler.DeleteFilter)
+			filterRoutes.GET("/:id/tasks", filterHandler.RunFilter)
 		}
 
 		userRoutes := api.Group("/users")
-		userRoutes.Use(userAuth.AuthMiddleware())
+		userRoutes.Use(userAuth.AuthMiddleware(), idem.Middleware())
 		{
 			userRoutes.PATCH("/password", userHandler.UpdatePassword)
 			userRoutes.DELETE("/account", userHandler.DeleteUser)
+			userRoutes.PATCH("/timezone", userHandler.UpdateTimeZone)
 		}
 	}
 
@@ -108,20 +487,192 @@ func main() {
 		}
 
 		taskRoutes := public.Group("/tasks")
-		taskRoutes.Use(userAuth.OptionalAuthMiddleware())
+		taskRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
 		{
 			taskRoutes.POST("", taskHandler.CreateTask)
+			taskRoutes.GET("/search", searchHandler.SearchTasks)
+			taskRoutes.POST("/quick", taskHandler.QuickAdd)
+			taskRoutes.POST("/bulk", bulkHandler.BulkTasks)
+			taskRoutes.GET("/export", transferHandler.Export)
+			taskRoutes.POST("/import", transferHandler.Import)
 			taskRoutes.GET("/:id", taskHandler.GetTask)
 			taskRoutes.GET("", taskHandler.ListTasks)
 			taskRoutes.PATCH("/:id/status", taskHandler.UpdateStatus)
+			taskRoutes.POST("/:id/restore", taskHandler.Restore)
+			taskRoutes.POST("/:id/move", taskHandler.MoveTask)
+			taskRoutes.DELETE("/trash", taskHandler.EmptyTrash)
 			taskRoutes.DELETE("/:id", taskHandler.Delete)
+
+			taskRoutes.GET("/:id/comments", commentHandler.ListComments)
+			taskRoutes.POST("/:id/comments", commentHandler.CreateComment)
+			taskRoutes.PATCH("/:id/comments/:commentID", commentHandler.UpdateComment)
+			taskRoutes.DELETE("/:id/comments/:commentID", commentHandler.DeleteComment)
+
+			taskRoutes.GET("/:id/attachments", attachmentHandler.List)
+			taskRoutes.POST("/:id/attachments", attachmentHandler.Upload)
+			idem.OnReplay(http.MethodPost, taskRoutes.BasePath()+"/:id/attachments", attachmentHandler.ReplayUpload)
+			taskRoutes.DELETE("/:id/attachments/:attachmentID", attachmentHandler.Delete)
+
+			taskRoutes.POST("/:id/timer/start", timeEntryHandler.StartTimer)
+			taskRoutes.POST("/:id/timer/stop", timeEntryHandler.StopTimer)
+			taskRoutes.GET("/:id/time-entries", timeEntryHandler.ListEntries)
+			taskRoutes.POST("/:id/time-entries", timeEntryHandler.CreateEntry)
+			taskRoutes.DELETE("/:id/time-entries/:entryID", timeEntryHandler.DeleteEntry)
+			taskRoutes.PUT("/:id/estimate", timeEntryHandler.SetEstimate)
+		}
+
+		attachmentRoutes := public.Group("/attachments")
+		{
+			attachmentRoutes.GET("/:id/download", attachmentHandler.Download)
+		}
+
+		projectRoutes := public.Group("/projects")
+		projectRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			projectRoutes.POST("", projectHandler.CreateProject)
+			projectRoutes.GET("", projectHandler.ListProjects)
+			projectRoutes.PATCH("/:id", projectHandler.UpdateProject)
+			projectRoutes.DELETE("/:id", projectHandler.DeleteProject)
+		}
+
+		templateRoutes := public.Group("/templates")
+		templateRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			templateRoutes.POST("", templateHandler.CreateTemplate)
+			templateRoutes.GET("", templateHandler.ListTemplates)
+			templateRoutes.GET("/:id", templateHandler.GetTemplate)
+			templateRoutes.PUT("/:id", templateHandler.UpdateTemplate)
+			templateRoutes.DELETE("/:id", templateHandler.DeleteTemplate)
+			templateRoutes.POST("/:id/instantiate", templateHandler.Instantiate)
+		}
+
+		notificationRoutes := public.Group("/notifications")
+		notificationRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			notificationRoutes.GET("", notificationHandler.ListNotifications)
+			notificationRoutes.POST("/read-all", notificationHandler.MarkAllRead)
+			notificationRoutes.POST("/:id/read", notificationHandler.MarkRead)
+			notificationRoutes.GET("/preferences", notificationHandler.GetPreferences)
+			notificationRoutes.PUT("/preferences", notificationHandler.UpdatePreferences)
+		}
+
+		webhookRoutes := public.Group("/webhooks")
+		webhookRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			webhookRoutes.POST("", webhookHandler.CreateWebhook)
+			webhookRoutes.GET("", webhookHandler.ListWebhooks)
+			webhookRoutes.GET("/:id", webhookHandler.GetWebhook)
+			webhookRoutes.PUT("/:id", webhookHandler.UpdateWebhook)
+			webhookRoutes.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhookRoutes.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhookRoutes.POST("/:id/deliveries/:deliveryID/redeliver", webhookHandler.Redeliver)
+		}
+
+		importRoutes := public.Group("/imports")
+		importRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			importRoutes.POST("", importJobHandler.CreateImport)
+			importRoutes.GET("", importJobHandler.ListImports)
+			importRoutes.GET("/:id", importJobHandler.GetImport)
+			importRoutes.POST("/:id/rerun", importJobHandler.RerunImport)
+			importRoutes.DELETE("/:id", importJobHandler.DeleteImport)
+		}
+
+		eventRoutes := public.Group("/events")
+		eventRoutes.Use(userAuth.OptionalAuthMiddleware())
+		{
+			eventRoutes.GET("/stream", streamHandler.Stream)
+		}
+
+		// The collaboration channel authenticates itself, since browsers
+		// can't set headers on WebSocket connections.
+		public.GET("/collab", collabHandler.Connect)
+
+		inboundRoutes := public.Group("/inbound-addresses")
+		inboundRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			inboundRoutes.POST("", inboundHandler.CreateAddress)
+			inboundRoutes.GET("", inboundHandler.ListAddresses)
+			inboundRoutes.DELETE("/:id", inboundHandler.DeleteAddress)
+		}
+
+		calendarFeedRoutes := public.Group("/calendar-feeds")
+		calendarFeedRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			calendarFeedRoutes.POST("", calendarHandler.CreateFeed)
+			calendarFeedRoutes.GET("", calendarHandler.ListFeeds)
+			calendarFeedRoutes.DELETE("/:id", calendarHandler.DeleteFeed)
+		}
+
+		calendarRoutes := public.Group("/calendar")
+		calendarRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			calendarRoutes.POST("/import", calendarHandler.Import)
+		}
+
+		accessTokenRoutes := public.Group("/access-tokens")
+		accessTokenRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			accessTokenRoutes.POST("", accessTokenHandler.CreateToken)
+			accessTokenRoutes.GET("", accessTokenHandler.ListTokens)
+			accessTokenRoutes.DELETE("/:id", accessTokenHandler.DeleteToken)
+		}
+
+		// Capture URLs are authorized by the secret token in the path.
+		public.POST("/inbound/:token", inboundHandler.Capture)
+
+		// Calendar feeds are fetched by calendar apps, which authenticate
+		// with the secret token in the path: /calendar/<token>.ics
+		public.GET("/calendar/:token", calendarHandler.Feed)
+
+		// CalDAV clients authenticate with HTTP Basic, a personal access
+		// token as the password.
+		public.GET("/.well-known/caldav", caldavHandler.WellKnown)
+		public.Handle("PROPFIND", "/.well-known/caldav", caldavHandler.WellKnown)
+		davRoutes := public.Group(caldav_handler.Root)
+		davRoutes.Use(caldavHandler.BasicAuthMiddleware())
+		{
+			for _, method := range caldav_handler.Methods {
+				davRoutes.Handle(method, "/*path", caldavHandler.ServeDAV)
+			}
+		}
+
+		boardRoutes := public.Group("/boards")
+		boardRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			boardRoutes.GET("", boardHandler.GetBoard)
+			boardRoutes.PUT("/columns/:status", boardHandler.SetWIPLimit)
+		}
+
+		reportRoutes := public.Group("/reports")
+		reportRoutes.Use(userAuth.OptionalAuthMiddleware())
+		{
+			reportRoutes.GET("/time", timeEntryHandler.TimeReport)
+		}
+
+		statsRoutes := public.Group("/stats")
+		statsRoutes.Use(userAuth.OptionalAuthMiddleware())
+		{
+			statsRoutes.GET("", statsHandler.GetStats)
+		}
+
+		filterRoutes := public.Group("/filters")
+		filterRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
+		{
+			filterRoutes.POST("", filterHandler.CreateFilter)
+			filterRoutes.GET("", filterHandler.ListFilters)
+			filterRoutes.GET("/:id", filterHandler.GetFilter)
+			filterRoutes.PUT("/:id", filterHandler.UpdateFilter)
+			filterRoutes.DELETE("/:id", filterHandler.DeleteFilter)
+			filterRoutes.GET("/:id/tasks", filterHandler.RunFilter)
 		}
 
 		userRoutes := public.Group("/users")
-		userRoutes.Use(userAuth.OptionalAuthMiddleware())
+		userRoutes.Use(userAuth.OptionalAuthMiddleware(), idem.Middleware())
 		{
 			userRoutes.PATCH("/password", userHandler.UpdatePassword)
 			userRoutes.DELETE("/account", userHandler.DeleteUser)
+			userRoutes.PATCH("/timezone", userHandler.UpdateTimeZone)
 		}
 	}
 
@@ -0,0 +1,21 @@
+package blobstore
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+// BlobStore stores opaque binary objects addressed by slash-separated keys.
+type BlobStore interface {
+	// Put writes size bytes from r under key, replacing any existing blob.
+	Put(key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key. Callers must close the reader.
+	Get(key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(key string) error
+}
@@ -0,0 +1,38 @@
+package blobstore
+
+import (
+	"fmt"
+	"taskflow/pkg"
+)
+
+type Config struct {
+	Driver   string // "local" or "s3"
+	LocalDir string
+	S3       S3Config
+}
+
+func LoadConfigFromEnv() *Config {
+	return &Config{
+		Driver:   pkg.GetEnv("BLOB_STORE", "local"),
+		LocalDir: pkg.GetEnv("BLOB_LOCAL_DIR", "./data/blobs"),
+		S3: S3Config{
+			Endpoint:  pkg.GetEnv("S3_ENDPOINT", "http://minio:9000"),
+			Bucket:    pkg.GetEnv("S3_BUCKET", "taskflow"),
+			Region:    pkg.GetEnv("S3_REGION", "us-east-1"),
+			AccessKey: pkg.GetEnv("S3_ACCESS_KEY", "minioadmin"),
+			SecretKey: pkg.GetEnv("S3_SECRET_KEY", "minioadmin"),
+		},
+	}
+}
+
+// New builds the BlobStore selected by cfg.Driver.
+func New(cfg *Config) (BlobStore, error) {
+	switch cfg.Driver {
+	case "local":
+		return NewLocalStore(cfg.LocalDir)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store driver %q", cfg.Driver)
+	}
+}
@@ -0,0 +1,53 @@
+package blobstore
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_RoundTrip(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put("tasks/1/abc", strings.NewReader("hello"), 5, "text/plain"))
+
+	rc, err := s.Get("tasks/1/abc")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	require.NoError(t, s.Delete("tasks/1/abc"))
+	_, err = s.Get("tasks/1/abc")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	// Deleting twice is fine
+	assert.NoError(t, s.Delete("tasks/1/abc"))
+}
+
+func TestLocalStore_ShortWrite(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	err = s.Put("a", strings.NewReader("abc"), 10, "")
+	assert.Error(t, err)
+
+	_, err = s.Get("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStore_InvalidKeys(t *testing.T) {
+	s, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../escape", "a/../../b", "a//b", `a\b`} {
+		t.Run(key, func(t *testing.T) {
+			assert.ErrorIs(t, s.Put(key, strings.NewReader("x"), 1, ""), ErrInvalidKey)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package blobstore
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible server.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	authz   []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.authz = append(f.authz, r.Header.Get("Authorization"))
+	if r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = data
+	case http.MethodGet:
+		data, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store_RoundTrip(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	s, err := NewS3Store(S3Config{
+		Endpoint:  srv.URL,
+		Bucket:    "attachments",
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "secret",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put("tasks/1/spec.pdf", strings.NewReader("%PDF-1.4"), 8, "application/pdf"))
+	assert.Contains(t, fake.objects, "/attachments/tasks/1/spec.pdf")
+
+	rc, err := s.Get("tasks/1/spec.pdf")
+	require.NoError(t, err)
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	assert.Equal(t, "%PDF-1.4", string(data))
+
+	require.NoError(t, s.Delete("tasks/1/spec.pdf"))
+	_, err = s.Get("tasks/1/spec.pdf")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	require.NotEmpty(t, fake.authz)
+	assert.True(t, strings.HasPrefix(fake.authz[0], "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"))
+	assert.Contains(t, fake.authz[0], "/us-east-1/s3/aws4_request")
+}
+
+func TestS3Store_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("AccessDenied"))
+	}))
+	defer srv.Close()
+
+	s, err := NewS3Store(S3Config{Endpoint: srv.URL, Bucket: "b"})
+	require.NoError(t, err)
+
+	err = s.Put("k", strings.NewReader("x"), 1, "")
+	assert.ErrorContains(t, err, "AccessDenied")
+}
+
+func TestDeriveSigningKey(t *testing.T) {
+	// Example from the AWS SigV4 documentation.
+	key := deriveSigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+	assert.Equal(t, "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d", hex.EncodeToString(key))
+}
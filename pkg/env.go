@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"taskflow/internal/common"
+	"time"
 )
 
 func GetEnv(key, fallback string) string {
@@ -24,3 +26,32 @@ func GetEnv(key, fallback string) string {
 
 	return fallback
 }
+
+// DurationEnv reads a positive duration such as 30s from key and exits
+// when it is not one.
+func DurationEnv(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(GetEnv(key, fallback.String()))
+	if err != nil || d <= 0 {
+		log.Fatalf("invalid %s: must be a positive duration such as 30s", key)
+	}
+	return d
+}
+
+// IntEnv reads a positive number from key and exits when it is not one.
+func IntEnv(key string, fallback int) int {
+	n, err := strconv.Atoi(GetEnv(key, strconv.Itoa(fallback)))
+	if err != nil || n <= 0 {
+		log.Fatalf("invalid %s: must be a positive number", key)
+	}
+	return n
+}
+
+// FloatEnv reads a positive number such as 0.5 from key and exits when it
+// is not one.
+func FloatEnv(key string, fallback float64) float64 {
+	f, err := strconv.ParseFloat(GetEnv(key, strconv.FormatFloat(fallback, 'g', -1, 64)), 64)
+	if err != nil || f <= 0 {
+		log.Fatalf("invalid %s: must be a positive number", key)
+	}
+	return f
+}
@@ -105,3 +105,16 @@ func TestGetEnv_EmptyFallbackWithSetEnv(t *testing.T) {
 		t.Errorf("GetEnv() = %q, want %q", result, value)
 	}
 }
+
+func TestFloatEnv(t *testing.T) {
+	os.Unsetenv("FLOAT_VAR")
+	if got := FloatEnv("FLOAT_VAR", 20); got != 20 {
+		t.Errorf("FloatEnv() = %v, want 20", got)
+	}
+
+	os.Setenv("FLOAT_VAR", "0.5")
+	defer os.Unsetenv("FLOAT_VAR")
+	if got := FloatEnv("FLOAT_VAR", 20); got != 0.5 {
+		t.Errorf("FloatEnv() = %v, want 0.5", got)
+	}
+}
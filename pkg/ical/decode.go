@@ -0,0 +1,188 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrMalformed is returned for data that is not valid iCalendar.
+var ErrMalformed = errors.New("ical: malformed calendar")
+
+// maxDepth bounds how deeply components may nest.
+const maxDepth = 10
+
+// Decode reads every top-level component, usually one VCALENDAR, from r.
+func Decode(r io.Reader) ([]*Component, error) {
+	var roots, stack []*Component
+	lines := newUnfolder(r)
+	for {
+		line, err := lines.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		num := lines.num
+		if line == "" {
+			continue
+		}
+
+		p, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrMalformed, num, err)
+		}
+		switch p.Name {
+		case "BEGIN":
+			if len(stack) == maxDepth {
+				return nil, fmt.Errorf("%w: line %d: components nested too deeply", ErrMalformed, num)
+			}
+			c := &Component{Name: strings.ToUpper(p.Value)}
+			if len(stack) == 0 {
+				roots = append(roots, c)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Components = append(parent.Components, c)
+			}
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].Name != strings.ToUpper(p.Value) {
+				return nil, fmt.Errorf("%w: line %d: unexpected END:%s", ErrMalformed, num, p.Value)
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("%w: line %d: property outside of a component", ErrMalformed, num)
+			}
+			c := stack[len(stack)-1]
+			c.Props = append(c.Props, p)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("%w: %s is not closed", ErrMalformed, stack[len(stack)-1].Name)
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("%w: no components", ErrMalformed)
+	}
+	return roots, nil
+}
+
+// parseLine splits a content line into name, parameters and value.
+func parseLine(line string) (Property, error) {
+	var p Property
+	i := strings.IndexAny(line, ";:")
+	if i <= 0 {
+		return p, errors.New("missing name")
+	}
+	p.Name = strings.ToUpper(line[:i])
+
+	for line[i] == ';' {
+		rest := line[i+1:]
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 {
+			return p, errors.New("malformed parameter")
+		}
+		name := strings.ToUpper(rest[:eq])
+		i += 1 + eq + 1
+		for {
+			value, n, err := paramValueAt(line[i:])
+			if err != nil {
+				return p, err
+			}
+			if p.Params == nil {
+				p.Params = Params{}
+			}
+			p.Params[name] = append(p.Params[name], value)
+			i += n
+			if i >= len(line) {
+				return p, errors.New("missing value")
+			}
+			if line[i] != ',' {
+				break
+			}
+			i++
+		}
+		if line[i] != ';' && line[i] != ':' {
+			return p, errors.New("malformed parameter")
+		}
+	}
+
+	p.Value = line[i+1:]
+	return p, nil
+}
+
+// paramValueAt reads one parameter value, quoted or not, from the start
+// of s and returns it with the number of bytes read.
+func paramValueAt(s string) (string, int, error) {
+	if strings.HasPrefix(s, `"`) {
+		end := strings.IndexByte(s[1:], '"')
+		if end < 0 {
+			return "", 0, errors.New("unterminated quoted parameter")
+		}
+		return s[1 : end+1], end + 2, nil
+	}
+	end := strings.IndexAny(s, ",;:")
+	if end < 0 {
+		return "", 0, errors.New("missing value")
+	}
+	return s[:end], end, nil
+}
+
+// unfolder joins folded lines: a line break followed by a space or tab
+// continues the previous line.
+type unfolder struct {
+	r       *bufio.Reader
+	pending string
+	held    bool
+	read    int
+	// num is the line number where the last line returned by next starts.
+	num int
+}
+
+func newUnfolder(r io.Reader) *unfolder {
+	return &unfolder{r: bufio.NewReader(r)}
+}
+
+func (u *unfolder) readRaw() (string, error) {
+	line, err := u.r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	u.read++
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (u *unfolder) next() (string, error) {
+	var line string
+	if u.held {
+		line, u.held = u.pending, false
+	} else {
+		var err error
+		if line, err = u.readRaw(); err != nil {
+			return "", err
+		}
+		if u.read == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+	}
+	u.num = u.read
+
+	for {
+		raw, err := u.readRaw()
+		if errors.Is(err, io.EOF) {
+			return line, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if strings.HasPrefix(raw, " ") || strings.HasPrefix(raw, "\t") {
+			line += raw[1:]
+			continue
+		}
+		u.pending, u.held = raw, true
+		return line, nil
+	}
+}
@@ -0,0 +1,93 @@
+package ical
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxLineOctets is the longest a line may be before it is folded,
+// excluding the line break.
+const maxLineOctets = 75
+
+// Encoder writes components as iCalendar text.
+type Encoder struct {
+	w *bufio.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// Encode writes c and its sub-components.
+func (e *Encoder) Encode(c *Component) error {
+	e.component(c)
+	return e.w.Flush()
+}
+
+func (e *Encoder) component(c *Component) {
+	e.line("BEGIN:" + c.Name)
+	for _, p := range c.Props {
+		e.line(contentLine(p))
+	}
+	for _, sub := range c.Components {
+		e.component(sub)
+	}
+	e.line("END:" + c.Name)
+}
+
+func contentLine(p Property) string {
+	var b strings.Builder
+	b.WriteString(p.Name)
+
+	names := make([]string, 0, len(p.Params))
+	for name := range p.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteByte(';')
+		b.WriteString(strings.ToUpper(name))
+		b.WriteByte('=')
+		for i, v := range p.Params[name] {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(paramValue(v))
+		}
+	}
+
+	b.WriteByte(':')
+	b.WriteString(strings.NewReplacer("\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(p.Value))
+	return b.String()
+}
+
+// paramValue quotes a parameter value that contains separators. Double
+// quotes can't be represented and are dropped.
+func paramValue(v string) string {
+	v = strings.ReplaceAll(v, `"`, "")
+	if strings.ContainsAny(v, ":;,") {
+		return `"` + v + `"`
+	}
+	return v
+}
+
+// line writes s folded into lines of at most maxLineOctets, never
+// splitting a UTF-8 sequence. Continuation lines start with a space.
+func (e *Encoder) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		e.w.WriteString(s[:cut])
+		e.w.WriteString("\r\n ")
+		s = s[cut:]
+		limit = maxLineOctets - 1
+	}
+	e.w.WriteString(s)
+	e.w.WriteString("\r\n")
+}
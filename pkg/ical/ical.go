@@ -0,0 +1,243 @@
+// Package ical reads and writes iCalendar data (RFC 5545).
+//
+// A calendar is a tree of components, such as VCALENDAR, VTODO and
+// VEVENT, each with properties. Property values are kept in their encoded
+// form; Text, AddText and the date helpers convert TEXT and DATE-TIME
+// values. The encoder folds long lines and the decoder unfolds them.
+package ical
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Component is a BEGIN:/END: block, such as VCALENDAR or VTODO.
+type Component struct {
+	Name       string
+	Props      []Property
+	Components []*Component
+}
+
+// Property is one content line. Value is kept as written on the line, so
+// TEXT values are escaped.
+type Property struct {
+	Name   string
+	Params Params
+	Value  string
+}
+
+// Params are property parameters, such as TZID or VALUE.
+type Params map[string][]string
+
+// Get returns the first value of a parameter, or "".
+func (p Params) Get(name string) string {
+	if v := p[strings.ToUpper(name)]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// NewCalendar returns a VCALENDAR with the properties every calendar needs.
+func NewCalendar(prodID string) *Component {
+	c := &Component{Name: "VCALENDAR"}
+	c.Add("VERSION", "2.0")
+	c.Add("PRODID", prodID)
+	c.Add("CALSCALE", "GREGORIAN")
+	return c
+}
+
+// Add appends a property with an already encoded value.
+func (c *Component) Add(name string, value string) *Property {
+	c.Props = append(c.Props, Property{Name: strings.ToUpper(name), Value: value})
+	return &c.Props[len(c.Props)-1]
+}
+
+// AddText appends a TEXT property, escaping value.
+func (c *Component) AddText(name string, value string) *Property {
+	return c.Add(name, EscapeText(value))
+}
+
+// AddDateTime appends a DATE-TIME property in UTC.
+func (c *Component) AddDateTime(name string, t time.Time) *Property {
+	return c.Add(name, FormatDateTime(t))
+}
+
+// AddDate appends a DATE property.
+func (c *Component) AddDate(name string, t time.Time) *Property {
+	p := c.Add(name, FormatDate(t))
+	p.Params = Params{"VALUE": {"DATE"}}
+	return p
+}
+
+// Get returns the first property with the given name, or nil.
+func (c *Component) Get(name string) *Property {
+	name = strings.ToUpper(name)
+	for i := range c.Props {
+		if c.Props[i].Name == name {
+			return &c.Props[i]
+		}
+	}
+	return nil
+}
+
+// Text returns the unescaped value of the first property with the given
+// name, or "".
+func (c *Component) Text(name string) string {
+	if p := c.Get(name); p != nil {
+		return p.Text()
+	}
+	return ""
+}
+
+// Children returns the sub-components with the given name.
+func (c *Component) Children(name string) []*Component {
+	name = strings.ToUpper(name)
+	var out []*Component
+	for _, sub := range c.Components {
+		if sub.Name == name {
+			out = append(out, sub)
+		}
+	}
+	return out
+}
+
+// Text unescapes a TEXT value.
+func (p *Property) Text() string {
+	return UnescapeText(p.Value)
+}
+
+// Texts splits a multi-valued TEXT property, such as CATEGORIES, and
+// unescapes the values.
+func (p *Property) Texts() []string {
+	var out []string
+	start := 0
+	for i := 0; i < len(p.Value); i++ {
+		switch p.Value[i] {
+		case '\\':
+			i++
+		case ',':
+			out = append(out, UnescapeText(p.Value[start:i]))
+			start = i + 1
+		}
+	}
+	return append(out, UnescapeText(p.Value[start:]))
+}
+
+// Time parses a DATE or DATE-TIME value. Times with a TZID are read in
+// that zone, floating times and dates in loc. allDay reports a DATE.
+func (p *Property) Time(loc *time.Location) (t time.Time, allDay bool, err error) {
+	if tzid := p.Params.Get("TZID"); tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	return ParseTime(p.Value, loc)
+}
+
+const (
+	dateTimeLayout = "20060102T150405Z"
+	floatingLayout = "20060102T150405"
+	dateLayout     = "20060102"
+)
+
+// FormatDateTime formats t as a UTC DATE-TIME.
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// FormatDate formats the day of t, in t's location, as a DATE.
+func FormatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+// ParseTime parses a DATE or DATE-TIME value. Floating times and dates
+// are read in loc.
+func ParseTime(value string, loc *time.Location) (t time.Time, allDay bool, err error) {
+	switch {
+	case len(value) == len(dateLayout):
+		t, err = time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse(dateTimeLayout, value)
+		return t, false, err
+	default:
+		t, err = time.ParseInLocation(floatingLayout, value, loc)
+		return t, false, err
+	}
+}
+
+// FormatDuration formats d as a DURATION, such as PT1H30M.
+func FormatDuration(d time.Duration) string {
+	var b strings.Builder
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteString("P")
+	if days := d / (24 * time.Hour); days > 0 {
+		fmt.Fprintf(&b, "%dD", days)
+		d -= days * 24 * time.Hour
+	}
+	if d == 0 && b.Len() > 1 {
+		return b.String()
+	}
+	b.WriteByte('T')
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if s := d / time.Second; s > 0 || strings.HasSuffix(b.String(), "T") {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
+
+// EscapeText escapes a TEXT value: backslashes, semicolons, commas and
+// line breaks.
+func EscapeText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\', ';', ',':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				continue
+			}
+			b.WriteString(`\n`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// UnescapeText reverses EscapeText.
+func UnescapeText(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,134 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncode(t *testing.T) {
+	cal := NewCalendar("-//TaskFlow//EN")
+	todo := &Component{Name: "VTODO"}
+	todo.Add("UID", "task-1@taskflow")
+	todo.AddDateTime("DUE", time.Date(2025, 9, 1, 19, 0, 0, 0, time.FixedZone("CEST", 2*3600)))
+	todo.AddDate("DTSTART", time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC))
+	todo.AddText("SUMMARY", "Milk, eggs; bread\\butter")
+	todo.AddText("DESCRIPTION", "line one\r\nline two")
+	todo.Add("X-NOTE", "v").Params = Params{"X-WHERE": {"a:b"}, "LANGUAGE": {"en"}}
+	cal.Components = append(cal.Components, todo)
+
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(cal))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"PRODID:-//TaskFlow//EN\r\n"+
+		"CALSCALE:GREGORIAN\r\n"+
+		"BEGIN:VTODO\r\n"+
+		"UID:task-1@taskflow\r\n"+
+		"DUE:20250901T170000Z\r\n"+
+		"DTSTART;VALUE=DATE:20250901\r\n"+
+		"SUMMARY:Milk\\, eggs\\; bread\\\\butter\r\n"+
+		"DESCRIPTION:line one\\nline two\r\n"+
+		"X-NOTE;LANGUAGE=en;X-WHERE=\"a:b\":v\r\n"+
+		"END:VTODO\r\n"+
+		"END:VCALENDAR\r\n", buf.String())
+}
+
+func TestEncode_Folding(t *testing.T) {
+	c := &Component{Name: "VTODO"}
+	summary := strings.Repeat("añ€", 40)
+	c.AddText("SUMMARY", summary)
+
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(c))
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 4)
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75, "line %d", i)
+		assert.True(t, utf8.ValidString(line), "line %d splits a character", i)
+		if i > 1 && i < len(lines)-1 {
+			assert.True(t, strings.HasPrefix(line, " "), "line %d is a continuation", i)
+		}
+	}
+
+	roots, err := Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, summary, roots[0].Text("SUMMARY"), "decoding unfolds")
+}
+
+func TestDecode(t *testing.T) {
+	data := "\ufeffBEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"BEGIN:VTODO\r\n" +
+		"UID:abc\r\n" +
+		"SUMMARY:Buy milk\\, eggs\r\n" +
+		"DESCRIPTION:two\\nlines and a long\r\n" +
+		"  folded tail\r\n" +
+		"DUE;TZID=Europe/Berlin:20250901T190000\r\n" +
+		"DTSTART;VALUE=DATE:20250830\r\n" +
+		"CATEGORIES:home,errands\\,urgent\r\n" +
+		"X-PARAMS;A=\"x:y\",z;B=1:value:with:colons\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"ACTION:DISPLAY\r\n" +
+		"END:VALARM\r\n" +
+		"END:VTODO\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:ev\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\n"
+
+	roots, err := Decode(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, roots, 1)
+	todos := roots[0].Children("VTODO")
+	require.Len(t, todos, 1)
+	todo := todos[0]
+
+	assert.Equal(t, "Buy milk, eggs", todo.Text("SUMMARY"))
+	assert.Equal(t, "two\nlines and a long folded tail", todo.Text("DESCRIPTION"))
+	assert.Equal(t, []string{"home", "errands,urgent"}, todo.Get("CATEGORIES").Texts())
+	assert.Len(t, todo.Children("VALARM"), 1)
+
+	due, allDay, err := todo.Get("DUE").Time(time.UTC)
+	require.NoError(t, err)
+	assert.False(t, allDay)
+	assert.Equal(t, time.Date(2025, 9, 1, 17, 0, 0, 0, time.UTC), due.UTC())
+
+	start, allDay, err := todo.Get("DTSTART").Time(time.UTC)
+	require.NoError(t, err)
+	assert.True(t, allDay)
+	assert.Equal(t, time.Date(2025, 8, 30, 0, 0, 0, 0, time.UTC), start)
+
+	p := todo.Get("X-PARAMS")
+	assert.Equal(t, []string{"x:y", "z"}, p.Params["A"])
+	assert.Equal(t, "1", p.Params.Get("b"))
+	assert.Equal(t, "value:with:colons", p.Value)
+}
+
+func TestDecode_Malformed(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":          "",
+		"unclosed":       "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"mismatched end": "BEGIN:VCALENDAR\r\nBEGIN:VTODO\r\nEND:VCALENDAR\r\n",
+		"stray property": "VERSION:2.0\r\n",
+		"no colon":       "BEGIN:VCALENDAR\r\nVERSION\r\nEND:VCALENDAR\r\n",
+		"bad parameter":  "BEGIN:VCALENDAR\r\nX;A=\"open:1\r\nEND:VCALENDAR\r\n",
+		"too deep":       strings.Repeat("BEGIN:X\r\n", 11),
+	} {
+		_, err := Decode(strings.NewReader(data))
+		assert.ErrorIs(t, err, ErrMalformed, name)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "PT1H30M", FormatDuration(90*time.Minute))
+	assert.Equal(t, "P1DT2H", FormatDuration(26*time.Hour))
+	assert.Equal(t, "P2D", FormatDuration(48*time.Hour))
+	assert.Equal(t, "PT0S", FormatDuration(0))
+	assert.Equal(t, "-PT15M", FormatDuration(-15*time.Minute))
+}
@@ -0,0 +1,142 @@
+// Package lexorank generates string sort keys that can always be squeezed
+// between two neighbours, so reordering one item never rewrites the others.
+//
+// Keys are base-36 fractions (digits then lower-case letters, so they order
+// the same under case-insensitive collations) and never end in '0', which
+// guarantees there is always room below a key.
+package lexorank
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const base = len(digits)
+
+// MaxLength is the key length after which callers should Spread the keys of
+// the whole list again.
+const MaxLength = 32
+
+var (
+	ErrInvalidKey   = errors.New("lexorank: invalid key")
+	ErrInvalidRange = errors.New("lexorank: lower key must sort before upper key")
+)
+
+// Between returns a key that sorts strictly after lo and before hi. An empty
+// lo means the start of the list and an empty hi the end of it.
+func Between(lo, hi string) (string, error) {
+	if !Valid(lo) && lo != "" || !Valid(hi) && hi != "" {
+		return "", ErrInvalidKey
+	}
+	if hi != "" && lo >= hi {
+		return "", ErrInvalidRange
+	}
+	return midpoint(lo, hi), nil
+}
+
+// Valid reports whether key is a non-empty key this package could have produced.
+func Valid(key string) bool {
+	if key == "" || key[len(key)-1] == '0' {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// midpoint assumes lo < hi, with hi == "" standing for the end of the list.
+func midpoint(lo, hi string) string {
+	if hi != "" {
+		// Skip the common prefix, padding lo with zeros.
+		n := 0
+		for n < len(hi) && digitAt(lo, n) == hi[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(lo) {
+				rest = lo[n:]
+			}
+			return hi[:n] + midpoint(rest, hi[n:])
+		}
+	}
+
+	dlo := 0
+	if lo != "" {
+		dlo = strings.IndexByte(digits, lo[0])
+	}
+	dhi := base
+	if hi != "" {
+		dhi = strings.IndexByte(digits, hi[0])
+	}
+	if dhi-dlo > 1 {
+		return string(digits[(dlo+dhi+1)/2])
+	}
+
+	// The first digits are adjacent.
+	if len(hi) > 1 {
+		return hi[:1]
+	}
+	rest := ""
+	if len(lo) > 1 {
+		rest = lo[1:]
+	}
+	return string(digits[dlo]) + midpoint(rest, "")
+}
+
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return '0'
+}
+
+// Spread returns n evenly spaced, ascending keys of equal length, used to
+// rebalance a list whose keys have grown too long.
+func Spread(n int) []string {
+	if n <= 0 {
+		return nil
+	}
+
+	// Use enough digits to leave at least base-1 free keys between neighbours.
+	width := 1
+	space := big.NewInt(int64(base))
+	limit := big.NewInt(int64(n+1) * int64(base))
+	for space.Cmp(limit) < 0 {
+		space.Mul(space, big.NewInt(int64(base)))
+		width++
+	}
+	step := new(big.Int).Div(space, big.NewInt(int64(n+1)))
+
+	keys := make([]string, n)
+	value := new(big.Int)
+	for i := range keys {
+		value.Add(value, step)
+		key := value.Text(base)
+		key = strings.Repeat("0", width-len(key)) + key
+		keys[i] = strings.TrimRight(key, "0")
+	}
+	return keys
+}
+
+// After returns n ascending keys that sort after key, for appending several
+// items to the end of a list at once. key must be the last key of the list;
+// an empty key means the list is empty.
+func After(key string, n int) ([]string, error) {
+	if key != "" && !Valid(key) {
+		return nil, ErrInvalidKey
+	}
+	// Every key that starts with key sorts after it, and nothing else in
+	// the list does.
+	keys := Spread(n)
+	for i := range keys {
+		keys[i] = key + keys[i]
+	}
+	return keys, nil
+}
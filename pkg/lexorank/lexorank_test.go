@@ -0,0 +1,100 @@
+package lexorank
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		lo, hi string
+		want   string
+	}{
+		{"", "", "i"},
+		{"i", "", "r"},
+		{"", "i", "9"},
+		{"a", "b", "ai"},
+		{"a", "a1", "a0i"},
+		{"az", "b", "azi"},
+		{"", "01", "00i"},
+		{"zz", "", "zzi"},
+	}
+	for _, tt := range tests {
+		got, err := Between(tt.lo, tt.hi)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got, "Between(%q, %q)", tt.lo, tt.hi)
+		assert.True(t, Valid(got))
+		assert.Greater(t, got, tt.lo)
+		if tt.hi != "" {
+			assert.Less(t, got, tt.hi)
+		}
+	}
+}
+
+func TestBetween_Errors(t *testing.T) {
+	_, err := Between("b", "a")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = Between("a", "a")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = Between("a0", "")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = Between("", "A")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestBetween_RandomInsertsKeepOrder(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var keys []string
+	for i := 0; i < 500; i++ {
+		at := rng.Intn(len(keys) + 1)
+		lo, hi := "", ""
+		if at > 0 {
+			lo = keys[at-1]
+		}
+		if at < len(keys) {
+			hi = keys[at]
+		}
+		key, err := Between(lo, hi)
+		require.NoError(t, err)
+		keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+	}
+	assert.True(t, sort.StringsAreSorted(keys))
+}
+
+func TestSpread(t *testing.T) {
+	assert.Nil(t, Spread(0))
+
+	for _, n := range []int{1, 35, 36, 1000} {
+		keys := Spread(n)
+		require.Len(t, keys, n)
+		assert.True(t, sort.StringsAreSorted(keys))
+		for i, k := range keys {
+			assert.True(t, Valid(k), k)
+			if i > 0 {
+				assert.NotEqual(t, keys[i-1], k)
+			}
+		}
+	}
+	assert.Equal(t, []string{"i"}, Spread(1))
+}
+
+func TestAfter(t *testing.T) {
+	for _, last := range []string{"", "i", "zz"} {
+		keys, err := After(last, 500)
+		require.NoError(t, err)
+		require.Len(t, keys, 500)
+		assert.True(t, sort.StringsAreSorted(keys))
+		assert.Greater(t, keys[0], last)
+		assert.LessOrEqual(t, len(keys[499]), len(last)+3, "keys stay short")
+		for _, k := range keys {
+			assert.True(t, Valid(k), k)
+		}
+	}
+
+	_, err := After("a0", 1)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -0,0 +1,144 @@
+package markdown
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	mentionPattern = regexp.MustCompile(`(^|[\s(])@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+	codePattern    = regexp.MustCompile("`([^`]+)`")
+	boldPattern    = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	italicPattern  = regexp.MustCompile(`\*([^*]+)\*`)
+	linkPattern    = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^\s)\x00]+)\)`)
+	heldPattern    = regexp.MustCompile("\x00([0-9]+)\x00")
+)
+
+// ExtractMentions returns the unique, lower-cased email addresses referenced
+// as @mentions in body, in order of first appearance.
+// A mention is written as "@" followed by the user's email, e.g. "@jane@example.com".
+func ExtractMentions(body string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+
+	for _, m := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		email := strings.ToLower(strings.TrimRight(m[2], "."))
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		mentions = append(mentions, email)
+	}
+
+	return mentions
+}
+
+// Render converts a small, safe subset of markdown to HTML.
+// All input is HTML-escaped first, so raw HTML in the source is never emitted.
+// Supported: paragraphs, "- " lists, fenced code blocks, `code`, **bold**,
+// *italic*, [links](https://...) and @mentions.
+func Render(src string) string {
+	var b strings.Builder
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+
+	var paragraph []string
+	inList := false
+	inCode := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + strings.Join(paragraph, "<br>") + "</p>")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if inList {
+			b.WriteString("</ul>")
+			inList = false
+		}
+	}
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			if inCode {
+				b.WriteString("</code></pre>")
+				inCode = false
+			} else {
+				flushParagraph()
+				closeList()
+				b.WriteString("<pre><code>")
+				inCode = true
+			}
+			continue
+		}
+
+		if inCode {
+			b.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case strings.HasPrefix(trimmed, "- "):
+			flushParagraph()
+			if !inList {
+				b.WriteString("<ul>")
+				inList = true
+			}
+			b.WriteString("<li>" + renderInline(strings.TrimPrefix(trimmed, "- ")) + "</li>")
+		default:
+			closeList()
+			paragraph = append(paragraph, renderInline(trimmed))
+		}
+	}
+
+	if inCode {
+		b.WriteString("</code></pre>")
+	}
+	flushParagraph()
+	closeList()
+
+	return b.String()
+}
+
+func renderInline(s string) string {
+	// NUL delimits the placeholders below, so it must not come from the input.
+	s = html.EscapeString(strings.ReplaceAll(s, "\x00", ""))
+
+	// Set code spans and then links aside first, so that their contents
+	// and hrefs are not formatted.
+	var held []string
+	hold := func(h string) string {
+		held = append(held, h)
+		return "\x00" + strconv.Itoa(len(held)-1) + "\x00"
+	}
+	restore := func(s string) string {
+		return heldPattern.ReplaceAllStringFunc(s, func(m string) string {
+			i, _ := strconv.Atoi(m[1 : len(m)-1])
+			return held[i]
+		})
+	}
+
+	s = codePattern.ReplaceAllStringFunc(s, func(m string) string {
+		return hold("<code>" + codePattern.FindStringSubmatch(m)[1] + "</code>")
+	})
+	s = linkPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sub := linkPattern.FindStringSubmatch(m)
+		return hold(`<a href="` + sub[2] + `" rel="nofollow noopener">` + restore(emphasize(sub[1])) + "</a>")
+	})
+	s = emphasize(s)
+	s = mentionPattern.ReplaceAllString(s, `$1<span class="mention">@$2</span>`)
+
+	return restore(s)
+}
+
+func emphasize(s string) string {
+	s = boldPattern.ReplaceAllString(s, "<strong>$1</strong>")
+	return italicPattern.ReplaceAllString(s, "<em>$1</em>")
+}
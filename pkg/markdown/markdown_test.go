@@ -0,0 +1,98 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "single mention",
+			body: "ping @jane@example.com please",
+			want: []string{"jane@example.com"},
+		},
+		{
+			name: "duplicates and case are collapsed",
+			body: "@Jane@Example.com and again @jane@example.com.",
+			want: []string{"jane@example.com"},
+		},
+		{
+			name: "multiple mentions keep order",
+			body: "(@bob@example.com) then @alice@example.org",
+			want: []string{"bob@example.com", "alice@example.org"},
+		},
+		{
+			name: "plain email is not a mention",
+			body: "mail jane@example.com",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExtractMentions(tt.body))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "escapes raw html",
+			src:  "<script>alert(1)</script>",
+			want: "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		},
+		{
+			name: "inline formatting",
+			src:  "**bold** and *italic* with `a*b*c`",
+			want: "<p><strong>bold</strong> and <em>italic</em> with <code>a*b*c</code></p>",
+		},
+		{
+			name: "links only allow http(s)",
+			src:  "[docs](https://example.com) [bad](javascript:alert(1))",
+			want: `<p><a href="https://example.com" rel="nofollow noopener">docs</a> [bad](javascript:alert(1))</p>`,
+		},
+		{
+			name: "links keep their href as written",
+			src:  "[**docs**](https://example.com/a*b*c/**x**) and *after*",
+			want: `<p><a href="https://example.com/a*b*c/**x**" rel="nofollow noopener"><strong>docs</strong></a> and <em>after</em></p>`,
+		},
+		{
+			name: "many code spans and NUL bytes",
+			src:  "`0` `1` `2` `3` `4` `5` `6` `7` `8` `9` `10` `11` \x000\x00",
+			want: "<p><code>0</code> <code>1</code> <code>2</code> <code>3</code> <code>4</code> <code>5</code> <code>6</code> " +
+				"<code>7</code> <code>8</code> <code>9</code> <code>10</code> <code>11</code> 0</p>",
+		},
+		{
+			name: "lists and paragraphs",
+			src:  "intro\n\n- one\n- two\n\noutro",
+			want: "<p>intro</p><ul><li>one</li><li>two</li></ul><p>outro</p>",
+		},
+		{
+			name: "fenced code",
+			src:  "```\n<b>x</b>\n```",
+			want: "<pre><code>&lt;b&gt;x&lt;/b&gt;\n</code></pre>",
+		},
+		{
+			name: "mentions",
+			src:  "hi @jane@example.com",
+			want: `<p>hi <span class="mention">@jane@example.com</span></p>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Render(tt.src))
+		})
+	}
+}
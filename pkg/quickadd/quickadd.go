@@ -0,0 +1,475 @@
+// Package quickadd parses one-line task descriptions such as
+// "Pay rent tomorrow 9am #finance !high every month" into task fields.
+//
+// Recognised phrases are removed from the text and reported as tokens, so
+// clients can show how the line was read. Everything else becomes the title.
+// Each kind of phrase is read once; a second date, for example, stays in the
+// title.
+package quickadd
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind tells what a token was read as.
+type Kind string
+
+const (
+	KindTag        Kind = "tag"
+	KindPriority   Kind = "priority"
+	KindDate       Kind = "date"
+	KindTime       Kind = "time"
+	KindRecurrence Kind = "recurrence"
+)
+
+// Token is a recognised phrase and its normalised value: a tag name, a
+// priority name, a YYYY-MM-DD date, an HH:MM time or a recurrence rule.
+type Token struct {
+	Text  string
+	Kind  Kind
+	Value string
+}
+
+// Result holds the fields read from a line. Recurrence is an iCalendar
+// RRULE value such as "FREQ=WEEKLY;BYDAY=MO".
+type Result struct {
+	Title      string
+	DueAt      *time.Time
+	Tags       []string
+	Priority   string
+	Recurrence string
+	Tokens     []Token
+}
+
+var ErrEmptyTitle = errors.New("quickadd: nothing is left for the title")
+
+// Due dates given without a time are due at the end of the day.
+const (
+	endOfDayHour   = 23
+	endOfDayMinute = 59
+)
+
+var priorities = map[string]bool{"none": true, "low": true, "medium": true, "high": true, "urgent": true}
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+var months = map[string]time.Month{
+	"january": time.January, "jan": time.January,
+	"february": time.February, "feb": time.February,
+	"march": time.March, "mar": time.March,
+	"april": time.April, "apr": time.April,
+	"may":  time.May,
+	"june": time.June, "jun": time.June,
+	"july": time.July, "jul": time.July,
+	"august": time.August, "aug": time.August,
+	"september": time.September, "sep": time.September, "sept": time.September,
+	"october": time.October, "oct": time.October,
+	"november": time.November, "nov": time.November,
+	"december": time.December, "dec": time.December,
+}
+
+var byDay = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Parse reads text relative to now, whose location decides what "today"
+// and "9am" mean.
+//
+// Weekday names mean the next such day, today included; "next friday"
+// excludes today. A bare hour after "at" is on the 24-hour clock. A time
+// without a date is today if it is still ahead and tomorrow otherwise. A
+// weekly recurrence on given days without a date is first due on the next of
+// those days.
+func Parse(text string, now time.Time) (Result, error) {
+	p := &parser{now: now, today: startOfDay(now)}
+	for _, w := range strings.Fields(text) {
+		p.words = append(p.words, w)
+		p.lower = append(p.lower, strings.ToLower(strings.TrimRight(w, ",.;")))
+	}
+
+	var title []string
+	for i := 0; i < len(p.words); {
+		if n := p.match(i); n > 0 {
+			i += n
+			continue
+		}
+		title = append(title, p.words[i])
+		i++
+	}
+
+	p.res.Title = strings.Join(title, " ")
+	if p.res.Title == "" {
+		return Result{}, ErrEmptyTitle
+	}
+	p.res.DueAt = p.due()
+	return p.res, nil
+}
+
+type parser struct {
+	words []string
+	lower []string
+	now   time.Time
+	today time.Time
+	res   Result
+
+	date    *time.Time
+	clock   *[2]int
+	repeats []time.Weekday
+}
+
+// match reads the phrase starting at word i and returns how many words it
+// used, or 0 when there is none.
+func (p *parser) match(i int) int {
+	for _, m := range []func(int) (int, Kind, string){p.tag, p.priority, p.recurrence, p.dateAt, p.timeAt} {
+		n, kind, value := m(i)
+		if n == 0 {
+			continue
+		}
+		p.res.Tokens = append(p.res.Tokens, Token{Text: strings.Join(p.words[i:i+n], " "), Kind: kind, Value: value})
+		return n
+	}
+	return 0
+}
+
+func (p *parser) word(i int) string {
+	if i < len(p.lower) {
+		return p.lower[i]
+	}
+	return ""
+}
+
+func (p *parser) tag(i int) (int, Kind, string) {
+	name := strings.TrimPrefix(p.word(i), "#")
+	if name == p.word(i) || name == "" || strings.HasPrefix(name, "#") {
+		return 0, "", ""
+	}
+	for _, t := range p.res.Tags {
+		if t == name {
+			return 1, KindTag, name
+		}
+	}
+	p.res.Tags = append(p.res.Tags, name)
+	return 1, KindTag, name
+}
+
+func (p *parser) priority(i int) (int, Kind, string) {
+	name := strings.TrimPrefix(p.word(i), "!")
+	if p.res.Priority != "" || name == p.word(i) || !priorities[name] {
+		return 0, "", ""
+	}
+	p.res.Priority = name
+	return 1, KindPriority, name
+}
+
+func (p *parser) recurrence(i int) (int, Kind, string) {
+	if p.res.Recurrence != "" {
+		return 0, "", ""
+	}
+
+	rule, n := "", 0
+	switch p.word(i) {
+	case "daily":
+		rule, n = "FREQ=DAILY", 1
+	case "weekly":
+		rule, n = "FREQ=WEEKLY", 1
+	case "monthly":
+		rule, n = "FREQ=MONTHLY", 1
+	case "yearly", "annually":
+		rule, n = "FREQ=YEARLY", 1
+	case "every":
+		rule, n = p.every(i + 1)
+		if n == 0 {
+			return 0, "", ""
+		}
+		n++
+	default:
+		return 0, "", ""
+	}
+	p.res.Recurrence = rule
+	return n, KindRecurrence, rule
+}
+
+// every reads what follows "every": "day", "3 weeks", "other month",
+// "weekday" or a weekday name.
+func (p *parser) every(i int) (string, int) {
+	interval, n := 1, 0
+	if p.word(i) == "other" {
+		interval, n = 2, 1
+	} else if v, err := strconv.Atoi(p.word(i)); err == nil && v > 0 && v < 1000 {
+		interval, n = v, 1
+	}
+
+	unit := strings.TrimSuffix(p.word(i+n), "s")
+	freq := map[string]string{"day": "DAILY", "week": "WEEKLY", "month": "MONTHLY", "year": "YEARLY"}[unit]
+	if freq != "" {
+		rule := "FREQ=" + freq
+		if interval > 1 {
+			rule += ";INTERVAL=" + strconv.Itoa(interval)
+		}
+		return rule, n + 1
+	}
+	if n > 0 {
+		return "", 0
+	}
+
+	if unit == "weekday" {
+		p.repeats = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", 1
+	}
+	if d, ok := weekdays[p.word(i)]; ok {
+		p.repeats = []time.Weekday{d}
+		return "FREQ=WEEKLY;BYDAY=" + byDay[d], 1
+	}
+	return "", 0
+}
+
+// dateAt reads a date, optionally after "on", "by" or "due".
+func (p *parser) dateAt(i int) (int, Kind, string) {
+	if p.date != nil {
+		return 0, "", ""
+	}
+	prefix := 0
+	switch p.word(i) {
+	case "on", "by", "due":
+		prefix = 1
+	}
+	d, n := p.parseDate(i + prefix)
+	if n == 0 {
+		return 0, "", ""
+	}
+	p.date = &d
+	return prefix + n, KindDate, d.Format("2006-01-02")
+}
+
+func (p *parser) parseDate(i int) (time.Time, int) {
+	w := p.word(i)
+	switch w {
+	case "today":
+		return p.today, 1
+	case "tomorrow":
+		return p.today.AddDate(0, 0, 1), 1
+	case "next":
+		switch p.word(i + 1) {
+		case "week":
+			days := (8 - int(p.today.Weekday())) % 7
+			if days == 0 {
+				days = 7
+			}
+			return p.today.AddDate(0, 0, days), 2
+		case "month":
+			y, m, _ := p.today.Date()
+			return time.Date(y, m+1, 1, 0, 0, 0, 0, p.today.Location()), 2
+		case "year":
+			return time.Date(p.today.Year()+1, time.January, 1, 0, 0, 0, 0, p.today.Location()), 2
+		}
+		if d, ok := weekdays[p.word(i+1)]; ok {
+			return p.nextWeekday(d, 1), 2
+		}
+		return time.Time{}, 0
+	case "in":
+		return p.relative(i + 1)
+	}
+
+	if d, ok := weekdays[w]; ok {
+		return p.nextWeekday(d, 0), 1
+	}
+	if d, err := time.ParseInLocation("2006-01-02", w, p.today.Location()); err == nil {
+		return d, 1
+	}
+
+	// "sep 5", "september 5th 2026", "5 sep"
+	if m, ok := months[w]; ok {
+		if day, ok := dayOfMonth(p.word(i + 1)); ok {
+			return p.monthDay(m, day, i+2, 2)
+		}
+	}
+	if day, ok := dayOfMonth(w); ok {
+		if m, ok := months[p.word(i+1)]; ok {
+			return p.monthDay(m, day, i+2, 2)
+		}
+	}
+	return time.Time{}, 0
+}
+
+// relative reads "3 days", "a week" or "2 months" after "in".
+func (p *parser) relative(i int) (time.Time, int) {
+	n, err := strconv.Atoi(p.word(i))
+	if p.word(i) == "a" || p.word(i) == "an" {
+		n, err = 1, nil
+	}
+	if err != nil || n < 0 || n > 1000 {
+		return time.Time{}, 0
+	}
+
+	switch strings.TrimSuffix(p.word(i+1), "s") {
+	case "day":
+		return p.today.AddDate(0, 0, n), 3
+	case "week":
+		return p.today.AddDate(0, 0, 7*n), 3
+	case "month":
+		return addMonths(p.today, n), 3
+	case "year":
+		return addMonths(p.today, 12*n), 3
+	}
+	return time.Time{}, 0
+}
+
+// monthDay resolves a day of a month, with an optional year at word i. Without
+// a year, a date that has passed means next year's.
+func (p *parser) monthDay(m time.Month, day int, i int, n int) (time.Time, int) {
+	year, explicit := p.today.Year(), false
+	if y, err := strconv.Atoi(p.word(i)); err == nil && len(p.word(i)) == 4 {
+		year, explicit = y, true
+		n++
+	}
+	d := time.Date(year, m, day, 0, 0, 0, 0, p.today.Location())
+	if d.Month() != m {
+		return time.Time{}, 0
+	}
+	if !explicit && d.Before(p.today) {
+		d = d.AddDate(1, 0, 0)
+	}
+	return d, n
+}
+
+// nextWeekday returns the first d at least skip days from today.
+func (p *parser) nextWeekday(d time.Weekday, skip int) time.Time {
+	day := p.today.AddDate(0, 0, skip)
+	for day.Weekday() != d {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// timeAt reads a time of day, optionally after "at".
+func (p *parser) timeAt(i int) (int, Kind, string) {
+	if p.clock != nil {
+		return 0, "", ""
+	}
+	prefix := 0
+	if p.word(i) == "at" {
+		prefix = 1
+	}
+	h, m, n := parseClock(p.word(i+prefix), p.word(i+prefix+1), prefix == 1)
+	if n == 0 {
+		return 0, "", ""
+	}
+	p.clock = &[2]int{h, m}
+	return prefix + n, KindTime, time.Date(0, 1, 1, h, m, 0, 0, time.UTC).Format("15:04")
+}
+
+// parseClock reads "9am", "9:30pm", "9 am", "21:00" or "noon", and a bare
+// hour such as "9" when it followed "at".
+func parseClock(w string, next string, bare bool) (int, int, int) {
+	if w == "noon" {
+		return 12, 0, 1
+	}
+
+	n := 1
+	suffix := ""
+	for _, s := range []string{"am", "pm"} {
+		if strings.HasSuffix(w, s) {
+			suffix, w = s, strings.TrimSuffix(w, s)
+		}
+	}
+	if suffix == "" && (next == "am" || next == "pm") {
+		suffix, n = next, 2
+	}
+
+	hs, ms, hasMinutes := strings.Cut(w, ":")
+	if suffix == "" && !hasMinutes && !bare {
+		return 0, 0, 0
+	}
+	h, err := strconv.Atoi(hs)
+	if err != nil || len(hs) > 2 {
+		return 0, 0, 0
+	}
+	m := 0
+	if hasMinutes {
+		if m, err = strconv.Atoi(ms); err != nil || len(ms) != 2 || m > 59 {
+			return 0, 0, 0
+		}
+	}
+
+	switch {
+	case suffix != "" && (h < 1 || h > 12):
+		return 0, 0, 0
+	case suffix == "am" && h == 12:
+		h = 0
+	case suffix == "pm" && h < 12:
+		h += 12
+	case h > 23:
+		return 0, 0, 0
+	}
+	return h, m, n
+}
+
+// due combines the date and time read into a due date, if any.
+func (p *parser) due() *time.Time {
+	day := p.date
+	if day == nil && len(p.repeats) > 0 {
+		first := p.nextWeekday(p.repeats[0], 0)
+		for _, d := range p.repeats[1:] {
+			if next := p.nextWeekday(d, 0); next.Before(first) {
+				first = next
+			}
+		}
+		day = &first
+	}
+	if day == nil && p.clock == nil {
+		return nil
+	}
+
+	h, m := endOfDayHour, endOfDayMinute
+	if p.clock != nil {
+		h, m = p.clock[0], p.clock[1]
+	}
+	if day == nil {
+		today := p.today
+		day = &today
+		if !atClock(today, h, m).After(p.now) {
+			tomorrow := today.AddDate(0, 0, 1)
+			day = &tomorrow
+		}
+	}
+	due := atClock(*day, h, m)
+	return &due
+}
+
+func atClock(day time.Time, h int, m int) time.Time {
+	y, mo, d := day.Date()
+	return time.Date(y, mo, d, h, m, 0, 0, day.Location())
+}
+
+func startOfDay(t time.Time) time.Time {
+	return atClock(t, 0, 0)
+}
+
+// addMonths adds n months, keeping to the last day of shorter months.
+func addMonths(day time.Time, n int) time.Time {
+	y, m, d := day.Date()
+	first := time.Date(y, m+time.Month(n), 1, 0, 0, 0, 0, day.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(d, last)-1)
+}
+
+// dayOfMonth reads "5", "05" or "5th".
+func dayOfMonth(w string) (int, bool) {
+	for _, s := range []string{"st", "nd", "rd", "th"} {
+		w = strings.TrimSuffix(w, s)
+	}
+	d, err := strconv.Atoi(w)
+	if err != nil || len(w) > 2 || d < 1 || d > 31 {
+		return 0, false
+	}
+	return d, true
+}
@@ -0,0 +1,169 @@
+package quickadd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Wednesday 3 September 2025, 10:00 in Berlin
+func berlinNow(t *testing.T) time.Time {
+	t.Helper()
+	loc, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	return time.Date(2025, 9, 3, 10, 0, 0, 0, loc)
+}
+
+func TestParse_Example(t *testing.T) {
+	now := berlinNow(t)
+	got, err := Parse("Pay rent tomorrow 9am #finance !high every month", now)
+	require.NoError(t, err)
+
+	due := time.Date(2025, 9, 4, 9, 0, 0, 0, now.Location())
+	assert.Equal(t, "Pay rent", got.Title)
+	require.NotNil(t, got.DueAt)
+	assert.True(t, due.Equal(*got.DueAt), got.DueAt)
+	assert.Equal(t, []string{"finance"}, got.Tags)
+	assert.Equal(t, "high", got.Priority)
+	assert.Equal(t, "FREQ=MONTHLY", got.Recurrence)
+	assert.Equal(t, []Token{
+		{Text: "tomorrow", Kind: KindDate, Value: "2025-09-04"},
+		{Text: "9am", Kind: KindTime, Value: "09:00"},
+		{Text: "#finance", Kind: KindTag, Value: "finance"},
+		{Text: "!high", Kind: KindPriority, Value: "high"},
+		{Text: "every month", Kind: KindRecurrence, Value: "FREQ=MONTHLY"},
+	}, got.Tokens)
+}
+
+func TestParse_Dates(t *testing.T) {
+	tests := []struct {
+		text  string
+		title string
+		due   string // local time, "" for none
+	}{
+		{"Buy milk", "Buy milk", ""},
+		{"Buy milk today", "Buy milk", "2025-09-03 23:59"},
+		{"Call Anna on friday", "Call Anna", "2025-09-05 23:59"},
+		{"Call Anna wed", "Call Anna", "2025-09-03 23:59"},
+		{"Call Anna next wednesday", "Call Anna", "2025-09-10 23:59"},
+		{"Plan sprint next week", "Plan sprint", "2025-09-08 23:59"},
+		{"Pay invoice next month", "Pay invoice", "2025-10-01 23:59"},
+		{"Renew passport next year", "Renew passport", "2026-01-01 23:59"},
+		{"Follow up in 3 days", "Follow up", "2025-09-06 23:59"},
+		{"Follow up in a week", "Follow up", "2025-09-10 23:59"},
+		{"Review in 6 months", "Review", "2026-03-03 23:59"},
+		{"Submit report by 2025-09-15", "Submit report", "2025-09-15 23:59"},
+		{"Dentist sep 20 at 14:30", "Dentist", "2025-09-20 14:30"},
+		{"Dentist 20th september 3pm", "Dentist", "2025-09-20 15:00"},
+		{"Birthday party jan 5", "Birthday party", "2026-01-05 23:59"},
+		{"Conference may 12 2027", "Conference", "2027-05-12 23:59"},
+		{"Stand-up 9:30am", "Stand-up", "2025-09-04 09:30"},
+		{"Lunch at noon", "Lunch", "2025-09-03 12:00"},
+		{"Call back at 4", "Call back", "2025-09-04 04:00"},
+		{"Call back at 16", "Call back", "2025-09-03 16:00"},
+		{"Gym tomorrow 7 pm", "Gym", "2025-09-04 19:00"},
+		{"Midnight snack tomorrow 12am", "Midnight snack", "2025-09-04 00:00"},
+	}
+
+	now := berlinNow(t)
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := Parse(tt.text, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.title, got.Title)
+			if tt.due == "" {
+				assert.Nil(t, got.DueAt)
+				return
+			}
+			require.NotNil(t, got.DueAt)
+			assert.Equal(t, tt.due, got.DueAt.Format("2006-01-02 15:04"))
+			assert.Equal(t, now.Location(), got.DueAt.Location())
+		})
+	}
+}
+
+func TestParse_Recurrence(t *testing.T) {
+	tests := []struct {
+		text string
+		rule string
+		due  string
+	}{
+		{"Water plants daily", "FREQ=DAILY", ""},
+		{"Backup every 2 weeks", "FREQ=WEEKLY;INTERVAL=2", ""},
+		{"Haircut every other month", "FREQ=MONTHLY;INTERVAL=2", ""},
+		{"Taxes every year", "FREQ=YEARLY", ""},
+		{"Review every friday 4pm", "FREQ=WEEKLY;BYDAY=FR", "2025-09-05 16:00"},
+		{"Stand-up every weekday at 9:15", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "2025-09-03 09:15"},
+		{"Stand-up every weekday at 9:15 from monday", "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", "2025-09-08 09:15"},
+	}
+
+	now := berlinNow(t)
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := Parse(tt.text, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.rule, got.Recurrence)
+			if tt.due == "" {
+				assert.Nil(t, got.DueAt)
+				return
+			}
+			require.NotNil(t, got.DueAt)
+			assert.Equal(t, tt.due, got.DueAt.Format("2006-01-02 15:04"))
+		})
+	}
+}
+
+func TestParse_LeavesOrdinaryWordsAlone(t *testing.T) {
+	tests := []struct {
+		text  string
+		title string
+	}{
+		{"Buy 2 apples", "Buy 2 apples"},
+		{"Meet at home", "Meet at home"},
+		{"Read chapter in book", "Read chapter in book"},
+		{"Ask if she may come", "Ask if she may come"},
+		{"Fix every bug", "Fix every bug"},
+		{"Price is 12:5 ratio", "Price is 12:5 ratio"},
+		{"Check issue # and !important", "Check issue # and !important"},
+		{"Book table 30 feb", "Book table 30 feb"},
+		{"Go out at 13pm", "Go out at 13pm"},
+	}
+
+	now := berlinNow(t)
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := Parse(tt.text, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.title, got.Title)
+			assert.Empty(t, got.Tokens)
+			assert.Nil(t, got.DueAt)
+		})
+	}
+}
+
+func TestParse_FirstPhraseWins(t *testing.T) {
+	got, err := Parse("Move meeting from today to tomorrow #work #Work !low !high", berlinNow(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Move meeting from to tomorrow !high", got.Title)
+	assert.Equal(t, "2025-09-03", got.DueAt.Format("2006-01-02"))
+	assert.Equal(t, []string{"work"}, got.Tags)
+	assert.Equal(t, "low", got.Priority)
+}
+
+func TestParse_EmptyTitle(t *testing.T) {
+	_, err := Parse("tomorrow 9am #work", berlinNow(t))
+	assert.ErrorIs(t, err, ErrEmptyTitle)
+
+	_, err = Parse("   ", berlinNow(t))
+	assert.ErrorIs(t, err, ErrEmptyTitle)
+}
+
+func TestAddMonths(t *testing.T) {
+	jan31 := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC), addMonths(jan31, 1))
+	assert.Equal(t, time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), addMonths(jan31, 2))
+	assert.Equal(t, time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC), addMonths(jan31, 12))
+}
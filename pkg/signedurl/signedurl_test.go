@@ -0,0 +1,24 @@
+package signedurl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSigner(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	s := NewSigner("secret")
+	s.now = func() time.Time { return now }
+
+	expires, sig := s.Sign("attachments/1", time.Minute)
+	assert.Equal(t, now.Add(time.Minute).Unix(), expires)
+
+	assert.NoError(t, s.Verify("attachments/1", expires, sig))
+	assert.ErrorIs(t, s.Verify("attachments/2", expires, sig), ErrInvalidSignature)
+	assert.ErrorIs(t, s.Verify("attachments/1", expires+1, sig), ErrInvalidSignature)
+
+	s.now = func() time.Time { return now.Add(2 * time.Minute) }
+	assert.ErrorIs(t, s.Verify("attachments/1", expires, sig), ErrExpired)
+}
@@ -0,0 +1,368 @@
+// Package smtpd is a small SMTP server for receiving mail (RFC 5321).
+//
+// It speaks what mail transfer agents and net/smtp need to hand over a
+// message: EHLO, HELO, MAIL, RCPT, DATA, RSET, NOOP and QUIT, with the
+// 8BITMIME and SIZE extensions. It does not relay, authenticate or offer
+// STARTTLS, so put it behind a mail server or a TLS-terminating proxy
+// when it faces the internet.
+package smtpd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMaxMessageBytes = 25 << 20 // 25 MiB
+	DefaultMaxRecipients   = 50
+	DefaultTimeout         = 5 * time.Minute
+
+	// maxLineBytes is the longest command line accepted, well above the
+	// 512 octets RFC 5321 requires.
+	maxLineBytes = 4096
+)
+
+// ErrServerClosed is returned by Serve after Close.
+var ErrServerClosed = errors.New("smtpd: server closed")
+
+// Error is an SMTP reply a Backend returns to reject a recipient or a
+// message with a specific code.
+type Error struct {
+	Code    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s", e.Code, e.Message)
+}
+
+// ErrNoSuchRecipient rejects a recipient permanently.
+var ErrNoSuchRecipient = &Error{Code: 550, Message: "5.1.1 No such recipient"}
+
+// Backend decides what happens to mail. Errors other than *Error are
+// reported to the client as temporary failures, so it tries again later.
+type Backend interface {
+	// Recipient is called for every RCPT TO address.
+	Recipient(addr string) error
+	// Deliver is called with every accepted message.
+	Deliver(from string, to []string, data []byte) error
+}
+
+type Server struct {
+	Addr string
+	// Hostname is announced in the greeting.
+	Hostname        string
+	Backend         Backend
+	MaxMessageBytes int64
+	MaxRecipients   int
+	// Timeout bounds every command, and the transfer of a message.
+	Timeout time.Duration
+
+	mu       sync.Mutex
+	listener net.Listener
+	conns    map[net.Conn]struct{}
+	closed   bool
+}
+
+// ListenAndServe listens on s.Addr and serves until Close.
+func (s *Server) ListenAndServe() error {
+	ln, err := net.Listen("tcp", s.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts connections on ln until Close.
+func (s *Server) Serve(ln net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return ErrServerClosed
+	}
+	s.listener = ln
+	s.conns = map[net.Conn]struct{}{}
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+			return err
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		go func() {
+			defer func() {
+				s.mu.Lock()
+				delete(s.conns, conn)
+				s.mu.Unlock()
+				conn.Close()
+			}()
+			s.newSession(conn).serve()
+		}()
+	}
+}
+
+// Close stops the listener and closes open connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Close()
+}
+
+type session struct {
+	srv    *Server
+	conn   net.Conn
+	r      *bufio.Reader
+	w      *bufio.Writer
+	helo   bool
+	from   *string
+	to     []string
+	maxMsg int64
+	maxRcp int
+	tmo    time.Duration
+}
+
+func (s *Server) newSession(conn net.Conn) *session {
+	sess := &session{
+		srv:    s,
+		conn:   conn,
+		r:      bufio.NewReaderSize(conn, maxLineBytes),
+		w:      bufio.NewWriter(conn),
+		maxMsg: s.MaxMessageBytes,
+		maxRcp: s.MaxRecipients,
+		tmo:    s.Timeout,
+	}
+	if sess.maxMsg <= 0 {
+		sess.maxMsg = DefaultMaxMessageBytes
+	}
+	if sess.maxRcp <= 0 {
+		sess.maxRcp = DefaultMaxRecipients
+	}
+	if sess.tmo <= 0 {
+		sess.tmo = DefaultTimeout
+	}
+	return sess
+}
+
+func (sess *session) hostname() string {
+	if sess.srv.Hostname != "" {
+		return sess.srv.Hostname
+	}
+	return "localhost"
+}
+
+func (sess *session) reply(code int, format string, args ...any) error {
+	fmt.Fprintf(sess.w, "%d %s\r\n", code, fmt.Sprintf(format, args...))
+	return sess.w.Flush()
+}
+
+func (sess *session) replyError(err error) error {
+	var se *Error
+	if errors.As(err, &se) {
+		return sess.reply(se.Code, "%s", se.Message)
+	}
+	log.Printf("smtpd: %v", err)
+	return sess.reply(451, "4.3.0 Temporary failure, try again later")
+}
+
+func (sess *session) reset() {
+	sess.from = nil
+	sess.to = nil
+}
+
+func (sess *session) serve() {
+	sess.conn.SetDeadline(time.Now().Add(sess.tmo))
+	if err := sess.reply(220, "%s ESMTP ready", sess.hostname()); err != nil {
+		return
+	}
+
+	for {
+		sess.conn.SetDeadline(time.Now().Add(sess.tmo))
+		line, err := sess.readLine()
+		if errors.Is(err, bufio.ErrBufferFull) {
+			if sess.reply(500, "5.5.2 Line too long") != nil {
+				return
+			}
+			continue
+		}
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+		verb = strings.ToUpper(verb)
+		if verb == "QUIT" {
+			sess.reply(221, "2.0.0 Bye")
+			return
+		}
+		if err := sess.handle(verb, strings.TrimSpace(arg)); err != nil {
+			return
+		}
+	}
+}
+
+// readLine reads one CRLF-terminated line. Overlong lines are skipped
+// and reported as bufio.ErrBufferFull.
+func (sess *session) readLine() (string, error) {
+	line, err := sess.r.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = sess.r.ReadSlice('\n')
+		}
+		if err != nil {
+			return "", err
+		}
+		return "", bufio.ErrBufferFull
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+func (sess *session) handle(verb, arg string) error {
+	switch verb {
+	case "HELO":
+		sess.reset()
+		sess.helo = true
+		return sess.reply(250, "%s", sess.hostname())
+	case "EHLO":
+		sess.reset()
+		sess.helo = true
+		fmt.Fprintf(sess.w, "250-%s\r\n250-8BITMIME\r\n250 SIZE %d\r\n", sess.hostname(), sess.maxMsg)
+		return sess.w.Flush()
+	case "MAIL":
+		return sess.mail(arg)
+	case "RCPT":
+		return sess.rcpt(arg)
+	case "DATA":
+		return sess.data()
+	case "RSET":
+		sess.reset()
+		return sess.reply(250, "2.0.0 OK")
+	case "NOOP":
+		return sess.reply(250, "2.0.0 OK")
+	case "VRFY":
+		return sess.reply(252, "2.5.0 Cannot verify user")
+	default:
+		return sess.reply(502, "5.5.1 Command not implemented")
+	}
+}
+
+func (sess *session) mail(arg string) error {
+	if !sess.helo {
+		return sess.reply(503, "5.5.1 Say hello first")
+	}
+	if sess.from != nil {
+		return sess.reply(503, "5.5.1 Sender already given")
+	}
+	from, params, ok := parsePath(arg, "FROM:")
+	if !ok {
+		return sess.reply(501, "5.5.4 Syntax: MAIL FROM:<address>")
+	}
+	for _, p := range params {
+		k, v, _ := strings.Cut(p, "=")
+		if strings.EqualFold(k, "SIZE") {
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > sess.maxMsg {
+				return sess.reply(552, "5.3.4 Message too big")
+			}
+		}
+	}
+	sess.from = &from
+	return sess.reply(250, "2.1.0 OK")
+}
+
+func (sess *session) rcpt(arg string) error {
+	if sess.from == nil {
+		return sess.reply(503, "5.5.1 Need MAIL first")
+	}
+	to, _, ok := parsePath(arg, "TO:")
+	if !ok || to == "" {
+		return sess.reply(501, "5.5.4 Syntax: RCPT TO:<address>")
+	}
+	if len(sess.to) >= sess.maxRcp {
+		return sess.reply(452, "4.5.3 Too many recipients")
+	}
+	if err := sess.srv.Backend.Recipient(to); err != nil {
+		return sess.replyError(err)
+	}
+	sess.to = append(sess.to, to)
+	return sess.reply(250, "2.1.5 OK")
+}
+
+func (sess *session) data() error {
+	if len(sess.to) == 0 {
+		return sess.reply(503, "5.5.1 Need RCPT first")
+	}
+	if err := sess.reply(354, "End data with <CR><LF>.<CR><LF>"); err != nil {
+		return err
+	}
+
+	dot := textproto.NewReader(sess.r).DotReader()
+	data, err := io.ReadAll(io.LimitReader(dot, sess.maxMsg+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > sess.maxMsg {
+		if _, err := io.Copy(io.Discard, dot); err != nil {
+			return err
+		}
+		sess.reset()
+		return sess.reply(552, "5.3.4 Message too big")
+	}
+
+	from, to := *sess.from, sess.to
+	sess.reset()
+	if err := sess.srv.Backend.Deliver(from, to, data); err != nil {
+		return sess.replyError(err)
+	}
+	return sess.reply(250, "2.0.0 OK: queued")
+}
+
+// parsePath parses "FROM:<addr> PARAM=x ..." style arguments. The null
+// path <> is returned as "".
+func parsePath(arg, prefix string) (addr string, params []string, ok bool) {
+	if len(arg) < len(prefix) || !strings.EqualFold(arg[:len(prefix)], prefix) {
+		return "", nil, false
+	}
+	rest := strings.TrimSpace(arg[len(prefix):])
+	if !strings.HasPrefix(rest, "<") {
+		return "", nil, false
+	}
+	end := strings.IndexByte(rest, '>')
+	if end < 0 {
+		return "", nil, false
+	}
+	return rest[1:end], strings.Fields(rest[end+1:]), true
+}
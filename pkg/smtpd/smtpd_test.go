@@ -0,0 +1,139 @@
+package smtpd
+
+import (
+	"errors"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type message struct {
+	from string
+	to   []string
+	data string
+}
+
+type backend struct {
+	mu         sync.Mutex
+	messages   []message
+	deliverErr error
+}
+
+func (b *backend) Recipient(addr string) error {
+	if !strings.HasSuffix(addr, "@in.example.com") {
+		return ErrNoSuchRecipient
+	}
+	return nil
+}
+
+func (b *backend) Deliver(from string, to []string, data []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.deliverErr != nil {
+		return b.deliverErr
+	}
+	b.messages = append(b.messages, message{from, to, string(data)})
+	return nil
+}
+
+func start(t *testing.T, b *backend, maxMessageBytes int64) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	srv := &Server{Hostname: "mx.example.com", Backend: b, MaxMessageBytes: maxMessageBytes}
+	done := make(chan error, 1)
+	go func() { done <- srv.Serve(ln) }()
+	t.Cleanup(func() {
+		srv.Close()
+		assert.ErrorIs(t, <-done, ErrServerClosed)
+	})
+	return ln.Addr().String()
+}
+
+func TestServer_SendMail(t *testing.T) {
+	b := &backend{}
+	addr := start(t, b, 0)
+
+	body := "Subject: Hello\r\n\r\nFirst line\r\n.leading dot\r\n"
+	err := smtp.SendMail(addr, nil, "sam@example.com", []string{"a@in.example.com", "b@in.example.com"}, []byte(body))
+	require.NoError(t, err)
+
+	require.Len(t, b.messages, 1)
+	assert.Equal(t, "sam@example.com", b.messages[0].from)
+	assert.Equal(t, []string{"a@in.example.com", "b@in.example.com"}, b.messages[0].to)
+	assert.Equal(t, "Subject: Hello\n\nFirst line\n.leading dot\n", b.messages[0].data, "lines end in LF and dots are unstuffed")
+}
+
+func TestServer_Rejections(t *testing.T) {
+	b := &backend{}
+	addr := start(t, b, 64)
+
+	t.Run("unknown recipient", func(t *testing.T) {
+		err := smtp.SendMail(addr, nil, "sam@example.com", []string{"a@elsewhere.com"}, []byte("Subject: x\r\n\r\nx\r\n"))
+		var tpErr *textproto.Error
+		require.True(t, errors.As(err, &tpErr), "%v", err)
+		assert.Equal(t, 550, tpErr.Code)
+	})
+
+	t.Run("message too big", func(t *testing.T) {
+		err := smtp.SendMail(addr, nil, "sam@example.com", []string{"a@in.example.com"}, []byte(strings.Repeat("x", 100)+"\r\n"))
+		var tpErr *textproto.Error
+		require.True(t, errors.As(err, &tpErr), "%v", err)
+		assert.Equal(t, 552, tpErr.Code)
+	})
+
+	t.Run("temporary failure", func(t *testing.T) {
+		b.mu.Lock()
+		b.deliverErr = errors.New("database is down")
+		b.mu.Unlock()
+
+		err := smtp.SendMail(addr, nil, "sam@example.com", []string{"a@in.example.com"}, []byte("Subject: x\r\n\r\nx\r\n"))
+		var tpErr *textproto.Error
+		require.True(t, errors.As(err, &tpErr), "%v", err)
+		assert.Equal(t, 451, tpErr.Code)
+	})
+
+	assert.Empty(t, b.messages)
+}
+
+func TestServer_CommandOrder(t *testing.T) {
+	addr := start(t, &backend{}, 0)
+	conn, err := textproto.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, _, err = conn.ReadResponse(220)
+	require.NoError(t, err)
+
+	for _, step := range []struct {
+		cmd  string
+		code int
+	}{
+		{"MAIL FROM:<sam@example.com>", 503},
+		{"HELO client", 250},
+		{"RCPT TO:<a@in.example.com>", 503},
+		{"MAIL FROM:sam@example.com", 501},
+		{"MAIL FROM:<sam@example.com> SIZE=999999999", 552},
+		{"MAIL FROM:<>", 250},
+		{"DATA", 503},
+		{"RCPT TO:<a@in.example.com>", 250},
+		{"STARTTLS", 502},
+		{"RSET", 250},
+		{"RCPT TO:<a@in.example.com>", 503},
+		{"NOOP", 250},
+		{strings.Repeat("X", maxLineBytes+10), 500},
+		{"QUIT", 221},
+	} {
+		id, err := conn.Cmd("%s", step.cmd)
+		require.NoError(t, err)
+		conn.StartResponse(id)
+		code, _, _ := conn.ReadResponse(0)
+		conn.EndResponse(id)
+		assert.Equal(t, step.code, code, step.cmd)
+	}
+}
@@ -0,0 +1,93 @@
+package textindex
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// Highlight returns an HTML-escaped excerpt of text of roughly width runes
+// around the first query term match, with matched words wrapped in <mark>.
+// It returns an empty string when no term matches.
+func Highlight(text string, query string, width int) string {
+	terms := make(map[string]bool)
+	for _, t := range Tokenize(query) {
+		terms[t] = true
+	}
+	if len(terms) == 0 {
+		return ""
+	}
+
+	runes := []rune(text)
+	words := wordSpans(runes)
+
+	first := -1
+	for _, w := range words {
+		if terms[strings.ToLower(string(runes[w[0]:w[1]]))] {
+			first = w[0]
+			break
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	start := first - width/3
+	if start < 0 {
+		start = 0
+	}
+	end := start + width
+	if end > len(runes) {
+		end = len(runes)
+	}
+	// Don't cut words in half at the window edges.
+	for start > 0 && !unicode.IsSpace(runes[start-1]) {
+		start--
+	}
+	for end < len(runes) && !unicode.IsSpace(runes[end]) {
+		end++
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, w := range words {
+		if w[0] < start || w[1] > end {
+			continue
+		}
+		if !terms[strings.ToLower(string(runes[w[0]:w[1]]))] {
+			continue
+		}
+		b.WriteString(html.EscapeString(string(runes[pos:w[0]])))
+		b.WriteString("<mark>" + html.EscapeString(string(runes[w[0]:w[1]])) + "</mark>")
+		pos = w[1]
+	}
+	b.WriteString(html.EscapeString(string(runes[pos:end])))
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+
+	return b.String()
+}
+
+// wordSpans returns [start, end) rune offsets of each letter/digit run.
+func wordSpans(runes []rune) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range runes {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case isWord && start < 0:
+			start = i
+		case !isWord && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, [2]int{start, len(runes)})
+	}
+	return spans
+}
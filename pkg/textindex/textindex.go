@@ -0,0 +1,107 @@
+package textindex
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"it": true, "of": true, "on": true, "or": true, "the": true, "to": true,
+	"with": true,
+}
+
+// Result is a matching document and its relevance score.
+type Result struct {
+	ID    int
+	Score float64
+}
+
+// Index is an in-memory inverted index with TF-IDF ranking.
+// It is not safe for concurrent writes.
+type Index struct {
+	postings map[string]map[int]float64
+	docs     map[int]bool
+}
+
+func New() *Index {
+	return &Index{
+		postings: make(map[string]map[int]float64),
+		docs:     make(map[int]bool),
+	}
+}
+
+// Add indexes text as part of document id. Matches in fields with a higher
+// boost contribute more to the document's score.
+func (ix *Index) Add(id int, text string, boost float64) {
+	ix.docs[id] = true
+	for _, term := range Tokenize(text) {
+		p, ok := ix.postings[term]
+		if !ok {
+			p = make(map[int]float64)
+			ix.postings[term] = p
+		}
+		p[id] += boost
+	}
+}
+
+// Search returns documents matching any query term, best first.
+func (ix *Index) Search(query string) []Result {
+	scores := make(map[int]float64)
+	n := float64(len(ix.docs))
+
+	for _, term := range uniq(Tokenize(query)) {
+		p := ix.postings[term]
+		if len(p) == 0 {
+			continue
+		}
+		idf := math.Log(1 + n/float64(len(p)))
+		for id, tf := range p {
+			scores[id] += math.Log1p(tf) * idf
+		}
+	}
+
+	results := make([]Result, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, Result{ID: id, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID > results[j].ID
+	})
+	return results
+}
+
+// Tokenize lower-cases text and splits it into searchable terms,
+// dropping single characters and common stop words.
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) < 2 || stopWords[f] {
+			continue
+		}
+		terms = append(terms, f)
+	}
+	return terms
+}
+
+func uniq(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := terms[:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package textindex
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"buy", "milk", "2l", "café"}, Tokenize("Buy the MILK (2L) at a café!"))
+}
+
+func TestIndex_Search(t *testing.T) {
+	ix := New()
+	ix.Add(1, "Buy milk", 2)
+	ix.Add(1, "from the corner shop", 1)
+	ix.Add(2, "Write report", 2)
+	ix.Add(2, "mention milk prices in the report", 1)
+	ix.Add(3, "Call mom", 2)
+
+	t.Run("title matches rank above body matches", func(t *testing.T) {
+		got := ix.Search("milk")
+		require.Len(t, got, 2)
+		assert.Equal(t, 1, got[0].ID)
+		assert.Equal(t, 2, got[1].ID)
+		assert.Greater(t, got[0].Score, got[1].Score)
+	})
+
+	t.Run("documents matching more terms rank higher", func(t *testing.T) {
+		got := ix.Search("milk report")
+		require.Len(t, got, 2)
+		assert.Equal(t, 2, got[0].ID)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		assert.Empty(t, ix.Search("groceries"))
+	})
+
+	t.Run("stop words only", func(t *testing.T) {
+		assert.Empty(t, ix.Search("the and"))
+	})
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		width int
+		want  string
+	}{
+		{
+			name:  "short text",
+			text:  "Buy milk & eggs",
+			query: "milk",
+			width: 80,
+			want:  "Buy <mark>milk</mark> &amp; eggs",
+		},
+		{
+			name:  "windowed with ellipses",
+			text:  "one two three four five six seven eight nine ten",
+			query: "six",
+			width: 10,
+			want:  "…five <mark>six</mark> seven…",
+		},
+		{
+			name:  "case insensitive, whole words only",
+			text:  "Milky way has MILK",
+			query: "milk",
+			width: 80,
+			want:  "Milky way has <mark>MILK</mark>",
+		},
+		{
+			name:  "no match",
+			text:  "nothing here",
+			query: "milk",
+			width: 80,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Highlight(tt.text, tt.query, tt.width))
+		})
+	}
+}